@@ -0,0 +1,185 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cauthdsl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+/*
+FromString compiles a policy expression into a SignaturePolicyEnvelope. The expression is built of
+  -- principals of the form 'MSPID.member' or 'MSPID.admin', optionally quoted, which are satisfied
+     by a signature of a member or an admin of the MSP
+  -- AND(e1, e2, ...), which requires all the given expressions to be satisfied
+  -- OR(e1, e2, ...), which requires one of the given expressions to be satisfied
+  -- OutOf(n, e1, e2, ...), which requires n of the given expressions to be satisfied
+The names of the operators are case insensitive. For instance, a policy that requires the signatures
+of two of three organizations reads OutOf(2, 'Org1.member', 'Org2.member', 'Org3.member')
+*/
+func FromString(policy string) (*cb.SignaturePolicyEnvelope, error) {
+	p := &policyParser{input: policy, principals: make(map[string]int32)}
+	sp, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos != len(p.input) {
+		return nil, p.errorf("unexpected input")
+	}
+	return &cb.SignaturePolicyEnvelope{Version: 0, Policy: sp, Identities: p.identities}, nil
+}
+
+// policyParser is a recursive descent parser for the policy expressions. The principals
+// are collected in the order of their first appearance
+type policyParser struct {
+	input      string
+	pos        int
+	identities []*cb.MSPPrincipal
+	principals map[string]int32
+}
+
+func (p *policyParser) parseExpression() (*cb.SignaturePolicy, error) {
+	p.skipSpaces()
+	if p.pos < len(p.input) && (p.input[p.pos] == '\'' || p.input[p.pos] == '"') {
+		quote := p.input[p.pos]
+		end := strings.IndexByte(p.input[p.pos+1:], quote)
+		if end < 0 {
+			return nil, p.errorf("unterminated principal")
+		}
+		principal := p.input[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		return p.signedBy(principal)
+	}
+
+	token := p.nextToken()
+	if token == "" {
+		return nil, p.errorf("expected an operator or a principal")
+	}
+	p.skipSpaces()
+	if p.pos == len(p.input) || p.input[p.pos] != '(' {
+		return p.signedBy(token)
+	}
+	p.pos++
+
+	var n int32
+	operator := strings.ToLower(token)
+	switch operator {
+	case "and", "or":
+	case "outof":
+		p.skipSpaces()
+		count, err := strconv.ParseInt(p.nextToken(), 10, 32)
+		if err != nil || count < 0 {
+			return nil, p.errorf("expected the number of expressions to be satisfied")
+		}
+		n = int32(count)
+		if err = p.expect(','); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, p.errorf("unknown operator %s", token)
+	}
+
+	var policies []*cb.SignaturePolicy
+	for {
+		sp, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, sp)
+		p.skipSpaces()
+		if p.pos < len(p.input) && p.input[p.pos] == ',' {
+			p.pos++
+			continue
+		}
+		if err = p.expect(')'); err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	switch operator {
+	case "and":
+		n = int32(len(policies))
+	case "or":
+		n = 1
+	default:
+		if int(n) > len(policies) {
+			return nil, p.errorf("%d out of %d expressions can never be satisfied", n, len(policies))
+		}
+	}
+	return NOutOf(n, policies), nil
+}
+
+// signedBy returns the policy satisfied by the signature of the given principal, adding the principal to the identities
+func (p *policyParser) signedBy(principal string) (*cb.SignaturePolicy, error) {
+	if index, ok := p.principals[principal]; ok {
+		return SignedBy(index), nil
+	}
+	dot := strings.LastIndex(principal, ".")
+	if dot <= 0 {
+		return nil, p.errorf("invalid principal %s, expected MSPID.member or MSPID.admin", principal)
+	}
+	var role cb.MSPRole_MSPRoleType
+	switch strings.ToLower(principal[dot+1:]) {
+	case "member":
+		role = cb.MSPRole_Member
+	case "admin":
+		role = cb.MSPRole_Admin
+	default:
+		return nil, p.errorf("invalid role in principal %s, expected member or admin", principal)
+	}
+	index := int32(len(p.identities))
+	p.identities = append(p.identities, &cb.MSPPrincipal{
+		PrincipalClassification: cb.MSPPrincipal_ByMSPRole,
+		Principal:               utils.MarshalOrPanic(&cb.MSPRole{Role: role, MSPIdentifier: principal[:dot]})})
+	p.principals[principal] = index
+	return SignedBy(index), nil
+}
+
+// nextToken returns the run of characters up to the next space, comma or parenthesis
+func (p *policyParser) nextToken() string {
+	start := p.pos
+	for p.pos < len(p.input) && !unicode.IsSpace(rune(p.input[p.pos])) && !strings.ContainsRune(",()'\"", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *policyParser) expect(c byte) error {
+	p.skipSpaces()
+	if p.pos == len(p.input) || p.input[p.pos] != c {
+		return p.errorf("expected '%c'", c)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *policyParser) skipSpaces() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *policyParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("Invalid policy [%s] at position %d: %s", p.input, p.pos, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,114 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cauthdsl
+
+import (
+	"reflect"
+	"testing"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+func memberOf(mspID string) []byte {
+	return utils.MarshalOrPanic(&cb.MSPRole{Role: cb.MSPRole_Member, MSPIdentifier: mspID})
+}
+
+func TestFromStringSignedByMember(t *testing.T) {
+	policy, err := FromString("'Org1.member'")
+	if err != nil {
+		t.Fatalf("Could not parse the policy: %s", err)
+	}
+	if !reflect.DeepEqual(policy, &cb.SignaturePolicyEnvelope{Policy: SignedBy(0), Identities: []*cb.MSPPrincipal{
+		{PrincipalClassification: cb.MSPPrincipal_ByMSPRole, Principal: memberOf("Org1")}}}) {
+		t.Fatalf("Unexpected policy %s", policy)
+	}
+
+	policy, err = FromString("OR(Org1.admin, Org1.member)")
+	if err != nil {
+		t.Fatalf("Could not parse the policy: %s", err)
+	}
+	if len(policy.Identities) != 2 || !reflect.DeepEqual(policy.Policy, Or(SignedBy(0), SignedBy(1))) {
+		t.Fatalf("Unexpected policy %s", policy)
+	}
+	admin := utils.MarshalOrPanic(&cb.MSPRole{Role: cb.MSPRole_Admin, MSPIdentifier: "Org1"})
+	if !reflect.DeepEqual(policy.Identities[0].Principal, admin) {
+		t.Fatalf("Expected the first principal to be an admin of Org1")
+	}
+}
+
+func TestFromStringNested(t *testing.T) {
+	policy, err := FromString("AND('Org1.member', or('Org2.member', \"Org3.member\"), Org1.member)")
+	if err != nil {
+		t.Fatalf("Could not parse the policy: %s", err)
+	}
+	expected := NOutOf(3, []*cb.SignaturePolicy{SignedBy(0), Or(SignedBy(1), SignedBy(2)), SignedBy(0)})
+	if !reflect.DeepEqual(policy.Policy, expected) {
+		t.Fatalf("Unexpected policy %s", policy.Policy)
+	}
+	if len(policy.Identities) != 3 {
+		t.Fatalf("Expected the principals to be deduplicated, got %d principals", len(policy.Identities))
+	}
+}
+
+func TestFromStringTwoOfThreeOrgs(t *testing.T) {
+	policy, err := FromString("OutOf(2, 'Org1.member', 'Org2.member', 'Org3.member')")
+	if err != nil {
+		t.Fatalf("Could not parse the policy: %s", err)
+	}
+
+	// the mock identities satisfy the principals that are equal to their serialization
+	spe, err := compile(policy.Policy, policy.Identities, &mockDeserializer{})
+	if err != nil {
+		t.Fatalf("Could not compile the policy: %s", err)
+	}
+	org1, org2, org3 := memberOf("Org1"), memberOf("Org2"), memberOf("Org3")
+	if !spe(toSignedData(msgs, [][]byte{org1, org3}, [][]byte{validSignature, validSignature})) {
+		t.Errorf("Expected the signatures of Org1 and Org3 to satisfy the policy")
+	}
+	if !spe(toSignedData(moreMsgs, [][]byte{org1, org2, org3}, [][]byte{validSignature, validSignature, validSignature})) {
+		t.Errorf("Expected the signatures of all the orgs to satisfy the policy")
+	}
+	if spe(toSignedData([][]byte{nil}, [][]byte{org2}, [][]byte{validSignature})) {
+		t.Errorf("Expected the signature of Org2 alone not to satisfy the policy")
+	}
+	if spe(toSignedData(msgs, [][]byte{org2, org2}, [][]byte{validSignature, validSignature})) {
+		t.Errorf("Expected two signatures of Org2 not to satisfy the policy")
+	}
+	if spe(toSignedData(msgs, [][]byte{org1, org2}, [][]byte{validSignature, invalidSignature})) {
+		t.Errorf("Expected an invalid signature of Org2 not to count")
+	}
+}
+
+func TestFromStringInvalid(t *testing.T) {
+	for _, policy := range []string{
+		"",
+		"Org1",
+		"'Org1.member",
+		"Org1.guest",
+		"AND('Org1.member'",
+		"AND('Org1.member',)",
+		"XOR('Org1.member', 'Org2.member')",
+		"OutOf(3, 'Org1.member', 'Org2.member')",
+		"OutOf(x, 'Org1.member')",
+		"'Org1.member' 'Org2.member'",
+	} {
+		if _, err := FromString(policy); err == nil {
+			t.Errorf("Expected an error for policy [%s]", policy)
+		}
+	}
+}
@@ -19,8 +19,11 @@ package chainconfig
 import (
 	"fmt"
 
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/factory"
 	"github.com/hyperledger/fabric/common/util"
 	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/op/go-logging"
@@ -36,6 +39,21 @@ const (
 const (
 	// SHAKE256 is the algorithm type for the sha3 shake256 hashing algorithm with 512 bits of output
 	SHA3Shake256 = "SHAKE256"
+
+	// SHA256 is the algorithm type for the sha2 256 bit hashing algorithm
+	SHA256 = bccsp.SHA256
+
+	// SHA384 is the algorithm type for the sha2 384 bit hashing algorithm
+	SHA384 = bccsp.SHA384
+
+	// SHA3_256 is the algorithm type for the sha3 256 bit hashing algorithm
+	SHA3_256 = bccsp.SHA3_256
+
+	// SHA3_384 is the algorithm type for the sha3 384 bit hashing algorithm
+	SHA3_384 = bccsp.SHA3_384
+
+	// DefaultHashingAlgorithm is the algorithm used to hash the blocks which precede any configuration of their chain
+	DefaultHashingAlgorithm = SHA3Shake256
 )
 
 var logger = logging.MustGetLogger("common/chainconfig")
@@ -51,7 +69,8 @@ type Descriptor interface {
 }
 
 type chainConfig struct {
-	hashingAlgorithm func(input []byte) []byte
+	hashingAlgorithmName string
+	hashingAlgorithm     func(input []byte) []byte
 }
 
 // DescriptorImpl is an implementation of Manager and configtx.ConfigHandler
@@ -73,6 +92,15 @@ func (pm *DescriptorImpl) HashingAlgorithm() func(input []byte) []byte {
 	return pm.config.hashingAlgorithm
 }
 
+// Validate returns an error if the committed configuration does not specify a hashing algorithm,
+// as the blocks of a chain may not be hashed with an algorithm the configuration does not name
+func (pm *DescriptorImpl) Validate() error {
+	if pm.config.hashingAlgorithm == nil {
+		return fmt.Errorf("Chain configuration does not specify a hashing algorithm")
+	}
+	return nil
+}
+
 // BeginConfig is used to start a new configuration proposal
 func (pm *DescriptorImpl) BeginConfig() {
 	if pm.pendingConfig != nil {
@@ -107,14 +135,94 @@ func (pm *DescriptorImpl) ProposeConfig(configItem *cb.ConfigurationItem) error
 		if err := proto.Unmarshal(configItem.Value, hashingAlgorithm); err != nil {
 			return fmt.Errorf("Unmarshaling error for HashingAlgorithm: %s", err)
 		}
-		switch hashingAlgorithm.Name {
-		case SHA3Shake256:
-			pm.pendingConfig.hashingAlgorithm = util.ComputeCryptoHash
-		default:
-			return fmt.Errorf("Unknown hashing algorithm type: %s", hashingAlgorithm.Name)
+		hashFunc, err := HashFunction(hashingAlgorithm.Name)
+		if err != nil {
+			return err
 		}
+		// A chain is hashed with a single algorithm over its lifetime, as blocks are chained by hash
+		if pm.pendingConfig.hashingAlgorithmName != "" && pm.pendingConfig.hashingAlgorithmName != hashingAlgorithm.Name {
+			return fmt.Errorf("Conflicting hashing algorithms %s and %s", pm.pendingConfig.hashingAlgorithmName, hashingAlgorithm.Name)
+		}
+		if pm.config.hashingAlgorithmName != "" && pm.config.hashingAlgorithmName != hashingAlgorithm.Name {
+			return fmt.Errorf("Cannot change hashing algorithm from %s to %s", pm.config.hashingAlgorithmName, hashingAlgorithm.Name)
+		}
+		pm.pendingConfig.hashingAlgorithmName = hashingAlgorithm.Name
+		pm.pendingConfig.hashingAlgorithm = hashFunc
 	default:
 		logger.Warningf("Uknown Chain configuration item with key %s", configItem.Key)
 	}
 	return nil
 }
+
+// HashFunction returns the hashing function of the named algorithm
+func HashFunction(name string) (func(input []byte) []byte, error) {
+	switch name {
+	case SHA3Shake256:
+		return util.ComputeCryptoHash, nil
+	case SHA256:
+		return bccspHashFunction(&bccsp.SHA256Opts{}), nil
+	case SHA384:
+		return bccspHashFunction(&bccsp.SHA384Opts{}), nil
+	case SHA3_256:
+		return bccspHashFunction(&bccsp.SHA3_256Opts{}), nil
+	case SHA3_384:
+		return bccspHashFunction(&bccsp.SHA3_384Opts{}), nil
+	default:
+		return nil, fmt.Errorf("Unknown hashing algorithm type: %s", name)
+	}
+}
+
+func bccspHashFunction(opts bccsp.HashOpts) func(input []byte) []byte {
+	return func(input []byte) []byte {
+		digest, err := factory.GetDefaultOrPanic().Hash(input, opts)
+		if err != nil {
+			logger.Panicf("Error computing %s hash: %s", opts.Algorithm(), err)
+		}
+		return digest
+	}
+}
+
+// GetHashingAlgorithm returns the hashing function named by the HashingAlgorithm item of the given
+// configuration, or an error if the configuration does not name one
+func GetHashingAlgorithm(configEnvelope *cb.ConfigurationEnvelope) (func(input []byte) []byte, error) {
+	name := ""
+	for _, signedItem := range configEnvelope.Items {
+		item := &cb.ConfigurationItem{}
+		if err := proto.Unmarshal(signedItem.ConfigurationItem, item); err != nil {
+			return nil, fmt.Errorf("Unmarshaling error for ConfigurationItem: %s", err)
+		}
+		if item.Type != cb.ConfigurationItem_Chain || item.Key != HashingAlgorithmKey {
+			continue
+		}
+		hashingAlgorithm := &cb.HashingAlgorithm{}
+		if err := proto.Unmarshal(item.Value, hashingAlgorithm); err != nil {
+			return nil, fmt.Errorf("Unmarshaling error for HashingAlgorithm: %s", err)
+		}
+		if name != "" && name != hashingAlgorithm.Name {
+			return nil, fmt.Errorf("Conflicting hashing algorithms %s and %s", name, hashingAlgorithm.Name)
+		}
+		name = hashingAlgorithm.Name
+	}
+
+	if name == "" {
+		return nil, fmt.Errorf("Configuration does not specify a hashing algorithm")
+	}
+	return HashFunction(name)
+}
+
+// GetHashingAlgorithmFromBlock returns the hashing function configured by the given configuration block
+func GetHashingAlgorithmFromBlock(block *cb.Block) (func(input []byte) []byte, error) {
+	envelope, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := utils.UnmarshalPayload(envelope.Payload)
+	if err != nil {
+		return nil, err
+	}
+	configEnvelope, err := utils.UnmarshalConfigurationEnvelope(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	return GetHashingAlgorithm(configEnvelope)
+}
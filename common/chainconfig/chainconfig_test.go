@@ -17,6 +17,7 @@ limitations under the License.
 package chainconfig
 
 import (
+	"bytes"
 	"testing"
 
 	cb "github.com/hyperledger/fabric/protos/common"
@@ -102,3 +103,110 @@ func TestHashingAlgorithm(t *testing.T) {
 		t.Fatalf("Should have set default hashing algorithm")
 	}
 }
+
+func hashingAlgorithmItem(name string) *cb.ConfigurationItem {
+	return &cb.ConfigurationItem{
+		Type:  cb.ConfigurationItem_Chain,
+		Key:   HashingAlgorithmKey,
+		Value: utils.MarshalOrPanic(&cb.HashingAlgorithm{Name: name}),
+	}
+}
+
+func TestMissingHashingAlgorithm(t *testing.T) {
+	m := NewDescriptorImpl()
+	m.BeginConfig()
+	m.CommitConfig()
+
+	if m.Validate() == nil {
+		t.Fatalf("Should have rejected a configuration without a hashing algorithm")
+	}
+
+	m.BeginConfig()
+	if err := m.ProposeConfig(hashingAlgorithmItem(SHA256)); err != nil {
+		t.Fatalf("Error applying valid config: %s", err)
+	}
+	m.CommitConfig()
+
+	if err := m.Validate(); err != nil {
+		t.Fatalf("Should have accepted a configuration with a hashing algorithm: %s", err)
+	}
+}
+
+func TestHashFunction(t *testing.T) {
+	for name, size := range map[string]int{SHA3Shake256: 64, SHA256: 32, SHA384: 48, SHA3_256: 32, SHA3_384: 48} {
+		hashFunc, err := HashFunction(name)
+		if err != nil {
+			t.Fatalf("Error retrieving %s: %s", name, err)
+		}
+		if len(hashFunc([]byte("data"))) != size {
+			t.Errorf("Expected a %d byte digest for %s", size, name)
+		}
+	}
+
+	if _, err := HashFunction("MD5"); err == nil {
+		t.Fatalf("Should have failed on unknown algorithm")
+	}
+}
+
+func TestHashingAlgorithmChange(t *testing.T) {
+	m := NewDescriptorImpl()
+	m.BeginConfig()
+	if err := m.ProposeConfig(hashingAlgorithmItem(SHA256)); err != nil {
+		t.Fatalf("Error applying valid config: %s", err)
+	}
+	m.CommitConfig()
+
+	m.BeginConfig()
+	if err := m.ProposeConfig(hashingAlgorithmItem(SHA256)); err != nil {
+		t.Fatalf("Should have allowed the same hashing algorithm: %s", err)
+	}
+	m.RollbackConfig()
+
+	m.BeginConfig()
+	if err := m.ProposeConfig(hashingAlgorithmItem(SHA384)); err == nil {
+		t.Fatalf("Should have rejected a change of hashing algorithm")
+	}
+	m.RollbackConfig()
+
+	sha256, _ := HashFunction(SHA256)
+	if !bytes.Equal(m.HashingAlgorithm()([]byte("data")), sha256([]byte("data"))) {
+		t.Fatalf("Should have retained the %s hashing algorithm", SHA256)
+	}
+}
+
+func TestMixedHashingAlgorithms(t *testing.T) {
+	m := NewDescriptorImpl()
+	m.BeginConfig()
+	if err := m.ProposeConfig(hashingAlgorithmItem(SHA256)); err != nil {
+		t.Fatalf("Error applying valid config: %s", err)
+	}
+	if err := m.ProposeConfig(hashingAlgorithmItem(SHA3_256)); err == nil {
+		t.Fatalf("Should have rejected conflicting hashing algorithms")
+	}
+}
+
+func TestGetHashingAlgorithm(t *testing.T) {
+	signedItem := func(item *cb.ConfigurationItem) *cb.SignedConfigurationItem {
+		return &cb.SignedConfigurationItem{ConfigurationItem: utils.MarshalOrPanic(item)}
+	}
+
+	if _, err := GetHashingAlgorithm(&cb.ConfigurationEnvelope{}); err == nil {
+		t.Fatalf("Should have rejected a configuration without a hashing algorithm")
+	}
+
+	hashFunc, err := GetHashingAlgorithm(&cb.ConfigurationEnvelope{Items: []*cb.SignedConfigurationItem{signedItem(hashingAlgorithmItem(SHA384))}})
+	if err != nil {
+		t.Fatalf("Error retrieving hashing algorithm: %s", err)
+	}
+	if len(hashFunc([]byte("data"))) != 48 {
+		t.Errorf("Should have returned the %s hashing algorithm", SHA384)
+	}
+
+	_, err = GetHashingAlgorithm(&cb.ConfigurationEnvelope{Items: []*cb.SignedConfigurationItem{
+		signedItem(hashingAlgorithmItem(SHA384)),
+		signedItem(hashingAlgorithmItem(SHA256)),
+	}})
+	if err == nil {
+		t.Fatalf("Should have rejected conflicting hashing algorithms")
+	}
+}
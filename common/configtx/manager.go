@@ -72,6 +72,7 @@ type configurationManager struct {
 	pm            policies.Manager
 	configuration map[cb.ConfigurationItem_ConfigurationType]map[string]*cb.ConfigurationItem
 	handlers      map[cb.ConfigurationItem_ConfigurationType]Handler
+	initialized   bool
 }
 
 // computeChainIDAndSequence returns the chain id and the sequence number for a configuration envelope
@@ -227,12 +228,14 @@ func (cm *configurationManager) processConfig(configtx *cb.ConfigurationEnvelope
 		}
 
 		// Ensure the config sequence numbers are correct to prevent replay attacks
+		// The items of the initial configuration, which may be the latest configuration
+		// of a chain being restarted, may have been modified by earlier configtxs
 		isModified := false
 
 		if val, ok := cm.configuration[config.Type][config.Key]; ok {
 			// Config was modified if the LastModified or the Data contents changed
 			isModified = (val.LastModified != config.LastModified) || !bytes.Equal(config.Value, val.Value)
-		} else {
+		} else if cm.initialized {
 			if config.LastModified != seq {
 				return nil, fmt.Errorf("Key %v for type %v was new, but had an older Sequence %d set", config.Key, config.Type, config.LastModified)
 			}
@@ -290,6 +293,7 @@ func (cm *configurationManager) Apply(configtx *cb.ConfigurationEnvelope) error
 	}
 	cm.configuration = configMap
 	cm.sequence++
+	cm.initialized = true
 	cm.commitHandlers()
 	return nil
 }
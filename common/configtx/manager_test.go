@@ -178,6 +178,44 @@ func TestValidConfigChange(t *testing.T) {
 	}
 }
 
+// TestInitialConfigModifiedEarlier tests that the initial configuration may hold items modified by earlier
+// configuration transactions, while updates must still set the LastModified of new items correctly
+func TestInitialConfigModifiedEarlier(t *testing.T) {
+	cm, err := NewConfigurationManager(&cb.ConfigurationEnvelope{
+		Items: []*cb.SignedConfigurationItem{
+			makeSignedConfigurationItem("foo", "foo", 0, []byte("foo"), defaultChain),
+			makeSignedConfigurationItem("bar", "bar", 2, []byte("bar"), defaultChain),
+		},
+	}, &mockPolicyManager{&mockPolicy{}}, defaultHandlers())
+
+	if err != nil {
+		t.Fatalf("Error constructing configuration manager: %s", err)
+	}
+
+	if cm.Sequence() != 2 {
+		t.Fatalf("Expected the sequence of the initial configuration to be 2, got %d", cm.Sequence())
+	}
+
+	newConfig := &cb.ConfigurationEnvelope{
+		Items: []*cb.SignedConfigurationItem{
+			makeSignedConfigurationItem("foo", "foo", 0, []byte("foo"), defaultChain),
+			makeSignedConfigurationItem("bar", "bar", 3, []byte("bar"), defaultChain),
+			makeSignedConfigurationItem("baz", "baz", 1, []byte("baz"), defaultChain),
+		},
+	}
+
+	err = cm.Validate(newConfig)
+	if err == nil {
+		t.Errorf("Should have errored validating config because a new item had an older sequence")
+	}
+
+	newConfig.Items[2] = makeSignedConfigurationItem("baz", "baz", 3, []byte("baz"), defaultChain)
+	err = cm.Apply(newConfig)
+	if err != nil {
+		t.Errorf("Should not have errored applying config: %s", err)
+	}
+}
+
 // TestConfigChangeRegressedSequence tests to make sure that a new config cannot roll back one of the
 // config values while advancing another
 func TestConfigChangeRegressedSequence(t *testing.T) {
@@ -17,12 +17,15 @@ limitations under the License.
 package test
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"os"
 
+	"github.com/hyperledger/fabric/common/cauthdsl"
 	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/common/genesis"
 	cb "github.com/hyperledger/fabric/protos/common"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
 	"github.com/hyperledger/fabric/protos/utils"
 
 	"github.com/golang/protobuf/proto"
@@ -31,6 +34,15 @@ import (
 const (
 	// AcceptAllPolicyKey is the key of the AcceptAllPolicy.
 	AcceptAllPolicyKey = "AcceptAllPolicy"
+
+	// ReadersPolicyKey is the key of the policy the creators of proposals reading the chain satisfy.
+	ReadersPolicyKey = "Readers"
+
+	// WritersPolicyKey is the key of the policy the creators of proposals writing to the chain satisfy.
+	WritersPolicyKey = "Writers"
+
+	// AdminsPolicyKey is the key of the policy the creators of proposals deploying or upgrading chaincodes satisfy.
+	AdminsPolicyKey = "Admins"
 )
 
 var template configtx.Template
@@ -44,6 +56,29 @@ func (msp MSPTemplate) Items(chainID string) ([]*cb.SignedConfigurationItem, err
 	return []*cb.SignedConfigurationItem{utils.EncodeMSP(chainID)}, nil
 }
 
+// PoliciesTemplate carries the Readers and Writers policies, satisfied by the
+// members of the MSP of MSPTemplate, and the Admins policy, satisfied by its admins
+type PoliciesTemplate struct{}
+
+func (pt PoliciesTemplate) Items(chainID string) ([]*cb.SignedConfigurationItem, error) {
+	mspConfig := &mspprotos.MSPConfig{}
+	if err := proto.Unmarshal(utils.EncodeMSPUnsigned(chainID).Value, mspConfig); err != nil {
+		return nil, err
+	}
+	fabricMSPConfig := &mspprotos.FabricMSPConfig{}
+	if err := json.Unmarshal(mspConfig.Config, fabricMSPConfig); err != nil {
+		return nil, err
+	}
+
+	members := utils.MarshalOrPanic(utils.MakePolicyOrPanic(cauthdsl.SignedByMspMember(fabricMSPConfig.Name)))
+	admins := utils.MarshalOrPanic(utils.MakePolicyOrPanic(cauthdsl.SignedByMspAdmin(fabricMSPConfig.Name)))
+	return configtx.NewSimpleTemplate(
+		utils.MakeConfigurationItem(nil, cb.ConfigurationItem_Policy, 0, configtx.DefaultModificationPolicyID, ReadersPolicyKey, members),
+		utils.MakeConfigurationItem(nil, cb.ConfigurationItem_Policy, 0, configtx.DefaultModificationPolicyID, WritersPolicyKey, members),
+		utils.MakeConfigurationItem(nil, cb.ConfigurationItem_Policy, 0, configtx.DefaultModificationPolicyID, AdminsPolicyKey, admins),
+	).Items(chainID)
+}
+
 func init() {
 
 	gopath := os.Getenv("GOPATH")
@@ -62,7 +97,7 @@ func init() {
 	}
 
 	template = configtx.NewSimpleTemplate(templateProto.Items...)
-	genesisFactory = genesis.NewFactoryImpl(configtx.NewCompositeTemplate(MSPTemplate{}, template))
+	genesisFactory = genesis.NewFactoryImpl(configtx.NewCompositeTemplate(MSPTemplate{}, PoliciesTemplate{}, template))
 }
 
 func MakeGenesisBlock(chainID string) (*cb.Block, error) {
@@ -19,12 +19,18 @@ package genesis
 import (
 	"testing"
 
+	"github.com/hyperledger/fabric/common/chainconfig"
 	"github.com/hyperledger/fabric/common/configtx"
 	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
 )
 
 func TestSanity(t *testing.T) {
-	impl := NewFactoryImpl(configtx.NewSimpleTemplate(&cb.ConfigurationItem{}))
+	impl := NewFactoryImpl(configtx.NewSimpleTemplate(&cb.ConfigurationItem{
+		Type:  cb.ConfigurationItem_Chain,
+		Key:   chainconfig.HashingAlgorithmKey,
+		Value: utils.MarshalOrPanic(&cb.HashingAlgorithm{Name: chainconfig.SHA3Shake256}),
+	}))
 	_, err := impl.Block("TestChainID")
 	if err != nil {
 		t.Fatalf("Basic sanity fails")
@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localmsp
+
+import (
+	"testing"
+
+	mspmgmt "github.com/hyperledger/fabric/core/peer/msp"
+)
+
+func init() {
+	if err := mspmgmt.LoadLocalMsp("../../msp/sampleconfig/"); err != nil {
+		panic(err)
+	}
+}
+
+func TestNewSignatureHeader(t *testing.T) {
+	sh, err := NewSigner().NewSignatureHeader()
+	if err != nil {
+		t.Fatalf("Failed creating signature header: %s", err)
+	}
+
+	if len(sh.Nonce) == 0 {
+		t.Fatalf("Signature header should carry a nonce")
+	}
+
+	if _, err := mspmgmt.GetLocalMSP().DeserializeIdentity(sh.Creator); err != nil {
+		t.Fatalf("Creator should be a serialized identity of the local MSP: %s", err)
+	}
+}
+
+func TestSign(t *testing.T) {
+	signer := NewSigner()
+	sh, err := signer.NewSignatureHeader()
+	if err != nil {
+		t.Fatalf("Failed creating signature header: %s", err)
+	}
+
+	msg := []byte("Hello World")
+	sig, err := signer.Sign(msg)
+	if err != nil {
+		t.Fatalf("Failed signing: %s", err)
+	}
+
+	identity, err := mspmgmt.GetLocalMSP().DeserializeIdentity(sh.Creator)
+	if err != nil {
+		t.Fatalf("Failed deserializing creator: %s", err)
+	}
+
+	if err := identity.Verify(msg, sig); err != nil {
+		t.Fatalf("Signature should have verified: %s", err)
+	}
+
+	if err := identity.Verify([]byte("Goodbye World"), sig); err == nil {
+		t.Fatalf("Signature should not have verified over a different message")
+	}
+}
@@ -18,6 +18,7 @@ package chaincode
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/ledger"
@@ -82,6 +83,9 @@ func (c *ccProviderImpl) GetCCValidationInfoFromLCCC(ctxt context.Context, txid
 	if data != nil && data.Vscc != "" {
 		vscc = data.Vscc
 	}
+	if !IsRegisteredSysCC(vscc, ValidationPlugin) {
+		return "", nil, fmt.Errorf("vscc %s of chaincode %s is not registered on this peer", vscc, chaincodeID)
+	}
 
 	return vscc, data.Policy, nil
 }
@@ -119,5 +119,5 @@ func Execute(ctxt context.Context, cccid *CCContext, spec interface{}) ([]byte,
 var errFailedToGetChainCodeSpecForTransaction = errors.New("Failed to get ChainCodeSpec from Transaction")
 
 func sendTxRejectedEvent(tx *pb.Transaction, errorMsg string) {
-	producer.Send(producer.CreateRejectionEvent(tx, errorMsg))
+	producer.Send(producer.CreateRejectionEvent(tx, pb.TxValidationCode_INVALID_OTHER_REASON, errorMsg))
 }
@@ -27,6 +27,8 @@ import (
 	"github.com/hyperledger/fabric/common/util"
 	ccintf "github.com/hyperledger/fabric/core/container/ccintf"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/peer"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/looplab/fsm"
@@ -389,6 +391,12 @@ func newChaincodeSupportHandler(chaincodeSupport *ChaincodeSupport, peerChatStre
 			{Name: pb.ChaincodeMessage_RANGE_QUERY_STATE_NEXT.String(), Src: []string{initstate}, Dst: initstate},
 			{Name: pb.ChaincodeMessage_RANGE_QUERY_STATE_CLOSE.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_RANGE_QUERY_STATE_CLOSE.String(), Src: []string{initstate}, Dst: initstate},
+			{Name: pb.ChaincodeMessage_GET_QUERY_RESULT.String(), Src: []string{readystate}, Dst: readystate},
+			{Name: pb.ChaincodeMessage_GET_QUERY_RESULT.String(), Src: []string{initstate}, Dst: initstate},
+			{Name: pb.ChaincodeMessage_GET_HISTORY_FOR_KEY.String(), Src: []string{readystate}, Dst: readystate},
+			{Name: pb.ChaincodeMessage_GET_HISTORY_FOR_KEY.String(), Src: []string{initstate}, Dst: initstate},
+			{Name: pb.ChaincodeMessage_GET_HISTORY_FOR_KEY_NEXT.String(), Src: []string{readystate}, Dst: readystate},
+			{Name: pb.ChaincodeMessage_GET_HISTORY_FOR_KEY_NEXT.String(), Src: []string{initstate}, Dst: initstate},
 			{Name: pb.ChaincodeMessage_ERROR.String(), Src: []string{initstate}, Dst: endstate},
 			{Name: pb.ChaincodeMessage_ERROR.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_RESPONSE.String(), Src: []string{initstate}, Dst: initstate},
@@ -396,20 +404,23 @@ func newChaincodeSupportHandler(chaincodeSupport *ChaincodeSupport, peerChatStre
 			{Name: pb.ChaincodeMessage_TRANSACTION.String(), Src: []string{readystate}, Dst: readystate},
 		},
 		fsm.Callbacks{
-			"before_" + pb.ChaincodeMessage_REGISTER.String():               func(e *fsm.Event) { v.beforeRegisterEvent(e, v.FSM.Current()) },
-			"before_" + pb.ChaincodeMessage_COMPLETED.String():              func(e *fsm.Event) { v.beforeCompletedEvent(e, v.FSM.Current()) },
-			"before_" + pb.ChaincodeMessage_INIT.String():                   func(e *fsm.Event) { v.beforeInitState(e, v.FSM.Current()) },
-			"after_" + pb.ChaincodeMessage_GET_STATE.String():               func(e *fsm.Event) { v.afterGetState(e, v.FSM.Current()) },
-			"after_" + pb.ChaincodeMessage_RANGE_QUERY_STATE.String():       func(e *fsm.Event) { v.afterRangeQueryState(e, v.FSM.Current()) },
-			"after_" + pb.ChaincodeMessage_RANGE_QUERY_STATE_NEXT.String():  func(e *fsm.Event) { v.afterRangeQueryStateNext(e, v.FSM.Current()) },
-			"after_" + pb.ChaincodeMessage_RANGE_QUERY_STATE_CLOSE.String(): func(e *fsm.Event) { v.afterRangeQueryStateClose(e, v.FSM.Current()) },
-			"after_" + pb.ChaincodeMessage_PUT_STATE.String():               func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
-			"after_" + pb.ChaincodeMessage_DEL_STATE.String():               func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
-			"after_" + pb.ChaincodeMessage_INVOKE_CHAINCODE.String():        func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
-			"enter_" + establishedstate:                                     func(e *fsm.Event) { v.enterEstablishedState(e, v.FSM.Current()) },
-			"enter_" + initstate:                                            func(e *fsm.Event) { v.enterInitState(e, v.FSM.Current()) },
-			"enter_" + readystate:                                           func(e *fsm.Event) { v.enterReadyState(e, v.FSM.Current()) },
-			"enter_" + endstate:                                             func(e *fsm.Event) { v.enterEndState(e, v.FSM.Current()) },
+			"before_" + pb.ChaincodeMessage_REGISTER.String():                func(e *fsm.Event) { v.beforeRegisterEvent(e, v.FSM.Current()) },
+			"before_" + pb.ChaincodeMessage_COMPLETED.String():               func(e *fsm.Event) { v.beforeCompletedEvent(e, v.FSM.Current()) },
+			"before_" + pb.ChaincodeMessage_INIT.String():                    func(e *fsm.Event) { v.beforeInitState(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_GET_STATE.String():                func(e *fsm.Event) { v.afterGetState(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_RANGE_QUERY_STATE.String():        func(e *fsm.Event) { v.afterRangeQueryState(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_RANGE_QUERY_STATE_NEXT.String():   func(e *fsm.Event) { v.afterRangeQueryStateNext(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_RANGE_QUERY_STATE_CLOSE.String():  func(e *fsm.Event) { v.afterRangeQueryStateClose(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_GET_QUERY_RESULT.String():         func(e *fsm.Event) { v.afterGetQueryResult(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_GET_HISTORY_FOR_KEY.String():      func(e *fsm.Event) { v.afterGetHistoryForKey(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_GET_HISTORY_FOR_KEY_NEXT.String(): func(e *fsm.Event) { v.afterGetHistoryForKeyNext(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_PUT_STATE.String():                func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_DEL_STATE.String():                func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_INVOKE_CHAINCODE.String():         func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
+			"enter_" + establishedstate:                                      func(e *fsm.Event) { v.enterEstablishedState(e, v.FSM.Current()) },
+			"enter_" + initstate:                                             func(e *fsm.Event) { v.enterInitState(e, v.FSM.Current()) },
+			"enter_" + readystate:                                            func(e *fsm.Event) { v.enterReadyState(e, v.FSM.Current()) },
+			"enter_" + endstate:                                              func(e *fsm.Event) { v.enterEndState(e, v.FSM.Current()) },
 		},
 	)
 
@@ -601,8 +612,6 @@ func (handler *Handler) handleGetState(msg *pb.ChaincodeMessage) {
 	}()
 }
 
-const maxRangeQueryStateLimit = 100
-
 // afterRangeQueryState handles a RANGE_QUERY_STATE request from the chaincode.
 func (handler *Handler) afterRangeQueryState(e *fsm.Event, state string) {
 	msg, ok := e.Args[0].(*pb.ChaincodeMessage)
@@ -617,6 +626,178 @@ func (handler *Handler) afterRangeQueryState(e *fsm.Event, state string) {
 	chaincodeLogger.Debug("Exiting GET_STATE")
 }
 
+// toRangeQueryStateKeyValue converts a result of a range query or of a rich query
+func toRangeQueryStateKeyValue(qresult ledger.QueryResult) (*pb.RangeQueryStateKeyValue, error) {
+	switch r := qresult.(type) {
+	case *ledger.KV:
+		return &pb.RangeQueryStateKeyValue{Key: r.Key, Value: r.Value}, nil
+	case *ledger.QueryRecord:
+		return &pb.RangeQueryStateKeyValue{Key: r.Key, Value: r.Record}, nil
+	}
+	return nil, fmt.Errorf("Unexpected query result type %T", qresult)
+}
+
+// getRangeQueryStateResponse collects the next batch of results of a range query or of a rich
+// query iterator. The iterator is closed and removed from the transaction context once exhausted
+func (handler *Handler) getRangeQueryStateResponse(iter ledger.ResultsIterator, txContext *transactionContext, iterID string) (*pb.RangeQueryStateResponse, error) {
+	var keysAndValues []*pb.RangeQueryStateKeyValue
+	var qresult ledger.QueryResult
+	var err error
+	batchSize := ledgerconfig.GetQueryBatchSize()
+	for i := 0; i < batchSize; i++ {
+		qresult, err = iter.Next()
+		if err != nil {
+			break
+		}
+		if qresult == nil {
+			break
+		}
+		var keyAndValue *pb.RangeQueryStateKeyValue
+		if keyAndValue, err = toRangeQueryStateKeyValue(qresult); err != nil {
+			break
+		}
+		keysAndValues = append(keysAndValues, keyAndValue)
+	}
+
+	if err != nil || qresult == nil {
+		iter.Close()
+		handler.deleteRangeQueryIterator(txContext, iterID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pb.RangeQueryStateResponse{KeysAndValues: keysAndValues, HasMore: qresult != nil, ID: iterID}, nil
+}
+
+// getHistoryForKeyResponse collects the next batch of results of a key history iterator.
+// The iterator is closed and removed from the transaction context once exhausted
+func (handler *Handler) getHistoryForKeyResponse(iter ledger.ResultsIterator, txContext *transactionContext, iterID string) (*pb.GetHistoryForKeyResponse, error) {
+	var keyModifications []*pb.KeyModification
+	var qresult ledger.QueryResult
+	var err error
+	batchSize := ledgerconfig.GetQueryBatchSize()
+	for i := 0; i < batchSize; i++ {
+		qresult, err = iter.Next()
+		if err != nil {
+			break
+		}
+		if qresult == nil {
+			break
+		}
+		km, ok := qresult.(*ledger.KeyModification)
+		if !ok {
+			err = fmt.Errorf("Unexpected query result type %T", qresult)
+			break
+		}
+		keyModifications = append(keyModifications, &pb.KeyModification{TxID: km.TxID, Value: km.Value})
+	}
+
+	if err != nil || qresult == nil {
+		iter.Close()
+		handler.deleteRangeQueryIterator(txContext, iterID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetHistoryForKeyResponse{KeyModifications: keyModifications, HasMore: qresult != nil, ID: iterID}, nil
+}
+
+// getPageOfResults collects at most pageSize results of a paginated range query or rich query and
+// closes the iterator. The result following the page is returned as well, nil if there is none
+func getPageOfResults(iter ledger.ResultsIterator, pageSize int) ([]*pb.RangeQueryStateKeyValue, *pb.RangeQueryStateKeyValue, error) {
+	defer iter.Close()
+	var keysAndValues []*pb.RangeQueryStateKeyValue
+	for i := 0; i <= pageSize; i++ {
+		qresult, err := iter.Next()
+		if err != nil {
+			return nil, nil, err
+		}
+		if qresult == nil {
+			break
+		}
+		keyAndValue, err := toRangeQueryStateKeyValue(qresult)
+		if err != nil {
+			return nil, nil, err
+		}
+		if i == pageSize {
+			return keysAndValues, keyAndValue, nil
+		}
+		keysAndValues = append(keysAndValues, keyAndValue)
+	}
+	return keysAndValues, nil, nil
+}
+
+func checkPageSize(pageSize int32) error {
+	if maxPageSize := ledgerconfig.GetMaxQueryPageSize(); int(pageSize) > maxPageSize {
+		return fmt.Errorf("Page size %d exceeds the maximum page size %d", pageSize, maxPageSize)
+	}
+	return nil
+}
+
+// getRangeQueryStatePage returns a page of the results of a paginated range query, starting from the
+// bookmark if any. The bookmark of the next page is the key following the page
+func getRangeQueryStatePage(txsim ledger.TxSimulator, chaincodeID string, rangeQueryState *pb.RangeQueryState) (*pb.RangeQueryStateResponse, error) {
+	if err := checkPageSize(rangeQueryState.PageSize); err != nil {
+		return nil, err
+	}
+	startKey := rangeQueryState.StartKey
+	if bookmark := rangeQueryState.Bookmark; bookmark != "" {
+		if bookmark < startKey || (rangeQueryState.EndKey != "" && bookmark >= rangeQueryState.EndKey) {
+			return nil, fmt.Errorf("Bookmark %s is not within the range of the query", bookmark)
+		}
+		startKey = bookmark
+	}
+	rangeIter, err := txsim.GetStateRangeScanIterator(chaincodeID, startKey, rangeQueryState.EndKey)
+	if err != nil {
+		return nil, err
+	}
+	keysAndValues, next, err := getPageOfResults(rangeIter, int(rangeQueryState.PageSize))
+	if err != nil {
+		return nil, err
+	}
+	response := &pb.RangeQueryStateResponse{KeysAndValues: keysAndValues}
+	if next != nil {
+		response.Bookmark = next.Key
+	}
+	return response, nil
+}
+
+// getQueryResultPage returns a page of the results of a paginated rich query, which are ordered
+// by key, starting from the bookmark if any. The bookmark of the next page is the key of its first result
+func getQueryResultPage(txsim ledger.TxSimulator, chaincodeID string, getQueryResult *pb.GetQueryResult) (*pb.RangeQueryStateResponse, error) {
+	if err := checkPageSize(getQueryResult.PageSize); err != nil {
+		return nil, err
+	}
+	pageSize := int(getQueryResult.PageSize)
+	queryIter, err := txsim.ExecuteQueryWithPagination(chaincodeID, getQueryResult.Query, getQueryResult.Bookmark, pageSize+1)
+	if err != nil {
+		return nil, err
+	}
+	keysAndValues, next, err := getPageOfResults(queryIter, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	response := &pb.RangeQueryStateResponse{KeysAndValues: keysAndValues}
+	if next != nil {
+		response.Bookmark = next.Key
+	}
+	return response, nil
+}
+
+// queryPageMessage marshals the page of a paginated query into the message to be sent to the chaincode
+func queryPageMessage(payload *pb.RangeQueryStateResponse, err error, txid string) *pb.ChaincodeMessage {
+	var payloadBytes []byte
+	if err == nil {
+		payloadBytes, err = proto.Marshal(payload)
+	}
+	if err != nil {
+		chaincodeLogger.Errorf("Failed to get the page of the query. Sending %s", pb.ChaincodeMessage_ERROR)
+		return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte(err.Error()), Txid: txid}
+	}
+	chaincodeLogger.Debugf("Got the page of the query. Sending %s", pb.ChaincodeMessage_RESPONSE)
+	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: payloadBytes, Txid: txid}
+}
+
 // Handles query to ledger to rage query state
 func (handler *Handler) handleRangeQueryState(msg *pb.ChaincodeMessage) {
 	// The defer followed by triggering a go routine dance is needed to ensure that the previous state transition
@@ -658,6 +839,12 @@ func (handler *Handler) handleRangeQueryState(msg *pb.ChaincodeMessage) {
 		}
 		chaincodeID := handler.getCCRootName()
 
+		if rangeQueryState.PageSize > 0 {
+			payload, err := getRangeQueryStatePage(txContext.txsimulator, chaincodeID, rangeQueryState)
+			serialSendMsg = queryPageMessage(payload, err, msg.Txid)
+			return
+		}
+
 		rangeIter, err := txContext.txsimulator.GetStateRangeScanIterator(chaincodeID, rangeQueryState.StartKey, rangeQueryState.EndKey)
 		if err != nil {
 			// Send error msg back to chaincode. GetState will not trigger event
@@ -669,29 +856,14 @@ func (handler *Handler) handleRangeQueryState(msg *pb.ChaincodeMessage) {
 
 		handler.putRangeQueryIterator(txContext, iterID, rangeIter)
 
-		var keysAndValues []*pb.RangeQueryStateKeyValue
-		var i = uint32(0)
-		var qresult ledger.QueryResult
-		for ; i < maxRangeQueryStateLimit; i++ {
-			qresult, err := rangeIter.Next()
-			if err != nil {
-				chaincodeLogger.Errorf("Failed to get query result from iterator. Sending %s", pb.ChaincodeMessage_ERROR)
-				return
-			}
-			if qresult == nil {
-				break
-			}
-			kv := qresult.(*ledger.KV)
-			keyAndValue := pb.RangeQueryStateKeyValue{Key: kv.Key, Value: kv.Value}
-			keysAndValues = append(keysAndValues, &keyAndValue)
-		}
-
-		if qresult != nil {
-			rangeIter.Close()
-			handler.deleteRangeQueryIterator(txContext, iterID)
+		payload, err := handler.getRangeQueryStateResponse(rangeIter, txContext, iterID)
+		if err != nil {
+			payload := []byte(err.Error())
+			chaincodeLogger.Errorf("Failed to get query result from iterator. Sending %s", pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
+			return
 		}
 
-		payload := &pb.RangeQueryStateResponse{KeysAndValues: keysAndValues, HasMore: qresult != nil, ID: iterID}
 		payloadBytes, err := proto.Marshal(payload)
 		if err != nil {
 			rangeIter.Close()
@@ -724,7 +896,7 @@ func (handler *Handler) afterRangeQueryStateNext(e *fsm.Event, state string) {
 	chaincodeLogger.Debug("Exiting RANGE_QUERY_STATE_NEXT")
 }
 
-// Handles query to ledger to rage query state next
+// Handles query to ledger to rage query state next. The iterator may also be the one of a rich query
 func (handler *Handler) handleRangeQueryStateNext(msg *pb.ChaincodeMessage) {
 	// The defer followed by triggering a go routine dance is needed to ensure that the previous state transition
 	// is completed before the next one is triggered. The previous state transition is deemed complete only when
@@ -755,7 +927,12 @@ func (handler *Handler) handleRangeQueryStateNext(msg *pb.ChaincodeMessage) {
 			return
 		}
 
-		txContext := handler.getTxContext(msg.Txid)
+		var txContext *transactionContext
+
+		txContext, serialSendMsg = handler.isValidTxSim(msg.Txid, "[%s]No ledger context for RangeQueryStateNext. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+		if txContext == nil {
+			return
+		}
 		rangeIter := handler.getRangeQueryIterator(txContext, rangeQueryStateNext.ID)
 
 		if rangeIter == nil {
@@ -765,31 +942,14 @@ func (handler *Handler) handleRangeQueryStateNext(msg *pb.ChaincodeMessage) {
 			return
 		}
 
-		var keysAndValues []*pb.RangeQueryStateKeyValue
-		var i = uint32(0)
-
-		var qresult ledger.QueryResult
-		var err error
-		for ; i < maxRangeQueryStateLimit; i++ {
-			qresult, err = rangeIter.Next()
-			if err != nil {
-				chaincodeLogger.Errorf("Failed to get query result from iterator. Sending %s", pb.ChaincodeMessage_ERROR)
-				return
-			}
-			if qresult != nil {
-				break
-			}
-			kv := qresult.(*ledger.KV)
-			keyAndValue := pb.RangeQueryStateKeyValue{Key: kv.Key, Value: kv.Value}
-			keysAndValues = append(keysAndValues, &keyAndValue)
-		}
-
-		if qresult != nil {
-			rangeIter.Close()
-			handler.deleteRangeQueryIterator(txContext, rangeQueryStateNext.ID)
+		payload, err := handler.getRangeQueryStateResponse(rangeIter, txContext, rangeQueryStateNext.ID)
+		if err != nil {
+			payload := []byte(err.Error())
+			chaincodeLogger.Errorf("Failed to get query result from iterator. Sending %s", pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
+			return
 		}
 
-		payload := &pb.RangeQueryStateResponse{KeysAndValues: keysAndValues, HasMore: qresult != nil, ID: rangeQueryStateNext.ID}
 		payloadBytes, err := proto.Marshal(payload)
 		if err != nil {
 			rangeIter.Close()
@@ -822,7 +982,8 @@ func (handler *Handler) afterRangeQueryStateClose(e *fsm.Event, state string) {
 	chaincodeLogger.Debug("Exiting RANGE_QUERY_STATE_CLOSE")
 }
 
-// Handles the closing of a state iterator
+// Handles the closing of a state iterator. This is used for range query, rich query and
+// key history iterators alike
 func (handler *Handler) handleRangeQueryStateClose(msg *pb.ChaincodeMessage) {
 	// The defer followed by triggering a go routine dance is needed to ensure that the previous state transition
 	// is completed before the next one is triggered. The previous state transition is deemed complete only when
@@ -853,7 +1014,12 @@ func (handler *Handler) handleRangeQueryStateClose(msg *pb.ChaincodeMessage) {
 			return
 		}
 
-		txContext := handler.getTxContext(msg.Txid)
+		var txContext *transactionContext
+
+		txContext, serialSendMsg = handler.isValidTxSim(msg.Txid, "[%s]No ledger context for RangeQueryStateClose. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+		if txContext == nil {
+			return
+		}
 		iter := handler.getRangeQueryIterator(txContext, rangeQueryStateClose.ID)
 		if iter != nil {
 			iter.Close()
@@ -877,6 +1043,291 @@ func (handler *Handler) handleRangeQueryStateClose(msg *pb.ChaincodeMessage) {
 	}()
 }
 
+// afterGetQueryResult handles a GET_QUERY_RESULT request from the chaincode.
+func (handler *Handler) afterGetQueryResult(e *fsm.Event, state string) {
+	msg, ok := e.Args[0].(*pb.ChaincodeMessage)
+	if !ok {
+		e.Cancel(fmt.Errorf("Received unexpected message type"))
+		return
+	}
+	chaincodeLogger.Debugf("Received %s, invoking query from ledger", pb.ChaincodeMessage_GET_QUERY_RESULT)
+
+	// Query ledger
+	handler.handleGetQueryResult(msg)
+	chaincodeLogger.Debug("Exiting GET_QUERY_RESULT")
+}
+
+// Handles a rich query to the state database. The query is restricted to the namespace of the chaincode
+func (handler *Handler) handleGetQueryResult(msg *pb.ChaincodeMessage) {
+	// The defer followed by triggering a go routine dance is needed to ensure that the previous state transition
+	// is completed before the next one is triggered. The previous state transition is deemed complete only when
+	// the afterGetQueryResult function is exited. Interesting bug fix!!
+	go func() {
+		// Check if this is the unique state request from this chaincode txid
+		uniqueReq := handler.createTXIDEntry(msg.Txid)
+		if !uniqueReq {
+			// Drop this request
+			chaincodeLogger.Error("Another state request pending for this Txid. Cannot process.")
+			return
+		}
+
+		var serialSendMsg *pb.ChaincodeMessage
+
+		defer func() {
+			handler.deleteTXIDEntry(msg.Txid)
+			chaincodeLogger.Debugf("[%s]handleGetQueryResult serial send %s", shorttxid(serialSendMsg.Txid), serialSendMsg.Type)
+			handler.serialSendAsync(serialSendMsg, nil)
+		}()
+
+		getQueryResult := &pb.GetQueryResult{}
+		unmarshalErr := proto.Unmarshal(msg.Payload, getQueryResult)
+		if unmarshalErr != nil {
+			payload := []byte(unmarshalErr.Error())
+			chaincodeLogger.Errorf("Failed to unmarshall query request. Sending %s", pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
+			return
+		}
+
+		iterID := util.GenerateUUID()
+
+		var txContext *transactionContext
+
+		txContext, serialSendMsg = handler.isValidTxSim(msg.Txid, "[%s]No ledger context for GetQueryResult. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+		if txContext == nil {
+			return
+		}
+		chaincodeID := handler.getCCRootName()
+
+		if getQueryResult.PageSize > 0 {
+			payload, err := getQueryResultPage(txContext.txsimulator, chaincodeID, getQueryResult)
+			serialSendMsg = queryPageMessage(payload, err, msg.Txid)
+			return
+		}
+
+		queryIter, err := txContext.txsimulator.ExecuteQuery(chaincodeID, getQueryResult.Query)
+		if err != nil {
+			payload := []byte(err.Error())
+			chaincodeLogger.Errorf("Failed to get ledger query iterator. Sending %s", pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
+			return
+		}
+
+		handler.putRangeQueryIterator(txContext, iterID, queryIter)
+
+		payload, err := handler.getRangeQueryStateResponse(queryIter, txContext, iterID)
+		if err != nil {
+			payload := []byte(err.Error())
+			chaincodeLogger.Errorf("Failed to get query result from iterator. Sending %s", pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
+			return
+		}
+
+		payloadBytes, err := proto.Marshal(payload)
+		if err != nil {
+			queryIter.Close()
+			handler.deleteRangeQueryIterator(txContext, iterID)
+
+			payload := []byte(err.Error())
+			chaincodeLogger.Errorf("Failed marshall resopnse. Sending %s", pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
+			return
+		}
+
+		chaincodeLogger.Debugf("Got keys and values. Sending %s", pb.ChaincodeMessage_RESPONSE)
+		serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: payloadBytes, Txid: msg.Txid}
+
+	}()
+}
+
+// afterGetHistoryForKey handles a GET_HISTORY_FOR_KEY request from the chaincode.
+func (handler *Handler) afterGetHistoryForKey(e *fsm.Event, state string) {
+	msg, ok := e.Args[0].(*pb.ChaincodeMessage)
+	if !ok {
+		e.Cancel(fmt.Errorf("Received unexpected message type"))
+		return
+	}
+	chaincodeLogger.Debugf("Received %s, invoking get history from ledger", pb.ChaincodeMessage_GET_HISTORY_FOR_KEY)
+
+	// Query ledger history db
+	handler.handleGetHistoryForKey(msg)
+	chaincodeLogger.Debug("Exiting GET_HISTORY_FOR_KEY")
+}
+
+// Handles query to the history database of the ledger for the modifications of a key of the chaincode
+func (handler *Handler) handleGetHistoryForKey(msg *pb.ChaincodeMessage) {
+	// The defer followed by triggering a go routine dance is needed to ensure that the previous state transition
+	// is completed before the next one is triggered. The previous state transition is deemed complete only when
+	// the afterGetHistoryForKey function is exited. Interesting bug fix!!
+	go func() {
+		// Check if this is the unique state request from this chaincode txid
+		uniqueReq := handler.createTXIDEntry(msg.Txid)
+		if !uniqueReq {
+			// Drop this request
+			chaincodeLogger.Error("Another state request pending for this Txid. Cannot process.")
+			return
+		}
+
+		var serialSendMsg *pb.ChaincodeMessage
+
+		defer func() {
+			handler.deleteTXIDEntry(msg.Txid)
+			chaincodeLogger.Debugf("[%s]handleGetHistoryForKey serial send %s", shorttxid(serialSendMsg.Txid), serialSendMsg.Type)
+			handler.serialSendAsync(serialSendMsg, nil)
+		}()
+
+		getHistoryForKey := &pb.GetHistoryForKey{}
+		unmarshalErr := proto.Unmarshal(msg.Payload, getHistoryForKey)
+		if unmarshalErr != nil {
+			payload := []byte(unmarshalErr.Error())
+			chaincodeLogger.Errorf("Failed to unmarshall key history request. Sending %s", pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
+			return
+		}
+
+		iterID := util.GenerateUUID()
+
+		var txContext *transactionContext
+
+		txContext, serialSendMsg = handler.isValidTxSim(msg.Txid, "[%s]No ledger context for GetHistoryForKey. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+		if txContext == nil {
+			return
+		}
+		chaincodeID := handler.getCCRootName()
+
+		historyIter, err := getHistoryIterator(txContext.chainID, chaincodeID, getHistoryForKey.Key)
+		if err != nil {
+			payload := []byte(err.Error())
+			chaincodeLogger.Errorf("Failed to get ledger history iterator. Sending %s", pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
+			return
+		}
+
+		handler.putRangeQueryIterator(txContext, iterID, historyIter)
+
+		payload, err := handler.getHistoryForKeyResponse(historyIter, txContext, iterID)
+		if err != nil {
+			payload := []byte(err.Error())
+			chaincodeLogger.Errorf("Failed to get history result from iterator. Sending %s", pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
+			return
+		}
+
+		payloadBytes, err := proto.Marshal(payload)
+		if err != nil {
+			historyIter.Close()
+			handler.deleteRangeQueryIterator(txContext, iterID)
+
+			payload := []byte(err.Error())
+			chaincodeLogger.Errorf("Failed marshall resopnse. Sending %s", pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
+			return
+		}
+
+		chaincodeLogger.Debugf("Got key modifications. Sending %s", pb.ChaincodeMessage_RESPONSE)
+		serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: payloadBytes, Txid: msg.Txid}
+
+	}()
+}
+
+// getHistoryIterator returns an iterator over the modifications of the key of the chaincode,
+// including the values written by each transaction
+func getHistoryIterator(chainID string, chaincodeID string, key string) (ledger.ResultsIterator, error) {
+	lgr := peer.GetLedger(chainID)
+	if lgr == nil {
+		return nil, fmt.Errorf("Ledger for chain %s not found", chainID)
+	}
+	historyQueryExecutor, err := lgr.NewHistoryQueryExecutor()
+	if err != nil {
+		return nil, err
+	}
+	return historyQueryExecutor.GetTransactionsForKey(chaincodeID, key, true, false)
+}
+
+// afterGetHistoryForKeyNext handles a GET_HISTORY_FOR_KEY_NEXT request from the chaincode.
+func (handler *Handler) afterGetHistoryForKeyNext(e *fsm.Event, state string) {
+	msg, ok := e.Args[0].(*pb.ChaincodeMessage)
+	if !ok {
+		e.Cancel(fmt.Errorf("Received unexpected message type"))
+		return
+	}
+	chaincodeLogger.Debugf("Received %s, invoking get history from ledger", pb.ChaincodeMessage_GET_HISTORY_FOR_KEY_NEXT)
+
+	// Query ledger history db
+	handler.handleGetHistoryForKeyNext(msg)
+	chaincodeLogger.Debug("Exiting GET_HISTORY_FOR_KEY_NEXT")
+}
+
+// Handles the retrieval of the next batch of modifications of a key history iterator
+func (handler *Handler) handleGetHistoryForKeyNext(msg *pb.ChaincodeMessage) {
+	// The defer followed by triggering a go routine dance is needed to ensure that the previous state transition
+	// is completed before the next one is triggered. The previous state transition is deemed complete only when
+	// the afterGetHistoryForKeyNext function is exited. Interesting bug fix!!
+	go func() {
+		// Check if this is the unique state request from this chaincode txid
+		uniqueReq := handler.createTXIDEntry(msg.Txid)
+		if !uniqueReq {
+			// Drop this request
+			chaincodeLogger.Error("Another state request pending for this Txid. Cannot process.")
+			return
+		}
+
+		var serialSendMsg *pb.ChaincodeMessage
+
+		defer func() {
+			handler.deleteTXIDEntry(msg.Txid)
+			chaincodeLogger.Debugf("[%s]handleGetHistoryForKeyNext serial send %s", shorttxid(serialSendMsg.Txid), serialSendMsg.Type)
+			handler.serialSendAsync(serialSendMsg, nil)
+		}()
+
+		getHistoryForKeyNext := &pb.GetHistoryForKeyNext{}
+		unmarshalErr := proto.Unmarshal(msg.Payload, getHistoryForKeyNext)
+		if unmarshalErr != nil {
+			payload := []byte(unmarshalErr.Error())
+			chaincodeLogger.Errorf("Failed to unmarshall key history next request. Sending %s", pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
+			return
+		}
+
+		var txContext *transactionContext
+
+		txContext, serialSendMsg = handler.isValidTxSim(msg.Txid, "[%s]No ledger context for GetHistoryForKeyNext. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+		if txContext == nil {
+			return
+		}
+		historyIter := handler.getRangeQueryIterator(txContext, getHistoryForKeyNext.ID)
+
+		if historyIter == nil {
+			payload := []byte("History query iterator not found")
+			chaincodeLogger.Errorf("History query iterator not found. Sending %s", pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
+			return
+		}
+
+		payload, err := handler.getHistoryForKeyResponse(historyIter, txContext, getHistoryForKeyNext.ID)
+		if err != nil {
+			payload := []byte(err.Error())
+			chaincodeLogger.Errorf("Failed to get history result from iterator. Sending %s", pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
+			return
+		}
+
+		payloadBytes, err := proto.Marshal(payload)
+		if err != nil {
+			historyIter.Close()
+			handler.deleteRangeQueryIterator(txContext, getHistoryForKeyNext.ID)
+
+			payload := []byte(err.Error())
+			chaincodeLogger.Errorf("Failed marshall resopnse. Sending %s", pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
+			return
+		}
+
+		chaincodeLogger.Debugf("Got key modifications. Sending %s", pb.ChaincodeMessage_RESPONSE)
+		serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: payloadBytes, Txid: msg.Txid}
+
+	}()
+}
+
 // afterPutState handles a PUT_STATE request from the chaincode.
 func (handler *Handler) afterPutState(e *fsm.Event, state string) {
 	_, ok := e.Args[0].(*pb.ChaincodeMessage)
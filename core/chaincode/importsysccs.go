@@ -43,6 +43,7 @@ var systemChaincodes = []*SystemChaincode{
 	{
 		ChainlessCC: false,
 		Enabled:     true,
+		Plugin:      EndorsementPlugin,
 		Name:        "escc",
 		Path:        "github.com/hyperledger/fabric/core/system_chaincode/escc",
 		InitArgs:    [][]byte{[]byte("")},
@@ -51,6 +52,7 @@ var systemChaincodes = []*SystemChaincode{
 	{
 		ChainlessCC: false,
 		Enabled:     true,
+		Plugin:      ValidationPlugin,
 		Name:        "vscc",
 		Path:        "github.com/hyperledger/fabric/core/system_chaincode/vscc",
 		InitArgs:    [][]byte{[]byte("")},
@@ -126,3 +128,18 @@ func IsChainlessSysCC(name string) bool {
 	}
 	return false
 }
+
+//IsRegisteredSysCC returns true if the name matches a system chaincode that is
+//enabled and whitelisted on this peer, that can be invoked on a chain and that
+//chaincodes may select as the given plugin, i.e., as their escc or their vscc
+func IsRegisteredSysCC(name string, plugin SysCCPlugin) bool {
+	if plugin == NotAPlugin {
+		return false
+	}
+	for _, sysCC := range systemChaincodes {
+		if sysCC.Name == name {
+			return sysCC.Plugin == plugin && sysCC.Enabled && !sysCC.ChainlessCC && isWhitelisted(sysCC)
+		}
+	}
+	return false
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/op/go-logging"
 	"golang.org/x/net/context"
@@ -54,8 +55,8 @@ func (*ChaincodeData) ProtoMessage() {}
 
 //The life cycle system chaincode manages chaincodes deployed
 //on this peer. It manages chaincodes via Invoke proposals.
-//     "Args":["deploy",<ChaincodeDeploymentSpec>]
-//     "Args":["upgrade",<ChaincodeDeploymentSpec>]
+//     "Args":["deploy",<ChaincodeDeploymentSpec>,<endorsement policy, optional>,<escc, optional>,<vscc, optional>]
+//     "Args":["upgrade",<ChaincodeDeploymentSpec>,<endorsement policy, optional>,<escc, optional>,<vscc, optional>]
 //     "Args":["stop",<ChaincodeInvocationSpec>]
 //     "Args":["start",<ChaincodeInvocationSpec>]
 
@@ -87,6 +88,11 @@ const (
 
 	// chaincode version when deploy
 	startVersion = "0"
+
+	//system chaincodes endorsing and validating the transactions of
+	//the chaincodes that are deployed without selecting them
+	defaultEscc = "escc"
+	defaultVscc = "vscc"
 )
 
 //---------- the LCCC -----------------
@@ -174,6 +180,20 @@ func (f InvalidChaincodeNameErr) Error() string {
 	return fmt.Sprintf("invalid chain code name %s", string(f))
 }
 
+//InvalidPolicyErr invalid endorsement policy error
+type InvalidPolicyErr string
+
+func (f InvalidPolicyErr) Error() string {
+	return fmt.Sprintf("invalid endorsement policy : %s", string(f))
+}
+
+//InvalidSysCCErr the escc or vscc is not a system chaincode registered on the peer
+type InvalidSysCCErr string
+
+func (f InvalidSysCCErr) Error() string {
+	return fmt.Sprintf("%s is not a registered system chaincode", string(f))
+}
+
 //MarshallErr error marshaling/unmarshalling
 type MarshallErr string
 
@@ -183,18 +203,18 @@ func (m MarshallErr) Error() string {
 
 //-------------- helper functions ------------------
 //create the chaincode on the given chain
-func (lccc *LifeCycleSysCC) createChaincode(stub shim.ChaincodeStubInterface, chainname string, ccname string, cccode []byte) (*ChaincodeData, error) {
-	return lccc.putChaincodeData(stub, chainname, ccname, startVersion, cccode)
+func (lccc *LifeCycleSysCC) createChaincode(stub shim.ChaincodeStubInterface, chainname string, ccname string, cccode []byte, policy []byte, escc string, vscc string) (*ChaincodeData, error) {
+	return lccc.putChaincodeData(stub, chainname, ccname, startVersion, cccode, policy, escc, vscc)
 }
 
 //upgrade the chaincode on the given chain
-func (lccc *LifeCycleSysCC) upgradeChaincode(stub shim.ChaincodeStubInterface, chainname string, ccname string, version string, cccode []byte) (*ChaincodeData, error) {
-	return lccc.putChaincodeData(stub, chainname, ccname, version, cccode)
+func (lccc *LifeCycleSysCC) upgradeChaincode(stub shim.ChaincodeStubInterface, chainname string, ccname string, version string, cccode []byte, policy []byte, escc string, vscc string) (*ChaincodeData, error) {
+	return lccc.putChaincodeData(stub, chainname, ccname, version, cccode, policy, escc, vscc)
 }
 
 //create the chaincode on the given chain
-func (lccc *LifeCycleSysCC) putChaincodeData(stub shim.ChaincodeStubInterface, chainname string, ccname string, version string, cccode []byte) (*ChaincodeData, error) {
-	cd := &ChaincodeData{Name: ccname, Version: version, DepSpec: cccode}
+func (lccc *LifeCycleSysCC) putChaincodeData(stub shim.ChaincodeStubInterface, chainname string, ccname string, version string, cccode []byte, policy []byte, escc string, vscc string) (*ChaincodeData, error) {
+	cd := &ChaincodeData{Name: ccname, Version: version, DepSpec: cccode, Escc: escc, Vscc: vscc, Policy: policy}
 	cdbytes, err := proto.Marshal(cd)
 	if err != nil {
 		return nil, err
@@ -241,6 +261,35 @@ func (lccc *LifeCycleSysCC) getChaincodeDeploymentSpec(code []byte) (*pb.Chainco
 	return cds, nil
 }
 
+//checks that the endorsement policy, if any, is a well formed signature policy.
+//The transactions of a chaincode deployed without an endorsement policy are
+//validated against the default policy of the committer
+func (lccc *LifeCycleSysCC) checkEndorsementPolicy(policy []byte) error {
+	if len(policy) == 0 {
+		return nil
+	}
+	spe := &common.SignaturePolicyEnvelope{}
+	if err := proto.Unmarshal(policy, spe); err != nil {
+		return InvalidPolicyErr(err.Error())
+	}
+	if spe.Policy == nil {
+		return InvalidPolicyErr("no policy in the signature policy envelope")
+	}
+	return nil
+}
+
+//checks that the system chaincodes selected to endorse and to validate the
+//transactions of the chaincode are registered on this peer as such
+func (lccc *LifeCycleSysCC) checkEsccAndVscc(escc string, vscc string) error {
+	if !IsRegisteredSysCC(escc, EndorsementPlugin) {
+		return InvalidSysCCErr(escc)
+	}
+	if !IsRegisteredSysCC(vscc, ValidationPlugin) {
+		return InvalidSysCCErr(vscc)
+	}
+	return nil
+}
+
 //do access control
 func (lccc *LifeCycleSysCC) acl(stub shim.ChaincodeStubInterface, chainname string, cds *pb.ChaincodeDeploymentSpec) error {
 	return nil
@@ -323,7 +372,7 @@ func (lccc *LifeCycleSysCC) deploy(stub shim.ChaincodeStubInterface, chainname s
 }
 
 //this implements "deploy" Invoke transaction
-func (lccc *LifeCycleSysCC) executeDeploy(stub shim.ChaincodeStubInterface, chainname string, code []byte) error {
+func (lccc *LifeCycleSysCC) executeDeploy(stub shim.ChaincodeStubInterface, chainname string, code []byte, policy []byte, escc string, vscc string) error {
 	cds, err := lccc.getChaincodeDeploymentSpec(code)
 
 	if err != nil {
@@ -338,6 +387,20 @@ func (lccc *LifeCycleSysCC) executeDeploy(stub shim.ChaincodeStubInterface, chai
 		return err
 	}
 
+	if err = lccc.checkEndorsementPolicy(policy); err != nil {
+		return err
+	}
+
+	if escc == "" {
+		escc = defaultEscc
+	}
+	if vscc == "" {
+		vscc = defaultVscc
+	}
+	if err = lccc.checkEsccAndVscc(escc, vscc); err != nil {
+		return err
+	}
+
 	cd, _, err := lccc.getChaincode(stub, chainname, cds.ChaincodeSpec.ChaincodeID.Name)
 	if cd != nil {
 		return ExistsErr(cds.ChaincodeSpec.ChaincodeID.Name)
@@ -351,13 +414,14 @@ func (lccc *LifeCycleSysCC) executeDeploy(stub shim.ChaincodeStubInterface, chai
 		 *}
 		 **/
 
-	_, err = lccc.createChaincode(stub, chainname, cds.ChaincodeSpec.ChaincodeID.Name, code)
+	_, err = lccc.createChaincode(stub, chainname, cds.ChaincodeSpec.ChaincodeID.Name, code, policy, escc, vscc)
 
 	return err
 }
 
-//this implements "upgrade" Invoke transaction
-func (lccc *LifeCycleSysCC) executeUpgrade(stub shim.ChaincodeStubInterface, chainName string, code []byte) ([]byte, error) {
+//this implements "upgrade" Invoke transaction. The chaincode keeps its
+//endorsement policy, escc and vscc unless new ones are given
+func (lccc *LifeCycleSysCC) executeUpgrade(stub shim.ChaincodeStubInterface, chainName string, code []byte, policy []byte, escc string, vscc string) ([]byte, error) {
 	cds, err := lccc.getChaincodeDeploymentSpec(code)
 	if err != nil {
 		return nil, err
@@ -372,6 +436,10 @@ func (lccc *LifeCycleSysCC) executeUpgrade(stub shim.ChaincodeStubInterface, cha
 		return nil, InvalidChaincodeNameErr(chaincodeName)
 	}
 
+	if err = lccc.checkEndorsementPolicy(policy); err != nil {
+		return nil, err
+	}
+
 	// check for existence of chaincode
 	cd, _, err := lccc.getChaincode(stub, chainName, chaincodeName)
 	if cd == nil {
@@ -396,7 +464,26 @@ func (lccc *LifeCycleSysCC) executeUpgrade(stub shim.ChaincodeStubInterface, cha
 
 	// replace the ChaincodeDeploymentSpec using the next version
 	newVersion := fmt.Sprintf("%d", (v + 1))
-	newCD, err := lccc.upgradeChaincode(stub, chainName, chaincodeName, newVersion, code)
+	if len(policy) == 0 {
+		policy = cd.Policy
+	}
+	if escc == "" {
+		escc = cd.Escc
+	}
+	if vscc == "" {
+		vscc = cd.Vscc
+	}
+	//chaincodes deployed before the selection of escc and vscc use the default ones
+	if escc == "" {
+		escc = defaultEscc
+	}
+	if vscc == "" {
+		vscc = defaultVscc
+	}
+	if err = lccc.checkEsccAndVscc(escc, vscc); err != nil {
+		return nil, err
+	}
+	newCD, err := lccc.upgradeChaincode(stub, chainName, chaincodeName, newVersion, code, policy, escc, vscc)
 	if err != nil {
 		return nil, err
 	}
@@ -404,6 +491,21 @@ func (lccc *LifeCycleSysCC) executeUpgrade(stub shim.ChaincodeStubInterface, cha
 	return []byte(newCD.Version), nil
 }
 
+//returns the optional arguments of deploy and upgrade, that is the endorsement
+//policy and the names of the escc and vscc
+func getOptionalDeployArgs(args [][]byte) (policy []byte, escc string, vscc string) {
+	if len(args) > 3 {
+		policy = args[3]
+	}
+	if len(args) > 4 {
+		escc = string(args[4])
+	}
+	if len(args) > 5 {
+		vscc = string(args[5])
+	}
+	return
+}
+
 //-------------- the chaincode stub interface implementation ----------
 
 //Init does nothing
@@ -412,7 +514,8 @@ func (lccc *LifeCycleSysCC) Init(stub shim.ChaincodeStubInterface) ([]byte, erro
 }
 
 // Invoke implements lifecycle functions "deploy", "start", "stop", "upgrade".
-// Deploy's arguments -  {[]byte("deploy"), []byte(<chainname>), <unmarshalled pb.ChaincodeDeploymentSpec>}
+// Deploy's arguments -  {[]byte("deploy"), []byte(<chainname>), <unmarshalled pb.ChaincodeDeploymentSpec>, <unmarshalled common.SignaturePolicyEnvelope, optional>,
+//                        []byte(<escc name>, optional), []byte(<vscc name>, optional)}
 //
 // Invoke also implements some query-like functions
 // Get chaincode arguments -  {[]byte("getid"), []byte(<chainname>), []byte(<chaincodename>)}
@@ -426,7 +529,7 @@ func (lccc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) ([]byte, er
 
 	switch function {
 	case DEPLOY:
-		if len(args) != 3 {
+		if len(args) < 3 || len(args) > 6 {
 			return nil, InvalidArgsLenErr(len(args))
 		}
 
@@ -441,11 +544,14 @@ func (lccc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) ([]byte, er
 		//bytes corresponding to deployment spec
 		code := args[2]
 
-		err := lccc.executeDeploy(stub, chainname, code)
+		//bytes corresponding to the endorsement policy and names of the escc and vscc, if any
+		policy, escc, vscc := getOptionalDeployArgs(args)
+
+		err := lccc.executeDeploy(stub, chainname, code, policy, escc, vscc)
 
 		return nil, err
 	case UPGRADE:
-		if len(args) != 3 {
+		if len(args) < 3 || len(args) > 6 {
 			return nil, InvalidArgsLenErr(len(args))
 		}
 
@@ -455,7 +561,9 @@ func (lccc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) ([]byte, er
 		}
 
 		code := args[2]
-		return lccc.executeUpgrade(stub, chainname, code)
+
+		policy, escc, vscc := getOptionalDeployArgs(args)
+		return lccc.executeUpgrade(stub, chainname, code, policy, escc, vscc)
 	case GETCCINFO, GETDEPSPEC, GETCCDATA:
 		if len(args) != 3 {
 			return nil, InvalidArgsLenErr(len(args))
@@ -20,9 +20,12 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/container"
+	"github.com/hyperledger/fabric/core/system_chaincode/samplesyscc"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
 	"google.golang.org/grpc"
 )
 
@@ -316,3 +319,148 @@ func TestUpgradeNonExistChaincode(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+//TestDeployUpgradeWithPolicy tests deploying and upgrading with an endorsement policy
+func TestDeployUpgradeWithPolicy(t *testing.T) {
+	initialize()
+
+	scc := new(LifeCycleSysCC)
+	stub := shim.NewMockStub("lccc", scc)
+
+	cds, err := constructDeploymentSpec("example02", "github.com/hyperledger/fabric/examples/chaincode/go/chaincode_example02", [][]byte{[]byte("init"), []byte("a"), []byte("100"), []byte("b"), []byte("200")})
+	var b []byte
+	if b, err = proto.Marshal(cds); err != nil || b == nil {
+		t.Fatalf("Marshal DeploymentSpec failed")
+	}
+
+	args := [][]byte{[]byte(DEPLOY), []byte("test"), b, []byte("not a policy")}
+	if _, err = stub.MockInvoke("1", args); err == nil {
+		t.Fatalf("Deploy with an invalid endorsement policy should have failed")
+	} else if _, ok := err.(InvalidPolicyErr); !ok {
+		t.Fatalf("Expected an InvalidPolicyErr, got %v", err)
+	}
+
+	spe, err := cauthdsl.FromString("OutOf(2, 'Org1.member', 'Org2.member', 'Org3.member')")
+	if err != nil {
+		t.Fatalf("Could not parse the endorsement policy: %s", err)
+	}
+	policy, err := proto.Marshal(spe)
+	if err != nil {
+		t.Fatalf("Marshal policy failed")
+	}
+	args = [][]byte{[]byte(DEPLOY), []byte("test"), b, policy}
+	if _, err = stub.MockInvoke("1", args); err != nil {
+		t.Fatalf("Deploy chaincode error: %v", err)
+	}
+
+	checkPolicy := func() {
+		args := [][]byte{[]byte(GETCCDATA), []byte("test"), []byte("example02")}
+		cdbytes, err := stub.MockInvoke("1", args)
+		if err != nil {
+			t.Fatalf("Get chaincode data error: %v", err)
+		}
+		cd := &ChaincodeData{}
+		if err = proto.Unmarshal(cdbytes, cd); err != nil {
+			t.Fatalf("Unmarshal chaincode data failed")
+		}
+		if !proto.Equal(cd, &ChaincodeData{Name: cd.Name, Version: cd.Version, DepSpec: cd.DepSpec, Escc: cd.Escc, Vscc: cd.Vscc, Policy: policy}) {
+			t.Fatalf("Expected the endorsement policy to be stored in the chaincode data")
+		}
+	}
+	checkPolicy()
+
+	// upgrading without a policy retains the endorsement policy of the chaincode
+	args = [][]byte{[]byte(UPGRADE), []byte("test"), b}
+	if _, err = stub.MockInvoke("1", args); err != nil {
+		t.Fatalf("Upgrade chaincode error: %v", err)
+	}
+	checkPolicy()
+}
+
+//TestDeployUpgradeWithEsccAndVscc tests deploying and upgrading with custom escc and vscc
+func TestDeployUpgradeWithEsccAndVscc(t *testing.T) {
+	initialize()
+
+	//register a custom validation system chaincode besides the default ones
+	origSystemCC, origWhitelist := systemChaincodes, viper.GetStringMapString("chaincode.system")
+	defer func() {
+		systemChaincodes = origSystemCC
+		viper.Set("chaincode.system", origWhitelist)
+	}()
+	systemChaincodes = append(systemChaincodes[:len(systemChaincodes):len(systemChaincodes)], &SystemChaincode{
+		Enabled:   true,
+		Plugin:    ValidationPlugin,
+		Name:      "customvscc",
+		Path:      "github.com/hyperledger/fabric/core/system_chaincode/samplesyscc",
+		InitArgs:  [][]byte{},
+		Chaincode: &samplesyscc.SampleSysCC{},
+	})
+	viper.Set("chaincode.system", map[string]string{"lccc": "enable", "escc": "enable", "vscc": "enable", "cscc": "enable", "customvscc": "enable"})
+
+	scc := new(LifeCycleSysCC)
+	stub := shim.NewMockStub("lccc", scc)
+
+	cds, err := constructDeploymentSpec("example02", "github.com/hyperledger/fabric/examples/chaincode/go/chaincode_example02", [][]byte{[]byte("init"), []byte("a"), []byte("100"), []byte("b"), []byte("200")})
+	var b []byte
+	if b, err = proto.Marshal(cds); err != nil || b == nil {
+		t.Fatalf("Marshal DeploymentSpec failed")
+	}
+
+	//unknown, chainless, not whitelisted and non validation system chaincodes are rejected
+	for _, syscc := range []string{"unknownscc", "cscc", "qscc", "lccc", "escc"} {
+		args := [][]byte{[]byte(DEPLOY), []byte("test"), b, nil, []byte("escc"), []byte(syscc)}
+		if _, err = stub.MockInvoke("1", args); err == nil {
+			t.Fatalf("Deploy with vscc %s should have failed", syscc)
+		} else if _, ok := err.(InvalidSysCCErr); !ok {
+			t.Fatalf("Expected an InvalidSysCCErr for vscc %s, got %v", syscc, err)
+		}
+	}
+
+	//only endorsement system chaincodes are accepted as escc
+	for _, syscc := range []string{"lccc", "vscc", "customvscc"} {
+		args := [][]byte{[]byte(DEPLOY), []byte("test"), b, nil, []byte(syscc), []byte("vscc")}
+		if _, err = stub.MockInvoke("1", args); err == nil {
+			t.Fatalf("Deploy with escc %s should have failed", syscc)
+		} else if _, ok := err.(InvalidSysCCErr); !ok {
+			t.Fatalf("Expected an InvalidSysCCErr for escc %s, got %v", syscc, err)
+		}
+	}
+
+	getChaincodeData := func() *ChaincodeData {
+		args := [][]byte{[]byte(GETCCDATA), []byte("test"), []byte("example02")}
+		cdbytes, err := stub.MockInvoke("1", args)
+		if err != nil {
+			t.Fatalf("Get chaincode data error: %v", err)
+		}
+		cd := &ChaincodeData{}
+		if err = proto.Unmarshal(cdbytes, cd); err != nil {
+			t.Fatalf("Unmarshal chaincode data failed")
+		}
+		return cd
+	}
+
+	args := [][]byte{[]byte(DEPLOY), []byte("test"), b, nil, nil, []byte("customvscc")}
+	if _, err = stub.MockInvoke("1", args); err != nil {
+		t.Fatalf("Deploy chaincode error: %v", err)
+	}
+	if cd := getChaincodeData(); cd.Escc != "escc" || cd.Vscc != "customvscc" {
+		t.Fatalf("Expected the default escc and the custom vscc, got %s and %s", cd.Escc, cd.Vscc)
+	}
+
+	//upgrading without selecting system chaincodes retains those of the chaincode
+	args = [][]byte{[]byte(UPGRADE), []byte("test"), b}
+	if _, err = stub.MockInvoke("1", args); err != nil {
+		t.Fatalf("Upgrade chaincode error: %v", err)
+	}
+	if cd := getChaincodeData(); cd.Escc != "escc" || cd.Vscc != "customvscc" {
+		t.Fatalf("Expected the escc and vscc to be retained, got %s and %s", cd.Escc, cd.Vscc)
+	}
+
+	args = [][]byte{[]byte(UPGRADE), []byte("test"), b, nil, nil, []byte("vscc")}
+	if _, err = stub.MockInvoke("1", args); err != nil {
+		t.Fatalf("Upgrade chaincode error: %v", err)
+	}
+	if cd := getChaincodeData(); cd.Vscc != "vscc" {
+		t.Fatalf("Expected the default vscc after the upgrade, got %s", cd.Vscc)
+	}
+}
@@ -34,8 +34,9 @@ import (
 // - GetChainInfo returns BlockchainInfo
 // - GetBlockByNumber returns a block
 // - GetBlockByHash returns a block
-// - GetTransactionByID returns a transaction
+// - GetTransactionByID returns a transaction along with its validation code
 // - GetQueryResult returns result of a freeform query
+// - GetStateHash returns the state hash as of a block
 type LedgerQuerier struct {
 }
 
@@ -48,6 +49,7 @@ const (
 	GetBlockByHash     string = "GetBlockByHash"
 	GetTransactionByID string = "GetTransactionByID"
 	GetQueryResult     string = "GetQueryResult"
+	GetStateHash       string = "GetStateHash"
 )
 
 // Init is called once per chain when the chain is created.
@@ -65,12 +67,15 @@ func (e *LedgerQuerier) Init(stub shim.ChaincodeStubInterface) ([]byte, error) {
 // # GetChainInfo: Return a BlockchainInfo object marshalled in bytes
 // # GetBlockByNumber: Return the block specified by block number in args[2]
 // # GetBlockByHash: Return the block specified by block hash in args[2]
-// # GetTransactionByID: Return the transaction specified by ID in args[2]
+// # GetTransactionByID: Return the ProcessedTransaction, i.e. the transaction along
+// with its validation code, specified by ID in args[2]
 // # GetQueryResult: Return the result of executing the specified native
 // query string in args[2]. Note that this only works if plugged in database
 // supports it. The result is a JSON array in a byte array. Note that error
 // may be returned together with a valid partial result as error might occur
 // during accummulating records from the ledger
+// # GetStateHash: Return the state hash as of the block specified by block number
+// in args[2], which peers compare to check that they have the same state
 func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) ([]byte, error) {
 	args := stub.GetArgs()
 
@@ -105,6 +110,8 @@ func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) ([]byte, error)
 		return getBlockByHash(targetLedger, args[2])
 	case GetChainInfo:
 		return getChainInfo(targetLedger)
+	case GetStateHash:
+		return getStateHash(targetLedger, args[2])
 	}
 
 	return nil, fmt.Errorf("Requested function %s not found.", fname)
@@ -136,7 +143,7 @@ func getQueryResult(vledger ledger.PeerLedger, query []byte) (ret []byte, err er
 	if qexe, err = vledger.NewQueryExecutor(); err != nil {
 		return nil, err
 	}
-	if ri, err = qexe.ExecuteQuery(qstring); err != nil {
+	if ri, err = qexe.ExecuteQuery("", qstring); err != nil {
 		return nil, err
 	}
 	defer ri.Close()
@@ -190,13 +197,12 @@ func getTransactionByID(vledger ledger.PeerLedger, tid []byte) ([]byte, error) {
 	if tid == nil {
 		return nil, fmt.Errorf("Transaction ID must not be nil.")
 	}
-	tx, err := vledger.GetTransactionByID(string(tid))
+	processedTx, err := vledger.GetTransactionByID(string(tid))
 	if err != nil {
 		return nil, fmt.Errorf("Failed to get transaction with id %s, error %s", string(tid), err)
 	}
-	// TODO: tx is *pb.Transaction, what should we return?
 
-	return utils.Marshal(tx)
+	return utils.Marshal(processedTx)
 }
 
 func getBlockByNumber(vledger ledger.PeerLedger, number []byte) ([]byte, error) {
@@ -236,3 +242,21 @@ func getChainInfo(vledger ledger.PeerLedger) ([]byte, error) {
 	}
 	return utils.Marshal(binfo)
 }
+
+func getStateHash(vledger ledger.PeerLedger, number []byte) ([]byte, error) {
+	if number == nil {
+		return nil, fmt.Errorf("Block number must not be nil.")
+	}
+	bnum, err := strconv.ParseUint(string(number), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse block number with error %s", err)
+	}
+	stateHash, err := vledger.GetStateHash(bnum)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get state hash of block number %d, error %s", bnum, err)
+	}
+	if stateHash == nil {
+		return nil, fmt.Errorf("State hash of block number %d is not available", bnum)
+	}
+	return stateHash, nil
+}
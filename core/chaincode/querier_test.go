@@ -132,3 +132,23 @@ func TestQueryGetQueryResult(t *testing.T) {
 		t.Fatalf("qscc GetQueryResult should have failed with invalid query: abc")
 	}
 }
+
+func TestQueryGetStateHash(t *testing.T) {
+	viper.Set("peer.fileSystemPath", "/var/hyperledger/test8/")
+	defer os.RemoveAll("/var/hyperledger/test8/")
+	peer.MockInitialize()
+	peer.MockCreateChain("mytestchainid8")
+
+	e := new(LedgerQuerier)
+	stub := shim.NewMockStub("LedgerQuerier", e)
+
+	args := [][]byte{[]byte(GetStateHash), []byte("mytestchainid8"), []byte("abc")}
+	if _, err := stub.MockInvoke("1", args); err == nil {
+		t.Fatalf("qscc GetStateHash should have failed with invalid number: abc")
+	}
+
+	args = [][]byte{[]byte(GetStateHash), []byte("mytestchainid8"), []byte("1")}
+	if _, err := stub.MockInvoke("1", args); err == nil {
+		t.Fatalf("qscc GetStateHash should have failed with uncommitted block: 1")
+	}
+}
@@ -330,13 +330,84 @@ type StateRangeQueryIterator struct {
 // between the startKey and endKey, inclusive. The order in which keys are
 // returned by the iterator is random.
 func (stub *ChaincodeStub) RangeQueryState(startKey, endKey string) (StateRangeQueryIteratorInterface, error) {
-	response, err := stub.handler.handleRangeQueryState(startKey, endKey, stub.TxID)
+	response, err := stub.handler.handleRangeQueryState(startKey, endKey, 0, "", stub.TxID)
 	if err != nil {
 		return nil, err
 	}
 	return &StateRangeQueryIterator{stub.handler, stub.TxID, response, 0}, nil
 }
 
+// RangeQueryStateWithPagination function can be invoked by a chaincode to query
+// a page of at most pageSize keys of a range of keys in the state. An iterator
+// over the keys and values of the page is returned along with the bookmark to be
+// passed for getting the next page. The bookmark is empty for the first page and
+// is returned empty when there are no more keys in the range. The page size may
+// not exceed the maximum page size configured on the peer.
+func (stub *ChaincodeStub) RangeQueryStateWithPagination(startKey, endKey string, pageSize int32, bookmark string) (StateRangeQueryIteratorInterface, string, error) {
+	if pageSize <= 0 {
+		return nil, "", fmt.Errorf("Invalid page size %d", pageSize)
+	}
+	response, err := stub.handler.handleRangeQueryState(startKey, endKey, pageSize, bookmark, stub.TxID)
+	if err != nil {
+		return nil, "", err
+	}
+	return &StateRangeQueryIterator{stub.handler, stub.TxID, response, 0}, response.Bookmark, nil
+}
+
+// GetQueryResult function can be invoked by a chaincode to perform a
+// rich query against the state database. The query string is in the syntax
+// of the underlying state database and only the documents of the chaincode
+// are queried. An iterator is returned which can be used to iterate over the
+// keys and the values of the documents satisfying the query. Rich queries are
+// only supported by state databases that support them, e.g. CouchDB.
+func (stub *ChaincodeStub) GetQueryResult(query string) (StateRangeQueryIteratorInterface, error) {
+	response, err := stub.handler.handleGetQueryResult(query, 0, "", stub.TxID)
+	if err != nil {
+		return nil, err
+	}
+	return &StateRangeQueryIterator{stub.handler, stub.TxID, response, 0}, nil
+}
+
+// GetQueryResultWithPagination function can be invoked by a chaincode to get a
+// page of at most pageSize results of a rich query. An iterator over the results
+// of the page is returned along with the bookmark to be passed for getting the
+// next page. The bookmark is empty for the first page and is returned empty when
+// there are no more results. The results are returned in the order of their keys,
+// so the query may not specify a sort nor a skip. The page size may not exceed the
+// maximum page size configured on the peer.
+func (stub *ChaincodeStub) GetQueryResultWithPagination(query string, pageSize int32, bookmark string) (StateRangeQueryIteratorInterface, string, error) {
+	if pageSize <= 0 {
+		return nil, "", fmt.Errorf("Invalid page size %d", pageSize)
+	}
+	response, err := stub.handler.handleGetQueryResult(query, pageSize, bookmark, stub.TxID)
+	if err != nil {
+		return nil, "", err
+	}
+	return &StateRangeQueryIterator{stub.handler, stub.TxID, response, 0}, response.Bookmark, nil
+}
+
+// HistoryQueryIterator allows a chaincode to iterate over the modifications
+// of a key, as returned by GetHistoryForKey.
+type HistoryQueryIterator struct {
+	handler    *Handler
+	uuid       string
+	response   *pb.GetHistoryForKeyResponse
+	currentLoc int
+}
+
+// GetHistoryForKey function can be invoked by a chaincode to get the history
+// of the modifications of a key of the chaincode. An iterator is returned which
+// can be used to iterate over the transaction ids and the values written by the
+// transactions that modified the key, in the order in which they were committed.
+// The history database of the peer needs to be enabled.
+func (stub *ChaincodeStub) GetHistoryForKey(key string) (HistoryQueryIteratorInterface, error) {
+	response, err := stub.handler.handleGetHistoryForKey(key, stub.TxID)
+	if err != nil {
+		return nil, err
+	}
+	return &HistoryQueryIterator{stub.handler, stub.TxID, response, 0}, nil
+}
+
 //Given a list of attributes, createCompositeKey function combines these attributes
 //to form a composite key.
 func (stub *ChaincodeStub) CreateCompositeKey(objectType string, attributes []string) (string, error) {
@@ -398,6 +469,9 @@ func (iter *StateRangeQueryIterator) Next() (string, []byte, error) {
 
 		iter.currentLoc = 0
 		iter.response = response
+		if len(iter.response.KeysAndValues) == 0 {
+			return "", nil, errors.New("No such key")
+		}
 		keyValue := iter.response.KeysAndValues[iter.currentLoc]
 		iter.currentLoc++
 		return keyValue.Key, keyValue.Value, nil
@@ -412,6 +486,48 @@ func (iter *StateRangeQueryIterator) Close() error {
 	return err
 }
 
+// HasNext returns true if the history query iterator contains additional
+// modifications.
+func (iter *HistoryQueryIterator) HasNext() bool {
+	if iter.currentLoc < len(iter.response.KeyModifications) || iter.response.HasMore {
+		return true
+	}
+	return false
+}
+
+// Next returns the transaction id and the value of the next modification in
+// the history query iterator.
+func (iter *HistoryQueryIterator) Next() (string, []byte, error) {
+	if iter.currentLoc < len(iter.response.KeyModifications) {
+		keyModification := iter.response.KeyModifications[iter.currentLoc]
+		iter.currentLoc++
+		return keyModification.TxID, keyModification.Value, nil
+	} else if !iter.response.HasMore {
+		return "", nil, errors.New("No such key modification")
+	}
+
+	response, err := iter.handler.handleGetHistoryForKeyNext(iter.response.ID, iter.uuid)
+	if err != nil {
+		return "", nil, err
+	}
+
+	iter.currentLoc = 0
+	iter.response = response
+	if len(iter.response.KeyModifications) == 0 {
+		return "", nil, errors.New("No such key modification")
+	}
+	keyModification := iter.response.KeyModifications[iter.currentLoc]
+	iter.currentLoc++
+	return keyModification.TxID, keyModification.Value, nil
+}
+
+// Close closes the history query iterator. This should be called when done
+// reading from the iterator to free up resources.
+func (iter *HistoryQueryIterator) Close() error {
+	_, err := iter.handler.handleRangeQueryStateClose(iter.response.ID, iter.uuid)
+	return err
+}
+
 func (stub *ChaincodeStub) GetArgs() [][]byte {
 	return stub.args
 }
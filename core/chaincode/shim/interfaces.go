@@ -69,6 +69,12 @@ type ChaincodeStubInterface interface {
 	// returned by the iterator is random.
 	RangeQueryState(startKey, endKey string) (StateRangeQueryIteratorInterface, error)
 
+	// RangeQueryStateWithPagination function can be invoked by a chaincode to
+	// query a page of at most pageSize keys of a range of keys in the state.
+	// The bookmark to be passed for the next page is returned with the
+	// iterator, and is empty when there are no more keys in the range.
+	RangeQueryStateWithPagination(startKey, endKey string, pageSize int32, bookmark string) (StateRangeQueryIteratorInterface, string, error)
+
 	//PartialCompositeKeyQuery function can be invoked by a chaincode to query the
 	//state based on a given partial composite key. This function returns an
 	//iterator which can be used to iterate over all composite keys whose prefix
@@ -77,6 +83,26 @@ type ChaincodeStubInterface interface {
 	//would be returned.
 	PartialCompositeKeyQuery(objectType string, keys []string) (StateRangeQueryIteratorInterface, error)
 
+	// GetQueryResult function can be invoked by a chaincode to perform a
+	// rich query against the state database. Only the documents of the
+	// chaincode are queried. An iterator is returned which can be used to
+	// iterate over the keys and the values of the documents satisfying the
+	// query. Only supported by state databases that support rich queries.
+	GetQueryResult(query string) (StateRangeQueryIteratorInterface, error)
+
+	// GetQueryResultWithPagination function can be invoked by a chaincode to
+	// get a page of at most pageSize results of a rich query. The bookmark to
+	// be passed for the next page is returned with the iterator, and is empty
+	// when there are no more results. The results are returned in the order
+	// of their keys, so the query may not specify a sort nor a skip.
+	GetQueryResultWithPagination(query string, pageSize int32, bookmark string) (StateRangeQueryIteratorInterface, string, error)
+
+	// GetHistoryForKey function can be invoked by a chaincode to get the
+	// history of the modifications of a key. An iterator is returned which
+	// can be used to iterate over the transaction ids and the values written
+	// by the transactions that modified the key.
+	GetHistoryForKey(key string) (HistoryQueryIteratorInterface, error)
+
 	//Given a list of attributes, createCompundKey function combines these attributes
 	//to form a composite key.
 	CreateCompositeKey(objectType string, attributes []string) (string, error)
@@ -118,3 +144,20 @@ type StateRangeQueryIteratorInterface interface {
 	// reading from the iterator to free up resources.
 	Close() error
 }
+
+// HistoryQueryIteratorInterface allows a chaincode to iterate over the
+// modifications of a key.
+type HistoryQueryIteratorInterface interface {
+
+	// HasNext returns true if the history query iterator contains additional
+	// modifications.
+	HasNext() bool
+
+	// Next returns the transaction id and the value of the next modification
+	// in the history query iterator.
+	Next() (string, []byte, error)
+
+	// Close closes the history query iterator. This should be called when done
+	// reading from the iterator to free up resources.
+	Close() error
+}
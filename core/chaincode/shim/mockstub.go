@@ -20,7 +20,10 @@ package shim
 
 import (
 	"container/list"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/golang/protobuf/ptypes/timestamp"
@@ -52,6 +55,10 @@ type MockStub struct {
 	// registered list of other MockStub chaincodes that can be called from this MockStub
 	Invokables map[string]*MockStub
 
+	// History keeps the transaction ids and the values of the modifications of each key,
+	// in the order in which they were made. A deletion is recorded with a nil value
+	History map[string][]MockKeyModification
+
 	// stores a transaction uuid while being Invoked / Deployed
 	// TODO if a chaincode uses recursion this may need to be a stack of TxIDs or possibly a reference counting map
 	TxID string
@@ -138,6 +145,7 @@ func (stub *MockStub) PutState(key string, value []byte) error {
 
 	mockLogger.Debug("MockStub", stub.Name, "Putting", key, value)
 	stub.State[key] = value
+	stub.History[key] = append(stub.History[key], MockKeyModification{TxID: stub.TxID, Value: value})
 
 	// insert key into ordered list of keys
 	for elem := stub.Keys.Front(); elem != nil; elem = elem.Next() {
@@ -176,6 +184,7 @@ func (stub *MockStub) PutState(key string, value []byte) error {
 func (stub *MockStub) DelState(key string) error {
 	mockLogger.Debug("MockStub", stub.Name, "Deleting", key, stub.State[key])
 	delete(stub.State, key)
+	stub.History[key] = append(stub.History[key], MockKeyModification{TxID: stub.TxID})
 
 	for elem := stub.Keys.Front(); elem != nil; elem = elem.Next() {
 		if strings.Compare(key, elem.Value.(string)) == 0 {
@@ -190,6 +199,93 @@ func (stub *MockStub) RangeQueryState(startKey, endKey string) (StateRangeQueryI
 	return NewMockStateRangeQueryIterator(stub, startKey, endKey), nil
 }
 
+// RangeQueryStateWithPagination returns a page of the keys of the range. The
+// bookmark is the first key of the next page.
+func (stub *MockStub) RangeQueryStateWithPagination(startKey, endKey string, pageSize int32, bookmark string) (StateRangeQueryIteratorInterface, string, error) {
+	if pageSize <= 0 {
+		return nil, "", fmt.Errorf("Invalid page size %d", pageSize)
+	}
+	if bookmark != "" {
+		startKey = bookmark
+	}
+	rangeIter := NewMockStateRangeQueryIterator(stub, startKey, endKey)
+	defer rangeIter.Close()
+
+	iter := &mockResultsIterator{}
+	for rangeIter.HasNext() {
+		key, value, err := rangeIter.Next()
+		if err != nil {
+			return nil, "", err
+		}
+		if len(iter.keys) == int(pageSize) {
+			return iter, key, nil
+		}
+		iter.keys = append(iter.keys, key)
+		iter.values = append(iter.values, value)
+	}
+	return iter, "", nil
+}
+
+// GetQueryResult supports only a subset of the CouchDB query syntax: a selector
+// matching the top level fields of the JSON values for equality, e.g.
+// {"selector":{"owner":"tom","size":{"$eq":35}}}. The matching keys are returned
+// in lexical order. The values that are not JSON objects never match.
+func (stub *MockStub) GetQueryResult(query string) (StateRangeQueryIteratorInterface, error) {
+	selector, err := parseMockSelector(query)
+	if err != nil {
+		mockLogger.Error("MockStub", stub.Name, "Invalid query", query, err)
+		return nil, err
+	}
+
+	iter := &mockResultsIterator{}
+	for elem := stub.Keys.Front(); elem != nil; elem = elem.Next() {
+		key := elem.Value.(string)
+		value := stub.State[key]
+		if matchesMockSelector(selector, value) {
+			iter.keys = append(iter.keys, key)
+			iter.values = append(iter.values, value)
+		}
+	}
+	return iter, nil
+}
+
+// GetQueryResultWithPagination returns a page of the results of GetQueryResult.
+// The bookmark is the key of the first result of the next page.
+func (stub *MockStub) GetQueryResultWithPagination(query string, pageSize int32, bookmark string) (StateRangeQueryIteratorInterface, string, error) {
+	if pageSize <= 0 {
+		return nil, "", fmt.Errorf("Invalid page size %d", pageSize)
+	}
+	results, err := stub.GetQueryResult(query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	all := results.(*mockResultsIterator)
+	iter := &mockResultsIterator{}
+	for i, key := range all.keys {
+		if key < bookmark {
+			continue
+		}
+		if len(iter.keys) == int(pageSize) {
+			return iter, key, nil
+		}
+		iter.keys = append(iter.keys, key)
+		iter.values = append(iter.values, all.values[i])
+	}
+	return iter, "", nil
+}
+
+// GetHistoryForKey returns an iterator over the transaction ids and the values
+// of the modifications of the key recorded by PutState and DelState.
+func (stub *MockStub) GetHistoryForKey(key string) (HistoryQueryIteratorInterface, error) {
+	iter := &mockResultsIterator{}
+	for _, km := range stub.History[key] {
+		iter.keys = append(iter.keys, km.TxID)
+		iter.values = append(iter.values, km.Value)
+	}
+	return iter, nil
+}
+
 //PartialCompositeKeyQuery function can be invoked by a chaincode to query the
 //state based on a given partial composite key. This function returns an
 //iterator which can be used to iterate over all composite keys whose prefix
@@ -258,6 +354,7 @@ func NewMockStub(name string, cc Chaincode) *MockStub {
 	s.cc = cc
 	s.State = make(map[string][]byte)
 	s.Invokables = make(map[string]*MockStub)
+	s.History = make(map[string][]MockKeyModification)
 	s.Keys = list.New()
 
 	return s
@@ -375,6 +472,90 @@ func NewMockStateRangeQueryIterator(stub *MockStub, startKey string, endKey stri
 	return iter
 }
 
+/*****************************
+ Query Result and History Iterator
+*****************************/
+
+// MockKeyModification is a modification of a key recorded by MockStub
+type MockKeyModification struct {
+	TxID  string
+	Value []byte
+}
+
+// mockResultsIterator iterates over the results of GetQueryResult, i.e. keys and
+// values, or of GetHistoryForKey, i.e. transaction ids and values
+type mockResultsIterator struct {
+	closed bool
+	keys   []string
+	values [][]byte
+}
+
+func (iter *mockResultsIterator) HasNext() bool {
+	return !iter.closed && len(iter.keys) > 0
+}
+
+func (iter *mockResultsIterator) Next() (string, []byte, error) {
+	if iter.closed {
+		return "", nil, errors.New("Next() called after Close()")
+	}
+	if len(iter.keys) == 0 {
+		return "", nil, errors.New("Next() called when it does not HaveNext()")
+	}
+	key, value := iter.keys[0], iter.values[0]
+	iter.keys, iter.values = iter.keys[1:], iter.values[1:]
+	return key, value, nil
+}
+
+func (iter *mockResultsIterator) Close() error {
+	if iter.closed {
+		return errors.New("Close() called after Close()")
+	}
+	iter.closed = true
+	return nil
+}
+
+// parseMockSelector returns the expected value of each field of the selector of the query
+func parseMockSelector(query string) (map[string]interface{}, error) {
+	var jsonQuery struct {
+		Selector map[string]interface{} `json:"selector"`
+	}
+	if err := json.Unmarshal([]byte(query), &jsonQuery); err != nil {
+		return nil, err
+	}
+	if jsonQuery.Selector == nil {
+		return nil, errors.New("The query has no selector")
+	}
+	selector := make(map[string]interface{})
+	for field, condition := range jsonQuery.Selector {
+		if strings.HasPrefix(field, "$") {
+			return nil, fmt.Errorf("Operator %s not supported by MockStub", field)
+		}
+		if operators, ok := condition.(map[string]interface{}); ok {
+			expected, ok := operators["$eq"]
+			if !ok || len(operators) != 1 {
+				return nil, fmt.Errorf("Only $eq is supported by MockStub for field %s", field)
+			}
+			condition = expected
+		}
+		selector[field] = condition
+	}
+	return selector, nil
+}
+
+func matchesMockSelector(selector map[string]interface{}, value []byte) bool {
+	doc := make(map[string]interface{})
+	if err := json.Unmarshal(value, &doc); err != nil {
+		return false
+	}
+	for field, expected := range selector {
+		actual, ok := doc[field]
+		if !ok || !reflect.DeepEqual(actual, expected) {
+			return false
+		}
+	}
+	return true
+}
+
 func getBytes(function string, args []string) [][]byte {
 	bytes := make([][]byte, 0, len(args)+1)
 	bytes = append(bytes, []byte(function))
@@ -130,3 +130,187 @@ func TestPartialCompositeKeyQuery(t *testing.T) {
 		}
 	}
 }
+
+func TestGetQueryResult(t *testing.T) {
+	stub := NewMockStub("GetQueryResultTest", nil)
+	stub.MockTransactionStart("init")
+
+	marble1 := &Marble{"marble", "marble1", "red", 5, "tom"}
+	marbleJSONBytes1, _ := json.Marshal(marble1)
+	stub.PutState("marble1", marbleJSONBytes1)
+
+	marble2 := &Marble{"marble", "marble2", "blue", 5, "jerry"}
+	marbleJSONBytes2, _ := json.Marshal(marble2)
+	stub.PutState("marble2", marbleJSONBytes2)
+
+	marble3 := &Marble{"marble", "marble3", "red", 7, "tom"}
+	marbleJSONBytes3, _ := json.Marshal(marble3)
+	stub.PutState("marble3", marbleJSONBytes3)
+
+	stub.PutState("notjson", []byte("tom"))
+	stub.MockTransactionEnd("init")
+
+	expectKeys := []string{"marble1", "marble3"}
+	expectValues := [][]byte{marbleJSONBytes1, marbleJSONBytes3}
+
+	iter, err := stub.GetQueryResult(`{"selector":{"owner":"tom","docType":{"$eq":"marble"}}}`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for i := range expectKeys {
+		if !iter.HasNext() {
+			t.Fatalf("Expected %d results, got %d", len(expectKeys), i)
+		}
+		key, value, err := iter.Next()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if key != expectKeys[i] || !jsonBytesEqual(expectValues[i], value) {
+			t.Fatalf("Expected %s=%s, got %s=%s", expectKeys[i], expectValues[i], key, value)
+		}
+	}
+	if iter.HasNext() {
+		t.Fatal("Expected no more results")
+	}
+	iter.Close()
+
+	iter, _ = stub.GetQueryResult(`{"selector":{"size":7}}`)
+	if key, _, _ := iter.Next(); key != "marble3" || iter.HasNext() {
+		t.Fatalf("Expected marble3 only, got %s", key)
+	}
+
+	if _, err = stub.GetQueryResult(`{"selector":{"size":{"$gt":5}}}`); err == nil {
+		t.Fatal("Expected an error for an unsupported operator")
+	}
+	if _, err = stub.GetQueryResult("this is an invalid query string"); err == nil {
+		t.Fatal("Expected an error for an invalid query string")
+	}
+}
+
+func TestGetHistoryForKey(t *testing.T) {
+	stub := NewMockStub("GetHistoryForKeyTest", nil)
+	stub.MockTransactionStart("tx1")
+	stub.PutState("key1", []byte("value1"))
+	stub.MockTransactionEnd("tx1")
+	stub.MockTransactionStart("tx2")
+	stub.PutState("key1", []byte("value2"))
+	stub.PutState("key2", []byte("value3"))
+	stub.MockTransactionEnd("tx2")
+	stub.MockTransactionStart("tx3")
+	stub.DelState("key1")
+	stub.MockTransactionEnd("tx3")
+
+	expectTxIDs := []string{"tx1", "tx2", "tx3"}
+	expectValues := [][]byte{[]byte("value1"), []byte("value2"), nil}
+
+	iter, err := stub.GetHistoryForKey("key1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for i := range expectTxIDs {
+		txID, value, err := iter.Next()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if txID != expectTxIDs[i] || !reflect.DeepEqual(value, expectValues[i]) {
+			t.Fatalf("Expected %s=%s, got %s=%s", expectTxIDs[i], expectValues[i], txID, value)
+		}
+	}
+	if iter.HasNext() {
+		t.Fatal("Expected no more modifications")
+	}
+	if err = iter.Close(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if _, _, err = iter.Next(); err == nil {
+		t.Fatal("Expected an error for Next() after Close()")
+	}
+
+	iter, _ = stub.GetHistoryForKey("key3")
+	if iter.HasNext() {
+		t.Fatal("Expected no modifications for key3")
+	}
+}
+
+func collectKeys(t *testing.T, iter StateRangeQueryIteratorInterface) []string {
+	var keys []string
+	for iter.HasNext() {
+		key, _, err := iter.Next()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		keys = append(keys, key)
+	}
+	iter.Close()
+	return keys
+}
+
+func TestRangeQueryStateWithPagination(t *testing.T) {
+	stub := NewMockStub("RangeQueryStateWithPaginationTest", nil)
+	stub.MockTransactionStart("init")
+	for _, key := range []string{"key1", "key2", "key3", "key4", "key5", "other"} {
+		stub.PutState(key, []byte(key))
+	}
+	stub.MockTransactionEnd("init")
+
+	iter, bookmark, err := stub.RangeQueryStateWithPagination("key1", "key5", 2, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if keys := collectKeys(t, iter); !reflect.DeepEqual(keys, []string{"key1", "key2"}) || bookmark != "key3" {
+		t.Fatalf("Unexpected first page %v, bookmark %s", keys, bookmark)
+	}
+
+	iter, bookmark, _ = stub.RangeQueryStateWithPagination("key1", "key5", 2, bookmark)
+	if keys := collectKeys(t, iter); !reflect.DeepEqual(keys, []string{"key3", "key4"}) || bookmark != "key5" {
+		t.Fatalf("Unexpected second page %v, bookmark %s", keys, bookmark)
+	}
+
+	iter, bookmark, _ = stub.RangeQueryStateWithPagination("key1", "key5", 2, bookmark)
+	if keys := collectKeys(t, iter); !reflect.DeepEqual(keys, []string{"key5"}) || bookmark != "" {
+		t.Fatalf("Unexpected last page %v, bookmark %s", keys, bookmark)
+	}
+
+	if _, _, err = stub.RangeQueryStateWithPagination("key1", "key5", 0, ""); err == nil {
+		t.Fatal("Expected an error for an invalid page size")
+	}
+}
+
+func TestGetQueryResultWithPagination(t *testing.T) {
+	stub := NewMockStub("GetQueryResultWithPaginationTest", nil)
+	stub.MockTransactionStart("init")
+	for i := 1; i <= 5; i++ {
+		marble := &Marble{"marble", fmt.Sprintf("marble%d", i), "red", i, "tom"}
+		marbleJSONBytes, _ := json.Marshal(marble)
+		stub.PutState(marble.Name, marbleJSONBytes)
+	}
+	stub.MockTransactionEnd("init")
+
+	query := `{"selector":{"owner":"tom"}}`
+	var pages [][]string
+	bookmark := ""
+	for {
+		iter, next, err := stub.GetQueryResultWithPagination(query, 2, bookmark)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		pages = append(pages, collectKeys(t, iter))
+		if next == "" {
+			break
+		}
+		bookmark = next
+	}
+	expectPages := [][]string{{"marble1", "marble2"}, {"marble3", "marble4"}, {"marble5"}}
+	if !reflect.DeepEqual(pages, expectPages) {
+		t.Fatalf("Expected pages %v, got %v", expectPages, pages)
+	}
+
+	// the bookmark is a key, which does not need to be the key of a result
+	iter, next, err := stub.GetQueryResultWithPagination(query, 2, "marble35")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if keys := collectKeys(t, iter); !reflect.DeepEqual(keys, []string{"marble4", "marble5"}) || next != "" {
+		t.Fatalf("Expected the last page [marble4 marble5], got %v with bookmark %s", keys, next)
+	}
+}
@@ -43,6 +43,10 @@ type SystemChaincode struct {
 	//save state in the ledger. CSCC is an example
 	ChainlessCC bool
 
+	//Plugin is the role for which chaincodes may select the system chaincode
+	//at deploy time, if any. ESCC and VSCC are examples
+	Plugin SysCCPlugin
+
 	// Enabled a convenient switch to enable/disable system chaincode without
 	// having to remove entry from importsysccs.go
 	Enabled bool
@@ -60,6 +64,19 @@ type SystemChaincode struct {
 	Chaincode shim.Chaincode
 }
 
+// SysCCPlugin is the role of a system chaincode that chaincodes select to
+// endorse or to validate their transactions
+type SysCCPlugin int
+
+const (
+	// NotAPlugin system chaincodes cannot be selected by chaincodes
+	NotAPlugin SysCCPlugin = iota
+	// EndorsementPlugin system chaincodes sign the proposal responses of chaincodes
+	EndorsementPlugin
+	// ValidationPlugin system chaincodes validate the transactions of chaincodes
+	ValidationPlugin
+)
+
 // RegisterSysCC registers the given system chaincode with the peer
 func RegisterSysCC(syscc *SystemChaincode) error {
 	if !syscc.Enabled || !isWhitelisted(syscc) {
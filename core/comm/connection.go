@@ -17,6 +17,10 @@ limitations under the License.
 package comm
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
 	"os"
 	"time"
 
@@ -82,3 +86,54 @@ func InitTLSForPeer() credentials.TransportCredentials {
 	}
 	return creds
 }
+
+// OrdererTLSEnabled returns whether connections to the orderer are secured with TLS
+func OrdererTLSEnabled() bool {
+	return viper.GetBool("peer.committer.ledger.tls.enabled")
+}
+
+// InitTLSForOrderer returns TLS credentials for connecting to the orderer. The
+// orderer certificate is verified against the configured root certificate, and
+// the configured client certificate is presented when mutual TLS is required
+func InitTLSForOrderer() (credentials.TransportCredentials, error) {
+	tlsConfig := &tls.Config{
+		ServerName: viper.GetString("peer.committer.ledger.tls.serverhostoverride"),
+	}
+
+	if rootCertFile := viper.GetString("peer.committer.ledger.tls.rootcert.file"); rootCertFile != "" {
+		rootCert, err := ioutil.ReadFile(rootCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read orderer root certificate %s: %s", rootCertFile, err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(rootCert) {
+			return nil, fmt.Errorf("Failed to append orderer root certificate %s", rootCertFile)
+		}
+	}
+
+	clientCertFile := viper.GetString("peer.committer.ledger.tls.clientcert.file")
+	clientKeyFile := viper.GetString("peer.committer.ledger.tls.clientkey.file")
+	if clientCertFile != "" || clientKeyFile != "" {
+		clientCert, err := tls.LoadX509KeyPair(clientCertFile, clientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load orderer client certificate %s and key %s: %s", clientCertFile, clientKeyFile, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// NewOrdererClientConnection returns a new grpc.ClientConn to the configured orderer
+func NewOrdererClientConnection(block bool) (*grpc.ClientConn, error) {
+	ordererAddress := viper.GetString("peer.committer.ledger.orderer")
+	if !OrdererTLSEnabled() {
+		return NewClientConnectionWithAddress(ordererAddress, block, false, nil)
+	}
+
+	creds, err := InitTLSForOrderer()
+	if err != nil {
+		return nil, err
+	}
+	return NewClientConnectionWithAddress(ordererAddress, block, true, creds)
+}
@@ -59,3 +59,46 @@ func TestConnection_WrongAddress(t *testing.T) {
 		tmpConn.Close()
 	}
 }
+
+func TestInitTLSForOrderer(t *testing.T) {
+	config.SetupTestConfig("./../../peer")
+	viper.Set("peer.committer.ledger.tls.rootcert.file", "testdata/certs/Org1-cert.pem")
+	viper.Set("peer.committer.ledger.tls.clientcert.file", "testdata/certs/Org1-client1-cert.pem")
+	viper.Set("peer.committer.ledger.tls.clientkey.file", "testdata/certs/Org1-client1-key.pem")
+	viper.Set("peer.committer.ledger.tls.serverhostoverride", "orderer")
+	defer viper.Set("peer.committer.ledger.tls.rootcert.file", "")
+	defer viper.Set("peer.committer.ledger.tls.clientcert.file", "")
+	defer viper.Set("peer.committer.ledger.tls.clientkey.file", "")
+	defer viper.Set("peer.committer.ledger.tls.serverhostoverride", "")
+
+	creds, err := InitTLSForOrderer()
+	if err != nil {
+		t.Fatalf("Failed to create orderer TLS credentials: %s", err)
+	}
+	if creds.Info().SecurityProtocol != "tls" {
+		t.Fatalf("Expected tls security protocol, got %s", creds.Info().SecurityProtocol)
+	}
+}
+
+func TestInitTLSForOrdererBadFiles(t *testing.T) {
+	config.SetupTestConfig("./../../peer")
+	defer viper.Set("peer.committer.ledger.tls.rootcert.file", "")
+	defer viper.Set("peer.committer.ledger.tls.clientcert.file", "")
+	defer viper.Set("peer.committer.ledger.tls.clientkey.file", "")
+
+	viper.Set("peer.committer.ledger.tls.rootcert.file", "testdata/certs/nonexistent-cert.pem")
+	if _, err := InitTLSForOrderer(); err == nil {
+		t.Fatalf("Should have failed to read a nonexistent root certificate")
+	}
+
+	viper.Set("peer.committer.ledger.tls.rootcert.file", "testdata/certs/Org1-key.pem")
+	if _, err := InitTLSForOrderer(); err == nil {
+		t.Fatalf("Should have failed to append a private key as root certificate")
+	}
+
+	viper.Set("peer.committer.ledger.tls.rootcert.file", "testdata/certs/Org1-cert.pem")
+	viper.Set("peer.committer.ledger.tls.clientcert.file", "testdata/certs/Org1-client1-cert.pem")
+	if _, err := InitTLSForOrderer(); err == nil {
+		t.Fatalf("Should have failed to load a client certificate without its key")
+	}
+}
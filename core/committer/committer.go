@@ -36,6 +36,9 @@ type Committer interface {
 	// Gets blocks with sequence numbers provided in the slice
 	GetBlocks(blockSeqs []uint64) []*common.Block
 
+	// Gets the hashing algorithm with which the blocks of the chain are hashed
+	HashingAlgorithm() func(input []byte) []byte
+
 	// Closes committing service
 	Close()
 }
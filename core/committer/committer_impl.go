@@ -17,10 +17,16 @@ limitations under the License.
 package committer
 
 import (
+	"bytes"
+	"fmt"
+
 	"github.com/hyperledger/fabric/core/committer/txvalidator"
 	"github.com/hyperledger/fabric/core/ledger"
+	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/events/producer"
 	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/op/go-logging"
 )
 
@@ -39,17 +45,35 @@ func init() {
 // it keeps the reference to the ledger to commit blocks and retreive
 // chain information
 type LedgerCommitter struct {
-	ledger    ledger.PeerLedger
-	validator txvalidator.Validator
+	ledger           ledger.PeerLedger
+	validator        txvalidator.Validator
+	hashingAlgorithm func(input []byte) []byte
+	eventer          ConfigBlockEventer
+}
+
+// ConfigBlockEventer callback function proto type to define action
+// upon arrival on new valid configuration block
+type ConfigBlockEventer func(block *common.Block) error
+
+// NewLedgerCommitter is a factory function to create an instance of the committer,
+// which checks the hashes of the blocks it commits with the given hashing algorithm
+func NewLedgerCommitter(ledger ledger.PeerLedger, validator txvalidator.Validator, hashingAlgorithm func(input []byte) []byte) *LedgerCommitter {
+	return NewLedgerCommitterReactive(ledger, validator, hashingAlgorithm, nil)
 }
 
-// NewLedgerCommitter is a factory function to create an instance of the committer
-func NewLedgerCommitter(ledger ledger.PeerLedger, validator txvalidator.Validator) *LedgerCommitter {
-	return &LedgerCommitter{ledger: ledger, validator: validator}
+// NewLedgerCommitterReactive is a factory function to create an instance of the committer
+// which, in addition, calls the eventer once a block carrying a valid configuration
+// transaction has been committed
+func NewLedgerCommitterReactive(ledger ledger.PeerLedger, validator txvalidator.Validator, hashingAlgorithm func(input []byte) []byte, eventer ConfigBlockEventer) *LedgerCommitter {
+	return &LedgerCommitter{ledger: ledger, validator: validator, hashingAlgorithm: hashingAlgorithm, eventer: eventer}
 }
 
 // CommitBlock commits block to into the ledger
 func (lc *LedgerCommitter) CommitBlock(block *common.Block) error {
+	if err := lc.verifyHashes(block); err != nil {
+		return err
+	}
+
 	// Validate and mark invalid transactions
 	logger.Debug("Validating block")
 	lc.validator.Validate(block)
@@ -57,9 +81,68 @@ func (lc *LedgerCommitter) CommitBlock(block *common.Block) error {
 	if err := lc.ledger.Commit(block); err != nil {
 		return err
 	}
+
+	// The block event is sent once the block is committed, so that its metadata carries the
+	// validation codes of the transactions, which are also sent for the invalid transactions
+	if err := producer.SendProducerBlockEvent(block); err != nil {
+		logger.Errorf("Error sending block event %s", err)
+	}
+	if err := producer.SendProducerRejectionEvents(block); err != nil {
+		logger.Errorf("Error sending rejection events %s", err)
+	}
+
+	// The configuration of a valid configuration block takes effect once the block is committed
+	if lc.eventer != nil && IsValidConfigBlock(block) {
+		if err := lc.eventer(block); err != nil {
+			return fmt.Errorf("Could not apply the configuration of block %d: %s", block.Header.Number, err)
+		}
+	}
 	return nil
 }
 
+// IsValidConfigBlock tells whether the block is a configuration block whose
+// transaction has not been marked invalid by the validator
+func IsValidConfigBlock(block *common.Block) bool {
+	if !utils.IsConfigBlock(block) {
+		return false
+	}
+	return ledgerUtil.GetTxValidationFlags(block.Metadata).IsValid(0)
+}
+
+// verifyHashes checks that the block carries the hash of its data, and of the block it follows
+func (lc *LedgerCommitter) verifyHashes(block *common.Block) error {
+	if block.Header == nil || block.Data == nil {
+		return fmt.Errorf("Block is missing its header or data")
+	}
+
+	if dataHash := block.Data.HashWith(lc.hashingAlgorithm); !bytes.Equal(block.Header.DataHash, dataHash) {
+		return fmt.Errorf("Block %d should have had data hash of %x but was %x", block.Header.Number, dataHash, block.Header.DataHash)
+	}
+
+	info, err := lc.ledger.GetBlockchainInfo()
+	if err != nil {
+		return err
+	}
+	if info.Height == 0 || block.Header.Number == 0 {
+		return nil
+	}
+
+	// The hash of the last block is taken from the blockchain info, which is available even if the
+	// block itself is not, as on a ledger created from a snapshot. The block store hashes the headers
+	// with the algorithm of the chain configuration it holds, which is that of the committer
+	previousHash := info.CurrentBlockHash
+	if !bytes.Equal(block.Header.PreviousHash, previousHash) {
+		return fmt.Errorf("Block %d should have had previous hash of %x but was %x", block.Header.Number, previousHash, block.Header.PreviousHash)
+	}
+
+	return nil
+}
+
+// HashingAlgorithm returns the hashing algorithm of the chain
+func (lc *LedgerCommitter) HashingAlgorithm() func(input []byte) []byte {
+	return lc.hashingAlgorithm
+}
+
 // LedgerHeight returns recently committed block sequence number
 func (lc *LedgerCommitter) LedgerHeight() (uint64, error) {
 	var info *pb.BlockchainInfo
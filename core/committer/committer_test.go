@@ -17,15 +17,25 @@ limitations under the License.
 package committer
 
 import (
+	"fmt"
+	"os"
 	"testing"
 
+	"github.com/hyperledger/fabric/common/chainconfig"
+	"github.com/hyperledger/fabric/common/configtx"
+	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/common/genesis"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger/testutil"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/core/mocks/validator"
+	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
 )
 
 func TestKVLedgerBlockStorage(t *testing.T) {
@@ -36,7 +46,7 @@ func TestKVLedgerBlockStorage(t *testing.T) {
 	assert.NoError(t, err, "Error while creating ledger: %s", err)
 	defer ledger.Close()
 
-	committer := NewLedgerCommitter(ledger, &validator.MockValidator{})
+	committer := NewLedgerCommitter(ledger, &validator.MockValidator{}, util.ComputeCryptoHash)
 	height, err := committer.LedgerHeight()
 	assert.Equal(t, uint64(0), height)
 	assert.NoError(t, err)
@@ -70,3 +80,149 @@ func TestKVLedgerBlockStorage(t *testing.T) {
 	testutil.AssertEquals(t, bcInfo, &pb.BlockchainInfo{
 		Height: 1, CurrentBlockHash: block1Hash, PreviousBlockHash: []byte{}})
 }
+
+func TestCommitBlockHashes(t *testing.T) {
+	viper.Set("peer.fileSystemPath", "/tmp/fabric/committertest")
+	ledgermgmt.InitializeTestEnv()
+	defer ledgermgmt.CleanupTestEnv()
+	ledger, err := ledgermgmt.CreateLedger("TestLedger")
+	assert.NoError(t, err, "Error while creating ledger: %s", err)
+	defer ledger.Close()
+
+	sha256, err := chainconfig.HashFunction(chainconfig.SHA256)
+	assert.NoError(t, err)
+	committer := NewLedgerCommitter(ledger, &validator.MockValidator{}, sha256)
+
+	newBlock := func(number uint64, previousHash []byte) *common.Block {
+		simulator, _ := ledger.NewTxSimulator()
+		simulator.SetState("ns1", "key1", []byte("value1"))
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		env, _, err := testutil.ConstructTransaction(t, simRes, true)
+		assert.NoError(t, err)
+
+		block := common.NewBlock(number, previousHash)
+		block.Data.Data = [][]byte{utils.MarshalOrPanic(env)}
+		block.Header.DataHash = block.Data.HashWith(sha256)
+		return block
+	}
+
+	// The genesis block configures the hashing algorithm of the chain
+	block0, err := genesis.NewFactoryImpl(configtx.NewSimpleTemplate(&common.ConfigurationItem{
+		Type:  common.ConfigurationItem_Chain,
+		Key:   chainconfig.HashingAlgorithmKey,
+		Value: utils.MarshalOrPanic(&common.HashingAlgorithm{Name: chainconfig.SHA256}),
+	})).Block("TestLedger")
+	assert.NoError(t, err)
+	block0.Header.DataHash = block0.Data.Hash()
+	assert.Error(t, committer.CommitBlock(block0), "Should have rejected a data hash computed with another algorithm")
+
+	block0.Header.DataHash = block0.Data.HashWith(sha256)
+	assert.NoError(t, committer.CommitBlock(block0))
+
+	block1 := newBlock(1, block0.Header.Hash())
+	assert.Error(t, committer.CommitBlock(block1), "Should have rejected a previous hash computed with another algorithm")
+
+	block1 = newBlock(1, block0.Header.HashWith(sha256))
+	assert.NoError(t, committer.CommitBlock(block1))
+
+	height, err := committer.LedgerHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), height)
+}
+
+func TestCommitBlockAfterSnapshot(t *testing.T) {
+	viper.Set("peer.fileSystemPath", "/tmp/fabric/committertest")
+	ledgermgmt.InitializeTestEnv()
+	defer ledgermgmt.CleanupTestEnv()
+	ledger, err := ledgermgmt.CreateLedger("TestLedger")
+	assert.NoError(t, err, "Error while creating ledger: %s", err)
+
+	newBlock := func(number uint64, previousHash []byte) *common.Block {
+		simulator, _ := ledger.NewTxSimulator()
+		simulator.SetState("ns1", "key1", []byte(fmt.Sprintf("value%d", number)))
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		env, _, err := testutil.ConstructTransaction(t, simRes, true)
+		assert.NoError(t, err)
+
+		block := common.NewBlock(number, previousHash)
+		block.Data.Data = [][]byte{utils.MarshalOrPanic(env)}
+		block.Header.DataHash = block.Data.Hash()
+		return block
+	}
+
+	committer := NewLedgerCommitter(ledger, &validator.MockValidator{}, util.ComputeCryptoHash)
+	block1 := newBlock(1, nil)
+	assert.NoError(t, committer.CommitBlock(block1))
+	block2 := newBlock(2, block1.Header.Hash())
+	assert.NoError(t, committer.CommitBlock(block2))
+
+	snapshotDir := "/tmp/fabric/committertest_snapshot"
+	os.RemoveAll(snapshotDir)
+	defer os.RemoveAll(snapshotDir)
+	_, err = ledger.ExportSnapshot(snapshotDir)
+	assert.NoError(t, err)
+	ledger.Close()
+
+	// the ledger is recreated from the snapshot in a new store, where the blocks of the snapshot are not
+	// available and the previous hash is checked against the blockchain info
+	ledgermgmt.CleanupTestEnv()
+	ledgermgmt.InitializeTestEnv()
+	ledger, err = ledgermgmt.CreateLedgerFromSnapshot("TestLedger", snapshotDir)
+	assert.NoError(t, err)
+	defer ledger.Close()
+	_, err = ledger.GetBlockByNumber(2)
+	assert.Error(t, err)
+
+	committer = NewLedgerCommitter(ledger, &validator.MockValidator{}, util.ComputeCryptoHash)
+	assert.Error(t, committer.CommitBlock(newBlock(3, block1.Header.Hash())), "Should have rejected a wrong previous hash")
+	assert.NoError(t, committer.CommitBlock(newBlock(3, block2.Header.Hash())))
+
+	height, err := committer.LedgerHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), height)
+}
+
+func TestCommitConfigBlock(t *testing.T) {
+	viper.Set("peer.fileSystemPath", "/tmp/fabric/committertest")
+	ledgermgmt.InitializeTestEnv()
+	defer ledgermgmt.CleanupTestEnv()
+	ledger, err := ledgermgmt.CreateLedger("TestLedger")
+	assert.NoError(t, err, "Error while creating ledger: %s", err)
+	defer ledger.Close()
+
+	var configBlocks []uint64
+	committer := NewLedgerCommitterReactive(ledger, &validator.MockValidator{}, util.ComputeCryptoHash, func(block *common.Block) error {
+		configBlocks = append(configBlocks, block.Header.Number)
+		return nil
+	})
+
+	block0, err := configtxtest.MakeGenesisBlock("testchainid")
+	assert.NoError(t, err)
+	assert.NoError(t, committer.CommitBlock(block0))
+	assert.Equal(t, []uint64{0}, configBlocks, "Expected the eventer to be called for the configuration block")
+
+	// a configuration block whose transaction was marked invalid does not take effect
+	block1 := common.NewBlock(1, block0.Header.Hash())
+	block1.Data.Data = block0.Data.Data
+	block1.Header.DataHash = block1.Data.Hash()
+	utils.InitBlockMetadata(block1)
+	txsFilter := ledgerUtil.NewTxValidationFlags(1)
+	txsFilter.SetFlag(0, pb.TxValidationCode_INVALID_CONFIG_TRANSACTION)
+	ledgerUtil.SetTxValidationFlags(block1, txsFilter)
+	assert.NoError(t, committer.CommitBlock(block1))
+	assert.Equal(t, []uint64{0}, configBlocks, "Expected the eventer not to be called for an invalid configuration block")
+
+	simulator, _ := ledger.NewTxSimulator()
+	simulator.SetState("ns1", "key1", []byte("value1"))
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	env, _, err := testutil.ConstructTransaction(t, simRes, true)
+	assert.NoError(t, err)
+	block2 := common.NewBlock(2, block1.Header.Hash())
+	block2.Data.Data = [][]byte{utils.MarshalOrPanic(env)}
+	block2.Header.DataHash = block2.Data.Hash()
+	assert.NoError(t, committer.CommitBlock(block2))
+	assert.Equal(t, []uint64{0}, configBlocks, "Expected the eventer not to be called for an endorser transaction block")
+}
@@ -17,10 +17,20 @@ limitations under the License.
 package txvalidator
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/policies"
 	util2 "github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	ledger2 "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwset"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	"github.com/hyperledger/fabric/core/ledger/testutil"
 	"github.com/hyperledger/fabric/core/ledger/util"
@@ -39,7 +49,7 @@ func TestKVLedgerBlockStorage(t *testing.T) {
 	ledger, _ := ledgermgmt.CreateLedger("TestLedger")
 	defer ledger.Close()
 
-	validator := &txValidator{ledger, &validator.MockVsccValidator{}}
+	validator := &txValidator{ledger, &validator.MockVsccValidator{}, nil}
 
 	bcInfo, _ := ledger.GetBlockchainInfo()
 	testutil.AssertEquals(t, bcInfo, &pb.BlockchainInfo{
@@ -56,11 +66,11 @@ func TestKVLedgerBlockStorage(t *testing.T) {
 
 	validator.Validate(block)
 
-	txsfltr := util.NewFilterBitArrayFromBytes(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	txsfltr := util.GetTxValidationFlags(block.Metadata)
 
-	assert.True(t, !txsfltr.IsSet(uint(0)))
-	assert.True(t, !txsfltr.IsSet(uint(1)))
-	assert.True(t, !txsfltr.IsSet(uint(2)))
+	assert.True(t, txsfltr.IsValid(0))
+	assert.True(t, txsfltr.IsValid(1))
+	assert.True(t, txsfltr.IsValid(2))
 }
 
 func TestNewTxValidator_DuplicateTransactions(t *testing.T) {
@@ -70,7 +80,7 @@ func TestNewTxValidator_DuplicateTransactions(t *testing.T) {
 	ledger, _ := ledgermgmt.CreateLedger("TestLedger")
 	defer ledger.Close()
 
-	validator := &txValidator{ledger, &validator.MockVsccValidator{}}
+	validator := &txValidator{ledger, &validator.MockVsccValidator{}, nil}
 
 	// Create simeple endorsement transaction
 	payload := &common.Payload{
@@ -120,7 +130,205 @@ func TestNewTxValidator_DuplicateTransactions(t *testing.T) {
 	// because it's already committed
 	validator.Validate(block)
 
-	txsfltr := util.NewFilterBitArrayFromBytes(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	txsfltr := util.GetTxValidationFlags(block.Metadata)
 
-	assert.True(t, txsfltr.IsSet(0))
+	assert.True(t, txsfltr.IsInvalid(0))
+}
+
+func TestValidationCodes(t *testing.T) {
+	validator := &txValidator{nil, &validator.MockVsccValidator{}, nil}
+
+	badPayload := utils.MarshalOrPanic(&common.Envelope{Payload: []byte("garbage")})
+	noHeader := utils.MarshalOrPanic(&common.Envelope{Payload: utils.MarshalOrPanic(&common.Payload{Data: []byte("test")})})
+	block := &common.Block{Data: &common.BlockData{Data: [][]byte{nil, badPayload, noHeader}}}
+	block.Header = &common.BlockHeader{Number: 1, DataHash: block.Data.Hash()}
+
+	validator.Validate(block)
+
+	txsfltr := util.GetTxValidationFlags(block.Metadata)
+	assert.Equal(t, pb.TxValidationCode_NIL_ENVELOPE, txsfltr.Flag(0))
+	assert.Equal(t, pb.TxValidationCode_BAD_PAYLOAD, txsfltr.Flag(1))
+	assert.Equal(t, pb.TxValidationCode_BAD_COMMON_HEADER, txsfltr.Flag(2))
+}
+
+// lcccCcProvider returns the validation info of a chaincode as
+// recorded by LCCC and records the invocations of the VSCC, which
+// are passed on to the validators registered with the provider
+type lcccCcProvider struct {
+	vscc       string
+	policy     []byte
+	validators map[string]shim.Chaincode
+	invokedCC  string
+	invokeArgs [][]byte
+}
+
+func (c *lcccCcProvider) GetContext(ledger ledger2.PeerLedger) (context.Context, error) {
+	return context.Background(), nil
+}
+
+func (c *lcccCcProvider) GetCCContext(cid, name, version, txid string, syscc bool, prop *pb.Proposal) interface{} {
+	return name
+}
+
+func (c *lcccCcProvider) GetCCValidationInfoFromLCCC(ctxt context.Context, txid string, prop *pb.Proposal, chainID string, chaincodeID string) (string, []byte, error) {
+	return c.vscc, c.policy, nil
+}
+
+func (c *lcccCcProvider) ExecuteChaincode(ctxt context.Context, cccid interface{}, args [][]byte) ([]byte, *pb.ChaincodeEvent, error) {
+	c.invokedCC = cccid.(string)
+	c.invokeArgs = args
+	if validator, ok := c.validators[c.invokedCC]; ok {
+		res, err := shim.NewMockStub(c.invokedCC, validator).MockInvoke("1", args)
+		return res, nil, err
+	}
+	return nil, nil, nil
+}
+
+func (c *lcccCcProvider) ReleaseContext() {
+}
+
+func TestVSCCValidateTxWithLCCCPolicy(t *testing.T) {
+	policy, err := cauthdsl.FromString("OutOf(2, 'Org1.member', 'Org2.member', 'Org3.member')")
+	assert.NoError(t, err)
+	policyBytes := utils.MarshalOrPanic(policy)
+	ccprovider := &lcccCcProvider{vscc: "myvscc", policy: policyBytes}
+	vscc := &vsccValidatorImpl{ccprovider: ccprovider}
+
+	hdrExt := utils.MarshalOrPanic(&pb.ChaincodeHeaderExtension{ChaincodeID: &pb.ChaincodeID{Name: "mycc"}})
+	payload := &common.Payload{
+		Header: &common.Header{
+			ChainHeader: &common.ChainHeader{
+				TxID:      "txid",
+				Type:      int32(common.HeaderType_ENDORSER_TRANSACTION),
+				ChainID:   util2.GetTestChainID(),
+				Extension: hdrExt,
+			},
+		},
+	}
+
+	// the VSCC listed by LCCC is invoked with the endorsement policy of the chaincode
+	err = vscc.VSCCValidateTx(payload, []byte("envelope"))
+	assert.NoError(t, err)
+	assert.Equal(t, "myvscc", ccprovider.invokedCC)
+	assert.Equal(t, [][]byte{[]byte(""), []byte("envelope"), policyBytes}, ccprovider.invokeArgs)
+}
+
+// nonNegativeValidator is a custom validation system chaincode enforcing
+// the business rule that the values written by a transaction are non
+// negative amounts
+type nonNegativeValidator struct {
+}
+
+func (v *nonNegativeValidator) Init(stub shim.ChaincodeStubInterface) ([]byte, error) {
+	return nil, nil
+}
+
+func (v *nonNegativeValidator) Invoke(stub shim.ChaincodeStubInterface) ([]byte, error) {
+	args := stub.GetArgs()
+	if len(args) < 3 {
+		return nil, errors.New("Incorrect number of arguments")
+	}
+	action, err := utils.GetActionFromEnvelope(args[1])
+	if err != nil {
+		return nil, err
+	}
+	txRWSet := &rwset.TxReadWriteSet{}
+	if err = txRWSet.Unmarshal(action.Results); err != nil {
+		return nil, err
+	}
+	for _, nsRWSet := range txRWSet.NsRWs {
+		for _, kvWrite := range nsRWSet.Writes {
+			if kvWrite.IsDelete {
+				continue
+			}
+			if amount, err := strconv.Atoi(string(kvWrite.Value)); err != nil || amount < 0 {
+				return nil, fmt.Errorf("Invalid amount %s for key %s", kvWrite.Value, kvWrite.Key)
+			}
+		}
+	}
+	return nil, nil
+}
+
+func TestVSCCValidateTxWithCustomValidator(t *testing.T) {
+	ccprovider := &lcccCcProvider{vscc: "nonnegvscc", policy: []byte("policy"),
+		validators: map[string]shim.Chaincode{"nonnegvscc": &nonNegativeValidator{}}}
+	vscc := &vsccValidatorImpl{ccprovider: ccprovider}
+
+	validateTx := func(values ...string) error {
+		nsRWSet := &rwset.NsReadWriteSet{NameSpace: "foo"}
+		for i, value := range values {
+			nsRWSet.Writes = append(nsRWSet.Writes, rwset.NewKVWrite(fmt.Sprintf("account%d", i), []byte(value)))
+		}
+		simRes, err := (&rwset.TxReadWriteSet{NsRWs: []*rwset.NsReadWriteSet{nsRWSet}}).Marshal()
+		assert.NoError(t, err)
+		env, _, err := testutil.ConstructTransaction(t, simRes, false)
+		assert.NoError(t, err)
+		payload, err := utils.GetPayload(env)
+		assert.NoError(t, err)
+		return vscc.VSCCValidateTx(payload, utils.MarshalOrPanic(env))
+	}
+
+	assert.NoError(t, validateTx("100", "0"))
+	assert.Equal(t, "nonnegvscc", ccprovider.invokedCC)
+	assert.Error(t, validateTx("100", "-50"), "Expected the custom validator to reject a negative amount")
+	assert.Error(t, validateTx("a hundred"), "Expected the custom validator to reject a value that is not an amount")
+}
+
+func makeConfigPayload(chainID string, lastModified uint64) *common.Payload {
+	item := &common.ConfigurationItem{
+		Header:       &common.ChainHeader{ChainID: chainID},
+		Type:         common.ConfigurationItem_Peer,
+		LastModified: lastModified,
+		Key:          "foo",
+		Value:        []byte("foo"),
+	}
+	configEnvelope := &common.ConfigurationEnvelope{
+		Items: []*common.SignedConfigurationItem{{ConfigurationItem: utils.MarshalOrPanic(item)}},
+	}
+	return &common.Payload{
+		Header: &common.Header{
+			ChainHeader: &common.ChainHeader{
+				Type:    int32(common.HeaderType_CONFIGURATION_TRANSACTION),
+				ChainID: chainID,
+			},
+		},
+		Data: utils.MarshalOrPanic(configEnvelope),
+	}
+}
+
+// acceptAllPolicyManager holds a policy, accepting all, for any policy id
+type acceptAllPolicyManager struct {
+}
+
+func (m *acceptAllPolicyManager) GetPolicy(id string) (policies.Policy, bool) {
+	return m, true
+}
+
+func (m *acceptAllPolicyManager) Evaluate(signedData []*common.SignedData) error {
+	return nil
+}
+
+func TestValidateConfigTx(t *testing.T) {
+	chainID := util2.GetTestChainID()
+	v := &txValidator{nil, &validator.MockVsccValidator{}, nil}
+	assert.Error(t, v.validateConfigTx(makeConfigPayload(chainID, 1)), "Expected an error without a configuration to validate against")
+
+	handlers := make(map[common.ConfigurationItem_ConfigurationType]configtx.Handler)
+	for ctype := range common.ConfigurationItem_ConfigurationType_name {
+		handlers[common.ConfigurationItem_ConfigurationType(ctype)] = configtx.NewBytesHandler()
+	}
+	configEnvelope, err := utils.GetConfigurationEnvelope(makeConfigPayload(chainID, 0).Data)
+	assert.NoError(t, err)
+	configManager, err := configtx.NewConfigurationManager(configEnvelope, &acceptAllPolicyManager{}, handlers)
+	assert.NoError(t, err)
+	v.configManager = configManager
+
+	assert.NoError(t, v.validateConfigTx(makeConfigPayload(chainID, 1)))
+	assert.Error(t, v.validateConfigTx(makeConfigPayload(chainID, 0)), "Expected a replayed configuration to be rejected")
+	assert.Error(t, v.validateConfigTx(makeConfigPayload(chainID, 2)), "Expected a configuration skipping a sequence number to be rejected")
+	assert.Error(t, v.validateConfigTx(makeConfigPayload("otherchain", 1)), "Expected a configuration of another chain to be rejected")
+	assert.Error(t, v.validateConfigTx(&common.Payload{Data: []byte("garbage")}), "Expected a malformed configuration to be rejected")
+
+	// validating does not apply the configuration
+	assert.Equal(t, uint64(0), configManager.Sequence())
 }
@@ -21,22 +21,25 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/configtx"
 	coreUtil "github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/validation"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/blkstorage"
 	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/core/peer/msp"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/op/go-logging"
 	"github.com/syndtr/goleveldb/leveldb/errors"
 )
 
 //Validator interface which defines API to validate block transactions
-// and return the bit array mask indicating invalid transactions which
-// didn't pass validation.
+// and record the validation code of each transaction in the
+// metadata of the block.
 type Validator interface {
 	Validate(block *common.Block)
 }
@@ -57,10 +60,13 @@ type vsccValidatorImpl struct {
 
 // implementation of Validator interface, keeps
 // reference to the ledger to enable tx simulation
-// and execution of vscc
+// and execution of vscc, and to the configuration
+// manager of the chain to validate configuration
+// transactions
 type txValidator struct {
-	ledger ledger.PeerLedger
-	vscc   vsccValidator
+	ledger        ledger.PeerLedger
+	vscc          vsccValidator
+	configManager configtx.Manager
 }
 
 var logger *logging.Logger // package-level logger
@@ -70,10 +76,11 @@ func init() {
 	logger = logging.MustGetLogger("txvalidator")
 }
 
-// NewTxValidator creates new transactions validator
-func NewTxValidator(ledger ledger.PeerLedger) Validator {
+// NewTxValidator creates new transactions validator, which validates the
+// configuration transactions against the configuration held by configManager
+func NewTxValidator(ledger ledger.PeerLedger, configManager configtx.Manager) Validator {
 	// Encapsulates interface implementation
-	return &txValidator{ledger, &vsccValidatorImpl{ledger: ledger, ccprovider: ccprovider.GetChaincodeProvider()}}
+	return &txValidator{ledger, &vsccValidatorImpl{ledger: ledger, ccprovider: ccprovider.GetChaincodeProvider()}, configManager}
 }
 
 func (v *txValidator) chainExists(chain string) bool {
@@ -84,14 +91,15 @@ func (v *txValidator) chainExists(chain string) bool {
 func (v *txValidator) Validate(block *common.Block) {
 	logger.Debug("START Block Validation")
 	defer logger.Debug("END Block Validation")
-	txsfltr := ledgerUtil.NewFilterBitArray(uint(len(block.Data.Data)))
+	txsfltr := ledgerUtil.NewTxValidationFlags(len(block.Data.Data))
 	for tIdx, d := range block.Data.Data {
-		// Start by marking transaction as invalid, before
+		// Start by marking transaction as not validated, before
 		// doing any validation checks.
-		txsfltr.Set(uint(tIdx))
+		txsfltr.SetFlag(tIdx, peer.TxValidationCode_NOT_VALIDATED)
 		if d != nil {
 			if env, err := utils.GetEnvelopeFromBlock(d); err != nil {
 				logger.Warningf("Error getting tx from block(%s)", err)
+				txsfltr.SetFlag(tIdx, peer.TxValidationCode_INVALID_OTHER_REASON)
 			} else if env != nil {
 				// validate the transaction: here we check that the transaction
 				// is properly formed, properly signed and that the security
@@ -101,8 +109,10 @@ func (v *txValidator) Validate(block *common.Block) {
 				logger.Debug("Validating transaction peer.ValidateTransaction()")
 				var payload *common.Payload
 				var err error
-				if payload, err = validation.ValidateTransaction(env); err != nil {
+				var txResult peer.TxValidationCode
+				if payload, txResult, err = validation.ValidateTransaction(env); err != nil {
 					logger.Errorf("Invalid transaction with index %d, error %s", tIdx, err)
+					txsfltr.SetFlag(tIdx, txResult)
 					continue
 				}
 
@@ -111,14 +121,17 @@ func (v *txValidator) Validate(block *common.Block) {
 
 				if !v.chainExists(chain) {
 					logger.Errorf("Dropping transaction for non-existent chain %s", chain)
+					txsfltr.SetFlag(tIdx, peer.TxValidationCode_TARGET_CHAIN_NOT_FOUND)
 					continue
 				}
 
 				if common.HeaderType(payload.Header.ChainHeader.Type) == common.HeaderType_ENDORSER_TRANSACTION {
 					// Check duplicate transactions
 					txID := payload.Header.ChainHeader.TxID
-					if _, err := v.ledger.GetTransactionByID(txID); err == nil {
+					// a transaction in a pruned block is still known to the ledger
+					if _, err := v.ledger.GetTransactionByID(txID); err == nil || err == blkstorage.ErrPruned {
 						logger.Warning("Duplicate transaction found, ", txID, ", skipping")
+						txsfltr.SetFlag(tIdx, peer.TxValidationCode_DUPLICATE_TXID)
 						continue
 					}
 
@@ -127,41 +140,65 @@ func (v *txValidator) Validate(block *common.Block) {
 					if err = v.vscc.VSCCValidateTx(payload, d); err != nil {
 						txID := txID
 						logger.Errorf("VSCCValidateTx for transaction txId = %s returned error %s", txID, err)
+						txsfltr.SetFlag(tIdx, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
 						continue
 					}
 				} else if common.HeaderType(payload.Header.ChainHeader.Type) == common.HeaderType_CONFIGURATION_TRANSACTION {
-					// TODO: here we should call CSCC and pass it the config tx
-					// note that there is quite a bit more validation necessary
-					// on this tx, namely, validation that each config item has
-					// signature matching the policy required for the item from
-					// the existing configuration; this is taken care of nicely
-					// by configtx.Manager (see fabric/common/configtx).
-					logger.Debug("config transaction received for chain %s", chain)
+					logger.Debugf("config transaction received for chain %s", chain)
+					// the genesis block carries the configuration the chain was
+					// created from, later configuration transactions have to be
+					// signed as required by the modification policies of the
+					// current configuration, and to follow its sequence number
+					if block.Header.Number != 0 {
+						if err = v.validateConfigTx(payload); err != nil {
+							logger.Errorf("Invalid configuration transaction with index %d for chain %s, error %s", tIdx, chain, err)
+							txsfltr.SetFlag(tIdx, peer.TxValidationCode_INVALID_CONFIG_TRANSACTION)
+							continue
+						}
+					}
 				}
 
 				if _, err := proto.Marshal(env); err != nil {
 					logger.Warningf("Cannot marshal transaction due to %s", err)
+					txsfltr.SetFlag(tIdx, peer.TxValidationCode_INVALID_OTHER_REASON)
 					continue
 				}
-				// Succeeded to pass down here, transaction is valid,
-				// just unset the filter bit array flag.
-				txsfltr.Unset(uint(tIdx))
+				// Succeeded to pass down here, transaction is valid
+				txsfltr.SetFlag(tIdx, peer.TxValidationCode_VALID)
 			} else {
 				logger.Warning("Nil tx from block")
+				txsfltr.SetFlag(tIdx, peer.TxValidationCode_NIL_ENVELOPE)
 			}
+		} else {
+			txsfltr.SetFlag(tIdx, peer.TxValidationCode_NIL_ENVELOPE)
 		}
 	}
 	// Initialize metadata structure
 	utils.InitBlockMetadata(block)
-	// Serialize invalid transaction bit array into block metadata field
-	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsfltr.ToBytes()
+	// Serialize the validation codes of the transactions into block metadata field
+	ledgerUtil.SetTxValidationFlags(block, txsfltr)
+}
+
+// validateConfigTx validates the configuration envelope carried by the payload
+// against the current configuration of the chain. The configuration is applied
+// once the block is committed
+func (v *txValidator) validateConfigTx(payload *common.Payload) error {
+	if v.configManager == nil {
+		return fmt.Errorf("No configuration to validate the configuration transaction against")
+	}
+	configEnvelope, err := utils.GetConfigurationEnvelope(payload.Data)
+	if err != nil {
+		return err
+	}
+	return v.configManager.Validate(configEnvelope)
 }
 
-// getHardcodedPolicy returns a policy that requests
+// getDefaultPolicy returns a policy that requests
 // one valid signature from the first MSP in this
-// chain's MSP manager
-// FIXME: this needs to be removed as soon as we extract the policy from LCCC
-func getHardcodedPolicy(chainID string) ([]byte, error) {
+// chain's MSP manager; it applies to the transactions
+// of LCCC and of the chaincodes deployed without an
+// endorsement policy
+func getDefaultPolicy(chainID string) ([]byte, error) {
 	// 1) determine the MSP identifier for the first MSP in this chain
 	var msp msp.MSP
 	mspMgr := mspmgmt.GetManagerForChain(chainID)
@@ -211,21 +248,6 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 		return err
 	}
 
-	// TODO: temporary workaround until the policy is specified
-	// by the deployer and can be retrieved via LCCC: we create
-	// a policy that requests 1 valid signature from this chain's
-	// MSP
-	policy, err := getHardcodedPolicy(chainID)
-	if err != nil {
-		return err
-	}
-
-	// build arguments for VSCC invocation
-	// args[0] - function name (not used now)
-	// args[1] - serialized Envelope
-	// args[2] - serialized policy
-	args := [][]byte{[]byte(""), envBytes, policy}
-
 	ctxt, err := v.ccprovider.GetContext(v.ledger)
 	if err != nil {
 		logger.Errorf("Cannot obtain context for txid=%s, err %s", txid, err)
@@ -244,16 +266,32 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 	// hence no lccc yet to query for the data, therefore currently
 	// introducing a workaround to skip obtaining LCCC data.
 	vscc := "vscc"
+	var policy []byte
 	if hdrExt.ChaincodeID.Name != "lccc" {
-		// Extracting vscc from lccc
-		// TODO: extract policy as well when available; it's the second argument returned by GetCCValidationInfoFromLCCC
-		vscc, _, err = v.ccprovider.GetCCValidationInfoFromLCCC(ctxt, txid, nil, chainID, hdrExt.ChaincodeID.Name)
+		// Extracting vscc and the endorsement policy from lccc
+		vscc, policy, err = v.ccprovider.GetCCValidationInfoFromLCCC(ctxt, txid, nil, chainID, hdrExt.ChaincodeID.Name)
 		if err != nil {
 			logger.Errorf("Unable to get chaincode data from LCCC for txid %s, due to %s", txid, err)
 			return err
 		}
 	}
 
+	if len(policy) == 0 {
+		// no endorsement policy was specified: we create
+		// a policy that requests 1 valid signature from
+		// this chain's MSP
+		policy, err = getDefaultPolicy(chainID)
+		if err != nil {
+			return err
+		}
+	}
+
+	// build arguments for VSCC invocation
+	// args[0] - function name (not used now)
+	// args[1] - serialized Envelope
+	// args[2] - serialized policy
+	args := [][]byte{[]byte(""), envBytes, policy}
+
 	vscctxid := coreUtil.GenerateUUID()
 	// Get chaincode version
 	version := coreUtil.GetSysCCVersion()
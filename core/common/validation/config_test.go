@@ -36,7 +36,7 @@ func TestValidateConfigTx(t *testing.T) {
 		return
 	}
 
-	_, err = ValidateTransaction(chCrtEnv)
+	_, _, err = ValidateTransaction(chCrtEnv)
 	if err != nil {
 		t.Fatalf("ValidateTransaction failed, err %s", err)
 		return
@@ -82,7 +82,7 @@ func TestGoodPath(t *testing.T) {
 	}
 
 	// validate the transaction
-	payl, err := ValidateTransaction(tx)
+	payl, _, err := ValidateTransaction(tx)
 	if err != nil {
 		t.Fatalf("ValidateTransaction failed, err %s", err)
 		return
@@ -218,7 +218,7 @@ func TestBadTx(t *testing.T) {
 	corrupt(tx.Payload)
 
 	// validate the transaction it should fail
-	_, err = ValidateTransaction(tx)
+	_, _, err = ValidateTransaction(tx)
 	if err == nil {
 		t.Fatalf("ValidateTransaction should have failed")
 		return
@@ -235,11 +235,15 @@ func TestBadTx(t *testing.T) {
 	corrupt(tx.Signature)
 
 	// validate the transaction it should fail
-	_, err = ValidateTransaction(tx)
+	_, code, err := ValidateTransaction(tx)
 	if err == nil {
 		t.Fatalf("ValidateTransaction should have failed")
 		return
 	}
+	if code != peer.TxValidationCode_BAD_CREATOR_SIGNATURE {
+		t.Fatalf("ValidateTransaction should have failed with code %s, got %s", peer.TxValidationCode_BAD_CREATOR_SIGNATURE, code)
+		return
+	}
 }
 
 func Test2EndorsersAgree(t *testing.T) {
@@ -276,7 +280,7 @@ func Test2EndorsersAgree(t *testing.T) {
 	}
 
 	// validate the transaction
-	_, err = ValidateTransaction(tx)
+	_, _, err = ValidateTransaction(tx)
 	if err != nil {
 		t.Fatalf("ValidateTransaction failed, err %s", err)
 		return
@@ -333,19 +333,20 @@ func validateEndorserTransaction(data []byte, hdr *common.Header) error {
 	return nil
 }
 
-// ValidateTransaction checks that the transaction envelope is properly formed
-func ValidateTransaction(e *common.Envelope) (*common.Payload, error) {
+// ValidateTransaction checks that the transaction envelope is properly formed. The validation code
+// returned along with the error tells why the transaction is not
+func ValidateTransaction(e *common.Envelope) (*common.Payload, pb.TxValidationCode, error) {
 	putilsLogger.Infof("ValidateTransactionEnvelope starts for envelope %p", e)
 
 	// check for nil argument
 	if e == nil {
-		return nil, fmt.Errorf("Nil Envelope")
+		return nil, pb.TxValidationCode_NIL_ENVELOPE, fmt.Errorf("Nil Envelope")
 	}
 
 	// get the payload from the envelope
 	payload, err := utils.GetPayload(e)
 	if err != nil {
-		return nil, fmt.Errorf("Could not extract payload from envelope, err %s", err)
+		return nil, pb.TxValidationCode_BAD_PAYLOAD, fmt.Errorf("Could not extract payload from envelope, err %s", err)
 	}
 
 	putilsLogger.Infof("Header is %s", payload.Header)
@@ -353,13 +354,13 @@ func ValidateTransaction(e *common.Envelope) (*common.Payload, error) {
 	// validate the header
 	err = validateCommonHeader(payload.Header)
 	if err != nil {
-		return nil, err
+		return nil, pb.TxValidationCode_BAD_COMMON_HEADER, err
 	}
 
 	// validate the signature in the envelope
 	err = checkSignatureFromCreator(payload.Header.SignatureHeader.Creator, e.Signature, e.Payload, payload.Header.ChainHeader.ChainID)
 	if err != nil {
-		return nil, err
+		return nil, pb.TxValidationCode_BAD_CREATOR_SIGNATURE, err
 	}
 
 	// TODO: ensure that creator can transact with us (some ACLs?) which set of APIs is supposed to give us this info?
@@ -371,12 +372,18 @@ func ValidateTransaction(e *common.Envelope) (*common.Payload, error) {
 	case common.HeaderType_ENDORSER_TRANSACTION:
 		err = validateEndorserTransaction(payload.Data, payload.Header)
 		putilsLogger.Infof("ValidateTransactionEnvelope returns err %s", err)
-		return payload, err
+		if err != nil {
+			return nil, pb.TxValidationCode_INVALID_ENDORSER_TRANSACTION, err
+		}
+		return payload, pb.TxValidationCode_VALID, nil
 	case common.HeaderType_CONFIGURATION_TRANSACTION:
 		err = validateConfigTransaction(payload.Data, payload.Header)
 		putilsLogger.Infof("ValidateTransactionEnvelope returns err %s", err)
-		return payload, err
+		if err != nil {
+			return nil, pb.TxValidationCode_INVALID_CONFIG_TRANSACTION, err
+		}
+		return payload, pb.TxValidationCode_VALID, nil
 	default:
-		return nil, fmt.Errorf("Unsupported transaction payload type %d", common.HeaderType(payload.Header.ChainHeader.Type))
+		return nil, pb.TxValidationCode_UNKNOWN_TX_TYPE, fmt.Errorf("Unsupported transaction payload type %d", common.HeaderType(payload.Header.ChainHeader.Type))
 	}
 }
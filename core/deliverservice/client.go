@@ -18,11 +18,10 @@ package deliverclient
 
 import (
 	"math"
-	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/core/committer"
-	"github.com/hyperledger/fabric/events/producer"
 	gossipcommon "github.com/hyperledger/fabric/gossip/common"
 	gossip_proto "github.com/hyperledger/fabric/gossip/proto"
 	"github.com/hyperledger/fabric/gossip/service"
@@ -108,9 +107,8 @@ func (d *DeliverService) startDeliver(committer committer.Committer) error {
 }
 
 func (d *DeliverService) initDeliver() error {
-	opts := []grpc.DialOption{grpc.WithInsecure(), grpc.WithTimeout(3 * time.Second), grpc.WithBlock()}
 	endpoint := viper.GetString("peer.committer.ledger.orderer")
-	conn, err := grpc.Dial(endpoint, opts...)
+	conn, err := comm.NewOrdererClientConnection(true)
 	if err != nil {
 		logger.Errorf("Cannot dial to %s, because of %s", endpoint, err)
 		return err
@@ -220,9 +218,6 @@ func (d *DeliverService) readUntilClose() {
 			// Gossip messages with other nodes
 			logger.Debugf("Gossiping block [%d], peers number [%d]", seqNum, numberOfPeers)
 			service.GetGossipService().Gossip(gossipMsg)
-			if err = producer.SendProducerBlockEvent(t.Block); err != nil {
-				logger.Errorf("Error sending block event %s", err)
-			}
 
 		default:
 			logger.Warning("Received unknown: ", t)
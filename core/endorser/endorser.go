@@ -23,11 +23,13 @@ import (
 	"github.com/op/go-logging"
 	"golang.org/x/net/context"
 
+	"github.com/hyperledger/fabric/common/cauthdsl"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/chaincode"
 	"github.com/hyperledger/fabric/core/common/validation"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/peer"
+	mspmgmt "github.com/hyperledger/fabric/core/peer/msp"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
@@ -36,6 +38,17 @@ import (
 
 var endorserLogger = logging.MustGetLogger("endorser")
 
+// The policies of the chain configuration that the creator of a proposal has to
+// satisfy: proposals querying the ledger or the chaincodes registered with LCCC
+// require the readers policy, while any other proposal may write to the ledger
+// and requires the writers policy. Deploying and upgrading chaincodes additionally
+// require the admins policy
+const (
+	readersPolicy = "Readers"
+	writersPolicy = "Writers"
+	adminsPolicy  = "Admins"
+)
+
 // The Jira issue that documents Endorser flow along with its relationship to
 // the lifecycle chaincode - https://jira.hyperledger.org/browse/FAB-181
 
@@ -49,13 +62,109 @@ func NewEndorserServer() pb.EndorserServer {
 	return e
 }
 
-//TODO - what would Endorser's ACL be ?
-func (*Endorser) checkACL(prop *pb.Proposal) error {
+//checks that the creator of the proposal satisfies the policies of the chain
+//required for the chaincode invocation. Chainless proposals, such as joining
+//a chain, are not subject to the policies of a chain
+func (*Endorser) checkACL(signedProp *pb.SignedProposal, hdr *common.Header, cis *pb.ChaincodeInvocationSpec) error {
+	chainID := hdr.ChainHeader.ChainID
+	if chainID == "" {
+		return nil
+	}
+
+	ccName := cis.ChaincodeSpec.ChaincodeID.Name
+	var args [][]byte
+	if cis.ChaincodeSpec.CtorMsg != nil {
+		args = cis.ChaincodeSpec.CtorMsg.Args
+	}
+
+	policyNames := []string{writersPolicy}
+	if isQuery(ccName, args) {
+		policyNames = []string{readersPolicy}
+	} else if ccName == "lccc" && len(args) > 0 && (string(args[0]) == chaincode.DEPLOY || string(args[0]) == chaincode.UPGRADE) {
+		policyNames = append(policyNames, adminsPolicy)
+	}
+
+	signedData := []*common.SignedData{{
+		Data:      signedProp.ProposalBytes,
+		Identity:  hdr.SignatureHeader.Creator,
+		Signature: signedProp.Signature,
+	}}
+	for _, policyName := range policyNames {
+		if err := checkChainPolicy(chainID, policyName, signedData); err != nil {
+			return fmt.Errorf("access denied for chaincode %s on chain %s: the creator does not satisfy the %s policy (%s)", ccName, chainID, policyName, err)
+		}
+	}
 	return nil
 }
 
-//TODO - check for escc and vscc
-func (*Endorser) checkEsccAndVscc(prop *pb.Proposal) error {
+//isQuery returns true if the invocation only reads the ledger or the chaincodes registered with LCCC
+func isQuery(ccName string, args [][]byte) bool {
+	switch ccName {
+	case "qscc":
+		return true
+	case "lccc":
+		if len(args) == 0 {
+			return false
+		}
+		function := string(args[0])
+		return function == chaincode.GETCCINFO || function == chaincode.GETDEPSPEC || function == chaincode.GETCCDATA
+	default:
+		return false
+	}
+}
+
+//checkChainPolicy evaluates the signed data against the named policy of the
+//chain configuration. A chain whose configuration does not define the policy
+//grants read and write access to the members of its MSPs and admin access to
+//the admins of its MSPs
+func checkChainPolicy(chainID string, policyName string, signedData []*common.SignedData) error {
+	if policyManager := peer.GetPolicyManager(chainID); policyManager != nil {
+		if policy, ok := policyManager.GetPolicy(policyName); ok {
+			return policy.Evaluate(signedData)
+		}
+	}
+	endorserLogger.Debugf("Policy %s is not defined for chain %s, applying the default one", policyName, chainID)
+	role := common.MSPRole_Member
+	if policyName == adminsPolicy {
+		role = common.MSPRole_Admin
+	}
+
+	mspManager := mspmgmt.GetManagerForChain(chainID)
+	msps, err := mspManager.GetMSPs()
+	if err != nil {
+		return err
+	}
+	spe := &common.SignaturePolicyEnvelope{}
+	var principals []*common.SignaturePolicy
+	for mspID := range msps {
+		principal, err := proto.Marshal(&common.MSPRole{Role: role, MSPIdentifier: mspID})
+		if err != nil {
+			return err
+		}
+		spe.Identities = append(spe.Identities, &common.MSPPrincipal{PrincipalClassification: common.MSPPrincipal_ByMSPRole, Principal: principal})
+		principals = append(principals, cauthdsl.SignedBy(int32(len(principals))))
+	}
+	spe.Policy = cauthdsl.NOutOf(1, principals)
+	speBytes, err := proto.Marshal(spe)
+	if err != nil {
+		return err
+	}
+	policy, err := cauthdsl.NewPolicyProvider(mspManager).NewPolicy(speBytes)
+	if err != nil {
+		return err
+	}
+	return policy.Evaluate(signedData)
+}
+
+//check that the escc and vscc selected for the chaincode are registered on
+//this peer, as the endorser could neither endorse nor validate its transactions otherwise
+func (*Endorser) checkEsccAndVscc(cd *chaincode.ChaincodeData) error {
+	if cd.Escc != "" && !chaincode.IsRegisteredSysCC(cd.Escc, chaincode.EndorsementPlugin) {
+		return fmt.Errorf("escc %s of chaincode %s is not registered on this peer", cd.Escc, cd.Name)
+	}
+	if cd.Vscc != "" && !chaincode.IsRegisteredSysCC(cd.Vscc, chaincode.ValidationPlugin) {
+		return fmt.Errorf("vscc %s of chaincode %s is not registered on this peer", cd.Vscc, cd.Name)
+	}
 	return nil
 }
 
@@ -117,7 +226,7 @@ func (e *Endorser) callChaincode(ctxt context.Context, chainID string, version s
 	//
 	//NOTE that if there's an error all simulation, including the chaincode
 	//table changes in lccc will be thrown away
-	if cid.Name == "lccc" && len(cis.ChaincodeSpec.CtorMsg.Args) == 3 && (string(cis.ChaincodeSpec.CtorMsg.Args[0]) == "deploy" || string(cis.ChaincodeSpec.CtorMsg.Args[0]) == "upgrade") {
+	if cid.Name == "lccc" && len(cis.ChaincodeSpec.CtorMsg.Args) >= 3 && (string(cis.ChaincodeSpec.CtorMsg.Args[0]) == "deploy" || string(cis.ChaincodeSpec.CtorMsg.Args[0]) == "upgrade") {
 		var ccVersion string
 		switch string(cis.ChaincodeSpec.CtorMsg.Args[0]) {
 		case "deploy":
@@ -162,16 +271,6 @@ func (e *Endorser) simulateProposal(ctx context.Context, chainID string, txid st
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
-	//---1. check ACL
-	if err = e.checkACL(prop); err != nil {
-		return nil, nil, nil, nil, err
-	}
-
-	//---2. check ESCC and VSCC for the chaincode
-	if err = e.checkEsccAndVscc(prop); err != nil {
-		return nil, nil, nil, nil, err
-	}
-
 	var cd *chaincode.ChaincodeData
 
 	//default it to a system CC
@@ -182,9 +281,14 @@ func (e *Endorser) simulateProposal(ctx context.Context, chainID string, txid st
 			return nil, nil, nil, nil, fmt.Errorf("failed to obtain cds for %s - %s", cid.Name, err)
 		}
 		version = cd.Version
+
+		//---1. check ESCC and VSCC for the chaincode
+		if err = e.checkEsccAndVscc(cd); err != nil {
+			return nil, nil, nil, nil, err
+		}
 	}
 
-	//---3. execute the proposal and get simulation results
+	//---2. execute the proposal and get simulation results
 	var simResult []byte
 	var resp []byte
 	var ccevent *pb.ChaincodeEvent
@@ -215,21 +319,12 @@ func (e *Endorser) getCDSFromLCCC(ctx context.Context, chainID string, txid stri
 func (e *Endorser) endorseProposal(ctx context.Context, chainID string, txid string, proposal *pb.Proposal, simRes []byte, event *pb.ChaincodeEvent, visibility []byte, ccid *pb.ChaincodeID, txsim ledger.TxSimulator, cd *chaincode.ChaincodeData) (*pb.ProposalResponse, error) {
 	endorserLogger.Infof("endorseProposal starts for chainID %s, ccid %s", chainID, ccid)
 
-	// 1) extract the chaincodeDeploymentSpec for the chaincode we are invoking; we need it to get the escc
-	var escc string
+	// 1) determine the escc for the chaincode we are invoking from its chaincode data
+	escc := "escc"
 
-	//ie, not "lccc" or system chaincodes
-	if cd != nil {
-		_, err := putils.GetChaincodeDeploymentSpec(cd.DepSpec)
-		if err != nil {
-			return nil, fmt.Errorf("failed to unmarshal cds for %s - %s", ccid, err)
-		}
-
-		// FIXME: pick the right escc from cds - currently cds doesn't have this info
-		escc = "escc"
-	} else {
-		// FIXME: getCDSFromLCCC seems to fail for lccc - not sure this is expected?
-		escc = "escc"
+	//ie, not "lccc" or system chaincodes, which are endorsed by the default escc
+	if cd != nil && cd.Escc != "" {
+		escc = cd.Escc
 	}
 
 	endorserLogger.Infof("endorseProposal info: escc for cid %s is %s", ccid, escc)
@@ -306,6 +401,16 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
 	}
 
+	//check that the creator is granted access to the chaincode on the chain
+	cis, err := putils.GetChaincodeInvocationSpec(prop)
+	if err != nil {
+		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+	}
+	if err = e.checkACL(signedProp, hdr, cis); err != nil {
+		endorserLogger.Warningf("Rejecting proposal: %s", err)
+		return &pb.ProposalResponse{Response: &pb.Response{Status: int32(common.Status_FORBIDDEN), Message: err.Error()}}, nil
+	}
+
 	//TODO check for uniqueness of prop.TxID with ledger
 
 	txid := hdr.ChainHeader.TxID
@@ -374,6 +479,10 @@ func (e *Endorser) commitTxSimulation(proposal *pb.Proposal, chainID string, sig
 	if lgr == nil {
 		return fmt.Errorf("failure while looking up the ledger")
 	}
+	lc := peer.GetCommitter(chainID)
+	if lc == nil {
+		return fmt.Errorf("failure while looking up the committer")
+	}
 
 	txBytes, err := proto.Marshal(tx)
 	if err != nil {
@@ -381,7 +490,7 @@ func (e *Endorser) commitTxSimulation(proposal *pb.Proposal, chainID string, sig
 	}
 	block := common.NewBlock(1, []byte{})
 	block.Data.Data = [][]byte{txBytes}
-	block.Header.DataHash = block.Data.Hash()
+	block.Header.DataHash = block.Data.HashWith(lc.HashingAlgorithm())
 	if err = lgr.Commit(block); err != nil {
 		return err
 	}
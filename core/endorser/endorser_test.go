@@ -17,6 +17,7 @@ limitations under the License.
 package endorser
 
 import (
+	"encoding/json"
 	"fmt"
 	"net"
 	"os"
@@ -33,6 +34,7 @@ import (
 	"github.com/hyperledger/fabric/core/peer/msp"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/protos/common"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	pbutils "github.com/hyperledger/fabric/protos/utils"
 	"github.com/spf13/viper"
@@ -45,6 +47,8 @@ var endorserServer pb.EndorserServer
 var mspInstance msp.MSP
 var signer msp.SigningIdentity
 
+const mspMgrConfigDir = "../../msp/sampleconfig/"
+
 //initialize peer and start up. If security==enabled, login as vp
 func initPeer(chainID string) (net.Listener, error) {
 	//start clean
@@ -436,6 +440,85 @@ func TestDeployAndUpgrade(t *testing.T) {
 	chaincode.GetChain().Stop(ctxt, cccid2, &pb.ChaincodeDeploymentSpec{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeID: chaincodeID2}})
 }
 
+//setupChainMSP sets up the MSP manager of the chain with the sample MSP
+//configuration, granting the admin role to its signing identity if signerIsAdmin
+func setupChainMSP(chainID string, signerIsAdmin bool) error {
+	conf, err := msp.GetLocalMspConfig(mspMgrConfigDir)
+	if err != nil {
+		return err
+	}
+	if signerIsAdmin {
+		fabricConf := &mspprotos.FabricMSPConfig{}
+		if err = json.Unmarshal(conf.Config, fabricConf); err != nil {
+			return err
+		}
+		fabricConf.Admins = append(fabricConf.Admins, fabricConf.SigningIdentity.PublicSigner)
+		if conf.Config, err = json.Marshal(fabricConf); err != nil {
+			return err
+		}
+	}
+
+	mspManager := msp.NewMSPManager()
+	if err = mspManager.Setup([]*mspprotos.MSPConfig{conf}); err != nil {
+		return err
+	}
+	mspmgmt.SetManagerForChain(chainID, mspManager)
+	return nil
+}
+
+func TestCheckACL(t *testing.T) {
+	//a chain with the sample MSP, of which the signer is a member but not an admin
+	chainID := "checkaclchain"
+	if err := setupChainMSP(chainID, false); err != nil {
+		t.Fatalf("Setup the msp of the chain failed: %s", err)
+	}
+	creator, err := signer.Serialize()
+	if err != nil {
+		t.Fatalf("Serialize the creator failed: %s", err)
+	}
+
+	checkACL := func(prop *pb.Proposal) error {
+		signedProp, err := getSignedProposal(prop, signer)
+		if err != nil {
+			t.Fatalf("Sign the proposal failed: %s", err)
+		}
+		hdr, err := pbutils.GetHeader(prop.Header)
+		if err != nil {
+			t.Fatalf("Get the proposal header failed: %s", err)
+		}
+		cis, err := pbutils.GetChaincodeInvocationSpec(prop)
+		if err != nil {
+			t.Fatalf("Get the invocation spec failed: %s", err)
+		}
+		return endorserServer.(*Endorser).checkACL(signedProp, hdr, cis)
+	}
+
+	//the chain does not define policies, so the members of its MSP can read and write...
+	for _, ccName := range []string{"qscc", "ex01"} {
+		cis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{Type: pb.ChaincodeSpec_GOLANG, ChaincodeID: &pb.ChaincodeID{Name: ccName}, CtorMsg: &pb.ChaincodeInput{Args: [][]byte{[]byte("query"), []byte("a")}}}}
+		prop, err := getInvokeProposal(cis, chainID, creator)
+		if err != nil {
+			t.Fatalf("Get the invoke proposal failed: %s", err)
+		}
+		if err = checkACL(prop); err != nil {
+			t.Fatalf("Invoking %s should have been allowed: %s", ccName, err)
+		}
+	}
+
+	//...but deploying and upgrading chaincodes requires an admin of its MSP
+	spec := &pb.ChaincodeSpec{Type: 1, ChaincodeID: &pb.ChaincodeID{Name: "ex01", Path: "github.com/hyperledger/fabric/examples/chaincode/go/chaincode_example01"}, CtorMsg: &pb.ChaincodeInput{Args: [][]byte{[]byte("init")}}}
+	cds := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: spec}
+	for _, upgrade := range []bool{false, true} {
+		prop, err := getDeployOrUpgradeProposal(cds, chainID, creator, upgrade)
+		if err != nil {
+			t.Fatalf("Get the deploy proposal failed: %s", err)
+		}
+		if err = checkACL(prop); err == nil {
+			t.Fatalf("Deploying with a creator that is not an admin should have been denied")
+		}
+	}
+}
+
 func TestMain(m *testing.M) {
 	SetupTestConfig()
 	viper.Set("peer.fileSystemPath", filepath.Join(os.TempDir(), "hyperledger", "production"))
@@ -452,7 +535,6 @@ func TestMain(m *testing.M) {
 	endorserServer = NewEndorserServer()
 
 	// setup the MSP manager so that we can sign/verify
-	mspMgrConfigDir := "../../msp/sampleconfig/"
 	err = mspmgmt.LoadFakeSetupWithLocalMspAndTestChainMsp(mspMgrConfigDir)
 	if err != nil {
 		fmt.Printf("Could not initialize msp/signer, err %s", err)
@@ -460,6 +542,15 @@ func TestMain(m *testing.M) {
 		finitPeer(lis)
 		return
 	}
+	// the test chain defines no policies, so deploying and upgrading chaincodes
+	// on it require an admin of its MSP: grant the admin role to the signer
+	err = setupChainMSP(chainID, true)
+	if err != nil {
+		fmt.Printf("Could not initialize the msp of the test chain, err %s", err)
+		os.Exit(-1)
+		finitPeer(lis)
+		return
+	}
 	signer, err = mspmgmt.GetLocalMSP().GetDefaultSigningIdentity()
 	if err != nil {
 		fmt.Printf("Could not initialize msp/signer")
@@ -30,10 +30,11 @@ type IndexableAttr string
 
 // constants for indexable attributes
 const (
-	IndexableAttrBlockNum        = IndexableAttr("BlockNum")
-	IndexableAttrBlockHash       = IndexableAttr("BlockHash")
-	IndexableAttrTxID            = IndexableAttr("TxID")
-	IndexableAttrBlockNumTranNum = IndexableAttr("BlockNumTranNum")
+	IndexableAttrBlockNum         = IndexableAttr("BlockNum")
+	IndexableAttrBlockHash        = IndexableAttr("BlockHash")
+	IndexableAttrTxID             = IndexableAttr("TxID")
+	IndexableAttrBlockNumTranNum  = IndexableAttr("BlockNumTranNum")
+	IndexableAttrTxValidationCode = IndexableAttr("TxValidationCode")
 )
 
 // IndexConfig - a configuration that includes a list of attributes that should be indexed
@@ -46,12 +47,15 @@ var (
 	ErrNotFoundInIndex = errors.New("Entry not found in index")
 	// ErrAttrNotIndexed is used to indicate that an attribute is not indexed
 	ErrAttrNotIndexed = errors.New("Attribute not indexed")
+	// ErrPruned is used to indicate that the requested block or transaction has been pruned from the block store
+	ErrPruned = errors.New("Block has been pruned")
 )
 
 // BlockStoreProvider provides an handle to a BlockStore
 type BlockStoreProvider interface {
 	CreateBlockStore(ledgerid string) (BlockStore, error)
 	OpenBlockStore(ledgerid string) (BlockStore, error)
+	OpenBlockStoreReadOnly(ledgerid string) (BlockStore, error)
 	Exists(ledgerid string) (bool, error)
 	List() ([]string, error)
 	Close()
@@ -67,5 +71,12 @@ type BlockStore interface {
 	RetrieveBlockByHash(blockHash []byte) (*common.Block, error)
 	RetrieveBlockByNumber(blockNum uint64) (*common.Block, error) // blockNum of  math.MaxUint64 will return last block
 	RetrieveTxByID(txID string) (*pb.Transaction, error)
+	RetrieveTxByBlockNumTranNum(blockNum uint64, tranNum uint64) (*pb.Transaction, error)
+	RetrieveTxValidationCodeByTxID(txID string) (pb.TxValidationCode, error)
+	RetrieveConfigBlock() (*common.Block, error)
+	Prune(policy ledger.PrunePolicy) error
+	Bootstrap(bcInfo *pb.BlockchainInfo, configBlock *common.Block) error
+	Verify() (uint64, error)
+	RebuildIndex() error
 	Shutdown()
 }
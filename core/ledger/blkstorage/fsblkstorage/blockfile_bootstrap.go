@@ -0,0 +1,115 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsblkstorage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+var (
+	blkMgrBootstrapInfoKey = []byte("blkMgrBootstrapInfo")
+	// errNotEmpty is returned when an attempt is made to bootstrap a block storage that already holds blocks
+	errNotEmpty = errors.New("Block storage is not empty")
+)
+
+/*
+bootstrap initializes an empty block storage for continuing the chain described by the given blockchain
+info, i.e., the next block added to the storage is expected to be the block with number `bcInfo.Height+1`.
+The blocks up to `bcInfo.Height` are never available in the storage and are treated as pruned.
+
+The blockchain info is persisted along with the checkpoint info and the prune info in a single batch, and
+is used for serving `getBlockchainInfo` until the first block is added. The given config block, if any, is
+persisted in the same batch as the latest valid config block of the chain. Bootstrapping a storage again with
+the same info, before any block is added, is a no-op, so that an interrupted bootstrap can be retried.
+*/
+func (mgr *blockfileMgr) bootstrap(bcInfo *pb.BlockchainInfo, configBlock *common.Block) error {
+	if mgr.readOnly {
+		return errReadOnly
+	}
+	existingBCInfo, err := mgr.loadBootstrapInfo()
+	if err != nil {
+		return err
+	}
+	if existingBCInfo != nil && proto.Equal(existingBCInfo, bcInfo) &&
+		mgr.cpInfo.lastBlockNumber == bcInfo.Height && mgr.cpInfo.latestFileChunksize == 0 {
+		logger.Debugf("Block storage is already bootstrapped with blockchain info [%s]", bcInfo)
+		return nil
+	}
+	if existingBCInfo != nil || mgr.cpInfo.lastBlockNumber != 0 || mgr.cpInfo.latestFileChunkSuffixNum != 0 ||
+		mgr.cpInfo.latestFileChunksize != 0 {
+		return errNotEmpty
+	}
+
+	cpInfo := &checkpointInfo{latestFileChunkSuffixNum: 0, latestFileChunksize: 0, lastBlockNumber: bcInfo.Height}
+	prunedInfo := &pruneInfo{firstFileSuffixNum: 0, firstBlockNum: bcInfo.Height + 1}
+	batch := leveldbhelper.NewUpdateBatch()
+	bcInfoBytes, err := proto.Marshal(bcInfo)
+	if err != nil {
+		return err
+	}
+	batch.Put(blkMgrBootstrapInfoKey, bcInfoBytes)
+	cpInfoBytes, err := cpInfo.marshal()
+	if err != nil {
+		return err
+	}
+	batch.Put(blkMgrInfoKey, cpInfoBytes)
+	prunedInfoBytes, err := prunedInfo.marshal()
+	if err != nil {
+		return err
+	}
+	batch.Put(blkMgrPruneInfoKey, prunedInfoBytes)
+	hashingAlgorithm, err := hashingAlgorithmOf(configBlock)
+	if err != nil {
+		return fmt.Errorf("Error while reading the hashing algorithm of config block: %s", err)
+	}
+	if configBlock != nil {
+		configBlockBytes, err := proto.Marshal(configBlock)
+		if err != nil {
+			return err
+		}
+		batch.Put(configBlockKey, configBlockBytes)
+	}
+	if err = mgr.db.WriteBatch(batch, true); err != nil {
+		return fmt.Errorf("Error while saving bootstrap info to db: %s", err)
+	}
+
+	logger.Infof("Bootstrapped block storage at height [%d]", bcInfo.Height)
+	mgr.prunedInfo.Store(prunedInfo)
+	mgr.bcInfo.Store(bcInfo)
+	mgr.hashingAlgorithm = hashingAlgorithm
+	mgr.updateCheckpoint(cpInfo)
+	return nil
+}
+
+// loadBootstrapInfo returns the blockchain info with which the storage was bootstrapped, nil if it was not
+func (mgr *blockfileMgr) loadBootstrapInfo() (*pb.BlockchainInfo, error) {
+	b, err := mgr.db.Get(blkMgrBootstrapInfoKey)
+	if b == nil || err != nil {
+		return nil, err
+	}
+	bcInfo := &pb.BlockchainInfo{}
+	if err = proto.Unmarshal(b, bcInfo); err != nil {
+		return nil, err
+	}
+	return bcInfo, nil
+}
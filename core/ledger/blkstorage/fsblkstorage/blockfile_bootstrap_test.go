@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsblkstorage
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/blkstorage"
+	"github.com/hyperledger/fabric/core/ledger/testutil"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestBlockfileMgrBootstrap(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath, 0))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blocks := testutil.ConstructTestBlocks(t, 7)
+	bcInfo := &pb.BlockchainInfo{
+		Height:            5,
+		CurrentBlockHash:  blocks[4].Header.Hash(),
+		PreviousBlockHash: blocks[4].Header.PreviousHash}
+
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+	err := blkfileMgrWrapper.blockfileMgr.bootstrap(bcInfo, nil)
+	testutil.AssertNoError(t, err, "Error while bootstrapping")
+	testutil.AssertEquals(t, blkfileMgrWrapper.blockfileMgr.getBlockchainInfo(), bcInfo)
+	// bootstrapping again with the same info is a no-op
+	err = blkfileMgrWrapper.blockfileMgr.bootstrap(bcInfo, nil)
+	testutil.AssertNoError(t, err, "Error while bootstrapping")
+	blkfileMgrWrapper.close()
+
+	// the bootstrap info survives a restart
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	testutil.AssertEquals(t, blkfileMgrWrapper.blockfileMgr.getBlockchainInfo(), bcInfo)
+	// the blocks preceding the bootstrap point were never added, so only lookups by number are answered as pruned
+	for _, block := range blocks[:5] {
+		_, err = blkfileMgrWrapper.blockfileMgr.retrieveBlockByNumber(block.Header.Number)
+		testutil.AssertSame(t, err, blkstorage.ErrPruned)
+	}
+	itr, err := blkfileMgrWrapper.blockfileMgr.retrieveBlocks(5)
+	testutil.AssertNoError(t, err, "")
+	_, err = itr.Next()
+	testutil.AssertSame(t, err, blkstorage.ErrPruned)
+	itr.Close()
+
+	blkfileMgrWrapper.addBlocks(blocks[5:])
+	testRetainedBlocks(blkfileMgrWrapper, blocks[5:])
+	testutil.AssertEquals(t, blkfileMgrWrapper.blockfileMgr.getBlockchainInfo(), &pb.BlockchainInfo{
+		Height:            7,
+		CurrentBlockHash:  blocks[6].Header.Hash(),
+		PreviousBlockHash: blocks[6].Header.PreviousHash})
+
+	err = blkfileMgrWrapper.blockfileMgr.bootstrap(bcInfo, nil)
+	testutil.AssertSame(t, err, errNotEmpty)
+}
+
+func TestBlockfileMgrBootstrapNonEmpty(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath, 0))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	blocks := testutil.ConstructTestBlocks(t, 2)
+	blkfileMgrWrapper.addBlocks(blocks)
+	err := blkfileMgrWrapper.blockfileMgr.bootstrap(&pb.BlockchainInfo{Height: 5}, nil)
+	testutil.AssertSame(t, err, errNotEmpty)
+	testRetainedBlocks(blkfileMgrWrapper, blocks)
+}
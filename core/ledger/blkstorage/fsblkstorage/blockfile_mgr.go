@@ -17,12 +17,14 @@ limitations under the License.
 package fsblkstorage
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"sync"
 	"sync/atomic"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/chainconfig"
 	"github.com/hyperledger/fabric/core/ledger/blkstorage"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/core/ledger/util/leveldbhelper"
@@ -41,6 +43,8 @@ const (
 
 var (
 	blkMgrInfoKey = []byte("blkMgrInfo")
+	// errReadOnly is returned when an attempt is made to write to a block storage opened read-only
+	errReadOnly = errors.New("Block storage is opened read-only")
 )
 
 type conf struct {
@@ -57,6 +61,10 @@ type blockfileMgr struct {
 	cpInfoCond        *sync.Cond
 	currentFileWriter *blockfileWriter
 	bcInfo            atomic.Value
+	prunedInfo        atomic.Value
+	pruneLock         sync.Mutex
+	hashingAlgorithm  func(input []byte) []byte
+	readOnly          bool
 }
 
 /*
@@ -99,16 +107,23 @@ At start up a new manager:
 		-- syncIndex comparing the last block indexed to what is in the FS
 		-- If index and file system are not in sync, syncs index from the FS
   *)  Updates blockchain info used by the APIs
+
+A manager started read-only, for inspecting the storage, performs none of the
+writes above: the checkpoint info synced from the FS is only kept in memory, no
+file writer is opened, the index is not synced and the blockchain info reflects
+the blocks indexed. Adding, pruning and bootstrapping fail on such a manager
 */
-func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig, indexStore *leveldbhelper.DBHandle) *blockfileMgr {
+func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig, indexStore *leveldbhelper.DBHandle, readOnly bool) *blockfileMgr {
 	//Determine the root directory for the blockfile storage, if it does not exist create it
 	rootDir := conf.getLedgerBlockDir(id)
-	_, err := util.CreateDirIfMissing(rootDir)
-	if err != nil {
-		panic(fmt.Sprintf("Error: %s", err))
+	if !readOnly {
+		_, err := util.CreateDirIfMissing(rootDir)
+		if err != nil {
+			panic(fmt.Sprintf("Error: %s", err))
+		}
 	}
 	// Instantiate the manager, i.e. blockFileMgr structure
-	mgr := &blockfileMgr{rootDir: rootDir, conf: conf, db: indexStore}
+	mgr := &blockfileMgr{rootDir: rootDir, conf: conf, db: indexStore, readOnly: readOnly}
 
 	// cp = checkpointInfo, retrieve from the database the file suffix or number of where blocks were stored.
 	// It also retrieves the current size of that file and the last block number that was written to that file.
@@ -119,39 +134,75 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 	}
 	if cpInfo == nil { //if no cpInfo stored in db initiate to zero
 		cpInfo = &checkpointInfo{latestFileChunkSuffixNum: 0, latestFileChunksize: 0}
-		err = mgr.saveCurrentInfo(cpInfo, true)
-		if err != nil {
-			panic(fmt.Sprintf("Could not save next block file info to db: %s", err))
+		if !readOnly {
+			err = mgr.saveCurrentInfo(cpInfo, true)
+			if err != nil {
+				panic(fmt.Sprintf("Could not save next block file info to db: %s", err))
+			}
 		}
 	}
+	//Load the info about the pruned block files and remove the files that may have been left behind
+	//by a crash during pruning
+	prunedInfo, err := mgr.loadPruneInfo()
+	if err != nil {
+		panic(fmt.Sprintf("Could not get prune info from db: %s", err))
+	}
+	if !readOnly {
+		if err = removeBlockfiles(rootDir, 0, prunedInfo.firstFileSuffixNum); err != nil {
+			panic(fmt.Sprintf("Could not remove pruned block files: %s", err))
+		}
+	}
+	mgr.prunedInfo.Store(prunedInfo)
 	//Verify that the checkpoint stored in db is accurate with what is actually stored in block file system
 	// If not the same, sync the cpInfo and the file system
 	syncCPInfoFromFS(rootDir, cpInfo)
-	//Open a writer to the file identified by the number and truncate it to only contain the latest block
-	// that was completely saved (file system, index, cpinfo, etc)
-	currentFileWriter, err := newBlockfileWriter(deriveBlockfilePath(rootDir, cpInfo.latestFileChunkSuffixNum))
-	if err != nil {
-		panic(fmt.Sprintf("Could not open writer to current file: %s", err))
-	}
-	//Truncate the file to remove excess past last block
-	err = currentFileWriter.truncateFile(cpInfo.latestFileChunksize)
-	if err != nil {
-		panic(fmt.Sprintf("Could not truncate current file to known size in db: %s", err))
+	if !readOnly {
+		//Open a writer to the file identified by the number and truncate it to only contain the latest block
+		// that was completely saved (file system, index, cpinfo, etc)
+		mgr.currentFileWriter, err = newBlockfileWriter(deriveBlockfilePath(rootDir, cpInfo.latestFileChunkSuffixNum))
+		if err != nil {
+			panic(fmt.Sprintf("Could not open writer to current file: %s", err))
+		}
+		//Truncate the file to remove excess past last block
+		err = mgr.currentFileWriter.truncateFile(cpInfo.latestFileChunksize)
+		if err != nil {
+			panic(fmt.Sprintf("Could not truncate current file to known size in db: %s", err))
+		}
 	}
 
 	// Create a new KeyValue store database handler for the blocks index in the keyvalue database
 	mgr.index = newBlockIndex(indexConfig, indexStore)
 
-	// Update the manager with the checkpoint info and the file writer
+	// Update the manager with the checkpoint info
 	mgr.cpInfo = cpInfo
-	mgr.currentFileWriter = currentFileWriter
 	// Create a checkpoint condition (event) variable, for the  goroutine waiting for
 	// or announcing the occurrence of an event.
 	mgr.cpInfoCond = sync.NewCond(&sync.Mutex{})
 
+	// Hash the blocks with the hashing algorithm configured by the latest config block indexed
+	configBlock, err := mgr.index.getConfigBlock()
+	if err != nil && err != blkstorage.ErrNotFoundInIndex {
+		panic(fmt.Sprintf("Could not retrieve the config block from db: %s", err))
+	}
+	if mgr.hashingAlgorithm, err = hashingAlgorithmOf(configBlock); err != nil {
+		panic(fmt.Sprintf("Could not determine the hashing algorithm: %s", err))
+	}
+
 	// Verify that the index stored in db is accurate with what is actually stored in block file system
 	// If not the same, sync the index and the file system
-	mgr.syncIndex()
+	lastBlockNumber := cpInfo.lastBlockNumber
+	if !readOnly {
+		mgr.syncIndex()
+	} else {
+		//The blocks that have not been indexed yet cannot be retrieved
+		lastBlockIndexed, err := mgr.index.getLastBlockIndexed()
+		if err != nil {
+			panic(fmt.Sprintf("Could not get the last block indexed from db: %s", err))
+		}
+		if lastBlockIndexed < lastBlockNumber && !mgr.isBlockPruned(lastBlockNumber) {
+			lastBlockNumber = lastBlockIndexed
+		}
+	}
 
 	// init BlockchainInfo for external API's
 	bcInfo := &pb.BlockchainInfo{
@@ -160,15 +211,20 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 		PreviousBlockHash: nil}
 
 	//If start up is a restart of an existing storage, update BlockchainInfo for external API's
-	if cpInfo.lastBlockNumber > 0 {
-		lastBlockHeader, err := mgr.retrieveBlockHeaderByNumber(cpInfo.lastBlockNumber)
+	if lastBlockNumber > 0 && mgr.isBlockPruned(lastBlockNumber) {
+		//No block has been added since the storage was bootstrapped
+		if bcInfo, err = mgr.loadBootstrapInfo(); err != nil {
+			panic(fmt.Sprintf("Could not load the bootstrap info from db: %s", err))
+		}
+	} else if lastBlockNumber > 0 {
+		lastBlockHeader, err := mgr.retrieveBlockHeaderByNumber(lastBlockNumber)
 		if err != nil {
 			panic(fmt.Sprintf("Could not retrieve header of the last block form file: %s", err))
 		}
-		lastBlockHash := lastBlockHeader.Hash()
+		lastBlockHash := lastBlockHeader.HashWith(mgr.hashingAlgorithm)
 		previousBlockHash := lastBlockHeader.PreviousHash
 		bcInfo = &pb.BlockchainInfo{
-			Height:            cpInfo.lastBlockNumber,
+			Height:            lastBlockNumber,
 			CurrentBlockHash:  lastBlockHash,
 			PreviousBlockHash: previousBlockHash}
 	}
@@ -214,11 +270,16 @@ func deriveBlockfilePath(rootDir string, suffixNum int) string {
 }
 
 func (mgr *blockfileMgr) open() error {
+	if mgr.readOnly {
+		return errReadOnly
+	}
 	return mgr.currentFileWriter.open()
 }
 
 func (mgr *blockfileMgr) close() {
-	mgr.currentFileWriter.close()
+	if mgr.currentFileWriter != nil {
+		mgr.currentFileWriter.close()
+	}
 }
 
 func (mgr *blockfileMgr) moveToNextFile() {
@@ -243,11 +304,25 @@ func (mgr *blockfileMgr) moveToNextFile() {
 }
 
 func (mgr *blockfileMgr) addBlock(block *common.Block) error {
+	if mgr.readOnly {
+		return errReadOnly
+	}
 	blockBytes, info, err := serializeBlock(block)
 	if err != nil {
 		return fmt.Errorf("Error while serializing block: %s", err)
 	}
-	blockHash := block.Header.Hash()
+	configBlockBytes, err := validConfigBlockBytes(block)
+	if err != nil {
+		return fmt.Errorf("Error while serializing config block: %s", err)
+	}
+	//A config block sets the hashing algorithm of the chain from this block on
+	hashingAlgorithm := mgr.hashingAlgorithm
+	if configBlockBytes != nil {
+		if hashingAlgorithm, err = hashingAlgorithmOf(block); err != nil {
+			return fmt.Errorf("Error while reading the hashing algorithm of config block: %s", err)
+		}
+	}
+	blockHash := block.Header.HashWith(hashingAlgorithm)
 	//Get the location / offset where each transaction starts in the block and where the block ends
 	txOffsets := info.txOffsets
 	currentOffset := mgr.cpInfo.latestFileChunksize
@@ -303,9 +378,10 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 	//save the index in the database
 	mgr.index.indexBlock(&blockIdxInfo{
 		blockNum: block.Header.Number, blockHash: blockHash,
-		flp: blockFLP, txOffsets: txOffsets})
+		flp: blockFLP, txOffsets: txOffsets, metadata: block.Metadata, configBlock: configBlockBytes})
 
 	//update the checkpoint info (for storage) and the blockchain info (for APIs) in the manager
+	mgr.hashingAlgorithm = hashingAlgorithm
 	mgr.updateCheckpoint(newCPInfo)
 	mgr.updateBlockchainInfo(blockHash, block)
 	return nil
@@ -318,8 +394,8 @@ func (mgr *blockfileMgr) syncIndex() error {
 	if lastBlockIndexed, err = mgr.index.getLastBlockIndexed(); err != nil {
 		return err
 	}
-	//initialize index to file number:zero, offset:zero and block:1
-	startFileNum := 0
+	//initialize index to the first retained file number, offset:zero and block:1
+	startFileNum := mgr.getPruneInfo().firstFileSuffixNum
 	startOffset := 0
 	blockNum := uint64(1)
 	//get the last file that blocks were added to using the checkpoint info
@@ -356,16 +432,35 @@ func (mgr *blockfileMgr) syncIndex() error {
 		if err != nil {
 			return err
 		}
+		// shift the txoffsets relative to the start of the block (as done in addBlock) because
+		// the block bytes are preceded by their length
 		for _, offset := range info.txOffsets {
-			offset.loc.offset += int(blockPlacementInfo.blockBytesOffset)
+			offset.loc.offset += int(blockPlacementInfo.blockBytesOffset - blockPlacementInfo.blockStartOffset)
 		}
 		//Update the blockIndexInfo with what was actually stored in file system
 		blockIdxInfo := &blockIdxInfo{}
-		blockIdxInfo.blockHash = info.blockHeader.Hash()
+		// only a block carrying a single transaction can be a config block, which sets
+		// the hashing algorithm of the chain from this block on
+		if len(info.txOffsets) == 1 {
+			block, err := deserializeBlock(blockBytes)
+			if err != nil {
+				return err
+			}
+			if blockIdxInfo.configBlock, err = validConfigBlockBytes(block); err != nil {
+				return err
+			}
+			if blockIdxInfo.configBlock != nil {
+				if mgr.hashingAlgorithm, err = hashingAlgorithmOf(block); err != nil {
+					return err
+				}
+			}
+		}
+		blockIdxInfo.blockHash = info.blockHeader.HashWith(mgr.hashingAlgorithm)
 		blockIdxInfo.blockNum = info.blockHeader.Number
 		blockIdxInfo.flp = &fileLocPointer{fileSuffixNum: blockPlacementInfo.fileNum,
 			locPointer: locPointer{offset: int(blockPlacementInfo.blockStartOffset)}}
 		blockIdxInfo.txOffsets = info.txOffsets
+		blockIdxInfo.metadata = info.metadata
 		if err = mgr.index.indexBlock(blockIdxInfo); err != nil {
 			return err
 		}
@@ -374,6 +469,27 @@ func (mgr *blockfileMgr) syncIndex() error {
 	return nil
 }
 
+// validConfigBlockBytes returns the bytes of the block if it is a config block whose transaction
+// has not been marked invalid, and nil otherwise
+func validConfigBlockBytes(block *common.Block) ([]byte, error) {
+	if !putil.IsConfigBlock(block) {
+		return nil, nil
+	}
+	if util.GetTxValidationFlags(block.Metadata).IsInvalid(0) {
+		return nil, nil
+	}
+	return proto.Marshal(block)
+}
+
+// hashingAlgorithmOf returns the hashing algorithm configured by the given config block, or the
+// default hashing algorithm if no config block is given
+func hashingAlgorithmOf(configBlock *common.Block) (func(input []byte) []byte, error) {
+	if configBlock == nil {
+		return chainconfig.HashFunction(chainconfig.DefaultHashingAlgorithm)
+	}
+	return chainconfig.GetHashingAlgorithmFromBlock(configBlock)
+}
+
 func (mgr *blockfileMgr) getBlockchainInfo() *pb.BlockchainInfo {
 	return mgr.bcInfo.Load().(*pb.BlockchainInfo)
 }
@@ -412,6 +528,9 @@ func (mgr *blockfileMgr) retrieveBlockByNumber(blockNum uint64) (*common.Block,
 	if blockNum == math.MaxUint64 {
 		blockNum = mgr.getBlockchainInfo().Height
 	}
+	if mgr.isBlockPruned(blockNum) {
+		return nil, blkstorage.ErrPruned
+	}
 
 	loc, err := mgr.index.getBlockLocByBlockNum(blockNum)
 	if err != nil {
@@ -422,6 +541,9 @@ func (mgr *blockfileMgr) retrieveBlockByNumber(blockNum uint64) (*common.Block,
 
 func (mgr *blockfileMgr) retrieveBlockHeaderByNumber(blockNum uint64) (*common.BlockHeader, error) {
 	logger.Debugf("retrieveBlockHeaderByNumber() - blockNum = [%d]", blockNum)
+	if mgr.isBlockPruned(blockNum) {
+		return nil, blkstorage.ErrPruned
+	}
 	loc, err := mgr.index.getBlockLocByBlockNum(blockNum)
 	if err != nil {
 		return nil, err
@@ -450,8 +572,21 @@ func (mgr *blockfileMgr) retrieveTransactionByID(txID string) (*pb.Transaction,
 	return mgr.fetchTransaction(loc)
 }
 
+func (mgr *blockfileMgr) retrieveTxValidationCodeByTxID(txID string) (pb.TxValidationCode, error) {
+	logger.Debugf("retrieveTxValidationCodeByTxID() - txId = [%s]", txID)
+	return mgr.index.getTxValidationCodeByTxID(txID)
+}
+
+func (mgr *blockfileMgr) retrieveConfigBlock() (*common.Block, error) {
+	logger.Debugf("retrieveConfigBlock()")
+	return mgr.index.getConfigBlock()
+}
+
 func (mgr *blockfileMgr) retrieveTransactionForBlockNumTranNum(blockNum uint64, tranNum uint64) (*pb.Transaction, error) {
 	logger.Debugf("retrieveTransactionForBlockNumTranNum() - blockNum = [%d], tranNum = [%d]", blockNum, tranNum)
+	if mgr.isBlockPruned(blockNum) {
+		return nil, blkstorage.ErrPruned
+	}
 	loc, err := mgr.index.getTXLocForBlockNumTranNum(blockNum, tranNum)
 	if err != nil {
 		return nil, err
@@ -482,6 +617,9 @@ func (mgr *blockfileMgr) fetchTransaction(lp *fileLocPointer) (*pb.Transaction,
 }
 
 func (mgr *blockfileMgr) fetchBlockBytes(lp *fileLocPointer) ([]byte, error) {
+	if mgr.isPruned(lp) {
+		return nil, blkstorage.ErrPruned
+	}
 	stream, err := newBlockfileStream(mgr.rootDir, lp.fileSuffixNum, int64(lp.offset))
 	if err != nil {
 		return nil, err
@@ -495,6 +633,9 @@ func (mgr *blockfileMgr) fetchBlockBytes(lp *fileLocPointer) ([]byte, error) {
 }
 
 func (mgr *blockfileMgr) fetchRawBytes(lp *fileLocPointer) ([]byte, error) {
+	if mgr.isPruned(lp) {
+		return nil, blkstorage.ErrPruned
+	}
 	filePath := deriveBlockfilePath(mgr.rootDir, lp.fileSuffixNum)
 	reader, err := newBlockfileReader(filePath)
 	if err != nil {
@@ -21,10 +21,14 @@ import (
 	"testing"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/chainconfig"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/genesis"
 	"github.com/hyperledger/fabric/core/ledger/testutil"
 
 	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
 )
 
 func TestBlockfileMgrBlockReadWrite(t *testing.T) {
@@ -197,3 +201,80 @@ func TestBlockfileMgrFileRolling(t *testing.T) {
 	testutil.AssertEquals(t, blkfileMgrWrapper.blockfileMgr.cpInfo.latestFileChunkSuffixNum, 2)
 	blkfileMgrWrapper.testGetBlockByHash(blocks)
 }
+
+func TestBlockfileMgrHashingAlgorithm(t *testing.T) {
+	env := newTestEnv(t, NewConf("/tmp/fabric/ledgertests", 0))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+
+	// the first block is a config block that sets the SHA256 hashing algorithm for the chain
+	hashingAlgorithmItem := putils.MakeConfigurationItem(nil, common.ConfigurationItem_Chain, 0, configtx.DefaultModificationPolicyID,
+		chainconfig.HashingAlgorithmKey, putils.MarshalOrPanic(&common.HashingAlgorithm{Name: chainconfig.SHA256}))
+	genesisBlock, err := genesis.NewFactoryImpl(configtx.NewSimpleTemplate(hashingAlgorithmItem)).Block("testchain")
+	testutil.AssertNoError(t, err, "")
+	sha256, err := chainconfig.HashFunction(chainconfig.SHA256)
+	testutil.AssertNoError(t, err, "")
+	blocks := testutil.ConstructTestBlocks(t, 5)
+	blocks[0].Data = genesisBlock.Data
+	blocks[0].Header.DataHash = blocks[0].Data.HashWith(sha256)
+	for i := 1; i < len(blocks); i++ {
+		blocks[i].Header.PreviousHash = blocks[i-1].Header.HashWith(sha256)
+	}
+	blkfileMgrWrapper.addBlocks(blocks)
+
+	checkHashes := func() {
+		for _, block := range blocks {
+			b, err := blkfileMgrWrapper.blockfileMgr.retrieveBlockByHash(block.Header.HashWith(sha256))
+			testutil.AssertNoError(t, err, fmt.Sprintf("Error while retrieving block %d by its SHA256 hash", block.Header.Number))
+			testutil.AssertEquals(t, b, block)
+		}
+		bcInfo := blkfileMgrWrapper.blockfileMgr.getBlockchainInfo()
+		testutil.AssertEquals(t, bcInfo.CurrentBlockHash, blocks[4].Header.HashWith(sha256))
+		numBlocks, err := blkfileMgrWrapper.blockfileMgr.verify()
+		testutil.AssertNoError(t, err, "Error while verifying the block store")
+		testutil.AssertEquals(t, numBlocks, uint64(5))
+	}
+	checkHashes()
+
+	// the hashing algorithm is restored from the config block on restart and when the index is rebuilt
+	blkfileMgrWrapper.close()
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	checkHashes()
+	testutil.AssertNoError(t, blkfileMgrWrapper.blockfileMgr.rebuildIndex(), "Error while rebuilding the index")
+	checkHashes()
+}
+
+func TestBlockfileMgrReadOnly(t *testing.T) {
+	env := newTestEnv(t, NewConf("/tmp/fabric/ledgertests", 0))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+	blocks := testutil.ConstructTestBlocks(t, 5)
+	blkfileMgrWrapper.addBlocks(blocks)
+	// the index lags behind the block files by a block, as after a crash
+	err := blkfileMgrWrapper.blockfileMgr.db.Put(indexCheckpointKey, encodeBlockNum(4), true)
+	testutil.AssertNoError(t, err, "")
+	blkfileMgrWrapper.close()
+
+	// a read-only block store reports the blocks indexed and leaves the index behind
+	blkStore, err := env.provider.OpenBlockStoreReadOnly(ledgerid)
+	testutil.AssertNoError(t, err, "")
+	bcInfo, err := blkStore.GetBlockchainInfo()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, bcInfo.Height, uint64(4))
+	_, err = blkStore.Verify()
+	testutil.AssertError(t, err, "Expected the index to be found behind the block files")
+	testutil.AssertSame(t, blkStore.AddBlock(testutil.ConstructTestBlock(t, 1, 10)), errReadOnly)
+	testutil.AssertSame(t, blkStore.RebuildIndex(), errReadOnly)
+	blkStore.Shutdown()
+
+	// the index is synced once the block store is opened by the peer
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	lastBlockIndexed, err := blkfileMgrWrapper.blockfileMgr.index.getLastBlockIndexed()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, lastBlockIndexed, uint64(5))
+	testutil.AssertEquals(t, blkfileMgrWrapper.blockfileMgr.getBlockchainInfo().Height, uint64(5))
+}
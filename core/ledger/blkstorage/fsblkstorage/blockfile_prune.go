@@ -0,0 +1,240 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsblkstorage
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+	putil "github.com/hyperledger/fabric/protos/utils"
+)
+
+var (
+	blkMgrPruneInfoKey = []byte("blkMgrPruneInfo")
+)
+
+// pruneInfo tracks the oldest block file (and the first block in it) that is retained in the block storage.
+// A zero value indicates that nothing has been pruned yet. For a block storage that is bootstrapped, the
+// blocks preceding the first block are treated as pruned
+type pruneInfo struct {
+	firstFileSuffixNum int
+	firstBlockNum      uint64
+}
+
+/*
+prune removes the oldest block files all of whose blocks can be pruned as per the given policy.
+Blocks are pruned in whole files and the file currently being written to is never pruned.
+The last block of a file is taken as the representative of the file i.e., a file is pruned only if
+the policy allows pruning of its last block.
+
+The block header carries no time, so the time of a block is the latest of the timestamps in the chain
+headers of its transactions. These timestamps are set by the clients that create the transactions,
+hence a block holding a transaction with a timestamp in the future is retained longer by a time based
+policy, as are the blocks that precede it.
+
+The entries of the pruned blocks are left in the index so that the lookups for a pruned block or a
+transaction in a pruned block can be answered with `blkstorage.ErrPruned` rather than with a missing entry.
+The prune info is persisted before the files are removed, and the files that are left behind by a crash
+in between are removed when the manager is started again.
+*/
+func (mgr *blockfileMgr) prune(policy ledger.PrunePolicy) error {
+	if mgr.readOnly {
+		return errReadOnly
+	}
+	mgr.pruneLock.Lock()
+	defer mgr.pruneLock.Unlock()
+
+	mgr.cpInfoCond.L.Lock()
+	latestFileNum := mgr.cpInfo.latestFileChunkSuffixNum
+	lastBlockNum := mgr.cpInfo.lastBlockNumber
+	mgr.cpInfoCond.L.Unlock()
+
+	currentPruneInfo := mgr.getPruneInfo()
+	newPruneInfo := currentPruneInfo
+	for fileNum := currentPruneInfo.firstFileSuffixNum; fileNum < latestFileNum; fileNum++ {
+		nextFirstBlockNum, ok, err := mgr.firstBlockNumInFile(fileNum + 1)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			// the next file is yet to receive its first block
+			break
+		}
+		if nextFirstBlockNum == newPruneInfo.firstBlockNum {
+			// the file holds no block, which happens when the first block did not fit in it
+			newPruneInfo = &pruneInfo{firstFileSuffixNum: fileNum + 1, firstBlockNum: nextFirstBlockNum}
+			continue
+		}
+		lastBlockNumInFile := nextFirstBlockNum - 1
+		lastBlockInFile, err := mgr.retrieveBlockByNumber(lastBlockNumInFile)
+		if err != nil {
+			return err
+		}
+		if !policy.CanPrune(lastBlockNum-lastBlockNumInFile, blockTime(lastBlockInFile)) {
+			break
+		}
+		newPruneInfo = &pruneInfo{firstFileSuffixNum: fileNum + 1, firstBlockNum: nextFirstBlockNum}
+	}
+	if newPruneInfo == currentPruneInfo {
+		logger.Debugf("No block file to prune, prune info [%s]", currentPruneInfo)
+		return nil
+	}
+
+	if err := mgr.savePruneInfo(newPruneInfo); err != nil {
+		return fmt.Errorf("Error while saving prune info to db: %s", err)
+	}
+	mgr.prunedInfo.Store(newPruneInfo)
+	logger.Infof("Pruning block files [%d] to [%d], first retained block is [%d]",
+		currentPruneInfo.firstFileSuffixNum, newPruneInfo.firstFileSuffixNum-1, newPruneInfo.firstBlockNum)
+	return removeBlockfiles(mgr.rootDir, currentPruneInfo.firstFileSuffixNum, newPruneInfo.firstFileSuffixNum)
+}
+
+func (mgr *blockfileMgr) getPruneInfo() *pruneInfo {
+	return mgr.prunedInfo.Load().(*pruneInfo)
+}
+
+// isBlockPruned returns true if the given block precedes the first block retained in the block storage
+func (mgr *blockfileMgr) isBlockPruned(blockNum uint64) bool {
+	return blockNum < mgr.getPruneInfo().firstBlockNum
+}
+
+// isPruned returns true if the given location lies in a block file that has been pruned
+func (mgr *blockfileMgr) isPruned(lp *fileLocPointer) bool {
+	return lp.fileSuffixNum < mgr.getPruneInfo().firstFileSuffixNum
+}
+
+// firstBlockNumInFile returns the number of the first block in the given block file.
+// The second return value is false if the file does not contain a complete block
+func (mgr *blockfileMgr) firstBlockNumInFile(fileNum int) (uint64, bool, error) {
+	stream, err := newBlockfileStream(mgr.rootDir, fileNum, 0)
+	if err != nil {
+		return 0, false, err
+	}
+	defer stream.close()
+	blockBytes, err := stream.nextBlockBytes()
+	if err == ErrUnexpectedEndOfBlockfile || (err == nil && blockBytes == nil) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	info, err := extractSerializedBlockInfo(blockBytes)
+	if err != nil {
+		return 0, false, err
+	}
+	return info.blockHeader.Number, true, nil
+}
+
+// blockTime returns the time of the given block, which is the latest of the timestamps in the chain
+// headers of its transactions, or the zero time if none of its transactions carries a timestamp.
+// The transactions which cannot be unmarshaled carry no timestamp
+func blockTime(block *common.Block) time.Time {
+	var t time.Time
+	for i, envBytes := range block.Data.Data {
+		env, err := putil.GetEnvelopeFromBlock(envBytes)
+		if err != nil {
+			logger.Debugf("Ignoring the time of transaction [%d] of block [%d]: %s", i, block.Header.Number, err)
+			continue
+		}
+		payload, err := putil.GetPayload(env)
+		if err != nil {
+			logger.Debugf("Ignoring the time of transaction [%d] of block [%d]: %s", i, block.Header.Number, err)
+			continue
+		}
+		if payload.Header == nil || payload.Header.ChainHeader == nil || payload.Header.ChainHeader.Timestamp == nil {
+			continue
+		}
+		timestamp := payload.Header.ChainHeader.Timestamp
+		if txTime := time.Unix(timestamp.Seconds, int64(timestamp.Nanos)); txTime.After(t) {
+			t = txTime
+		}
+	}
+	return t
+}
+
+// removeBlockfiles removes the block files with suffix in the range [startFileNum, endFileNum)
+func removeBlockfiles(rootDir string, startFileNum int, endFileNum int) error {
+	for fileNum := startFileNum; fileNum < endFileNum; fileNum++ {
+		filePath := deriveBlockfilePath(rootDir, fileNum)
+		if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("Error while removing pruned block file [%s]: %s", filePath, err)
+		}
+	}
+	return nil
+}
+
+func (mgr *blockfileMgr) loadPruneInfo() (*pruneInfo, error) {
+	var b []byte
+	var err error
+	if b, err = mgr.db.Get(blkMgrPruneInfoKey); err != nil {
+		return nil, err
+	}
+	i := &pruneInfo{}
+	if b == nil {
+		return i, nil
+	}
+	if err = i.unmarshal(b); err != nil {
+		return nil, err
+	}
+	logger.Debugf("loaded pruneInfo:%s", i)
+	return i, nil
+}
+
+func (mgr *blockfileMgr) savePruneInfo(i *pruneInfo) error {
+	b, err := i.marshal()
+	if err != nil {
+		return err
+	}
+	return mgr.db.Put(blkMgrPruneInfoKey, b, true)
+}
+
+func (i *pruneInfo) marshal() ([]byte, error) {
+	buffer := proto.NewBuffer([]byte{})
+	var err error
+	if err = buffer.EncodeVarint(uint64(i.firstFileSuffixNum)); err != nil {
+		return nil, err
+	}
+	if err = buffer.EncodeVarint(i.firstBlockNum); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (i *pruneInfo) unmarshal(b []byte) error {
+	buffer := proto.NewBuffer(b)
+	var val uint64
+	var err error
+
+	if val, err = buffer.DecodeVarint(); err != nil {
+		return err
+	}
+	i.firstFileSuffixNum = int(val)
+
+	if val, err = buffer.DecodeVarint(); err != nil {
+		return err
+	}
+	i.firstBlockNum = val
+	return nil
+}
+
+func (i *pruneInfo) String() string {
+	return fmt.Sprintf("firstFileSuffixNum=[%d], firstBlockNum=[%d]", i.firstFileSuffixNum, i.firstBlockNum)
+}
@@ -0,0 +1,183 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsblkstorage
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/blkstorage"
+	"github.com/hyperledger/fabric/core/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestBlockfileMgrPruneKeepLastNBlocks(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath, 0))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	addBlocksInSeparateFiles(blkfileMgrWrapper, blocks)
+
+	err := blkfileMgrWrapper.blockfileMgr.prune(&ledger.KeepLastNBlocksPolicy{N: 3})
+	testutil.AssertNoError(t, err, "Error while pruning")
+	// files 0 to 6 contain the blocks 1 to 7
+	for fileNum := 0; fileNum < 7; fileNum++ {
+		_, err := os.Stat(deriveBlockfilePath(blkfileMgrWrapper.blockfileMgr.rootDir, fileNum))
+		testutil.AssertEquals(t, os.IsNotExist(err), true)
+	}
+	testPrunedBlocks(blkfileMgrWrapper, blocks[:7])
+	testRetainedBlocks(blkfileMgrWrapper, blocks[7:])
+
+	// pruning again with the same policy is a no-op
+	err = blkfileMgrWrapper.blockfileMgr.prune(&ledger.KeepLastNBlocksPolicy{N: 3})
+	testutil.AssertNoError(t, err, "Error while pruning")
+	testRetainedBlocks(blkfileMgrWrapper, blocks[7:])
+	blkfileMgrWrapper.close()
+
+	// the pruned blocks remain pruned after a restart and new blocks can be added
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	testPrunedBlocks(blkfileMgrWrapper, blocks[:7])
+	testRetainedBlocks(blkfileMgrWrapper, blocks[7:])
+	bg := testutil.NewBlockGenerator(t)
+	moreBlocks := bg.NextTestBlocks(11)[10:]
+	blkfileMgrWrapper.addBlocks(moreBlocks)
+	testRetainedBlocks(blkfileMgrWrapper, append(blocks[7:], moreBlocks...))
+}
+
+func TestBlockfileMgrPruneKeepBlocksNewerThan(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath, 0))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	blocks := testutil.ConstructTestBlocks(t, 5)
+	addBlocksInSeparateFiles(blkfileMgrWrapper, blocks)
+
+	err := blkfileMgrWrapper.blockfileMgr.prune(&ledger.KeepBlocksNewerThanPolicy{T: time.Now().Add(-time.Hour)})
+	testutil.AssertNoError(t, err, "Error while pruning")
+	testRetainedBlocks(blkfileMgrWrapper, blocks)
+
+	// the time of a block is that of its transactions, not the modification time of its file
+	old := time.Now().Add(-2 * time.Hour)
+	err = os.Chtimes(deriveBlockfilePath(blkfileMgrWrapper.blockfileMgr.rootDir, 0), old, old)
+	testutil.AssertNoError(t, err, "Error while changing the modification time of a block file")
+	err = blkfileMgrWrapper.blockfileMgr.prune(&ledger.KeepBlocksNewerThanPolicy{T: time.Now().Add(-time.Hour)})
+	testutil.AssertNoError(t, err, "Error while pruning")
+	testRetainedBlocks(blkfileMgrWrapper, blocks)
+
+	// the file currently being written to is never pruned
+	err = blkfileMgrWrapper.blockfileMgr.prune(&ledger.KeepBlocksNewerThanPolicy{T: time.Now().Add(time.Hour)})
+	testutil.AssertNoError(t, err, "Error while pruning")
+	testPrunedBlocks(blkfileMgrWrapper, blocks[:4])
+	testRetainedBlocks(blkfileMgrWrapper, blocks[4:])
+}
+
+func TestBlockfileMgrPruneConfigBlock(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath, 0))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+	_, err := blkfileMgrWrapper.blockfileMgr.retrieveConfigBlock()
+	testutil.AssertSame(t, err, blkstorage.ErrNotFoundInIndex)
+
+	// blocks 2 and 4 carry a config transaction, of which the latter is invalid
+	genesisBlock, err := configtxtest.MakeGenesisBlock("testchain")
+	testutil.AssertNoError(t, err, "")
+	blocks := testutil.ConstructTestBlocks(t, 5)
+	for _, block := range []*common.Block{blocks[1], blocks[3]} {
+		block.Data = genesisBlock.Data
+		block.Header.DataHash = block.Data.Hash()
+	}
+	txsFilter := util.NewTxValidationFlags(1)
+	txsFilter.SetFlag(0, pb.TxValidationCode_INVALID_CONFIG_TRANSACTION)
+	util.SetTxValidationFlags(blocks[3], txsFilter)
+	addBlocksInSeparateFiles(blkfileMgrWrapper, blocks)
+	configBlock, err := blkfileMgrWrapper.blockfileMgr.retrieveConfigBlock()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, configBlock, blocks[1])
+
+	// the config block remains available once pruned, after the index is rebuilt and after a restart
+	err = blkfileMgrWrapper.blockfileMgr.prune(&ledger.KeepLastNBlocksPolicy{N: 1})
+	testutil.AssertNoError(t, err, "Error while pruning")
+	testPrunedBlocks(blkfileMgrWrapper, blocks[:4])
+	testutil.AssertNoError(t, blkfileMgrWrapper.blockfileMgr.rebuildIndex(), "")
+	blkfileMgrWrapper.close()
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	configBlock, err = blkfileMgrWrapper.blockfileMgr.retrieveConfigBlock()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, configBlock, blocks[1])
+}
+
+func TestBlockfileMgrPruneInfo(t *testing.T) {
+	i := &pruneInfo{firstFileSuffixNum: 12, firstBlockNum: 3456}
+	b, err := i.marshal()
+	testutil.AssertNoError(t, err, "")
+	unmarshalledInfo := &pruneInfo{}
+	testutil.AssertNoError(t, unmarshalledInfo.unmarshal(b), "")
+	testutil.AssertEquals(t, unmarshalledInfo, i)
+}
+
+// addBlocksInSeparateFiles adds each of the blocks to a block file of its own
+func addBlocksInSeparateFiles(w *testBlockfileMgrWrapper, blocks []*common.Block) {
+	for i, block := range blocks {
+		if i > 0 {
+			w.blockfileMgr.moveToNextFile()
+		}
+		w.addBlocks([]*common.Block{block})
+	}
+}
+
+func testPrunedBlocks(w *testBlockfileMgrWrapper, blocks []*common.Block) {
+	for _, block := range blocks {
+		_, err := w.blockfileMgr.retrieveBlockByNumber(block.Header.Number)
+		testutil.AssertSame(w.t, err, blkstorage.ErrPruned)
+		_, err = w.blockfileMgr.retrieveBlockByHash(block.Header.Hash())
+		testutil.AssertSame(w.t, err, blkstorage.ErrPruned)
+		txID, err := extractTxID(block.Data.Data[0])
+		testutil.AssertNoError(w.t, err, "")
+		_, err = w.blockfileMgr.retrieveTransactionByID(txID)
+		testutil.AssertSame(w.t, err, blkstorage.ErrPruned)
+		_, err = w.blockfileMgr.retrieveTransactionForBlockNumTranNum(block.Header.Number, 1)
+		testutil.AssertSame(w.t, err, blkstorage.ErrPruned)
+
+		itr, err := w.blockfileMgr.retrieveBlocks(block.Header.Number)
+		testutil.AssertNoError(w.t, err, "")
+		_, err = itr.Next()
+		testutil.AssertSame(w.t, err, blkstorage.ErrPruned)
+		itr.Close()
+	}
+}
+
+func testRetainedBlocks(w *testBlockfileMgrWrapper, blocks []*common.Block) {
+	w.testGetBlockByHash(blocks)
+	w.testGetBlockByNumber(blocks, blocks[0].Header.Number)
+	itr, err := w.blockfileMgr.retrieveBlocks(blocks[0].Header.Number)
+	testutil.AssertNoError(w.t, err, "")
+	defer itr.Close()
+	for _, block := range blocks {
+		bh, err := itr.Next()
+		testutil.AssertNoError(w.t, err, "")
+		testutil.AssertEquals(w.t, bh.(*blockHolder).GetBlock(), block)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsblkstorage
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/blkstorage"
+	"github.com/hyperledger/fabric/core/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+// indexKeyPrefixes are the prefixes of the index entries that point to the block files. The validation codes of the
+// transactions are not among them: they are rewritten along with the entries of the retained blocks when the index
+// is rebuilt, and are kept for the pruned blocks
+var indexKeyPrefixes = []byte{blockNumIdxKeyPrefix, blockHashIdxKeyPrefix, txIDIdxKeyPrefix, blockNumTranNumIdxKeyPrefix}
+
+// verify scans the retained block files and verifies that each block carries the hash of the preceding block,
+// computed with the hashing algorithm of the chain, and that the index points the number, the hash and the transactions
+// of each block to the place of the block in the block files. The number of blocks verified is returned
+func (mgr *blockfileMgr) verify() (uint64, error) {
+	stream, err := newBlockStream(mgr.rootDir, mgr.getPruneInfo().firstFileSuffixNum, 0, mgr.cpInfo.latestFileChunkSuffixNum)
+	if err != nil {
+		return 0, err
+	}
+	defer stream.close()
+
+	var numBlocks uint64
+	var previousHeader *common.BlockHeader
+	for {
+		blockBytes, blockPlacementInfo, err := stream.nextBlockBytesAndPlacementInfo()
+		if err != nil {
+			return numBlocks, err
+		}
+		if blockBytes == nil {
+			break
+		}
+		info, err := extractSerializedBlockInfo(blockBytes)
+		if err != nil {
+			return numBlocks, fmt.Errorf("Error while reading the block at [%s]: %s", blockPlacementInfo, err)
+		}
+		header := info.blockHeader
+		if previousHeader != nil {
+			if header.Number != previousHeader.Number+1 {
+				return numBlocks, fmt.Errorf("Block %d is followed by block %d in the block files", previousHeader.Number, header.Number)
+			}
+			if previousHash := previousHeader.HashWith(mgr.hashingAlgorithm); !bytes.Equal(header.PreviousHash, previousHash) {
+				return numBlocks, fmt.Errorf("Block %d should have had previous hash of %x but was %x", header.Number, previousHash, header.PreviousHash)
+			}
+		}
+
+		// shift the txoffsets relative to the start of the block (as done in addBlock) because
+		// the block bytes are preceded by their length
+		for _, offset := range info.txOffsets {
+			offset.loc.offset += int(blockPlacementInfo.blockBytesOffset - blockPlacementInfo.blockStartOffset)
+		}
+		blockFLP := &fileLocPointer{fileSuffixNum: blockPlacementInfo.fileNum,
+			locPointer: locPointer{offset: int(blockPlacementInfo.blockStartOffset)}}
+		if err = mgr.verifyBlockIndex(header, blockFLP, info.txOffsets); err != nil {
+			return numBlocks, err
+		}
+		previousHeader = header
+		numBlocks++
+	}
+
+	if previousHeader != nil && previousHeader.Number != mgr.cpInfo.lastBlockNumber {
+		return numBlocks, fmt.Errorf("The last block in the block files is block %d, while the checkpoint info records block %d",
+			previousHeader.Number, mgr.cpInfo.lastBlockNumber)
+	}
+	if previousHeader != nil {
+		lastBlockIndexed, err := mgr.index.getLastBlockIndexed()
+		if err != nil {
+			return numBlocks, err
+		}
+		if lastBlockIndexed != previousHeader.Number {
+			return numBlocks, fmt.Errorf("The last block in the block files is block %d, while the last block indexed is block %d",
+				previousHeader.Number, lastBlockIndexed)
+		}
+	}
+	return numBlocks, nil
+}
+
+// verifyBlockIndex verifies the entries of the index for a block, skipping the attributes that are not indexed
+func (mgr *blockfileMgr) verifyBlockIndex(header *common.BlockHeader, blockFLP *fileLocPointer, txOffsets []*txindexInfo) error {
+	sameBlockLoc := func(flp *fileLocPointer, err error) bool {
+		return err == blkstorage.ErrAttrNotIndexed ||
+			(err == nil && flp.fileSuffixNum == blockFLP.fileSuffixNum && flp.offset == blockFLP.offset)
+	}
+	if !sameBlockLoc(mgr.index.getBlockLocByBlockNum(header.Number)) {
+		return fmt.Errorf("The index does not point block number %d to the block at [%s]", header.Number, blockFLP)
+	}
+	if !sameBlockLoc(mgr.index.getBlockLocByHash(header.HashWith(mgr.hashingAlgorithm))) {
+		return fmt.Errorf("The index does not point the hash of block %d to the block at [%s]", header.Number, blockFLP)
+	}
+
+	for i, txOffset := range txOffsets {
+		txFLP := newFileLocationPointer(blockFLP.fileSuffixNum, blockFLP.offset, txOffset.loc)
+		flp, err := mgr.index.getTXLocForBlockNumTranNum(header.Number, uint64(i+1))
+		if err != nil && err != blkstorage.ErrAttrNotIndexed {
+			return fmt.Errorf("Error while looking up transaction %d of block %d in the index: %s", i+1, header.Number, err)
+		}
+		if err == nil && *flp != *txFLP {
+			return fmt.Errorf("The index points transaction %d of block %d to [%s] instead of [%s]", i+1, header.Number, flp, txFLP)
+		}
+		// a transaction id that is replayed by a later transaction points to the earlier transaction, which
+		// may be in a pruned block file, hence only the presence of the entries is checked
+		if _, err = mgr.index.getTxLoc(txOffset.txID); err != nil && err != blkstorage.ErrAttrNotIndexed {
+			return fmt.Errorf("Error while looking up transaction [%s] of block %d in the index: %s", txOffset.txID, header.Number, err)
+		}
+		if _, err = mgr.index.getTxValidationCodeByTxID(txOffset.txID); err != nil && err != blkstorage.ErrAttrNotIndexed {
+			return fmt.Errorf("Error while looking up the validation code of transaction [%s] of block %d in the index: %s", txOffset.txID, header.Number, err)
+		}
+	}
+	return nil
+}
+
+// rebuildIndex removes the entries of the index and indexes the blocks of the retained block files again.
+// The entries that point to the pruned block files are left in place, as done by prune
+func (mgr *blockfileMgr) rebuildIndex() error {
+	if mgr.readOnly {
+		return errReadOnly
+	}
+	batch := leveldbhelper.NewUpdateBatch()
+	for _, prefix := range indexKeyPrefixes {
+		itr := mgr.db.GetIterator([]byte{prefix}, []byte{prefix + 1})
+		for itr.Next() {
+			flp := &fileLocPointer{}
+			if err := flp.unmarshal(itr.Value()); err == nil && mgr.isPruned(flp) {
+				continue
+			}
+			batch.Delete(append([]byte{}, itr.Key()...))
+		}
+		itr.Release()
+	}
+	batch.Delete(indexCheckpointKey)
+	if err := mgr.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	logger.Infof("Removed the index entries, rebuilding the index from the block files")
+	return mgr.syncIndex()
+}
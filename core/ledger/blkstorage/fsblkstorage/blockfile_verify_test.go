@@ -0,0 +1,96 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsblkstorage
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/testutil"
+)
+
+func TestBlockfileMgrVerify(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath, 0))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	blkfileMgr := blkfileMgrWrapper.blockfileMgr
+
+	numBlocks, err := blkfileMgr.verify()
+	testutil.AssertNoError(t, err, "Error while verifying an empty block store")
+	testutil.AssertEquals(t, numBlocks, uint64(0))
+
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	addBlocksInSeparateFiles(blkfileMgrWrapper, blocks[:5])
+	blkfileMgrWrapper.addBlocks(blocks[5:])
+	numBlocks, err = blkfileMgr.verify()
+	testutil.AssertNoError(t, err, "Error while verifying the block store")
+	testutil.AssertEquals(t, numBlocks, uint64(10))
+
+	// the block hashes are not indexed with another hashing algorithm
+	blkfileMgr.hashingAlgorithm = func(input []byte) []byte { return testutil.ComputeCryptoHash(input, input) }
+	numBlocks, err = blkfileMgr.verify()
+	testutil.AssertError(t, err, "Expected the block hashes not to be found in the index")
+	testutil.AssertEquals(t, numBlocks, uint64(0))
+	blkfileMgr.hashingAlgorithm = util.ComputeCryptoHash
+
+	// only the retained blocks are verified
+	err = blkfileMgr.prune(&ledger.KeepLastNBlocksPolicy{N: 6})
+	testutil.AssertNoError(t, err, "Error while pruning")
+	numBlocks, err = blkfileMgr.verify()
+	testutil.AssertNoError(t, err, "Error while verifying the pruned block store")
+	testutil.AssertEquals(t, numBlocks, uint64(6))
+}
+
+func TestBlockfileMgrRebuildIndex(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath, 0))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	blkfileMgr := blkfileMgrWrapper.blockfileMgr
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	addBlocksInSeparateFiles(blkfileMgrWrapper, blocks)
+	err := blkfileMgr.prune(&ledger.KeepLastNBlocksPolicy{N: 5})
+	testutil.AssertNoError(t, err, "Error while pruning")
+
+	// point block 8 to the location of block 7, and drop the entries of the hash of block 9 and of the first transaction of block 10
+	flpBytes, err := blkfileMgr.db.Get(constructBlockNumKey(7))
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNoError(t, blkfileMgr.db.Put(constructBlockNumKey(8), flpBytes, true), "")
+	_, err = blkfileMgr.verify()
+	testutil.AssertError(t, err, "Expected the misplaced block to be detected")
+	testutil.AssertNoError(t, blkfileMgr.rebuildIndex(), "Error while rebuilding the index")
+	numBlocks, err := blkfileMgr.verify()
+	testutil.AssertNoError(t, err, "Error while verifying the rebuilt index")
+	testutil.AssertEquals(t, numBlocks, uint64(5))
+
+	testutil.AssertNoError(t, blkfileMgr.db.Delete(constructBlockHashKey(blocks[8].Header.Hash()), true), "")
+	_, err = blkfileMgr.verify()
+	testutil.AssertError(t, err, "Expected the missing block hash entry to be detected")
+	testutil.AssertNoError(t, blkfileMgr.db.Delete(constructBlockNumTranNumKey(10, 1), true), "")
+	_, err = blkfileMgr.verify()
+	testutil.AssertError(t, err, "Expected the missing transaction entry to be detected")
+
+	err = blkfileMgr.rebuildIndex()
+	testutil.AssertNoError(t, err, "Error while rebuilding the index")
+	numBlocks, err = blkfileMgr.verify()
+	testutil.AssertNoError(t, err, "Error while verifying the rebuilt index")
+	testutil.AssertEquals(t, numBlocks, uint64(5))
+	testPrunedBlocks(blkfileMgrWrapper, blocks[:5])
+	testRetainedBlocks(blkfileMgrWrapper, blocks[5:])
+}
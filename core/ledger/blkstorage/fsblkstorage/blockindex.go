@@ -23,18 +23,25 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/blkstorage"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/core/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
 )
 
 const (
-	blockNumIdxKeyPrefix        = 'n'
-	blockHashIdxKeyPrefix       = 'h'
-	txIDIdxKeyPrefix            = 't'
-	blockNumTranNumIdxKeyPrefix = 'a'
-	indexCheckpointKeyStr       = "indexCheckpointKey"
+	blockNumIdxKeyPrefix         = 'n'
+	blockHashIdxKeyPrefix        = 'h'
+	txIDIdxKeyPrefix             = 't'
+	blockNumTranNumIdxKeyPrefix  = 'a'
+	txValidationCodeIdxKeyPrefix = 'v'
+	indexCheckpointKeyStr        = "indexCheckpointKey"
+	configBlockKeyStr            = "configBlockKey"
 )
 
 var indexCheckpointKey = []byte(indexCheckpointKeyStr)
 
+// configBlockKey holds the latest valid config block in full, so that the block remains available once pruned
+var configBlockKey = []byte(configBlockKeyStr)
+
 type index interface {
 	getLastBlockIndexed() (uint64, error)
 	indexBlock(blockIdxInfo *blockIdxInfo) error
@@ -42,13 +49,17 @@ type index interface {
 	getBlockLocByBlockNum(blockNum uint64) (*fileLocPointer, error)
 	getTxLoc(txID string) (*fileLocPointer, error)
 	getTXLocForBlockNumTranNum(blockNum uint64, tranNum uint64) (*fileLocPointer, error)
+	getTxValidationCodeByTxID(txID string) (pb.TxValidationCode, error)
+	getConfigBlock() (*common.Block, error)
 }
 
 type blockIdxInfo struct {
-	blockNum  uint64
-	blockHash []byte
-	flp       *fileLocPointer
-	txOffsets []*txindexInfo
+	blockNum    uint64
+	blockHash   []byte
+	flp         *fileLocPointer
+	txOffsets   []*txindexInfo
+	metadata    *common.BlockMetadata
+	configBlock []byte
 }
 
 type blockIndex struct {
@@ -89,6 +100,7 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 	if err != nil {
 		return err
 	}
+	txsFilter := util.GetTxValidationFlags(blockIdxInfo.metadata)
 
 	//Index1
 	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockHash]; ok {
@@ -101,8 +113,13 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 	}
 
 	//Index3 Used to find a transactin by it's transaction id
+	//A transaction replaying the id of an earlier transaction is left out of Index3 and Index5,
+	//so that the entries of the earlier transaction are not overwritten
 	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxID]; ok {
-		for _, txoffset := range txOffsets {
+		for txIterator, txoffset := range txOffsets {
+			if txsFilter.Flag(txIterator) == pb.TxValidationCode_DUPLICATE_TXID {
+				continue
+			}
 			txFlp := newFileLocationPointer(flp.fileSuffixNum, flp.offset, txoffset.loc)
 			logger.Debugf("Adding txLoc [%s] for tx ID: [%s] to index", txFlp, txoffset.txID)
 			txFlpBytes, marshalErr := txFlp.marshal()
@@ -126,6 +143,21 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 		}
 	}
 
+	//Index5 - Used to find the validation code of a transaction by its transaction id
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxValidationCode]; ok {
+		for txIterator, txoffset := range txOffsets {
+			if txsFilter.Flag(txIterator) == pb.TxValidationCode_DUPLICATE_TXID {
+				continue
+			}
+			batch.Put(constructTxValidationCodeIDKey(txoffset.txID), []byte{byte(txsFilter.Flag(txIterator))})
+		}
+	}
+
+	//Index6 - The latest valid config block
+	if blockIdxInfo.configBlock != nil {
+		batch.Put(configBlockKey, blockIdxInfo.configBlock)
+	}
+
 	batch.Put(indexCheckpointKey, encodeBlockNum(blockIdxInfo.blockNum))
 	if err := index.db.WriteBatch(batch, false); err != nil {
 		return err
@@ -197,6 +229,35 @@ func (index *blockIndex) getTXLocForBlockNumTranNum(blockNum uint64, tranNum uin
 	return txFLP, nil
 }
 
+func (index *blockIndex) getTxValidationCodeByTxID(txID string) (pb.TxValidationCode, error) {
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxValidationCode]; !ok {
+		return pb.TxValidationCode_INVALID_OTHER_REASON, blkstorage.ErrAttrNotIndexed
+	}
+	b, err := index.db.Get(constructTxValidationCodeIDKey(txID))
+	if err != nil {
+		return pb.TxValidationCode_INVALID_OTHER_REASON, err
+	}
+	if len(b) != 1 {
+		return pb.TxValidationCode_INVALID_OTHER_REASON, blkstorage.ErrNotFoundInIndex
+	}
+	return pb.TxValidationCode(b[0]), nil
+}
+
+func (index *blockIndex) getConfigBlock() (*common.Block, error) {
+	b, err := index.db.Get(configBlockKey)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, blkstorage.ErrNotFoundInIndex
+	}
+	block := &common.Block{}
+	if err = proto.Unmarshal(b, block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
 func constructBlockNumKey(blockNum uint64) []byte {
 	blkNumBytes := util.EncodeOrderPreservingVarUint64(blockNum)
 	return append([]byte{blockNumIdxKeyPrefix}, blkNumBytes...)
@@ -210,6 +271,10 @@ func constructTxIDKey(txID string) []byte {
 	return append([]byte{txIDIdxKeyPrefix}, []byte(txID)...)
 }
 
+func constructTxValidationCodeIDKey(txID string) []byte {
+	return append([]byte{txValidationCodeIdxKeyPrefix}, []byte(txID)...)
+}
+
 func constructBlockNumTranNumKey(blockNum uint64, txNum uint64) []byte {
 	blkNumBytes := util.EncodeOrderPreservingVarUint64(blockNum)
 	tranNumBytes := util.EncodeOrderPreservingVarUint64(txNum)
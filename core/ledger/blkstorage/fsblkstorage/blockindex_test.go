@@ -22,6 +22,9 @@ import (
 
 	"github.com/hyperledger/fabric/core/ledger/blkstorage"
 	"github.com/hyperledger/fabric/core/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
 )
 
 type noopIndex struct {
@@ -45,6 +48,12 @@ func (i *noopIndex) getTxLoc(txID string) (*fileLocPointer, error) {
 func (i *noopIndex) getTXLocForBlockNumTranNum(blockNum uint64, tranNum uint64) (*fileLocPointer, error) {
 	return nil, nil
 }
+func (i *noopIndex) getTxValidationCodeByTxID(txID string) (pb.TxValidationCode, error) {
+	return pb.TxValidationCode_VALID, nil
+}
+func (i *noopIndex) getConfigBlock() (*common.Block, error) {
+	return nil, nil
+}
 
 func TestBlockIndexSync(t *testing.T) {
 	testBlockIndexSync(t, 10, 5, false)
@@ -100,6 +109,14 @@ func testBlockIndexSync(t *testing.T, numBlocks int, numBlocksToIndex int, syncB
 		block, err := blkfileMgr.retrieveBlockByNumber(uint64(i))
 		testutil.AssertNoError(t, err, fmt.Sprintf("block [%d] should have been present in the index", i))
 		testutil.AssertEquals(t, block, blocks[i-1])
+		// the transactions of the synced blocks should be retrievable as well
+		for j, txEnvelopeBytes := range block.Data.Data {
+			expectedTx, err := extractTransaction(txEnvelopeBytes)
+			testutil.AssertNoError(t, err, "")
+			tx, err := blkfileMgr.retrieveTransactionForBlockNumTranNum(uint64(i), uint64(j+1))
+			testutil.AssertNoError(t, err, fmt.Sprintf("tx [%d:%d] should have been present in the index", i, j+1))
+			testutil.AssertEquals(t, tx, expectedTx)
+		}
 	}
 }
 
@@ -110,6 +127,7 @@ func TestBlockIndexSelectiveIndexing(t *testing.T) {
 	testBlockIndexSelectiveIndexing(t, []blkstorage.IndexableAttr{blkstorage.IndexableAttrBlockNumTranNum})
 	testBlockIndexSelectiveIndexing(t, []blkstorage.IndexableAttr{blkstorage.IndexableAttrBlockHash, blkstorage.IndexableAttrBlockNum})
 	testBlockIndexSelectiveIndexing(t, []blkstorage.IndexableAttr{blkstorage.IndexableAttrTxID, blkstorage.IndexableAttrBlockNumTranNum})
+	testBlockIndexSelectiveIndexing(t, []blkstorage.IndexableAttr{blkstorage.IndexableAttrTxValidationCode})
 }
 
 func testBlockIndexSelectiveIndexing(t *testing.T, indexItems []blkstorage.IndexableAttr) {
@@ -119,6 +137,9 @@ func testBlockIndexSelectiveIndexing(t *testing.T, indexItems []blkstorage.Index
 	defer blkfileMgrWrapper.close()
 
 	blocks := testutil.ConstructTestBlocks(t, 3)
+	txsFilter := util.NewTxValidationFlags(len(blocks[0].Data.Data))
+	txsFilter.SetFlag(0, pb.TxValidationCode_MVCC_READ_CONFLICT)
+	util.SetTxValidationFlags(blocks[0], txsFilter)
 	// add test blocks
 	blkfileMgrWrapper.addBlocks(blocks)
 	blockfileMgr := blkfileMgrWrapper.blockfileMgr
@@ -155,6 +176,15 @@ func testBlockIndexSelectiveIndexing(t *testing.T, indexItems []blkstorage.Index
 		testutil.AssertSame(t, err, blkstorage.ErrAttrNotIndexed)
 	}
 
+	// test 'retrieveTxValidationCodeByTxID'
+	code, err := blockfileMgr.retrieveTxValidationCodeByTxID(txid)
+	if testutil.Contains(indexItems, blkstorage.IndexableAttrTxValidationCode) {
+		testutil.AssertNoError(t, err, "Error while retrieving tx validation code by id")
+		testutil.AssertEquals(t, code, pb.TxValidationCode_MVCC_READ_CONFLICT)
+	} else {
+		testutil.AssertSame(t, err, blkstorage.ErrAttrNotIndexed)
+	}
+
 	//test 'retrieveTrasnactionsByBlockNumTranNum
 	tx2, err := blockfileMgr.retrieveTransactionForBlockNumTranNum(1, 1)
 	if testutil.Contains(indexItems, blkstorage.IndexableAttrBlockNumTranNum) {
@@ -166,3 +196,39 @@ func testBlockIndexSelectiveIndexing(t *testing.T, indexItems []blkstorage.Index
 		testutil.AssertSame(t, err, blkstorage.ErrAttrNotIndexed)
 	}
 }
+
+func TestBlockIndexDuplicateTxID(t *testing.T) {
+	env := newTestEnv(t, NewConf("/tmp/fabric/ledgertests", 0))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testledger")
+	defer blkfileMgrWrapper.close()
+
+	// the first transaction of the second block replays the first transaction of the first block
+	blocks := testutil.ConstructTestBlocks(t, 2)
+	blocks[1].Data.Data[0] = blocks[0].Data.Data[0]
+	blocks[1].Header.DataHash = blocks[1].Data.Hash()
+	txsFilter := util.NewTxValidationFlags(len(blocks[1].Data.Data))
+	txsFilter.SetFlag(0, pb.TxValidationCode_DUPLICATE_TXID)
+	util.SetTxValidationFlags(blocks[1], txsFilter)
+	blkfileMgrWrapper.addBlocks(blocks)
+	blockfileMgr := blkfileMgrWrapper.blockfileMgr
+
+	// the transaction id keeps resolving to the original transaction
+	txid, err := extractTxID(blocks[0].Data.Data[0])
+	testutil.AssertNoError(t, err, "")
+	code, err := blockfileMgr.retrieveTxValidationCodeByTxID(txid)
+	testutil.AssertNoError(t, err, "Error while retrieving tx validation code by id")
+	testutil.AssertEquals(t, code, pb.TxValidationCode_VALID)
+	txLoc, err := blockfileMgr.index.getTxLoc(txid)
+	testutil.AssertNoError(t, err, "Error while retrieving tx location by id")
+	blockLoc, err := blockfileMgr.index.getBlockLocByBlockNum(2)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, txLoc.offset < blockLoc.offset, true)
+
+	// the replayed transaction is still found by its position in the block
+	tx, err := blockfileMgr.retrieveTransactionForBlockNumTranNum(2, 1)
+	testutil.AssertNoError(t, err, "Error while retrieving tx by blockNum and tranNum")
+	txOrig, err := extractTransaction(blocks[0].Data.Data[0])
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, tx, txOrig)
+}
@@ -21,6 +21,7 @@ import (
 	"sync"
 
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/blkstorage"
 
 	"github.com/hyperledger/fabric/protos/common"
 )
@@ -73,9 +74,15 @@ func (itr *blocksItr) waitForBlock(blockNum uint64) uint64 {
 func (itr *blocksItr) initStream() error {
 	var lp *fileLocPointer
 	var err error
+	if itr.mgr.isBlockPruned(itr.blockNumToRetrieve) {
+		return blkstorage.ErrPruned
+	}
 	if lp, err = itr.mgr.index.getBlockLocByBlockNum(itr.blockNumToRetrieve); err != nil {
 		return err
 	}
+	if itr.mgr.isPruned(lp) {
+		return blkstorage.ErrPruned
+	}
 	if itr.stream, err = newBlockStream(itr.mgr.rootDir, lp.fileSuffixNum, int64(lp.offset), -1); err != nil {
 		return err
 	}
@@ -119,5 +126,7 @@ func (itr *blocksItr) Close() {
 	itr.mgr.cpInfoCond.L.Lock()
 	defer itr.mgr.cpInfoCond.L.Unlock()
 	itr.mgr.cpInfoCond.Broadcast()
-	itr.stream.close()
+	if itr.stream != nil {
+		itr.stream.close()
+	}
 }
@@ -34,8 +34,8 @@ type fsBlockStore struct {
 
 // NewFsBlockStore constructs a `FsBlockStore`
 func newFsBlockStore(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
-	dbHandle *leveldbhelper.DBHandle) *fsBlockStore {
-	return &fsBlockStore{id, conf, newBlockfileMgr(id, conf, indexConfig, dbHandle)}
+	dbHandle *leveldbhelper.DBHandle, readOnly bool) *fsBlockStore {
+	return &fsBlockStore{id, conf, newBlockfileMgr(id, conf, indexConfig, dbHandle, readOnly)}
 }
 
 // AddBlock adds a new block
@@ -73,6 +73,49 @@ func (store *fsBlockStore) RetrieveTxByID(txID string) (*pb.Transaction, error)
 	return store.fileMgr.retrieveTransactionByID(txID)
 }
 
+// RetrieveTxByBlockNumTranNum returns the transaction at the given position (starting from 1) in the given block.
+// This requires the block store to index the attribute `IndexableAttrBlockNumTranNum`
+func (store *fsBlockStore) RetrieveTxByBlockNumTranNum(blockNum uint64, tranNum uint64) (*pb.Transaction, error) {
+	return store.fileMgr.retrieveTransactionForBlockNumTranNum(blockNum, tranNum)
+}
+
+// RetrieveTxValidationCodeByTxID returns the validation code of the transaction with the given id,
+// as recorded in the metadata of its block. The code remains available once the block is pruned.
+// This requires the block store to index the attribute `IndexableAttrTxValidationCode`
+func (store *fsBlockStore) RetrieveTxValidationCodeByTxID(txID string) (pb.TxValidationCode, error) {
+	return store.fileMgr.retrieveTxValidationCodeByTxID(txID)
+}
+
+// Prune removes the oldest block files whose blocks can be pruned as per the given policy.
+// A subsequent retrieval of a pruned block or transaction returns `blkstorage.ErrPruned`
+func (store *fsBlockStore) Prune(policy ledger.PrunePolicy) error {
+	return store.fileMgr.prune(policy)
+}
+
+// RetrieveConfigBlock returns the latest config block of the store whose transaction is valid.
+// The block remains available once pruned
+func (store *fsBlockStore) RetrieveConfigBlock() (*common.Block, error) {
+	return store.fileMgr.retrieveConfigBlock()
+}
+
+// Bootstrap initializes an empty block store for continuing the chain described by the given info.
+// The blocks up to the height in the info are not available in the store and are reported as pruned.
+// The given config block, if any, is retained as the latest config block of the chain
+func (store *fsBlockStore) Bootstrap(bcInfo *pb.BlockchainInfo, configBlock *common.Block) error {
+	return store.fileMgr.bootstrap(bcInfo, configBlock)
+}
+
+// Verify verifies the hash chain of the retained blocks and the consistency of the index with the block files.
+// The number of blocks verified is returned along with the first inconsistency found, if any
+func (store *fsBlockStore) Verify() (uint64, error) {
+	return store.fileMgr.verify()
+}
+
+// RebuildIndex discards the index and indexes the retained blocks again from the block files
+func (store *fsBlockStore) RebuildIndex() error {
+	return store.fileMgr.rebuildIndex()
+}
+
 // Shutdown shuts down the block store
 func (store *fsBlockStore) Shutdown() {
 	logger.Debugf("closing fs blockStore:%s", store.id)
@@ -45,7 +45,15 @@ func (p *FsBlockstoreProvider) CreateBlockStore(ledgerid string) (blkstorage.Blo
 // This method should be invoked only once for a particular ledgerid
 func (p *FsBlockstoreProvider) OpenBlockStore(ledgerid string) (blkstorage.BlockStore, error) {
 	indexStoreHandle := p.leveldbProvider.GetDBHandle(ledgerid)
-	return newFsBlockStore(ledgerid, p.conf, p.indexConfig, indexStoreHandle), nil
+	return newFsBlockStore(ledgerid, p.conf, p.indexConfig, indexStoreHandle, false), nil
+}
+
+// OpenBlockStoreReadOnly opens the existing block store for given ledgerid without
+// writing to it, i.e., the index is not synced with the block files and the store
+// rejects the blocks added to it. This method is meant for inspecting a block store
+func (p *FsBlockstoreProvider) OpenBlockStoreReadOnly(ledgerid string) (blkstorage.BlockStore, error) {
+	indexStoreHandle := p.leveldbProvider.GetDBHandle(ledgerid)
+	return newFsBlockStore(ledgerid, p.conf, p.indexConfig, indexStoreHandle, true), nil
 }
 
 // Exists tells whether the BlockStore with given id exits
@@ -38,7 +38,7 @@ func TestHistoryDatabaseAutoCreate(t *testing.T) {
 	logger.Debugf("===HISTORYDB=== TestHistoryDatabaseAutoCreate IsCouchDBEnabled()value: %v , IsHistoryDBEnabled()value: %v\n",
 		ledgerconfig.IsCouchDBEnabled(), ledgerconfig.IsHistoryDBEnabled())
 
-	if ledgerconfig.IsHistoryDBEnabled() == true {
+	if ledgerconfig.IsCouchDBEnabled() == true && ledgerconfig.IsHistoryDBEnabled() == true {
 
 		env := newTestEnvHistoryCouchDB(t, "history-test")
 		env.cleanup()       //cleanup at the beginning to ensure the database doesn't exist already
@@ -86,7 +86,7 @@ func TestConstructCompositeKey(t *testing.T) {
 //TestSavepoint tests the recordSavepoint and GetBlockNumfromSavepoint methods for recording and reading a savepoint document
 func TestSavepoint(t *testing.T) {
 
-	if ledgerconfig.IsHistoryDBEnabled() == true {
+	if ledgerconfig.IsCouchDBEnabled() == true && ledgerconfig.IsHistoryDBEnabled() == true {
 
 		env := newTestEnvHistoryCouchDB(t, "history-test")
 		env.cleanup()       //cleanup at the beginning to ensure the database doesn't exist already
@@ -125,7 +125,7 @@ func TestHistoryDatabaseCommit(t *testing.T) {
 	logger.Debugf("===HISTORYDB=== TestHistoryDatabaseCommit  IsCouchDBEnabled()value: %v , IsHistoryDBEnabled()value: %v\n",
 		ledgerconfig.IsCouchDBEnabled(), ledgerconfig.IsHistoryDBEnabled())
 
-	if ledgerconfig.IsHistoryDBEnabled() == true {
+	if ledgerconfig.IsCouchDBEnabled() == true && ledgerconfig.IsHistoryDBEnabled() == true {
 		//TODO Build the necessary infrastructure so that history can be tested iwthout ledger
 
 	}
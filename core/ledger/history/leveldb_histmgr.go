@@ -0,0 +1,251 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/blkstorage"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwset"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/core/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+)
+
+// savepointKey is the key (within the history db of a ledger) under which the savepoint is stored
+var savepointKey = []byte{0x00}
+var historyKeySep = []byte{0x00}
+var historyKeyEndIndicator = byte(0x01)
+
+// LevelDBHistMgrProvider provides a `LevelDBHistMgr` for each ledger.
+// The history of all the ledgers is maintained in a single leveldb
+type LevelDBHistMgrProvider struct {
+	dbProvider *leveldbhelper.Provider
+}
+
+// NewLevelDBHistMgrProvider constructs a new `LevelDBHistMgrProvider`
+func NewLevelDBHistMgrProvider() *LevelDBHistMgrProvider {
+	dbPath := ledgerconfig.GetHistoryLevelDBPath()
+	logger.Debugf("constructing LevelDBHistMgrProvider dbPath=%s", dbPath)
+	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: dbPath})
+	return &LevelDBHistMgrProvider{dbProvider}
+}
+
+// GetHistMgr returns the history manager for the given ledger. The values and the transactions
+// of the history are not duplicated in the history db but are retrieved from the block store of the ledger
+func (provider *LevelDBHistMgrProvider) GetHistMgr(ledgerID string, blockStore blkstorage.BlockStore) *LevelDBHistMgr {
+	return NewLevelDBHistMgr(provider.dbProvider.GetDBHandle(ledgerID), blockStore)
+}
+
+// Close closes the underlying leveldb
+func (provider *LevelDBHistMgrProvider) Close() {
+	provider.dbProvider.Close()
+}
+
+// LevelDBHistMgr an implementation of interface `histmgmt.HistMgr` backed by leveldb.
+// For every valid write, an entry with the key <namespace, key, blockNum, tranNum> is
+// maintained with the ID of the writing transaction as its value
+type LevelDBHistMgr struct {
+	db         *leveldbhelper.DBHandle
+	blockStore blkstorage.BlockStore
+}
+
+// NewLevelDBHistMgr constructs a new `LevelDBHistMgr`
+func NewLevelDBHistMgr(db *leveldbhelper.DBHandle, blockStore blkstorage.BlockStore) *LevelDBHistMgr {
+	return &LevelDBHistMgr{db, blockStore}
+}
+
+// NewHistoryQueryExecutor implements method in interface `histmgmt.HistMgr`
+func (histmgr *LevelDBHistMgr) NewHistoryQueryExecutor() (ledger.HistoryQueryExecutor, error) {
+	return &LevelDBHistQueryExecutor{histmgr}, nil
+}
+
+// Commit implements method in interface `histmgmt.HistMgr`
+// Only the writes of the valid endorser transactions of the block are recorded
+func (histmgr *LevelDBHistMgr) Commit(block *common.Block) error {
+	blockNo := block.Header.Number
+	logger.Debugf("Updating history for blockNo: %v with [%d] transactions", blockNo, len(block.Data.Data))
+
+	txsFilter := util.GetTxValidationFlags(block.Metadata)
+	dbBatch := leveldbhelper.NewUpdateBatch()
+	for txIndex, envBytes := range block.Data.Data {
+		// transaction numbers start from 1, in line with the block store index and the state versions
+		tranNo := uint64(txIndex + 1)
+		if txsFilter.IsInvalid(txIndex) {
+			logger.Debugf("Skipping history for invalid transaction, tranNo: %v", tranNo)
+			continue
+		}
+
+		env, err := putils.GetEnvelopeFromBlock(envBytes)
+		if err != nil {
+			return err
+		}
+		payload, err := putils.GetPayload(env)
+		if err != nil {
+			return err
+		}
+		if common.HeaderType(payload.Header.ChainHeader.Type) != common.HeaderType_ENDORSER_TRANSACTION {
+			logger.Debugf("Skipping history for transaction of type %d, tranNo: %v", payload.Header.ChainHeader.Type, tranNo)
+			continue
+		}
+
+		respPayload, err := putils.GetActionFromEnvelope(envBytes)
+		if err != nil {
+			return err
+		}
+		txRWSet := &rwset.TxReadWriteSet{}
+		if err = txRWSet.Unmarshal(respPayload.Results); err != nil {
+			return err
+		}
+		txID := []byte(payload.Header.ChainHeader.TxID)
+		for _, nsRWSet := range txRWSet.NsRWs {
+			for _, kvWrite := range nsRWSet.Writes {
+				dbBatch.Put(constructHistoryKey(nsRWSet.NameSpace, kvWrite.Key, blockNo, tranNo), txID)
+			}
+		}
+	}
+
+	// the savepoint is written in the same batch as the history of the block
+	dbBatch.Put(savepointKey, util.EncodeOrderPreservingVarUint64(blockNo))
+	if err := histmgr.db.WriteBatch(dbBatch, true); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetBlockNumFromSavepoint implements method in interface `histmgmt.HistMgr`
+// If no savepoint is found, it returns 0
+func (histmgr *LevelDBHistMgr) GetBlockNumFromSavepoint() (uint64, error) {
+	savepointBytes, err := histmgr.db.Get(savepointKey)
+	if err != nil {
+		return 0, err
+	}
+	if savepointBytes == nil {
+		return 0, nil
+	}
+	blockNum, _ := util.DecodeOrderPreservingVarUint64(savepointBytes)
+	return blockNum, nil
+}
+
+// getTransactionsForNsKey returns a scanner over the history entries of the given key
+func (histmgr *LevelDBHistMgr) getTransactionsForNsKey(namespace string, key string) *levelHistScanner {
+	startKey := constructHistoryKeyPrefix(namespace, key)
+	endKey := constructHistoryKeyPrefix(namespace, key)
+	endKey[len(endKey)-1] = historyKeyEndIndicator
+	return &levelHistScanner{startKey, histmgr.db.GetIterator(startKey, endKey)}
+}
+
+// getTransaction retrieves the transaction at the given position from the block store
+func (histmgr *LevelDBHistMgr) getTransaction(blockNum uint64, tranNum uint64) (*pb.Transaction, error) {
+	tx, err := histmgr.blockStore.RetrieveTxByBlockNumTranNum(blockNum, tranNum)
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving transaction [%d:%d] from the block store: %s", blockNum, tranNum, err)
+	}
+	return tx, nil
+}
+
+// getWriteValue returns the value written to the given key by the transaction, nil for a delete
+func getWriteValue(tx *pb.Transaction, namespace string, key string) ([]byte, error) {
+	for _, action := range tx.Actions {
+		_, respPayload, err := putils.GetPayloads(action)
+		if err != nil {
+			return nil, err
+		}
+		txRWSet := &rwset.TxReadWriteSet{}
+		if err = txRWSet.Unmarshal(respPayload.Results); err != nil {
+			return nil, err
+		}
+		for _, nsRWSet := range txRWSet.NsRWs {
+			if nsRWSet.NameSpace != namespace {
+				continue
+			}
+			for _, kvWrite := range nsRWSet.Writes {
+				if kvWrite.Key == key {
+					return kvWrite.Value, nil
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+func constructHistoryKeyPrefix(ns string, key string) []byte {
+	historyKey := append([]byte(ns), historyKeySep...)
+	historyKey = append(historyKey, []byte(key)...)
+	return append(historyKey, historyKeySep...)
+}
+
+func constructHistoryKey(ns string, key string, blockNum uint64, tranNum uint64) []byte {
+	//History Key is: "namespace key blocknum trannum", with the numbers in an order preserving encoding
+	//so that the history of a key is scanned in the order of the commits
+	historyKey := constructHistoryKeyPrefix(ns, key)
+	historyKey = append(historyKey, util.EncodeOrderPreservingVarUint64(blockNum)...)
+	return append(historyKey, util.EncodeOrderPreservingVarUint64(tranNum)...)
+}
+
+// splitHistoryKeySuffix decodes the block and tran numbers following the prefix of a history key.
+// The last return value is false if the suffix is not an encoded block and tran number, which is the
+// case for the history of a different key that shares the prefix (i.e., a key containing the separator)
+func splitHistoryKeySuffix(suffix []byte) (uint64, uint64, bool) {
+	blockNum, blockNumBytes, ok := decodeVarUint64(suffix)
+	if !ok {
+		return 0, 0, false
+	}
+	tranNum, tranNumBytes, ok := decodeVarUint64(suffix[blockNumBytes:])
+	if !ok || blockNumBytes+tranNumBytes != len(suffix) {
+		return 0, 0, false
+	}
+	return blockNum, tranNum, true
+}
+
+func decodeVarUint64(b []byte) (uint64, int, bool) {
+	if len(b) == 0 || int(b[0]) > 8 || int(b[0])+1 > len(b) {
+		return 0, 0, false
+	}
+	num, numBytes := util.DecodeOrderPreservingVarUint64(b)
+	return num, numBytes, true
+}
+
+type levelHistScanner struct {
+	prefix []byte
+	dbItr  *leveldbhelper.Iterator
+}
+
+type historyEntry struct {
+	blockNum uint64
+	tranNum  uint64
+	txID     string
+}
+
+func (scanner *levelHistScanner) next() *historyEntry {
+	for scanner.dbItr.Next() {
+		blockNum, tranNum, ok := splitHistoryKeySuffix(scanner.dbItr.Key()[len(scanner.prefix):])
+		if !ok {
+			continue
+		}
+		return &historyEntry{blockNum, tranNum, string(scanner.dbItr.Value())}
+	}
+	return nil
+}
+
+func (scanner *levelHistScanner) close() {
+	scanner.dbItr.Release()
+}
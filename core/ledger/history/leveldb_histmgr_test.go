@@ -0,0 +1,161 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwset"
+	"github.com/hyperledger/fabric/core/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+)
+
+func TestLevelDBHistorySavepoint(t *testing.T) {
+	env := newTestEnvHistoryLevelDB(t)
+	defer env.cleanup()
+	histMgr := env.getHistMgr("ledger1")
+
+	blockNum, err := histMgr.GetBlockNumFromSavepoint()
+	testutil.AssertNoError(t, err, "Error when reading the savepoint")
+	testutil.AssertEquals(t, blockNum, uint64(0))
+
+	bg := testutil.NewBlockGenerator(t)
+	env.commit(t, histMgr, bg.NextBlock([][]byte{writeSet(t, "ns1", "key1", []byte("value1"))}, false))
+	env.commit(t, histMgr, bg.NextBlock([][]byte{writeSet(t, "ns1", "key1", []byte("value2"))}, false))
+
+	blockNum, err = histMgr.GetBlockNumFromSavepoint()
+	testutil.AssertNoError(t, err, "Error when reading the savepoint")
+	testutil.AssertEquals(t, blockNum, uint64(2))
+
+	// the savepoint is maintained per ledger
+	blockNum, err = env.getHistMgr("ledger2").GetBlockNumFromSavepoint()
+	testutil.AssertNoError(t, err, "Error when reading the savepoint")
+	testutil.AssertEquals(t, blockNum, uint64(0))
+}
+
+func TestLevelDBHistoryQuery(t *testing.T) {
+	env := newTestEnvHistoryLevelDB(t)
+	defer env.cleanup()
+	histMgr := env.getHistMgr("ledger1")
+
+	bg := testutil.NewBlockGenerator(t)
+	block1 := bg.NextBlock([][]byte{
+		writeSet(t, "ns1", "key1", []byte("value1")),
+		writeSet(t, "ns1", "key10", []byte("value10")),
+	}, false)
+	env.commit(t, histMgr, block1)
+
+	// the second transaction of block2 is invalid and is not part of the history
+	block2 := bg.NextBlock([][]byte{
+		writeSet(t, "ns1", "key1", nil),
+		writeSet(t, "ns1", "key1", []byte("invalid")),
+	}, false)
+	txsFilter := util.NewTxValidationFlags(len(block2.Data.Data))
+	txsFilter.SetFlag(1, pb.TxValidationCode_MVCC_READ_CONFLICT)
+	util.SetTxValidationFlags(block2, txsFilter)
+	env.commit(t, histMgr, block2)
+
+	block3 := bg.NextBlock([][]byte{
+		writeSet(t, "ns1", "key1", []byte("value3")),
+		writeSet(t, "ns2", "key1", []byte("otherNamespace")),
+	}, false)
+	env.commit(t, histMgr, block3)
+
+	expectedTxIDs := []string{txID(t, block1, 0), txID(t, block2, 0), txID(t, block3, 0)}
+	expectedValues := [][]byte{[]byte("value1"), nil, []byte("value3")}
+
+	qe, err := histMgr.NewHistoryQueryExecutor()
+	testutil.AssertNoError(t, err, "Error when creating the history query executor")
+
+	// without values and transactions
+	kms := getKeyModifications(t, qe, "ns1", "key1", false, false)
+	testutil.AssertEquals(t, len(kms), 3)
+	for i, km := range kms {
+		testutil.AssertEquals(t, km.TxID, expectedTxIDs[i])
+		testutil.AssertNil(t, km.Value)
+		testutil.AssertNil(t, km.Transaction)
+	}
+
+	// with values and transactions
+	kms = getKeyModifications(t, qe, "ns1", "key1", true, true)
+	testutil.AssertEquals(t, len(kms), 3)
+	for i, km := range kms {
+		testutil.AssertEquals(t, km.TxID, expectedTxIDs[i])
+		testutil.AssertEquals(t, km.Value, expectedValues[i])
+		testutil.AssertNotNil(t, km.Transaction)
+	}
+
+	kms = getKeyModifications(t, qe, "ns1", "key10", true, false)
+	testutil.AssertEquals(t, len(kms), 1)
+	testutil.AssertEquals(t, kms[0].Value, []byte("value10"))
+
+	kms = getKeyModifications(t, qe, "ns2", "key1", true, false)
+	testutil.AssertEquals(t, len(kms), 1)
+	testutil.AssertEquals(t, kms[0].Value, []byte("otherNamespace"))
+
+	kms = getKeyModifications(t, qe, "ns1", "key2", true, false)
+	testutil.AssertEquals(t, len(kms), 0)
+}
+
+func TestHistoryKeySuffix(t *testing.T) {
+	historyKey := constructHistoryKey("ns1", "key1", 300, 2)
+	prefix := constructHistoryKeyPrefix("ns1", "key1")
+	blockNum, tranNum, ok := splitHistoryKeySuffix(historyKey[len(prefix):])
+	testutil.AssertEquals(t, ok, true)
+	testutil.AssertEquals(t, blockNum, uint64(300))
+	testutil.AssertEquals(t, tranNum, uint64(2))
+
+	// the history of the key "key1\x00key2" shares the prefix of "key1"
+	historyKey = constructHistoryKey("ns1", "key1\x00key2", 1, 1)
+	_, _, ok = splitHistoryKeySuffix(historyKey[len(prefix):])
+	testutil.AssertEquals(t, ok, false)
+}
+
+func writeSet(t *testing.T, ns string, key string, value []byte) []byte {
+	rwSet := rwset.NewRWSet()
+	rwSet.AddToWriteSet(ns, key, value)
+	simulationResults, err := rwSet.GetTxReadWriteSet().Marshal()
+	testutil.AssertNoError(t, err, "Error when marshalling the read-write set")
+	return simulationResults
+}
+
+func txID(t *testing.T, block *common.Block, txIndex int) string {
+	env, err := putils.GetEnvelopeFromBlock(block.Data.Data[txIndex])
+	testutil.AssertNoError(t, err, "Error when extracting the envelope")
+	payload, err := putils.GetPayload(env)
+	testutil.AssertNoError(t, err, "Error when extracting the payload")
+	return payload.Header.ChainHeader.TxID
+}
+
+func getKeyModifications(t *testing.T, qe ledger.HistoryQueryExecutor, ns string, key string, includeValues bool, includeTransactions bool) []*ledger.KeyModification {
+	itr, err := qe.GetTransactionsForKey(ns, key, includeValues, includeTransactions)
+	testutil.AssertNoError(t, err, "Error when querying the history")
+	defer itr.Close()
+	kms := []*ledger.KeyModification{}
+	for {
+		result, err := itr.Next()
+		testutil.AssertNoError(t, err, "Error when iterating over the history")
+		if result == nil {
+			return kms
+		}
+		kms = append(kms, result.(*ledger.KeyModification))
+	}
+}
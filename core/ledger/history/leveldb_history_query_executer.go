@@ -0,0 +1,72 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import "github.com/hyperledger/fabric/core/ledger"
+
+// LevelDBHistQueryExecutor is a query executor used in `LevelDBHistMgr`
+type LevelDBHistQueryExecutor struct {
+	histmgr *LevelDBHistMgr
+}
+
+// GetTransactionsForKey implements method in interface `ledger.HistoryQueryExecutor`
+// The modifications are returned in the order in which they were committed. The values and
+// the transactions are retrieved from the block store only if requested
+func (q *LevelDBHistQueryExecutor) GetTransactionsForKey(namespace string, key string, includeValues bool, includeTransactions bool) (ledger.ResultsIterator, error) {
+	scanner := q.histmgr.getTransactionsForNsKey(namespace, key)
+	return &levelHistoryItr{q.histmgr, scanner, namespace, key, includeValues, includeTransactions}, nil
+}
+
+type levelHistoryItr struct {
+	histmgr             *LevelDBHistMgr
+	scanner             *levelHistScanner
+	namespace           string
+	key                 string
+	includeValues       bool
+	includeTransactions bool
+}
+
+// Next implements Next() method in ledger.ResultsIterator
+func (itr *levelHistoryItr) Next() (ledger.QueryResult, error) {
+	entry := itr.scanner.next()
+	if entry == nil {
+		return nil, nil
+	}
+	keyModification := &ledger.KeyModification{TxID: entry.txID}
+	if !itr.includeValues && !itr.includeTransactions {
+		return keyModification, nil
+	}
+
+	tx, err := itr.histmgr.getTransaction(entry.blockNum, entry.tranNum)
+	if err != nil {
+		return nil, err
+	}
+	if itr.includeValues {
+		if keyModification.Value, err = getWriteValue(tx, itr.namespace, itr.key); err != nil {
+			return nil, err
+		}
+	}
+	if itr.includeTransactions {
+		keyModification.Transaction = tx
+	}
+	return keyModification, nil
+}
+
+// Close implements Close() method in ledger.ResultsIterator
+func (itr *levelHistoryItr) Close() {
+	itr.scanner.close()
+}
@@ -17,10 +17,16 @@ limitations under the License.
 package history
 
 import (
+	"os"
 	"testing"
 
+	"github.com/hyperledger/fabric/core/ledger/blkstorage"
+	"github.com/hyperledger/fabric/core/ledger/blkstorage/fsblkstorage"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/testutil"
 	"github.com/hyperledger/fabric/core/ledger/util/couchdb"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/spf13/viper"
 )
 
 //Complex setup to test the use of couch in ledger
@@ -53,3 +59,42 @@ func (env *testEnvHistoryCouchDB) cleanup() {
 		couchDB.DropDatabase()
 	}
 }
+
+//testEnvHistoryLevelDB provides a leveldb history database along with the block store it refers to
+type testEnvHistoryLevelDB struct {
+	blockStoreProvider blkstorage.BlockStoreProvider
+	historyDBProvider  *LevelDBHistMgrProvider
+}
+
+func newTestEnvHistoryLevelDB(t testing.TB) *testEnvHistoryLevelDB {
+	viper.Set("peer.fileSystemPath", "/tmp/fabric/ledgertests/history")
+	os.RemoveAll(ledgerconfig.GetRootPath())
+	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: []blkstorage.IndexableAttr{
+		blkstorage.IndexableAttrBlockNum,
+		blkstorage.IndexableAttrBlockNumTranNum,
+	}}
+	blockStoreProvider := fsblkstorage.NewProvider(
+		fsblkstorage.NewConf(ledgerconfig.GetBlockStorePath(), ledgerconfig.GetMaxBlockfileSize()),
+		indexConfig)
+	return &testEnvHistoryLevelDB{blockStoreProvider, NewLevelDBHistMgrProvider()}
+}
+
+func (env *testEnvHistoryLevelDB) getHistMgr(ledgerID string) *LevelDBHistMgr {
+	blockStore, err := env.blockStoreProvider.OpenBlockStore(ledgerID)
+	if err != nil {
+		panic(err)
+	}
+	return env.historyDBProvider.GetHistMgr(ledgerID, blockStore)
+}
+
+//commit adds the block to the block store as well as to the history database
+func (env *testEnvHistoryLevelDB) commit(t testing.TB, histMgr *LevelDBHistMgr, block *common.Block) {
+	testutil.AssertNoError(t, histMgr.blockStore.AddBlock(block), "Error when adding the block to the block store")
+	testutil.AssertNoError(t, histMgr.Commit(block), "Error when committing the block to the history database")
+}
+
+func (env *testEnvHistoryLevelDB) cleanup() {
+	env.historyDBProvider.Close()
+	env.blockStoreProvider.Close()
+	os.RemoveAll(ledgerconfig.GetRootPath())
+}
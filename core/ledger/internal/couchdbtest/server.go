@@ -0,0 +1,585 @@
+// +build couchdbfake
+
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package couchdbtest
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Server is an in-memory stand-in for a CouchDB server, for running the tests of the couchdb client and
+// the couchdb backed state database without a CouchDB instance. It supports the part of the CouchDB REST
+// API that is used by the couchdb client. The queries (`_find`) support only the $and, $or, $eq, $ne, $gt,
+// $gte, $lt, $lte and $exists operators. The documents are returned in the order of the bytes of their
+// ids, which matches the raw collation CouchDB applies to the ids in `_all_docs` for UTF-8 ids; a query
+// returns the documents in the same order, whereas CouchDB does not guarantee an order without a sort.
+// The sort of a query is ignored, so only a sort on the ids is supported.
+// The Server is only built with the couchdbfake tag, e.g. `go test -tags couchdbfake ./core/ledger/...`
+type Server struct {
+	server    *httptest.Server
+	mux       sync.Mutex
+	databases map[string]*testDatabase
+}
+
+// batchUpdateResponse is the response for a document in a `_bulk_docs` request
+type batchUpdateResponse struct {
+	ID     string `json:"id"`
+	Error  string `json:"error"`
+	Reason string `json:"reason"`
+	Ok     bool   `json:"ok"`
+	Rev    string `json:"rev"`
+}
+
+// attachment is an attachment of a document, with its data inline
+type attachment struct {
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"`
+}
+
+type testDatabase struct {
+	updateSeq int
+	docs      map[string]*testDoc
+}
+
+type testDoc struct {
+	generation  int
+	rev         string
+	deleted     bool
+	fields      map[string]json.RawMessage
+	attachments map[string]*attachment
+}
+
+// NewServer starts a Server on a local port
+func NewServer() *Server {
+	testServer := &Server{databases: make(map[string]*testDatabase)}
+	testServer.server = httptest.NewServer(http.HandlerFunc(testServer.handle))
+	return testServer
+}
+
+// Address returns the address (host:port) of the server, to be used as the `couchDBAddress`
+func (testServer *Server) Address() string {
+	serverURL, _ := url.Parse(testServer.server.URL)
+	return serverURL.Host
+}
+
+// Close stops the server
+func (testServer *Server) Close() {
+	testServer.server.Close()
+}
+
+func (testServer *Server) handle(w http.ResponseWriter, r *http.Request) {
+	testServer.mux.Lock()
+	defer testServer.mux.Unlock()
+
+	pathElements := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+	dbName := pathElements[0]
+	if dbName == "" {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"couchdb": "Welcome"})
+		return
+	}
+	db := testServer.databases[dbName]
+
+	if len(pathElements) == 1 {
+		switch {
+		case r.Method == http.MethodPut && db == nil:
+			testServer.databases[dbName] = &testDatabase{docs: make(map[string]*testDoc)}
+			writeJSON(w, http.StatusCreated, map[string]interface{}{"ok": true})
+		case r.Method == http.MethodPut:
+			writeError(w, http.StatusPreconditionFailed, "file_exists", "The database could not be created, the file already exists.")
+		case db == nil:
+			writeError(w, http.StatusNotFound, "not_found", "Database does not exist.")
+		case r.Method == http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]interface{}{"db_name": dbName, "update_seq": db.updateSeqString(), "doc_count": db.docCount()})
+		case r.Method == http.MethodDelete:
+			delete(testServer.databases, dbName)
+			writeJSON(w, http.StatusOK, map[string]interface{}{"ok": true})
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Only GET,PUT,DELETE allowed")
+		}
+		return
+	}
+
+	if db == nil {
+		writeError(w, http.StatusNotFound, "not_found", "Database does not exist.")
+		return
+	}
+	switch docID := pathElements[1]; {
+	case docID == "_ensure_full_commit" && r.Method == http.MethodPost:
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"ok": true, "instance_start_time": "0"})
+	case docID == "_all_docs" && r.Method == http.MethodGet:
+		db.handleAllDocsRange(w, r)
+	case docID == "_all_docs" && r.Method == http.MethodPost:
+		db.handleAllDocsKeys(w, r)
+	case docID == "_bulk_docs" && r.Method == http.MethodPost:
+		db.handleBulkDocs(w, r)
+	case docID == "_find" && r.Method == http.MethodPost:
+		db.handleFind(w, r)
+	case strings.HasPrefix(docID, "_"):
+		writeError(w, http.StatusNotImplemented, "not_implemented", "Not supported by the test server")
+	case r.Method == http.MethodGet:
+		db.handleGetDoc(w, r, docID)
+	case r.Method == http.MethodPut:
+		db.handlePutDoc(w, r, docID)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Only GET,PUT allowed")
+	}
+}
+
+func (db *testDatabase) handleAllDocsRange(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var startKey, endKey string
+	if query.Get("startkey") != "" {
+		if err := json.Unmarshal([]byte(query.Get("startkey")), &startKey); err != nil {
+			writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+			return
+		}
+	}
+	if query.Get("endkey") != "" {
+		if err := json.Unmarshal([]byte(query.Get("endkey")), &endKey); err != nil {
+			writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+			return
+		}
+	}
+	inclusiveEnd := query.Get("inclusive_end") != "false"
+	skip, _ := strconv.Atoi(query.Get("skip"))
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil {
+		limit = -1
+	}
+
+	rows := []map[string]interface{}{}
+	for _, id := range db.sortedIDs() {
+		if id < startKey {
+			continue
+		}
+		if query.Get("endkey") != "" && (id > endKey || (id == endKey && !inclusiveEnd)) {
+			break
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		if limit >= 0 && len(rows) == limit {
+			break
+		}
+		rows = append(rows, db.row(id, query.Get("include_docs") == "true", query.Get("attachments") == "true"))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"total_rows": db.docCount(), "offset": 0, "rows": rows})
+}
+
+func (db *testDatabase) handleAllDocsKeys(w http.ResponseWriter, r *http.Request) {
+	request := &struct {
+		Keys []string `json:"keys"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+	query := r.URL.Query()
+	rows := []map[string]interface{}{}
+	for _, key := range request.Keys {
+		doc, ok := db.docs[key]
+		switch {
+		case !ok:
+			rows = append(rows, map[string]interface{}{"key": key, "error": "not_found"})
+		case doc.deleted:
+			rows = append(rows, map[string]interface{}{"id": key, "key": key,
+				"value": map[string]interface{}{"rev": doc.rev, "deleted": true}, "doc": nil})
+		default:
+			rows = append(rows, db.row(key, query.Get("include_docs") == "true", query.Get("attachments") == "true"))
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"total_rows": db.docCount(), "rows": rows})
+}
+
+func (db *testDatabase) handleBulkDocs(w http.ResponseWriter, r *http.Request) {
+	request := &struct {
+		Docs []map[string]json.RawMessage `json:"docs"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+	responses := []*batchUpdateResponse{}
+	for _, fields := range request.Docs {
+		var id, rev string
+		var deleted bool
+		json.Unmarshal(fields["_id"], &id)
+		json.Unmarshal(fields["_rev"], &rev)
+		json.Unmarshal(fields["_deleted"], &deleted)
+		attachments := make(map[string]*attachment)
+		if fields["_attachments"] != nil {
+			if err := json.Unmarshal(fields["_attachments"], &attachments); err != nil {
+				responses = append(responses, &batchUpdateResponse{ID: id, Error: "bad_request", Reason: err.Error()})
+				continue
+			}
+		}
+		newRev, err := db.update(id, rev, deleted, fields, attachments)
+		if err != nil {
+			responses = append(responses, &batchUpdateResponse{ID: id, Error: "conflict", Reason: err.Error()})
+			continue
+		}
+		responses = append(responses, &batchUpdateResponse{ID: id, Ok: true, Rev: newRev})
+	}
+	writeJSON(w, http.StatusCreated, responses)
+}
+
+func (db *testDatabase) handleFind(w http.ResponseWriter, r *http.Request) {
+	request := &struct {
+		Selector map[string]interface{} `json:"selector"`
+		Limit    *int                   `json:"limit"`
+		Skip     int                    `json:"skip"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+	if request.Selector == nil {
+		writeError(w, http.StatusBadRequest, "missing_required_key", "Missing required key: selector")
+		return
+	}
+	// the default limit of CouchDB
+	limit := 25
+	if request.Limit != nil {
+		limit = *request.Limit
+	}
+
+	docs := []map[string]interface{}{}
+	skip := request.Skip
+	for _, id := range db.sortedIDs() {
+		if len(docs) == limit {
+			break
+		}
+		fields := map[string]interface{}{"_id": id, "_rev": db.docs[id].rev}
+		for field, value := range db.docs[id].fields {
+			var decodedValue interface{}
+			json.Unmarshal(value, &decodedValue)
+			fields[field] = decodedValue
+		}
+		matches, err := matchSelector(request.Selector, fields)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_operator", err.Error())
+			return
+		}
+		if !matches {
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		docs = append(docs, db.docJSON(id, false))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"docs": docs})
+}
+
+func (db *testDatabase) handleGetDoc(w http.ResponseWriter, r *http.Request, id string) {
+	doc, ok := db.docs[id]
+	if !ok || doc.deleted {
+		writeError(w, http.StatusNotFound, "not_found", "missing")
+		return
+	}
+	w.Header().Set("Etag", strconv.Quote(doc.rev))
+	withAttachments := r.URL.Query().Get("attachments") == "true"
+	if !withAttachments || len(doc.attachments) == 0 || !strings.Contains(r.Header.Get("Accept"), "multipart/related") {
+		writeJSON(w, http.StatusOK, db.docJSON(id, withAttachments))
+		return
+	}
+
+	// the document is followed by its attachments, in a multipart response
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Type", "application/json")
+	part, _ := writer.CreatePart(header)
+	json.NewEncoder(part).Encode(db.docJSON(id, false))
+	for _, name := range sortedAttachmentNames(doc.attachments) {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+		header.Set("Content-Type", doc.attachments[name].ContentType)
+		part, _ := writer.CreatePart(header)
+		part.Write(doc.attachments[name].Data)
+	}
+	writer.Close()
+	w.Header().Set("Content-Type", "multipart/related; boundary=\""+writer.Boundary()+"\"")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body.Bytes())
+}
+
+func (db *testDatabase) handlePutDoc(w http.ResponseWriter, r *http.Request, id string) {
+	fields := make(map[string]json.RawMessage)
+	attachments := make(map[string]*attachment)
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		// the first part is the document, and the data of the attachments follow
+		// in the order of the names of the attachments in the document
+		reader := multipart.NewReader(r.Body, params["boundary"])
+		part, err := reader.NextPart()
+		if err == nil {
+			err = json.NewDecoder(part).Decode(&fields)
+		}
+		if err == nil && fields["_attachments"] != nil {
+			err = json.Unmarshal(fields["_attachments"], &attachments)
+		}
+		for _, name := range sortedAttachmentNames(attachments) {
+			if err != nil {
+				break
+			}
+			if part, err = reader.NextPart(); err == nil {
+				attachments[name].Data, err = ioutil.ReadAll(part)
+			}
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+			return
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
+	rev := r.Header.Get("If-Match")
+	if rev == "" {
+		json.Unmarshal(fields["_rev"], &rev)
+	}
+	newRev, err := db.update(id, rev, false, fields, attachments)
+	if err != nil {
+		writeError(w, http.StatusConflict, "conflict", err.Error())
+		return
+	}
+	w.Header().Set("Etag", strconv.Quote(newRev))
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"ok": true, "id": id, "rev": newRev})
+}
+
+// update saves, updates or deletes a document. The given revision needs to be
+// the current revision of the document, unless the document does not exist
+func (db *testDatabase) update(id, rev string, deleted bool, fields map[string]json.RawMessage,
+	attachments map[string]*attachment) (string, error) {
+	if id == "" {
+		return "", fmt.Errorf("Document id missing.")
+	}
+	doc, ok := db.docs[id]
+	if !ok {
+		doc = &testDoc{deleted: true}
+	}
+	if (doc.deleted && rev != "" && rev != doc.rev) || (!doc.deleted && rev != doc.rev) {
+		return "", fmt.Errorf("Document update conflict.")
+	}
+	if !ok && deleted {
+		return "", fmt.Errorf("Document update conflict.")
+	}
+
+	docFields := make(map[string]json.RawMessage)
+	for field, value := range fields {
+		if !strings.HasPrefix(field, "_") {
+			docFields[field] = value
+		}
+	}
+	db.updateSeq++
+	doc.generation++
+	doc.rev = fmt.Sprintf("%d-%x", doc.generation, md5.Sum([]byte(fmt.Sprintf("%s-%d", id, db.updateSeq))))
+	doc.deleted = deleted
+	doc.fields = docFields
+	doc.attachments = attachments
+	db.docs[id] = doc
+	return doc.rev, nil
+}
+
+func (db *testDatabase) row(id string, includeDoc, withAttachments bool) map[string]interface{} {
+	row := map[string]interface{}{"id": id, "key": id, "value": map[string]interface{}{"rev": db.docs[id].rev}}
+	if includeDoc {
+		row["doc"] = db.docJSON(id, withAttachments)
+	}
+	return row
+}
+
+// docJSON returns the document in the form returned by CouchDB, with the data of
+// the attachments inline or with stubs for the attachments
+func (db *testDatabase) docJSON(id string, withAttachments bool) map[string]interface{} {
+	doc := db.docs[id]
+	docJSON := make(map[string]interface{})
+	for field, value := range doc.fields {
+		docJSON[field] = value
+	}
+	docJSON["_id"] = id
+	docJSON["_rev"] = doc.rev
+	if len(doc.attachments) > 0 {
+		attachments := make(map[string]interface{})
+		for name, attachment := range doc.attachments {
+			if withAttachments {
+				attachments[name] = map[string]interface{}{
+					"content_type": attachment.ContentType, "revpos": doc.generation,
+					"data": base64.StdEncoding.EncodeToString(attachment.Data)}
+			} else {
+				attachments[name] = map[string]interface{}{
+					"content_type": attachment.ContentType, "revpos": doc.generation,
+					"length": len(attachment.Data), "stub": true}
+			}
+		}
+		docJSON["_attachments"] = attachments
+	}
+	return docJSON
+}
+
+func (db *testDatabase) sortedIDs() []string {
+	ids := []string{}
+	for id, doc := range db.docs {
+		if !doc.deleted {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (db *testDatabase) docCount() int {
+	return len(db.sortedIDs())
+}
+
+func (db *testDatabase) updateSeqString() string {
+	return fmt.Sprintf("%d-teststandin", db.updateSeq)
+}
+
+func matchSelector(selector map[string]interface{}, fields map[string]interface{}) (bool, error) {
+	for field, condition := range selector {
+		switch field {
+		case "$and", "$or":
+			subSelectors, ok := condition.([]interface{})
+			if !ok {
+				return false, fmt.Errorf("%s requires an array of selectors", field)
+			}
+			matchesAny := false
+			for _, subSelector := range subSelectors {
+				subSelectorMap, ok := subSelector.(map[string]interface{})
+				if !ok {
+					return false, fmt.Errorf("%s requires an array of selectors", field)
+				}
+				matches, err := matchSelector(subSelectorMap, fields)
+				if err != nil {
+					return false, err
+				}
+				if !matches && field == "$and" {
+					return false, nil
+				}
+				matchesAny = matchesAny || matches
+			}
+			if field == "$or" && !matchesAny {
+				return false, nil
+			}
+		default:
+			if strings.HasPrefix(field, "$") {
+				return false, fmt.Errorf("Operator %s not supported by the test server", field)
+			}
+			value, exists := fields[field]
+			matches, err := matchCondition(condition, value, exists)
+			if err != nil || !matches {
+				return false, err
+			}
+		}
+	}
+	return true, nil
+}
+
+func matchCondition(condition interface{}, value interface{}, exists bool) (bool, error) {
+	operators, ok := condition.(map[string]interface{})
+	if !ok {
+		return exists && reflect.DeepEqual(value, condition), nil
+	}
+	for operator, operand := range operators {
+		var matches bool
+		switch operator {
+		case "$eq":
+			matches = exists && reflect.DeepEqual(value, operand)
+		case "$ne":
+			matches = exists && !reflect.DeepEqual(value, operand)
+		case "$exists":
+			matches = exists == (operand == true)
+		case "$gt", "$gte", "$lt", "$lte":
+			comparison, comparable := compareValues(value, operand)
+			matches = exists && comparable &&
+				((operator == "$gt" && comparison > 0) || (operator == "$gte" && comparison >= 0) ||
+					(operator == "$lt" && comparison < 0) || (operator == "$lte" && comparison <= 0))
+		default:
+			return false, fmt.Errorf("Operator %s not supported by the test server", operator)
+		}
+		if !matches {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// compareValues compares two strings or two numbers
+func compareValues(value1 interface{}, value2 interface{}) (int, bool) {
+	switch v1 := value1.(type) {
+	case string:
+		if v2, ok := value2.(string); ok {
+			return strings.Compare(v1, v2), true
+		}
+	case float64:
+		if v2, ok := value2.(float64); ok {
+			switch {
+			case v1 < v2:
+				return -1, true
+			case v1 > v2:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	return 0, false
+}
+
+func sortedAttachmentNames(attachments map[string]*attachment) []string {
+	names := []string{}
+	for name := range attachments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, errorName, reason string) {
+	writeJSON(w, statusCode, map[string]interface{}{"error": errorName, "reason": reason})
+}
@@ -148,11 +148,11 @@ func tryDoubleSpend() {
 func printBlocksInfo(block *common.Block) {
 	logger.Debug("Entering printBlocksInfo()")
 	// Read invalid transactions filter
-	txsFltr := util.NewFilterBitArrayFromBytes(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	txsFltr := util.GetTxValidationFlags(block.Metadata)
 	numOfInvalid := 0
 	// Count how many transaction indeed invalid
 	for i := 0; i < len(block.Data.Data); i++ {
-		if txsFltr.IsSet(uint(i)) {
+		if txsFltr.IsInvalid(i) {
 			numOfInvalid++
 		}
 	}
@@ -0,0 +1,184 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package example
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"os"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/protos/common"
+	logging "github.com/op/go-logging"
+	"github.com/spf13/viper"
+)
+
+const (
+	benchLedgerID    = "BenchLedger"
+	benchRootPath    = "/tmp/fabric/ledgertests/example"
+	benchNumAccounts = 2000
+	benchNumBlocks   = 10
+	benchTxsPerBlock = 200
+)
+
+// benchWorkload holds the blocks committed by the benchmarks, along with the transaction
+// filters and the state hash that result from committing the blocks with the serial validation
+type benchWorkload struct {
+	blocks     []*common.Block
+	txsFilters [][]byte
+	stateHash  []byte
+}
+
+var workload *benchWorkload
+var workloadErr error
+var workloadOnce sync.Once
+
+// BenchmarkCommitSerialValidation commits the blocks of fund transfers validating one transaction at a time
+func BenchmarkCommitSerialValidation(b *testing.B) {
+	benchmarkCommit(b, 1)
+}
+
+// BenchmarkCommitParallelValidation commits the same blocks validating the transactions concurrently,
+// and checks that the transaction filters and the state hash are identical to the ones of the serial validation
+func BenchmarkCommitParallelValidation(b *testing.B) {
+	benchmarkCommit(b, runtime.NumCPU())
+}
+
+func benchmarkCommit(b *testing.B, parallelism int) {
+	logging.SetLevel(logging.WARNING, "")
+	workloadOnce.Do(func() {
+		workload, workloadErr = constructWorkload()
+	})
+	if workloadErr != nil {
+		b.Fatalf("Error while constructing the workload: %s", workloadErr)
+	}
+	b.StopTimer()
+	for i := 0; i < b.N; i++ {
+		provider, l, err := createBenchLedger(fmt.Sprintf("%s/parallelism%d", benchRootPath, parallelism), parallelism)
+		if err != nil {
+			b.Fatalf("Error while creating the ledger: %s", err)
+		}
+		blocks := make([]*common.Block, len(workload.blocks))
+		for j, block := range workload.blocks {
+			blocks[j] = proto.Clone(block).(*common.Block)
+		}
+
+		b.StartTimer()
+		for _, block := range blocks {
+			if err := l.Commit(block); err != nil {
+				b.Fatalf("Error while committing block %d: %s", block.Header.Number, err)
+			}
+		}
+		b.StopTimer()
+
+		for j, block := range blocks {
+			if !bytes.Equal(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_VALIDATION_CODES], workload.txsFilters[j]) {
+				b.Fatalf("Transaction filter of block %d differs from the one of the serial validation", block.Header.Number)
+			}
+		}
+		stateHash, err := l.GetStateHash(blocks[len(blocks)-1].Header.Number)
+		if err != nil {
+			b.Fatalf("Error while getting the state hash: %s", err)
+		}
+		if !bytes.Equal(stateHash, workload.stateHash) {
+			b.Fatalf("State hash %x differs from the state hash %x of the serial validation", stateHash, workload.stateHash)
+		}
+		l.Close()
+		provider.Close()
+	}
+	os.RemoveAll(benchRootPath)
+}
+
+// constructWorkload simulates blocks of fund transfers between random accounts. As the transfers of a block
+// are simulated before the block is committed, some of the transfers conflict with the preceding ones in the block
+func constructWorkload() (*benchWorkload, error) {
+	provider, l, err := createBenchLedger(benchRootPath+"/reference", 1)
+	if err != nil {
+		return nil, err
+	}
+	defer provider.Close()
+	defer l.Close()
+
+	w := &benchWorkload{}
+	app := ConstructAppInstance(l)
+	consenter := ConstructConsenter()
+	commit := func(block *common.Block) error {
+		w.blocks = append(w.blocks, proto.Clone(block).(*common.Block))
+		if err := l.Commit(block); err != nil {
+			return err
+		}
+		w.txsFilters = append(w.txsFilters, block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_VALIDATION_CODES])
+		return nil
+	}
+
+	accounts := make([]string, benchNumAccounts)
+	initialBalances := make(map[string]int)
+	for i := range accounts {
+		accounts[i] = fmt.Sprintf("account%d", i)
+		initialBalances[accounts[i]] = 1000
+	}
+	tx, err := app.Init(initialBalances)
+	if err != nil {
+		return nil, err
+	}
+	if err = commit(consenter.ConstructBlock(tx)); err != nil {
+		return nil, err
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < benchNumBlocks; i++ {
+		txs := make([]*common.Envelope, benchTxsPerBlock)
+		for j := range txs {
+			from := rnd.Intn(benchNumAccounts)
+			to := (from + 1 + rnd.Intn(benchNumAccounts-1)) % benchNumAccounts
+			if txs[j], err = app.TransferFunds(accounts[from], accounts[to], 1); err != nil {
+				return nil, err
+			}
+		}
+		if err = commit(consenter.ConstructBlock(txs...)); err != nil {
+			return nil, err
+		}
+	}
+
+	lastBlock := w.blocks[len(w.blocks)-1]
+	if w.stateHash, err = l.GetStateHash(lastBlock.Header.Number); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func createBenchLedger(path string, parallelism int) (ledger.PeerLedgerProvider, ledger.PeerLedger, error) {
+	os.RemoveAll(path)
+	viper.Set("peer.fileSystemPath", path)
+	viper.Set("ledger.state.validationParallelism", parallelism)
+	provider, err := kvledger.NewProvider()
+	if err != nil {
+		return nil, nil, err
+	}
+	l, err := provider.Create(benchLedgerID)
+	if err != nil {
+		provider.Close()
+		return nil, nil, err
+	}
+	return provider, l, nil
+}
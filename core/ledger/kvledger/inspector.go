@@ -0,0 +1,259 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvledger
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/blkstorage"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statehash"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr/lockbasedtxmgr"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// Savepoints describes how far the block storage, the state database and the history database of a ledger have progressed
+type Savepoints struct {
+	BlockStorageHeight uint64
+	StateDB            uint64
+	HistoryDBEnabled   bool
+	HistoryDB          uint64
+}
+
+// Inspector provides the offline inspection and repair of the ledgers stored under `ledgerconfig.GetRootPath()`.
+// The databases of the ledgers are opened without the recovery performed when a ledger is opened by the peer,
+// so that the savepoints are reported as they are found. The block stores are opened read-only, so that their index
+// is neither caught up with the block files nor otherwise written, except for rebuilding the index. An inspector is
+// not to be used while a peer runs on the same ledgers
+type Inspector struct {
+	provider    *Provider
+	blockStores map[string]blkstorage.BlockStore
+	vDBs        map[string]*statehash.VersionedDB
+}
+
+// NewInspector constructs an `Inspector` for the ledgers configured for the peer
+func NewInspector() (*Inspector, error) {
+	provider, err := NewProvider()
+	if err != nil {
+		return nil, err
+	}
+	return &Inspector{provider.(*Provider), make(map[string]blkstorage.BlockStore), make(map[string]*statehash.VersionedDB)}, nil
+}
+
+// List returns the ids of the existing ledgers
+func (inspector *Inspector) List() ([]string, error) {
+	return inspector.provider.List()
+}
+
+// GetBlockchainInfo returns the blockchain info of the given ledger
+func (inspector *Inspector) GetBlockchainInfo(ledgerID string) (*pb.BlockchainInfo, error) {
+	blockStore, err := inspector.openBlockStore(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	return blockStore.GetBlockchainInfo()
+}
+
+// GetBlockByNumber returns the block with the given number from the given ledger
+func (inspector *Inspector) GetBlockByNumber(ledgerID string, blockNum uint64) (*common.Block, error) {
+	blockStore, err := inspector.openBlockStore(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	return blockStore.RetrieveBlockByNumber(blockNum)
+}
+
+// GetTransactionByID returns the transaction with the given id from the given ledger, along with its validation code
+func (inspector *Inspector) GetTransactionByID(ledgerID string, txID string) (*pb.ProcessedTransaction, error) {
+	blockStore, err := inspector.openBlockStore(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	return retrieveProcessedTx(blockStore, txID)
+}
+
+// GetSavepoints returns the height of the block storage and the savepoints of the databases of the given ledger
+func (inspector *Inspector) GetSavepoints(ledgerID string) (*Savepoints, error) {
+	blockStore, err := inspector.openBlockStore(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	info, err := blockStore.GetBlockchainInfo()
+	if err != nil {
+		return nil, err
+	}
+	savepoints := &Savepoints{BlockStorageHeight: info.Height, HistoryDBEnabled: ledgerconfig.IsHistoryDBEnabled()}
+	//The savepoints are recorded along with the first block, hence there is none for an empty ledger
+	if info.Height == 0 {
+		return savepoints, nil
+	}
+	vDB, err := inspector.openVersionedDB(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	if savepoints.StateDB, err = lockbasedtxmgr.NewLockBasedTxMgr(vDB).GetBlockNumFromSavepoint(); err != nil {
+		return nil, err
+	}
+	if savepoints.HistoryDBEnabled {
+		if savepoints.HistoryDB, err = inspector.provider.newHistMgr(ledgerID, blockStore).GetBlockNumFromSavepoint(); err != nil {
+			return nil, err
+		}
+	}
+	return savepoints, nil
+}
+
+// VerifyBlockStorage verifies the hash chain of the blocks of the given ledger and the consistency of the
+// block index with the block files. The hashes are computed with the hashing algorithm set by the latest
+// config block of the ledger, or with the default algorithm if the ledger holds none. The number of blocks
+// verified is returned
+func (inspector *Inspector) VerifyBlockStorage(ledgerID string) (uint64, error) {
+	blockStore, err := inspector.openBlockStore(ledgerID)
+	if err != nil {
+		return 0, err
+	}
+	return blockStore.Verify()
+}
+
+// RebuildBlockIndex discards the block index of the given ledger and indexes its blocks again from the block files
+func (inspector *Inspector) RebuildBlockIndex(ledgerID string) error {
+	if err := inspector.checkExists(ledgerID); err != nil {
+		return err
+	}
+	// the read-only block store, if opened, would not reflect the rebuilt index
+	if blockStore, ok := inspector.blockStores[ledgerID]; ok {
+		blockStore.Shutdown()
+		delete(inspector.blockStores, ledgerID)
+	}
+	blockStore, err := inspector.provider.blockStoreProvider.OpenBlockStore(ledgerID)
+	if err != nil {
+		return err
+	}
+	defer blockStore.Shutdown()
+	return blockStore.RebuildIndex()
+}
+
+// RebuildStateDB discards the state of the given ledger and recommits the blocks to the state database,
+// as the recovery does for the blocks that follow the savepoint. The state database is left with a zero
+// savepoint while it is being emptied, so that an interrupted rebuild is completed when the ledger is next opened.
+// A ledger that does not retain its first block, such as a ledger created from a snapshot, cannot be rebuilt
+func (inspector *Inspector) RebuildStateDB(ledgerID string) error {
+	blockStore, err := inspector.openBlockStore(ledgerID)
+	if err != nil {
+		return err
+	}
+	info, err := blockStore.GetBlockchainInfo()
+	if err != nil {
+		return err
+	}
+	if info.Height == 0 {
+		return nil
+	}
+	if _, err = blockStore.RetrieveBlockByNumber(1); err == blkstorage.ErrPruned {
+		return fmt.Errorf("The state database of ledger [%s] cannot be rebuilt as the first block is not retained", ledgerID)
+	}
+	if err != nil {
+		return err
+	}
+	vDB, err := inspector.openVersionedDB(ledgerID)
+	if err != nil {
+		return err
+	}
+	if err = clearStateDB(vDB); err != nil {
+		return err
+	}
+	logger.Infof("Removed the state of ledger [%s], recommitting blocks 1 to %d", ledgerID, info.Height)
+	l := &kvLedger{ledgerID, blockStore, lockbasedtxmgr.NewLockBasedTxMgr(vDB), nil, vDB}
+	return recommitLostBlocks(l, 0, info.Height, true, false)
+}
+
+// Close closes the ledgers opened by the inspector
+func (inspector *Inspector) Close() {
+	for _, blockStore := range inspector.blockStores {
+		blockStore.Shutdown()
+	}
+	for _, vDB := range inspector.vDBs {
+		vDB.Close()
+	}
+	inspector.provider.Close()
+}
+
+func (inspector *Inspector) openBlockStore(ledgerID string) (blkstorage.BlockStore, error) {
+	if blockStore, ok := inspector.blockStores[ledgerID]; ok {
+		return blockStore, nil
+	}
+	if err := inspector.checkExists(ledgerID); err != nil {
+		return nil, err
+	}
+	blockStore, err := inspector.provider.blockStoreProvider.OpenBlockStoreReadOnly(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	inspector.blockStores[ledgerID] = blockStore
+	return blockStore, nil
+}
+
+func (inspector *Inspector) checkExists(ledgerID string) error {
+	exists, err := inspector.provider.Exists(ledgerID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNonExistingLedgerID
+	}
+	return nil
+}
+
+func (inspector *Inspector) openVersionedDB(ledgerID string) (*statehash.VersionedDB, error) {
+	if vDB, ok := inspector.vDBs[ledgerID]; ok {
+		return vDB, nil
+	}
+	vDB, err := inspector.provider.vdbProvider.GetDBHandle(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	if err = vDB.Open(); err != nil {
+		return nil, err
+	}
+	inspector.vDBs[ledgerID] = vDB
+	return vDB, nil
+}
+
+// clearStateDB deletes all the key-values of the state with a zero height, which does not
+// record a state hash and resets the savepoint
+func clearStateDB(vDB *statehash.VersionedDB) error {
+	itr, err := vDB.GetFullScanIterator()
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+	batch := statedb.NewUpdateBatch()
+	for {
+		result, err := itr.Next()
+		if err != nil {
+			return err
+		}
+		if result == nil {
+			break
+		}
+		kv := result.(*statedb.VersionedKV)
+		batch.Delete(kv.Namespace, kv.Key, version.NewHeight(0, 0))
+	}
+	return vDB.ApplyUpdates(batch, version.NewHeight(0, 0))
+}
@@ -0,0 +1,130 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvledger
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/chainconfig"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/genesis"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/testutil"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+func TestInspector(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	ledger, _ := provider.Create("testLedger")
+	bg := testutil.NewBlockGenerator(t)
+	for i := 0; i < 3; i++ {
+		simulator, _ := ledger.NewTxSimulator()
+		simulator.SetState("ns1", fmt.Sprintf("key%d", i), []byte(fmt.Sprintf("value%d", i)))
+		simulator.SetState("ns1", "key", []byte(fmt.Sprintf("value%d", i)))
+		if i == 2 {
+			simulator.DeleteState("ns1", "key0")
+		}
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		testutil.AssertNoError(t, ledger.Commit(bg.NextBlock([][]byte{simRes}, false)), "")
+	}
+	stateHash, _ := ledger.GetStateHash(3)
+	testutil.AssertNotNil(t, stateHash)
+	ledger.Close()
+	provider.Close()
+
+	inspector, err := NewInspector()
+	testutil.AssertNoError(t, err, "")
+	ledgerIDs, err := inspector.List()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, ledgerIDs, []string{"testLedger"})
+	_, err = inspector.GetSavepoints("nonExistingLedger")
+	testutil.AssertSame(t, err, ErrNonExistingLedgerID)
+
+	savepoints, err := inspector.GetSavepoints("testLedger")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, savepoints, &Savepoints{BlockStorageHeight: 3, StateDB: 3,
+		HistoryDBEnabled: ledgerconfig.IsHistoryDBEnabled(), HistoryDB: savepoints.HistoryDB})
+	numBlocks, err := inspector.VerifyBlockStorage("testLedger")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, numBlocks, uint64(3))
+	testutil.AssertNoError(t, inspector.RebuildBlockIndex("testLedger"), "")
+	block, err := inspector.GetBlockByNumber("testLedger", 2)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, block.Header.Number, uint64(2))
+
+	testutil.AssertNoError(t, inspector.RebuildStateDB("testLedger"), "")
+	savepoints, err = inspector.GetSavepoints("testLedger")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, savepoints.StateDB, uint64(3))
+	inspector.Close()
+
+	// the rebuilt state db carries the same state and state hash
+	provider, _ = NewProvider()
+	defer provider.Close()
+	ledger, _ = provider.Open("testLedger")
+	defer ledger.Close()
+	rebuiltStateHash, _ := ledger.GetStateHash(3)
+	testutil.AssertEquals(t, rebuiltStateHash, stateHash)
+	qe, _ := ledger.NewQueryExecutor()
+	defer qe.Done()
+	value, _ := qe.GetState("ns1", "key0")
+	testutil.AssertNil(t, value)
+	value, _ = qe.GetState("ns1", "key2")
+	testutil.AssertEquals(t, value, []byte("value2"))
+	value, _ = qe.GetState("ns1", "key")
+	testutil.AssertEquals(t, value, []byte("value2"))
+}
+
+func TestInspectorVerifyHashingAlgorithm(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	ledger, _ := provider.Create("testLedger")
+
+	// the chain of the ledger is configured to hash with SHA256 rather than the default algorithm
+	sha256, err := chainconfig.HashFunction(chainconfig.SHA256)
+	testutil.AssertNoError(t, err, "")
+	configBlock, err := genesis.NewFactoryImpl(configtx.NewSimpleTemplate(&common.ConfigurationItem{
+		Type:  common.ConfigurationItem_Chain,
+		Key:   chainconfig.HashingAlgorithmKey,
+		Value: utils.MarshalOrPanic(&common.HashingAlgorithm{Name: chainconfig.SHA256}),
+	})).Block("testLedger")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNoError(t, ledger.Commit(configBlock), "")
+	previousBlock := configBlock
+	bg := testutil.NewBlockGenerator(t)
+	for i := 0; i < 2; i++ {
+		block := bg.NextBlock([][]byte{}, false)
+		block.Header.PreviousHash = previousBlock.Header.HashWith(sha256)
+		testutil.AssertNoError(t, ledger.Commit(block), "")
+		previousBlock = block
+	}
+	ledger.Close()
+	provider.Close()
+
+	inspector, err := NewInspector()
+	testutil.AssertNoError(t, err, "")
+	defer inspector.Close()
+	numBlocks, err := inspector.VerifyBlockStorage("testLedger")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, numBlocks, uint64(3))
+}
@@ -23,7 +23,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/blkstorage"
 	"github.com/hyperledger/fabric/core/ledger/history"
-	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statehash"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr/lockbasedtxmgr"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
@@ -43,30 +43,17 @@ type kvLedger struct {
 	blockStore  blkstorage.BlockStore
 	txtmgmt     txmgr.TxMgr
 	historymgmt history.HistMgr
+	versionedDB *statehash.VersionedDB
 }
 
 // NewKVLedger constructs new `KVLedger`
-func newKVLedger(ledgerID string, blockStore blkstorage.BlockStore, versionedDB statedb.VersionedDB) (*kvLedger, error) {
+// historymgmt is expected to be nil if the history database is not enabled
+func newKVLedger(ledgerID string, blockStore blkstorage.BlockStore, versionedDB *statehash.VersionedDB, historymgmt history.HistMgr) (*kvLedger, error) {
 	logger.Debugf("Creating KVLedger ledgerID=%s: ", ledgerID)
-	//State and History database managers
-	var txmgmt txmgr.TxMgr
-	var historymgmt history.HistMgr
+	//State database manager
+	txmgmt := lockbasedtxmgr.NewLockBasedTxMgr(versionedDB)
 
-	txmgmt = lockbasedtxmgr.NewLockBasedTxMgr(versionedDB)
-
-	if ledgerconfig.IsHistoryDBEnabled() == true {
-		logger.Debugf("===HISTORYDB=== NewKVLedger() Using CouchDB for transaction history database")
-
-		couchDBDef := ledgerconfig.GetCouchDBDefinition()
-
-		historymgmt = history.NewCouchDBHistMgr(
-			couchDBDef.URL,      //couchDB connection URL
-			"system_history",    //couchDB db name matches ledger name, TODO for now use system_history ledger, eventually allow passing in subledger name
-			couchDBDef.Username, //enter couchDB id here
-			couchDBDef.Password) //enter couchDB pw here
-	}
-
-	l := &kvLedger{ledgerID, blockStore, txmgmt, historymgmt}
+	l := &kvLedger{ledgerID, blockStore, txmgmt, historymgmt, versionedDB}
 
 	//Recover both state DB and history DB if they are out of sync with block storage
 	if err := recoverDB(l); err != nil {
@@ -185,7 +172,14 @@ func recommitLostBlocks(l *kvLedger, savepoint uint64, blockHeight uint64, recov
 	var err error
 	var block *common.Block
 	for blockNumber := savepoint + 1; blockNumber <= blockHeight; blockNumber++ {
-		if block, err = l.GetBlockByNumber(blockNumber); err != nil {
+		block, err = l.GetBlockByNumber(blockNumber)
+		if err == blkstorage.ErrPruned && !recoverStateDB {
+			//The history of the blocks that precede the snapshot from which the ledger was created,
+			//or that have been pruned, is not available
+			logger.Debugf("Skipping the history of the pruned block %d", blockNumber)
+			continue
+		}
+		if err != nil {
 			return err
 		}
 		if recoverStateDB == true {
@@ -208,9 +202,22 @@ func recommitLostBlocks(l *kvLedger, savepoint uint64, blockHeight uint64, recov
 	return nil
 }
 
-// GetTransactionByID retrieves a transaction by id
-func (l *kvLedger) GetTransactionByID(txID string) (*pb.Transaction, error) {
-	return l.blockStore.RetrieveTxByID(txID)
+// GetTransactionByID retrieves a transaction by id, along with its validation code
+func (l *kvLedger) GetTransactionByID(txID string) (*pb.ProcessedTransaction, error) {
+	return retrieveProcessedTx(l.blockStore, txID)
+}
+
+// retrieveProcessedTx retrieves a transaction and its validation code from the block store
+func retrieveProcessedTx(blockStore blkstorage.BlockStore, txID string) (*pb.ProcessedTransaction, error) {
+	tx, err := blockStore.RetrieveTxByID(txID)
+	if err != nil {
+		return nil, err
+	}
+	validationCode, err := blockStore.RetrieveTxValidationCodeByTxID(txID)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ProcessedTransaction{Transaction: tx, ValidationCode: validationCode}, nil
 }
 
 // GetBlockchainInfo returns basic info about blockchain
@@ -233,14 +240,25 @@ func (l *kvLedger) GetBlocksIterator(startBlockNumber uint64) (ledger.ResultsIte
 
 }
 
+// GetConfigBlock returns the latest valid config block committed to the ledger
+func (l *kvLedger) GetConfigBlock() (*common.Block, error) {
+	return l.blockStore.RetrieveConfigBlock()
+}
+
 // GetBlockByHash returns a block given it's hash
 func (l *kvLedger) GetBlockByHash(blockHash []byte) (*common.Block, error) {
 	return l.blockStore.RetrieveBlockByHash(blockHash)
 }
 
-//Prune prunes the blocks/transactions that satisfy the given policy
+//Prune prunes the blocks/transactions that satisfy the given policy.
+//Blocks are removed from the block storage in whole block files, the state and history databases are left intact
 func (l *kvLedger) Prune(policy ledger.PrunePolicy) error {
-	return errors.New("Not yet implemented")
+	return l.blockStore.Prune(policy)
+}
+
+// GetStateHash returns the hash of the state as of the given block
+func (l *kvLedger) GetStateHash(blockNum uint64) ([]byte, error) {
+	return l.versionedDB.GetStateHash(blockNum)
 }
 
 // NewTxSimulator returns new `ledger.TxSimulator`
@@ -259,6 +277,9 @@ func (l *kvLedger) NewQueryExecutor() (ledger.QueryExecutor, error) {
 // A client can obtain more than one 'HistoryQueryExecutor's for parallel execution.
 // Any synchronization should be performed at the implementation level if required
 func (l *kvLedger) NewHistoryQueryExecutor() (ledger.HistoryQueryExecutor, error) {
+	if l.historymgmt == nil {
+		return nil, errors.New("History database is not enabled")
+	}
 	return l.historymgmt.NewHistoryQueryExecutor()
 }
 
@@ -272,25 +293,40 @@ func (l *kvLedger) Commit(block *common.Block) error {
 		return err
 	}
 
+	//The recovery recommits the blocks that follow the savepoints of the state and history databases,
+	//so the savepoints must not move past the block storage. The part of the commit to the state database
+	//that does not move the savepoint overlaps with appending the block to the block storage
 	logger.Debugf("Committing block to storage")
-	if err = l.blockStore.AddBlock(block); err != nil {
+	blockStoreErr := make(chan error, 1)
+	go func() {
+		blockStoreErr <- l.blockStore.AddBlock(block)
+	}()
+	preCommitErr := l.txtmgmt.PreCommit()
+	if err = <-blockStoreErr; err != nil {
 		return err
 	}
-
-	logger.Debugf("Committing block to state database")
-	if err = l.txtmgmt.Commit(); err != nil {
-		panic(fmt.Errorf(`Error during commit to txmgr:%s`, err))
+	if preCommitErr != nil {
+		panic(fmt.Errorf(`Error during precommit to txmgr:%s`, preCommitErr))
 	}
 
-	//TODO There are still some decisions to be made regarding how consistent history
-	//needs to stay with the state.  At min will want this to run async with state db writes.
-	//(History needs to wait for the block (FSBlock) to write but not the state db)
+	//The history database only depends on the block, and is committed alongside the state database
 	logger.Debugf("===HISTORYDB=== Commit() will write to history if enabled else will be by-passed if not enabled: vledgerconfig.IsHistoryDBEnabled(): %v\n", ledgerconfig.IsHistoryDBEnabled())
+	historyErr := make(chan error, 1)
 	if ledgerconfig.IsHistoryDBEnabled() == true {
 		logger.Debugf("Committing transactions to history database")
-		if err := l.historymgmt.Commit(block); err != nil {
-			panic(fmt.Errorf(`Error during commit to txthistory:%s`, err))
-		}
+		go func() {
+			historyErr <- l.historymgmt.Commit(block)
+		}()
+	} else {
+		historyErr <- nil
+	}
+
+	logger.Debugf("Committing block to state database")
+	if err = l.txtmgmt.Commit(); err != nil {
+		panic(fmt.Errorf(`Error during commit to txmgr:%s`, err))
+	}
+	if err = <-historyErr; err != nil {
+		panic(fmt.Errorf(`Error during commit to txthistory:%s`, err))
 	}
 
 	return nil
@@ -18,12 +18,15 @@ package kvledger
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/blkstorage"
 	"github.com/hyperledger/fabric/core/ledger/blkstorage/fsblkstorage"
+	"github.com/hyperledger/fabric/core/ledger/history"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statecouchdb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statehash"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/util/leveldbhelper"
@@ -41,8 +44,9 @@ var (
 // Provider implements interface ledger.PeerLedgerProvider
 type Provider struct {
 	idStore            *idStore
-	vdbProvider        statedb.VersionedDBProvider
+	vdbProvider        *statehash.VersionedDBProvider
 	blockStoreProvider blkstorage.BlockStoreProvider
+	historyDBProvider  *history.LevelDBHistMgrProvider
 }
 
 // NewProvider instantiates a new Provider.
@@ -66,15 +70,22 @@ func NewProvider() (ledger.PeerLedgerProvider, error) {
 		blkstorage.IndexableAttrBlockNum,
 		blkstorage.IndexableAttrTxID,
 		blkstorage.IndexableAttrBlockNumTranNum,
+		blkstorage.IndexableAttrTxValidationCode,
 	}
 	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex}
 	blockStoreProvider := fsblkstorage.NewProvider(
 		fsblkstorage.NewConf(ledgerconfig.GetBlockStorePath(), ledgerconfig.GetMaxBlockfileSize()),
 		indexConfig)
 
+	var historyDBProvider *history.LevelDBHistMgrProvider
+	if ledgerconfig.IsHistoryDBEnabled() && !ledgerconfig.IsCouchDBEnabled() {
+		logger.Debugf("Constructing leveldb history database provider")
+		historyDBProvider = history.NewLevelDBHistMgrProvider()
+	}
+
 	idStore := openIDStore(ledgerconfig.GetLedgerProviderPath())
 	logger.Info("ledger provider Initialized")
-	return &Provider{idStore, vdbProvider, blockStoreProvider}, nil
+	return &Provider{idStore, statehash.NewVersionedDBProvider(vdbProvider), blockStoreProvider, historyDBProvider}, nil
 }
 
 // Create implements the corresponding method from interface ledger.PeerLedgerProvider
@@ -90,6 +101,53 @@ func (provider *Provider) Create(ledgerID string) (ledger.PeerLedger, error) {
 	return provider.Open(ledgerID)
 }
 
+// CreateFromSnapshot implements the corresponding method from interface ledger.PeerLedgerProvider
+// The snapshot is verified before its state is loaded into the state db and the block store is bootstrapped
+// from the blockchain info and the config block of the snapshot. The ledger id is recorded at the end, so that an interrupted
+// creation can be retried with the same snapshot
+func (provider *Provider) CreateFromSnapshot(ledgerID string, snapshotDir string) (ledger.PeerLedger, error) {
+	metadata, err := loadSnapshotMetadata(snapshotDir)
+	if err != nil {
+		return nil, err
+	}
+	if metadata.LedgerID != ledgerID {
+		return nil, fmt.Errorf("Snapshot is of ledger [%s], not of ledger [%s]", metadata.LedgerID, ledgerID)
+	}
+	exists, err := provider.idStore.ledgerIDExists(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrLedgerIDExists
+	}
+	if err = verifySnapshotState(snapshotDir, metadata); err != nil {
+		return nil, err
+	}
+	configBlock, err := loadSnapshotConfigBlock(snapshotDir)
+	if err != nil {
+		return nil, err
+	}
+	vDB, err := provider.vdbProvider.GetDBHandle(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	if err = importSnapshotState(snapshotDir, metadata, vDB); err != nil {
+		return nil, err
+	}
+	blockStore, err := provider.blockStoreProvider.OpenBlockStore(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	if err = blockStore.Bootstrap(metadata.blockchainInfo(), configBlock); err != nil {
+		return nil, err
+	}
+	if err = provider.idStore.createLedgerID(ledgerID); err != nil {
+		return nil, err
+	}
+	logger.Infof("Created ledger [%s] from snapshot at height [%d]", ledgerID, metadata.Height)
+	return newKVLedger(ledgerID, blockStore, vDB, provider.newHistMgr(ledgerID, blockStore))
+}
+
 // Open implements the corresponding method from interface ledger.PeerLedgerProvider
 func (provider *Provider) Open(ledgerID string) (ledger.PeerLedger, error) {
 	exists, err := provider.idStore.ledgerIDExists(ledgerID)
@@ -107,13 +165,31 @@ func (provider *Provider) Open(ledgerID string) (ledger.PeerLedger, error) {
 	if err != nil {
 		return nil, err
 	}
-	l, err := newKVLedger(ledgerID, blockStore, vDB)
+	l, err := newKVLedger(ledgerID, blockStore, vDB, provider.newHistMgr(ledgerID, blockStore))
 	if err != nil {
 		return nil, err
 	}
 	return l, nil
 }
 
+// newHistMgr returns the history database manager for the given ledger, or nil if the history database is not enabled
+func (provider *Provider) newHistMgr(ledgerID string, blockStore blkstorage.BlockStore) history.HistMgr {
+	if !ledgerconfig.IsHistoryDBEnabled() {
+		return nil
+	}
+	if provider.historyDBProvider != nil {
+		logger.Debugf("===HISTORYDB=== Using leveldb for transaction history database")
+		return provider.historyDBProvider.GetHistMgr(ledgerID, blockStore)
+	}
+	logger.Debugf("===HISTORYDB=== Using CouchDB for transaction history database")
+	couchDBDef := ledgerconfig.GetCouchDBDefinition()
+	return history.NewCouchDBHistMgr(
+		couchDBDef.URL,      //couchDB connection URL
+		"system_history",    //couchDB db name matches ledger name, TODO for now use system_history ledger, eventually allow passing in subledger name
+		couchDBDef.Username, //enter couchDB id here
+		couchDBDef.Password) //enter couchDB pw here
+}
+
 // Exists implements the corresponding method from interface ledger.PeerLedgerProvider
 func (provider *Provider) Exists(ledgerID string) (bool, error) {
 	return provider.idStore.ledgerIDExists(ledgerID)
@@ -129,6 +205,9 @@ func (provider *Provider) Close() {
 	provider.vdbProvider.Close()
 	provider.idStore.close()
 	provider.blockStoreProvider.Close()
+	if provider.historyDBProvider != nil {
+		provider.historyDBProvider.Close()
+	}
 }
 
 type idStore struct {
@@ -23,6 +23,8 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/testutil"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -80,8 +82,65 @@ func TestKVLedgerBlockStorage(t *testing.T) {
 	testutil.AssertEquals(t, b2, block2)
 }
 
+func TestKVLedgerGetTransactionByID(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	defer provider.Close()
+	ledger, _ := provider.Create("testLedger")
+	defer ledger.Close()
+
+	simulator, _ := ledger.NewTxSimulator()
+	simulator.SetState("ns1", "key1", []byte("value1"))
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	bg := testutil.NewBlockGenerator(t)
+	testutil.AssertNoError(t, ledger.Commit(bg.NextBlock([][]byte{simRes}, false)), "")
+
+	// both transactions read and update key1, the second one is invalidated by the first one
+	simResults := [][]byte{}
+	for i := 0; i < 2; i++ {
+		simulator, _ = ledger.NewTxSimulator()
+		simulator.GetState("ns1", "key1")
+		simulator.SetState("ns1", "key1", []byte(fmt.Sprintf("value%d", i+2)))
+		simulator.Done()
+		simRes, _ = simulator.GetTxSimulationResults()
+		simResults = append(simResults, simRes)
+	}
+	block2 := bg.NextBlock(simResults, false)
+	testutil.AssertNoError(t, ledger.Commit(block2), "")
+
+	expectedCodes := []pb.TxValidationCode{pb.TxValidationCode_VALID, pb.TxValidationCode_MVCC_READ_CONFLICT}
+	for i, envBytes := range block2.Data.Data {
+		env, err := putils.GetEnvelopeFromBlock(envBytes)
+		testutil.AssertNoError(t, err, "")
+		payload, err := putils.GetPayload(env)
+		testutil.AssertNoError(t, err, "")
+		tx, err := putils.GetTransaction(payload.Data)
+		testutil.AssertNoError(t, err, "")
+
+		processedTx, err := ledger.GetTransactionByID(payload.Header.ChainHeader.TxID)
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, processedTx, &pb.ProcessedTransaction{Transaction: tx, ValidationCode: expectedCodes[i]})
+	}
+}
+
 func TestKVLedgerDBRecovery(t *testing.T) {
 	testutil.SetupCoreYAMLConfig("./../../../peer")
+	testKVLedgerDBRecovery(t)
+}
+
+func TestKVLedgerDBRecoveryWithLevelDBHistory(t *testing.T) {
+	testutil.SetupCoreYAMLConfig("./../../../peer")
+	if ledgerconfig.IsCouchDBEnabled() == true {
+		t.Skip("Skipping leveldb history test as CouchDB is enabled")
+	}
+	viper.Set("ledger.state.historyDatabase", true)
+	defer testutil.ResetConfigToDefaultValues()
+	testKVLedgerDBRecovery(t)
+}
+
+func testKVLedgerDBRecovery(t *testing.T) {
 	env := newTestEnv(t)
 	defer env.cleanup()
 	provider, _ := NewProvider()
@@ -0,0 +1,405 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvledger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/blkstorage"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statehash"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+/*
+A snapshot is a directory with two files
+  -- the state file, which holds the key-values of the state ordered by namespace and key. Each key-value
+     is written as a record <namespace, key, value, version.BlockNum, version.TxNum> preceded by its length
+  -- the metadata file, which holds the `snapshotMetadata` in JSON format. This includes the savepoint of the
+     exported state, the blockchain info as of the block of the savepoint, the SHA256 hash of the state file and
+     the state hash of the ledger as of the block of the savepoint, which the other peers of the chain report too
+and, if the ledger holds a valid config block, the config block file with the latest one in protobuf format
+*/
+const (
+	snapshotStateFileName       = "state"
+	snapshotMetadataFileName    = "metadata.json"
+	snapshotConfigBlockFileName = "configBlock"
+	// snapshotImportBatchSize is the number of key-values that are written to the state db in a single batch
+	snapshotImportBatchSize = 1000
+)
+
+// ErrEmptyLedger is returned by an ExportSnapshot call on a ledger without any committed block
+var ErrEmptyLedger = errors.New("Ledger does not have any committed block")
+
+// ErrNoLedgerStateHash is returned by a CheckSnapshotStateHash call on a snapshot exported at a block
+// for which the ledger did not record a state hash
+var ErrNoLedgerStateHash = errors.New("Snapshot does not have the state hash of the ledger")
+
+type snapshotMetadata struct {
+	LedgerID          string `json:"LedgerID"`
+	Height            uint64 `json:"Height"`
+	CurrentBlockHash  []byte `json:"CurrentBlockHash"`
+	PreviousBlockHash []byte `json:"PreviousBlockHash"`
+	SavepointTxNum    uint64 `json:"SavepointTxNum"`
+	NumKVs            uint64 `json:"NumKVs"`
+	StateHash         []byte `json:"StateHash"`
+	LedgerStateHash   []byte `json:"LedgerStateHash"`
+}
+
+func (metadata *snapshotMetadata) blockchainInfo() *pb.BlockchainInfo {
+	return &pb.BlockchainInfo{
+		Height:            metadata.Height,
+		CurrentBlockHash:  metadata.CurrentBlockHash,
+		PreviousBlockHash: metadata.PreviousBlockHash}
+}
+
+func (metadata *snapshotMetadata) snapshotInfo() *ledger.SnapshotInfo {
+	return &ledger.SnapshotInfo{
+		LedgerID:        metadata.LedgerID,
+		BlockchainInfo:  metadata.blockchainInfo(),
+		StateHash:       metadata.StateHash,
+		LedgerStateHash: metadata.LedgerStateHash}
+}
+
+func (metadata *snapshotMetadata) savepoint() *version.Height {
+	return version.NewHeight(metadata.Height, metadata.SavepointTxNum)
+}
+
+// ExportSnapshot implements method in interface `ledger.PeerLedger`
+// The commits are held off while the state is being exported
+func (l *kvLedger) ExportSnapshot(snapshotDir string) (*ledger.SnapshotInfo, error) {
+	info, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return nil, err
+	}
+	if info.Height == 0 {
+		return nil, ErrEmptyLedger
+	}
+	empty, err := util.CreateDirIfMissing(snapshotDir)
+	if err != nil {
+		return nil, err
+	}
+	if !empty {
+		return nil, fmt.Errorf("Snapshot directory [%s] is not empty", snapshotDir)
+	}
+
+	stateFile, err := os.Create(filepath.Join(snapshotDir, snapshotStateFileName))
+	if err != nil {
+		return nil, err
+	}
+	defer stateFile.Close()
+	stateHash := sha256.New()
+	writer := bufio.NewWriter(io.MultiWriter(stateFile, stateHash))
+	numKVs := uint64(0)
+	savepoint, err := l.txtmgmt.ExportState(func(kv *statedb.VersionedKV) error {
+		numKVs++
+		return writeSnapshotRecord(writer, kv)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = writer.Flush(); err != nil {
+		return nil, err
+	}
+	if err = stateFile.Sync(); err != nil {
+		return nil, err
+	}
+
+	bcInfo, err := l.blockchainInfoAt(savepoint.BlockNum)
+	if err != nil {
+		return nil, err
+	}
+	ledgerStateHash, err := l.versionedDB.GetStateHash(savepoint.BlockNum)
+	if err != nil {
+		return nil, err
+	}
+	if err = l.exportConfigBlock(snapshotDir, savepoint.BlockNum); err != nil {
+		return nil, err
+	}
+	metadata := &snapshotMetadata{
+		LedgerID:          l.ledgerID,
+		Height:            bcInfo.Height,
+		CurrentBlockHash:  bcInfo.CurrentBlockHash,
+		PreviousBlockHash: bcInfo.PreviousBlockHash,
+		SavepointTxNum:    savepoint.TxNum,
+		NumKVs:            numKVs,
+		StateHash:         stateHash.Sum(nil),
+		LedgerStateHash:   ledgerStateHash,
+	}
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	if err = ioutil.WriteFile(filepath.Join(snapshotDir, snapshotMetadataFileName), metadataJSON, 0644); err != nil {
+		return nil, err
+	}
+	logger.Infof("Exported snapshot of ledger [%s] at height [%d] with [%d] keys to [%s]", l.ledgerID, metadata.Height, numKVs, snapshotDir)
+	return metadata.snapshotInfo(), nil
+}
+
+// ReadSnapshotInfo returns the info about the snapshot in the given directory
+func ReadSnapshotInfo(snapshotDir string) (*ledger.SnapshotInfo, error) {
+	metadata, err := loadSnapshotMetadata(snapshotDir)
+	if err != nil {
+		return nil, err
+	}
+	return metadata.snapshotInfo(), nil
+}
+
+// CheckSnapshotStateHash checks the state hash of the ledger recorded in the snapshot in the given directory
+// against the state hash as of the block of the snapshot that the given function returns, such as the state
+// hash that another peer of the chain reports for the block. The snapshot is thereby checked against the
+// other peers before a ledger is created from it
+func CheckSnapshotStateHash(snapshotDir string, getStateHash func(blockNum uint64) ([]byte, error)) error {
+	metadata, err := loadSnapshotMetadata(snapshotDir)
+	if err != nil {
+		return err
+	}
+	if metadata.LedgerStateHash == nil {
+		return ErrNoLedgerStateHash
+	}
+	blockNum := metadata.savepoint().BlockNum
+	stateHash, err := getStateHash(blockNum)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(stateHash, metadata.LedgerStateHash) {
+		return fmt.Errorf("State hash of the snapshot as of block [%d] is [%x], whereas it is [%x] on the other peer", blockNum, metadata.LedgerStateHash, stateHash)
+	}
+	return nil
+}
+
+// blockchainInfoAt returns the blockchain info as of the given block. The block store may be ahead of
+// the state by a block that is being committed, in which case the info is derived from the given block
+func (l *kvLedger) blockchainInfoAt(blockNum uint64) (*pb.BlockchainInfo, error) {
+	info, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return nil, err
+	}
+	if info.Height == blockNum {
+		return info, nil
+	}
+	if info.Height != blockNum+1 {
+		return nil, fmt.Errorf("The block store is at height [%d] while the state is at block [%d]", info.Height, blockNum)
+	}
+	// the hash of the block, computed with the hashing algorithm of the chain, is the previous hash of the block being committed
+	block, err := l.blockStore.RetrieveBlockByNumber(blockNum)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.BlockchainInfo{
+		Height:            blockNum,
+		CurrentBlockHash:  info.PreviousBlockHash,
+		PreviousBlockHash: block.Header.PreviousHash}, nil
+}
+
+// exportConfigBlock writes the latest valid config block of the ledger, if any, to the snapshot directory.
+// The block store may be ahead of the state by a block that is being committed, in which case that block
+// is not part of the snapshot and cannot be exported as its config block
+func (l *kvLedger) exportConfigBlock(snapshotDir string, blockNum uint64) error {
+	configBlock, err := l.blockStore.RetrieveConfigBlock()
+	if err == blkstorage.ErrNotFoundInIndex {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if configBlock.Header.Number > blockNum {
+		return fmt.Errorf("Config block [%d] is being committed, the snapshot has to be exported again", configBlock.Header.Number)
+	}
+	configBlockBytes, err := proto.Marshal(configBlock)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(snapshotDir, snapshotConfigBlockFileName), configBlockBytes, 0644)
+}
+
+// loadSnapshotConfigBlock reads the config block of the snapshot in the given directory, nil if the snapshot has none
+func loadSnapshotConfigBlock(snapshotDir string) (*common.Block, error) {
+	configBlockBytes, err := ioutil.ReadFile(filepath.Join(snapshotDir, snapshotConfigBlockFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	configBlock := &common.Block{}
+	if err = proto.Unmarshal(configBlockBytes, configBlock); err != nil {
+		return nil, fmt.Errorf("Error while unmarshalling snapshot config block: %s", err)
+	}
+	return configBlock, nil
+}
+
+// loadSnapshotMetadata reads the metadata of the snapshot in the given directory
+func loadSnapshotMetadata(snapshotDir string) (*snapshotMetadata, error) {
+	metadataJSON, err := ioutil.ReadFile(filepath.Join(snapshotDir, snapshotMetadataFileName))
+	if err != nil {
+		return nil, err
+	}
+	metadata := &snapshotMetadata{}
+	if err = json.Unmarshal(metadataJSON, metadata); err != nil {
+		return nil, fmt.Errorf("Error while unmarshalling snapshot metadata: %s", err)
+	}
+	if metadata.LedgerID == "" || metadata.Height == 0 {
+		return nil, fmt.Errorf("Invalid snapshot metadata: ledgerID=[%s], height=[%d]", metadata.LedgerID, metadata.Height)
+	}
+	return metadata, nil
+}
+
+// verifySnapshotState checks the state file of the snapshot against the hash and
+// the number of key-values recorded in the metadata
+func verifySnapshotState(snapshotDir string, metadata *snapshotMetadata) error {
+	stateHash := sha256.New()
+	numKVs := uint64(0)
+	err := readSnapshotState(snapshotDir, stateHash, func(kv *statedb.VersionedKV) error {
+		numKVs++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(stateHash.Sum(nil), metadata.StateHash) {
+		return errors.New("Snapshot state does not match the state hash in the snapshot metadata")
+	}
+	if numKVs != metadata.NumKVs {
+		return fmt.Errorf("Snapshot state has [%d] keys, whereas the snapshot metadata has [%d]", numKVs, metadata.NumKVs)
+	}
+	return nil
+}
+
+// importSnapshotState loads the state file of the snapshot into the given state db. The savepoint of
+// the snapshot is recorded only with the last batch, so that an interrupted import is not taken as complete.
+// The state hash that the state db computes for the imported state has to match the one of the snapshot
+func importSnapshotState(snapshotDir string, metadata *snapshotMetadata, vdb *statehash.VersionedDB) error {
+	noSavepoint := version.NewHeight(0, 0)
+	batch := statedb.NewUpdateBatch()
+	err := readSnapshotState(snapshotDir, ioutil.Discard, func(kv *statedb.VersionedKV) error {
+		batch.Put(kv.Namespace, kv.Key, kv.Value, kv.Version)
+		if len(batch.KVs) < snapshotImportBatchSize {
+			return nil
+		}
+		if err := vdb.ApplyUpdates(batch, noSavepoint); err != nil {
+			return err
+		}
+		batch = statedb.NewUpdateBatch()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err = vdb.ApplyUpdates(batch, metadata.savepoint()); err != nil {
+		return err
+	}
+	if metadata.LedgerStateHash == nil {
+		return nil
+	}
+	stateHash, err := vdb.GetStateHash(metadata.savepoint().BlockNum)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(stateHash, metadata.LedgerStateHash) {
+		return errors.New("Imported state does not match the state hash of the ledger in the snapshot metadata")
+	}
+	return nil
+}
+
+// readSnapshotState passes the key-values in the state file of the snapshot to the given function.
+// The bytes of the state file are copied to the given writer as they are read
+func readSnapshotState(snapshotDir string, w io.Writer, importKV func(kv *statedb.VersionedKV) error) error {
+	stateFile, err := os.Open(filepath.Join(snapshotDir, snapshotStateFileName))
+	if err != nil {
+		return err
+	}
+	defer stateFile.Close()
+	reader := bufio.NewReader(io.TeeReader(stateFile, w))
+	for {
+		kv, err := readSnapshotRecord(reader)
+		if err != nil {
+			return err
+		}
+		if kv == nil {
+			return nil
+		}
+		if err = importKV(kv); err != nil {
+			return err
+		}
+	}
+}
+
+func writeSnapshotRecord(w io.Writer, kv *statedb.VersionedKV) error {
+	buffer := proto.NewBuffer([]byte{})
+	buffer.EncodeStringBytes(kv.Namespace)
+	buffer.EncodeStringBytes(kv.Key)
+	buffer.EncodeRawBytes(kv.Value)
+	buffer.EncodeVarint(kv.Version.BlockNum)
+	buffer.EncodeVarint(kv.Version.TxNum)
+	if _, err := w.Write(proto.EncodeVarint(uint64(len(buffer.Bytes())))); err != nil {
+		return err
+	}
+	_, err := w.Write(buffer.Bytes())
+	return err
+}
+
+// readSnapshotRecord returns the next key-value from the reader, nil if the end of the state file is reached
+func readSnapshotRecord(reader *bufio.Reader) (*statedb.VersionedKV, error) {
+	recordLen, err := binary.ReadUvarint(reader)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	recordBytes := make([]byte, recordLen)
+	if _, err = io.ReadFull(reader, recordBytes); err != nil {
+		return nil, fmt.Errorf("Error while reading snapshot record: %s", err)
+	}
+	buffer := proto.NewBuffer(recordBytes)
+	kv := &statedb.VersionedKV{}
+	if kv.Namespace, err = buffer.DecodeStringBytes(); err != nil {
+		return nil, err
+	}
+	if kv.Key, err = buffer.DecodeStringBytes(); err != nil {
+		return nil, err
+	}
+	if kv.Value, err = buffer.DecodeRawBytes(true); err != nil {
+		return nil, err
+	}
+	var blockNum, txNum uint64
+	if blockNum, err = buffer.DecodeVarint(); err != nil {
+		return nil, err
+	}
+	if txNum, err = buffer.DecodeVarint(); err != nil {
+		return nil, err
+	}
+	kv.Version = version.NewHeight(blockNum, txNum)
+	return kv, nil
+}
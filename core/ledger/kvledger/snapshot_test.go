@@ -0,0 +1,248 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvledger
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/blkstorage"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/testutil"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
+)
+
+var testSnapshotPath = "/tmp/fabric/snapshottests"
+
+func TestSnapshotExportAndCreate(t *testing.T) {
+	testutil.SetupCoreYAMLConfig("./../../../peer")
+	testSnapshotExportAndCreate(t)
+}
+
+func TestSnapshotExportAndCreateWithLevelDBHistory(t *testing.T) {
+	testutil.SetupCoreYAMLConfig("./../../../peer")
+	if ledgerconfig.IsCouchDBEnabled() == true {
+		t.Skip("Skipping leveldb history test as CouchDB is enabled")
+	}
+	viper.Set("ledger.state.historyDatabase", true)
+	defer testutil.ResetConfigToDefaultValues()
+	testSnapshotExportAndCreate(t)
+}
+
+func testSnapshotExportAndCreate(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	os.RemoveAll(testSnapshotPath)
+	defer os.RemoveAll(testSnapshotPath)
+	snapshotDir := filepath.Join(testSnapshotPath, "snapshot1")
+
+	provider, _ := NewProvider()
+	l, _ := provider.Create("testLedger")
+	_, err := l.ExportSnapshot(snapshotDir)
+	testutil.AssertSame(t, err, ErrEmptyLedger)
+
+	bg := testutil.NewBlockGenerator(t)
+	commitKVs(t, l, bg, map[string]string{"key1": "value1", "key2": "value2"})
+	commitKVs(t, l, bg, map[string]string{"key2": "value2-updated", "key3": "value3"})
+	expectedBCInfo, _ := l.GetBlockchainInfo()
+	expectedStateHash, _ := l.GetStateHash(2)
+	testutil.AssertNotNil(t, expectedStateHash)
+
+	snapshotInfo, err := l.ExportSnapshot(snapshotDir)
+	testutil.AssertNoError(t, err, "Error while exporting snapshot")
+	testutil.AssertEquals(t, snapshotInfo.LedgerID, "testLedger")
+	testutil.AssertEquals(t, snapshotInfo.BlockchainInfo, expectedBCInfo)
+	_, err = l.ExportSnapshot(snapshotDir)
+	testutil.AssertError(t, err, "Expected an error for exporting to a non-empty directory")
+	l.Close()
+	provider.Close()
+
+	// create a ledger from the snapshot on a fresh peer
+	env.cleanup()
+	provider, _ = NewProvider()
+	defer provider.Close()
+	_, err = provider.CreateFromSnapshot("otherLedger", snapshotDir)
+	testutil.AssertError(t, err, "Expected an error for a snapshot of a different ledger")
+	l, err = provider.CreateFromSnapshot("testLedger", snapshotDir)
+	testutil.AssertNoError(t, err, "Error while creating ledger from snapshot")
+	defer l.Close()
+
+	bcInfo, _ := l.GetBlockchainInfo()
+	testutil.AssertEquals(t, bcInfo, expectedBCInfo)
+	_, err = l.GetBlockByNumber(2)
+	testutil.AssertSame(t, err, blkstorage.ErrPruned)
+	testState(t, l, map[string]string{"key1": "value1", "key2": "value2-updated", "key3": "value3"})
+	stateHash, _ := l.GetStateHash(2)
+	testutil.AssertEquals(t, stateHash, expectedStateHash)
+
+	// the state of the new ledger hashes the same as the state of the original ledger
+	otherSnapshotInfo, err := l.ExportSnapshot(filepath.Join(testSnapshotPath, "snapshot2"))
+	testutil.AssertNoError(t, err, "Error while exporting snapshot")
+	testutil.AssertEquals(t, otherSnapshotInfo, snapshotInfo)
+
+	// the ledger continues with the block following the snapshot
+	block3 := commitKVs(t, l, bg, map[string]string{"key1": "value1-updated"})
+	bcInfo, _ = l.GetBlockchainInfo()
+	testutil.AssertEquals(t, bcInfo, &pb.BlockchainInfo{
+		Height: 3, CurrentBlockHash: block3.Header.Hash(), PreviousBlockHash: expectedBCInfo.CurrentBlockHash})
+	testState(t, l, map[string]string{"key1": "value1-updated", "key2": "value2-updated", "key3": "value3"})
+}
+
+func TestSnapshotVerification(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	os.RemoveAll(testSnapshotPath)
+	defer os.RemoveAll(testSnapshotPath)
+	snapshotDir := filepath.Join(testSnapshotPath, "snapshot1")
+
+	provider, _ := NewProvider()
+	l, _ := provider.Create("testLedger")
+	commitKVs(t, l, testutil.NewBlockGenerator(t), map[string]string{"key1": "value1"})
+	_, err := l.ExportSnapshot(snapshotDir)
+	testutil.AssertNoError(t, err, "Error while exporting snapshot")
+	l.Close()
+	provider.Close()
+
+	metadata, err := loadSnapshotMetadata(snapshotDir)
+	testutil.AssertNoError(t, err, "Error while loading snapshot metadata")
+	metadata.StateHash[0]++
+	metadataJSON, _ := json.Marshal(metadata)
+	ioutil.WriteFile(filepath.Join(snapshotDir, snapshotMetadataFileName), metadataJSON, 0644)
+
+	env.cleanup()
+	provider, _ = NewProvider()
+	defer provider.Close()
+	_, err = provider.CreateFromSnapshot("testLedger", snapshotDir)
+	testutil.AssertError(t, err, "Expected an error for a snapshot with a mismatching state hash")
+	exists, _ := provider.Exists("testLedger")
+	testutil.AssertEquals(t, exists, false)
+}
+
+func TestSnapshotLedgerStateHash(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	os.RemoveAll(testSnapshotPath)
+	defer os.RemoveAll(testSnapshotPath)
+	snapshotDir := filepath.Join(testSnapshotPath, "snapshot1")
+
+	provider, _ := NewProvider()
+	l, _ := provider.Create("testLedger")
+	bg := testutil.NewBlockGenerator(t)
+	commitKVs(t, l, bg, map[string]string{"key1": "value1"})
+	snapshotInfo, err := l.ExportSnapshot(snapshotDir)
+	testutil.AssertNoError(t, err, "Error while exporting snapshot")
+	expectedStateHash, _ := l.GetStateHash(1)
+	testutil.AssertEquals(t, snapshotInfo.LedgerStateHash, expectedStateHash)
+	readSnapshotInfo, err := ReadSnapshotInfo(snapshotDir)
+	testutil.AssertNoError(t, err, "Error while reading snapshot info")
+	testutil.AssertEquals(t, readSnapshotInfo, snapshotInfo)
+
+	// the snapshot is checked against the state hash of a peer that committed the block of the snapshot
+	testutil.AssertNoError(t, CheckSnapshotStateHash(snapshotDir, l.GetStateHash), "")
+	commitKVs(t, l, bg, map[string]string{"key1": "value1-updated"})
+	otherStateHash, _ := l.GetStateHash(2)
+	err = CheckSnapshotStateHash(snapshotDir, func(blockNum uint64) ([]byte, error) {
+		testutil.AssertEquals(t, blockNum, uint64(1))
+		return otherStateHash, nil
+	})
+	testutil.AssertError(t, err, "Expected an error for a mismatching state hash")
+	l.Close()
+	provider.Close()
+
+	metadata, _ := loadSnapshotMetadata(snapshotDir)
+	metadata.LedgerStateHash[0]++
+	metadataJSON, _ := json.Marshal(metadata)
+	ioutil.WriteFile(filepath.Join(snapshotDir, snapshotMetadataFileName), metadataJSON, 0644)
+
+	env.cleanup()
+	provider, _ = NewProvider()
+	defer provider.Close()
+	_, err = provider.CreateFromSnapshot("testLedger", snapshotDir)
+	testutil.AssertError(t, err, "Expected an error for a snapshot with a mismatching ledger state hash")
+	exists, _ := provider.Exists("testLedger")
+	testutil.AssertEquals(t, exists, false)
+}
+
+func TestSnapshotConfigBlock(t *testing.T) {
+	testutil.SetupCoreYAMLConfig("./../../../peer")
+	env := newTestEnv(t)
+	defer env.cleanup()
+	os.RemoveAll(testSnapshotPath)
+	defer os.RemoveAll(testSnapshotPath)
+	snapshotDir := filepath.Join(testSnapshotPath, "snapshot1")
+
+	provider, _ := NewProvider()
+	l, _ := provider.Create("testLedger")
+	_, err := l.GetConfigBlock()
+	testutil.AssertSame(t, err, blkstorage.ErrNotFoundInIndex)
+
+	genesisBlock, err := configtxtest.MakeGenesisBlock("testLedger")
+	testutil.AssertNoError(t, err, "")
+	bg := testutil.NewBlockGenerator(t)
+	configBlock := bg.NextBlock([][]byte{}, false)
+	configBlock.Data = genesisBlock.Data
+	configBlock.Header.DataHash = configBlock.Data.Hash()
+	testutil.AssertNoError(t, l.Commit(configBlock), "Error while committing config block")
+	commitKVs(t, l, bg, map[string]string{"key1": "value1"})
+	_, err = l.ExportSnapshot(snapshotDir)
+	testutil.AssertNoError(t, err, "Error while exporting snapshot")
+	l.Close()
+	provider.Close()
+
+	// the ledger created from the snapshot holds the config block, which precedes the snapshot
+	env.cleanup()
+	provider, _ = NewProvider()
+	defer provider.Close()
+	l, err = provider.CreateFromSnapshot("testLedger", snapshotDir)
+	testutil.AssertNoError(t, err, "Error while creating ledger from snapshot")
+	defer l.Close()
+	_, err = l.GetBlockByNumber(1)
+	testutil.AssertSame(t, err, blkstorage.ErrPruned)
+	block, err := l.GetConfigBlock()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, proto.Equal(block, configBlock), true)
+}
+
+func commitKVs(t *testing.T, l ledger.PeerLedger, bg *testutil.BlockGenerator, kvs map[string]string) *common.Block {
+	simulator, _ := l.NewTxSimulator()
+	for key, value := range kvs {
+		simulator.SetState("ns1", key, []byte(value))
+	}
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	block := bg.NextBlock([][]byte{simRes}, false)
+	testutil.AssertNoError(t, l.Commit(block), "Error while committing block")
+	return block
+}
+
+func testState(t *testing.T, l ledger.PeerLedger, expectedKVs map[string]string) {
+	qe, _ := l.NewQueryExecutor()
+	defer qe.Done()
+	for key, value := range expectedKVs {
+		val, err := qe.GetState("ns1", key)
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, val, []byte(value))
+	}
+}
@@ -19,11 +19,22 @@ package rwset
 import (
 	"bytes"
 	"fmt"
+	"math"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 )
 
+const (
+	// formatMarker leads a serialized `TxReadWriteSet`, followed by its format version. The read-write sets
+	// which predate the versioning lead with the number of namespaces instead, which is never this large
+	formatMarker = uint64(math.MaxUint32)
+
+	// FormatVersion is the version of the serialization of a `TxReadWriteSet`. Version 2 adds the range
+	// queries, the read-write sets without a version being of version 1
+	FormatVersion = uint64(2)
+)
+
 // KVRead - a tuple of key and its version at the time of transaction simulation
 type KVRead struct {
 	Key     string
@@ -54,11 +65,33 @@ func (w *KVWrite) SetValue(value []byte) {
 	w.IsDelete = value == nil
 }
 
+// RangeQueryInfo captures a range query executed by a transaction along with the
+// keys (and their versions) that the transaction observed while iterating over the range.
+// If the iterator was not exhausted, only the range up to (and including) the last key in
+// Results was observed by the transaction. This is used for phantom-read validation at commit time
+type RangeQueryInfo struct {
+	StartKey     string
+	EndKey       string
+	ItrExhausted bool
+	Results      []*KVRead
+}
+
+// NewRangeQueryInfo constructs a new `RangeQueryInfo`
+func NewRangeQueryInfo(startKey string, endKey string) *RangeQueryInfo {
+	return &RangeQueryInfo{StartKey: startKey, EndKey: endKey}
+}
+
+// AddResult appends a key and its version observed while iterating over the range
+func (rqi *RangeQueryInfo) AddResult(key string, version *version.Height) {
+	rqi.Results = append(rqi.Results, NewKVRead(key, version))
+}
+
 // NsReadWriteSet - a collection of all the reads and writes that belong to a common namespace
 type NsReadWriteSet struct {
-	NameSpace string
-	Reads     []*KVRead
-	Writes    []*KVWrite
+	NameSpace        string
+	Reads            []*KVRead
+	Writes           []*KVWrite
+	RangeQueriesInfo []*RangeQueryInfo
 }
 
 // TxReadWriteSet - a collection of all the reads and writes collected as a result of a transaction simulation
@@ -138,6 +171,61 @@ func (w *KVWrite) Unmarshal(buf *proto.Buffer) error {
 	return nil
 }
 
+// Marshal serializes a `RangeQueryInfo`
+func (rqi *RangeQueryInfo) Marshal(buf *proto.Buffer) error {
+	var err error
+	if err = buf.EncodeStringBytes(rqi.StartKey); err != nil {
+		return err
+	}
+	if err = buf.EncodeStringBytes(rqi.EndKey); err != nil {
+		return err
+	}
+	itrExhaustedMarker := 0
+	if rqi.ItrExhausted {
+		itrExhaustedMarker = 1
+	}
+	if err = buf.EncodeVarint(uint64(itrExhaustedMarker)); err != nil {
+		return err
+	}
+	if err = buf.EncodeVarint(uint64(len(rqi.Results))); err != nil {
+		return err
+	}
+	for i := 0; i < len(rqi.Results); i++ {
+		if err = rqi.Results[i].Marshal(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Unmarshal deserializes a `RangeQueryInfo`
+func (rqi *RangeQueryInfo) Unmarshal(buf *proto.Buffer) error {
+	var err error
+	if rqi.StartKey, err = buf.DecodeStringBytes(); err != nil {
+		return err
+	}
+	if rqi.EndKey, err = buf.DecodeStringBytes(); err != nil {
+		return err
+	}
+	var itrExhaustedMarker uint64
+	if itrExhaustedMarker, err = buf.DecodeVarint(); err != nil {
+		return err
+	}
+	rqi.ItrExhausted = itrExhaustedMarker == 1
+	var numResults uint64
+	if numResults, err = buf.DecodeVarint(); err != nil {
+		return err
+	}
+	for i := 0; i < int(numResults); i++ {
+		r := &KVRead{}
+		if err = r.Unmarshal(buf); err != nil {
+			return err
+		}
+		rqi.Results = append(rqi.Results, r)
+	}
+	return nil
+}
+
 // Marshal serializes a `NsReadWriteSet`
 func (nsRW *NsReadWriteSet) Marshal(buf *proto.Buffer) error {
 	var err error
@@ -156,6 +244,14 @@ func (nsRW *NsReadWriteSet) Marshal(buf *proto.Buffer) error {
 	for i := 0; i < len(nsRW.Writes); i++ {
 		nsRW.Writes[i].Marshal(buf)
 	}
+	if err = buf.EncodeVarint(uint64(len(nsRW.RangeQueriesInfo))); err != nil {
+		return err
+	}
+	for i := 0; i < len(nsRW.RangeQueriesInfo); i++ {
+		if err = nsRW.RangeQueriesInfo[i].Marshal(buf); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -188,6 +284,18 @@ func (nsRW *NsReadWriteSet) Unmarshal(buf *proto.Buffer) error {
 		}
 		nsRW.Writes = append(nsRW.Writes, w)
 	}
+
+	var numRangeQueries uint64
+	if numRangeQueries, err = buf.DecodeVarint(); err != nil {
+		return err
+	}
+	for i := 0; i < int(numRangeQueries); i++ {
+		rqi := &RangeQueryInfo{}
+		if err = rqi.Unmarshal(buf); err != nil {
+			return err
+		}
+		nsRW.RangeQueriesInfo = append(nsRW.RangeQueriesInfo, rqi)
+	}
 	return nil
 }
 
@@ -195,6 +303,12 @@ func (nsRW *NsReadWriteSet) Unmarshal(buf *proto.Buffer) error {
 func (txRW *TxReadWriteSet) Marshal() ([]byte, error) {
 	buf := proto.NewBuffer(nil)
 	var err error
+	if err = buf.EncodeVarint(formatMarker); err != nil {
+		return nil, err
+	}
+	if err = buf.EncodeVarint(FormatVersion); err != nil {
+		return nil, err
+	}
 	if err = buf.EncodeVarint(uint64(len(txRW.NsRWs))); err != nil {
 		return nil, err
 	}
@@ -206,10 +320,24 @@ func (txRW *TxReadWriteSet) Marshal() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// Unmarshal deserializes a `TxReadWriteSet`
+// Unmarshal deserializes a `TxReadWriteSet`. It fails for the read-write sets serialized in another
+// format version, such as those of the peers which predate the versioning
 func (txRW *TxReadWriteSet) Unmarshal(b []byte) error {
 	buf := proto.NewBuffer(b)
 	var err error
+	var marker, formatVersion uint64
+	if marker, err = buf.DecodeVarint(); err != nil {
+		return err
+	}
+	if marker != formatMarker {
+		return fmt.Errorf("Read-write set has no format version, it has been serialized by a peer which only supports version 1 whereas this peer supports version %d", FormatVersion)
+	}
+	if formatVersion, err = buf.DecodeVarint(); err != nil {
+		return err
+	}
+	if formatVersion != FormatVersion {
+		return fmt.Errorf("Read-write set has format version %d, whereas this peer supports version %d", formatVersion, FormatVersion)
+	}
 	var numEntries uint64
 	if numEntries, err = buf.DecodeVarint(); err != nil {
 		return err
@@ -234,6 +362,18 @@ func (w *KVWrite) String() string {
 	return fmt.Sprintf("%s=[%#v]", w.Key, w.Value)
 }
 
+// String prints a `RangeQueryInfo`
+func (rqi *RangeQueryInfo) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString(fmt.Sprintf("[%s-%s, exhausted=%t]{", rqi.StartKey, rqi.EndKey, rqi.ItrExhausted))
+	for _, r := range rqi.Results {
+		buffer.WriteString(r.String())
+		buffer.WriteString(",")
+	}
+	buffer.WriteString("}")
+	return buffer.String()
+}
+
 // String prints a `NsReadWriteSet`
 func (nsRW *NsReadWriteSet) String() string {
 	var buffer bytes.Buffer
@@ -247,6 +387,11 @@ func (nsRW *NsReadWriteSet) String() string {
 		buffer.WriteString(w.String())
 		buffer.WriteString(",")
 	}
+	buffer.WriteString("RangeQuerySet~")
+	for _, rqi := range nsRW.RangeQueriesInfo {
+		buffer.WriteString(rqi.String())
+		buffer.WriteString(",")
+	}
 	return buffer.String()
 }
 
@@ -26,12 +26,13 @@ import (
 var logger = logging.MustGetLogger("rwset")
 
 type nsRWs struct {
-	readMap  map[string]*KVRead
-	writeMap map[string]*KVWrite
+	readMap      map[string]*KVRead
+	writeMap     map[string]*KVWrite
+	rangeQueries []*RangeQueryInfo
 }
 
 func newNsRWs() *nsRWs {
-	return &nsRWs{make(map[string]*KVRead), make(map[string]*KVWrite)}
+	return &nsRWs{make(map[string]*KVRead), make(map[string]*KVWrite), nil}
 }
 
 // RWSet maintains the read-write set
@@ -56,6 +57,13 @@ func (rws *RWSet) AddToWriteSet(ns string, key string, value []byte) {
 	nsRWs.writeMap[key] = NewKVWrite(key, value)
 }
 
+// AddToRangeQuerySet adds a range query to the read-set. The results observed by the
+// transaction can continue to be added to the RangeQueryInfo until the simulation completes
+func (rws *RWSet) AddToRangeQuerySet(ns string, rqi *RangeQueryInfo) {
+	nsRWs := rws.getOrCreateNsRW(ns)
+	nsRWs.rangeQueries = append(nsRWs.rangeQueries, rqi)
+}
+
 // GetFromWriteSet return the value of a key from the write-set
 func (rws *RWSet) GetFromWriteSet(ns string, key string) ([]byte, bool) {
 	nsRWs, ok := rws.rwMap[ns]
@@ -90,7 +98,7 @@ func (rws *RWSet) GetTxReadWriteSet() *TxReadWriteSet {
 		for _, key := range sortedWriteKeys {
 			writes = append(writes, nsReadWriteMap.writeMap[key])
 		}
-		nsRWs := &NsReadWriteSet{NameSpace: ns, Reads: reads, Writes: writes}
+		nsRWs := &NsReadWriteSet{NameSpace: ns, Reads: reads, Writes: writes, RangeQueriesInfo: nsReadWriteMap.rangeQueries}
 		txRWSet.NsRWs = append(txRWSet.NsRWs, nsRWs)
 	}
 	return txRWSet
@@ -19,6 +19,7 @@ package rwset
 import (
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/testutil"
 )
@@ -27,7 +28,8 @@ func TestNilTxRWSet(t *testing.T) {
 	txRW := &TxReadWriteSet{}
 	nsRW1 := &NsReadWriteSet{"ns1",
 		[]*KVRead{&KVRead{"key1", nil}},
-		[]*KVWrite{&KVWrite{"key1", false, []byte("value1")}}}
+		[]*KVWrite{&KVWrite{"key1", false, []byte("value1")}},
+		nil}
 	txRW.NsRWs = append(txRW.NsRWs, nsRW1)
 	b, err := txRW.Marshal()
 	testutil.AssertNoError(t, err, "Error while marshalling changeset")
@@ -43,15 +45,19 @@ func TestTxRWSetMarshalUnmarshal(t *testing.T) {
 	txRW := &TxReadWriteSet{}
 	nsRW1 := &NsReadWriteSet{"ns1",
 		[]*KVRead{&KVRead{"key1", version.NewHeight(1, 1)}},
-		[]*KVWrite{&KVWrite{"key2", false, []byte("value2")}}}
+		[]*KVWrite{&KVWrite{"key2", false, []byte("value2")}},
+		[]*RangeQueryInfo{&RangeQueryInfo{"key1", "key3", true,
+			[]*KVRead{&KVRead{"key1", version.NewHeight(1, 1)}, &KVRead{"key2", nil}}}}}
 
 	nsRW2 := &NsReadWriteSet{"ns2",
 		[]*KVRead{&KVRead{"key3", version.NewHeight(1, 2)}},
-		[]*KVWrite{&KVWrite{"key4", true, nil}}}
+		[]*KVWrite{&KVWrite{"key4", true, nil}},
+		[]*RangeQueryInfo{&RangeQueryInfo{"key3", "", false, nil}}}
 
 	nsRW3 := &NsReadWriteSet{"ns3",
 		[]*KVRead{&KVRead{"key5", version.NewHeight(1, 3)}},
-		[]*KVWrite{&KVWrite{"key6", false, []byte("value6")}, &KVWrite{"key7", false, []byte("value7")}}}
+		[]*KVWrite{&KVWrite{"key6", false, []byte("value6")}, &KVWrite{"key7", false, []byte("value7")}},
+		nil}
 
 	txRW.NsRWs = append(txRW.NsRWs, nsRW1, nsRW2, nsRW3)
 
@@ -64,3 +70,23 @@ func TestTxRWSetMarshalUnmarshal(t *testing.T) {
 	t.Logf("Unmarshalled changeset = %#+v", deserializedRWSet.NsRWs[0].Writes[0].IsDelete)
 	testutil.AssertEquals(t, deserializedRWSet, txRW)
 }
+
+func TestTxRWSetFormatVersion(t *testing.T) {
+	// a read-write set which predates the versioning, holding one namespace without reads, writes or range queries
+	buf := proto.NewBuffer(nil)
+	buf.EncodeVarint(1)
+	buf.EncodeStringBytes("ns1")
+	buf.EncodeVarint(0)
+	buf.EncodeVarint(0)
+	buf.EncodeVarint(0)
+	err := (&TxReadWriteSet{}).Unmarshal(buf.Bytes())
+	testutil.AssertError(t, err, "Should have failed unmarshalling a read-write set without format version")
+
+	// a read-write set of a later format version
+	buf = proto.NewBuffer(nil)
+	buf.EncodeVarint(formatMarker)
+	buf.EncodeVarint(FormatVersion + 1)
+	buf.EncodeVarint(0)
+	err = (&TxReadWriteSet{}).Unmarshal(buf.Bytes())
+	testutil.AssertError(t, err, "Should have failed unmarshalling a read-write set of an unsupported format version")
+}
@@ -17,11 +17,15 @@ limitations under the License.
 package commontests
 
 import (
+	"fmt"
+	"os"
 	"strings"
 	"testing"
 
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statehash"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/testutil"
 )
 
@@ -135,6 +139,72 @@ func TestDeletes(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 	testutil.AssertNil(t, vv)
 }
 
+// TestDeleteAndReAdd tests that the deleted keys are not returned by any of the reads and that the deleted
+// keys can be added again. Only the values are verified, as not all the state db backends retain the versions
+func TestDeleteAndReAdd(t *testing.T, dbProvider statedb.VersionedDBProvider) {
+	db, err := dbProvider.GetDBHandle("TestDB")
+	testutil.AssertNoError(t, err, "")
+	db.Open()
+	defer db.Close()
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns", "key1", []byte("value1"), version.NewHeight(1, 1))
+	batch.Put("ns", "key2", []byte("value2"), version.NewHeight(1, 2))
+	batch.Put("ns", "key3", []byte(`{"asset_name":"marble1","color":"blue"}`), version.NewHeight(1, 3))
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 3)), "")
+
+	// delete a binary and a JSON value, along with a key that does not exist
+	batch = statedb.NewUpdateBatch()
+	batch.Delete("ns", "key1", version.NewHeight(2, 1))
+	batch.Delete("ns", "key3", version.NewHeight(2, 2))
+	batch.Delete("ns", "key4", version.NewHeight(2, 3))
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 3)), "")
+	testValues(t, db, "ns", map[string][]byte{"key1": nil, "key2": []byte("value2"), "key3": nil, "key4": nil})
+	itr, _ := db.GetStateRangeScanIterator("ns", "", "")
+	testItr(t, itr, []string{"key2"})
+
+	// applying the deletes again, as in a recovery, is a no-op
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 3)), "")
+	testValues(t, db, "ns", map[string][]byte{"key1": nil, "key2": []byte("value2"), "key3": nil, "key4": nil})
+	savePoint, err := db.GetLatestSavePoint()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, savePoint, version.NewHeight(2, 3))
+
+	// re-add the deleted keys, the JSON value with a binary value
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns", "key1", []byte("value1-readded"), version.NewHeight(3, 1))
+	batch.Put("ns", "key3", []byte("value3"), version.NewHeight(3, 2))
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(3, 2)), "")
+	testValues(t, db, "ns", map[string][]byte{"key1": []byte("value1-readded"), "key2": []byte("value2"), "key3": []byte("value3"), "key4": nil})
+	itr, _ = db.GetStateRangeScanIterator("ns", "", "")
+	testItr(t, itr, []string{"key1", "key2", "key3"})
+}
+
+// testValues verifies the values of the keys with both GetState and GetStateMultipleKeys. A nil value means that the key is not expected to exist
+func testValues(t *testing.T, db statedb.VersionedDB, ns string, expectedValues map[string][]byte) {
+	keys := []string{}
+	for key, expectedValue := range expectedValues {
+		keys = append(keys, key)
+		vv, err := db.GetState(ns, key)
+		testutil.AssertNoError(t, err, "")
+		if expectedValue == nil {
+			testutil.AssertNil(t, vv)
+		} else {
+			testutil.AssertEquals(t, vv.Value, expectedValue)
+		}
+	}
+	vvs, err := db.GetStateMultipleKeys(ns, keys)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, len(vvs), len(keys))
+	for i, key := range keys {
+		if expectedValues[key] == nil {
+			testutil.AssertNil(t, vvs[i])
+		} else {
+			testutil.AssertEquals(t, vvs[i].Value, expectedValues[key])
+		}
+	}
+}
+
 // TestIterator tests the iterator
 func TestIterator(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 	db, err := dbProvider.GetDBHandle("TestDB")
@@ -163,6 +233,9 @@ func TestIterator(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 
 	itr4, _ := db.GetStateRangeScanIterator("ns2", "", "")
 	testItr(t, itr4, []string{"key5", "key6"})
+
+	itr5, _ := db.GetFullScanIterator()
+	testItr(t, itr5, []string{"key1", "key2", "key3", "key4", "key5", "key6", "key7"})
 }
 
 func testItr(t *testing.T, itr statedb.ResultsIterator, expectedKeys []string) {
@@ -189,11 +262,13 @@ func TestQuery(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 	batch.Put("ns1", "key1", []byte(jsonValue1), version.NewHeight(1, 1))
 	jsonValue2 := "{\"asset_name\": \"marble1\",\"color\": \"blue\",\"size\": 35,\"owner\": \"jerry\"}"
 	batch.Put("ns1", "key2", []byte(jsonValue2), version.NewHeight(1, 2))
+	jsonValue3 := "{\"asset_name\": \"marble3\",\"color\": \"red\",\"size\": 20,\"owner\": \"jerry\"}"
+	batch.Put("ns2", "key3", []byte(jsonValue3), version.NewHeight(1, 3))
 	savePoint := version.NewHeight(2, 5)
 	db.ApplyUpdates(batch, savePoint)
 
-	// query for owner=jerry
-	itr, err := db.ExecuteQuery("{\"selector\":{\"owner\":\"jerry\"}}")
+	// query for owner=jerry, the document of ns2 should not be returned
+	itr, err := db.ExecuteQuery("ns1", "{\"selector\":{\"owner\":\"jerry\"}}")
 	testutil.AssertNoError(t, err, "")
 
 	// verify one jerry result
@@ -204,6 +279,7 @@ func TestQuery(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 	stringRecord := string(versionedQueryRecord.Record)
 	bFoundJerry := strings.Contains(stringRecord, "jerry")
 	testutil.AssertEquals(t, bFoundJerry, true)
+	testutil.AssertEquals(t, versionedQueryRecord.Namespace, "ns1")
 
 	// verify no more results
 	queryResult2, err := itr.Next()
@@ -211,11 +287,11 @@ func TestQuery(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 	testutil.AssertNil(t, queryResult2)
 
 	// query using bad query string
-	itr, err = db.ExecuteQuery("this is an invalid query string")
+	itr, err = db.ExecuteQuery("ns1", "this is an invalid query string")
 	testutil.AssertError(t, err, "Should have received an error for invalid query string")
 
 	// query returns 0 records
-	itr, err = db.ExecuteQuery("{\"selector\":{\"owner\":\"not_a_valid_name\"}}")
+	itr, err = db.ExecuteQuery("ns1", "{\"selector\":{\"owner\":\"not_a_valid_name\"}}")
 	testutil.AssertNoError(t, err, "")
 
 	// verify no results
@@ -223,3 +299,103 @@ func TestQuery(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 	testutil.AssertNoError(t, err, "")
 	testutil.AssertNil(t, queryResult3)
 }
+
+// TestQueryPagination tests the queries that return the results from a start key and limit them
+func TestQueryPagination(t *testing.T, dbProvider statedb.VersionedDBProvider) {
+	db, err := dbProvider.GetDBHandle("TestDB")
+	testutil.AssertNoError(t, err, "")
+	db.Open()
+	defer db.Close()
+	batch := statedb.NewUpdateBatch()
+	for i := 1; i <= 5; i++ {
+		jsonValue := fmt.Sprintf("{\"asset_name\": \"marble%d\",\"owner\": \"tom\"}", i)
+		batch.Put("ns1", fmt.Sprintf("key%d", i), []byte(jsonValue), version.NewHeight(1, uint64(i)))
+	}
+	batch.Put("ns1", "key6", []byte("{\"asset_name\": \"marble6\",\"owner\": \"jerry\"}"), version.NewHeight(1, 6))
+	batch.Put("ns2", "key7", []byte("{\"asset_name\": \"marble7\",\"owner\": \"tom\"}"), version.NewHeight(1, 7))
+	db.ApplyUpdates(batch, version.NewHeight(2, 1))
+
+	query := "{\"selector\":{\"owner\":\"tom\"}}"
+	itr, err := db.ExecuteQueryWithPagination("ns1", query, "", 2)
+	testutil.AssertNoError(t, err, "")
+	testQueryItr(t, itr, []string{"key1", "key2"})
+
+	itr, err = db.ExecuteQueryWithPagination("ns1", query, "key3", 2)
+	testutil.AssertNoError(t, err, "")
+	testQueryItr(t, itr, []string{"key3", "key4"})
+
+	// the start key does not need to be the key of a result
+	itr, err = db.ExecuteQueryWithPagination("ns1", query, "key45", 2)
+	testutil.AssertNoError(t, err, "")
+	testQueryItr(t, itr, []string{"key5"})
+
+	itr, err = db.ExecuteQueryWithPagination("ns1", query, "", 0)
+	testutil.AssertNoError(t, err, "")
+	testQueryItr(t, itr, []string{"key1", "key2", "key3", "key4", "key5"})
+
+	// the limit of the query applies from the start key
+	itr, err = db.ExecuteQueryWithPagination("ns1", "{\"selector\":{\"owner\":\"tom\"},\"limit\":3}", "key2", 5)
+	testutil.AssertNoError(t, err, "")
+	testQueryItr(t, itr, []string{"key2", "key3", "key4"})
+
+	// the results of a paginated query are ordered by key
+	_, err = db.ExecuteQueryWithPagination("ns1", "{\"selector\":{\"owner\":\"tom\"},\"skip\":1}", "", 2)
+	testutil.AssertError(t, err, "Should have received an error for a paginated query with a skip")
+	_, err = db.ExecuteQueryWithPagination("ns1", "{\"selector\":{\"owner\":\"tom\"},\"sort\":[\"owner\"]}", "", 2)
+	testutil.AssertError(t, err, "Should have received an error for a paginated query with a sort")
+}
+
+func testQueryItr(t *testing.T, itr statedb.ResultsIterator, expectedKeys []string) {
+	defer itr.Close()
+	for _, expectedKey := range expectedKeys {
+		queryResult, err := itr.Next()
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertNotNil(t, queryResult)
+		testutil.AssertEquals(t, queryResult.(*statedb.VersionedQueryRecord).Key, expectedKey)
+	}
+	last, err := itr.Next()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, last)
+}
+
+// TestStateHash tests that the state hash depends only on the state and not on how the state was arrived at
+func TestStateHash(t *testing.T, dbProvider statedb.VersionedDBProvider) {
+	os.RemoveAll(ledgerconfig.GetStateHashLevelDBPath())
+	defer os.RemoveAll(ledgerconfig.GetStateHashLevelDBPath())
+	hashDBProvider := statehash.NewVersionedDBProvider(dbProvider)
+	defer hashDBProvider.Close()
+	db1, err := hashDBProvider.GetDBHandle("TestDB1")
+	testutil.AssertNoError(t, err, "")
+	db2, err := hashDBProvider.GetDBHandle("TestDB2")
+	testutil.AssertNoError(t, err, "")
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	batch.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 2))
+	batch.Put("ns2", "key1", []byte("value3"), version.NewHeight(1, 3))
+	testutil.AssertNoError(t, db1.ApplyUpdates(batch, version.NewHeight(1, 3)), "")
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns1", "key2", []byte("value2-updated"), version.NewHeight(2, 1))
+	testutil.AssertNoError(t, db1.ApplyUpdates(batch, version.NewHeight(2, 1)), "")
+
+	// the same state arrived at in a single batch with different versions
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns2", "key1", []byte("value3"), version.NewHeight(1, 1))
+	batch.Put("ns1", "key2", []byte("value2-updated"), version.NewHeight(1, 2))
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 3))
+	testutil.AssertNoError(t, db2.ApplyUpdates(batch, version.NewHeight(1, 3)), "")
+
+	stateHash1, err := db1.GetStateHash(1)
+	testutil.AssertNoError(t, err, "")
+	stateHash2, err := db1.GetStateHash(2)
+	testutil.AssertNoError(t, err, "")
+	otherStateHash, err := db2.GetStateHash(1)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNotNil(t, stateHash2)
+	testutil.AssertEquals(t, otherStateHash, stateHash2)
+	testutil.AssertNotEquals(t, stateHash1, stateHash2)
+
+	stateHash, err := db1.GetStateHash(3)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, stateHash)
+}
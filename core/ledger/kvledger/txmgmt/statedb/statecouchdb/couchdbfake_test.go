@@ -1,5 +1,7 @@
+// +build couchdbfake
+
 /*
-Copyright IBM Corp. 2016 All Rights Reserved.
+Copyright IBM Corp. 2017 All Rights Reserved.
 
 Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
@@ -14,25 +16,13 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-syntax = "proto3";
-
-option go_package = "github.com/hyperledger/fabric/orderer/sbft";
-
-package sbft;
-
-import "simplebft/simplebft.proto";
-
-message consensus_config {
-        simplebft.Config consensus = 1;
-        map<string, bytes> peers = 2;
-};
+package statecouchdb
 
-message json_config {
-        simplebft.Config consensus = 1;
-        repeated peer peers = 2;
-};
+import "github.com/hyperledger/fabric/core/ledger/internal/couchdbtest"
 
-message peer {
-        string address = 1;
-        string cert = 2;
-};
+// startCouchDBStandIn starts the in-memory stand-in for CouchDB and returns its address
+// along with the function stopping it
+func startCouchDBStandIn() (string, func()) {
+	couchDBServer := couchdbtest.NewServer()
+	return couchDBServer.Address(), couchDBServer.Close
+}
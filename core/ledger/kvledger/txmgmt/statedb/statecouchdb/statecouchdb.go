@@ -141,15 +141,25 @@ func (vdb *VersionedDB) GetState(namespace string, key string) (*statedb.Version
 }
 
 // GetStateMultipleKeys implements method in VersionedDB interface
+// The documents for all the keys are retrieved from CouchDB in a single request
 func (vdb *VersionedDB) GetStateMultipleKeys(namespace string, keys []string) ([]*statedb.VersionedValue, error) {
 
-	vals := make([]*statedb.VersionedValue, len(keys))
+	compositeKeys := make([]string, len(keys))
 	for i, key := range keys {
-		val, err := vdb.GetState(namespace, key)
-		if err != nil {
-			return nil, err
+		compositeKeys[i] = string(constructCompositeKey(namespace, key))
+	}
+	queryResults, err := vdb.db.BatchRetrieveDocuments(compositeKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	vals := make([]*statedb.VersionedValue, len(keys))
+	for i, queryResult := range queryResults {
+		if queryResult == nil {
+			continue
 		}
-		vals[i] = val
+		//TODO - change hardcoded version (1,1) when version support is available in CouchDB
+		vals[i] = &statedb.VersionedValue{Value: queryResult.Value, Version: version.NewHeight(1, 1)}
 	}
 	return vals, nil
 
@@ -158,6 +168,7 @@ func (vdb *VersionedDB) GetStateMultipleKeys(namespace string, keys []string) ([
 // GetStateRangeScanIterator implements method in VersionedDB interface
 // startKey is inclusive
 // endKey is exclusive
+// The documents are fetched from CouchDB one page at a time as the iterator advances
 func (vdb *VersionedDB) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (statedb.ResultsIterator, error) {
 
 	compositeStartKey := constructCompositeKey(namespace, startKey)
@@ -165,36 +176,107 @@ func (vdb *VersionedDB) GetStateRangeScanIterator(namespace string, startKey str
 	if endKey == "" {
 		compositeEndKey[len(compositeEndKey)-1] = lastKeyIndicator
 	}
-	queryResult, err := vdb.db.ReadDocRange(string(compositeStartKey), string(compositeEndKey), 1000, 0)
-	if err != nil {
-		logger.Debugf("Error calling ReadDocRange(): %s\n", err.Error())
+	docScanner := &docRangeScanner{db: vdb.db, startKey: string(compositeStartKey), endKey: string(compositeEndKey)}
+	if err := docScanner.fetchNextPage(); err != nil {
 		return nil, err
 	}
 	logger.Debugf("Exiting GetStateRangeScanIterator")
-	return newKVScanner(namespace, *queryResult), nil
+	return &kvScanner{namespace, docScanner}, nil
 
 }
 
-// ExecuteQuery implements method in VersionedDB interface
-func (vdb *VersionedDB) ExecuteQuery(query string) (statedb.ResultsIterator, error) {
+// GetFullScanIterator implements method in VersionedDB interface
+// The documents are fetched from CouchDB one page at a time as the iterator advances
+func (vdb *VersionedDB) GetFullScanIterator() (statedb.ResultsIterator, error) {
+	return &fullScanner{&docRangeScanner{db: vdb.db}}, nil
+}
 
-	//TODO - limit is currently set at 1000,  eventually this will need to be changed
-	//to reflect a config option and potentially return an exception if the threshold is exceeded
-	// skip (paging) is not utilized by fabric
-	queryResult, err := vdb.db.QueryDocuments(query, 1000, 0)
+// ExecuteQuery implements method in VersionedDB interface
+// The selector of the query is combined with a range on the document ids so that only
+// the documents of the given namespace are returned. An empty namespace queries all the documents
+func (vdb *VersionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIterator, error) {
+	jsonQuery, querySkip, queryLimit, err := parseNamespaceQuery(namespace, query)
 	if err != nil {
-		logger.Debugf("Error calling QueryDocuments(): %s\n", err.Error())
+		return nil, err
+	}
+
+	// remaining is the number of documents still to be returned, -1 for no limit
+	remaining := -1
+	if queryLimit > 0 {
+		remaining = queryLimit
+	}
+
+	scanner := &queryScanner{db: vdb.db, query: jsonQuery, skip: querySkip, remaining: remaining}
+	if err = scanner.fetchNextPage(); err != nil {
 		return nil, err
 	}
 	logger.Debugf("Exiting ExecuteQuery")
-	return newQueryScanner(*queryResult), nil
+	return scanner, nil
+}
+
+// ExecuteQueryWithPagination implements method in VersionedDB interface
+// The results are sorted by id and the documents are fetched from CouchDB one page at a time as the iterator
+// advances, each page selecting the documents that follow the last document of the previous page
+func (vdb *VersionedDB) ExecuteQueryWithPagination(namespace, query string, startKey string, limit int) (statedb.ResultsIterator, error) {
+	if startKey != "" && namespace == "" {
+		return nil, errors.New("A paginated query across all the namespaces cannot start from a key")
+	}
+	jsonQuery, _, queryLimit, err := parseNamespaceQuery(namespace, query)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range []string{"sort", "skip"} {
+		if _, ok := jsonQuery[field]; ok {
+			return nil, fmt.Errorf("Invalid query: the results of a paginated query are ordered by key, %s is not supported", field)
+		}
+	}
+
+	// remaining is the number of documents still to be returned, -1 for no limit
+	remaining := -1
+	if queryLimit > 0 {
+		remaining = queryLimit
+	}
+	if limit > 0 && (remaining < 0 || limit < remaining) {
+		remaining = limit
+	}
+
+	jsonQuery["sort"] = []interface{}{map[string]interface{}{"_id": "asc"}}
+	scanner := &queryScanner{db: vdb.db, query: jsonQuery, selector: jsonQuery["selector"], orderedByKey: true, remaining: remaining}
+	if startKey != "" {
+		scanner.startKey = string(constructCompositeKey(namespace, startKey))
+	}
+	if err = scanner.fetchNextPage(); err != nil {
+		return nil, err
+	}
+	logger.Debugf("Exiting ExecuteQueryWithPagination")
+	return scanner, nil
 }
 
 // ApplyUpdates implements method in VersionedDB interface
+// The revisions of the existing documents for the keys in the batch are retrieved in a single request,
+// and the documents are then saved, updated and deleted in a single bulk update
 func (vdb *VersionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version.Height) error {
 
+	keys := []string{}
+	for ck := range batch.KVs {
+		keys = append(keys, string(constructCompositeKey(ck.Namespace, ck.Key)))
+	}
+
+	revisions := make(map[string]string)
+	if len(keys) > 0 {
+		docMetadataArray, err := vdb.db.BatchRetrieveIDRevision(keys)
+		if err != nil {
+			logger.Errorf("Error during Commit(): %s\n", err.Error())
+			return err
+		}
+		for _, docMetadata := range docMetadataArray {
+			revisions[docMetadata.ID] = docMetadata.Rev
+		}
+	}
+
+	docs := []*couchdb.CouchDoc{}
 	for ck, vv := range batch.KVs {
-		compositeKey := constructCompositeKey(ck.Namespace, ck.Key)
+		compositeKey := string(constructCompositeKey(ck.Namespace, ck.Key))
 
 		// trace the first 200 characters of versioned value only, in case it is huge
 		if logger.IsEnabledFor(logging.DEBUG) {
@@ -205,47 +287,40 @@ func (vdb *VersionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version
 			logger.Debugf("Applying key=%#v, versionedValue=%s", ck, versionedValueDump)
 		}
 
-		// TODO add delete logic for couch using this approach from stateleveldb - convert nils to deletes
-		/*		if vv.Value == nil {
-					levelBatch.Delete(compositeKey)
-				} else {
-					levelBatch.Put(compositeKey, encodeValue(vv.Value, vv.Version))
-				}
-		*/
-
-		if couchdb.IsJSON(string(vv.Value)) {
-
-			// SaveDoc using couchdb client and use JSON format
-			rev, err := vdb.db.SaveDoc(string(compositeKey), "", vv.Value, nil)
-			if err != nil {
-				logger.Errorf("Error during Commit(): %s\n", err.Error())
-				return err
-			}
-			if rev != "" {
-				logger.Debugf("Saved document revision number: %s\n", rev)
-			}
-
-		} else { // if the data is not JSON, save as binary attachment in Couch
+		rev, exists := revisions[compositeKey]
 
-			//Create an attachment structure and load the bytes
-			attachment := &couchdb.Attachment{}
+		if vv.Value == nil {
+			// a delete of a key that does not exist (e.g., deleted again while reapplying a batch) is a no-op
+			if exists {
+				docs = append(docs, &couchdb.CouchDoc{ID: compositeKey, Rev: rev, Deleted: true})
+			}
+		} else if couchdb.IsJSON(string(vv.Value)) {
+			// save the value in JSON format
+			docs = append(docs, &couchdb.CouchDoc{ID: compositeKey, Rev: rev, JSONValue: vv.Value})
+		} else {
+			// if the data is not JSON, save as binary attachment in Couch
+			attachment := couchdb.Attachment{}
 			attachment.AttachmentBytes = vv.Value
 			attachment.ContentType = "application/octet-stream"
 			attachment.Name = "valueBytes"
+			docs = append(docs, &couchdb.CouchDoc{ID: compositeKey, Rev: rev, Attachments: []couchdb.Attachment{attachment}})
+		}
+	}
 
-			attachments := []couchdb.Attachment{}
-			attachments = append(attachments, *attachment)
-
-			// SaveDoc using couchdb client and use attachment to persist the binary data
-			rev, err := vdb.db.SaveDoc(string(compositeKey), "", nil, attachments)
-			if err != nil {
-				logger.Errorf("Error during Commit(): %s\n", err.Error())
-				return err
-			}
-			if rev != "" {
-				logger.Debugf("Saved document revision number: %s\n", rev)
+	if len(docs) > 0 {
+		responses, err := vdb.db.BatchUpdateDocuments(docs)
+		if err != nil {
+			logger.Errorf("Error during Commit(): %s\n", err.Error())
+			return err
+		}
+		// CouchDB does not apply a bulk update atomically, the response for each of the documents
+		// needs to be checked. A failed batch is applied again during the recovery from the savepoint
+		for _, response := range responses {
+			if response.Error != "" {
+				logger.Errorf("Error during Commit(): document [%s]: %s: %s\n", response.ID, response.Error, response.Reason)
+				return fmt.Errorf("Error while saving document [%s]: %s: %s", response.ID, response.Error, response.Reason)
 			}
-
+			logger.Debugf("Saved document [%s] revision number: %s\n", response.ID, response.Rev)
 		}
 	}
 
@@ -348,30 +423,157 @@ func constructCompositeKey(ns string, key string) []byte {
 	return compositeKey
 }
 
+// parseQuery parses a CouchDB query, the numbers being kept as json.Number
+func parseQuery(query string) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(strings.NewReader(query))
+	decoder.UseNumber()
+	jsonQuery := make(map[string]interface{})
+	if err := decoder.Decode(&jsonQuery); err != nil {
+		return nil, fmt.Errorf("Invalid query [%s]: %s", query, err)
+	}
+	if _, ok := jsonQuery["selector"]; !ok {
+		return nil, fmt.Errorf("Invalid query [%s]: selector is missing", query)
+	}
+	return jsonQuery, nil
+}
+
+// parseNamespaceQuery parses a CouchDB query, restricting it to the documents of the namespace unless the
+// namespace is empty, and returns the query along with its skip and its limit
+func parseNamespaceQuery(namespace, query string) (map[string]interface{}, int, int, error) {
+	jsonQuery, err := parseQuery(query)
+	if err != nil {
+		logger.Debugf("Error parsing the query: %s\n", err.Error())
+		return nil, 0, 0, err
+	}
+	if namespace != "" {
+		if err = addNamespaceToQuery(namespace, jsonQuery); err != nil {
+			logger.Debugf("Error adding the namespace to the query: %s\n", err.Error())
+			return nil, 0, 0, err
+		}
+	}
+	querySkip, queryLimit, err := getSkipAndLimit(jsonQuery)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return jsonQuery, querySkip, queryLimit, nil
+}
+
+// addNamespaceToQuery restricts the selector of a CouchDB query to the ids of the documents
+// of the namespace, i.e. the ids between ns+compositeKeySep and ns+lastKeyIndicator
+func addNamespaceToQuery(namespace string, jsonQuery map[string]interface{}) error {
+	namespaceSelector := map[string]interface{}{
+		"_id": map[string]interface{}{
+			"$gte": string(constructCompositeKey(namespace, "")),
+			"$lt":  namespace + string(lastKeyIndicator),
+		},
+	}
+	selector, ok := jsonQuery["selector"]
+	if !ok {
+		return errors.New("Invalid query: selector is missing")
+	}
+	jsonQuery["selector"] = map[string]interface{}{
+		"$and": []interface{}{selector, namespaceSelector},
+	}
+	return nil
+}
+
+// getSkipAndLimit returns the skip and the limit of a parsed CouchDB query, 0 when not present
+func getSkipAndLimit(jsonQuery map[string]interface{}) (int, int, error) {
+	values := make([]int, 2)
+	for i, field := range []string{"skip", "limit"} {
+		value, ok := jsonQuery[field]
+		if !ok {
+			continue
+		}
+		number, ok := value.(json.Number)
+		if !ok {
+			return 0, 0, fmt.Errorf("Invalid query: %s is not a number", field)
+		}
+		intValue, err := number.Int64()
+		if err != nil || intValue < 0 {
+			return 0, 0, fmt.Errorf("Invalid query: %s is not a positive integer", field)
+		}
+		values[i] = int(intValue)
+	}
+	return values[0], values[1], nil
+}
+
 func splitCompositeKey(compositeKey []byte) (string, string) {
 	split := bytes.SplitN(compositeKey, compositeKeySep, 2)
 	return string(split[0]), string(split[1])
 }
 
-type kvScanner struct {
-	cursor    int
-	namespace string
+// docRangeScanner iterates over the documents of a range of ids, the documents being fetched
+// from CouchDB one page at a time. Each page starts from the id of the last document of the
+// previous page, which is dropped from the page. Empty startKey and endKey refer to the first
+// and the last document
+type docRangeScanner struct {
+	db        *couchdb.CouchDatabase
+	startKey  string
+	endKey    string
 	results   []couchdb.QueryResult
+	cursor    int
+	lastKey   string
+	exhausted bool
 }
 
-func newKVScanner(namespace string, queryResults []couchdb.QueryResult) *kvScanner {
-	return &kvScanner{-1, namespace, queryResults}
+func (scanner *docRangeScanner) fetchNextPage() error {
+	startKey, limit := scanner.startKey, ledgerconfig.GetQueryLimit()
+	if scanner.lastKey != "" {
+		// one more document for the last document of the previous page
+		startKey, limit = scanner.lastKey, limit+1
+	}
+	queryResult, err := scanner.db.ReadDocRange(startKey, scanner.endKey, limit, 0)
+	if err != nil {
+		logger.Debugf("Error calling ReadDocRange(): %s\n", err.Error())
+		return err
+	}
+	results := *queryResult
+	scanner.exhausted = len(results) < limit
+	if len(results) > 0 && results[0].ID == scanner.lastKey {
+		results = results[1:]
+	}
+	if len(results) > 0 {
+		scanner.lastKey = results[len(results)-1].ID
+	}
+	scanner.results = results
+	scanner.cursor = 0
+	return nil
 }
 
-func (scanner *kvScanner) Next() (statedb.QueryResult, error) {
-
-	scanner.cursor++
-
+func (scanner *docRangeScanner) next() (*couchdb.QueryResult, error) {
 	if scanner.cursor >= len(scanner.results) {
-		return nil, nil
+		if scanner.exhausted {
+			return nil, nil
+		}
+		if err := scanner.fetchNextPage(); err != nil {
+			return nil, err
+		}
+		if len(scanner.results) == 0 {
+			return nil, nil
+		}
 	}
+	result := &scanner.results[scanner.cursor]
+	scanner.cursor++
+	return result, nil
+}
+
+func (scanner *docRangeScanner) close() {
+	scanner.results = nil
+	scanner.exhausted = true
+}
+
+type kvScanner struct {
+	namespace string
+	scanner   *docRangeScanner
+}
 
-	selectedKV := scanner.results[scanner.cursor]
+func (scanner *kvScanner) Next() (statedb.QueryResult, error) {
+
+	selectedKV, err := scanner.scanner.next()
+	if err != nil || selectedKV == nil {
+		return nil, err
+	}
 
 	_, key := splitCompositeKey([]byte(selectedKV.ID))
 
@@ -382,38 +584,137 @@ func (scanner *kvScanner) Next() (statedb.QueryResult, error) {
 }
 
 func (scanner *kvScanner) Close() {
-	scanner = nil
+	scanner.scanner.close()
 }
 
-type queryScanner struct {
-	cursor  int
-	results []couchdb.QueryResult
+// fullScanner iterates over the key-values of all the namespaces, skipping the documents that do not
+// hold a key-value (such as the savepoint)
+type fullScanner struct {
+	scanner *docRangeScanner
 }
 
-func newQueryScanner(queryResults []couchdb.QueryResult) *queryScanner {
-	return &queryScanner{-1, queryResults}
+func (scanner *fullScanner) Next() (statedb.QueryResult, error) {
+	for {
+		selectedKV, err := scanner.scanner.next()
+		if err != nil || selectedKV == nil {
+			return nil, err
+		}
+		if !bytes.Contains([]byte(selectedKV.ID), compositeKeySep) {
+			continue
+		}
+		namespace, key := splitCompositeKey([]byte(selectedKV.ID))
+		//TODO - change hardcoded version (1,1) when version header is available in CouchDB
+		return &statedb.VersionedKV{
+			CompositeKey:   statedb.CompositeKey{Namespace: namespace, Key: key},
+			VersionedValue: statedb.VersionedValue{Value: selectedKV.Value, Version: version.NewHeight(1, 1)}}, nil
+	}
 }
 
-func (scanner *queryScanner) Next() (statedb.QueryResult, error) {
+func (scanner *fullScanner) Close() {
+	scanner.scanner.close()
+}
 
-	scanner.cursor++
+// queryScanner iterates over the results of a query, the documents being fetched from CouchDB
+// one page at a time. When the results are ordered by key, each page selects the documents that
+// follow the last document of the previous page, otherwise the pages are fetched by setting the
+// skip and the limit of the query
+type queryScanner struct {
+	db           *couchdb.CouchDatabase
+	query        map[string]interface{}
+	selector     interface{}
+	orderedByKey bool
+	startKey     string
+	lastKey      string
+	results      []couchdb.QueryResult
+	cursor       int
+	skip         int
+	remaining    int
+	exhausted    bool
+}
 
-	if scanner.cursor >= len(scanner.results) {
-		return nil, nil
+func (scanner *queryScanner) fetchNextPage() error {
+	pageSize := ledgerconfig.GetQueryLimit()
+	if scanner.remaining >= 0 && scanner.remaining < pageSize {
+		pageSize = scanner.remaining
+	}
+	if pageSize == 0 {
+		scanner.results = nil
+		scanner.exhausted = true
+		return nil
+	}
+	if scanner.orderedByKey {
+		var keySelector map[string]interface{}
+		if scanner.lastKey != "" {
+			keySelector = map[string]interface{}{"_id": map[string]interface{}{"$gt": scanner.lastKey}}
+		} else if scanner.startKey != "" {
+			keySelector = map[string]interface{}{"_id": map[string]interface{}{"$gte": scanner.startKey}}
+		}
+		if keySelector != nil {
+			scanner.query["selector"] = map[string]interface{}{"$and": []interface{}{scanner.selector, keySelector}}
+		}
+	} else {
+		scanner.query["skip"] = scanner.skip
+	}
+	scanner.query["limit"] = pageSize
+	queryBytes, err := json.Marshal(scanner.query)
+	if err != nil {
+		return err
+	}
+	queryResult, err := scanner.db.QueryDocuments(string(queryBytes), pageSize, scanner.skip)
+	if err != nil {
+		logger.Debugf("Error calling QueryDocuments(): %s\n", err.Error())
+		return err
 	}
+	scanner.results = *queryResult
+	scanner.cursor = 0
+	if scanner.orderedByKey {
+		if len(scanner.results) > 0 {
+			scanner.lastKey = scanner.results[len(scanner.results)-1].ID
+		}
+	} else {
+		scanner.skip += len(scanner.results)
+	}
+	if scanner.remaining >= 0 {
+		scanner.remaining -= len(scanner.results)
+	}
+	scanner.exhausted = len(scanner.results) < pageSize || scanner.remaining == 0
+	return nil
+}
 
-	selectedResultRecord := scanner.results[scanner.cursor]
+// Next skips the documents that do not hold a key-value (such as the savepoint),
+// which can be returned by the queries that are not restricted to a namespace
+func (scanner *queryScanner) Next() (statedb.QueryResult, error) {
+	for {
+		if scanner.cursor >= len(scanner.results) {
+			if scanner.exhausted {
+				return nil, nil
+			}
+			if err := scanner.fetchNextPage(); err != nil {
+				return nil, err
+			}
+			if len(scanner.results) == 0 {
+				return nil, nil
+			}
+		}
+
+		selectedResultRecord := scanner.results[scanner.cursor]
+		scanner.cursor++
+		if !bytes.Contains([]byte(selectedResultRecord.ID), compositeKeySep) {
+			continue
+		}
 
-	namespace, key := splitCompositeKey([]byte(selectedResultRecord.ID))
+		namespace, key := splitCompositeKey([]byte(selectedResultRecord.ID))
 
-	//TODO - change hardcoded version (1,1) when version support is available in CouchDB
-	return &statedb.VersionedQueryRecord{
-		Namespace: namespace,
-		Key:       key,
-		Version:   version.NewHeight(1, 1),
-		Record:    selectedResultRecord.Value}, nil
+		//TODO - change hardcoded version (1,1) when version support is available in CouchDB
+		return &statedb.VersionedQueryRecord{
+			Namespace: namespace,
+			Key:       key,
+			Version:   version.NewHeight(1, 1),
+			Record:    selectedResultRecord.Value}, nil
+	}
 }
 
 func (scanner *queryScanner) Close() {
-	scanner = nil
+	scanner.results = nil
+	scanner.exhausted = true
 }
@@ -17,62 +17,90 @@ limitations under the License.
 package statecouchdb
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"testing"
 
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/commontests"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/testutil"
 	"github.com/spf13/viper"
 )
 
+//couchDBUnavailable is set when neither CouchDB nor its stand-in is available to the tests
+var couchDBUnavailable bool
+
+const couchDBUnavailableMsg = "Skipping the tests requiring CouchDB, as CouchDB is not enabled in core.yaml and the tests were not built with the couchdbfake tag"
+
 func TestMain(m *testing.M) {
 
 	//call a helper method to load the core.yaml, will be used to detect if CouchDB is enabled
 	testutil.SetupCoreYAMLConfig("./../../../../../../peer")
 
-	viper.Set("ledger.state.couchDBConfig.couchDBAddress", "127.0.0.1:5984")
+	//if CouchDB is not enabled, the tests run against a local stand-in for CouchDB if built with it
+	if ledgerconfig.IsCouchDBEnabled() == true {
+		viper.Set("ledger.state.couchDBConfig.couchDBAddress", "127.0.0.1:5984")
+		os.Exit(m.Run())
+	}
+	address, stop := startCouchDBStandIn()
+	if stop == nil {
+		fmt.Fprintln(os.Stderr, couchDBUnavailableMsg)
+		couchDBUnavailable = true
+		os.Exit(m.Run())
+	}
+	viper.Set("ledger.state.couchDBConfig.couchDBAddress", address)
+	result := m.Run()
+	stop()
+	os.Exit(result)
+}
 
-	os.Exit(m.Run())
+//newTestVDBEnv skips the test if CouchDB is unavailable and creates a TestVDBEnv otherwise
+func newTestVDBEnv(t *testing.T) *TestVDBEnv {
+	if couchDBUnavailable {
+		t.Skip(couchDBUnavailableMsg)
+	}
+	return NewTestVDBEnv(t)
 }
 
 //TODO add wrapper for version in couchdb to resolve final tests in TestBasicRW and TestMultiDBBasicRW
 func TestBasicRW(t *testing.T) {
-	if ledgerconfig.IsCouchDBEnabled() == true {
-
-		env := NewTestVDBEnv(t)
-		defer env.Cleanup()
-		commontests.TestBasicRW(t, env.DBProvider)
-
-	}
+	env := newTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestBasicRW(t, env.DBProvider)
 }
 
 func TestMultiDBBasicRW(t *testing.T) {
-	if ledgerconfig.IsCouchDBEnabled() == true {
-
-		env := NewTestVDBEnv(t)
-		defer env.Cleanup()
-		commontests.TestMultiDBBasicRW(t, env.DBProvider)
+	env := newTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestMultiDBBasicRW(t, env.DBProvider)
+}
 
-	}
+func TestDeleteAndReAdd(t *testing.T) {
+	env := newTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestDeleteAndReAdd(t, env.DBProvider)
 }
 
-/* TODO add delete support in couchdb and then convert key value of nil to a couch delete. This will resolve TestDeletes
+/* TODO re-visit after adding version wrapper in couchdb, TestDeletes verifies the versions of the values
 func TestDeletes(t *testing.T) {
-	env := NewTestVDBEnv(t)
+	env := newTestVDBEnv(t)
 	defer env.Cleanup()
 	commontests.TestDeletes(t, env.DBProvider)
 }
 */
 
 func TestIterator(t *testing.T) {
-	if ledgerconfig.IsCouchDBEnabled() == true {
-
-		env := NewTestVDBEnv(t)
-		defer env.Cleanup()
-		commontests.TestIterator(t, env.DBProvider)
+	env := newTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestIterator(t, env.DBProvider)
+}
 
-	}
+func TestStateHash(t *testing.T) {
+	env := newTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestStateHash(t, env.DBProvider)
 }
 
 /* TODO re-visit after adding version wrapper in couchdb
@@ -90,12 +118,8 @@ func testValueAndVersionEncodeing(t *testing.T, value []byte, version *version.H
 */
 
 func TestCompositeKey(t *testing.T) {
-	if ledgerconfig.IsCouchDBEnabled() == true {
-
-		testCompositeKey(t, "ns", "key")
-		testCompositeKey(t, "ns", "")
-
-	}
+	testCompositeKey(t, "ns", "key")
+	testCompositeKey(t, "ns", "")
 }
 
 func testCompositeKey(t *testing.T, ns string, key string) {
@@ -110,11 +134,63 @@ func testCompositeKey(t *testing.T, ns string, key string) {
 
 //  query test
 func TestQuery(t *testing.T) {
-	if ledgerconfig.IsCouchDBEnabled() == true {
+	env := newTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestQuery(t, env.DBProvider)
+}
 
-		env := NewTestVDBEnv(t)
-		defer env.Cleanup()
-		commontests.TestQuery(t, env.DBProvider)
+func TestQueryPagination(t *testing.T) {
+	env := newTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestQueryPagination(t, env.DBProvider)
+}
 
+// TestIteratorPaging runs the iterator tests with pages of two documents fetched from CouchDB
+func TestIteratorPaging(t *testing.T) {
+	viper.Set("ledger.state.couchDBConfig.queryLimit", 2)
+	defer viper.Set("ledger.state.couchDBConfig.queryLimit", 1000)
+	tests := []func(*testing.T, statedb.VersionedDBProvider){
+		commontests.TestIterator, commontests.TestQuery, commontests.TestQueryPagination}
+	for _, test := range tests {
+		env := newTestVDBEnv(t)
+		test(t, env.DBProvider)
+		env.Cleanup()
 	}
 }
+
+func TestAddNamespaceToQuery(t *testing.T) {
+	jsonQuery, err := parseQuery(`{"selector":{"owner":"jerry"},"limit":10}`)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNoError(t, addNamespaceToQuery("ns1", jsonQuery), "")
+	query, _ := json.Marshal(jsonQuery)
+	testutil.AssertEquals(t, string(query),
+		`{"limit":10,"selector":{"$and":[{"owner":"jerry"},{"_id":{"$gte":"ns1\u0000","$lt":"ns1\u0001"}}]}}`)
+
+	_, err = parseQuery("this is an invalid query string")
+	testutil.AssertError(t, err, "Should have received an error for invalid query string")
+
+	_, err = parseQuery(`{"fields":["owner"]}`)
+	testutil.AssertError(t, err, "Should have received an error for a query without selector")
+}
+
+func TestGetSkipAndLimit(t *testing.T) {
+	jsonQuery, _ := parseQuery(`{"selector":{"owner":"jerry"},"skip":5,"limit":10}`)
+	skip, limit, err := getSkipAndLimit(jsonQuery)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, skip, 5)
+	testutil.AssertEquals(t, limit, 10)
+
+	jsonQuery, _ = parseQuery(`{"selector":{"owner":"jerry"}}`)
+	skip, limit, err = getSkipAndLimit(jsonQuery)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, skip, 0)
+	testutil.AssertEquals(t, limit, 0)
+
+	jsonQuery, _ = parseQuery(`{"selector":{"owner":"jerry"},"limit":"10"}`)
+	_, _, err = getSkipAndLimit(jsonQuery)
+	testutil.AssertError(t, err, "Should have received an error for a limit that is not a number")
+
+	jsonQuery, _ = parseQuery(`{"selector":{"owner":"jerry"},"skip":-1}`)
+	_, _, err = getSkipAndLimit(jsonQuery)
+	testutil.AssertError(t, err, "Should have received an error for a negative skip")
+}
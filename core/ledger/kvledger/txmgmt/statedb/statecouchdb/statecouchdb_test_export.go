@@ -20,15 +20,10 @@ import (
 	"testing"
 
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/util/couchdb"
 )
 
-//Basic setup to test couch
-var connectURL = "localhost:5984"
-var badConnectURL = "localhost:5990"
-var username = ""
-var password = ""
-
 // TestVDBEnv provides a level db backed versioned db for testing
 type TestVDBEnv struct {
 	t          testing.TB
@@ -55,8 +50,9 @@ func (env *TestVDBEnv) Cleanup() {
 
 }
 func cleanupDB(dbName string) {
-	//create a new connection
-	couchInstance, _ := couchdb.CreateCouchInstance(connectURL, username, password)
+	//create a new connection to the configured couch instance
+	couchDBDef := ledgerconfig.GetCouchDBDefinition()
+	couchInstance, _ := couchdb.CreateCouchInstance(couchDBDef.URL, couchDBDef.Username, couchDBDef.Password)
 	db, _ := couchdb.CreateCouchDatabase(*couchInstance, dbName)
 	//drop the test database
 	db.DropDatabase()
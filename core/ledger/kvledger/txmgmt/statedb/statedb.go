@@ -37,8 +37,16 @@ type VersionedDB interface {
 	// endKey is exclusive
 	// The returned ResultsIterator contains results of type *VersionedKV
 	GetStateRangeScanIterator(namespace string, startKey string, endKey string) (ResultsIterator, error)
+	// GetFullScanIterator returns an iterator that contains all the key-values of all the namespaces,
+	// ordered by namespace and key. The returned ResultsIterator contains results of type *VersionedKV
+	GetFullScanIterator() (ResultsIterator, error)
 	// ExecuteQuery executes the given query and returns an iterator that contains results of type *VersionedKV.
-	ExecuteQuery(query string) (ResultsIterator, error)
+	// The results are restricted to the given namespace, unless the namespace is empty
+	ExecuteQuery(namespace, query string) (ResultsIterator, error)
+	// ExecuteQueryWithPagination executes the given query as ExecuteQuery does, returning the results in the
+	// order of their keys from the key `startKey` and at most `limit` results. An empty startKey refers to
+	// the first key and a zero limit means that the results are not limited
+	ExecuteQueryWithPagination(namespace, query string, startKey string, limit int) (ResultsIterator, error)
 	// ApplyUpdates applies the batch to the underlying db.
 	// height is the height of the highest transaction in the Batch that
 	// a state db implementation is expected to ues as a save point
@@ -52,6 +60,14 @@ type VersionedDB interface {
 	Close()
 }
 
+// UpdatesPreparer is implemented by the VersionedDBs that can perform a part of applying a batch ahead of
+// ApplyUpdates. PrepareUpdates does not move the save point, and hence can be invoked while the block is
+// still being appended to the block storage
+type UpdatesPreparer interface {
+	// PrepareUpdates prepares the batch that is to be passed next to ApplyUpdates with the same height
+	PrepareUpdates(batch *UpdateBatch, height *version.Height) error
+}
+
 // CompositeKey encloses Namespace and Key components
 type CompositeKey struct {
 	Namespace string
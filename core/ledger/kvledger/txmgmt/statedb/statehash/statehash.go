@@ -0,0 +1,281 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statehash
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/core/ledger/util/leveldbhelper"
+	logging "github.com/op/go-logging"
+)
+
+var logger = logging.MustGetLogger("statehash")
+
+/*
+The state hash is a two level bucket structure over the world state.
+  -- each key-value of the state falls into one of the `numBuckets` buckets, based on the FNV-1a hash of its composite key
+  -- the hash of a key-value is the SHA256 hash of its namespace, key and value
+  -- the hash of a bucket is the SHA256 hash of the hashes of its key-values, in the order of the composite keys
+  -- the state hash is the SHA256 hash of the numbers and the hashes of the non-empty buckets, in the order of the bucket numbers
+
+The version of a key-value is not part of its hash, as not all the state db backends retain the versions.
+The number of buckets is fixed, as the state hash of the peers can be compared only if they use the same number of buckets.
+
+The hashes of the key-values and the buckets are maintained in a leveldb of their own (keyed as below) so that
+only the buckets touched by an update batch need to be rehashed, and that the state hash does not depend on how
+a state db backend represents the values it stores
+  -- entryKeyPrefix + bucketNum + namespace + compositeKeySep + key --> hash of the key-value
+  -- bucketKeyPrefix + bucketNum --> hash of the bucket
+  -- stateHashKeyPrefix + blockNum --> state hash as of the block
+*/
+const numBuckets = 1024
+
+var (
+	entryKeyPrefix     = []byte{'e'}
+	bucketKeyPrefix    = []byte{'b'}
+	stateHashKeyPrefix = []byte{'s'}
+	compositeKeySep    = []byte{0x00}
+)
+
+// VersionedDBProvider wraps a `statedb.VersionedDBProvider` and provides a `VersionedDB`
+// that maintains the state hash for each of the dbs of the wrapped provider
+type VersionedDBProvider struct {
+	vdbProvider statedb.VersionedDBProvider
+	dbProvider  *leveldbhelper.Provider
+}
+
+// NewVersionedDBProvider constructs a `VersionedDBProvider` that wraps the given provider.
+// The state hashes of all the dbs are maintained in a single leveldb
+func NewVersionedDBProvider(vdbProvider statedb.VersionedDBProvider) *VersionedDBProvider {
+	dbPath := ledgerconfig.GetStateHashLevelDBPath()
+	logger.Debugf("constructing state hash VersionedDBProvider dbPath=%s", dbPath)
+	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: dbPath})
+	return &VersionedDBProvider{vdbProvider, dbProvider}
+}
+
+// GetDBHandle returns a handle to the `VersionedDB` for the given id
+func (provider *VersionedDBProvider) GetDBHandle(dbName string) (*VersionedDB, error) {
+	vdb, err := provider.vdbProvider.GetDBHandle(dbName)
+	if err != nil {
+		return nil, err
+	}
+	return &VersionedDB{VersionedDB: vdb, db: provider.dbProvider.GetDBHandle(dbName)}, nil
+}
+
+// Close closes the wrapped provider and the underlying leveldb
+func (provider *VersionedDBProvider) Close() {
+	provider.vdbProvider.Close()
+	provider.dbProvider.Close()
+}
+
+// VersionedDB implements interface `statedb.VersionedDB` by delegating to the wrapped db,
+// and updates the state hash as the update batches are applied
+type VersionedDB struct {
+	statedb.VersionedDB
+	db            *leveldbhelper.DBHandle
+	preparedBatch *statedb.UpdateBatch
+}
+
+// PrepareUpdates implements method in UpdatesPreparer interface.
+// The state hash is updated with the batch, which leaves only the batch itself to be applied by ApplyUpdates
+func (vdb *VersionedDB) PrepareUpdates(batch *statedb.UpdateBatch, height *version.Height) error {
+	if err := vdb.updateStateHash(batch, height); err != nil {
+		return fmt.Errorf("Error while updating state hash: %s", err)
+	}
+	vdb.preparedBatch = batch
+	return nil
+}
+
+// ApplyUpdates implements method in VersionedDB interface.
+// The state hash is updated and recorded against `height.BlockNum` before the batch is applied to the wrapped db.
+// A batch applied with a zero height (such as a partial import of a snapshot) does not correspond to a block,
+// and the state hash is not recorded for it.
+// Updating the state hash with a batch is idempotent, so a batch that is applied again during a recovery
+// after a crash in between leaves the state hash as if the batch was applied once
+func (vdb *VersionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version.Height) error {
+	prepared := vdb.preparedBatch == batch
+	vdb.preparedBatch = nil
+	if !prepared {
+		if err := vdb.updateStateHash(batch, height); err != nil {
+			return fmt.Errorf("Error while updating state hash: %s", err)
+		}
+	}
+	return vdb.VersionedDB.ApplyUpdates(batch, height)
+}
+
+// GetStateHash returns the state hash as of the given block. nil is returned if the state hash
+// is not recorded for the block, e.g., the block was not committed on this peer
+func (vdb *VersionedDB) GetStateHash(blockNum uint64) ([]byte, error) {
+	return vdb.db.Get(constructStateHashKey(blockNum))
+}
+
+func (vdb *VersionedDB) updateStateHash(batch *statedb.UpdateBatch, height *version.Height) error {
+	// group the updated key-values by bucket
+	updates := make(map[uint32]map[string][]byte)
+	for ck, vv := range batch.KVs {
+		bucketNum := computeBucketNum(ck)
+		if updates[bucketNum] == nil {
+			updates[bucketNum] = make(map[string][]byte)
+		}
+		var entryHash []byte
+		if vv.Value != nil {
+			entryHash = computeEntryHash(ck, vv.Value)
+		}
+		updates[bucketNum][string(constructCompositeKey(ck))] = entryHash
+	}
+
+	dbBatch := leveldbhelper.NewUpdateBatch()
+	bucketHashes := make(map[uint32][]byte)
+	for bucketNum, bucketUpdates := range updates {
+		bucketHash, err := vdb.rehashBucket(bucketNum, bucketUpdates)
+		if err != nil {
+			return err
+		}
+		bucketHashes[bucketNum] = bucketHash
+		for compositeKey, entryHash := range bucketUpdates {
+			entryKey := constructEntryKey(bucketNum, []byte(compositeKey))
+			if entryHash == nil {
+				dbBatch.Delete(entryKey)
+			} else {
+				dbBatch.Put(entryKey, entryHash)
+			}
+		}
+		if bucketHash == nil {
+			dbBatch.Delete(constructBucketKey(bucketNum))
+		} else {
+			dbBatch.Put(constructBucketKey(bucketNum), bucketHash)
+		}
+	}
+
+	if height.BlockNum != 0 || height.TxNum != 0 {
+		stateHash := vdb.computeStateHash(bucketHashes)
+		dbBatch.Put(constructStateHashKey(height.BlockNum), stateHash)
+		logger.Debugf("State hash as of block [%d] = %#v", height.BlockNum, stateHash)
+	}
+	return vdb.db.WriteBatch(dbBatch, true)
+}
+
+// rehashBucket computes the hash of the bucket after applying the given updates to the entries in the bucket.
+// The updates map composite keys to the hashes of the key-values, a nil hash indicating a delete.
+// A nil hash is returned for a bucket that is left empty
+func (vdb *VersionedDB) rehashBucket(bucketNum uint32, updates map[string][]byte) ([]byte, error) {
+	entries := make(map[string][]byte)
+	bucketPrefix := constructEntryKey(bucketNum, nil)
+	itr := vdb.db.GetIterator(bucketPrefix, constructEntryKey(bucketNum+1, nil))
+	for itr.Next() {
+		entryHash := make([]byte, len(itr.Value()))
+		copy(entryHash, itr.Value())
+		entries[string(bytes.TrimPrefix(itr.Key(), bucketPrefix))] = entryHash
+	}
+	itr.Release()
+	if err := itr.Error(); err != nil {
+		return nil, err
+	}
+	for compositeKey, entryHash := range updates {
+		if entryHash == nil {
+			delete(entries, compositeKey)
+		} else {
+			entries[compositeKey] = entryHash
+		}
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	compositeKeys := make([]string, 0, len(entries))
+	for compositeKey := range entries {
+		compositeKeys = append(compositeKeys, compositeKey)
+	}
+	sort.Strings(compositeKeys)
+	bucketHash := sha256.New()
+	for _, compositeKey := range compositeKeys {
+		bucketHash.Write(entries[compositeKey])
+	}
+	return bucketHash.Sum(nil), nil
+}
+
+// computeStateHash computes the state hash from the hashes of the buckets stored in the db,
+// with the given hashes of the updated buckets taking precedence over the stored ones
+func (vdb *VersionedDB) computeStateHash(updatedBucketHashes map[uint32][]byte) []byte {
+	bucketHashes := make(map[uint32][]byte)
+	itr := vdb.db.GetIterator(bucketKeyPrefix, constructBucketKey(numBuckets))
+	for itr.Next() {
+		bucketNum, _ := util.DecodeOrderPreservingVarUint64(bytes.TrimPrefix(itr.Key(), bucketKeyPrefix))
+		bucketHash := make([]byte, len(itr.Value()))
+		copy(bucketHash, itr.Value())
+		bucketHashes[uint32(bucketNum)] = bucketHash
+	}
+	itr.Release()
+	for bucketNum, bucketHash := range updatedBucketHashes {
+		if bucketHash == nil {
+			delete(bucketHashes, bucketNum)
+		} else {
+			bucketHashes[bucketNum] = bucketHash
+		}
+	}
+	stateHash := sha256.New()
+	for bucketNum := uint32(0); bucketNum < numBuckets; bucketNum++ {
+		bucketHash, ok := bucketHashes[bucketNum]
+		if !ok {
+			continue
+		}
+		stateHash.Write(util.EncodeOrderPreservingVarUint64(uint64(bucketNum)))
+		stateHash.Write(bucketHash)
+	}
+	return stateHash.Sum(nil)
+}
+
+func computeBucketNum(ck statedb.CompositeKey) uint32 {
+	h := fnv.New32a()
+	h.Write(constructCompositeKey(ck))
+	return h.Sum32() % numBuckets
+}
+
+func computeEntryHash(ck statedb.CompositeKey, value []byte) []byte {
+	buffer := proto.NewBuffer([]byte{})
+	buffer.EncodeStringBytes(ck.Namespace)
+	buffer.EncodeStringBytes(ck.Key)
+	buffer.EncodeRawBytes(value)
+	entryHash := sha256.Sum256(buffer.Bytes())
+	return entryHash[:]
+}
+
+func constructCompositeKey(ck statedb.CompositeKey) []byte {
+	return append(append([]byte(ck.Namespace), compositeKeySep...), []byte(ck.Key)...)
+}
+
+func constructEntryKey(bucketNum uint32, compositeKey []byte) []byte {
+	entryKey := append([]byte{}, entryKeyPrefix...)
+	entryKey = append(entryKey, util.EncodeOrderPreservingVarUint64(uint64(bucketNum))...)
+	return append(entryKey, compositeKey...)
+}
+
+func constructBucketKey(bucketNum uint32) []byte {
+	return append(append([]byte{}, bucketKeyPrefix...), util.EncodeOrderPreservingVarUint64(uint64(bucketNum))...)
+}
+
+func constructStateHashKey(blockNum uint64) []byte {
+	return append(append([]byte{}, stateHashKeyPrefix...), util.EncodeOrderPreservingVarUint64(blockNum)...)
+}
@@ -0,0 +1,156 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statehash
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/testutil"
+	"github.com/spf13/viper"
+)
+
+func TestMain(m *testing.M) {
+	viper.Set("peer.fileSystemPath", "/tmp/fabric/ledgertests")
+	os.Exit(m.Run())
+}
+
+func TestStateHashDeletes(t *testing.T) {
+	db, cleanup := newTestVersionedDB(t)
+	defer cleanup()
+
+	batch := statedb.NewUpdateBatch()
+	for i := 0; i < 10; i++ {
+		batch.Put("ns", fmt.Sprintf("key%d", i), []byte(fmt.Sprintf("value%d", i)), version.NewHeight(1, uint64(i)))
+	}
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 10)), "")
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns", "key10", []byte("value10"), version.NewHeight(2, 1))
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 1)), "")
+
+	// deleting the key added by block 2 brings back the state hash as of block 1
+	batch = statedb.NewUpdateBatch()
+	batch.Delete("ns", "key10", version.NewHeight(3, 1))
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(3, 1)), "")
+	testutil.AssertEquals(t, getStateHash(t, db, 3), getStateHash(t, db, 1))
+	testutil.AssertNotEquals(t, getStateHash(t, db, 2), getStateHash(t, db, 1))
+
+	// deleting all the keys brings back the state hash of an empty state
+	batch = statedb.NewUpdateBatch()
+	for i := 0; i < 10; i++ {
+		batch.Delete("ns", fmt.Sprintf("key%d", i), version.NewHeight(4, uint64(i)))
+	}
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(4, 10)), "")
+	testutil.AssertEquals(t, getStateHash(t, db, 4), db.computeStateHash(nil))
+	itr := db.db.GetIterator(nil, nil)
+	defer itr.Release()
+	for itr.Next() {
+		testutil.AssertEquals(t, itr.Key()[0], stateHashKeyPrefix[0])
+	}
+}
+
+func TestStateHashReapply(t *testing.T) {
+	db, cleanup := newTestVersionedDB(t)
+	defer cleanup()
+
+	batch1 := statedb.NewUpdateBatch()
+	batch1.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	batch1.Put("ns2", "key2", []byte("value2"), version.NewHeight(1, 1))
+	testutil.AssertNoError(t, db.ApplyUpdates(batch1, version.NewHeight(1, 1)), "")
+	batch2 := statedb.NewUpdateBatch()
+	batch2.Put("ns1", "key1", []byte("value1-updated"), version.NewHeight(2, 1))
+	batch2.Delete("ns2", "key2", version.NewHeight(2, 1))
+	testutil.AssertNoError(t, db.ApplyUpdates(batch2, version.NewHeight(2, 1)), "")
+	stateHash := getStateHash(t, db, 2)
+
+	// applying the last batch again, as in a recovery, does not change the state hash
+	testutil.AssertNoError(t, db.ApplyUpdates(batch2, version.NewHeight(2, 1)), "")
+	testutil.AssertEquals(t, getStateHash(t, db, 2), stateHash)
+}
+
+func TestStateHashPrepareUpdates(t *testing.T) {
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns", "key1", []byte("value1"), version.NewHeight(1, 1))
+	batch.Put("ns", "key2", []byte("value2"), version.NewHeight(1, 2))
+	height := version.NewHeight(1, 2)
+
+	db, cleanup := newTestVersionedDB(t)
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, height), "")
+	stateHash := getStateHash(t, db, 1)
+	cleanup()
+
+	db, cleanup = newTestVersionedDB(t)
+	defer cleanup()
+	// the state hash is recorded by PrepareUpdates, the batch itself is not applied
+	testutil.AssertNoError(t, db.PrepareUpdates(batch, height), "")
+	testutil.AssertEquals(t, getStateHash(t, db, 1), stateHash)
+	vv, err := db.GetState("ns", "key1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, vv)
+
+	// the prepared batch is applied without updating the state hash again
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, height), "")
+	testutil.AssertNil(t, db.preparedBatch)
+	testutil.AssertEquals(t, getStateHash(t, db, 1), stateHash)
+	vv, err = db.GetState("ns", "key1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, vv.Value, []byte("value1"))
+}
+
+func TestStateHashNotRecordedForZeroHeight(t *testing.T) {
+	db, cleanup := newTestVersionedDB(t)
+	defer cleanup()
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns", "key1", []byte("value1"), version.NewHeight(5, 1))
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(0, 0)), "")
+	testutil.AssertNil(t, getStateHash(t, db, 0))
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns", "key2", []byte("value2"), version.NewHeight(5, 2))
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(5, 2)), "")
+	testutil.AssertNotNil(t, getStateHash(t, db, 5))
+}
+
+func TestBucketNum(t *testing.T) {
+	ck := statedb.CompositeKey{Namespace: "ns", Key: "key"}
+	bucketNum := computeBucketNum(ck)
+	testutil.AssertEquals(t, computeBucketNum(ck), bucketNum)
+	testutil.AssertEquals(t, bucketNum < numBuckets, true)
+}
+
+func newTestVersionedDB(t *testing.T) (*VersionedDB, func()) {
+	env := stateleveldb.NewTestVDBEnv(t)
+	os.RemoveAll(ledgerconfig.GetStateHashLevelDBPath())
+	provider := NewVersionedDBProvider(env.DBProvider)
+	db, err := provider.GetDBHandle("TestDB")
+	testutil.AssertNoError(t, err, "")
+	return db, func() {
+		provider.Close()
+		env.Cleanup()
+		os.RemoveAll(ledgerconfig.GetStateHashLevelDBPath())
+	}
+}
+
+func getStateHash(t *testing.T, db *VersionedDB, blockNum uint64) []byte {
+	stateHash, err := db.GetStateHash(blockNum)
+	testutil.AssertNoError(t, err, "")
+	return stateHash
+}
@@ -18,6 +18,7 @@ package stateleveldb
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
@@ -120,9 +121,19 @@ func (vdb *versionedDB) GetStateRangeScanIterator(namespace string, startKey str
 	return newKVScanner(namespace, dbItr), nil
 }
 
+// GetFullScanIterator implements method in VersionedDB interface
+func (vdb *versionedDB) GetFullScanIterator() (statedb.ResultsIterator, error) {
+	return &fullScanner{vdb.db.GetIterator(nil, nil)}, nil
+}
+
 // ExecuteQuery implements method in VersionedDB interface
-func (vdb *versionedDB) ExecuteQuery(query string) (statedb.ResultsIterator, error) {
-	panic("Method not supported for leveldb")
+func (vdb *versionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIterator, error) {
+	return nil, errors.New("ExecuteQuery not supported for leveldb")
+}
+
+// ExecuteQueryWithPagination implements method in VersionedDB interface
+func (vdb *versionedDB) ExecuteQueryWithPagination(namespace, query string, startKey string, limit int) (statedb.ResultsIterator, error) {
+	return nil, errors.New("ExecuteQueryWithPagination not supported for leveldb")
 }
 
 // ApplyUpdates implements method in VersionedDB interface
@@ -207,3 +218,26 @@ func (scanner *kvScanner) Next() (statedb.QueryResult, error) {
 func (scanner *kvScanner) Close() {
 	scanner.dbItr.Release()
 }
+
+// fullScanner iterates over the key-values of all the namespaces, skipping the savepoint
+type fullScanner struct {
+	dbItr iterator.Iterator
+}
+
+func (scanner *fullScanner) Next() (statedb.QueryResult, error) {
+	for scanner.dbItr.Next() {
+		if bytes.Equal(scanner.dbItr.Key(), savePointKey) {
+			continue
+		}
+		namespace, key := splitCompositeKey(scanner.dbItr.Key())
+		value, version := decodeValue(scanner.dbItr.Value())
+		return &statedb.VersionedKV{
+			CompositeKey:   statedb.CompositeKey{Namespace: namespace, Key: key},
+			VersionedValue: statedb.VersionedValue{Value: value, Version: version}}, nil
+	}
+	return nil, nil
+}
+
+func (scanner *fullScanner) Close() {
+	scanner.dbItr.Release()
+}
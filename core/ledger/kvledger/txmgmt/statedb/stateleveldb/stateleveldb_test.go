@@ -49,12 +49,24 @@ func TestDeletes(t *testing.T) {
 	commontests.TestDeletes(t, env.DBProvider)
 }
 
+func TestDeleteAndReAdd(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestDeleteAndReAdd(t, env.DBProvider)
+}
+
 func TestIterator(t *testing.T) {
 	env := NewTestVDBEnv(t)
 	defer env.Cleanup()
 	commontests.TestIterator(t, env.DBProvider)
 }
 
+func TestStateHash(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestStateHash(t, env.DBProvider)
+}
+
 func TestEncodeDecodeValueAndVersion(t *testing.T) {
 	testValueAndVersionEncodeing(t, []byte("value1"), version.NewHeight(1, 2))
 	testValueAndVersionEncodeing(t, []byte{}, version.NewHeight(50, 50))
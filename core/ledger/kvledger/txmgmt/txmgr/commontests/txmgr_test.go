@@ -377,6 +377,68 @@ func testTxValidationWithItr(t *testing.T, env testEnv) {
 
 }
 
+func TestTxPhantomValidation(t *testing.T) {
+	for _, testEnv := range testEnvs {
+		t.Logf("Running test for TestEnv = %s", testEnv.getName())
+		testEnv.init(t)
+		testTxPhantomValidation(t, testEnv)
+		testEnv.cleanup()
+	}
+}
+
+func testTxPhantomValidation(t *testing.T, env testEnv) {
+	cID := "cID"
+	txMgr := env.getTxMgr()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+
+	// simulate tx1
+	s1, _ := txMgr.NewTxSimulator()
+	for i := 1; i <= 5; i++ {
+		k := createTestKey(i)
+		v := createTestValue(i)
+		t.Logf("Adding k=[%s], v=[%s]", k, v)
+		s1.SetState(cID, k, v)
+	}
+	s1.Done()
+	// validate and commit RWset
+	txRWSet1, _ := s1.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet1)
+
+	// simulate tx2 that scans the complete range key_002 - key_004
+	s2, _ := txMgr.NewTxSimulator()
+	itr, _ := s2.GetStateRangeScanIterator(cID, createTestKey(2), createTestKey(5))
+	for kv, _ := itr.Next(); kv != nil; kv, _ = itr.Next() {
+	}
+	itr.Close()
+	s2.Done()
+
+	// simulate tx3 that scans the complete range key_004 - key_005
+	s3, _ := txMgr.NewTxSimulator()
+	itr, _ = s3.GetStateRangeScanIterator(cID, createTestKey(4), "")
+	for kv, _ := itr.Next(); kv != nil; kv, _ = itr.Next() {
+	}
+	itr.Close()
+	s3.Done()
+
+	// simulate tx4 that deletes key_003 and inserts key_006
+	s4, _ := txMgr.NewTxSimulator()
+	s4.DeleteState(cID, createTestKey(3))
+	s4.SetState(cID, createTestKey(6), createTestValue(6))
+	s4.Done()
+
+	// validate and commit RWset for tx4
+	txRWSet4, _ := s4.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet4)
+
+	// tx2 observed the deleted key_003 and should be invalid now
+	txRWSet2, _ := s2.GetTxSimulationResults()
+	txMgrHelper.checkRWsetInvalid(txRWSet2)
+
+	// tx3 did not observe the inserted key_006 and should be invalid now
+	txRWSet3, _ := s3.GetTxSimulationResults()
+	txMgrHelper.checkRWsetInvalid(txRWSet3)
+}
+
 func TestGetSetMultipeKeys(t *testing.T) {
 	for _, testEnv := range testEnvs {
 		t.Logf("Running test for TestEnv = %s", testEnv.getName())
@@ -494,7 +556,7 @@ func testExecuteQuery(t *testing.T, env testEnv) {
 	queryExecuter, _ := txMgr.NewQueryExecutor()
 	queryString := "{\"selector\":{\"owner\": {\"$eq\": \"bob\"}},\"limit\": 10,\"skip\": 0}"
 
-	itr, _ := queryExecuter.ExecuteQuery(queryString)
+	itr, _ := queryExecuter.ExecuteQuery("ns1", queryString)
 
 	counter := 0
 	for {
@@ -65,11 +65,24 @@ func (h *queryHelper) getStateRangeScanIterator(namespace string, startKey strin
 	if err != nil {
 		return nil, err
 	}
-	return &resultsItr{DBItr: dbItr, RWSet: h.rwset}, nil
+	itr := &resultsItr{DBItr: dbItr}
+	if h.rwset != nil {
+		itr.RangeQueryInfo = rwset.NewRangeQueryInfo(startKey, endKey)
+		h.rwset.AddToRangeQuerySet(namespace, itr.RangeQueryInfo)
+	}
+	return itr, nil
+}
+
+func (h *queryHelper) executeQuery(namespace, query string) (ledger.ResultsIterator, error) {
+	dbItr, err := h.txmgr.db.ExecuteQuery(namespace, query)
+	if err != nil {
+		return nil, err
+	}
+	return &queryResultsItr{DBItr: dbItr, RWSet: h.rwset}, nil
 }
 
-func (h *queryHelper) executeQuery(query string) (ledger.ResultsIterator, error) {
-	dbItr, err := h.txmgr.db.ExecuteQuery(query)
+func (h *queryHelper) executeQueryWithPagination(namespace, query string, startKey string, limit int) (ledger.ResultsIterator, error) {
+	dbItr, err := h.txmgr.db.ExecuteQueryWithPagination(namespace, query, startKey, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -88,8 +101,8 @@ func (h *queryHelper) checkDone() {
 }
 
 type resultsItr struct {
-	DBItr statedb.ResultsIterator
-	RWSet *rwset.RWSet
+	DBItr          statedb.ResultsIterator
+	RangeQueryInfo *rwset.RangeQueryInfo
 }
 
 // Next implements method in interface ledger.ResultsIterator
@@ -100,11 +113,14 @@ func (itr *resultsItr) Next() (ledger.QueryResult, error) {
 		return nil, err
 	}
 	if queryResult == nil {
+		if itr.RangeQueryInfo != nil {
+			itr.RangeQueryInfo.ItrExhausted = true
+		}
 		return nil, nil
 	}
 	versionedKV := queryResult.(*statedb.VersionedKV)
-	if itr.RWSet != nil {
-		itr.RWSet.AddToReadSet(versionedKV.Namespace, versionedKV.Key, versionedKV.Version)
+	if itr.RangeQueryInfo != nil {
+		itr.RangeQueryInfo.AddResult(versionedKV.Key, versionedKV.Version)
 	}
 	return &ledger.KV{Key: versionedKV.Key, Value: versionedKV.Value}, nil
 }
@@ -53,8 +53,13 @@ func (q *lockBasedQueryExecutor) GetStateRangeScanIterator(namespace string, sta
 }
 
 // ExecuteQuery implements method in interface `ledger.QueryExecutor`
-func (q *lockBasedQueryExecutor) ExecuteQuery(query string) (ledger.ResultsIterator, error) {
-	return q.helper.executeQuery(query)
+func (q *lockBasedQueryExecutor) ExecuteQuery(namespace, query string) (ledger.ResultsIterator, error) {
+	return q.helper.executeQuery(namespace, query)
+}
+
+// ExecuteQueryWithPagination implements method in interface `ledger.QueryExecutor`
+func (q *lockBasedQueryExecutor) ExecuteQueryWithPagination(namespace, query string, startKey string, limit int) (ledger.ResultsIterator, error) {
+	return q.helper.executeQueryWithPagination(namespace, query, startKey, limit)
 }
 
 // Done implements method in interface `ledger.QueryExecutor`
@@ -56,6 +56,35 @@ func (txmgr *LockBasedTxMgr) GetBlockNumFromSavepoint() (uint64, error) {
 	return height.BlockNum, nil
 }
 
+// ExportState implements method in interface `txmgmt.TxMgr`
+// All the key-values of the state are passed to the given function while the commits are held off,
+// so that the exported state is consistent with the returned savepoint
+func (txmgr *LockBasedTxMgr) ExportState(exportKV func(kv *statedb.VersionedKV) error) (*version.Height, error) {
+	txmgr.commitRWLock.RLock()
+	defer txmgr.commitRWLock.RUnlock()
+	savepoint, err := txmgr.db.GetLatestSavePoint()
+	if err != nil {
+		return nil, err
+	}
+	itr, err := txmgr.db.GetFullScanIterator()
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Close()
+	for {
+		queryResult, err := itr.Next()
+		if err != nil {
+			return nil, err
+		}
+		if queryResult == nil {
+			return savepoint, nil
+		}
+		if err = exportKV(queryResult.(*statedb.VersionedKV)); err != nil {
+			return nil, err
+		}
+	}
+}
+
 // NewQueryExecutor implements method in interface `txmgmt.TxMgr`
 func (txmgr *LockBasedTxMgr) NewQueryExecutor() (ledger.QueryExecutor, error) {
 	qe := newQueryExecutor(txmgr)
@@ -88,6 +117,21 @@ func (txmgr *LockBasedTxMgr) Shutdown() {
 	txmgr.db.Close()
 }
 
+// PreCommit implements method in interface `txmgmt.TxMgr`
+// The part of the commit that does not move the save point of the state database is performed, if the state
+// database supports it. PreCommit can hence be invoked while the block is being appended to the block storage
+func (txmgr *LockBasedTxMgr) PreCommit() error {
+	if txmgr.batch == nil {
+		panic("validateAndPrepare() method should have been called before calling preCommit()")
+	}
+	preparer, ok := txmgr.db.(statedb.UpdatesPreparer)
+	if !ok {
+		return nil
+	}
+	logger.Debugf("Preparing updates for the state database")
+	return preparer.PrepareUpdates(txmgr.batch, txmgr.commitHeight())
+}
+
 // Commit implements method in interface `txmgmt.TxMgr`
 func (txmgr *LockBasedTxMgr) Commit() error {
 	logger.Debugf("Committing updates to state database")
@@ -98,14 +142,17 @@ func (txmgr *LockBasedTxMgr) Commit() error {
 		panic("validateAndPrepare() method should have been called before calling commit()")
 	}
 	defer func() { txmgr.batch = nil }()
-	if err := txmgr.db.ApplyUpdates(txmgr.batch,
-		version.NewHeight(txmgr.currentBlock.Header.Number, uint64(len(txmgr.currentBlock.Data.Data)))); err != nil {
+	if err := txmgr.db.ApplyUpdates(txmgr.batch, txmgr.commitHeight()); err != nil {
 		return err
 	}
 	logger.Debugf("Updates committed to state database")
 	return nil
 }
 
+func (txmgr *LockBasedTxMgr) commitHeight() *version.Height {
+	return version.NewHeight(txmgr.currentBlock.Header.Number, uint64(len(txmgr.currentBlock.Data.Data)))
+}
+
 // Rollback implements method in interface `txmgmt.TxMgr`
 func (txmgr *LockBasedTxMgr) Rollback() {
 	txmgr.batch = nil
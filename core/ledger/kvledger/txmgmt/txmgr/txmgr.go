@@ -18,6 +18,8 @@ package txmgr
 
 import (
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/protos/common"
 )
 
@@ -27,6 +29,8 @@ type TxMgr interface {
 	NewTxSimulator() (ledger.TxSimulator, error)
 	ValidateAndPrepare(block *common.Block, doMVCCValidation bool) error
 	GetBlockNumFromSavepoint() (uint64, error)
+	ExportState(exportKV func(kv *statedb.VersionedKV) error) (*version.Height, error)
+	PreCommit() error
 	Commit() error
 	Rollback()
 	Shutdown()
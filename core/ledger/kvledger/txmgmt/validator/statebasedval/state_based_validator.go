@@ -17,11 +17,15 @@ limitations under the License.
 package statebasedval
 
 import (
+	"sync"
+
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwset"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
 	putils "github.com/hyperledger/fabric/protos/utils"
 	logging "github.com/op/go-logging"
 )
@@ -29,18 +33,34 @@ import (
 var logger = logging.MustGetLogger("statevalidator")
 
 // Validator validates a tx against the latest committed state
-// and preceding valid transactions with in the same block
+// and preceding valid transactions with in the same block.
+// The transactions whose reads do not intersect the writes of the preceding transactions
+// in the block are validated concurrently, by up to `parallelism` goroutines
 type Validator struct {
-	db statedb.VersionedDB
+	db          statedb.VersionedDB
+	parallelism int
 }
 
 // NewValidator constructs StateValidator
 func NewValidator(db statedb.VersionedDB) *Validator {
-	return &Validator{db}
+	return &Validator{db, ledgerconfig.GetValidationParallelism()}
+}
+
+// blockTx holds a transaction of the block being validated
+type blockTx struct {
+	index   int
+	env     *common.Envelope
+	txType  common.HeaderType
+	txRWSet *rwset.TxReadWriteSet
+	// independent is set for an endorser transaction whose reads do not intersect the writes of any of the
+	// preceding transactions in the block. Such a transaction is validated against the committed state only,
+	// concurrently with the others, and the outcome is recorded in codeInCommittedState
+	independent          bool
+	codeInCommittedState pb.TxValidationCode
 }
 
-//validate endorser transaction
-func (v *Validator) validateEndorserTX(envBytes []byte, doMVCCValidation bool, updates *statedb.UpdateBatch) (*rwset.TxReadWriteSet, error) {
+//get the read-write set of an endorser transaction
+func getTxRWSet(envBytes []byte) (*rwset.TxReadWriteSet, error) {
 	// extract actions from the envelope message
 	respPayload, err := putils.GetActionFromEnvelope(envBytes)
 	if err != nil {
@@ -65,17 +85,7 @@ func (v *Validator) validateEndorserTX(envBytes []byte, doMVCCValidation bool, u
 			logger.Debugf("validating txRWSet:[%s...]", txRWSetString[0:1000])
 		}
 	}
-
-	//mvccvalidation, may invalidate transaction
-	if doMVCCValidation {
-		if valid, err := v.validateTx(txRWSet, updates); err != nil {
-			return nil, err
-		} else if !valid {
-			txRWSet = nil
-		}
-	}
-
-	return txRWSet, err
+	return txRWSet, nil
 }
 
 // TODO validate configuration transaction
@@ -88,53 +98,165 @@ func (v *Validator) ValidateAndPrepareBatch(block *common.Block, doMVCCValidatio
 	logger.Debugf("New block arrived for validation:%#v, doMVCCValidation=%t", block, doMVCCValidation)
 	updates := statedb.NewUpdateBatch()
 	logger.Debugf("Validating a block with [%d] transactions", len(block.Data.Data))
-	txsFilter := util.NewFilterBitArrayFromBytes(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
-	for txIndex, envBytes := range block.Data.Data {
-		if txsFilter.IsSet(uint(txIndex)) {
+	txsFilter := util.GetTxValidationFlags(block.Metadata)
+	if len(txsFilter) < len(block.Data.Data) {
+		// record a validation code for each transaction, the ones without a code being valid so far
+		flags := util.NewTxValidationFlags(len(block.Data.Data))
+		copy(flags, txsFilter)
+		txsFilter = flags
+	}
+	txs, err := v.unmarshalTxs(block, txsFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	if doMVCCValidation {
+		if err = v.validateIndependentTxs(txs); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, tx := range txs {
+		if tx == nil {
+			continue
+		}
+
+		code := pb.TxValidationCode_VALID
+		if tx.txType == common.HeaderType_ENDORSER_TRANSACTION {
+			//mvccvalidation, may invalidate transaction
+			if doMVCCValidation {
+				if tx.independent {
+					code = tx.codeInCommittedState
+				} else if code, err = v.validateTx(tx.txRWSet, updates); err != nil {
+					return nil, err
+				}
+			}
+			if code == pb.TxValidationCode_VALID {
+				committingTxHeight := version.NewHeight(block.Header.Number, uint64(tx.index+1))
+				addWriteSetToBatch(tx.txRWSet, committingTxHeight, updates)
+			}
+		} else if tx.txType == common.HeaderType_CONFIGURATION_TRANSACTION {
+			valid, err := v.validateConfigTX(tx.env)
+			if err != nil {
+				return nil, err
+			}
+			if !valid {
+				code = pb.TxValidationCode_INVALID_CONFIG_TRANSACTION
+			}
+		} else {
+			logger.Errorf("Skipping transaction %d that's not an endorsement or configuration %d", tx.index, tx.txType)
+			code = pb.TxValidationCode_UNKNOWN_TX_TYPE
+		}
+
+		if code != pb.TxValidationCode_VALID {
+			// Record the validation code of the invalid transaction
+			txsFilter.SetFlag(tx.index, code)
+		}
+	}
+	util.SetTxValidationFlags(block, txsFilter)
+	return updates, nil
+}
+
+// unmarshalTxs unmarshals the transactions of the block concurrently. The transactions that are marked
+// as invalid in the filter are left nil
+func (v *Validator) unmarshalTxs(block *common.Block, txsFilter util.TxValidationFlags) ([]*blockTx, error) {
+	txs := make([]*blockTx, len(block.Data.Data))
+	err := v.runConcurrently(len(txs), func(txIndex int) error {
+		if txsFilter.IsInvalid(txIndex) {
 			// Skiping invalid transaction
 			logger.Debug("Skipping transaction marked as invalid, txIndex=", txIndex)
-			continue
+			return nil
 		}
 
+		envBytes := block.Data.Data[txIndex]
 		env, err := putils.GetEnvelopeFromBlock(envBytes)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		payload, err := putils.GetPayload(env)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		valid := false
-		if common.HeaderType(payload.Header.ChainHeader.Type) == common.HeaderType_ENDORSER_TRANSACTION {
-			txRWSet, err := v.validateEndorserTX(envBytes, doMVCCValidation, updates)
-			if err != nil {
-				return nil, err
+		tx := &blockTx{index: txIndex, env: env, txType: common.HeaderType(payload.Header.ChainHeader.Type)}
+		if tx.txType == common.HeaderType_ENDORSER_TRANSACTION {
+			if tx.txRWSet, err = getTxRWSet(envBytes); err != nil {
+				return err
 			}
-			//txRWSet != nil => t is valid
-			if txRWSet != nil {
-				committingTxHeight := version.NewHeight(block.Header.Number, uint64(txIndex+1))
-				addWriteSetToBatch(txRWSet, committingTxHeight, updates)
-				valid = true
-			}
-		} else if common.HeaderType(payload.Header.ChainHeader.Type) == common.HeaderType_CONFIGURATION_TRANSACTION {
-			valid, err = v.validateConfigTX(env)
-			if err != nil {
-				return nil, err
+		}
+		txs[txIndex] = tx
+		return nil
+	})
+	return txs, err
+}
+
+// validateIndependentTxs finds the endorser transactions whose reads do not intersect the writes of any of the
+// preceding transactions in the block, and validates them against the committed state concurrently.
+// Whether or not the preceding transactions turn out to be valid, the reads of such a transaction are not
+// affected by the updates of the block, so its validity depends on the committed state only
+func (v *Validator) validateIndependentTxs(txs []*blockTx) error {
+	precedingWrites := statedb.NewUpdateBatch()
+	var independentTxs []*blockTx
+	for _, tx := range txs {
+		if tx == nil || tx.txType != common.HeaderType_ENDORSER_TRANSACTION {
+			continue
+		}
+		if conflictsWithUpdates(tx.txRWSet, precedingWrites) == pb.TxValidationCode_VALID {
+			tx.independent = true
+			independentTxs = append(independentTxs, tx)
+		}
+		addWriteSetToBatch(tx.txRWSet, nil, precedingWrites)
+	}
+	logger.Debugf("Validating [%d] independent transactions concurrently", len(independentTxs))
+
+	return v.runConcurrently(len(independentTxs), func(i int) error {
+		tx := independentTxs[i]
+		code, err := v.validateTxInCommittedState(tx.txRWSet)
+		tx.codeInCommittedState = code
+		return err
+	})
+}
+
+// runConcurrently invokes fn for the indexes 0 to n-1 with up to `parallelism` goroutines, and returns
+// the error returned for the lowest index, if any. With a parallelism of 1, fn is invoked in order in the calling goroutine
+func (v *Validator) runConcurrently(n int, fn func(i int) error) error {
+	errs := make([]error, n)
+	if v.parallelism <= 1 || n <= 1 {
+		for i := 0; i < n; i++ {
+			if errs[i] = fn(i); errs[i] != nil {
+				return errs[i]
 			}
-		} else {
-			logger.Errorf("Skipping transaction %d that's not an endorsement or configuration %d", txIndex, payload.Header.ChainHeader.Type)
-			valid = false
 		}
+		return nil
+	}
 
-		if !valid {
-			// Unset bit in byte array corresponded to the invalid transaction
-			txsFilter.Set(uint(txIndex))
+	indexes := make(chan int, n)
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	numWorkers := v.parallelism
+	if numWorkers > n {
+		numWorkers = n
+	}
+	var wg sync.WaitGroup
+	wg.Add(numWorkers)
+	for w := 0; w < numWorkers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				errs[i] = fn(i)
+			}
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
 		}
 	}
-	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsFilter.ToBytes()
-	return updates, nil
+	return nil
 }
 
 func addWriteSetToBatch(txRWSet *rwset.TxReadWriteSet, txHeight *version.Height, batch *statedb.UpdateBatch) {
@@ -150,16 +272,44 @@ func addWriteSetToBatch(txRWSet *rwset.TxReadWriteSet, txHeight *version.Height,
 	}
 }
 
-func (v *Validator) validateTx(txRWSet *rwset.TxReadWriteSet, updates *statedb.UpdateBatch) (bool, error) {
+func (v *Validator) validateTx(txRWSet *rwset.TxReadWriteSet, updates *statedb.UpdateBatch) (pb.TxValidationCode, error) {
+	if code := conflictsWithUpdates(txRWSet, updates); code != pb.TxValidationCode_VALID {
+		return code, nil
+	}
+	return v.validateTxInCommittedState(txRWSet)
+}
+
+// conflictsWithUpdates returns MVCC_READ_CONFLICT if a key read by the transaction is updated in the given batch,
+// PHANTOM_READ_CONFLICT if a key in the part of the range of a range query that the transaction observed is, and VALID otherwise
+func conflictsWithUpdates(txRWSet *rwset.TxReadWriteSet, updates *statedb.UpdateBatch) pb.TxValidationCode {
 	for _, nsRWSet := range txRWSet.NsRWs {
 		ns := nsRWSet.NameSpace
 		for _, kvRead := range nsRWSet.Reads {
 			if updates.Exists(ns, kvRead.Key) {
-				return false, nil
+				return pb.TxValidationCode_MVCC_READ_CONFLICT
+			}
+		}
+		for _, rqi := range nsRWSet.RangeQueriesInfo {
+			for compositeKey := range updates.KVs {
+				if compositeKey.Namespace == ns && inObservedRange(rqi, compositeKey.Key) {
+					logger.Debugf("Key [%s:%s] in range query [%s] is updated by a preceding transaction in the block",
+						ns, compositeKey.Key, rqi)
+					return pb.TxValidationCode_PHANTOM_READ_CONFLICT
+				}
 			}
+		}
+	}
+	return pb.TxValidationCode_VALID
+}
+
+// validateTxInCommittedState validates the reads and the range queries of the transaction against the committed state
+func (v *Validator) validateTxInCommittedState(txRWSet *rwset.TxReadWriteSet) (pb.TxValidationCode, error) {
+	for _, nsRWSet := range txRWSet.NsRWs {
+		ns := nsRWSet.NameSpace
+		for _, kvRead := range nsRWSet.Reads {
 			versionedValue, err := v.db.GetState(ns, kvRead.Key)
 			if err != nil {
-				return false, nil
+				return pb.TxValidationCode_MVCC_READ_CONFLICT, nil
 			}
 			var committedVersion *version.Height
 			if versionedValue != nil {
@@ -168,9 +318,69 @@ func (v *Validator) validateTx(txRWSet *rwset.TxReadWriteSet, updates *statedb.U
 			if !version.AreSame(committedVersion, kvRead.Version) {
 				logger.Debugf("Version mismatch for key [%s:%s]. Committed version = [%s], Version in readSet [%s]",
 					ns, kvRead.Key, committedVersion, kvRead.Version)
-				return false, nil
+				return pb.TxValidationCode_MVCC_READ_CONFLICT, nil
+			}
+		}
+		for _, rqi := range nsRWSet.RangeQueriesInfo {
+			if valid, err := v.validateRangeQuery(ns, rqi); err != nil || !valid {
+				return pb.TxValidationCode_PHANTOM_READ_CONFLICT, err
 			}
 		}
 	}
+	return pb.TxValidationCode_VALID, nil
+}
+
+// inObservedRange returns true if the key is in the part of the range that the transaction observed
+// during simulation. If the iterator was not exhausted, the transaction observed the range only up to the last result
+func inObservedRange(rqi *rwset.RangeQueryInfo, key string) bool {
+	if key < rqi.StartKey {
+		return false
+	}
+	if !rqi.ItrExhausted {
+		return len(rqi.Results) > 0 && key <= rqi.Results[len(rqi.Results)-1].Key
+	}
+	return rqi.EndKey == "" || key < rqi.EndKey
+}
+
+// validateRangeQuery re-executes a range query of a transaction against the committed state. The transaction
+// is invalid if a key has been inserted into, deleted from or updated within the part of the range that the
+// transaction observed during simulation (i.e., a phantom read). The updates of the preceding valid transactions
+// in the block are checked by conflictsWithUpdates
+func (v *Validator) validateRangeQuery(ns string, rqi *rwset.RangeQueryInfo) (bool, error) {
+	itr, err := v.db.GetStateRangeScanIterator(ns, rqi.StartKey, rqi.EndKey)
+	if err != nil {
+		return false, err
+	}
+	defer itr.Close()
+	numResults := 0
+	for {
+		queryResult, err := itr.Next()
+		if err != nil {
+			return false, err
+		}
+		if queryResult == nil {
+			break
+		}
+		versionedKV := queryResult.(*statedb.VersionedKV)
+		if !inObservedRange(rqi, versionedKV.Key) {
+			break
+		}
+		if numResults >= len(rqi.Results) {
+			logger.Debugf("Phantom key [%s:%s] found in range query [%s]", ns, versionedKV.Key, rqi)
+			return false, nil
+		}
+		kvRead := rqi.Results[numResults]
+		if kvRead.Key != versionedKV.Key || !version.AreSame(kvRead.Version, versionedKV.Version) {
+			logger.Debugf("Range query [%s] mismatch. Committed key [%s:%s] with version [%s], observed key [%s] with version [%s]",
+				rqi, ns, versionedKV.Key, versionedKV.Version, kvRead.Key, kvRead.Version)
+			return false, nil
+		}
+		numResults++
+	}
+	if numResults != len(rqi.Results) {
+		logger.Debugf("Range query [%s] mismatch. Committed state has [%d] results, [%d] were observed",
+			rqi, numResults, len(rqi.Results))
+		return false, nil
+	}
 	return true, nil
 }
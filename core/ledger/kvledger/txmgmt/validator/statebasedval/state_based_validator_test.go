@@ -17,6 +17,7 @@ limitations under the License.
 package statebasedval
 
 import (
+	"fmt"
 	"os"
 	"testing"
 
@@ -27,6 +28,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/testutil"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/spf13/viper"
 )
 
@@ -57,17 +59,17 @@ func TestValidator(t *testing.T) {
 	rwset1 := rwset.NewRWSet()
 	rwset1.AddToReadSet("ns1", "key1", version.NewHeight(1, 1))
 	rwset1.AddToReadSet("ns2", "key2", nil)
-	checkValidation(t, validator, []*rwset.RWSet{rwset1}, []int{})
+	checkValidation(t, validator, []*rwset.RWSet{rwset1}, []int{}, pb.TxValidationCode_MVCC_READ_CONFLICT)
 
 	//rwset2 should not be valid
 	rwset2 := rwset.NewRWSet()
 	rwset2.AddToReadSet("ns1", "key1", version.NewHeight(1, 2))
-	checkValidation(t, validator, []*rwset.RWSet{rwset2}, []int{0})
+	checkValidation(t, validator, []*rwset.RWSet{rwset2}, []int{0}, pb.TxValidationCode_MVCC_READ_CONFLICT)
 
 	//rwset3 should not be valid
 	rwset3 := rwset.NewRWSet()
 	rwset3.AddToReadSet("ns1", "key1", nil)
-	checkValidation(t, validator, []*rwset.RWSet{rwset3}, []int{0})
+	checkValidation(t, validator, []*rwset.RWSet{rwset3}, []int{0}, pb.TxValidationCode_MVCC_READ_CONFLICT)
 
 	// rwset4 and rwset5 within same block - rwset4 should be valid and makes rwset5 as invalid
 	rwset4 := rwset.NewRWSet()
@@ -75,27 +77,144 @@ func TestValidator(t *testing.T) {
 	rwset4.AddToWriteSet("ns1", "key1", []byte("value1_new"))
 	rwset5 := rwset.NewRWSet()
 	rwset5.AddToReadSet("ns1", "key1", version.NewHeight(1, 1))
-	checkValidation(t, validator, []*rwset.RWSet{rwset4, rwset5}, []int{1})
+	checkValidation(t, validator, []*rwset.RWSet{rwset4, rwset5}, []int{1}, pb.TxValidationCode_MVCC_READ_CONFLICT)
 }
 
-func checkValidation(t *testing.T, validator *Validator, rwsets []*rwset.RWSet, invalidTxIndexes []int) {
+func TestPhantomValidation(t *testing.T) {
+	testDBEnv := stateleveldb.NewTestVDBEnv(t)
+	defer testDBEnv.Cleanup()
+
+	db, err := testDBEnv.DBProvider.GetDBHandle("TestDB")
+	testutil.AssertNoError(t, err, "")
+
+	//populate db with initial data
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	batch.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 2))
+	batch.Put("ns1", "key4", []byte("value4"), version.NewHeight(1, 4))
+	batch.Put("ns1", "key5", []byte("value5"), version.NewHeight(1, 5))
+	db.ApplyUpdates(batch, version.NewHeight(1, 5))
+
+	validator := NewValidator(db)
+
+	//rwset1 observed the complete range and should be valid
+	rwset1 := rwset.NewRWSet()
+	rwset1.AddToRangeQuerySet("ns1", newRangeQueryInfo("key1", "key5", true,
+		"key1", version.NewHeight(1, 1), "key2", version.NewHeight(1, 2), "key4", version.NewHeight(1, 4)))
+	checkValidation(t, validator, []*rwset.RWSet{rwset1}, []int{}, pb.TxValidationCode_PHANTOM_READ_CONFLICT)
+
+	//rwset2 did not observe key4 and should not be valid
+	rwset2 := rwset.NewRWSet()
+	rwset2.AddToRangeQuerySet("ns1", newRangeQueryInfo("key1", "key5", true,
+		"key1", version.NewHeight(1, 1), "key2", version.NewHeight(1, 2)))
+	checkValidation(t, validator, []*rwset.RWSet{rwset2}, []int{0}, pb.TxValidationCode_PHANTOM_READ_CONFLICT)
+
+	//rwset3 observed key3 which does not exist anymore and should not be valid
+	rwset3 := rwset.NewRWSet()
+	rwset3.AddToRangeQuerySet("ns1", newRangeQueryInfo("key1", "key5", true,
+		"key1", version.NewHeight(1, 1), "key2", version.NewHeight(1, 2), "key3", version.NewHeight(1, 3),
+		"key4", version.NewHeight(1, 4)))
+	checkValidation(t, validator, []*rwset.RWSet{rwset3}, []int{0}, pb.TxValidationCode_PHANTOM_READ_CONFLICT)
+
+	//rwset4 stopped iterating after key2, so key4 is not a phantom and it should be valid
+	rwset4 := rwset.NewRWSet()
+	rwset4.AddToRangeQuerySet("ns1", newRangeQueryInfo("key1", "key5", false,
+		"key1", version.NewHeight(1, 1), "key2", version.NewHeight(1, 2)))
+	checkValidation(t, validator, []*rwset.RWSet{rwset4}, []int{}, pb.TxValidationCode_PHANTOM_READ_CONFLICT)
+
+	//rwset5 inserts key3 which makes rwset6 (within the same block) invalid
+	rwset5 := rwset.NewRWSet()
+	rwset5.AddToWriteSet("ns1", "key3", []byte("value3"))
+	rwset6 := rwset.NewRWSet()
+	rwset6.AddToRangeQuerySet("ns1", newRangeQueryInfo("key1", "key5", true,
+		"key1", version.NewHeight(1, 1), "key2", version.NewHeight(1, 2), "key4", version.NewHeight(1, 4)))
+	checkValidation(t, validator, []*rwset.RWSet{rwset5, rwset6}, []int{1}, pb.TxValidationCode_PHANTOM_READ_CONFLICT)
+}
+
+func TestParallelValidation(t *testing.T) {
+	testDBEnv := stateleveldb.NewTestVDBEnv(t)
+	defer testDBEnv.Cleanup()
+
+	db, err := testDBEnv.DBProvider.GetDBHandle("TestDB")
+	testutil.AssertNoError(t, err, "")
+
+	//populate db with initial data
+	batch := statedb.NewUpdateBatch()
+	for i := 1; i <= 5; i++ {
+		batch.Put("ns1", fmt.Sprintf("key%d", i), []byte(fmt.Sprintf("value%d", i)), version.NewHeight(1, uint64(i)))
+	}
+	db.ApplyUpdates(batch, version.NewHeight(1, 5))
+
+	//rwset0 is stale and invalid, so its write does not invalidate rwset1
+	rwset0 := rwset.NewRWSet()
+	rwset0.AddToReadSet("ns1", "key1", version.NewHeight(1, 2))
+	rwset0.AddToWriteSet("ns1", "key1", []byte("value1_new"))
+	rwset1 := rwset.NewRWSet()
+	rwset1.AddToReadSet("ns1", "key1", version.NewHeight(1, 1))
+	rwset1.AddToWriteSet("ns1", "key2", []byte("value2_new"))
+	//rwset2 reads key2 written by rwset1 and is invalid
+	rwset2 := rwset.NewRWSet()
+	rwset2.AddToReadSet("ns1", "key2", version.NewHeight(1, 2))
+	//rwset3 is independent and valid
+	rwset3 := rwset.NewRWSet()
+	rwset3.AddToReadSet("ns1", "key3", version.NewHeight(1, 3))
+	rwset3.AddToWriteSet("ns1", "key6", []byte("value6"))
+	//rwset4 is independent and invalid
+	rwset4 := rwset.NewRWSet()
+	rwset4.AddToReadSet("ns1", "key4", nil)
+	//rwset5 observed the range in which rwset3 inserted key6 and is invalid
+	rwset5 := rwset.NewRWSet()
+	rwset5.AddToRangeQuerySet("ns1", newRangeQueryInfo("key5", "", true, "key5", version.NewHeight(1, 5)))
+	rwsets := []*rwset.RWSet{rwset0, rwset1, rwset2, rwset3, rwset4, rwset5}
+	expectedCodes := []pb.TxValidationCode{pb.TxValidationCode_MVCC_READ_CONFLICT, pb.TxValidationCode_VALID,
+		pb.TxValidationCode_MVCC_READ_CONFLICT, pb.TxValidationCode_VALID, pb.TxValidationCode_MVCC_READ_CONFLICT,
+		pb.TxValidationCode_PHANTOM_READ_CONFLICT}
+
+	for _, parallelism := range []int{1, 4} {
+		validator := &Validator{db, parallelism}
+		block := constructBlock(t, rwsets)
+		_, err := validator.ValidateAndPrepareBatch(block, true)
+		testutil.AssertNoError(t, err, "")
+		txsFltr := util.GetTxValidationFlags(block.Metadata)
+		codes := []pb.TxValidationCode{}
+		for i := 0; i < len(block.Data.Data); i++ {
+			codes = append(codes, txsFltr.Flag(i))
+		}
+		testutil.AssertEquals(t, codes, expectedCodes)
+	}
+}
+
+func newRangeQueryInfo(startKey string, endKey string, itrExhausted bool, keysAndVersions ...interface{}) *rwset.RangeQueryInfo {
+	rqi := rwset.NewRangeQueryInfo(startKey, endKey)
+	rqi.ItrExhausted = itrExhausted
+	for i := 0; i < len(keysAndVersions); i += 2 {
+		rqi.AddResult(keysAndVersions[i].(string), keysAndVersions[i+1].(*version.Height))
+	}
+	return rqi
+}
+
+func constructBlock(t *testing.T, rwsets []*rwset.RWSet) *common.Block {
 	simulationResults := [][]byte{}
 	for _, rwset := range rwsets {
 		sr, err := rwset.GetTxReadWriteSet().Marshal()
 		testutil.AssertNoError(t, err, "")
 		simulationResults = append(simulationResults, sr)
 	}
-	block := testutil.ConstructBlock(t, simulationResults, false)
+	return testutil.ConstructBlock(t, simulationResults, false)
+}
+
+func checkValidation(t *testing.T, validator *Validator, rwsets []*rwset.RWSet, invalidTxIndexes []int, invalidCode pb.TxValidationCode) {
+	block := constructBlock(t, rwsets)
 	_, err := validator.ValidateAndPrepareBatch(block, true)
-	txsFltr := util.NewFilterBitArrayFromBytes(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
-	invalidTxNum := 0
+	txsFltr := util.GetTxValidationFlags(block.Metadata)
+	foundInvalidTxIndexes := []int{}
 	for i := 0; i < len(block.Data.Data); i++ {
-		if txsFltr.IsSet(uint(i)) {
-			invalidTxNum++
+		if txsFltr.IsInvalid(i) {
+			foundInvalidTxIndexes = append(foundInvalidTxIndexes, i)
+			testutil.AssertEquals(t, txsFltr.Flag(i), invalidCode)
 		}
 	}
 
 	testutil.AssertNoError(t, err, "")
-	testutil.AssertEquals(t, invalidTxNum, len(invalidTxIndexes))
-	//TODO Add the check for exact txnum that is marked invlid when bitarray is in place
+	testutil.AssertEquals(t, foundInvalidTxIndexes, invalidTxIndexes)
 }
@@ -17,6 +17,8 @@ limitations under the License.
 package ledger
 
 import (
+	"time"
+
 	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
 )
@@ -47,6 +49,10 @@ type OrdererLedger interface {
 type PeerLedgerProvider interface {
 	// Create creates a new ledger with a given unique id
 	Create(ledgerID string) (PeerLedger, error)
+	// CreateFromSnapshot creates a new ledger from a snapshot exported by `PeerLedger.ExportSnapshot`.
+	// The given id should match the id of the ledger from which the snapshot was exported. The new
+	// ledger expects the block following the last block of the snapshot as its next block
+	CreateFromSnapshot(ledgerID string, snapshotDir string) (PeerLedger, error)
 	// Open opens an already created ledger
 	Open(ledgerID string) (PeerLedger, error)
 	// Exists tells whether the ledger with given id exits
@@ -75,10 +81,13 @@ type OrdererLedgerProvider interface {
 // that tells apart valid transactions from invalid ones
 type PeerLedger interface {
 	Ledger
-	// GetTransactionByID retrieves a transaction by id
-	GetTransactionByID(txID string) (*pb.Transaction, error)
+	// GetTransactionByID retrieves a transaction by id, along with its validation code
+	GetTransactionByID(txID string) (*pb.ProcessedTransaction, error)
 	// GetBlockByHash returns a block given it's hash
 	GetBlockByHash(blockHash []byte) (*common.Block, error)
+	// GetConfigBlock returns the latest committed config block whose transaction is valid. The block remains
+	// available once it is pruned, and is carried over to a ledger created from a snapshot
+	GetConfigBlock() (*common.Block, error)
 	// NewTxSimulator gives handle to a transaction simulator.
 	// A client can obtain more than one 'TxSimulator's for parallel execution.
 	// Any snapshoting/synchronization should be performed at the implementation level if required
@@ -95,6 +104,13 @@ type PeerLedger interface {
 	Commit(block *common.Block) error
 	//Prune prunes the blocks/transactions that satisfy the given policy
 	Prune(policy PrunePolicy) error
+	// ExportSnapshot writes the state as of the last committed block, along with the info about that
+	// block, to the given directory which is expected to be empty or non-existent
+	ExportSnapshot(snapshotDir string) (*SnapshotInfo, error)
+	// GetStateHash returns a deterministic hash of the state as of the given block. The state hashes of two
+	// peers for the same block are equal only if their states are the same, irrespective of the state db in use.
+	// nil is returned if the state hash is not available for the block, e.g., the block was not committed by this peer
+	GetStateHash(blockNum uint64) ([]byte, error)
 }
 
 // ValidatedLedger represents the 'final ledger' after filtering out invalid transactions from PeerLedger.
@@ -120,8 +136,13 @@ type QueryExecutor interface {
 	// The returned ResultsIterator contains results of type *KV
 	GetStateRangeScanIterator(namespace string, startKey string, endKey string) (ResultsIterator, error)
 	// ExecuteQuery executes the given query and returns an iterator that contains results of type specific to the underlying data store.
-	// Only used for state databases that support query
-	ExecuteQuery(query string) (ResultsIterator, error)
+	// Only used for state databases that support query. The results are restricted to the given namespace;
+	// an empty namespace queries across all the namespaces and is meant to be used only by system chaincodes
+	ExecuteQuery(namespace, query string) (ResultsIterator, error)
+	// ExecuteQueryWithPagination executes the given query as ExecuteQuery does, returning the results in the order
+	// of their keys from the key `startKey` and at most `limit` results. An empty startKey refers to the first key
+	// and a zero limit means that the results are not limited. The query may not specify a sort nor a skip
+	ExecuteQueryWithPagination(namespace, query string, startKey string, limit int) (ResultsIterator, error)
 	// Done releases resources occupied by the QueryExecutor
 	Done()
 }
@@ -195,5 +216,22 @@ type BlockHolder interface {
 	GetBlockBytes() []byte
 }
 
-// PrunePolicy - a general interface for supporting different pruning policies
-type PrunePolicy interface{}
+// SnapshotInfo describes a snapshot exported by `PeerLedger.ExportSnapshot`
+type SnapshotInfo struct {
+	LedgerID string
+	// BlockchainInfo as of the last block whose updates are included in the snapshot
+	BlockchainInfo *pb.BlockchainInfo
+	// StateHash is a hash of the exported state, which matches across the peers that have the same state
+	StateHash []byte
+	// LedgerStateHash is the state hash of the ledger as of the last block whose updates are included in
+	// the snapshot, as returned by `PeerLedger.GetStateHash`, nil if the ledger did not record one
+	LedgerStateHash []byte
+}
+
+// PrunePolicy - a general interface for supporting different pruning policies.
+// The blocks are pruned from the oldest to the newest and the pruning stops at the first block that the policy retains
+type PrunePolicy interface {
+	// CanPrune returns true if a block can be pruned given the number of blocks committed after it
+	// and the time of the block, which is the latest creation time of its transactions
+	CanPrune(numNewerBlocks uint64, blockTime time.Time) bool
+}
@@ -18,6 +18,7 @@ package ledgerconfig
 
 import (
 	"path/filepath"
+	"runtime"
 
 	"github.com/spf13/viper"
 )
@@ -30,6 +31,10 @@ var historyDatabase = true
 
 var maxBlockFileSize = 0
 
+var defaultQueryBatchSize = 100
+var defaultMaxQueryPageSize = 1000
+var defaultQueryLimit = 1000
+
 // CouchDBDef contains parameters
 type CouchDBDef struct {
 	URL      string
@@ -63,6 +68,16 @@ func GetStateLevelDBPath() string {
 	return filepath.Join(GetRootPath(), "stateLeveldb")
 }
 
+// GetStateHashLevelDBPath returns the filesystem path that is used to maintain the state hash level db
+func GetStateHashLevelDBPath() string {
+	return filepath.Join(GetRootPath(), "stateHashLeveldb")
+}
+
+// GetHistoryLevelDBPath returns the filesystem path that is used to maintain the history level db
+func GetHistoryLevelDBPath() string {
+	return filepath.Join(GetRootPath(), "historyLeveldb")
+}
+
 // GetBlockStorePath returns the filesystem path that is used by the block store
 func GetBlockStorePath() string {
 	return filepath.Join(GetRootPath(), "blocks")
@@ -84,13 +99,41 @@ func GetCouchDBDefinition() *CouchDBDef {
 }
 
 //IsHistoryDBEnabled exposes the historyDatabase variable
-//History database can only be enabled if couchDb is enabled
-//as it the history stored in the same couchDB instance.
-//TODO put History DB in it's own instance
+//The history is stored in the same couchDB instance as the state if couchDb is enabled,
+//otherwise it is stored in a leveldb of its own
+//TODO put History DB in it's own instance when couchDb is enabled
 func IsHistoryDBEnabled() bool {
 	historyDatabase = viper.GetBool("ledger.state.historyDatabase")
-	if IsCouchDBEnabled() && historyDatabase {
-		return historyDatabase
+	return historyDatabase
+}
+
+// GetQueryBatchSize returns the number of results of a range query, a rich query or a key history
+// that are sent to the chaincode in a single response. The remaining results are fetched lazily
+func GetQueryBatchSize() int {
+	return getPositiveInt("ledger.state.queryBatchSize", defaultQueryBatchSize)
+}
+
+// GetMaxQueryPageSize returns the maximum page size that a chaincode can request for a paginated query
+func GetMaxQueryPageSize() int {
+	return getPositiveInt("ledger.state.maxQueryPageSize", defaultMaxQueryPageSize)
+}
+
+// GetQueryLimit returns the limit on the number of documents returned by a single CouchDB request, which
+// is the number of documents that the iterators of the CouchDB state database fetch at a time
+func GetQueryLimit() int {
+	return getPositiveInt("ledger.state.couchDBConfig.queryLimit", defaultQueryLimit)
+}
+
+// GetValidationParallelism returns the number of goroutines that validate the transactions of a block
+// concurrently. It defaults to the number of CPUs
+func GetValidationParallelism() int {
+	return getPositiveInt("ledger.state.validationParallelism", runtime.NumCPU())
+}
+
+func getPositiveInt(key string, defaultValue int) int {
+	value := viper.GetInt(key)
+	if value <= 0 {
+		return defaultValue
 	}
-	return false
+	return value
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package ledgerconfig
 
 import (
+	"runtime"
 	"testing"
 
 	"github.com/hyperledger/fabric/core/ledger/testutil"
@@ -67,7 +68,7 @@ func TestIsHistoryDBEnabledWhenCouchDBIsDisabled(t *testing.T) {
 	viper.Set("ledger.state.stateDatabase", "goleveldb")
 	viper.Set("ledger.state.historyDatabase", true)
 	updatedValue := IsHistoryDBEnabled()
-	testutil.AssertEquals(t, updatedValue, false) //test config is false
+	testutil.AssertEquals(t, updatedValue, true) //test config returns true, history is stored in leveldb
 }
 
 func TestIsHistoryDBEnabledWhenOnlyCouchDBEnabled(t *testing.T) {
@@ -88,6 +89,35 @@ func TestIsHistoryDBEnabled(t *testing.T) {
 	testutil.AssertEquals(t, updatedValue, true) //test config returns true
 }
 
+func TestGetQueryLimits(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer testutil.ResetConfigToDefaultValues()
+	testutil.AssertEquals(t, GetQueryBatchSize(), 100)
+	testutil.AssertEquals(t, GetMaxQueryPageSize(), 1000)
+	testutil.AssertEquals(t, GetQueryLimit(), 1000)
+
+	viper.Set("ledger.state.queryBatchSize", 10)
+	viper.Set("ledger.state.maxQueryPageSize", 20)
+	viper.Set("ledger.state.couchDBConfig.queryLimit", 30)
+	testutil.AssertEquals(t, GetQueryBatchSize(), 10)
+	testutil.AssertEquals(t, GetMaxQueryPageSize(), 20)
+	testutil.AssertEquals(t, GetQueryLimit(), 30)
+
+	// the defaults are used for the values that are not positive
+	viper.Set("ledger.state.queryBatchSize", 0)
+	viper.Set("ledger.state.couchDBConfig.queryLimit", -1)
+	testutil.AssertEquals(t, GetQueryBatchSize(), 100)
+	testutil.AssertEquals(t, GetQueryLimit(), 1000)
+}
+
+func TestGetValidationParallelism(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer testutil.ResetConfigToDefaultValues()
+	testutil.AssertEquals(t, GetValidationParallelism(), runtime.NumCPU())
+	viper.Set("ledger.state.validationParallelism", 4)
+	testutil.AssertEquals(t, GetValidationParallelism(), 4)
+}
+
 func setUpCoreYAMLConfig() {
 	//call a helper method to load the core.yaml
 	testutil.SetupCoreYAMLConfig("./../../../peer")
@@ -80,6 +80,24 @@ func CreateLedger(id string) (ledger.PeerLedger, error) {
 	return l, nil
 }
 
+// CreateLedgerFromSnapshot creates a new ledger with the given id from the snapshot in the given directory
+func CreateLedgerFromSnapshot(id string, snapshotDir string) (ledger.PeerLedger, error) {
+	logger.Infof("Creating leadger with id = %s from snapshot = %s", id, snapshotDir)
+	lock.Lock()
+	defer lock.Unlock()
+	if !initialized {
+		return nil, ErrLedgerMgmtNotInitialized
+	}
+	l, err := ledgerProvider.CreateFromSnapshot(id, snapshotDir)
+	if err != nil {
+		return nil, err
+	}
+	l = wrapLedger(id, l)
+	openedLedgers[id] = l
+	logger.Infof("Created leadger with id = %s from snapshot", id)
+	return l, nil
+}
+
 // OpenLedger returns a ledger for the given id
 func OpenLedger(id string) (ledger.PeerLedger, error) {
 	logger.Infof("Opening leadger with id = %s", id)
@@ -17,8 +17,6 @@ limitations under the License.
 package ordererledger
 
 import (
-	"errors"
-
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/blkstorage"
 
@@ -52,9 +50,11 @@ func (rl *fsBasedOrdererLedger) GetBlocksIterator(startBlockNumber uint64) (ledg
 	return rl.blockStore.RetrieveBlocks(startBlockNumber)
 }
 
-//Prune prunes the blocks/transactions that satisfy the given policy
+// Prune removes the oldest block files all of whose blocks satisfy the given policy.
+// The pruned blocks can no longer be delivered, hence a policy should retain the blocks that
+// the consumers of the chain may still need to fetch
 func (rl *fsBasedOrdererLedger) Prune(policy ledger.PrunePolicy) error {
-	return errors.New("Not yet implemented")
+	return rl.blockStore.Prune(policy)
 }
 
 // Close closes the ledger
@@ -0,0 +1,63 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ledger
+
+import "time"
+
+// KeepLastNBlocksPolicy is a `PrunePolicy` that retains the latest N blocks of the chain
+type KeepLastNBlocksPolicy struct {
+	N uint64
+}
+
+// CanPrune implements method in interface `PrunePolicy`
+func (p *KeepLastNBlocksPolicy) CanPrune(numNewerBlocks uint64, blockTime time.Time) bool {
+	return numNewerBlocks >= p.N
+}
+
+// KeepBlocksNewerThanPolicy is a `PrunePolicy` that retains the blocks newer than the given time
+type KeepBlocksNewerThanPolicy struct {
+	T time.Time
+}
+
+// CanPrune implements method in interface `PrunePolicy`
+func (p *KeepBlocksNewerThanPolicy) CanPrune(numNewerBlocks uint64, blockTime time.Time) bool {
+	return !blockTime.After(p.T)
+}
+
+// KeepBlocksForPolicy is a `PrunePolicy` that retains the blocks newer than the given duration
+// at the time of pruning
+type KeepBlocksForPolicy struct {
+	D time.Duration
+}
+
+// CanPrune implements method in interface `PrunePolicy`
+func (p *KeepBlocksForPolicy) CanPrune(numNewerBlocks uint64, blockTime time.Time) bool {
+	return !blockTime.After(time.Now().Add(-p.D))
+}
+
+// KeepBlocksOfAnyPolicy is a `PrunePolicy` that retains the blocks that any of the given policies retains
+type KeepBlocksOfAnyPolicy []PrunePolicy
+
+// CanPrune implements method in interface `PrunePolicy`
+func (p KeepBlocksOfAnyPolicy) CanPrune(numNewerBlocks uint64, blockTime time.Time) bool {
+	for _, policy := range p {
+		if !policy.CanPrune(numNewerBlocks, blockTime) {
+			return false
+		}
+	}
+	return true
+}
@@ -90,6 +90,10 @@ func ResetConfigToDefaultValues() {
 	//reset to defaults
 	viper.Set("ledger.state.stateDatabase", "goleveldb")
 	viper.Set("ledger.state.historyDatabase", false)
+	viper.Set("ledger.state.queryBatchSize", 100)
+	viper.Set("ledger.state.maxQueryPageSize", 1000)
+	viper.Set("ledger.state.couchDBConfig.queryLimit", 1000)
+	viper.Set("ledger.state.validationParallelism", 0)
 }
 
 // SetLogLevel sets up log level
@@ -147,6 +147,51 @@ type DocRev struct {
 	Rev string `json:"_rev"`
 }
 
+//DocMetadata returns the ID and revision of a couchdb document
+type DocMetadata struct {
+	ID  string
+	Rev string
+}
+
+//CouchDoc defines a document to be sent to couchdb in a bulk update.  The value of the document is either
+//the JSONValue or the Attachments.  Rev is expected to be set for updating or deleting an existing document
+type CouchDoc struct {
+	ID          string
+	Rev         string
+	JSONValue   []byte
+	Attachments []Attachment
+	Deleted     bool
+}
+
+//BatchRetrieveDocResponse is used for processing REST responses from CouchDB for `_all_docs` with keys
+type BatchRetrieveDocResponse struct {
+	Rows []struct {
+		ID    string `json:"id"`
+		Key   string `json:"key"`
+		Error string `json:"error"`
+		Value struct {
+			Rev     string `json:"rev"`
+			Deleted bool   `json:"deleted"`
+		} `json:"value"`
+		Doc json.RawMessage `json:"doc"`
+	} `json:"rows"`
+}
+
+//BatchUpdateResponse defines a structure for the response of a document in a bulk update
+type BatchUpdateResponse struct {
+	ID     string `json:"id"`
+	Error  string `json:"error"`
+	Reason string `json:"reason"`
+	Ok     bool   `json:"ok"`
+	Rev    string `json:"rev"`
+}
+
+//inlineAttachment is used for sending and retrieving the data of an attachment within a JSON document
+type inlineAttachment struct {
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"`
+}
+
 //FileDetails defines the structure needed to send an attachment to couchdb
 type FileDetails struct {
 	Follows     bool   `json:"follows"`
@@ -784,6 +829,198 @@ func (dbclient *CouchDatabase) QueryDocuments(query string, limit, skip int) (*[
 
 }
 
+//BatchRetrieveIDRevision method provides function to retrieve the revisions of multiple documents in a
+//single request.  Only the documents that exist (and are not deleted) are returned
+func (dbclient *CouchDatabase) BatchRetrieveIDRevision(keys []string) ([]*DocMetadata, error) {
+
+	logger.Debugf("Entering BatchRetrieveIDRevision()  keys=%s", keys)
+
+	jsonResponse, err := dbclient.batchRetrieve(keys, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var docMetadataArray []*DocMetadata
+	for _, row := range jsonResponse.Rows {
+		if row.Error != "" || row.Value.Deleted {
+			continue
+		}
+		docMetadataArray = append(docMetadataArray, &DocMetadata{ID: row.ID, Rev: row.Value.Rev})
+	}
+
+	logger.Debugf("Exiting BatchRetrieveIDRevision()")
+
+	return docMetadataArray, nil
+
+}
+
+//BatchRetrieveDocuments method provides function to retrieve multiple documents in a single request.
+//The results are in the order of the given keys, with a nil entry for a document that does not exist or is deleted.
+//For a document saved with attachments, the value of the "valueBytes" attachment is returned
+func (dbclient *CouchDatabase) BatchRetrieveDocuments(keys []string) ([]*QueryResult, error) {
+
+	logger.Debugf("Entering BatchRetrieveDocuments()  keys=%s", keys)
+
+	jsonResponse, err := dbclient.batchRetrieve(keys, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(jsonResponse.Rows) != len(keys) {
+		return nil, fmt.Errorf("Expected [%d] rows in the response, received [%d]", len(keys), len(jsonResponse.Rows))
+	}
+
+	results := make([]*QueryResult, len(keys))
+	for i, row := range jsonResponse.Rows {
+		if row.Error != "" || row.Value.Deleted || row.Doc == nil {
+			continue
+		}
+
+		var jsonDoc = &Doc{}
+		err = json.Unmarshal(row.Doc, &jsonDoc)
+		if err != nil {
+			return nil, err
+		}
+
+		if jsonDoc.Attachments != nil {
+			attachments := make(map[string]*inlineAttachment)
+			err = json.Unmarshal(jsonDoc.Attachments, &attachments)
+			if err != nil {
+				return nil, err
+			}
+			var value []byte
+			if attachment, ok := attachments["valueBytes"]; ok {
+				value = attachment.Data
+			}
+			results[i] = &QueryResult{jsonDoc.ID, version.NewHeight(1, 1), value}
+		} else {
+			results[i] = &QueryResult{jsonDoc.ID, version.NewHeight(1, 1), row.Doc}
+		}
+	}
+
+	logger.Debugf("Exiting BatchRetrieveDocuments()")
+
+	return results, nil
+
+}
+
+//batchRetrieve posts the keys to `_all_docs`, which is the equivalent of `_all_docs?keys=` that is not limited
+//by the length of the URL.  The documents, along with the data of their attachments, are included if includeDocs is set
+func (dbclient *CouchDatabase) batchRetrieve(keys []string, includeDocs bool) (*BatchRetrieveDocResponse, error) {
+
+	batchURL, err := url.Parse(dbclient.couchInstance.conf.URL)
+	if err != nil {
+		logger.Errorf("URL parse error: %s", err.Error())
+		return nil, err
+	}
+	batchURL.Path = dbclient.dbName + "/_all_docs"
+
+	if includeDocs {
+		queryParms := batchURL.Query()
+		queryParms.Add("include_docs", "true")
+		queryParms.Add("attachments", "true")
+		batchURL.RawQuery = queryParms.Encode()
+	}
+
+	keysJSON, err := json.Marshal(map[string]interface{}{"keys": keys})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, _, err := dbclient.handleRequest(http.MethodPost, batchURL.String(), bytes.NewReader(keysJSON), "", "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	jsonResponse := &BatchRetrieveDocResponse{}
+	err = json.NewDecoder(resp.Body).Decode(&jsonResponse)
+	if err != nil {
+		return nil, err
+	}
+	return jsonResponse, nil
+
+}
+
+//BatchUpdateDocuments method provides function to save, update and delete multiple documents in a single
+//`_bulk_docs` request.  CouchDB processes each of the documents independently, so the response for each
+//of the documents is returned and is expected to be checked for an error by the caller
+func (dbclient *CouchDatabase) BatchUpdateDocuments(documents []*CouchDoc) ([]*BatchUpdateResponse, error) {
+
+	logger.Debugf("Entering BatchUpdateDocuments()  number of documents=%d", len(documents))
+
+	batchURL, err := url.Parse(dbclient.couchInstance.conf.URL)
+	if err != nil {
+		logger.Errorf("URL parse error: %s", err.Error())
+		return nil, err
+	}
+	batchURL.Path = dbclient.dbName + "/_bulk_docs"
+
+	docs := make([]json.RawMessage, len(documents))
+	for i, document := range documents {
+		docs[i], err = document.toJSON()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	bulkDocsJSON, err := json.Marshal(map[string]interface{}{"docs": docs})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, _, err := dbclient.handleRequest(http.MethodPost, batchURL.String(), bytes.NewReader(bulkDocsJSON), "", "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var jsonResponse []*BatchUpdateResponse
+	err = json.NewDecoder(resp.Body).Decode(&jsonResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("Exiting BatchUpdateDocuments()")
+
+	return jsonResponse, nil
+
+}
+
+//toJSON constructs the JSON of the document for a bulk update.  The "_id" and the "_rev" fields are
+//added to the JSON value, and the attachments are included inline
+func (document *CouchDoc) toJSON() ([]byte, error) {
+
+	fields := make(map[string]interface{})
+
+	switch {
+	case document.Deleted:
+		fields["_deleted"] = true
+	case document.Attachments != nil:
+		attachments := make(map[string]*inlineAttachment)
+		for _, attachment := range document.Attachments {
+			attachments[attachment.Name] = &inlineAttachment{attachment.ContentType, attachment.AttachmentBytes}
+		}
+		fields["_attachments"] = attachments
+	default:
+		jsonValue := make(map[string]json.RawMessage)
+		err := json.Unmarshal(document.JSONValue, &jsonValue)
+		if err != nil {
+			return nil, fmt.Errorf("JSON format is not valid: %s", err)
+		}
+		for field, value := range jsonValue {
+			fields[field] = value
+		}
+	}
+
+	fields["_id"] = document.ID
+	if document.Rev != "" {
+		fields["_rev"] = document.Rev
+	}
+
+	return json.Marshal(fields)
+
+}
+
 //handleRequest method is a generic http request handler
 func (dbclient *CouchDatabase) handleRequest(method, connectURL string, data io.Reader, rev string, multipartBoundary string) (*http.Response, *DBReturn, error) {
 
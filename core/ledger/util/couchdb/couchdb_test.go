@@ -255,3 +255,91 @@ func TestDBSaveAttachment(t *testing.T) {
 
 	}
 }
+
+func TestDBBatchOperations(t *testing.T) {
+
+	//run against the local stand-in for CouchDB if CouchDB is not enabled
+	batchConnectURL := connectURL
+	if ledgerconfig.IsCouchDBEnabled() == false {
+		address, stop := startCouchDBStandIn()
+		if stop == nil {
+			t.Skip("Skipping, as CouchDB is not enabled in core.yaml and the test was not built with the couchdbfake tag")
+		}
+		defer stop()
+		batchConnectURL = address
+	}
+
+	//create a new instance and database object
+	couchInstance, err := CreateCouchInstance(batchConnectURL, username, password)
+	testutil.AssertNoError(t, err, fmt.Sprintf("Error when trying to create couch instance"))
+	db, err := CreateCouchDatabase(*couchInstance, database)
+	testutil.AssertNoError(t, err, fmt.Sprintf("Error when trying to create database"))
+	defer db.DropDatabase()
+
+	byteText := []byte(`This is a test document.  This is only a test`)
+	attachment := Attachment{Name: "valueBytes", ContentType: "application/octet-stream", AttachmentBytes: byteText}
+
+	//save a JSON document and a document with an attachment in a single request
+	responses, err := db.BatchUpdateDocuments([]*CouchDoc{
+		{ID: "marble1", JSONValue: assetJSON},
+		{ID: "binary1", Attachments: []Attachment{attachment}}})
+	testutil.AssertNoError(t, err, fmt.Sprintf("Error when trying to update documents in a batch"))
+	testutil.AssertEquals(t, len(responses), 2)
+	for _, response := range responses {
+		testutil.AssertEquals(t, response.Ok, true)
+	}
+
+	//the documents saved in the batch can be read individually
+	returnDoc, _, err := db.ReadDoc("binary1")
+	testutil.AssertNoError(t, err, fmt.Sprintf("Error when trying to retrieve a document with attachment"))
+	testutil.AssertEquals(t, returnDoc, byteText)
+	returnDoc, _, err = db.ReadDoc("marble1")
+	testutil.AssertNoError(t, err, fmt.Sprintf("Error when trying to retrieve a document"))
+	asset := &Asset{}
+	json.Unmarshal(returnDoc, asset)
+	testutil.AssertEquals(t, asset.Owner, "jerry")
+
+	//retrieve the revisions, a document that does not exist is not returned
+	docMetadataArray, err := db.BatchRetrieveIDRevision([]string{"marble1", "binary1", "missing"})
+	testutil.AssertNoError(t, err, fmt.Sprintf("Error when trying to retrieve the revisions of documents"))
+	testutil.AssertEquals(t, len(docMetadataArray), 2)
+	revisions := make(map[string]string)
+	for _, docMetadata := range docMetadataArray {
+		revisions[docMetadata.ID] = docMetadata.Rev
+	}
+
+	//retrieve the documents, in the order of the keys
+	queryResults, err := db.BatchRetrieveDocuments([]string{"binary1", "missing", "marble1"})
+	testutil.AssertNoError(t, err, fmt.Sprintf("Error when trying to retrieve documents in a batch"))
+	testutil.AssertEquals(t, len(queryResults), 3)
+	testutil.AssertEquals(t, queryResults[0].Value, byteText)
+	testutil.AssertNil(t, queryResults[1])
+	asset = &Asset{}
+	json.Unmarshal(queryResults[2].Value, asset)
+	testutil.AssertEquals(t, asset.AssetName, "marble1")
+
+	//an update without the current revision is rejected for the document
+	responses, err = db.BatchUpdateDocuments([]*CouchDoc{{ID: "marble1", JSONValue: assetJSON}})
+	testutil.AssertNoError(t, err, fmt.Sprintf("Error when trying to update documents in a batch"))
+	testutil.AssertEquals(t, responses[0].Error, "conflict")
+
+	//delete a document and update the other
+	responses, err = db.BatchUpdateDocuments([]*CouchDoc{
+		{ID: "marble1", Rev: revisions["marble1"], Deleted: true},
+		{ID: "binary1", Rev: revisions["binary1"], JSONValue: assetJSON}})
+	testutil.AssertNoError(t, err, fmt.Sprintf("Error when trying to update documents in a batch"))
+	for _, response := range responses {
+		testutil.AssertEquals(t, response.Error, "")
+	}
+	returnDoc, _, err = db.ReadDoc("marble1")
+	testutil.AssertNoError(t, err, fmt.Sprintf("Error when trying to retrieve a deleted document"))
+	testutil.AssertNil(t, returnDoc)
+	docMetadataArray, err = db.BatchRetrieveIDRevision([]string{"marble1", "binary1"})
+	testutil.AssertNoError(t, err, fmt.Sprintf("Error when trying to retrieve the revisions of documents"))
+	testutil.AssertEquals(t, len(docMetadataArray), 1)
+	testutil.AssertEquals(t, docMetadataArray[0].ID, "binary1")
+	queryResults, err = db.BatchRetrieveDocuments([]string{"marble1"})
+	testutil.AssertNoError(t, err, fmt.Sprintf("Error when trying to retrieve documents in a batch"))
+	testutil.AssertNil(t, queryResults[0])
+
+}
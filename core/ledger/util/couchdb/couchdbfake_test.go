@@ -0,0 +1,28 @@
+// +build couchdbfake
+
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package couchdb
+
+import "github.com/hyperledger/fabric/core/ledger/internal/couchdbtest"
+
+// startCouchDBStandIn starts the in-memory stand-in for CouchDB and returns its address
+// along with the function stopping it
+func startCouchDBStandIn() (string, func()) {
+	couchDBServer := couchdbtest.NewServer()
+	return couchDBServer.Address(), couchDBServer.Close
+}
@@ -0,0 +1,118 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// TxValidationFlags holds the validation code of each transaction of a block, one byte per
+// transaction, as stored in the TRANSACTIONS_VALIDATION_CODES metadata of the block. A transaction
+// beyond the end of the flags, such as a transaction of a block that carries no flags, is valid.
+//
+// The TRANSACTIONS_FILTER metadata of the block keeps holding the FilterBitArray of the invalid
+// transactions, for the consumers which only tell valid and invalid transactions apart. The blocks
+// committed before the validation codes were recorded only carry the bit array, and the invalid
+// transactions of those blocks have the INVALID_OTHER_REASON code
+type TxValidationFlags []uint8
+
+// NewTxValidationFlags creates the flags for the given number of transactions, all of them valid
+func NewTxValidationFlags(size int) TxValidationFlags {
+	return make(TxValidationFlags, size)
+}
+
+// NewTxValidationFlagsFromBytes reconstructs the flags from the given bytes
+func NewTxValidationFlagsFromBytes(bytes []byte) TxValidationFlags {
+	return TxValidationFlags(bytes)
+}
+
+// SetFlag assigns the validation code of the transaction at the given index, which starts
+// from 0. The flags are extended to accommodate the index, the added transactions being valid
+func (flags *TxValidationFlags) SetFlag(txIndex int, flag pb.TxValidationCode) {
+	if txIndex >= len(*flags) {
+		extended := make(TxValidationFlags, txIndex+1)
+		copy(extended, *flags)
+		*flags = extended
+	}
+	(*flags)[txIndex] = uint8(flag)
+}
+
+// Flag returns the validation code of the transaction at the given index
+func (flags TxValidationFlags) Flag(txIndex int) pb.TxValidationCode {
+	if txIndex >= len(flags) {
+		return pb.TxValidationCode_VALID
+	}
+	return pb.TxValidationCode(flags[txIndex])
+}
+
+// IsValid returns true if the transaction at the given index is valid
+func (flags TxValidationFlags) IsValid(txIndex int) bool {
+	return flags.Flag(txIndex) == pb.TxValidationCode_VALID
+}
+
+// IsInvalid returns true if the transaction at the given index is not valid
+func (flags TxValidationFlags) IsInvalid(txIndex int) bool {
+	return !flags.IsValid(txIndex)
+}
+
+// ToBytes returns the bytes of the flags for storage
+func (flags TxValidationFlags) ToBytes() []byte {
+	return flags
+}
+
+// GetTxValidationFlags returns the validation flags stored in the given block metadata, which
+// are derived from the bit array of TRANSACTIONS_FILTER if the metadata carries no validation codes
+func GetTxValidationFlags(metadata *common.BlockMetadata) TxValidationFlags {
+	if metadata == nil {
+		return nil
+	}
+	if len(metadata.Metadata) > int(common.BlockMetadataIndex_TRANSACTIONS_VALIDATION_CODES) &&
+		len(metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_VALIDATION_CODES]) > 0 {
+		return NewTxValidationFlagsFromBytes(metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_VALIDATION_CODES])
+	}
+	if len(metadata.Metadata) <= int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		return nil
+	}
+	bitArray := NewFilterBitArrayFromBytes(metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	var flags TxValidationFlags
+	for i := uint(0); i < bitArray.Capacity(); i++ {
+		if bitArray.IsSet(i) {
+			flags.SetFlag(int(i), pb.TxValidationCode_INVALID_OTHER_REASON)
+		}
+	}
+	return flags
+}
+
+// SetTxValidationFlags stores the given validation flags in the metadata of the given block, along
+// with the bit array of the invalid transactions in TRANSACTIONS_FILTER
+func SetTxValidationFlags(block *common.Block, flags TxValidationFlags) {
+	if block.Metadata == nil {
+		block.Metadata = &common.BlockMetadata{}
+	}
+	for len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_TRANSACTIONS_VALIDATION_CODES) {
+		block.Metadata.Metadata = append(block.Metadata.Metadata, []byte{})
+	}
+	bitArray := FilterBitArray{}
+	for i := range flags {
+		if flags.IsInvalid(i) {
+			bitArray.Set(uint(i))
+		}
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = bitArray.ToBytes()
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_VALIDATION_CODES] = flags.ToBytes()
+}
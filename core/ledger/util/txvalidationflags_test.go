@@ -0,0 +1,93 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxValidationFlags(t *testing.T) {
+	flags := NewTxValidationFlags(3)
+	for i := 0; i < 3; i++ {
+		assert.True(t, flags.IsValid(i))
+	}
+	flags.SetFlag(1, pb.TxValidationCode_MVCC_READ_CONFLICT)
+	flags.SetFlag(2, pb.TxValidationCode_INVALID_OTHER_REASON)
+	assert.True(t, flags.IsValid(0))
+	assert.True(t, flags.IsInvalid(1))
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, flags.Flag(1))
+	assert.Equal(t, pb.TxValidationCode_INVALID_OTHER_REASON, flags.Flag(2))
+
+	flags = NewTxValidationFlagsFromBytes(flags.ToBytes())
+	assert.Equal(t, []byte{0, 11, 255}, flags.ToBytes())
+
+	// the flags are extended to accommodate the index, and the transactions beyond them are valid
+	assert.True(t, flags.IsValid(5))
+	flags.SetFlag(5, pb.TxValidationCode_DUPLICATE_TXID)
+	assert.Equal(t, []byte{0, 11, 255, 0, 0, 9}, flags.ToBytes())
+
+	var noFlags TxValidationFlags
+	assert.True(t, noFlags.IsValid(0))
+}
+
+func TestBlockTxValidationFlags(t *testing.T) {
+	block := common.NewBlock(0, []byte{})
+	block.Data.Data = [][]byte{[]byte("tx0"), []byte("tx1"), []byte("tx2")}
+	assert.True(t, GetTxValidationFlags(block.Metadata).IsValid(1))
+
+	flags := NewTxValidationFlags(3)
+	flags.SetFlag(1, pb.TxValidationCode_MVCC_READ_CONFLICT)
+	SetTxValidationFlags(block, flags)
+	assert.Equal(t, flags, GetTxValidationFlags(block.Metadata))
+	// the bit array of the invalid transactions is kept for the consumers of the transactions filter
+	bitArray := NewFilterBitArrayFromBytes(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	assert.False(t, bitArray.IsSet(0))
+	assert.True(t, bitArray.IsSet(1))
+	assert.False(t, bitArray.IsSet(2))
+
+	// the metadata is extended to hold the validation codes
+	block.Metadata.Metadata = block.Metadata.Metadata[:common.BlockMetadataIndex_TRANSACTIONS_FILTER+1]
+	SetTxValidationFlags(block, flags)
+	assert.Equal(t, flags, GetTxValidationFlags(block.Metadata))
+
+	assert.Nil(t, GetTxValidationFlags(nil))
+}
+
+func TestLegacyTxValidationFlags(t *testing.T) {
+	// a block committed before the validation codes were recorded only carries the bit array
+	bitArray := NewFilterBitArray(10)
+	bitArray.Set(2)
+	bitArray.Set(9)
+	metadata := &common.BlockMetadata{Metadata: [][]byte{[]byte{}, []byte{}, bitArray.ToBytes()}}
+	flags := GetTxValidationFlags(metadata)
+	for i := 0; i < 10; i++ {
+		if i == 2 || i == 9 {
+			assert.Equal(t, pb.TxValidationCode_INVALID_OTHER_REASON, flags.Flag(i))
+		} else {
+			assert.True(t, flags.IsValid(i))
+		}
+	}
+
+	metadata.Metadata = append(metadata.Metadata, []byte{}, []byte{})
+	assert.Equal(t, flags, GetTxValidationFlags(metadata))
+
+	assert.Nil(t, GetTxValidationFlags(&common.BlockMetadata{Metadata: [][]byte{[]byte{}, []byte{}}}))
+}
@@ -138,3 +138,12 @@ func (bh *MSPConfigHandler) ProposeConfig(configItem *common.ConfigurationItem)
 func (bh *MSPConfigHandler) GetMSPManager() msp.MSPManager {
 	return bh.committedMgr
 }
+
+// DeserializeIdentity deserializes an identity with the currently committed MSP
+// manager, so that the policies of a chain follow the updates of its MSP configuration
+func (bh *MSPConfigHandler) DeserializeIdentity(serializedIdentity []byte) (msp.Identity, error) {
+	if bh.committedMgr == nil {
+		return nil, fmt.Errorf("No MSP configured for this chain")
+	}
+	return bh.committedMgr.DeserializeIdentity(serializedIdentity)
+}
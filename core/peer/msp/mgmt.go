@@ -59,6 +59,16 @@ func GetManagerForChain(ChainID string) msp.MSPManager {
 	return mspMgr
 }
 
+// SetManagerForChain sets the msp manager for the supplied chain,
+// replacing the existing one once the configuration of the chain
+// changes, as an msp manager is set up only once
+func SetManagerForChain(ChainID string, mspMgr msp.MSPManager) {
+	m.Lock()
+	defer m.Unlock()
+
+	mspMap[ChainID] = mspMgr
+}
+
 // GetLocalMSP returns the local msp (and creates it if it doesn't exist)
 func GetLocalMSP() msp.MSP {
 	var lclMsp msp.MSP
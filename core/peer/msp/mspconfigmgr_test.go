@@ -64,3 +64,32 @@ func TestMSPConfigManager(t *testing.T) {
 	err = mspCH.ProposeConfig(&common.ConfigurationItem{Key: msputils.MSPKey, Value: []byte("BARF!")})
 	assert.Error(t, err)
 }
+
+func TestMSPConfigHandlerDeserializeIdentity(t *testing.T) {
+	conf, err := msp.GetLocalMspConfig("../../../msp/sampleconfig/")
+	assert.NoError(t, err)
+	confBytes, err := proto.Marshal(conf)
+	assert.NoError(t, err)
+	ci := &common.ConfigurationItem{Key: msputils.MSPKey, Value: confBytes}
+
+	localMsp, err := msp.NewBccspMsp()
+	assert.NoError(t, err)
+	assert.NoError(t, localMsp.Setup(conf))
+	signer, err := localMsp.GetDefaultSigningIdentity()
+	assert.NoError(t, err)
+	serializedIdentity, err := signer.Serialize()
+	assert.NoError(t, err)
+
+	// no MSP is committed yet, even when one is proposed
+	mspCH := &MSPConfigHandler{}
+	mspCH.BeginConfig()
+	assert.NoError(t, mspCH.ProposeConfig(ci))
+	_, err = mspCH.DeserializeIdentity(serializedIdentity)
+	assert.Error(t, err)
+
+	// the committed MSP deserializes the identities of its members
+	mspCH.CommitConfig()
+	identity, err := mspCH.DeserializeIdentity(serializedIdentity)
+	assert.NoError(t, err)
+	assert.Equal(t, signer.GetIdentifier(), identity.GetIdentifier())
+}
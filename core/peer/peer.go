@@ -18,7 +18,6 @@ package peer
 
 import (
 	"fmt"
-	"math"
 	"net"
 	"sync"
 
@@ -27,12 +26,17 @@ import (
 	"github.com/op/go-logging"
 	"github.com/spf13/viper"
 
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/chainconfig"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/core/committer"
 	"github.com/hyperledger/fabric/core/committer/txvalidator"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	"github.com/hyperledger/fabric/core/peer/msp"
+	"github.com/hyperledger/fabric/core/peer/sharedconfig"
 	"github.com/hyperledger/fabric/gossip/service"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/protos/common"
@@ -43,10 +47,14 @@ var peerLogger = logging.MustGetLogger("peer")
 
 // chain is a local struct to manage objects in a chain
 type chain struct {
-	cb        *common.Block
-	ledger    ledger.PeerLedger
-	committer committer.Committer
-	mspmgr    msp.MSPManager
+	cb               *common.Block
+	ledger           ledger.PeerLedger
+	committer        committer.Committer
+	mspmgr           msp.MSPManager
+	policyManager    policies.Manager
+	configManager    configtx.Manager
+	mspConfigHandler *mspmgmt.MSPConfigHandler
+	peerConfig       sharedconfig.Descriptor
 }
 
 // chains is a local map of chainID->chainObject
@@ -112,54 +120,146 @@ func CreateDeliveryService(chainID string) error {
 }
 
 func getCurrConfigBlockFromLedger(ledger ledger.PeerLedger) (*common.Block, error) {
-	// Configuration blocks contain only 1 transaction, so we look for 1-tx
-	// blocks and check the transaction type
-	var envelope *common.Envelope
-	var tx *common.Payload
-	var block *common.Block
-	var err error
-	var currBlockNumber uint64 = math.MaxUint64
-	for currBlockNumber >= 0 {
-		if block, err = ledger.GetBlockByNumber(currBlockNumber); err != nil {
-			return nil, err
-		}
-		if block.Data != nil && len(block.Data.Data) == 1 {
-			if envelope, err = utils.ExtractEnvelope(block, 0); err != nil {
-				peerLogger.Warning("Failed to get Envelope from Block %d.", block.Header.Number)
-				currBlockNumber = block.Header.Number - 1
-				continue
-			}
-			if tx, err = utils.ExtractPayload(envelope); err != nil {
-				peerLogger.Warning("Failed to get Payload from Block %d.", block.Header.Number)
-				currBlockNumber = block.Header.Number - 1
-				continue
-			}
-			if tx.Header.ChainHeader.Type == int32(common.HeaderType_CONFIGURATION_TRANSACTION) {
-				return block, nil
-			}
-		}
-		currBlockNumber = block.Header.Number - 1
+	// The ledger keeps the latest valid configuration block apart from the
+	// block files, so it is found even when it has been pruned or precedes
+	// the snapshot the ledger was created from
+	block, err := ledger.GetConfigBlock()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to find configuration block: %s", err)
 	}
-	return nil, fmt.Errorf("Failed to find configuration block.")
+	return block, nil
 }
 
 // createChain creates a new chain object and insert it into the chains
 func createChain(cid string, ledger ledger.PeerLedger, cb *common.Block) error {
-	c := committer.NewLedgerCommitter(ledger, txvalidator.NewTxValidator(ledger))
+	configEnvelope, err := getConfigEnvelopeFromBlock(cb)
+	if err != nil {
+		return err
+	}
 
-	mgr, err := mspmgmt.GetMSPManagerFromBlock(cid, cb)
+	configManager, policyManager, mspConfigHandler, chainConfig, peerConfig, err := newConfigManagerAndHandlers(configEnvelope)
 	if err != nil {
 		return err
 	}
 
+	mgr := mspConfigHandler.GetMSPManager()
+	if mgr == nil {
+		return fmt.Errorf("No MSP configuration found in the configuration block of chain %s", cid)
+	}
+	mspmgmt.SetManagerForChain(cid, mgr)
+
+	c := committer.NewLedgerCommitterReactive(ledger, txvalidator.NewTxValidator(ledger, configManager), chainConfig.HashingAlgorithm(), func(block *common.Block) error {
+		return updateChainConfig(cid, block)
+	})
+
+	// The chain is registered before joining the channel, as gossip may commit a block, and hence apply
+	// its configuration, as soon as the channel is joined
+	chains.Lock()
+	chains.list[cid] = &chain{cb: cb, ledger: ledger, mspmgr: mgr, committer: c, policyManager: policyManager,
+		configManager: configManager, mspConfigHandler: mspConfigHandler, peerConfig: peerConfig}
+	chains.Unlock()
+
 	if err := service.GetGossipService().JoinChannel(c, cb); err != nil {
+		chains.Lock()
+		delete(chains.list, cid)
+		chains.Unlock()
 		return err
 	}
 
+	// The anchor peers are read under the lock, as a configuration block may have been applied meanwhile
 	chains.Lock()
-	defer chains.Unlock()
-	chains.list[cid] = &chain{cb: cb, ledger: ledger, mspmgr: mgr, committer: c}
-	return nil
+	anchorPeers := peerConfig.AnchorPeers()
+	chains.Unlock()
+	return service.GetGossipService().UpdateChannel(cid, anchorPeers)
+}
+
+// getConfigEnvelopeFromBlock returns the configuration envelope carried by the config block
+func getConfigEnvelopeFromBlock(cb *common.Block) (*common.ConfigurationEnvelope, error) {
+	envelope, err := utils.ExtractEnvelope(cb, 0)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := utils.ExtractPayload(envelope)
+	if err != nil {
+		return nil, err
+	}
+	return utils.GetConfigurationEnvelope(payload.Data)
+}
+
+// newConfigManagerAndHandlers creates the configuration manager of a chain from the
+// configuration envelope of its config block, along with the handlers which hold the
+// policies, the MSPs, and the chain and peer configuration of the chain. The initial
+// configuration is not checked against modification policies, as the block comes from
+// a trusted source or the ledger, while the later configuration transactions are
+func newConfigManagerAndHandlers(configEnvelope *common.ConfigurationEnvelope) (configtx.Manager, policies.Manager, *mspmgmt.MSPConfigHandler, chainconfig.Descriptor, sharedconfig.Descriptor, error) {
+	mspConfigHandler := &mspmgmt.MSPConfigHandler{}
+	policyManager := policies.NewManagerImpl(map[int32]policies.Provider{
+		int32(common.Policy_SIGNATURE): cauthdsl.NewPolicyProvider(mspConfigHandler),
+	})
+	chainConfig := chainconfig.NewDescriptorImpl()
+	peerConfig := sharedconfig.NewManagerImpl()
+	configHandlerMap := make(map[common.ConfigurationItem_ConfigurationType]configtx.Handler)
+	for ctype := range common.ConfigurationItem_ConfigurationType_name {
+		rtype := common.ConfigurationItem_ConfigurationType(ctype)
+		switch rtype {
+		case common.ConfigurationItem_Policy:
+			configHandlerMap[rtype] = policyManager
+		case common.ConfigurationItem_Chain:
+			configHandlerMap[rtype] = chainConfig
+		case common.ConfigurationItem_Peer:
+			configHandlerMap[rtype] = peerConfig
+		case common.ConfigurationItem_MSP:
+			configHandlerMap[rtype] = mspConfigHandler
+		default:
+			configHandlerMap[rtype] = configtx.NewBytesHandler()
+		}
+	}
+
+	configManager, err := configtx.NewConfigurationManager(configEnvelope, policyManager, configHandlerMap)
+	if err != nil {
+		return nil, nil, nil, nil, nil, fmt.Errorf("Error unpacking configuration transaction: %s", err)
+	}
+
+	if err = chainConfig.Validate(); err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+
+	return configManager, policyManager, mspConfigHandler, chainConfig, peerConfig, nil
+}
+
+// updateChainConfig applies the configuration carried by a committed config block
+// of the chain, which the committer validated against the current configuration.
+// The policies and the MSPs of the chain are updated, gossip is given the anchor
+// peers of the new configuration and the block becomes the current config block
+func updateChainConfig(cid string, block *common.Block) error {
+	// The genesis block carries the configuration the chain was created from
+	if block.Header.Number == 0 {
+		return nil
+	}
+
+	configEnvelope, err := getConfigEnvelopeFromBlock(block)
+	if err != nil {
+		return err
+	}
+
+	chains.Lock()
+	c, ok := chains.list[cid]
+	if !ok {
+		chains.Unlock()
+		return fmt.Errorf("Chain %s doesn't exist on the peer", cid)
+	}
+	if err = c.configManager.Apply(configEnvelope); err != nil {
+		chains.Unlock()
+		return err
+	}
+	c.mspmgr = c.mspConfigHandler.GetMSPManager()
+	mspmgmt.SetManagerForChain(cid, c.mspmgr)
+	c.cb = block
+	anchorPeers := c.peerConfig.AnchorPeers()
+	chains.Unlock()
+
+	peerLogger.Infof("Applied the configuration of block %d to chain %s, configuration sequence %d", block.Header.Number, cid, c.configManager.Sequence())
+	return service.GetGossipService().UpdateChannel(cid, anchorPeers)
 }
 
 // CreateChainFromBlock creates a new chain from config block
@@ -214,6 +314,18 @@ func GetCommitter(cid string) committer.Committer {
 	return nil
 }
 
+// GetPolicyManager returns the policy manager holding the policies of the
+// configuration of the chain with chain ID. Note that this call returns nil
+// if chain cid has not been created, or has been created without a config block
+func GetPolicyManager(cid string) policies.Manager {
+	chains.RLock()
+	defer chains.RUnlock()
+	if c, ok := chains.list[cid]; ok {
+		return c.policyManager
+	}
+	return nil
+}
+
 // GetCurrConfigBlock returns the cached config block of the specified chain.
 // Note that this call returns nil if chain cid has not been created.
 func GetCurrConfigBlock(cid string) *common.Block {
@@ -225,16 +337,14 @@ func GetCurrConfigBlock(cid string) *common.Block {
 	return nil
 }
 
-// SetCurrConfigBlock sets the current config block of the specified chain
+// SetCurrConfigBlock sets the current config block of the specified chain.
+// Note that the configuration of the chain takes effect as the config blocks
+// of the chain are committed, rather than when this call sets the block
 func SetCurrConfigBlock(block *common.Block, cid string) error {
 	chains.Lock()
 	defer chains.Unlock()
 	if c, ok := chains.list[cid]; ok {
 		c.cb = block
-		// TODO: Change MSP configuration
-		// c.mspmgr.Reconfig(block)
-
-		// TODO: Change gossip configurations
 		return nil
 	}
 	return fmt.Errorf("Chain %s doesn't exist on the peer", cid)
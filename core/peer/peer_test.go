@@ -22,14 +22,25 @@ import (
 	"os"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
 
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/configtx"
 	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
 	ccp "github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/ledger/blkstorage"
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/hyperledger/fabric/core/ledger/testutil"
 	"github.com/hyperledger/fabric/core/mocks/ccprovider"
+	"github.com/hyperledger/fabric/core/peer/msp"
+	"github.com/hyperledger/fabric/core/peer/sharedconfig"
 	"github.com/hyperledger/fabric/gossip/service"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
 )
 
 func TestInitialize(t *testing.T) {
@@ -76,6 +87,20 @@ func TestCreateChainFromBlock(t *testing.T) {
 		t.Fatalf("got a bogus ledger")
 	}
 
+	// Correct policy manager, holding the policies of the config block
+	policyManager := GetPolicyManager(testChainID)
+	if policyManager == nil {
+		t.Fatalf("failed to get correct policy manager")
+	}
+	if _, ok := policyManager.GetPolicy(configtxtest.AcceptAllPolicyKey); !ok {
+		t.Fatalf("failed to get the policy %s of the config block", configtxtest.AcceptAllPolicyKey)
+	}
+
+	// Bad policy manager
+	if GetPolicyManager("BogusChain") != nil {
+		t.Fatalf("got a bogus policy manager")
+	}
+
 	// Correct block
 	block = GetCurrConfigBlock(testChainID)
 	if block == nil {
@@ -88,12 +113,184 @@ func TestCreateChainFromBlock(t *testing.T) {
 		t.Fatalf("got a bogus block")
 	}
 
+	// The configuration of the test genesis block is locked against modifications
+	configBlock := makeConfigBlock(t, GetCurrConfigBlock(testChainID), 1, makeAnchorPeersItem(testChainID, 1, nil))
+	if err = updateChainConfig(testChainID, configBlock); err == nil {
+		t.Fatalf("should have failed to apply a configuration violating the modification policy")
+	}
+	if GetCurrConfigBlock(testChainID) == configBlock {
+		t.Fatalf("should have kept the current config block")
+	}
+
 	// Chaos monkey test
 	Initialize(nil)
 
 	SetCurrConfigBlock(block, testChainID)
 }
 
+func TestUpdateChainConfig(t *testing.T) {
+	viper.Set("peer.fileSystemPath", "/var/hyperledger/test/")
+	defer os.RemoveAll("/var/hyperledger/test/")
+	ccp.RegisterChaincodeProviderFactory(&ccprovider.MockCcProviderFactory{})
+	MockInitialize()
+	testChainID := "myupdatedchainid"
+	genesisBlock, err := configtxtest.MakeGenesisBlock(testChainID)
+	if err != nil {
+		t.Fatalf("Failed to create a config block, err %s", err)
+	}
+
+	// Initialize gossip service, unless a previous test did
+	grpcServer := grpc.NewServer()
+	socket, err := net.Listen("tcp", fmt.Sprintf("%s:%d", "", 13612))
+	assert.NoError(t, err)
+	go grpcServer.Serve(socket)
+	defer grpcServer.Stop()
+	service.InitGossipService("localhost:13612", grpcServer)
+
+	// unlock the configuration of the chain
+	acceptAllPolicy := utils.MarshalOrPanic(utils.MakePolicyOrPanic(cauthdsl.AcceptAllPolicy))
+	block := makeConfigBlock(t, genesisBlock, 0, &common.ConfigurationItem{
+		Header:             &common.ChainHeader{ChainID: testChainID},
+		Type:               common.ConfigurationItem_Policy,
+		ModificationPolicy: configtx.DefaultModificationPolicyID,
+		Key:                configtx.DefaultModificationPolicyID,
+		Value:              acceptAllPolicy,
+	})
+	if err = CreateChainFromBlock(block); err != nil {
+		t.Fatalf("failed to create chain %s", err)
+	}
+
+	// The genesis block carries the configuration the chain was created from
+	if err = updateChainConfig(testChainID, block); err != nil {
+		t.Fatalf("failed to commit the genesis block %s", err)
+	}
+
+	// A committed config block updates the configuration of the chain
+	anchorPeers := []*pb.AnchorPeer{{Host: "peer0", Port: 7051, Cert: []byte("cert")}}
+	configBlock := makeConfigBlock(t, block, 1, makeAnchorPeersItem(testChainID, 1, anchorPeers))
+	if err = updateChainConfig(testChainID, configBlock); err != nil {
+		t.Fatalf("failed to update the configuration of the chain %s", err)
+	}
+	if GetCurrConfigBlock(testChainID) != configBlock {
+		t.Fatalf("failed to set the committed config block as the current config block")
+	}
+	chains.RLock()
+	c := chains.list[testChainID]
+	assert.Equal(t, anchorPeers, c.peerConfig.AnchorPeers())
+	assert.Equal(t, uint64(1), c.configManager.Sequence())
+	assert.Equal(t, c.mspmgr, mspmgmt.GetManagerForChain(testChainID), "Expected the MSP manager of the new configuration to be the MSP manager of the chain")
+	chains.RUnlock()
+
+	// The same configuration cannot be applied twice
+	if err = updateChainConfig(testChainID, configBlock); err == nil {
+		t.Fatalf("should have failed to apply a replayed configuration")
+	}
+
+	if err = updateChainConfig("BogusChain", configBlock); err == nil {
+		t.Fatalf("should have failed to update a bogus chain")
+	}
+}
+
+func TestInitializeFromSnapshot(t *testing.T) {
+	viper.Set("peer.fileSystemPath", "/var/hyperledger/test/")
+	defer os.RemoveAll("/var/hyperledger/test/")
+	snapshotDir := "/tmp/hyperledger/test/snapshot"
+	os.RemoveAll(snapshotDir)
+	defer os.RemoveAll(snapshotDir)
+	ccp.RegisterChaincodeProviderFactory(&ccprovider.MockCcProviderFactory{})
+	// Initialize ledger mgmt ahead of the mock, so that Initialize below
+	// loads the chains from the ledgers of the test environment
+	ledgermgmt.Initialize()
+	MockInitialize()
+	testChainID := "mysnapshotchainid"
+	genesisBlock, err := configtxtest.MakeGenesisBlock(testChainID)
+	if err != nil {
+		t.Fatalf("Failed to create a config block, err %s", err)
+	}
+
+	// Initialize gossip service, unless a previous test did
+	grpcServer := grpc.NewServer()
+	socket, err := net.Listen("tcp", fmt.Sprintf("%s:%d", "", 13613))
+	assert.NoError(t, err)
+	go grpcServer.Serve(socket)
+	defer grpcServer.Stop()
+	service.InitGossipService("localhost:13613", grpcServer)
+
+	if err = CreateChainFromBlock(genesisBlock); err != nil {
+		t.Fatalf("failed to create chain %s", err)
+	}
+	l := GetLedger(testChainID)
+	assert.NoError(t, l.Commit(genesisBlock))
+	bg := testutil.NewBlockGenerator(t)
+	assert.NoError(t, l.Commit(bg.NextBlock([][]byte{}, false)))
+	_, err = l.ExportSnapshot(snapshotDir)
+	assert.NoError(t, err)
+
+	// The config block precedes the snapshot, and is pruned from the ledger created from it
+	ledgermgmt.CleanupTestEnv()
+	MockInitialize()
+	l, err = ledgermgmt.CreateLedgerFromSnapshot(testChainID, snapshotDir)
+	assert.NoError(t, err)
+	_, err = l.GetBlockByNumber(0)
+	assert.Equal(t, blkstorage.ErrPruned, err)
+	l.Close()
+
+	// Restart
+	Initialize(func(string) error { return nil })
+	if GetLedger(testChainID) == nil {
+		t.Fatalf("failed to load the chain created from the snapshot")
+	}
+	if !proto.Equal(GetCurrConfigBlock(testChainID), genesisBlock) {
+		t.Fatalf("failed to load the config block of the chain created from the snapshot")
+	}
+	if GetPolicyManager(testChainID) == nil {
+		t.Fatalf("failed to load the policy manager of the chain created from the snapshot")
+	}
+}
+
+func makeAnchorPeersItem(chainID string, lastModified uint64, anchorPeers []*pb.AnchorPeer) *common.ConfigurationItem {
+	return &common.ConfigurationItem{
+		Header:             &common.ChainHeader{ChainID: chainID},
+		Type:               common.ConfigurationItem_Peer,
+		LastModified:       lastModified,
+		ModificationPolicy: configtx.DefaultModificationPolicyID,
+		Key:                sharedconfig.AnchorPeersKey,
+		Value:              utils.MarshalOrPanic(&pb.AnchorPeers{AnchorPees: anchorPeers}),
+	}
+}
+
+// makeConfigBlock returns a config block with the given number, whose configuration envelope holds
+// the configuration items of the given block, the given items replacing the items of the same key
+func makeConfigBlock(t *testing.T, block *common.Block, number uint64, items ...*common.ConfigurationItem) *common.Block {
+	envelope, err := utils.ExtractEnvelope(block, 0)
+	assert.NoError(t, err)
+	payload, err := utils.ExtractPayload(envelope)
+	assert.NoError(t, err)
+	configEnvelope, err := utils.GetConfigurationEnvelope(payload.Data)
+	assert.NoError(t, err)
+
+	for _, item := range items {
+		signedItem := &common.SignedConfigurationItem{ConfigurationItem: utils.MarshalOrPanic(item)}
+		replaced := false
+		for i, entry := range configEnvelope.Items {
+			existing := &common.ConfigurationItem{}
+			assert.NoError(t, proto.Unmarshal(entry.ConfigurationItem, existing))
+			if existing.Type == item.Type && existing.Key == item.Key {
+				configEnvelope.Items[i] = signedItem
+				replaced = true
+			}
+		}
+		if !replaced {
+			configEnvelope.Items = append(configEnvelope.Items, signedItem)
+		}
+	}
+	payload.Data = utils.MarshalOrPanic(configEnvelope)
+	configBlock := common.NewBlock(number, block.Header.Hash())
+	configBlock.Data.Data = [][]byte{utils.MarshalOrPanic(&common.Envelope{Payload: utils.MarshalOrPanic(payload)})}
+	configBlock.Header.DataHash = configBlock.Data.Hash()
+	return configBlock
+}
+
 func TestNewPeerClientConnection(t *testing.T) {
 	if _, err := NewPeerClientConnection(); err != nil {
 		t.Log(err)
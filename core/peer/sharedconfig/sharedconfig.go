@@ -0,0 +1,110 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharedconfig
+
+import (
+	"fmt"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/op/go-logging"
+)
+
+const (
+	// AnchorPeersKey is the cb.ConfigurationItem type key name for the AnchorPeers message
+	AnchorPeersKey = "AnchorPeers"
+)
+
+var logger = logging.MustGetLogger("core/peer/sharedconfig")
+
+// Descriptor stores the common shared peer configuration
+// It is intended to be the primary accessor of ManagerImpl
+// It is intended to discourage use of the other exported ManagerImpl methods
+// which are used for updating the peer configuration by the configtx.Manager
+type Descriptor interface {
+	// AnchorPeers returns the list of anchor peers of the chain
+	AnchorPeers() []*pb.AnchorPeer
+}
+
+type peerConfig struct {
+	anchorPeers []*pb.AnchorPeer
+}
+
+// ManagerImpl is an implementation of Descriptor and configtx.Handler
+// In general, it should only be referenced as an Impl for the configtx.Manager
+type ManagerImpl struct {
+	pendingConfig *peerConfig
+	config        *peerConfig
+}
+
+// NewManagerImpl creates a new ManagerImpl
+func NewManagerImpl() *ManagerImpl {
+	return &ManagerImpl{
+		config: &peerConfig{},
+	}
+}
+
+// AnchorPeers returns the list of anchor peers of the chain
+func (pm *ManagerImpl) AnchorPeers() []*pb.AnchorPeer {
+	return pm.config.anchorPeers
+}
+
+// BeginConfig is used to start a new configuration proposal
+func (pm *ManagerImpl) BeginConfig() {
+	if pm.pendingConfig != nil {
+		logger.Panicf("Programming error, cannot call begin in the middle of a proposal")
+	}
+	pm.pendingConfig = &peerConfig{}
+}
+
+// RollbackConfig is used to abandon a new configuration proposal
+func (pm *ManagerImpl) RollbackConfig() {
+	pm.pendingConfig = nil
+}
+
+// CommitConfig is used to commit a new configuration proposal
+func (pm *ManagerImpl) CommitConfig() {
+	if pm.pendingConfig == nil {
+		logger.Panicf("Programming error, cannot call commit without an existing proposal")
+	}
+	pm.config = pm.pendingConfig
+	pm.pendingConfig = nil
+}
+
+// ProposeConfig is used to add new configuration to the configuration proposal
+func (pm *ManagerImpl) ProposeConfig(configItem *cb.ConfigurationItem) error {
+	if configItem.Type != cb.ConfigurationItem_Peer {
+		return fmt.Errorf("Expected type of ConfigurationItem_Peer, got %v", configItem.Type)
+	}
+
+	switch configItem.Key {
+	case AnchorPeersKey:
+		anchorPeers := &pb.AnchorPeers{}
+		if err := proto.Unmarshal(configItem.Value, anchorPeers); err != nil {
+			return fmt.Errorf("Unmarshaling error for AnchorPeers: %s", err)
+		}
+		for _, anchorPeer := range anchorPeers.AnchorPees {
+			if anchorPeer.Host == "" || anchorPeer.Port <= 0 {
+				return fmt.Errorf("Attempted to set an anchor peer with an invalid endpoint %s:%d", anchorPeer.Host, anchorPeer.Port)
+			}
+		}
+		pm.pendingConfig.anchorPeers = anchorPeers.AnchorPees
+	}
+	return nil
+}
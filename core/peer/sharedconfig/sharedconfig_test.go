@@ -0,0 +1,83 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharedconfig
+
+import (
+	"testing"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+
+	logging "github.com/op/go-logging"
+)
+
+func init() {
+	logging.SetLevel(logging.DEBUG, "")
+}
+
+func makeAnchorPeersItem(anchorPeers ...*pb.AnchorPeer) *cb.ConfigurationItem {
+	return &cb.ConfigurationItem{
+		Type:  cb.ConfigurationItem_Peer,
+		Key:   AnchorPeersKey,
+		Value: utils.MarshalOrPanic(&pb.AnchorPeers{AnchorPees: anchorPeers}),
+	}
+}
+
+func TestRollback(t *testing.T) {
+	m := NewManagerImpl()
+	m.pendingConfig = &peerConfig{}
+	m.RollbackConfig()
+	if m.pendingConfig != nil {
+		t.Fatalf("Should have cleared pending config on rollback")
+	}
+}
+
+func TestAnchorPeers(t *testing.T) {
+	anchorPeer := &pb.AnchorPeer{Host: "peer0", Port: 7051, Cert: []byte("cert")}
+	m := NewManagerImpl()
+	m.BeginConfig()
+	if err := m.ProposeConfig(makeAnchorPeersItem(anchorPeer)); err != nil {
+		t.Fatalf("Error applying valid config: %s", err)
+	}
+	m.CommitConfig()
+	if len(m.AnchorPeers()) != 1 || m.AnchorPeers()[0].Host != anchorPeer.Host {
+		t.Fatalf("Expected the anchor peer %s, got %v", anchorPeer, m.AnchorPeers())
+	}
+
+	m.BeginConfig()
+	if err := m.ProposeConfig(&cb.ConfigurationItem{Type: cb.ConfigurationItem_Peer, Key: AnchorPeersKey, Value: []byte("Garbage Data")}); err == nil {
+		t.Fatalf("Should have failed on invalid message")
+	}
+	if err := m.ProposeConfig(makeAnchorPeersItem(&pb.AnchorPeer{Host: "peer1"})); err == nil {
+		t.Fatalf("Should have failed on an anchor peer without a port")
+	}
+	if err := m.ProposeConfig(&cb.ConfigurationItem{Type: cb.ConfigurationItem_Chain, Key: AnchorPeersKey}); err == nil {
+		t.Fatalf("Should have failed on a configuration item of another type")
+	}
+	m.RollbackConfig()
+	if len(m.AnchorPeers()) != 1 {
+		t.Fatalf("Should have kept the committed anchor peers on rollback")
+	}
+
+	// a configuration without anchor peers clears them
+	m.BeginConfig()
+	m.CommitConfig()
+	if len(m.AnchorPeers()) != 0 {
+		t.Fatalf("Expected no anchor peers, got %v", m.AnchorPeers())
+	}
+}
@@ -266,7 +266,7 @@ func validateProposalResponse(prBytes []byte, proposal *pb.Proposal, visibility
 	}
 
 	// validate the transaction
-	_, err = validation.ValidateTransaction(tx)
+	_, _, err = validation.ValidateTransaction(tx)
 	if err != nil {
 		return err
 	}
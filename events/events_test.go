@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/ptypes/timestamp"
+	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/events/consumer"
 	"github.com/hyperledger/fabric/events/producer"
 	"github.com/hyperledger/fabric/protos/common"
@@ -38,8 +39,9 @@ import (
 
 type Adapter struct {
 	sync.RWMutex
-	notfy chan struct{}
-	count int
+	notfy     chan struct{}
+	count     int
+	rejection *ehpb.Rejection
 }
 
 var peerAddress string
@@ -68,6 +70,11 @@ func (a *Adapter) Recv(msg *ehpb.Event) (bool, error) {
 	switch x := msg.Event.(type) {
 	case *ehpb.Event_Block, *ehpb.Event_ChaincodeEvent, *ehpb.Event_Register, *ehpb.Event_Unregister:
 		a.updateCountNotify()
+	case *ehpb.Event_Rejection:
+		a.Lock()
+		a.rejection = x.Rejection
+		a.Unlock()
+		a.updateCountNotify()
 	case nil:
 		// The field is not set.
 		return false, fmt.Errorf("event not set")
@@ -196,6 +203,47 @@ func TestReceiveAnyMessage(t *testing.T) {
 		}
 	}
 }
+
+func TestReceiveRejection(t *testing.T) {
+	adapter.count = 1
+	obcEHClient.RegisterAsync([]*ehpb.Interest{&ehpb.Interest{EventType: ehpb.EventType_REJECTION}})
+	select {
+	case <-adapter.notfy:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out on registration")
+	}
+
+	// only the transaction marked invalid is rejected, with its validation code
+	block := createTestBlock(t)
+	block.Data.Data = append(block.Data.Data, block.Data.Data[0])
+	txsFilter := ledgerUtil.NewTxValidationFlags(2)
+	txsFilter.SetFlag(1, ehpb.TxValidationCode_MVCC_READ_CONFLICT)
+	ledgerUtil.SetTxValidationFlags(block, txsFilter)
+	adapter.count = 1
+	if err := producer.SendProducerRejectionEvents(block); err != nil {
+		t.Fatalf("Error sending rejection events %s", err)
+	}
+	select {
+	case <-adapter.notfy:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out on rejection")
+	}
+	adapter.RLock()
+	rejection := adapter.rejection
+	adapter.RUnlock()
+	if rejection.ValidationCode != ehpb.TxValidationCode_MVCC_READ_CONFLICT || rejection.Tx == nil {
+		t.Fatalf("Unexpected rejection %v", rejection)
+	}
+
+	adapter.count = 1
+	obcEHClient.UnregisterAsync([]*ehpb.Interest{&ehpb.Interest{EventType: ehpb.EventType_REJECTION}})
+	select {
+	case <-adapter.notfy:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out on unregistration")
+	}
+}
+
 func TestReceiveCCWildcard(t *testing.T) {
 	var err error
 
@@ -19,6 +19,7 @@ package producer
 import (
 	"fmt"
 
+	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -101,6 +102,34 @@ func SendProducerBlockEvent(block *common.Block) error {
 	return Send(CreateBlockEvent(bevent))
 }
 
+// SendProducerRejectionEvents sends a rejection event, carrying the validation code, for
+// each transaction of the block that the committer marked invalid. The transaction of the
+// event is left out when it cannot be extracted from the block
+func SendProducerRejectionEvents(block *common.Block) error {
+	txsFilter := ledgerUtil.GetTxValidationFlags(block.Metadata)
+	for txIndex, d := range block.Data.Data {
+		if txsFilter.IsValid(txIndex) {
+			continue
+		}
+		code := txsFilter.Flag(txIndex)
+		var tx *pb.Transaction
+		txID := ""
+		if env, err := utils.GetEnvelopeFromBlock(d); err == nil && env != nil {
+			if payload, err := utils.GetPayload(env); err == nil && payload.Header != nil && payload.Header.ChainHeader != nil {
+				txID = payload.Header.ChainHeader.TxID
+				if common.HeaderType(payload.Header.ChainHeader.Type) == common.HeaderType_ENDORSER_TRANSACTION {
+					tx, _ = utils.GetTransaction(payload.Data)
+				}
+			}
+		}
+		errorMsg := fmt.Sprintf("Transaction %d [%s] of block %d is invalid: %s", txIndex, txID, block.Header.Number, code)
+		if err := Send(CreateRejectionEvent(tx, code, errorMsg)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 //CreateBlockEvent creates a Event from a Block
 func CreateBlockEvent(te *common.Block) *pb.Event {
 	return &pb.Event{Event: &pb.Event_Block{Block: te}}
@@ -112,6 +141,6 @@ func CreateChaincodeEvent(te *pb.ChaincodeEvent) *pb.Event {
 }
 
 //CreateRejectionEvent creates an Event from TxResults
-func CreateRejectionEvent(tx *pb.Transaction, errorMsg string) *pb.Event {
-	return &pb.Event{Event: &pb.Event_Rejection{Rejection: &pb.Rejection{Tx: tx, ErrorMsg: errorMsg}}}
+func CreateRejectionEvent(tx *pb.Transaction, validationCode pb.TxValidationCode, errorMsg string) *pb.Event {
+	return &pb.Event{Event: &pb.Event_Rejection{Rejection: &pb.Rejection{Tx: tx, ValidationCode: validationCode, ErrorMsg: errorMsg}}}
 }
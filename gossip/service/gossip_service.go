@@ -17,6 +17,7 @@ limitations under the License.
 package service
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/hyperledger/fabric/gossip/proto"
 	"github.com/hyperledger/fabric/gossip/state"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/op/go-logging"
 	"google.golang.org/grpc"
@@ -42,21 +44,38 @@ var (
 
 type gossipSvc gossip.Gossip
 
-// TODO: This is a temporary join channel struct, to be removed once
-// the structure of configuration block in terms of anchor peers will
-// be defined and coded. Currently need it to allow the end-to-end
-// skeleton to work, having gossip multi chain support.
+// joinChanMsg carries the anchor peers of the configuration of a chain.
+// TODO: The test organization stands in for the anchor peers of chains
+// whose configuration has none, to allow the end-to-end skeleton to work,
+// having gossip multi chain support.
 type joinChanMsg struct {
+	timestamp   time.Time
+	anchorPeers []api.AnchorPeer
+}
+
+func newJoinChanMsg(anchorPeers []*peer.AnchorPeer) *joinChanMsg {
+	msg := &joinChanMsg{timestamp: time.Now()}
+	for _, anchorPeer := range anchorPeers {
+		msg.anchorPeers = append(msg.anchorPeers, api.AnchorPeer{
+			Cert: api.PeerIdentityType(anchorPeer.Cert),
+			Host: anchorPeer.Host,
+			Port: int(anchorPeer.Port),
+		})
+	}
+	return msg
 }
 
 // GetTimestamp returns the timestamp of the message's creation
-func (*joinChanMsg) GetTimestamp() time.Time {
-	return time.Now()
+func (jcm *joinChanMsg) GetTimestamp() time.Time {
+	return jcm.timestamp
 }
 
 // AnchorPeers returns all the anchor peers that are in the channel
-func (*joinChanMsg) AnchorPeers() []api.AnchorPeer {
-	return []api.AnchorPeer{{Cert: api.PeerIdentityType(util.GetTestOrgID())}}
+func (jcm *joinChanMsg) AnchorPeers() []api.AnchorPeer {
+	if len(jcm.anchorPeers) == 0 {
+		return []api.AnchorPeer{{Cert: api.PeerIdentityType(util.GetTestOrgID())}}
+	}
+	return jcm.anchorPeers
 }
 
 // GossipService encapsulates gossip and state capabilities into single interface
@@ -65,6 +84,8 @@ type GossipService interface {
 
 	// JoinChannel joins new chain given the configuration block and initialized committer service
 	JoinChannel(committer committer.Committer, block *common.Block) error
+	// UpdateChannel reconfigures the chain with the anchor peers of its new configuration
+	UpdateChannel(chainID string, anchorPeers []*peer.AnchorPeer) error
 	// GetBlock returns block for given chain
 	GetBlock(chainID string, index uint64) *common.Block
 	// AddPayload appends message payload to for given chain
@@ -114,12 +135,25 @@ func (g *gossipServiceImpl) JoinChannel(commiter committer.Committer, block *com
 		// Initialize new state provider for given committer
 		logger.Debug("Creating state provider for chainID", chainID)
 		g.chains[chainID] = state.NewGossipStateProvider(chainID, g, commiter)
-		g.JoinChan(&joinChanMsg{}, gossipCommon.ChainID(chainID))
+		g.JoinChan(newJoinChanMsg(nil), gossipCommon.ChainID(chainID))
 	}
 
 	return nil
 }
 
+// UpdateChannel reconfigures the chain with the anchor peers of its new configuration
+func (g *gossipServiceImpl) UpdateChannel(chainID string, anchorPeers []*peer.AnchorPeer) error {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	if _, exists := g.chains[chainID]; !exists {
+		return fmt.Errorf("Chain %s has not been joined", chainID)
+	}
+	logger.Debug("Updating the anchor peers of chainID", chainID)
+	g.JoinChan(newJoinChanMsg(anchorPeers), gossipCommon.ChainID(chainID))
+	return nil
+}
+
 // GetBlock returns block for given chain
 func (g *gossipServiceImpl) GetBlock(chainID string, index uint64) *common.Block {
 	g.lock.RLock()
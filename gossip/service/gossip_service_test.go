@@ -22,6 +22,8 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/hyperledger/fabric/gossip/api"
+	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
 )
@@ -54,3 +56,13 @@ func TestInitGossipService(t *testing.T) {
 		}(gossip)
 	}
 }
+
+func TestJoinChanMsgAnchorPeers(t *testing.T) {
+	// A chain without anchor peers in its configuration falls back to the test organization
+	msg := newJoinChanMsg(nil)
+	assert.Len(t, msg.AnchorPeers(), 1)
+
+	msg = newJoinChanMsg([]*peer.AnchorPeer{{Host: "peer0", Port: 7051, Cert: []byte("cert")}})
+	assert.Equal(t, []api.AnchorPeer{{Cert: api.PeerIdentityType("cert"), Host: "peer0", Port: 7051}}, msg.AnchorPeers())
+	assert.False(t, newJoinChanMsg(nil).GetTimestamp().Before(msg.GetTimestamp()), "Expected the later message to be more recent")
+}
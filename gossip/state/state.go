@@ -234,7 +234,7 @@ func (s *GossipStateProviderImpl) handleStateRequest(msg comm.ReceivedMessage) {
 		response.Payloads = append(response.Payloads, &proto.Payload{
 			SeqNum: seqNum,
 			Data:   blockBytes,
-			Hash:   string(blocks[0].Header.Hash()),
+			Hash:   string(blocks[0].Header.HashWith(s.committer.HashingAlgorithm())),
 		})
 	}
 	// Sending back response with missing blocks
@@ -303,6 +303,10 @@ func (s *GossipStateProviderImpl) deliverPayloads() {
 						s.logger.Errorf("Error getting block with seqNum = %d due to (%s)...dropping block\n", payload.SeqNum, err)
 						continue
 					}
+					if payload.Hash != "" && payload.Hash != string(rawblock.Header.HashWith(s.committer.HashingAlgorithm())) {
+						s.logger.Errorf("Block with seqNum = %d does not match the hash of its payload...dropping block\n", payload.SeqNum)
+						continue
+					}
 					s.logger.Debug("New block with sequence number ", payload.SeqNum, " transactions num ", len(rawblock.Data.Data))
 					s.commitBlock(rawblock, payload.SeqNum)
 				}
@@ -163,7 +163,7 @@ func newGossipInstance(config *gossip.Config) gossip.Gossip {
 // Create new instance of KVLedger to be used for testing
 func newCommitter(id int) committer.Committer {
 	ledger, _ := ledgermgmt.CreateLedger(strconv.Itoa(id))
-	return committer.NewLedgerCommitter(ledger, &validator.MockValidator{})
+	return committer.NewLedgerCommitter(ledger, &validator.MockValidator{}, util.ComputeCryptoHash)
 }
 
 // Constructing pseudo peer node, simulating only gossip and state transfer part
@@ -285,8 +285,11 @@ func TestNewGossipStateProvider_SendingManyMessages(t *testing.T) {
 
 	msgCount := 10
 
+	previousHash := []byte{}
 	for i := 0; i < msgCount; i++ {
-		rawblock := pcomm.NewBlock(uint64(i), []byte{})
+		rawblock := pcomm.NewBlock(uint64(i), previousHash)
+		rawblock.Header.DataHash = rawblock.Data.Hash()
+		previousHash = rawblock.Header.Hash()
 		if bytes, err := pb.Marshal(rawblock); err == nil {
 			payload := &proto.Payload{uint64(i), "", bytes}
 			bootstrapSet[0].s.AddPayload(payload)
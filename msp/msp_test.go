@@ -4,6 +4,9 @@ import (
 	"os"
 	"reflect"
 	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/common"
 )
 
 var localMsp MSP
@@ -155,6 +158,31 @@ func TestSignAndVerify(t *testing.T) {
 	}
 }
 
+func TestSatisfiesPrincipalAdmin(t *testing.T) {
+	mspID, err := localMsp.GetIdentifier()
+	if err != nil {
+		t.Fatalf("GetIdentifier should have succeeded, got err %s", err)
+	}
+	adminRole, err := proto.Marshal(&common.MSPRole{Role: common.MSPRole_Admin, MSPIdentifier: mspID})
+	if err != nil {
+		t.Fatalf("Marshal should have succeeded, got err %s", err)
+	}
+	principal := &common.MSPPrincipal{PrincipalClassification: common.MSPPrincipal_ByMSPRole, Principal: adminRole}
+
+	admin := localMsp.(*bccspmsp).admins[0]
+	if err = localMsp.SatisfiesPrincipal(admin, principal); err != nil {
+		t.Fatalf("The admin identity should satisfy the admin principal, got err %s", err)
+	}
+
+	id, err := localMsp.GetDefaultSigningIdentity()
+	if err != nil {
+		t.Fatalf("GetDefaultSigningIdentity should have succeeded, got err %s", err)
+	}
+	if err = localMsp.SatisfiesPrincipal(id.GetPublicVersion(), principal); err == nil {
+		t.Fatalf("The signing identity is not an admin and should not satisfy the admin principal")
+	}
+}
+
 func TestMain(m *testing.M) {
 	retVal := m.Run()
 	os.Exit(retVal)
@@ -252,6 +252,23 @@ func (msp *bccspmsp) Validate(id Identity) error {
 	}
 }
 
+// isAdmin returns true if the supplied identity
+// holds one of the admin certificates of this MSP
+func (msp *bccspmsp) isAdmin(id Identity) bool {
+	candidate, ok := id.(*identity)
+	if !ok {
+		return false
+	}
+
+	for _, admin := range msp.admins {
+		if bytes.Equal(admin.(*identity).cert.Raw, candidate.cert.Raw) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // DeserializeIdentity returns an Identity
 // instance that was marshalled to the supplied byte array
 func (msp *bccspmsp) DeserializeIdentity(serializedID []byte) (Identity, error) {
@@ -327,8 +344,13 @@ func (msp *bccspmsp) SatisfiesPrincipal(id Identity, principal *common.MSPPrinci
 		// whether this identity is valid for the MSP
 		case common.MSPRole_Member:
 			return msp.Validate(id)
+		// in the case of admin, we check whether this
+		// identity is one of the admins of this MSP
 		case common.MSPRole_Admin:
-			panic("Not yet implemented")
+			if msp.isAdmin(id) {
+				return nil
+			}
+			return errors.New("The identity is not an admin of this MSP")
 		default:
 			return fmt.Errorf("Invalid MSP role type %d", int32(mspRole.Role))
 		}
@@ -18,6 +18,7 @@ package provisional
 
 import (
 	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/chainconfig"
 	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/orderer/common/sharedconfig"
 	cb "github.com/hyperledger/fabric/protos/common"
@@ -52,6 +53,15 @@ func (cbs *commonBootstrapper) encodeBatchTimeout() *cb.ConfigurationItem {
 	return utils.MakeConfigurationItem(configItemChainHeader, cb.ConfigurationItem_Orderer, lastModified, modPolicy, configItemKey, configItemValue)
 }
 
+func (cbs *commonBootstrapper) encodeHashingAlgorithm() *cb.ConfigurationItem {
+	configItemKey := chainconfig.HashingAlgorithmKey
+	configItemValue := utils.MarshalOrPanic(&cb.HashingAlgorithm{Name: cbs.hashingAlgorithm})
+	modPolicy := configtx.DefaultModificationPolicyID
+
+	configItemChainHeader := utils.MakeChainHeader(cb.HeaderType_CONFIGURATION_ITEM, msgVersion, cbs.chainID, epoch)
+	return utils.MakeConfigurationItem(configItemChainHeader, cb.ConfigurationItem_Chain, lastModified, modPolicy, configItemKey, configItemValue)
+}
+
 func (cbs *commonBootstrapper) encodeChainCreators() *cb.ConfigurationItem {
 	configItemKey := sharedconfig.ChainCreatorsKey
 	configItemValue := utils.MarshalOrPanic(&ab.ChainCreators{Policies: DefaultChainCreators})
@@ -106,3 +116,12 @@ func (kbs *kafkaBootstrapper) encodeKafkaBrokers() *cb.ConfigurationItem {
 	configItemChainHeader := utils.MakeChainHeader(cb.HeaderType_CONFIGURATION_ITEM, msgVersion, kbs.chainID, epoch)
 	return utils.MakeConfigurationItem(configItemChainHeader, cb.ConfigurationItem_Orderer, lastModified, modPolicy, configItemKey, configItemValue)
 }
+
+func (sbs *sbftBootstrapper) encodeSbftOptions() *cb.ConfigurationItem {
+	configItemKey := sharedconfig.SbftOptionsKey
+	configItemValue := utils.MarshalOrPanic(sbs.sbftOptions)
+	modPolicy := configtx.DefaultModificationPolicyID
+
+	configItemChainHeader := utils.MakeChainHeader(cb.HeaderType_CONFIGURATION_ITEM, msgVersion, sbs.chainID, epoch)
+	return utils.MakeConfigurationItem(configItemChainHeader, cb.ConfigurationItem_Orderer, lastModified, modPolicy, configItemKey, configItemValue)
+}
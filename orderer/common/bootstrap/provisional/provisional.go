@@ -23,6 +23,7 @@ import (
 	"github.com/hyperledger/fabric/common/genesis"
 	"github.com/hyperledger/fabric/orderer/common/bootstrap"
 	"github.com/hyperledger/fabric/orderer/localconfig"
+	"github.com/hyperledger/fabric/orderer/sbft/crypto"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 )
@@ -63,10 +64,11 @@ const (
 var DefaultChainCreators = []string{AcceptAllPolicyKey}
 
 type commonBootstrapper struct {
-	chainID       string
-	consensusType string
-	batchSize     *ab.BatchSize
-	batchTimeout  string
+	chainID          string
+	consensusType    string
+	batchSize        *ab.BatchSize
+	batchTimeout     string
+	hashingAlgorithm string
 }
 
 type soloBootstrapper struct {
@@ -78,6 +80,11 @@ type kafkaBootstrapper struct {
 	kafkaBrokers []string
 }
 
+type sbftBootstrapper struct {
+	commonBootstrapper
+	sbftOptions *ab.SbftOptions
+}
+
 // New returns a new provisional bootstrap helper.
 func New(conf *config.TopLevel) Generator {
 	cbs := &commonBootstrapper{
@@ -88,14 +95,20 @@ func New(conf *config.TopLevel) Generator {
 			AbsoluteMaxBytes:  conf.Genesis.BatchSize.AbsoluteMaxBytes,
 			PreferredMaxBytes: conf.Genesis.BatchSize.PreferredMaxBytes,
 		},
-		batchTimeout: conf.Genesis.BatchTimeout.String(),
+		batchTimeout:     conf.Genesis.BatchTimeout.String(),
+		hashingAlgorithm: conf.Genesis.HashingAlgorithm,
 	}
 
 	switch conf.Genesis.OrdererType {
-	case ConsensusTypeSolo, ConsensusTypeSbft:
+	case ConsensusTypeSolo:
 		return &soloBootstrapper{
 			commonBootstrapper: *cbs,
 		}
+	case ConsensusTypeSbft:
+		return &sbftBootstrapper{
+			commonBootstrapper: *cbs,
+			sbftOptions:        makeSbftOptions(conf.Genesis.SbftShared),
+		}
 	case ConsensusTypeKafka:
 		return &kafkaBootstrapper{
 			commonBootstrapper: *cbs,
@@ -129,3 +142,25 @@ func (cbs *commonBootstrapper) GenesisBlock() *cb.Block {
 func (kbs *kafkaBootstrapper) GenesisBlock() *cb.Block {
 	return kbs.genesisBlock(kbs.TemplateItems)
 }
+
+// GenesisBlock returns the genesis block to be used for bootstrapping.
+func (sbs *sbftBootstrapper) GenesisBlock() *cb.Block {
+	return sbs.genesisBlock(sbs.TemplateItems)
+}
+
+func makeSbftOptions(conf config.SbftShared) *ab.SbftOptions {
+	peers := make(map[string][]byte)
+	for _, peer := range conf.Peers {
+		cert, err := crypto.ParseCertPEM(peer.Certificate)
+		if err != nil {
+			panic(fmt.Errorf("Error reading certificate of SBFT peer %s: %s", peer.Address, err))
+		}
+		peers[peer.Address] = cert
+	}
+	return &ab.SbftOptions{
+		N:                  conf.N,
+		F:                  conf.F,
+		RequestTimeoutNsec: uint64(conf.RequestTimeout),
+		Peers:              peers,
+	}
+}
@@ -20,18 +20,25 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/hyperledger/fabric/orderer/common/sharedconfig"
 	"github.com/hyperledger/fabric/orderer/localconfig"
 	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+
+	"github.com/golang/protobuf/proto"
 )
 
-var confSolo, confKafka *config.TopLevel
+var confSolo, confKafka, confSbft *config.TopLevel
 var testCases []*config.TopLevel
 
 func init() {
 	confSolo = config.Load()
 	confKafka = config.Load()
 	confKafka.Genesis.OrdererType = ConsensusTypeKafka
-	testCases = []*config.TopLevel{confSolo, confKafka}
+	confSbft = config.Load()
+	confSbft.Genesis.OrdererType = ConsensusTypeSbft
+	confSbft.Genesis.SbftShared.Peers = []config.SbftPeer{{Address: "127.0.0.1:6101", Certificate: "../../../sbft/testdata/cert1.pem"}}
+	testCases = []*config.TopLevel{confSolo, confKafka, confSbft}
 }
 
 func TestGenesisBlockHeader(t *testing.T) {
@@ -60,3 +67,24 @@ func TestGenesisMetadata(t *testing.T) {
 		}
 	}
 }
+
+func TestSbftOptions(t *testing.T) {
+	for _, item := range New(confSbft).TemplateItems() {
+		if item.Key != sharedconfig.SbftOptionsKey {
+			continue
+		}
+		sbftOptions := &ab.SbftOptions{}
+		if err := proto.Unmarshal(item.Value, sbftOptions); err != nil {
+			t.Fatalf("Could not unmarshal SBFT options: %s", err)
+		}
+		if sbftOptions.N != confSbft.Genesis.SbftShared.N || sbftOptions.F != confSbft.Genesis.SbftShared.F {
+			t.Fatalf("Expected N=%d and F=%d, got N=%d and F=%d", confSbft.Genesis.SbftShared.N, confSbft.Genesis.SbftShared.F, sbftOptions.N, sbftOptions.F)
+		}
+		address := confSbft.Genesis.SbftShared.Peers[0].Address
+		if len(sbftOptions.Peers) != 1 || sbftOptions.Peers[address] == nil {
+			t.Fatalf("Expected the certificate of peer %s, got %v", address, sbftOptions.Peers)
+		}
+		return
+	}
+	t.Fatalf("Should have included the SBFT options")
+}
@@ -29,6 +29,7 @@ func (cbs *commonBootstrapper) TemplateItems() []*cb.ConfigurationItem {
 		cbs.encodeConsensusType(),
 		cbs.encodeBatchSize(),
 		cbs.encodeBatchTimeout(),
+		cbs.encodeHashingAlgorithm(),
 		cbs.encodeAcceptAllPolicy(),
 		cbs.encodeIngressPolicy(),
 		cbs.encodeEgressPolicy(),
@@ -39,3 +40,7 @@ func (cbs *commonBootstrapper) TemplateItems() []*cb.ConfigurationItem {
 func (kbs *kafkaBootstrapper) TemplateItems() []*cb.ConfigurationItem {
 	return append(kbs.commonBootstrapper.TemplateItems(), kbs.encodeKafkaBrokers())
 }
+
+func (sbs *sbftBootstrapper) TemplateItems() []*cb.ConfigurationItem {
+	return append(sbs.commonBootstrapper.TemplateItems(), sbs.encodeSbftOptions())
+}
@@ -26,6 +26,7 @@ import (
 	ordererledger "github.com/hyperledger/fabric/orderer/ledger"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/op/go-logging"
 
 	"github.com/golang/protobuf/proto"
@@ -106,6 +107,16 @@ func (ds *deliverServer) Handle(srv ab.AtomicBroadcast_DeliverServer) error {
 
 		logger.Debugf("Received seekInfo %v", seekInfo)
 
+		if _, ok := ab.SeekInfo_SeekContentType_name[int32(seekInfo.ContentType)]; !ok {
+			logger.Errorf("Received a deliver request with unknown content type %d", seekInfo.ContentType)
+			return sendStatusReply(srv, cb.Status_BAD_REQUEST)
+		}
+
+		if seekInfo.ContentType == ab.SeekInfo_CHAINCODE_TXS && seekInfo.ChaincodeName == "" {
+			logger.Errorf("Received a deliver request for the transactions of a chaincode without a chaincode name")
+			return sendStatusReply(srv, cb.Status_BAD_REQUEST)
+		}
+
 		cursor, number := chain.Reader().Iterator(seekInfo.Start)
 		var stopNum uint64
 		switch stop := seekInfo.Stop.Type.(type) {
@@ -135,7 +146,7 @@ func (ds *deliverServer) Handle(srv ab.AtomicBroadcast_DeliverServer) error {
 			}
 
 			logger.Debugf("Delivering block")
-			if err := sendBlockReply(srv, block); err != nil {
+			if err := sendContentReply(srv, block, seekInfo); err != nil {
 				return err
 			}
 
@@ -163,3 +174,80 @@ func sendBlockReply(srv ab.AtomicBroadcast_DeliverServer, block *cb.Block) error
 		Type: &ab.DeliverResponse_Block{Block: block},
 	})
 }
+
+func sendFilteredBlockReply(srv ab.AtomicBroadcast_DeliverServer, filteredBlock *ab.FilteredBlock) error {
+	return srv.Send(&ab.DeliverResponse{
+		Type: &ab.DeliverResponse_FilteredBlock{FilteredBlock: filteredBlock},
+	})
+}
+
+// sendContentReply sends the parts of the block which were requested by the content type of the seekInfo
+func sendContentReply(srv ab.AtomicBroadcast_DeliverServer, block *cb.Block, seekInfo *ab.SeekInfo) error {
+	switch seekInfo.ContentType {
+	case ab.SeekInfo_HEADER_WITH_METADATA:
+		return sendFilteredBlockReply(srv, &ab.FilteredBlock{Header: block.Header, Metadata: block.Metadata})
+	case ab.SeekInfo_TX_IDS:
+		return sendFilteredBlockReply(srv, &ab.FilteredBlock{Header: block.Header, TxIds: txIDs(block)})
+	case ab.SeekInfo_CHAINCODE_TXS:
+		data, indexes := chaincodeTxs(block, seekInfo.ChaincodeName)
+		return sendFilteredBlockReply(srv, &ab.FilteredBlock{Header: block.Header, Metadata: block.Metadata, Data: data, TxIndexes: indexes})
+	default:
+		return sendBlockReply(srv, block)
+	}
+}
+
+// txIDs returns the IDs of the transactions in the block, the ID of a transaction
+// which cannot be decoded is left empty so that the IDs line up with the block data
+func txIDs(block *cb.Block) []string {
+	if block.Data == nil {
+		return nil
+	}
+	ids := make([]string, len(block.Data.Data))
+	for i, data := range block.Data.Data {
+		if chainHeader := txChainHeader(block, i, data); chainHeader != nil {
+			ids[i] = chainHeader.TxID
+		}
+	}
+	return ids
+}
+
+// chaincodeTxs returns the transactions in the block which invoke the given chaincode, along
+// with their indexes in the block data, skipping the transactions which cannot be decoded
+func chaincodeTxs(block *cb.Block, chaincodeName string) (*cb.BlockData, []uint32) {
+	data := &cb.BlockData{}
+	var indexes []uint32
+	if block.Data == nil {
+		return data, indexes
+	}
+	for i, tx := range block.Data.Data {
+		chainHeader := txChainHeader(block, i, tx)
+		if chainHeader == nil || chainHeader.Type != int32(cb.HeaderType_ENDORSER_TRANSACTION) {
+			continue
+		}
+		extension := &pb.ChaincodeHeaderExtension{}
+		if err := proto.Unmarshal(chainHeader.Extension, extension); err != nil || extension.ChaincodeID == nil {
+			logger.Warningf("Transaction %d of block %d has no valid chaincode header extension", i, block.Header.Number)
+			continue
+		}
+		if extension.ChaincodeID.Name == chaincodeName {
+			data.Data = append(data.Data, tx)
+			indexes = append(indexes, uint32(i))
+		}
+	}
+	return data, indexes
+}
+
+// txChainHeader returns the chain header of the given transaction of the block, or nil if it cannot be decoded
+func txChainHeader(block *cb.Block, i int, data []byte) *cb.ChainHeader {
+	envelope := &cb.Envelope{}
+	if err := proto.Unmarshal(data, envelope); err != nil {
+		logger.Warningf("Transaction %d of block %d is not an envelope: %s", i, block.Header.Number, err)
+		return nil
+	}
+	payload := &cb.Payload{}
+	if err := proto.Unmarshal(envelope.Payload, payload); err != nil || payload.Header == nil || payload.Header.ChainHeader == nil {
+		logger.Warningf("Transaction %d of block %d has no valid header", i, block.Header.Number)
+		return nil
+	}
+	return payload.Header.ChainHeader
+}
@@ -18,6 +18,9 @@ package deliver
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
 	"testing"
 	"time"
 
@@ -25,12 +28,14 @@ import (
 	"github.com/hyperledger/fabric/orderer/common/bootstrap/provisional"
 	"github.com/hyperledger/fabric/orderer/common/sharedconfig"
 	ordererledger "github.com/hyperledger/fabric/orderer/ledger"
+	fileledger "github.com/hyperledger/fabric/orderer/ledger/file"
 	ramledger "github.com/hyperledger/fabric/orderer/ledger/ram"
 	"github.com/hyperledger/fabric/orderer/localconfig"
 	mockpolicies "github.com/hyperledger/fabric/orderer/mocks/policies"
 	mocksharedconfig "github.com/hyperledger/fabric/orderer/mocks/sharedconfig"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 	logging "github.com/op/go-logging"
 	"google.golang.org/grpc"
@@ -96,6 +101,18 @@ func (mcs *mockSupport) Reader() ordererledger.Reader {
 	return mcs.ledger
 }
 
+// NewFileLedger creates a file ledger in a new temporary directory, which the returned function removes
+func NewFileLedger() (ordererledger.ReadWriter, func()) {
+	location, err := ioutil.TempDir("", "hyperledger")
+	if err != nil {
+		panic(err)
+	}
+	flf := fileledger.New(location)
+	fl, _ := flf.GetOrCreate(provisional.TestChainID)
+	fl.Append(genesisBlock)
+	return fl, func() { os.RemoveAll(location) }
+}
+
 func NewRAMLedger() ordererledger.ReadWriter {
 	rlf := ramledger.New(ledgerSize + 1)
 	rl, _ := rlf.GetOrCreate(provisional.TestChainID)
@@ -386,3 +403,189 @@ func TestBlockingSeek(t *testing.T) {
 		t.Fatalf("Timed out waiting to get all blocks")
 	}
 }
+
+func makeTx(txID string) *cb.Envelope {
+	return &cb.Envelope{
+		Payload: utils.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChainHeader: &cb.ChainHeader{TxID: txID},
+			},
+		}),
+	}
+}
+
+func makeChaincodeTx(txID string, chaincodeName string) *cb.Envelope {
+	return &cb.Envelope{
+		Payload: utils.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChainHeader: &cb.ChainHeader{
+					Type:      int32(cb.HeaderType_ENDORSER_TRANSACTION),
+					TxID:      txID,
+					Extension: utils.MarshalOrPanic(&pb.ChaincodeHeaderExtension{ChaincodeID: &pb.ChaincodeID{Name: chaincodeName}}),
+				},
+			},
+		}),
+	}
+}
+
+// chaincodeOfBlock returns the chaincode which the chaincode transaction of the test block with the given number invokes
+func chaincodeOfBlock(number uint64) string {
+	if number%2 == 0 {
+		return "othercc"
+	}
+	return "mycc"
+}
+
+func TestSeekContentTypes(t *testing.T) {
+	fl, cleanup := NewFileLedger()
+	defer cleanup()
+
+	for name, ledger := range map[string]ordererledger.ReadWriter{"ram": NewRAMLedger(), "file": fl} {
+		for i := 1; i < ledgerSize; i++ {
+			ledger.Append(ordererledger.CreateNextBlock(ledger, []*cb.Envelope{
+				makeTx(fmt.Sprintf("tx%d", i)),
+				{Payload: []byte("garbage")},
+				makeChaincodeTx(fmt.Sprintf("cctx%d", i), chaincodeOfBlock(uint64(i))),
+			}))
+		}
+
+		mm := newMockMultichainManager()
+		mm.chains[systemChainID].ledger = ledger
+
+		for _, contentType := range []ab.SeekInfo_SeekContentType{ab.SeekInfo_FULL_BLOCK, ab.SeekInfo_HEADER_WITH_METADATA, ab.SeekInfo_TX_IDS, ab.SeekInfo_CHAINCODE_TXS} {
+			t.Run(fmt.Sprintf("%s/%s", name, contentType), func(t *testing.T) {
+				m := newMockD()
+				defer close(m.recvChan)
+				ds := NewHandlerImpl(mm)
+
+				go ds.Handle(m)
+
+				m.recvChan <- makeSeek(systemChainID, &ab.SeekInfo{Start: seekSpecified(1), Stop: seekNewest, Behavior: ab.SeekInfo_BLOCK_UNTIL_READY, ContentType: contentType, ChaincodeName: "mycc"})
+
+				for number := uint64(1); number < ledgerSize; number++ {
+					select {
+					case deliverReply := <-m.sendChan:
+						checkContent(t, deliverReply, ordererledger.GetBlock(ledger, number), contentType)
+					case <-time.After(time.Second):
+						t.Fatalf("Timed out waiting to get block %d", number)
+					}
+				}
+
+				select {
+				case deliverReply := <-m.sendChan:
+					if deliverReply.GetStatus() != cb.Status_SUCCESS {
+						t.Fatalf("Expected delivery to complete")
+					}
+				case <-time.After(time.Second):
+					t.Fatalf("Timed out waiting to get all blocks")
+				}
+			})
+		}
+	}
+}
+
+// checkContent verifies that the reply holds the parts of the block which the content type requests
+func checkContent(t *testing.T, deliverReply *ab.DeliverResponse, block *cb.Block, contentType ab.SeekInfo_SeekContentType) {
+	if contentType == ab.SeekInfo_FULL_BLOCK {
+		if !reflect.DeepEqual(deliverReply.GetBlock(), block) {
+			t.Fatalf("Expected block %d but got %v", block.Header.Number, deliverReply)
+		}
+		return
+	}
+
+	filteredBlock := deliverReply.GetFilteredBlock()
+	if filteredBlock == nil {
+		t.Fatalf("Expected a filtered block but got %v", deliverReply)
+	}
+	if !reflect.DeepEqual(filteredBlock.Header, block.Header) {
+		t.Fatalf("Expected the header of block %d but got %v", block.Header.Number, filteredBlock.Header)
+	}
+
+	switch contentType {
+	case ab.SeekInfo_HEADER_WITH_METADATA:
+		if !reflect.DeepEqual(filteredBlock.Metadata, block.Metadata) || filteredBlock.TxIds != nil || filteredBlock.Data != nil {
+			t.Fatalf("Expected only the metadata of block %d but got %v", block.Header.Number, filteredBlock)
+		}
+	case ab.SeekInfo_TX_IDS:
+		expected := []string{fmt.Sprintf("tx%d", block.Header.Number), "", fmt.Sprintf("cctx%d", block.Header.Number)}
+		if !reflect.DeepEqual(filteredBlock.TxIds, expected) || filteredBlock.Metadata != nil || filteredBlock.Data != nil {
+			t.Fatalf("Expected only the transaction IDs %v of block %d but got %v", expected, block.Header.Number, filteredBlock)
+		}
+	case ab.SeekInfo_CHAINCODE_TXS:
+		// only the chaincode transaction of the odd blocks invokes the requested chaincode
+		expectedData := &cb.BlockData{}
+		var expectedIndexes []uint32
+		if chaincodeOfBlock(block.Header.Number) == "mycc" {
+			expectedData.Data = [][]byte{block.Data.Data[2]}
+			expectedIndexes = []uint32{2}
+		}
+		if !reflect.DeepEqual(filteredBlock.Metadata, block.Metadata) || filteredBlock.TxIds != nil ||
+			!reflect.DeepEqual(filteredBlock.Data, expectedData) || !reflect.DeepEqual(filteredBlock.TxIndexes, expectedIndexes) {
+			t.Fatalf("Expected the metadata and the transactions %v of block %d but got %v", expectedIndexes, block.Header.Number, filteredBlock)
+		}
+	}
+}
+
+func TestUnknownContentTypeSeek(t *testing.T) {
+	mm := newMockMultichainManager()
+
+	m := newMockD()
+	defer close(m.recvChan)
+	ds := NewHandlerImpl(mm)
+
+	go ds.Handle(m)
+
+	m.recvChan <- makeSeek(systemChainID, &ab.SeekInfo{Start: seekOldest, Stop: seekNewest, Behavior: ab.SeekInfo_BLOCK_UNTIL_READY, ContentType: ab.SeekInfo_SeekContentType(42)})
+
+	select {
+	case deliverReply := <-m.sendChan:
+		if deliverReply.GetStatus() != cb.Status_BAD_REQUEST {
+			t.Fatalf("Received wrong error on the reply channel")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for the reply")
+	}
+}
+
+func TestChaincodeTxsSeekWithoutChaincode(t *testing.T) {
+	mm := newMockMultichainManager()
+
+	m := newMockD()
+	defer close(m.recvChan)
+	ds := NewHandlerImpl(mm)
+
+	go ds.Handle(m)
+
+	m.recvChan <- makeSeek(systemChainID, &ab.SeekInfo{Start: seekOldest, Stop: seekNewest, Behavior: ab.SeekInfo_BLOCK_UNTIL_READY, ContentType: ab.SeekInfo_CHAINCODE_TXS})
+
+	select {
+	case deliverReply := <-m.sendChan:
+		if deliverReply.GetStatus() != cb.Status_BAD_REQUEST {
+			t.Fatalf("Received wrong error on the reply channel")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for the reply")
+	}
+}
+
+func TestUnauthorizedFilteredSeek(t *testing.T) {
+	mm := newMockMultichainManager()
+	mm.chains[systemChainID].policyManager.Policy.Err = fmt.Errorf("Fail to evaluate policy")
+
+	m := newMockD()
+	defer close(m.recvChan)
+	ds := NewHandlerImpl(mm)
+
+	go ds.Handle(m)
+
+	m.recvChan <- makeSeek(systemChainID, &ab.SeekInfo{Start: seekOldest, Stop: seekOldest, Behavior: ab.SeekInfo_BLOCK_UNTIL_READY, ContentType: ab.SeekInfo_HEADER_WITH_METADATA})
+
+	select {
+	case deliverReply := <-m.sendChan:
+		if deliverReply.GetStatus() != cb.Status_FORBIDDEN {
+			t.Fatalf("Received wrong error on the reply channel")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for the reply")
+	}
+}
@@ -51,6 +51,9 @@ const (
 
 	// EgressPolicyKey is the cb.ConfigurationItem type key name for the EgressPolicy message
 	EgressPolicyKey = "EgressPolicy"
+
+	// SbftOptionsKey is the cb.ConfigurationItem type key name for the SbftOptions message
+	SbftOptionsKey = "SbftOptions"
 )
 
 var logger = logging.MustGetLogger("orderer/common/sharedconfig")
@@ -83,6 +86,10 @@ type Manager interface {
 
 	// EgressPolicy returns the name of the policy to validate incoming broadcast messages against
 	EgressPolicy() string
+
+	// SbftOptions returns the SBFT protocol parameters and the replica set of the chain
+	// This field is only set for chains ordered by SBFT
+	SbftOptions() *ab.SbftOptions
 }
 
 type ordererConfig struct {
@@ -93,6 +100,7 @@ type ordererConfig struct {
 	kafkaBrokers  []string
 	ingressPolicy string
 	egressPolicy  string
+	sbftOptions   *ab.SbftOptions
 }
 
 // ManagerImpl is an implementation of Manager and configtx.ConfigHandler
@@ -147,6 +155,12 @@ func (pm *ManagerImpl) EgressPolicy() string {
 	return pm.config.egressPolicy
 }
 
+// SbftOptions returns the SBFT protocol parameters and the replica set of the chain
+// This field is only set for chains ordered by SBFT
+func (pm *ManagerImpl) SbftOptions() *ab.SbftOptions {
+	return pm.config.sbftOptions
+}
+
 // BeginConfig is used to start a new configuration proposal
 func (pm *ManagerImpl) BeginConfig() {
 	if pm.pendingConfig != nil {
@@ -253,6 +267,21 @@ func (pm *ManagerImpl) ProposeConfig(configItem *cb.ConfigurationItem) error {
 			}
 		}
 		pm.pendingConfig.kafkaBrokers = kafkaBrokers.Brokers
+	case SbftOptionsKey:
+		sbftOptions := &ab.SbftOptions{}
+		if err := proto.Unmarshal(configItem.Value, sbftOptions); err != nil {
+			return fmt.Errorf("Unmarshaling error for SbftOptions: %s", err)
+		}
+		if sbftOptions.N == 0 {
+			return fmt.Errorf("Attempted to set the SBFT replica count to an invalid value: 0")
+		}
+		if sbftOptions.F*3+1 > sbftOptions.N {
+			return fmt.Errorf("Attempted to set an invalid combination of SBFT replica count (%d) and tolerated faults (%d)", sbftOptions.N, sbftOptions.F)
+		}
+		if uint64(len(sbftOptions.Peers)) != sbftOptions.N {
+			return fmt.Errorf("Attempted to set %d SBFT peers for a replica count of %d", len(sbftOptions.Peers), sbftOptions.N)
+		}
+		pm.pendingConfig.sbftOptions = sbftOptions
 	}
 	return nil
 }
@@ -329,6 +329,78 @@ func TestKafkaBrokers(t *testing.T) {
 	}
 }
 
+func TestSbftOptions(t *testing.T) {
+	endOptions := &ab.SbftOptions{
+		N:                  4,
+		F:                  1,
+		RequestTimeoutNsec: uint64(time.Second),
+		Peers: map[string][]byte{
+			"127.0.0.1:6101": []byte("cert1"),
+			"127.0.0.1:6102": []byte("cert2"),
+			"127.0.0.1:6103": []byte("cert3"),
+			"127.0.0.1:6104": []byte("cert4"),
+		},
+	}
+
+	invalidMessage := &cb.ConfigurationItem{
+		Type:  cb.ConfigurationItem_Orderer,
+		Key:   SbftOptionsKey,
+		Value: []byte("Garbage Data"),
+	}
+	zeroReplicas := &cb.ConfigurationItem{
+		Type:  cb.ConfigurationItem_Orderer,
+		Key:   SbftOptionsKey,
+		Value: utils.MarshalOrPanic(&ab.SbftOptions{}),
+	}
+	tooManyFaults := &cb.ConfigurationItem{
+		Type:  cb.ConfigurationItem_Orderer,
+		Key:   SbftOptionsKey,
+		Value: utils.MarshalOrPanic(&ab.SbftOptions{N: 4, F: 2, Peers: endOptions.Peers}),
+	}
+	peerCountMismatch := &cb.ConfigurationItem{
+		Type:  cb.ConfigurationItem_Orderer,
+		Key:   SbftOptionsKey,
+		Value: utils.MarshalOrPanic(&ab.SbftOptions{N: 5, F: 1, Peers: endOptions.Peers}),
+	}
+	validMessage := &cb.ConfigurationItem{
+		Type:  cb.ConfigurationItem_Orderer,
+		Key:   SbftOptionsKey,
+		Value: utils.MarshalOrPanic(endOptions),
+	}
+
+	m := NewManagerImpl()
+	m.BeginConfig()
+
+	err := m.ProposeConfig(validMessage)
+	if err != nil {
+		t.Fatalf("Error applying valid config: %s", err)
+	}
+
+	err = m.ProposeConfig(invalidMessage)
+	if err == nil {
+		t.Fatalf("Should have failed on invalid message")
+	}
+
+	err = m.ProposeConfig(zeroReplicas)
+	if err == nil {
+		t.Fatalf("Should have rejected a replica count of zero")
+	}
+
+	err = m.ProposeConfig(tooManyFaults)
+	if err == nil {
+		t.Fatalf("Should have rejected tolerating more faults than the replica count allows")
+	}
+
+	err = m.ProposeConfig(peerCountMismatch)
+	if err == nil {
+		t.Fatalf("Should have rejected a peer set which does not match the replica count")
+	}
+
+	m.CommitConfig()
+
+	assert.Equal(t, endOptions, m.SbftOptions(), "Did not get back the committed SBFT options")
+}
+
 func TestIngressPolicy(t *testing.T) {
 	endPolicy := "foo"
 	invalidMessage :=
@@ -18,24 +18,26 @@ package sizefilter
 
 import (
 	"github.com/hyperledger/fabric/orderer/common/filter"
+	"github.com/hyperledger/fabric/orderer/common/sharedconfig"
 	ab "github.com/hyperledger/fabric/protos/common"
 	logging "github.com/op/go-logging"
 )
 
 var logger = logging.MustGetLogger("orderer/common/sizefilter")
 
-// MaxBytesRule rejects messages larger than maxBytes
-func MaxBytesRule(maxBytes uint32) filter.Rule {
-	return &maxBytesRule{maxBytes: maxBytes}
+// MaxBytesRule rejects messages larger than the AbsoluteMaxBytes of the current BatchSize
+func MaxBytesRule(sharedConfigManager sharedconfig.Manager) filter.Rule {
+	return &maxBytesRule{sharedConfigManager: sharedConfigManager}
 }
 
 type maxBytesRule struct {
-	maxBytes uint32
+	sharedConfigManager sharedconfig.Manager
 }
 
 func (r *maxBytesRule) Apply(message *ab.Envelope) (filter.Action, filter.Committer) {
-	if size := messageByteSize(message); size > r.maxBytes {
-		logger.Warningf("%d byte message payload exceeds maximum allowed %d bytes", size, r.maxBytes)
+	maxBytes := r.sharedConfigManager.BatchSize().AbsoluteMaxBytes
+	if size := messageByteSize(message); size > maxBytes {
+		logger.Warningf("%d byte message payload exceeds maximum allowed %d bytes", size, maxBytes)
 		return filter.Reject, nil
 	}
 	return filter.Forward, nil
@@ -21,13 +21,16 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/orderer/common/filter"
+	mocksharedconfig "github.com/hyperledger/fabric/orderer/mocks/sharedconfig"
 	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
 )
 
 func TestMaxBytesRule(t *testing.T) {
 	dataSize := uint32(100)
 	maxBytes := calcMessageBytesForPayloadDataSize(dataSize)
-	rs := filter.NewRuleSet([]filter.Rule{MaxBytesRule(maxBytes), filter.AcceptRule})
+	sharedConfig := &mocksharedconfig.Manager{BatchSizeVal: &ab.BatchSize{AbsoluteMaxBytes: maxBytes}}
+	rs := filter.NewRuleSet([]filter.Rule{MaxBytesRule(sharedConfig), filter.AcceptRule})
 
 	t.Run("LessThan", func(t *testing.T) {
 		_, err := rs.Apply(makeMessage(make([]byte, dataSize-1)))
@@ -47,6 +50,13 @@ func TestMaxBytesRule(t *testing.T) {
 			t.Fatalf("Should have rejected")
 		}
 	})
+	t.Run("ConfigChange", func(t *testing.T) {
+		sharedConfig.BatchSizeVal = &ab.BatchSize{AbsoluteMaxBytes: maxBytes + 1}
+		_, err := rs.Apply(makeMessage(make([]byte, dataSize+1)))
+		if err != nil {
+			t.Fatalf("Should have accepted after the maximum was raised")
+		}
+	})
 }
 
 func calcMessageBytesForPayloadDataSize(dataSize uint32) uint32 {
@@ -63,6 +63,7 @@ var testConf = &config.TopLevel{
 			AbsoluteMaxBytes:  10 * 1024 * 1024,
 			PreferredMaxBytes: 512 * 1024,
 		},
+		HashingAlgorithm: "SHAKE256",
 	},
 }
 
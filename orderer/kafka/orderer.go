@@ -17,6 +17,9 @@ limitations under the License.
 package kafka
 
 import (
+	"fmt"
+	"reflect"
+	"sync"
 	"time"
 
 	"github.com/Shopify/sarama"
@@ -78,8 +81,26 @@ type consenterImpl struct {
 // HandleChain creates/returns a reference to a Chain for the given set of support resources.
 // Implements the multichain.Consenter interface. Called by multichain.newChainSupport(), which
 // is itself called by multichain.NewManagerImpl() when ranging over the ledgerFactory's existingChains.
-func (co *consenterImpl) HandleChain(cs multichain.ConsenterSupport) (multichain.Chain, error) {
-	return newChain(co, cs), nil
+// The metadata carries the offset of the last Kafka message that was persisted in the ledger, if any.
+func (co *consenterImpl) HandleChain(cs multichain.ConsenterSupport, metadata *cb.Metadata) (multichain.Chain, error) {
+	lastOffsetPersisted, err := getLastOffsetPersisted(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot retrieve the last persisted offset for chain %s: %s", cs.ChainID(), err)
+	}
+	return newChain(co, cs, lastOffsetPersisted), nil
+}
+
+// getLastOffsetPersisted decodes the Kafka metadata stored in the last block of the chain.
+// If no metadata has been stored yet, consumption should start from the oldest available offset.
+func getLastOffsetPersisted(metadata *cb.Metadata) (int64, error) {
+	if metadata == nil || metadata.Value == nil {
+		return sarama.OffsetOldest - 1, nil
+	}
+	kafkaMetadata := &ab.KafkaMetadata{}
+	if err := proto.Unmarshal(metadata.Value, kafkaMetadata); err != nil {
+		return 0, err
+	}
+	return kafkaMetadata.LastOffsetPersisted, nil
 }
 
 // When testing we need to inject our own broker/producer/consumer.
@@ -90,14 +111,15 @@ func (co *consenterImpl) HandleChain(cs multichain.ConsenterSupport) (multichain
 // definition of an interface (see testableConsenter below) that will
 // be satisfied by both the actual and the mock object and will allow
 // us to retrieve these constructors.
-func newChain(consenter testableConsenter, support multichain.ConsenterSupport) *chainImpl {
+func newChain(consenter testableConsenter, support multichain.ConsenterSupport, lastOffsetPersisted int64) *chainImpl {
 	return &chainImpl{
 		consenter:     consenter,
 		support:       support,
 		partition:     newChainPartition(support.ChainID(), rawPartition),
-		batchTimeout:  support.SharedConfig().BatchTimeout(),
-		lastProcessed: sarama.OffsetOldest - 1, // TODO This should be retrieved by ConsenterSupport; also see note in loop() below
+		lastProcessed: lastOffsetPersisted,
+		lastCutBlock:  support.Height() - 1, // All blocks but the genesis block were cut by this consenter
 		producer:      consenter.prodFunc()(support.SharedConfig().KafkaBrokers(), consenter.kafkaVersion(), consenter.retryOptions()),
+		brokers:       support.SharedConfig().KafkaBrokers(),
 		halted:        false, // Redundant as the default value for booleans is false but added for readability
 		exitChan:      make(chan struct{}),
 		haltedChan:    make(chan struct{}),
@@ -126,12 +148,13 @@ type chainImpl struct {
 	support   multichain.ConsenterSupport
 
 	partition     ChainPartition
-	batchTimeout  time.Duration
 	lastProcessed int64
 	lastCutBlock  uint64
 
-	producer Producer
-	consumer Consumer
+	producer     Producer
+	brokers      []string   // The brokers the producer was created for
+	producerLock sync.Mutex // Guards the producer, which is recreated when the brokers of the chain change
+	consumer     Consumer
 
 	halted   bool          // For the Enqueue() calls
 	exitChan chan struct{} // For the Chain's Halt() method
@@ -148,16 +171,15 @@ func (ch *chainImpl) Start() {
 	// 1. Post the CONNECT message to prevent panicking that occurs
 	// when seeking on a partition that hasn't been created yet.
 	logger.Debug("Posting the CONNECT message...")
-	if err := ch.producer.Send(ch.partition, utils.MarshalOrPanic(newConnectMessage())); err != nil {
+	if err := ch.send(utils.MarshalOrPanic(newConnectMessage())); err != nil {
 		logger.Criticalf("Couldn't post CONNECT message to %s: %s", ch.partition, err)
 		close(ch.exitChan)
 		ch.halted = true
 		return
 	}
 
-	// 2. Set up the listener/consumer for this partition.
-	// TODO When restart support gets added to the common components level, start
-	// the consumer from lastProcessed. For now, hard-code to oldest available.
+	// 2. Set up the listener/consumer for this partition, picking up
+	// right after the last message that made it to the ledger.
 	consumer, err := ch.consenter.consFunc()(ch.support.SharedConfig().KafkaBrokers(), ch.consenter.kafkaVersion(), ch.partition, ch.lastProcessed+1)
 	if err != nil {
 		logger.Criticalf("Cannot retrieve required offset from Kafka cluster for chain %s: %s", ch.partition, err)
@@ -194,7 +216,7 @@ func (ch *chainImpl) Enqueue(env *cb.Envelope) bool {
 	}
 
 	logger.Debug("Enqueueing:", env)
-	if err := ch.producer.Send(ch.partition, utils.MarshalOrPanic(newRegularMessage(utils.MarshalOrPanic(env)))); err != nil {
+	if err := ch.send(utils.MarshalOrPanic(newRegularMessage(utils.MarshalOrPanic(env)))); err != nil {
 		logger.Errorf("Couldn't post to %s: %s", ch.partition, err)
 		return false
 	}
@@ -202,13 +224,36 @@ func (ch *chainImpl) Enqueue(env *cb.Envelope) bool {
 	return !ch.halted // If ch.halted has been set to true while sending, we should return false
 }
 
+// send posts the payload to the partition of the chain. The producer is recreated first if the
+// Kafka brokers of the chain have changed since it was created, so that a reconfiguration of the
+// brokers takes effect without restarting the orderer
+func (ch *chainImpl) send(payload []byte) error {
+	ch.producerLock.Lock()
+	defer ch.producerLock.Unlock()
+	if brokers := ch.support.SharedConfig().KafkaBrokers(); !reflect.DeepEqual(brokers, ch.brokers) {
+		logger.Infof("Kafka brokers of chain %s changed from %v to %v, recreating the producer", ch.partition.Topic(), ch.brokers, brokers)
+		if err := ch.producer.Close(); err != nil {
+			logger.Warningf("Error closing the producer for brokers %v: %s", ch.brokers, err)
+		}
+		ch.producer = ch.consenter.prodFunc()(brokers, ch.consenter.kafkaVersion(), ch.consenter.retryOptions())
+		ch.brokers = brokers
+	}
+	return ch.producer.Send(ch.partition, payload)
+}
+
+func (ch *chainImpl) closeProducer() {
+	ch.producerLock.Lock()
+	defer ch.producerLock.Unlock()
+	ch.producer.Close()
+}
+
 func (ch *chainImpl) loop() {
 	msg := new(ab.KafkaMessage)
 	var timer <-chan time.Time
 	var ttcNumber uint64
 
 	defer close(ch.haltedChan)
-	defer ch.producer.Close()
+	defer ch.closeProducer()
 	defer func() { ch.halted = true }()
 	defer ch.consumer.Close()
 
@@ -237,7 +282,9 @@ func (ch *chainImpl) loop() {
 						return
 					}
 					block := ch.support.CreateNextBlock(batch)
-					ch.support.WriteBlock(block, committers)
+					encodedLastOffsetPersisted := utils.MarshalOrPanic(&ab.KafkaMetadata{LastOffsetPersisted: in.Offset})
+					ch.support.WriteBlock(block, committers, encodedLastOffsetPersisted)
+					ch.lastProcessed = in.Offset
 					ch.lastCutBlock++
 					logger.Debug("Proper time-to-cut received, just cut block", ch.lastCutBlock)
 					continue
@@ -257,14 +304,18 @@ func (ch *chainImpl) loop() {
 				batches, committers, ok := ch.support.BlockCutter().Ordered(env)
 				logger.Debugf("Ordering results: batches: %v, ok: %v", batches, ok)
 				if ok && len(batches) == 0 && timer == nil {
-					timer = time.After(ch.batchTimeout)
-					logger.Debugf("Just began %s batch timer", ch.batchTimeout.String())
+					timer = multichain.NewBatchTimer(ch.support)
 					continue
 				}
 				// If !ok, batches == nil, so this will be skipped
 				for i, batch := range batches {
+					// If two batches are returned, the first one was completed by the
+					// message preceding this one, so it should be resumed from there.
+					offset := in.Offset - int64(len(batches)-i-1)
 					block := ch.support.CreateNextBlock(batch)
-					ch.support.WriteBlock(block, committers[i])
+					encodedLastOffsetPersisted := utils.MarshalOrPanic(&ab.KafkaMetadata{LastOffsetPersisted: offset})
+					ch.support.WriteBlock(block, committers[i], encodedLastOffsetPersisted)
+					ch.lastProcessed = offset
 					ch.lastCutBlock++
 					logger.Debug("Batch filled, just cut block", ch.lastCutBlock)
 				}
@@ -275,7 +326,7 @@ func (ch *chainImpl) loop() {
 		case <-timer:
 			logger.Debugf("Time-to-cut block %d timer expired", ch.lastCutBlock+1)
 			timer = nil
-			if err := ch.producer.Send(ch.partition, utils.MarshalOrPanic(newTimeToCutMessage(ch.lastCutBlock+1))); err != nil {
+			if err := ch.send(utils.MarshalOrPanic(newTimeToCutMessage(ch.lastCutBlock+1))); err != nil {
 				logger.Errorf("Couldn't post to %s: %s", ch.partition, err)
 				// Do not exit
 			}
@@ -17,12 +17,15 @@ limitations under the License.
 package kafka
 
 import (
+	"fmt"
+	"reflect"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/hyperledger/fabric/orderer/common/bootstrap/provisional"
+	"github.com/hyperledger/fabric/orderer/common/filter"
 	"github.com/hyperledger/fabric/orderer/localconfig"
 	mockblockcutter "github.com/hyperledger/fabric/orderer/mocks/blockcutter"
 	mockmultichain "github.com/hyperledger/fabric/orderer/mocks/multichain"
@@ -124,13 +127,13 @@ func TestKafkaConsenterEmptyBatch(t *testing.T) {
 		Batches:         make(chan []*cb.Envelope),
 		BlockCutterVal:  mockblockcutter.NewReceiver(),
 		ChainIDVal:      provisional.TestChainID,
+		HeightVal:       1,
 		SharedConfigVal: &mocksharedconfig.Manager{BatchTimeoutVal: testTimePadding},
 	}
 	defer close(cs.BlockCutterVal.Block)
 
 	co := mockNewConsenter(t, testConf.Kafka.Version, testConf.Kafka.Retry)
-	ch := newChain(co, cs)
-	ch.lastProcessed = testOldestOffset - 1
+	ch := newChain(co, cs, testOldestOffset-1)
 
 	go ch.Start()
 	defer ch.Halt()
@@ -158,13 +161,13 @@ func TestKafkaConsenterBatchTimer(t *testing.T) {
 		Batches:         make(chan []*cb.Envelope),
 		BlockCutterVal:  mockblockcutter.NewReceiver(),
 		ChainIDVal:      provisional.TestChainID,
+		HeightVal:       1,
 		SharedConfigVal: &mocksharedconfig.Manager{BatchTimeoutVal: batchTimeout},
 	}
 	defer close(cs.BlockCutterVal.Block)
 
 	co := mockNewConsenter(t, testConf.Kafka.Version, testConf.Kafka.Retry)
-	ch := newChain(co, cs)
-	ch.lastProcessed = testOldestOffset - 1
+	ch := newChain(co, cs, testOldestOffset-1)
 
 	go ch.Start()
 	defer ch.Halt()
@@ -200,6 +203,103 @@ func TestKafkaConsenterBatchTimer(t *testing.T) {
 	}
 }
 
+func TestKafkaConsenterBatchTimerUpdated(t *testing.T) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	batchTimeout, _ := time.ParseDuration("1h")
+	cs := &mockmultichain.ConsenterSupport{
+		Batches:         make(chan []*cb.Envelope),
+		BlockCutterVal:  mockblockcutter.NewReceiver(),
+		ChainIDVal:      provisional.TestChainID,
+		HeightVal:       1,
+		SharedConfigVal: &mocksharedconfig.Manager{BatchTimeoutVal: batchTimeout},
+	}
+	defer close(cs.BlockCutterVal.Block)
+
+	co := mockNewConsenter(t, testConf.Kafka.Version, testConf.Kafka.Retry)
+	ch := newChain(co, cs, testOldestOffset-1)
+
+	go ch.Start()
+	defer ch.Halt()
+
+	prepareMockObjectDisks(t, co, ch)
+
+	// A configuration update of the running chain makes the batch timeout near instant
+	cs.SharedConfigVal.BatchTimeoutVal = time.Millisecond
+
+	// The second message that will be picked up is the time-to-cut message
+	// that will be posted when the short timer expires
+	waitableSyncQueueMessage(newTestEnvelope("one"), 2, &wg, co, cs, ch)
+
+	select {
+	case <-cs.Batches: // This is the success path
+	case <-time.After(testTimePadding):
+		t.Fatal("Expected block to be cut with the updated batch timeout")
+	}
+
+	// Stop the loop
+	ch.Halt()
+
+	select {
+	case <-cs.Batches:
+		t.Fatal("Expected no invocations of Append")
+	case <-ch.haltedChan: // If we're here, we definitely had a chance to invoke Append but didn't (which is great)
+	}
+}
+
+func TestKafkaConsenterBrokersUpdated(t *testing.T) {
+	cs := &mockmultichain.ConsenterSupport{
+		Batches:         make(chan []*cb.Envelope),
+		BlockCutterVal:  mockblockcutter.NewReceiver(),
+		ChainIDVal:      provisional.TestChainID,
+		HeightVal:       1,
+		SharedConfigVal: &mocksharedconfig.Manager{BatchTimeoutVal: testTimePadding, KafkaBrokersVal: []string{"old:9092"}},
+	}
+	defer close(cs.BlockCutterVal.Block)
+
+	co := mockNewConsenter(t, testConf.Kafka.Version, testConf.Kafka.Retry)
+	var producerBrokers [][]string
+	producerCreated := make(chan struct{}, 2)
+	mockPfValue := co.pf
+	co.pf = func(brokers []string, kafkaVersion sarama.KafkaVersion, retryOptions config.Retry) Producer {
+		producer := mockPfValue(brokers, kafkaVersion, retryOptions)
+		producerBrokers = append(producerBrokers, brokers)
+		producerCreated <- struct{}{}
+		return producer
+	}
+	ch := newChain(co, cs, testOldestOffset-1)
+	<-producerCreated
+
+	go ch.Start()
+	defer ch.Halt()
+
+	prepareMockObjectDisks(t, co, ch)
+
+	// A configuration update of the running chain replaces the brokers
+	cs.SharedConfigVal.KafkaBrokersVal = []string{"new:9092"}
+
+	go ch.Enqueue(newTestEnvelope("one"))
+
+	select {
+	case <-producerCreated:
+	case <-time.After(testTimePadding):
+		t.Fatal("Expected the producer to be recreated for the updated brokers")
+	}
+	select {
+	case msg := <-co.prodDisk:
+		if msg.GetRegular() == nil {
+			t.Fatalf("Expected the enqueued message to be posted by the new producer, got %v", msg)
+		}
+	case <-time.After(testTimePadding):
+		t.Fatal("Expected the enqueued message to be posted by the new producer")
+	}
+
+	if !reflect.DeepEqual(producerBrokers, [][]string{{"old:9092"}, {"new:9092"}}) {
+		t.Fatalf("Expected producers for the old and the new brokers, got producers for %v", producerBrokers)
+	}
+}
+
 func TestKafkaConsenterTimerHaltOnFilledBatch(t *testing.T) {
 	var wg sync.WaitGroup
 	defer wg.Wait()
@@ -209,13 +309,13 @@ func TestKafkaConsenterTimerHaltOnFilledBatch(t *testing.T) {
 		Batches:         make(chan []*cb.Envelope),
 		BlockCutterVal:  mockblockcutter.NewReceiver(),
 		ChainIDVal:      provisional.TestChainID,
+		HeightVal:       1,
 		SharedConfigVal: &mocksharedconfig.Manager{BatchTimeoutVal: batchTimeout},
 	}
 	defer close(cs.BlockCutterVal.Block)
 
 	co := mockNewConsenter(t, testConf.Kafka.Version, testConf.Kafka.Retry)
-	ch := newChain(co, cs)
-	ch.lastProcessed = testOldestOffset - 1
+	ch := newChain(co, cs, testOldestOffset-1)
 
 	go ch.Start()
 	defer ch.Halt()
@@ -236,7 +336,7 @@ func TestKafkaConsenterTimerHaltOnFilledBatch(t *testing.T) {
 
 	// Change the batch timeout to be near instant.
 	// If the timer was not reset, it will still be waiting an hour.
-	ch.batchTimeout = time.Millisecond
+	cs.SharedConfigVal.BatchTimeoutVal = time.Millisecond
 
 	cs.BlockCutterVal.CutNext = false
 
@@ -268,13 +368,13 @@ func TestKafkaConsenterConfigStyleMultiBatch(t *testing.T) {
 		Batches:         make(chan []*cb.Envelope),
 		BlockCutterVal:  mockblockcutter.NewReceiver(),
 		ChainIDVal:      provisional.TestChainID,
+		HeightVal:       1,
 		SharedConfigVal: &mocksharedconfig.Manager{BatchTimeoutVal: testTimePadding},
 	}
 	defer close(cs.BlockCutterVal.Block)
 
 	co := mockNewConsenter(t, testConf.Kafka.Version, testConf.Kafka.Retry)
-	ch := newChain(co, cs)
-	ch.lastProcessed = testOldestOffset - 1
+	ch := newChain(co, cs, testOldestOffset-1)
 
 	go ch.Start()
 	defer ch.Halt()
@@ -317,13 +417,13 @@ func TestKafkaConsenterTimeToCutForced(t *testing.T) {
 		Batches:         make(chan []*cb.Envelope),
 		BlockCutterVal:  mockblockcutter.NewReceiver(),
 		ChainIDVal:      provisional.TestChainID,
+		HeightVal:       1,
 		SharedConfigVal: &mocksharedconfig.Manager{BatchTimeoutVal: batchTimeout},
 	}
 	defer close(cs.BlockCutterVal.Block)
 
 	co := mockNewConsenter(t, testConf.Kafka.Version, testConf.Kafka.Retry)
-	ch := newChain(co, cs)
-	ch.lastProcessed = testOldestOffset - 1
+	ch := newChain(co, cs, testOldestOffset-1)
 
 	go ch.Start()
 	defer ch.Halt()
@@ -373,13 +473,13 @@ func TestKafkaConsenterTimeToCutDuplicate(t *testing.T) {
 		Batches:         make(chan []*cb.Envelope),
 		BlockCutterVal:  mockblockcutter.NewReceiver(),
 		ChainIDVal:      provisional.TestChainID,
+		HeightVal:       1,
 		SharedConfigVal: &mocksharedconfig.Manager{BatchTimeoutVal: batchTimeout},
 	}
 	defer close(cs.BlockCutterVal.Block)
 
 	co := mockNewConsenter(t, testConf.Kafka.Version, testConf.Kafka.Retry)
-	ch := newChain(co, cs)
-	ch.lastProcessed = testOldestOffset - 1
+	ch := newChain(co, cs, testOldestOffset-1)
 
 	go ch.Start()
 	defer ch.Halt()
@@ -461,13 +561,13 @@ func TestKafkaConsenterTimeToCutStale(t *testing.T) {
 		Batches:         make(chan []*cb.Envelope),
 		BlockCutterVal:  mockblockcutter.NewReceiver(),
 		ChainIDVal:      provisional.TestChainID,
+		HeightVal:       1,
 		SharedConfigVal: &mocksharedconfig.Manager{BatchTimeoutVal: batchTimeout},
 	}
 	defer close(cs.BlockCutterVal.Block)
 
 	co := mockNewConsenter(t, testConf.Kafka.Version, testConf.Kafka.Retry)
-	ch := newChain(co, cs)
-	ch.lastProcessed = testOldestOffset - 1
+	ch := newChain(co, cs, testOldestOffset-1)
 
 	go ch.Start()
 	defer ch.Halt()
@@ -519,13 +619,13 @@ func TestKafkaConsenterTimeToCutLarger(t *testing.T) {
 		Batches:         make(chan []*cb.Envelope),
 		BlockCutterVal:  mockblockcutter.NewReceiver(),
 		ChainIDVal:      provisional.TestChainID,
+		HeightVal:       1,
 		SharedConfigVal: &mocksharedconfig.Manager{BatchTimeoutVal: batchTimeout},
 	}
 	defer close(cs.BlockCutterVal.Block)
 
 	co := mockNewConsenter(t, testConf.Kafka.Version, testConf.Kafka.Retry)
-	ch := newChain(co, cs)
-	ch.lastProcessed = testOldestOffset - 1
+	ch := newChain(co, cs, testOldestOffset-1)
 
 	go ch.Start()
 	defer ch.Halt()
@@ -574,3 +674,268 @@ func TestKafkaConsenterTimeToCutLarger(t *testing.T) {
 	case <-ch.haltedChan: // If we're here, we definitely had a chance to invoke Append but didn't (which is great)
 	}
 }
+
+// blockRecorder passes the blocks written through the mock ConsenterSupport on to the blocks channel
+type blockRecorder struct {
+	*mockmultichain.ConsenterSupport
+	blocks chan *cb.Block
+}
+
+func (br *blockRecorder) WriteBlock(block *cb.Block, committers []filter.Committer, encodedMetadataValue []byte) *cb.Block {
+	block = br.ConsenterSupport.WriteBlock(block, committers, encodedMetadataValue)
+	br.blocks <- block
+	return block
+}
+
+// cutBlocks hands a chain over to the consenter along with the given metadata, as is done
+// on orderer startup, feeds it the messages of the partition that follow the offset it
+// resumes from, and returns the blocks it writes.
+func cutBlocks(t *testing.T, metadata *cb.Metadata, height uint64, partition []*ab.KafkaMessage, expectedBlocks int) []*cb.Block {
+	cs := &blockRecorder{
+		ConsenterSupport: &mockmultichain.ConsenterSupport{
+			Batches:         make(chan []*cb.Envelope, expectedBlocks),
+			BlockCutterVal:  mockblockcutter.NewReceiver(),
+			ChainIDVal:      provisional.TestChainID,
+			HeightVal:       height,
+			SharedConfigVal: &mocksharedconfig.Manager{BatchTimeoutVal: time.Hour},
+		},
+		blocks: make(chan *cb.Block, expectedBlocks),
+	}
+	close(cs.BlockCutterVal.Block)
+
+	co := mockNewConsenter(t, testConf.Kafka.Version, testConf.Kafka.Retry)
+	chain, err := co.HandleChain(cs, metadata)
+	if err != nil {
+		t.Fatalf("Expected the chain to be handled: %s", err)
+	}
+	ch := chain.(*chainImpl)
+
+	go ch.Start()
+	defer ch.Halt()
+
+	prepareMockObjectDisks(t, co, ch)
+
+	for _, msg := range partition[ch.lastProcessed+1-testOldestOffset:] {
+		co.consDisk <- msg
+	}
+
+	blocks := make([]*cb.Block, expectedBlocks)
+	for i := range blocks {
+		select {
+		case blocks[i] = <-cs.blocks:
+		case <-time.After(testTimePadding):
+			t.Fatalf("Expected %d blocks to be cut but got %d", expectedBlocks, i)
+		}
+	}
+	return blocks
+}
+
+func newKafkaMetadata(lastOffsetPersisted int64) *cb.Metadata {
+	return &cb.Metadata{Value: utils.MarshalOrPanic(&ab.KafkaMetadata{LastOffsetPersisted: lastOffsetPersisted})}
+}
+
+func TestKafkaConsenterRestart(t *testing.T) {
+	// The contents of the partition, starting at testOldestOffset
+	partition := []*ab.KafkaMessage{
+		newRegularMessage(utils.MarshalOrPanic(newTestEnvelope("one"))),
+		newRegularMessage(utils.MarshalOrPanic(newTestEnvelope("two"))),
+		newTimeToCutMessage(1),
+		newRegularMessage(utils.MarshalOrPanic(newTestEnvelope("three"))),
+		newTimeToCutMessage(2),
+		newTimeToCutMessage(2), // Duplicate, should be ignored
+		newRegularMessage(utils.MarshalOrPanic(newTestEnvelope("four"))),
+		newRegularMessage(utils.MarshalOrPanic(newTestEnvelope("five"))),
+		newTimeToCutMessage(3),
+	}
+
+	original := cutBlocks(t, newKafkaMetadata(testOldestOffset-1), 1, partition, 3)
+
+	// Restart from the first block, as if the orderer crashed right after writing it
+	metadata, err := utils.GetMetadataFromBlock(original[0], cb.BlockMetadataIndex_ORDERER)
+	if err != nil {
+		t.Fatalf("Expected the first block to carry orderer metadata: %s", err)
+	}
+	lastOffsetPersisted, err := getLastOffsetPersisted(metadata)
+	if err != nil {
+		t.Fatalf("Expected the orderer metadata to be decodable: %s", err)
+	}
+	if expected := testOldestOffset + 2; lastOffsetPersisted != expected {
+		t.Fatalf("Expected the first block to be cut at offset %d but got %d", expected, lastOffsetPersisted)
+	}
+
+	restarted := cutBlocks(t, metadata, 2, partition, 2)
+
+	for i := range restarted {
+		if !reflect.DeepEqual(utils.MarshalOrPanic(original[i+1]), utils.MarshalOrPanic(restarted[i])) {
+			t.Fatalf("Expected block %d of the restarted orderer to be identical to the original one", i+2)
+		}
+	}
+}
+
+func TestKafkaConsenterRestartMultiBatch(t *testing.T) {
+	partition := []*ab.KafkaMessage{
+		newRegularMessage(utils.MarshalOrPanic(newTestEnvelope("one"))),
+		newRegularMessage(utils.MarshalOrPanic(newTestEnvelope("two"))),
+	}
+
+	// The second message is isolated, so the first block should be resumed
+	// from the first message, which completed it
+	cs := &blockRecorder{
+		ConsenterSupport: &mockmultichain.ConsenterSupport{
+			Batches:         make(chan []*cb.Envelope, 2),
+			BlockCutterVal:  mockblockcutter.NewReceiver(),
+			ChainIDVal:      provisional.TestChainID,
+			HeightVal:       1,
+			SharedConfigVal: &mocksharedconfig.Manager{BatchTimeoutVal: time.Hour},
+		},
+		blocks: make(chan *cb.Block, 2),
+	}
+	defer close(cs.BlockCutterVal.Block)
+
+	co := mockNewConsenter(t, testConf.Kafka.Version, testConf.Kafka.Retry)
+	ch := newChain(co, cs, testOldestOffset-1)
+
+	go ch.Start()
+	defer ch.Halt()
+
+	prepareMockObjectDisks(t, co, ch)
+
+	co.consDisk <- partition[0]
+	cs.BlockCutterVal.Block <- struct{}{}
+	cs.BlockCutterVal.IsolatedTx = true
+	co.consDisk <- partition[1]
+	cs.BlockCutterVal.Block <- struct{}{}
+
+	for i := int64(0); i < 2; i++ {
+		select {
+		case block := <-cs.blocks:
+			metadata, err := utils.GetMetadataFromBlock(block, cb.BlockMetadataIndex_ORDERER)
+			if err != nil {
+				t.Fatalf("Expected block %d to carry orderer metadata: %s", i+1, err)
+			}
+			lastOffsetPersisted, err := getLastOffsetPersisted(metadata)
+			if err != nil {
+				t.Fatalf("Expected the orderer metadata to be decodable: %s", err)
+			}
+			if expected := testOldestOffset + i; lastOffsetPersisted != expected {
+				t.Fatalf("Expected block %d to be cut at offset %d but got %d", i+1, expected, lastOffsetPersisted)
+			}
+		case <-time.After(testTimePadding):
+			t.Fatalf("Expected two blocks to be cut but got %d", i)
+		}
+	}
+}
+
+func TestKafkaConsenterHandleChainNoMetadata(t *testing.T) {
+	cs := &mockmultichain.ConsenterSupport{
+		ChainIDVal:      provisional.TestChainID,
+		HeightVal:       1,
+		SharedConfigVal: newMockSharedConfigManager(),
+	}
+	co := mockNewConsenter(t, testConf.Kafka.Version, testConf.Kafka.Retry)
+
+	for _, metadata := range []*cb.Metadata{nil, {}} {
+		chain, err := co.HandleChain(cs, metadata)
+		if err != nil {
+			t.Fatalf("Expected the chain to be handled: %s", err)
+		}
+		if lastProcessed := chain.(*chainImpl).lastProcessed; lastProcessed != sarama.OffsetOldest-1 {
+			t.Fatalf("Expected consumption to start from the oldest offset, but the last processed offset is %d", lastProcessed)
+		}
+	}
+}
+
+func TestKafkaConsenterHandleChainBadMetadata(t *testing.T) {
+	cs := &mockmultichain.ConsenterSupport{
+		ChainIDVal:      provisional.TestChainID,
+		HeightVal:       1,
+		SharedConfigVal: newMockSharedConfigManager(),
+	}
+	co := mockNewConsenter(t, testConf.Kafka.Version, testConf.Kafka.Retry)
+
+	if _, err := co.HandleChain(cs, &cb.Metadata{Value: []byte{0x08}}); err == nil {
+		t.Fatal("Expected the chain to be rejected because of undecodable metadata")
+	}
+}
+
+// newPartitionChain starts a chain on the partition, which consumes it from the beginning and
+// records the blocks it cuts of the given number of messages, each block holding at least one
+func newPartitionChain(t *testing.T, partition *mockPartition, batchTimeout time.Duration, messages int) (*chainImpl, *blockRecorder) {
+	cs := &blockRecorder{
+		ConsenterSupport: &mockmultichain.ConsenterSupport{
+			Batches:         make(chan []*cb.Envelope, messages),
+			BlockCutterVal:  mockblockcutter.NewReceiver(),
+			ChainIDVal:      provisional.TestChainID,
+			HeightVal:       1,
+			SharedConfigVal: &mocksharedconfig.Manager{BatchTimeoutVal: batchTimeout},
+		},
+		blocks: make(chan *cb.Block, messages),
+	}
+	close(cs.BlockCutterVal.Block)
+
+	chain, err := partition.newConsenter().HandleChain(cs, newKafkaMetadata(testOldestOffset-1))
+	if err != nil {
+		t.Fatalf("Expected the chain to be handled: %s", err)
+	}
+	ch := chain.(*chainImpl)
+	ch.Start()
+	return ch, cs
+}
+
+// readLedger returns the blocks written by the chain until it has ordered the given number of messages
+func readLedger(t *testing.T, cs *blockRecorder, messages int) []*cb.Block {
+	var blocks []*cb.Block
+	for ordered := 0; ordered < messages; {
+		select {
+		case block := <-cs.blocks:
+			blocks = append(blocks, block)
+			ordered += len(block.Data.Data)
+		case <-time.After(testTimePadding):
+			t.Fatalf("Expected %d messages to be ordered but got %d", messages, ordered)
+		}
+	}
+	return blocks
+}
+
+func compareLedgers(t *testing.T, expected, actual []*cb.Block) {
+	if len(expected) != len(actual) {
+		t.Fatalf("Expected the ledgers to have the same number of blocks but got %d and %d", len(expected), len(actual))
+	}
+	for i := range expected {
+		if !reflect.DeepEqual(utils.MarshalOrPanic(expected[i]), utils.MarshalOrPanic(actual[i])) {
+			t.Fatalf("Expected block %d to be identical on both ledgers", i+1)
+		}
+	}
+}
+
+func TestKafkaConsenterMultipleOrderersMatch(t *testing.T) {
+	partition := newMockPartition()
+
+	// The timers of the orderers expire at different times, and the last one never
+	// expires at all, but they all cut blocks at the first time-to-cut message
+	batchTimeouts := []time.Duration{time.Millisecond, 3 * time.Millisecond, time.Hour}
+	messages := 30
+	chains := make([]*chainImpl, len(batchTimeouts))
+	supports := make([]*blockRecorder, len(batchTimeouts))
+	for i, batchTimeout := range batchTimeouts {
+		chains[i], supports[i] = newPartitionChain(t, partition, batchTimeout, messages)
+		defer chains[i].Halt()
+	}
+
+	for i := 0; i < messages; i++ {
+		if !chains[i%len(chains)].Enqueue(newTestEnvelope(fmt.Sprintf("message %d", i))) {
+			t.Fatalf("Expected message %d to be enqueued", i)
+		}
+		time.Sleep(time.Duration(i%4) * time.Millisecond)
+	}
+
+	ledger := readLedger(t, supports[0], messages)
+	for i := 1; i < len(supports); i++ {
+		compareLedgers(t, ledger, readLedger(t, supports[i], messages))
+	}
+
+	// An orderer which consumes the partition after the fact should arrive at the same ledger
+	late, cs := newPartitionChain(t, partition, time.Hour, messages)
+	defer late.Halt()
+	compareLedgers(t, ledger, readLedger(t, cs, messages))
+}
@@ -0,0 +1,143 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafka
+
+import (
+	"sync"
+
+	"github.com/Shopify/sarama"
+	"github.com/hyperledger/fabric/orderer/localconfig"
+)
+
+// mockPartition simulates a chain partition that is shared by several orderers.
+// Every message sent to it is assigned the next offset, and is delivered in
+// order to every consumer of the partition.
+type mockPartition struct {
+	lock    sync.Mutex
+	appends *sync.Cond // Signaled whenever a message is appended to the log
+	log     []*sarama.ConsumerMessage
+}
+
+type mockPartitionProducer struct {
+	partition *mockPartition
+}
+
+// mockPartitionConsumer forwards the messages of the partition log to its channel
+// one at a time, so that it holds any number of messages without blocking the producers
+type mockPartitionConsumer struct {
+	partition *mockPartition
+	messages  chan *sarama.ConsumerMessage
+	closed    bool // Guarded by the lock of the partition
+	done      chan struct{}
+}
+
+func newMockPartition() *mockPartition {
+	mp := &mockPartition{}
+	mp.appends = sync.NewCond(&mp.lock)
+	return mp
+}
+
+// newConsenter returns a consenter whose producers and consumers are attached to the partition
+func (mp *mockPartition) newConsenter() *consenterImpl {
+	return &consenterImpl{
+		kv: testConf.Kafka.Version,
+		ro: testConf.Kafka.Retry,
+		pf: func(brokers []string, kafkaVersion sarama.KafkaVersion, retryOptions config.Retry) Producer {
+			return &mockPartitionProducer{partition: mp}
+		},
+		cf: func(brokers []string, kafkaVersion sarama.KafkaVersion, cp ChainPartition, offset int64) (Consumer, error) {
+			return mp.newConsumer(offset), nil
+		},
+	}
+}
+
+// newConsumer returns a consumer which receives all messages from the given offset onwards
+func (mp *mockPartition) newConsumer(offset int64) Consumer {
+	mc := &mockPartitionConsumer{
+		partition: mp,
+		messages:  make(chan *sarama.ConsumerMessage),
+		done:      make(chan struct{}),
+	}
+	next := offset - testOldestOffset
+	if next < 0 {
+		next = 0
+	}
+	go mc.forward(int(next))
+	return mc
+}
+
+// forward sends the messages of the log to the channel of the consumer, starting at the given index, until it is closed
+func (mpc *mockPartitionConsumer) forward(next int) {
+	mp := mpc.partition
+	for {
+		mp.lock.Lock()
+		for next >= len(mp.log) && !mpc.closed {
+			mp.appends.Wait()
+		}
+		if mpc.closed {
+			mp.lock.Unlock()
+			return
+		}
+		msg := mp.log[next]
+		mp.lock.Unlock()
+
+		select {
+		case mpc.messages <- msg:
+			next++
+		case <-mpc.done:
+			return
+		}
+	}
+}
+
+func (mp *mockPartition) append(cp ChainPartition, payload []byte) {
+	mp.lock.Lock()
+	defer mp.lock.Unlock()
+
+	mp.log = append(mp.log, &sarama.ConsumerMessage{
+		Value:     payload,
+		Topic:     cp.Topic(),
+		Partition: cp.Partition(),
+		Offset:    testOldestOffset + int64(len(mp.log)),
+	})
+	mp.appends.Broadcast()
+}
+
+func (mpp *mockPartitionProducer) Send(cp ChainPartition, payload []byte) error {
+	mpp.partition.append(cp, payload)
+	return nil
+}
+
+func (mpp *mockPartitionProducer) Close() error {
+	return nil
+}
+
+func (mpc *mockPartitionConsumer) Recv() <-chan *sarama.ConsumerMessage {
+	return mpc.messages
+}
+
+func (mpc *mockPartitionConsumer) Close() error {
+	mp := mpc.partition
+	mp.lock.Lock()
+	defer mp.lock.Unlock()
+	if !mpc.closed {
+		mpc.closed = true
+		close(mpc.done)
+		mp.appends.Broadcast()
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blockfileledger
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/blkstorage"
+	"github.com/hyperledger/fabric/core/ledger/blkstorage/fsblkstorage"
+	ordererledger "github.com/hyperledger/fabric/orderer/ledger"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/op/go-logging"
+)
+
+var logger = logging.MustGetLogger("ordererledger/blockfileledger")
+var closedChan chan struct{}
+
+func init() {
+	closedChan = make(chan struct{})
+	close(closedChan)
+}
+
+// The orderer only ever looks blocks up by their number
+var indexConfig = &blkstorage.IndexConfig{
+	AttrsToIndex: []blkstorage.IndexableAttr{blkstorage.IndexableAttrBlockNum},
+}
+
+type cursor struct {
+	bfl         *blockfileLedger
+	blockNumber uint64
+}
+
+type blockfileLedger struct {
+	blockStore       blkstorage.BlockStore
+	mutex            sync.Mutex
+	height           uint64
+	lastHash         []byte
+	hashingAlgorithm func(input []byte) []byte
+	prunePolicy      ledger.PrunePolicy
+	signal           chan struct{}
+}
+
+type blockfileLedgerFactory struct {
+	blockStoreProvider blkstorage.BlockStoreProvider
+	prunePolicy        ledger.PrunePolicy
+	ledgers            map[string]ordererledger.ReadWriter
+	mutex              sync.Mutex
+}
+
+// New creates a new blockfileledger Factory, which stores the blocks of every chain in the append-only
+// block files of the peer's fsblkstorage under the given directory, indexed by block number in LevelDB.
+// If a prune policy is given, the block files it allows are pruned as blocks are appended, except for
+// the latest configuration block, which remains retrievable
+func New(directory string, prunePolicy ledger.PrunePolicy) ordererledger.Factory {
+	return newFactory(directory, 0, prunePolicy)
+}
+
+// newFactory creates a new blockfileledger Factory whose block files hold up to the given number of bytes,
+// or the default size of the fsblkstorage block files if zero
+func newFactory(directory string, maxBlockfileSize int, prunePolicy ledger.PrunePolicy) *blockfileLedgerFactory {
+	logger.Debugf("Initializing blockfileLedger at '%s'", directory)
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		logger.Fatalf("Could not create directory %s: %s", directory, err)
+	}
+
+	bflf := &blockfileLedgerFactory{
+		blockStoreProvider: fsblkstorage.NewProvider(fsblkstorage.NewConf(directory, maxBlockfileSize), indexConfig),
+		prunePolicy:        prunePolicy,
+		ledgers:            make(map[string]ordererledger.ReadWriter),
+	}
+
+	chainIDs, err := bflf.blockStoreProvider.List()
+	if err != nil && !os.IsNotExist(err) {
+		logger.Panicf("Error listing the chains in directory %s while initializing blockfileledger: %s", directory, err)
+	}
+
+	for _, chainID := range chainIDs {
+		if _, err := bflf.GetOrCreate(chainID); err != nil {
+			logger.Warningf("Failed to initialize chain %s: %s", chainID, err)
+		}
+	}
+
+	return bflf
+}
+
+func (bflf *blockfileLedgerFactory) ChainIDs() []string {
+	bflf.mutex.Lock()
+	defer bflf.mutex.Unlock()
+	ids := make([]string, len(bflf.ledgers))
+
+	i := 0
+	for key := range bflf.ledgers {
+		ids[i] = key
+		i++
+	}
+
+	return ids
+}
+
+func (bflf *blockfileLedgerFactory) GetOrCreate(chainID string) (ordererledger.ReadWriter, error) {
+	bflf.mutex.Lock()
+	defer bflf.mutex.Unlock()
+
+	key := chainID
+
+	l, ok := bflf.ledgers[key]
+	if ok {
+		return l, nil
+	}
+
+	// The block store of a chain may only be opened once, which the ledgers map ensures
+	blockStore, err := bflf.blockStoreProvider.OpenBlockStore(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	ch, err := newChain(blockStore, bflf.prunePolicy)
+	if err != nil {
+		return nil, err
+	}
+	bflf.ledgers[key] = ch
+	return ch, nil
+}
+
+// Close shuts down the block stores of all chains, after which the Factory and its ledgers must no longer be used
+func (bflf *blockfileLedgerFactory) Close() {
+	bflf.mutex.Lock()
+	defer bflf.mutex.Unlock()
+
+	for _, l := range bflf.ledgers {
+		l.(*blockfileLedger).blockStore.Shutdown()
+	}
+	bflf.blockStoreProvider.Close()
+}
+
+// newChain creates a new chain backed by the given block store
+func newChain(blockStore blkstorage.BlockStore, prunePolicy ledger.PrunePolicy) (*blockfileLedger, error) {
+	bfl := &blockfileLedger{
+		blockStore:  blockStore,
+		prunePolicy: prunePolicy,
+		signal:      make(chan struct{}),
+	}
+	if err := bfl.initializeBlockHeight(); err != nil {
+		return nil, err
+	}
+	logger.Debugf("Initialized to block height %d with hash %x", bfl.height, bfl.lastHash)
+	return bfl, nil
+}
+
+// initializeBlockHeight retrieves the last block of the block store to populate the height and lastHash
+func (bfl *blockfileLedger) initializeBlockHeight() error {
+	// The block store counts its height from its first block, whereas orderer chains start with block 0,
+	// so the last block is looked up rather than relying on the height reported by the block store
+	block, err := bfl.blockStore.RetrieveBlockByNumber(math.MaxUint64)
+	if err == blkstorage.ErrNotFoundInIndex {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error retrieving the last block: %s", err)
+	}
+	// The genesis block may have been pruned, unlike the latest configuration block
+	configBlock, err := bfl.blockStore.RetrieveConfigBlock()
+	if err == blkstorage.ErrNotFoundInIndex {
+		configBlock, err = bfl.blockStore.RetrieveBlockByNumber(0)
+	}
+	if err != nil {
+		return fmt.Errorf("Error retrieving the configuration block: %s", err)
+	}
+	bfl.height = block.Header.Number + 1
+	bfl.hashingAlgorithm = ordererledger.HashingAlgorithm(configBlock)
+	bfl.lastHash = block.Header.HashWith(bfl.hashingAlgorithm)
+	return nil
+}
+
+// Height returns the highest block number in the chain, plus one
+func (bfl *blockfileLedger) Height() uint64 {
+	bfl.mutex.Lock()
+	defer bfl.mutex.Unlock()
+	return bfl.height
+}
+
+// Append appends a new block to the ledger
+func (bfl *blockfileLedger) Append(block *cb.Block) error {
+	bfl.mutex.Lock()
+	defer bfl.mutex.Unlock()
+
+	if block.Header.Number != bfl.height {
+		return fmt.Errorf("Block number should have been %d but was %d", bfl.height, block.Header.Number)
+	}
+
+	if !bytes.Equal(block.Header.PreviousHash, bfl.lastHash) {
+		return fmt.Errorf("Block should have had previous hash of %x but was %x", bfl.lastHash, block.Header.PreviousHash)
+	}
+
+	if block.Header.Number == 0 {
+		bfl.hashingAlgorithm = ordererledger.HashingAlgorithm(block)
+	}
+
+	if err := bfl.blockStore.AddBlock(block); err != nil {
+		return err
+	}
+	logger.Debugf("Wrote block %d", block.Header.Number)
+
+	bfl.lastHash = block.Header.HashWith(bfl.hashingAlgorithm)
+	bfl.height++
+	close(bfl.signal)
+	bfl.signal = make(chan struct{})
+
+	// The block has been committed, so a failure to prune is not a failure to append
+	if bfl.prunePolicy != nil {
+		if err := bfl.blockStore.Prune(bfl.prunePolicy); err != nil {
+			logger.Errorf("Error pruning the ledger after block %d: %s", block.Header.Number, err)
+		}
+	}
+	return nil
+}
+
+// Iterator implements the ordererledger.Reader definition
+func (bfl *blockfileLedger) Iterator(startPosition *ab.SeekPosition) (ordererledger.Iterator, uint64) {
+	height := bfl.Height()
+	switch start := startPosition.Type.(type) {
+	case *ab.SeekPosition_Oldest:
+		return &cursor{bfl: bfl, blockNumber: 0}, 0
+	case *ab.SeekPosition_Newest:
+		high := height - 1
+		return &cursor{bfl: bfl, blockNumber: high}, high
+	case *ab.SeekPosition_Specified:
+		if start.Specified.Number > height {
+			return &ordererledger.NotFoundErrorIterator{}, 0
+		}
+		return &cursor{bfl: bfl, blockNumber: start.Specified.Number}, start.Specified.Number
+	}
+
+	// This line should be unreachable, but the compiler requires it
+	return &ordererledger.NotFoundErrorIterator{}, 0
+}
+
+// waitFor returns a channel which is closed once the ledger holds the given block
+func (bfl *blockfileLedger) waitFor(blockNumber uint64) <-chan struct{} {
+	bfl.mutex.Lock()
+	defer bfl.mutex.Unlock()
+	if blockNumber < bfl.height {
+		return closedChan
+	}
+	return bfl.signal
+}
+
+// Next blocks until there is a new block available, or returns an error if the next block is no longer retrievable
+func (cu *cursor) Next() (*cb.Block, cb.Status) {
+	// A cursor never points more than one block past the end of the chain,
+	// so the block is available once the next signal has been received
+	<-cu.bfl.waitFor(cu.blockNumber)
+	block, err := cu.bfl.blockStore.RetrieveBlockByNumber(cu.blockNumber)
+	if err == blkstorage.ErrPruned {
+		// The latest configuration block is retained, as the chain is set up from it
+		if configBlock, configErr := cu.bfl.blockStore.RetrieveConfigBlock(); configErr == nil && configBlock.Header.Number == cu.blockNumber {
+			block, err = configBlock, nil
+		}
+	}
+	if err == blkstorage.ErrPruned {
+		logger.Warningf("Block %d has been pruned from the ledger", cu.blockNumber)
+		return nil, cb.Status_GONE
+	}
+	if err != nil {
+		logger.Errorf("Error retrieving block %d: %s", cu.blockNumber, err)
+		return nil, cb.Status_SERVICE_UNAVAILABLE
+	}
+	cu.blockNumber++
+	return block, cb.Status_SUCCESS
+}
+
+// ReadyChan returns a channel that will close when Next is ready to be called without blocking
+func (cu *cursor) ReadyChan() <-chan struct{} {
+	return cu.bfl.waitFor(cu.blockNumber)
+}
@@ -0,0 +1,239 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blockfileledger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/orderer/common/bootstrap/provisional"
+	ordererledger "github.com/hyperledger/fabric/orderer/ledger"
+	"github.com/hyperledger/fabric/orderer/localconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+)
+
+var genesisBlock = provisional.New(config.Load()).GenesisBlock()
+
+type testEnv struct {
+	t                *testing.T
+	location         string
+	maxBlockfileSize int
+	prunePolicy      ledger.PrunePolicy
+	factory          *blockfileLedgerFactory
+}
+
+func initialize(t *testing.T) (*testEnv, *blockfileLedger) {
+	return initializeWithPrunePolicy(t, 0, nil)
+}
+
+func initializeWithPrunePolicy(t *testing.T, maxBlockfileSize int, prunePolicy ledger.PrunePolicy) (*testEnv, *blockfileLedger) {
+	name, err := ioutil.TempDir("", "hyperledger_fabric")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	tev := &testEnv{location: name, t: t, maxBlockfileSize: maxBlockfileSize, prunePolicy: prunePolicy}
+	bfl := tev.open(provisional.TestChainID)
+	if err := bfl.Append(genesisBlock); err != nil {
+		t.Fatalf("Error appending genesis block: %s", err)
+	}
+	return tev, bfl
+}
+
+// open (re)opens the ledger in the test location and returns the given chain
+func (tev *testEnv) open(chainID string) *blockfileLedger {
+	if tev.factory != nil {
+		tev.factory.Close()
+	}
+	tev.factory = newFactory(tev.location, tev.maxBlockfileSize, tev.prunePolicy)
+	bfl, err := tev.factory.GetOrCreate(chainID)
+	if err != nil {
+		tev.t.Fatalf("Error opening chain %s: %s", chainID, err)
+	}
+	return bfl.(*blockfileLedger)
+}
+
+func (tev *testEnv) tearDown() {
+	tev.factory.Close()
+	err := os.RemoveAll(tev.location)
+	if err != nil {
+		tev.t.Fatalf("Error tearing down env: %s", err)
+	}
+}
+
+func TestInitialization(t *testing.T) {
+	tev, bfl := initialize(t)
+	defer tev.tearDown()
+	if bfl.height != 1 {
+		t.Fatalf("Block height should be 1")
+	}
+	block := ordererledger.GetBlock(bfl, 0)
+	if block == nil {
+		t.Fatalf("Error retrieving genesis block")
+	}
+	if !bytes.Equal(block.Header.Hash(), bfl.lastHash) {
+		t.Fatalf("Block hashes did no match")
+	}
+}
+
+func TestReinitializationGenesisOnly(t *testing.T) {
+	tev, _ := initialize(t)
+	defer tev.tearDown()
+
+	bfl := tev.open(provisional.TestChainID)
+	if bfl.height != 1 {
+		t.Fatalf("Block height should be 1 but is %d", bfl.height)
+	}
+	if !bytes.Equal(genesisBlock.Header.Hash(), bfl.lastHash) {
+		t.Fatalf("Block hashes did no match")
+	}
+}
+
+func TestReinitialization(t *testing.T) {
+	tev, obfl := initialize(t)
+	defer tev.tearDown()
+	for i := 0; i < 10; i++ {
+		if err := obfl.Append(ordererledger.CreateNextBlock(obfl, []*cb.Envelope{{Payload: []byte("My Data")}})); err != nil {
+			t.Fatalf("Error appending block: %s", err)
+		}
+	}
+	lastHash := obfl.lastHash
+
+	bfl := tev.open("foo")
+	if chains := tev.factory.ChainIDs(); len(chains) != 2 {
+		t.Fatalf("Should have recovered the chain, but got chains %v", chains)
+	}
+	if bfl.height != 0 {
+		t.Fatalf("The new chain should be empty")
+	}
+
+	bfl = tev.open(provisional.TestChainID)
+	if bfl.height != 11 {
+		t.Fatalf("Block height should be 11 but is %d", bfl.height)
+	}
+	if !bytes.Equal(lastHash, bfl.lastHash) {
+		t.Fatalf("Block hashes did no match")
+	}
+	block := ordererledger.GetBlock(bfl, 10)
+	if block == nil {
+		t.Fatalf("Error retrieving block 10")
+	}
+	if !bytes.Equal(block.Header.Hash(), lastHash) {
+		t.Fatalf("Block hashes did no match")
+	}
+}
+
+func TestAppendOutOfOrder(t *testing.T) {
+	tev, bfl := initialize(t)
+	defer tev.tearDown()
+
+	block := ordererledger.CreateNextBlock(bfl, []*cb.Envelope{{Payload: []byte("My Data")}})
+	block.Header.Number++
+	if err := bfl.Append(block); err == nil {
+		t.Fatalf("Should have rejected a block with the wrong number")
+	}
+
+	block = ordererledger.CreateNextBlock(bfl, []*cb.Envelope{{Payload: []byte("My Data")}})
+	block.Header.PreviousHash = []byte("foo")
+	if err := bfl.Append(block); err == nil {
+		t.Fatalf("Should have rejected a block with the wrong previous hash")
+	}
+
+	if bfl.height != 1 {
+		t.Fatalf("Block height should still be 1")
+	}
+}
+
+func TestBlockingNext(t *testing.T) {
+	tev, bfl := initialize(t)
+	defer tev.tearDown()
+
+	it, num := bfl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Newest{}})
+	if num != 0 {
+		t.Fatalf("Expected the newest block to be the genesis block, but got %d", num)
+	}
+	if _, status := it.Next(); status != cb.Status_SUCCESS {
+		t.Fatalf("Expected to successfully read the genesis block")
+	}
+
+	blocks := make(chan *cb.Block)
+	go func() {
+		block, _ := it.Next()
+		blocks <- block
+	}()
+
+	select {
+	case <-blocks:
+		t.Fatalf("Next should block until the next block is appended")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	bfl.Append(ordererledger.CreateNextBlock(bfl, []*cb.Envelope{{Payload: []byte("My Data")}}))
+
+	select {
+	case block := <-blocks:
+		if block == nil || block.Header.Number != 1 {
+			t.Fatalf("Expected to retrieve block 1 but got %v", block)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Next should have returned once the block was appended")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	// Every block is written to its own block file, which is pruned once two newer blocks are appended
+	tev, bfl := initializeWithPrunePolicy(t, 1, &ledger.KeepLastNBlocksPolicy{N: 2})
+	defer tev.tearDown()
+	for i := 0; i < 5; i++ {
+		if err := bfl.Append(ordererledger.CreateNextBlock(bfl, []*cb.Envelope{{Payload: []byte("My Data")}})); err != nil {
+			t.Fatalf("Error appending block: %s", err)
+		}
+	}
+	lastHash := bfl.lastHash
+
+	for _, test := range []struct {
+		number uint64
+		status cb.Status
+	}{
+		{0, cb.Status_SUCCESS}, // the genesis block is the latest configuration block
+		{1, cb.Status_GONE},
+		{3, cb.Status_GONE},
+		{4, cb.Status_SUCCESS},
+		{5, cb.Status_SUCCESS},
+	} {
+		it, _ := bfl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: test.number}}})
+		block, status := it.Next()
+		if status != test.status {
+			t.Fatalf("Expected status %s for block %d but got %s", test.status, test.number, status)
+		}
+		if status == cb.Status_SUCCESS && block.Header.Number != test.number {
+			t.Fatalf("Expected block %d but got block %d", test.number, block.Header.Number)
+		}
+	}
+
+	bfl = tev.open(provisional.TestChainID)
+	if bfl.height != 6 {
+		t.Fatalf("Block height should be 6 but is %d", bfl.height)
+	}
+	if !bytes.Equal(lastHash, bfl.lastHash) {
+		t.Fatalf("Block hashes did no match")
+	}
+}
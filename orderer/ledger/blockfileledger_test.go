@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ordererledger_test
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/hyperledger/fabric/orderer/common/bootstrap/provisional"
+	. "github.com/hyperledger/fabric/orderer/ledger"
+	blockfileledger "github.com/hyperledger/fabric/orderer/ledger/blockfile"
+)
+
+func init() {
+	testables = append(testables, &blockfileLedgerTestEnv{})
+}
+
+// closeable is implemented by the blockfileledger Factory, whose LevelDB
+// index must be released before the ledger can be opened again
+type closeable interface {
+	Close()
+}
+
+type blockfileLedgerTestFactory struct {
+	location string
+	factory  Factory
+}
+
+type blockfileLedgerTestEnv struct {
+}
+
+func (env *blockfileLedgerTestEnv) Initialize() (ledgerTestFactory, error) {
+	location, err := ioutil.TempDir("", "hyperledger")
+	if err != nil {
+		return nil, err
+	}
+	return &blockfileLedgerTestFactory{location: location}, nil
+}
+
+func (env *blockfileLedgerTestEnv) Name() string {
+	return "blockfileledger"
+}
+
+func (env *blockfileLedgerTestFactory) close() {
+	if env.factory != nil {
+		env.factory.(closeable).Close()
+		env.factory = nil
+	}
+}
+
+func (env *blockfileLedgerTestFactory) Destroy() error {
+	env.close()
+	return os.RemoveAll(env.location)
+}
+
+func (env *blockfileLedgerTestFactory) Persistent() bool {
+	return true
+}
+
+func (env *blockfileLedgerTestFactory) New() (Factory, ReadWriter) {
+	env.close()
+	env.factory = blockfileledger.New(env.location, nil)
+	bfl, err := env.factory.GetOrCreate(provisional.TestChainID)
+	if err != nil {
+		panic(err)
+	}
+	if bfl.Height() == 0 {
+		if err := bfl.Append(genesisBlock); err != nil {
+			panic(err)
+		}
+	}
+	return env.factory, bfl
+}
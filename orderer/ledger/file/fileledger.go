@@ -50,12 +50,13 @@ type cursor struct {
 }
 
 type fileLedger struct {
-	directory      string
-	fqFormatString string
-	height         uint64
-	signal         chan struct{}
-	lastHash       []byte
-	marshaler      *jsonpb.Marshaler
+	directory        string
+	fqFormatString   string
+	height           uint64
+	signal           chan struct{}
+	lastHash         []byte
+	hashingAlgorithm func(input []byte) []byte
+	marshaler        *jsonpb.Marshaler
 }
 
 type fileLedgerFactory struct {
@@ -185,7 +186,12 @@ func (fl *fileLedger) initializeBlockHeight() {
 	if block == nil {
 		panic(fmt.Errorf("Error reading block %d", fl.height-1))
 	}
-	fl.lastHash = block.Header.Hash()
+	genesisBlock, _ := fl.readBlock(0)
+	if genesisBlock == nil {
+		panic(fmt.Errorf("Error reading genesis block"))
+	}
+	fl.hashingAlgorithm = ordererledger.HashingAlgorithm(genesisBlock)
+	fl.lastHash = block.Header.HashWith(fl.hashingAlgorithm)
 }
 
 // blockFilename returns the fully qualified path to where a block of a given number should be stored on disk
@@ -239,8 +245,12 @@ func (fl *fileLedger) Append(block *cb.Block) error {
 		return fmt.Errorf("Block should have had previous hash of %x but was %x", fl.lastHash, block.Header.PreviousHash)
 	}
 
+	if block.Header.Number == 0 {
+		fl.hashingAlgorithm = ordererledger.HashingAlgorithm(block)
+	}
+
 	fl.writeBlock(block)
-	fl.lastHash = block.Header.Hash()
+	fl.lastHash = block.Header.HashWith(fl.hashingAlgorithm)
 	fl.height++
 	close(fl.signal)
 	fl.signal = make(chan struct{})
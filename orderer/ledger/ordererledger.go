@@ -17,12 +17,17 @@ limitations under the License.
 package ordererledger
 
 import (
+	"github.com/hyperledger/fabric/common/chainconfig"
+	"github.com/hyperledger/fabric/common/util"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/op/go-logging"
 )
 
+var logger = logging.MustGetLogger("orderer/ledger")
+
 // Factory retrieves or creates new ledgers by chainID
 type Factory interface {
 	// GetOrCreate gets an existing ledger (if it exists) or creates it if it does not
@@ -61,8 +66,15 @@ type ReadWriter interface {
 }
 
 // CreateNextBlock provides a utility way to construct the next block from contents and metadata for a given ledger
-// XXX this will need to be modified to accept marshaled envelopes to accomodate non-deterministic marshaling
+// using the default hashing algorithm
 func CreateNextBlock(rl Reader, messages []*cb.Envelope) *cb.Block {
+	return CreateNextBlockWithHashingAlgorithm(rl, messages, util.ComputeCryptoHash)
+}
+
+// CreateNextBlockWithHashingAlgorithm constructs the next block from contents and metadata for a given ledger,
+// computing the data hash and the previous block hash with the given hashing algorithm
+// XXX this will need to be modified to accept marshaled envelopes to accomodate non-deterministic marshaling
+func CreateNextBlockWithHashingAlgorithm(rl Reader, messages []*cb.Envelope, hashingAlgorithm func(input []byte) []byte) *cb.Block {
 	var nextBlockNumber uint64
 	var previousBlockHash []byte
 
@@ -74,7 +86,7 @@ func CreateNextBlock(rl Reader, messages []*cb.Envelope) *cb.Block {
 			panic("Error seeking to newest block for chain with non-zero height")
 		}
 		nextBlockNumber = block.Header.Number + 1
-		previousBlockHash = block.Header.Hash()
+		previousBlockHash = block.Header.HashWith(hashingAlgorithm)
 	}
 
 	data := &cb.BlockData{
@@ -90,12 +102,24 @@ func CreateNextBlock(rl Reader, messages []*cb.Envelope) *cb.Block {
 	}
 
 	block := cb.NewBlock(nextBlockNumber, previousBlockHash)
-	block.Header.DataHash = data.Hash()
+	block.Header.DataHash = data.HashWith(hashingAlgorithm)
 	block.Data = data
 
 	return block
 }
 
+// HashingAlgorithm returns the hashing algorithm configured by the genesis block of a chain, with which
+// the blocks of the chain are chained together. Genesis blocks which do not carry a configuration
+// transaction yield the default hashing algorithm
+func HashingAlgorithm(genesisBlock *cb.Block) func(input []byte) []byte {
+	hashingAlgorithm, err := chainconfig.GetHashingAlgorithmFromBlock(genesisBlock)
+	if err != nil {
+		logger.Debugf("Using the default hashing algorithm, as the genesis block does not configure one: %s", err)
+		return util.ComputeCryptoHash
+	}
+	return hashingAlgorithm
+}
+
 // GetBlock is a utility method for retrieving a single block
 func GetBlock(rl Reader, index uint64) *cb.Block {
 	i, _ := rl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: index}}})
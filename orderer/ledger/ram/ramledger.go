@@ -40,10 +40,11 @@ type simpleList struct {
 }
 
 type ramLedger struct {
-	maxSize int
-	size    int
-	oldest  *simpleList
-	newest  *simpleList
+	maxSize          int
+	size             int
+	oldest           *simpleList
+	newest           *simpleList
+	hashingAlgorithm func(input []byte) []byte
 }
 
 type ramLedgerFactory struct {
@@ -204,9 +205,12 @@ func (rl *ramLedger) Append(block *cb.Block) error {
 	}
 
 	if rl.newest.block.Header.Number+1 != 0 { // Skip this check for genesis block insertion
-		if !bytes.Equal(block.Header.PreviousHash, rl.newest.block.Header.Hash()) {
-			return fmt.Errorf("Block should have had previous hash of %x but was %x", rl.newest.block.Header.Hash(), block.Header.PreviousHash)
+		previousHash := rl.newest.block.Header.HashWith(rl.hashingAlgorithm)
+		if !bytes.Equal(block.Header.PreviousHash, previousHash) {
+			return fmt.Errorf("Block should have had previous hash of %x but was %x", previousHash, block.Header.PreviousHash)
 		}
+	} else {
+		rl.hashingAlgorithm = ordererledger.HashingAlgorithm(block)
 	}
 
 	rl.appendBlock(block)
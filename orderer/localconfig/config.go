@@ -48,13 +48,27 @@ type General struct {
 	GenesisFile   string
 	Profile       Profile
 	LogLevel      string
+	LocalMSPDir   string
+	TLS           TLS
+}
+
+// TLS contains config for TLS connections to the orderer
+type TLS struct {
+	Enabled           bool
+	PrivateKey        string
+	Certificate       string
+	RootCAs           []string
+	ClientAuthEnabled bool
+	ClientRootCAs     []string
 }
 
 // Genesis contains config which is used by the provisional bootstrapper
 type Genesis struct {
-	OrdererType  string
-	BatchTimeout time.Duration
-	BatchSize    BatchSize
+	OrdererType      string
+	BatchTimeout     time.Duration
+	BatchSize        BatchSize
+	HashingAlgorithm string
+	SbftShared       SbftShared
 }
 
 // BatchSize contains configuration affecting the size of batches
@@ -75,10 +89,17 @@ type RAMLedger struct {
 	HistorySize uint
 }
 
-// FileLedger contains config for the File ledger
+// FileLedger contains config for the File and Blockfile ledgers
 type FileLedger struct {
 	Location string
 	Prefix   string
+	Prune    Prune
+}
+
+// Prune contains config for the pruning of the Blockfile ledger, a zero value retaining blocks regardless of it
+type Prune struct {
+	KeepLastBlocks uint64
+	KeepNewerThan  time.Duration
 }
 
 // Kafka contains config for the Kafka orderer
@@ -95,6 +116,28 @@ type Retry struct {
 	Stop   time.Duration
 }
 
+// SbftLocal contains config for the local SBFT replica
+type SbftLocal struct {
+	PeerCommAddr string
+	CertFile     string
+	KeyFile      string
+	DataDir      string
+}
+
+// SbftShared contains the SBFT network config which is used by the provisional bootstrapper
+type SbftShared struct {
+	N              uint64
+	F              uint64
+	RequestTimeout time.Duration
+	Peers          []SbftPeer
+}
+
+// SbftPeer identifies an SBFT replica by its replica-to-replica address and certificate
+type SbftPeer struct {
+	Address     string
+	Certificate string
+}
+
 // TopLevel directly corresponds to the orderer config yaml
 // Note, for non 1-1 mappings, you may append
 // something like `mapstructure:"weirdFoRMat"` to
@@ -106,6 +149,7 @@ type TopLevel struct {
 	FileLedger FileLedger
 	Kafka      Kafka
 	Genesis    Genesis
+	SbftLocal  SbftLocal
 }
 
 var defaults = TopLevel{
@@ -121,7 +165,8 @@ var defaults = TopLevel{
 			Enabled: false,
 			Address: "0.0.0.0:6060",
 		},
-		LogLevel: "INFO",
+		LogLevel:    "INFO",
+		LocalMSPDir: "../msp/sampleconfig/",
 	},
 	RAMLedger: RAMLedger{
 		HistorySize: 10000,
@@ -129,6 +174,10 @@ var defaults = TopLevel{
 	FileLedger: FileLedger{
 		Location: "",
 		Prefix:   "hyperledger-fabric-ordererledger",
+		Prune: Prune{
+			KeepLastBlocks: 0,
+			KeepNewerThan:  0,
+		},
 	},
 	Kafka: Kafka{
 		Brokers: []string{"127.0.0.1:9092"},
@@ -147,6 +196,16 @@ var defaults = TopLevel{
 			AbsoluteMaxBytes:  100000000,
 			PreferredMaxBytes: 512 * 1024,
 		},
+		HashingAlgorithm: "SHAKE256",
+		SbftShared: SbftShared{
+			N:              1,
+			F:              0,
+			RequestTimeout: time.Second,
+		},
+	},
+	SbftLocal: SbftLocal{
+		PeerCommAddr: "127.0.0.1:6101",
+		DataDir:      "/var/hyperledger/production/sbft",
 	},
 }
 
@@ -173,6 +232,13 @@ func (c *TopLevel) completeInitialization() {
 		case c.General.LogLevel == "":
 			logger.Infof("General.LogLevel unset, setting to %s", defaults.General.LogLevel)
 			c.General.LogLevel = defaults.General.LogLevel
+		case c.General.LocalMSPDir == "":
+			logger.Infof("General.LocalMSPDir unset, setting to %s", defaults.General.LocalMSPDir)
+			c.General.LocalMSPDir = defaults.General.LocalMSPDir
+		case c.General.TLS.Enabled && (c.General.TLS.Certificate == "" || c.General.TLS.PrivateKey == ""):
+			logger.Panicf("General.TLS.Enabled set to true but General.TLS.Certificate or General.TLS.PrivateKey unset")
+		case c.General.TLS.ClientAuthEnabled && !c.General.TLS.Enabled:
+			logger.Panicf("General.TLS.ClientAuthEnabled set to true but General.TLS.Enabled unset")
 		case c.General.GenesisMethod == "":
 			c.General.GenesisMethod = defaults.General.GenesisMethod
 		case c.General.GenesisFile == "":
@@ -207,6 +273,20 @@ func (c *TopLevel) completeInitialization() {
 		case c.Genesis.BatchSize.PreferredMaxBytes == 0:
 			logger.Infof("Genesis.BatchSize.PreferredMaxBytes unset, setting to %s", defaults.Genesis.BatchSize.PreferredMaxBytes)
 			c.Genesis.BatchSize.PreferredMaxBytes = defaults.Genesis.BatchSize.PreferredMaxBytes
+		case c.Genesis.HashingAlgorithm == "":
+			logger.Infof("Genesis.HashingAlgorithm unset, setting to %s", defaults.Genesis.HashingAlgorithm)
+			c.Genesis.HashingAlgorithm = defaults.Genesis.HashingAlgorithm
+		case c.Genesis.OrdererType == "sbft" && (c.SbftLocal.CertFile == "" || c.SbftLocal.KeyFile == ""):
+			logger.Panicf("Genesis.OrdererType set to sbft but SbftLocal.CertFile or SbftLocal.KeyFile unset")
+		case c.Genesis.SbftShared.RequestTimeout == 0:
+			logger.Infof("Genesis.SbftShared.RequestTimeout unset, setting to %s", defaults.Genesis.SbftShared.RequestTimeout)
+			c.Genesis.SbftShared.RequestTimeout = defaults.Genesis.SbftShared.RequestTimeout
+		case c.SbftLocal.PeerCommAddr == "":
+			logger.Infof("SbftLocal.PeerCommAddr unset, setting to %s", defaults.SbftLocal.PeerCommAddr)
+			c.SbftLocal.PeerCommAddr = defaults.SbftLocal.PeerCommAddr
+		case c.SbftLocal.DataDir == "":
+			logger.Infof("SbftLocal.DataDir unset, setting to %s", defaults.SbftLocal.DataDir)
+			c.SbftLocal.DataDir = defaults.SbftLocal.DataDir
 		default:
 			// A bit hacky, but its type makes it impossible to test for a nil value.
 			// This may be overwritten by the Kafka orderer upon instantiation.
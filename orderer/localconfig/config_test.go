@@ -191,3 +191,29 @@ func TestEnvInnerVar(t *testing.T) {
 		t.Fatalf("Environmental override of inner config test 2 did not work")
 	}
 }
+
+func TestTLSEnabledWithoutCertificate(t *testing.T) {
+	envVar := "ORDERER_GENERAL_TLS_ENABLED"
+	os.Setenv(envVar, "true")
+	defer os.Unsetenv(envVar)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Should have panicked when TLS is enabled without a certificate and key")
+		}
+	}()
+	Load()
+}
+
+func TestSbftWithoutCertificate(t *testing.T) {
+	envVar := "ORDERER_GENESIS_ORDERERTYPE"
+	os.Setenv(envVar, "sbft")
+	defer os.Unsetenv(envVar)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Should have panicked when the orderer type is sbft without a replica certificate and key")
+		}
+	}()
+	Load()
+}
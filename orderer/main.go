@@ -20,20 +20,25 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
-	"net"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/localmsp"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/core/ledger"
+	mspmgmt "github.com/hyperledger/fabric/core/peer/msp"
 	"github.com/hyperledger/fabric/orderer/common/bootstrap/file"
 	"github.com/hyperledger/fabric/orderer/common/bootstrap/provisional"
 	"github.com/hyperledger/fabric/orderer/kafka"
 	ordererledger "github.com/hyperledger/fabric/orderer/ledger"
+	blockfileledger "github.com/hyperledger/fabric/orderer/ledger/blockfile"
 	fileledger "github.com/hyperledger/fabric/orderer/ledger/file"
 	ramledger "github.com/hyperledger/fabric/orderer/ledger/ram"
 	"github.com/hyperledger/fabric/orderer/localconfig"
 	"github.com/hyperledger/fabric/orderer/multichain"
+	"github.com/hyperledger/fabric/orderer/sbft"
 	"github.com/hyperledger/fabric/orderer/solo"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
@@ -41,7 +46,6 @@ import (
 
 	"github.com/Shopify/sarama"
 	logging "github.com/op/go-logging"
-	"google.golang.org/grpc"
 )
 
 var logger = logging.MustGetLogger("orderer/main")
@@ -59,9 +63,18 @@ func main() {
 		}()
 	}
 
-	grpcServer := grpc.NewServer()
+	// Load the local MSP, which provides the signing identity of this orderer
+	err := mspmgmt.LoadLocalMsp(conf.General.LocalMSPDir)
+	if err != nil {
+		logger.Panicf("Failed initializing local MSP from %s: %s", conf.General.LocalMSPDir, err)
+	}
+
+	secureConfig, err := newSecureServerConfig(conf.General.TLS)
+	if err != nil {
+		logger.Panicf("Failed loading TLS configuration: %s", err)
+	}
 
-	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", conf.General.ListenAddress, conf.General.ListenPort))
+	grpcServer, err := comm.NewGRPCServer(fmt.Sprintf("%s:%d", conf.General.ListenAddress, conf.General.ListenPort), secureConfig)
 	if err != nil {
 		fmt.Println("Failed to listen:", err)
 		return
@@ -70,15 +83,9 @@ func main() {
 	var lf ordererledger.Factory
 	switch conf.General.LedgerType {
 	case "file":
-		location := conf.FileLedger.Location
-		if location == "" {
-			var err error
-			location, err = ioutil.TempDir("", conf.FileLedger.Prefix)
-			if err != nil {
-				panic(fmt.Errorf("Error creating temp dir: %s", err))
-			}
-		}
-		lf = fileledger.New(location)
+		lf = fileledger.New(fileLedgerLocation(conf.FileLedger))
+	case "blockfile":
+		lf = blockfileledger.New(fileLedgerLocation(conf.FileLedger), prunePolicy(conf.FileLedger.Prune))
 	case "ram":
 		fallthrough
 	default:
@@ -126,8 +133,9 @@ func main() {
 	consenters := make(map[string]multichain.Consenter)
 	consenters["solo"] = solo.New()
 	consenters["kafka"] = kafka.New(conf.Kafka.Version, conf.Kafka.Retry)
+	consenters["sbft"] = sbft.New(conf.SbftLocal)
 
-	manager := multichain.NewManagerImpl(lf, consenters)
+	manager := multichain.NewManagerImpl(lf, consenters, localmsp.NewSigner())
 
 	server := NewServer(
 		manager,
@@ -135,6 +143,76 @@ func main() {
 		int(conf.General.MaxWindowSize),
 	)
 
-	ab.RegisterAtomicBroadcastServer(grpcServer, server)
-	grpcServer.Serve(lis)
+	ab.RegisterAtomicBroadcastServer(grpcServer.Server(), server)
+	if err := grpcServer.Start(); err != nil {
+		logger.Fatalf("Failed to serve the atomic broadcast service: %s", err)
+	}
+}
+
+// fileLedgerLocation returns the directory in which the file based ledgers store
+// their blocks, which is a new temporary directory if no location is configured
+func fileLedgerLocation(conf config.FileLedger) string {
+	if conf.Location != "" {
+		return conf.Location
+	}
+	location, err := ioutil.TempDir("", conf.Prefix)
+	if err != nil {
+		panic(fmt.Errorf("Error creating temp dir: %s", err))
+	}
+	return location
+}
+
+// prunePolicy returns the policy retaining the blocks that any of the limits of the prune
+// config retains, or nil if no limit is set
+func prunePolicy(conf config.Prune) ledger.PrunePolicy {
+	var policy ledger.KeepBlocksOfAnyPolicy
+	if conf.KeepLastBlocks > 0 {
+		policy = append(policy, &ledger.KeepLastNBlocksPolicy{N: conf.KeepLastBlocks})
+	}
+	if conf.KeepNewerThan > 0 {
+		policy = append(policy, &ledger.KeepBlocksForPolicy{D: conf.KeepNewerThan})
+	}
+	if len(policy) == 0 {
+		return nil
+	}
+	return policy
+}
+
+// newSecureServerConfig reads the PEM files referenced by the TLS section of
+// the orderer config into a comm.SecureServerConfig
+func newSecureServerConfig(conf config.TLS) (comm.SecureServerConfig, error) {
+	secureConfig := comm.SecureServerConfig{
+		UseTLS:            conf.Enabled,
+		RequireClientCert: conf.ClientAuthEnabled,
+	}
+	if !conf.Enabled {
+		return secureConfig, nil
+	}
+
+	var err error
+	if secureConfig.ServerCertificate, err = ioutil.ReadFile(conf.Certificate); err != nil {
+		return secureConfig, fmt.Errorf("Failed to read TLS certificate %s: %s", conf.Certificate, err)
+	}
+	if secureConfig.ServerKey, err = ioutil.ReadFile(conf.PrivateKey); err != nil {
+		return secureConfig, fmt.Errorf("Failed to read TLS private key %s: %s", conf.PrivateKey, err)
+	}
+	if secureConfig.ServerRootCAs, err = readPEMFiles(conf.RootCAs); err != nil {
+		return secureConfig, err
+	}
+	if secureConfig.ClientRootCAs, err = readPEMFiles(conf.ClientRootCAs); err != nil {
+		return secureConfig, err
+	}
+	return secureConfig, nil
+}
+
+func readPEMFiles(files []string) ([][]byte, error) {
+	pems := make([][]byte, len(files))
+	for i, file := range files {
+		pem, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read certificate %s: %s", file, err)
+		}
+		pems[i] = pem
+	}
+	return pems, nil
 }
@@ -0,0 +1,297 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/orderer/localconfig"
+)
+
+// testCA issues the certificates of the TLS tests
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Error generating key: %s", err)
+	}
+	template := certTemplate(name)
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Error creating CA certificate: %s", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Error parsing CA certificate: %s", err)
+	}
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns the PEM encoded certificate and key of a new TLS server or client
+func (ca *testCA) issue(t *testing.T, name string, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Error generating key: %s", err)
+	}
+	template := certTemplate(name)
+	template.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+	template.ExtKeyUsage = []x509.ExtKeyUsage{usage}
+	template.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("Error creating certificate: %s", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Error marshaling key: %s", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func certTemplate(name string) *x509.Certificate {
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+}
+
+func writeFile(t *testing.T, dir string, name string, data []byte) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("Error writing %s: %s", path, err)
+	}
+	return path
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "orderer-tls-test")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	return dir
+}
+
+func TestReadPEMFiles(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	first := writeFile(t, dir, "first.pem", []byte("first"))
+	second := writeFile(t, dir, "second.pem", []byte("second"))
+
+	pems, err := readPEMFiles([]string{first, second})
+	if err != nil {
+		t.Fatalf("Error reading PEM files: %s", err)
+	}
+	if len(pems) != 2 || string(pems[0]) != "first" || string(pems[1]) != "second" {
+		t.Fatalf("Read unexpected PEM files: %q", pems)
+	}
+
+	pems, err = readPEMFiles(nil)
+	if err != nil || len(pems) != 0 {
+		t.Fatalf("Expected no PEM files, got %q, %v", pems, err)
+	}
+
+	if _, err = readPEMFiles([]string{first, filepath.Join(dir, "missing.pem")}); err == nil {
+		t.Fatalf("Should have failed reading a missing PEM file")
+	}
+}
+
+func TestNewSecureServerConfigTLSDisabled(t *testing.T) {
+	secureConfig, err := newSecureServerConfig(config.TLS{Certificate: "missing.pem", PrivateKey: "missing.pem"})
+	if err != nil {
+		t.Fatalf("Should not read any file with TLS disabled: %s", err)
+	}
+	if secureConfig.UseTLS || secureConfig.ServerCertificate != nil || secureConfig.ServerKey != nil {
+		t.Fatalf("Unexpected secure config with TLS disabled: %+v", secureConfig)
+	}
+}
+
+func TestNewSecureServerConfig(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	conf := config.TLS{
+		Enabled:           true,
+		Certificate:       writeFile(t, dir, "cert.pem", []byte("cert")),
+		PrivateKey:        writeFile(t, dir, "key.pem", []byte("key")),
+		RootCAs:           []string{writeFile(t, dir, "root.pem", []byte("root"))},
+		ClientAuthEnabled: true,
+		ClientRootCAs:     []string{writeFile(t, dir, "clientroot.pem", []byte("clientroot"))},
+	}
+
+	secureConfig, err := newSecureServerConfig(conf)
+	if err != nil {
+		t.Fatalf("Error creating secure config: %s", err)
+	}
+	expected := comm.SecureServerConfig{
+		UseTLS:            true,
+		RequireClientCert: true,
+		ServerCertificate: []byte("cert"),
+		ServerKey:         []byte("key"),
+		ServerRootCAs:     [][]byte{[]byte("root")},
+		ClientRootCAs:     [][]byte{[]byte("clientroot")},
+	}
+	if !reflect.DeepEqual(secureConfig, expected) {
+		t.Fatalf("Expected secure config %+v, got %+v", expected, secureConfig)
+	}
+
+	missing := filepath.Join(dir, "missing.pem")
+	for name, badConf := range map[string]config.TLS{
+		"certificate":    {Enabled: true, Certificate: missing, PrivateKey: conf.PrivateKey},
+		"private key":    {Enabled: true, Certificate: conf.Certificate, PrivateKey: missing},
+		"root CA":        {Enabled: true, Certificate: conf.Certificate, PrivateKey: conf.PrivateKey, RootCAs: []string{missing}},
+		"client root CA": {Enabled: true, Certificate: conf.Certificate, PrivateKey: conf.PrivateKey, ClientAuthEnabled: true, ClientRootCAs: []string{missing}},
+	} {
+		if _, err := newSecureServerConfig(badConf); err == nil {
+			t.Errorf("Should have failed reading a missing %s", name)
+		}
+	}
+}
+
+func TestPrunePolicy(t *testing.T) {
+	if policy := prunePolicy(config.Prune{}); policy != nil {
+		t.Fatalf("Expected no prune policy without limits, got %v", policy)
+	}
+
+	policy := prunePolicy(config.Prune{KeepLastBlocks: 10, KeepNewerThan: time.Hour})
+	old := time.Now().Add(-2 * time.Hour)
+	for _, test := range []struct {
+		numNewerBlocks uint64
+		blockTime      time.Time
+		canPrune       bool
+	}{
+		{10, old, true},
+		{9, old, false},
+		{10, time.Now(), false},
+	} {
+		if canPrune := policy.CanPrune(test.numNewerBlocks, test.blockTime); canPrune != test.canPrune {
+			t.Errorf("Expected CanPrune to be %t for %d newer blocks and block time %s", test.canPrune, test.numNewerBlocks, test.blockTime)
+		}
+	}
+
+	policy = prunePolicy(config.Prune{KeepLastBlocks: 10})
+	if !reflect.DeepEqual(policy, ledger.KeepBlocksOfAnyPolicy{&ledger.KeepLastNBlocksPolicy{N: 10}}) {
+		t.Fatalf("Unexpected prune policy %v", policy)
+	}
+}
+
+// handshake connects to the server with the given client certificate, and
+// returns the error of the TLS handshake, which the client only learns from
+// the first read of the server's HTTP/2 settings frame with TLS 1.3
+func handshake(address string, rootCA []byte, clientCert []byte, clientKey []byte) error {
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(rootCA)
+	tlsConfig := &tls.Config{RootCAs: roots, ServerName: "127.0.0.1", NextProtos: []string{"h2"}}
+	if clientCert != nil {
+		cert, err := tls.X509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: time.Second}, "tcp", address, tlsConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err = conn.Write([]byte("PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n")); err != nil {
+		return err
+	}
+	_, err = conn.Read(make([]byte, 1))
+	return err
+}
+
+func TestTLSHandshake(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	serverCA := newTestCA(t, "serverCA")
+	clientCA := newTestCA(t, "clientCA")
+	otherCA := newTestCA(t, "otherCA")
+
+	serverCert, serverKey := serverCA.issue(t, "server", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := clientCA.issue(t, "client", x509.ExtKeyUsageClientAuth)
+	badClientCert, badClientKey := otherCA.issue(t, "badclient", x509.ExtKeyUsageClientAuth)
+
+	conf := config.TLS{
+		Enabled:       true,
+		Certificate:   writeFile(t, dir, "server-cert.pem", serverCert),
+		PrivateKey:    writeFile(t, dir, "server-key.pem", serverKey),
+		ClientRootCAs: []string{writeFile(t, dir, "client-ca.pem", clientCA.pem)},
+	}
+
+	for _, test := range []struct {
+		name              string
+		clientAuthEnabled bool
+		cert, key         []byte
+		succeeds          bool
+	}{
+		{"TLS without client certificate", false, nil, nil, true},
+		{"mutual TLS with good client certificate", true, clientCert, clientKey, true},
+		{"mutual TLS with client certificate of unknown CA", true, badClientCert, badClientKey, false},
+		{"mutual TLS without client certificate", true, nil, nil, false},
+	} {
+		conf.ClientAuthEnabled = test.clientAuthEnabled
+		secureConfig, err := newSecureServerConfig(conf)
+		if err != nil {
+			t.Fatalf("%s: error creating secure config: %s", test.name, err)
+		}
+		grpcServer, err := comm.NewGRPCServer("127.0.0.1:0", secureConfig)
+		if err != nil {
+			t.Fatalf("%s: error creating gRPC server: %s", test.name, err)
+		}
+		go grpcServer.Start()
+
+		err = handshake(grpcServer.Address(), serverCA.pem, test.cert, test.key)
+		grpcServer.Stop()
+		if test.succeeds && err != nil {
+			t.Errorf("%s: handshake should have succeeded: %s", test.name, err)
+		}
+		if !test.succeeds && err == nil {
+			t.Errorf("%s: handshake should have failed", test.name)
+		}
+	}
+}
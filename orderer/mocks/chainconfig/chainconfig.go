@@ -0,0 +1,28 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chainconfig
+
+// Descriptor is a mock implementation of chainconfig.Descriptor
+type Descriptor struct {
+	// HashingAlgorithmVal is returned as the result of HashingAlgorithm()
+	HashingAlgorithmVal func([]byte) []byte
+}
+
+// HashingAlgorithm returns the HashingAlgorithmVal
+func (d *Descriptor) HashingAlgorithm() func([]byte) []byte {
+	return d.HashingAlgorithmVal
+}
@@ -44,6 +44,9 @@ type ConsenterSupport struct {
 
 	// ChainIDVal is the value returned by ChainID()
 	ChainIDVal string
+
+	// HeightVal is the value returned by Height()
+	HeightVal uint64
 }
 
 // BlockCutter returns BlockCutterVal
@@ -67,10 +70,14 @@ func (mcs *ConsenterSupport) CreateNextBlock(data []*cb.Envelope) *cb.Block {
 	return block
 }
 
-// WriteBlock writes data to the Batches channel
+// WriteBlock writes data to the Batches channel and sets the ORDERER metadata of the block
 // Note that _committers is ignored by this mock implementation
-func (mcs *ConsenterSupport) WriteBlock(block *cb.Block, _committers []filter.Committer) *cb.Block {
+func (mcs *ConsenterSupport) WriteBlock(block *cb.Block, _committers []filter.Committer, encodedMetadataValue []byte) *cb.Block {
 	logger.Debugf("mockWriter: attempting to write batch")
+	if encodedMetadataValue != nil {
+		block.Metadata.Metadata[cb.BlockMetadataIndex_ORDERER] = utils.MarshalOrPanic(&cb.Metadata{Value: encodedMetadataValue})
+	}
+	mcs.HeightVal++
 	umtxs := make([]*cb.Envelope, len(block.Data.Data))
 	for i := range block.Data.Data {
 		umtxs[i] = utils.UnmarshalEnvelopeOrPanic(block.Data.Data[i])
@@ -84,12 +91,17 @@ func (mcs *ConsenterSupport) ChainID() string {
 	return mcs.ChainIDVal
 }
 
+// Height returns the number of blocks on the chain this specific consenter instance is associated with
+func (mcs *ConsenterSupport) Height() uint64 {
+	return mcs.HeightVal
+}
+
 // Sign returns the bytes passed in
-func (mcs *ConsenterSupport) Sign(message []byte) []byte {
-	return message
+func (mcs *ConsenterSupport) Sign(message []byte) ([]byte, error) {
+	return message, nil
 }
 
 // NewSignatureHeader returns an empty signature header
-func (mcs *ConsenterSupport) NewSignatureHeader() *cb.SignatureHeader {
-	return &cb.SignatureHeader{}
+func (mcs *ConsenterSupport) NewSignatureHeader() (*cb.SignatureHeader, error) {
+	return &cb.SignatureHeader{}, nil
 }
@@ -35,6 +35,8 @@ type Manager struct {
 	IngressPolicyVal string
 	// EgressPolicyVal is returned as the result of EgressPolicy()
 	EgressPolicyVal string
+	// SbftOptionsVal is returned as the result of SbftOptions()
+	SbftOptionsVal *ab.SbftOptions
 }
 
 // ConsensusType returns the ConsensusTypeVal
@@ -71,3 +73,8 @@ func (scm *Manager) IngressPolicy() string {
 func (scm *Manager) EgressPolicy() string {
 	return scm.EgressPolicyVal
 }
+
+// SbftOptions returns the SbftOptionsVal
+func (scm *Manager) SbftOptions() *ab.SbftOptions {
+	return scm.SbftOptionsVal
+}
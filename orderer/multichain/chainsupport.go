@@ -17,9 +17,15 @@ limitations under the License.
 package multichain
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric/common/chainconfig"
 	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/broadcast"
 	"github.com/hyperledger/fabric/orderer/common/filter"
@@ -36,7 +42,9 @@ type Consenter interface {
 	// HandleChain should create a return a reference to a Chain for the given set of resources
 	// It will only be invoked for a given chain once per process.  In general, errors will be treated
 	// as irrecoverable and cause system shutdown.  See the description of Chain for more details
-	HandleChain(support ConsenterSupport) (Chain, error)
+	// The metadata is the ORDERER block metadata of the last block of the chain, which allows the
+	// Chain to resume from where it left off
+	HandleChain(support ConsenterSupport, metadata *cb.Metadata) (Chain, error)
 }
 
 // Chain defines a way to inject messages for ordering
@@ -60,12 +68,21 @@ type Chain interface {
 
 // ConsenterSupport provides the resources available to a Consenter implementation
 type ConsenterSupport interface {
-	Signer
+	crypto.LocalSigner
 	BlockCutter() blockcutter.Receiver
 	SharedConfig() sharedconfig.Manager
 	CreateNextBlock(messages []*cb.Envelope) *cb.Block
-	WriteBlock(block *cb.Block, committers []filter.Committer) *cb.Block
+	WriteBlock(block *cb.Block, committers []filter.Committer, encodedMetadataValue []byte) *cb.Block
 	ChainID() string // ChainID returns the chain ID this specific consenter instance is associated with
+	Height() uint64  // Height returns the number of blocks on the chain this specific consenter instance is associated with
+}
+
+// NewBatchTimer returns a channel which fires once the batch timeout of the chain has elapsed.
+// The batch timeout is read as the timer is armed, as the configuration of the chain may have changed
+func NewBatchTimer(support ConsenterSupport) <-chan time.Time {
+	batchTimeout := support.SharedConfig().BatchTimeout()
+	logger.Debugf("Starting the %s batch timer of chain %s", batchTimeout, support.ChainID())
+	return time.After(batchTimeout)
 }
 
 // ChainSupport provides a wrapper for the resources backing a chain
@@ -84,6 +101,10 @@ type ChainSupport interface {
 
 	// ConfigTxManager returns the corresponding configtx.Manager for this chain
 	ConfigTxManager() configtx.Manager
+
+	// VerifySignature checks the signature in the supplied SignedData against the
+	// identity it carries, as deserialized by the MSP manager of this chain
+	VerifySignature(sd *cb.SignedData) error
 }
 
 type chainSupport struct {
@@ -92,9 +113,11 @@ type chainSupport struct {
 	configManager       configtx.Manager
 	policyManager       policies.Manager
 	sharedConfigManager sharedconfig.Manager
+	chainConfig         chainconfig.Descriptor
 	ledger              ordererledger.ReadWriter
 	filters             *filter.RuleSet
-	signer              Signer
+	mspManager          msp.Common
+	signer              crypto.LocalSigner
 	lastConfiguration   uint64
 	lastConfigSeq       uint64
 }
@@ -105,8 +128,10 @@ func newChainSupport(
 	policyManager policies.Manager,
 	backing ordererledger.ReadWriter,
 	sharedConfigManager sharedconfig.Manager,
+	chainConfig chainconfig.Descriptor,
+	mspManager msp.Common,
 	consenters map[string]Consenter,
-	signer Signer,
+	signer crypto.LocalSigner,
 ) *chainSupport {
 
 	cutter := blockcutter.NewReceiverImpl(sharedConfigManager, filters)
@@ -120,14 +145,21 @@ func newChainSupport(
 		configManager:       configManager,
 		policyManager:       policyManager,
 		sharedConfigManager: sharedConfigManager,
+		chainConfig:         chainConfig,
 		cutter:              cutter,
 		filters:             filters,
 		ledger:              backing,
+		mspManager:          mspManager,
 		signer:              signer,
 	}
 
-	var err error
-	cs.chain, err = consenter.HandleChain(cs)
+	metadata, err := ordererMetadata(ordererledger.GetBlock(cs.Reader(), cs.Reader().Height()-1))
+	if err != nil {
+		logger.Fatalf("Error extracting orderer metadata for chain %x: %s", configManager.ChainID(), err)
+	}
+	logger.Debugf("Retrieved metadata for tip of chain (block #%d): %+v", cs.Reader().Height()-1, metadata)
+
+	cs.chain, err = consenter.HandleChain(cs, metadata)
 	if err != nil {
 		logger.Fatalf("Error creating consenter for chain %x: %s", configManager.ChainID(), err)
 	}
@@ -135,11 +167,20 @@ func newChainSupport(
 	return cs
 }
 
+// ordererMetadata returns the ORDERER metadata of the given block, which is empty
+// for blocks which were not written by a consenter storing such metadata
+func ordererMetadata(block *cb.Block) (*cb.Metadata, error) {
+	if block == nil || block.Metadata == nil || len(block.Metadata.Metadata) <= int(cb.BlockMetadataIndex_ORDERER) {
+		return &cb.Metadata{}, nil
+	}
+	return utils.GetMetadataFromBlock(block, cb.BlockMetadataIndex_ORDERER)
+}
+
 // createStandardFilters creates the set of filters for a normal (non-system) chain
 func createStandardFilters(configManager configtx.Manager, policyManager policies.Manager, sharedConfig sharedconfig.Manager) *filter.RuleSet {
 	return filter.NewRuleSet([]filter.Rule{
 		filter.EmptyRejectRule,
-		sizefilter.MaxBytesRule(sharedConfig.BatchSize().AbsoluteMaxBytes),
+		sizefilter.MaxBytesRule(sharedConfig),
 		sigfilter.New(sharedConfig.IngressPolicy, policyManager),
 		configtx.NewFilter(configManager),
 		filter.AcceptRule,
@@ -151,7 +192,7 @@ func createStandardFilters(configManager configtx.Manager, policyManager policie
 func createSystemChainFilters(ml *multiLedger, configManager configtx.Manager, policyManager policies.Manager, sharedConfig sharedconfig.Manager) *filter.RuleSet {
 	return filter.NewRuleSet([]filter.Rule{
 		filter.EmptyRejectRule,
-		sizefilter.MaxBytesRule(sharedConfig.BatchSize().AbsoluteMaxBytes),
+		sizefilter.MaxBytesRule(sharedConfig),
 		sigfilter.New(sharedConfig.IngressPolicy, policyManager),
 		newSystemChainFilter(ml),
 		configtx.NewFilter(configManager),
@@ -163,14 +204,22 @@ func (cs *chainSupport) start() {
 	cs.chain.Start()
 }
 
-func (cs *chainSupport) NewSignatureHeader() *cb.SignatureHeader {
+func (cs *chainSupport) NewSignatureHeader() (*cb.SignatureHeader, error) {
 	return cs.signer.NewSignatureHeader()
 }
 
-func (cs *chainSupport) Sign(message []byte) []byte {
+func (cs *chainSupport) Sign(message []byte) ([]byte, error) {
 	return cs.signer.Sign(message)
 }
 
+func (cs *chainSupport) VerifySignature(sd *cb.SignedData) error {
+	identity, err := cs.mspManager.DeserializeIdentity(sd.Identity)
+	if err != nil {
+		return fmt.Errorf("Failed to deserialize signer identity: %s", err)
+	}
+	return identity.Verify(sd.Data, sd.Signature)
+}
+
 func (cs *chainSupport) SharedConfig() sharedconfig.Manager {
 	return cs.sharedConfigManager
 }
@@ -199,26 +248,42 @@ func (cs *chainSupport) Reader() ordererledger.Reader {
 	return cs.ledger
 }
 
+func (cs *chainSupport) Height() uint64 {
+	return cs.Reader().Height()
+}
+
 func (cs *chainSupport) Enqueue(env *cb.Envelope) bool {
 	return cs.chain.Enqueue(env)
 }
 
 func (cs *chainSupport) CreateNextBlock(messages []*cb.Envelope) *cb.Block {
-	return ordererledger.CreateNextBlock(cs.ledger, messages)
+	return ordererledger.CreateNextBlockWithHashingAlgorithm(cs.ledger, messages, cs.chainConfig.HashingAlgorithm())
 }
 
-func (cs *chainSupport) addBlockSignature(block *cb.Block) {
-	logger.Debugf("%+v", cs)
-	logger.Debugf("%+v", cs.signer)
-	blockSignature := &cb.MetadataSignature{
-		SignatureHeader: utils.MarshalOrPanic(cs.signer.NewSignatureHeader()),
+func (cs *chainSupport) newMetadataSignature(value []byte, block *cb.Block) *cb.MetadataSignature {
+	signatureHeader, err := cs.signer.NewSignatureHeader()
+	if err != nil {
+		logger.Panicf("Could not create signature header for block %d: %s", block.Header.Number, err)
+	}
+
+	metadataSignature := &cb.MetadataSignature{
+		SignatureHeader: utils.MarshalOrPanic(signatureHeader),
+	}
+
+	metadataSignature.Signature, err = cs.signer.Sign(util.ConcatenateBytes(value, metadataSignature.SignatureHeader, block.Header.Bytes()))
+	if err != nil {
+		logger.Panicf("Could not sign metadata for block %d: %s", block.Header.Number, err)
 	}
 
+	return metadataSignature
+}
+
+func (cs *chainSupport) addBlockSignature(block *cb.Block) {
 	// Note, this value is intentionally nil, as this metadata is only about the signature, there is no additional metadata
 	// information required beyond the fact that the metadata item is signed.
 	blockSignatureValue := []byte(nil)
 
-	blockSignature.Signature = cs.signer.Sign(util.ConcatenateBytes(blockSignatureValue, blockSignature.SignatureHeader, block.Header.Bytes()))
+	blockSignature := cs.newMetadataSignature(blockSignatureValue, block)
 
 	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(&cb.Metadata{
 		Value: blockSignatureValue,
@@ -235,13 +300,9 @@ func (cs *chainSupport) addLastConfigSignature(block *cb.Block) {
 		cs.lastConfigSeq = configSeq
 	}
 
-	lastConfigSignature := &cb.MetadataSignature{
-		SignatureHeader: utils.MarshalOrPanic(cs.signer.NewSignatureHeader()),
-	}
-
 	lastConfigValue := utils.MarshalOrPanic(&cb.LastConfiguration{Index: cs.lastConfiguration})
 
-	lastConfigSignature.Signature = cs.signer.Sign(util.ConcatenateBytes(lastConfigValue, lastConfigSignature.SignatureHeader, block.Header.Bytes()))
+	lastConfigSignature := cs.newMetadataSignature(lastConfigValue, block)
 
 	block.Metadata.Metadata[cb.BlockMetadataIndex_LAST_CONFIGURATION] = utils.MarshalOrPanic(&cb.Metadata{
 		Value: lastConfigValue,
@@ -251,11 +312,19 @@ func (cs *chainSupport) addLastConfigSignature(block *cb.Block) {
 	})
 }
 
-func (cs *chainSupport) WriteBlock(block *cb.Block, committers []filter.Committer) *cb.Block {
+// WriteBlock commits the block to the ledger, after applying the committers and signing it.
+// If encodedMetadataValue is non-nil, it is stored in the ORDERER metadata of the block, where
+// the consenter can retrieve it through HandleChain after a restart
+func (cs *chainSupport) WriteBlock(block *cb.Block, committers []filter.Committer, encodedMetadataValue []byte) *cb.Block {
 	for _, committer := range committers {
 		committer.Commit()
 	}
 
+	// Set the orderer-related metadata field
+	if encodedMetadataValue != nil {
+		block.Metadata.Metadata[cb.BlockMetadataIndex_ORDERER] = utils.MarshalOrPanic(&cb.Metadata{Value: encodedMetadataValue})
+	}
+
 	cs.addBlockSignature(block)
 	cs.addLastConfigSignature(block)
 
@@ -20,14 +20,44 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/hyperledger/fabric/common/localmsp"
+	"github.com/hyperledger/fabric/common/util"
+	mspmgmt "github.com/hyperledger/fabric/core/peer/msp"
+	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/orderer/common/filter"
 	ordererledger "github.com/hyperledger/fabric/orderer/ledger"
+	mockchainconfig "github.com/hyperledger/fabric/orderer/mocks/chainconfig"
 	mockconfigtx "github.com/hyperledger/fabric/orderer/mocks/configtx"
 	cb "github.com/hyperledger/fabric/protos/common"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/utils"
+
+	"github.com/golang/protobuf/proto"
 )
 
+const localMSPDir = "../../msp/sampleconfig/"
+
+func init() {
+	if err := mspmgmt.LoadLocalMsp(localMSPDir); err != nil {
+		panic(err)
+	}
+}
+
+// newVerifyingMSPManager returns an MSP manager which knows nothing but the sample MSP configuration,
+// and therefore shares no state with the local MSP which is used for signing
+func newVerifyingMSPManager(t *testing.T) msp.MSPManager {
+	conf, err := msp.GetLocalMspConfig(localMSPDir)
+	if err != nil {
+		t.Fatalf("Error loading MSP config: %s", err)
+	}
+	mspManager := msp.NewMSPManager()
+	if err := mspManager.Setup([]*mspprotos.MSPConfig{conf}); err != nil {
+		t.Fatalf("Error setting up MSP manager: %s", err)
+	}
+	return mspManager
+}
+
 type mockLedgerReadWriter struct {
 	data     [][]byte
 	metadata [][]byte
@@ -64,11 +94,12 @@ func (mc *mockCommitter) Commit() {
 func TestCommitConfig(t *testing.T) {
 	ml := &mockLedgerReadWriter{}
 	cm := &mockconfigtx.Manager{}
-	cs := &chainSupport{ledger: ml, configManager: cm, signer: &xxxCryptoHelper{}}
+	cc := &mockchainconfig.Descriptor{HashingAlgorithmVal: util.ComputeCryptoHash}
+	cs := &chainSupport{ledger: ml, configManager: cm, chainConfig: cc, signer: &mockCrypto{}}
 	txs := []*cb.Envelope{makeNormalTx("foo", 0), makeNormalTx("bar", 1)}
 	committers := []filter.Committer{&mockCommitter{}, &mockCommitter{}}
 	block := cs.CreateNextBlock(txs)
-	cs.WriteBlock(block, committers)
+	cs.WriteBlock(block, committers, nil)
 
 	blockTXs := make([]*cb.Envelope, len(ml.data))
 	for i := range ml.data {
@@ -89,7 +120,7 @@ func TestCommitConfig(t *testing.T) {
 func TestWriteBlockSignatures(t *testing.T) {
 	ml := &mockLedgerReadWriter{}
 	cm := &mockconfigtx.Manager{}
-	cs := &chainSupport{ledger: ml, configManager: cm, signer: &xxxCryptoHelper{}}
+	cs := &chainSupport{ledger: ml, configManager: cm, signer: &mockCrypto{}}
 
 	blockMetadata := func(block *cb.Block) *cb.Metadata {
 		metadata, err := utils.GetMetadataFromBlock(block, cb.BlockMetadataIndex_SIGNATURES)
@@ -99,7 +130,7 @@ func TestWriteBlockSignatures(t *testing.T) {
 		return metadata
 	}
 
-	if blockMetadata(cs.WriteBlock(cb.NewBlock(0, nil), nil)) == nil {
+	if blockMetadata(cs.WriteBlock(cb.NewBlock(0, nil), nil, nil)) == nil {
 		t.Fatalf("Block should have block signature")
 	}
 }
@@ -107,7 +138,7 @@ func TestWriteBlockSignatures(t *testing.T) {
 func TestWriteLastConfiguration(t *testing.T) {
 	ml := &mockLedgerReadWriter{}
 	cm := &mockconfigtx.Manager{}
-	cs := &chainSupport{ledger: ml, configManager: cm, signer: &xxxCryptoHelper{}}
+	cs := &chainSupport{ledger: ml, configManager: cm, signer: &mockCrypto{}}
 
 	lastConfig := func(block *cb.Block) uint64 {
 		index, err := utils.GetLastConfigurationIndexFromBlock(block)
@@ -118,22 +149,125 @@ func TestWriteLastConfiguration(t *testing.T) {
 	}
 
 	expected := uint64(0)
-	if lc := lastConfig(cs.WriteBlock(cb.NewBlock(0, nil), nil)); lc != expected {
+	if lc := lastConfig(cs.WriteBlock(cb.NewBlock(0, nil), nil, nil)); lc != expected {
 		t.Fatalf("First block should have config block index of %d, but got %d", expected, lc)
 	}
 
-	if lc := lastConfig(cs.WriteBlock(cb.NewBlock(1, nil), nil)); lc != expected {
+	if lc := lastConfig(cs.WriteBlock(cb.NewBlock(1, nil), nil, nil)); lc != expected {
 		t.Fatalf("Second block should have config block index of %d, but got %d", expected, lc)
 	}
 
 	cm.SequenceVal = 1
 	expected = uint64(2)
-	if lc := lastConfig(cs.WriteBlock(cb.NewBlock(2, nil), nil)); lc != expected {
+	if lc := lastConfig(cs.WriteBlock(cb.NewBlock(2, nil), nil, nil)); lc != expected {
 		t.Fatalf("Second block should have config block index of %d, but got %d", expected, lc)
 	}
 
-	if lc := lastConfig(cs.WriteBlock(cb.NewBlock(3, nil), nil)); lc != expected {
+	if lc := lastConfig(cs.WriteBlock(cb.NewBlock(3, nil), nil, nil)); lc != expected {
 		t.Fatalf("Second block should have config block index of %d, but got %d", expected, lc)
 	}
 
 }
+
+func TestWriteOrdererMetadata(t *testing.T) {
+	ml := &mockLedgerReadWriter{}
+	cm := &mockconfigtx.Manager{}
+	cs := &chainSupport{ledger: ml, configManager: cm, signer: &mockCrypto{}}
+
+	metadata, err := ordererMetadata(cs.WriteBlock(cb.NewBlock(0, nil), nil, nil))
+	if err != nil {
+		t.Fatalf("Error retrieving orderer metadata: %s", err)
+	}
+	if metadata.Value != nil {
+		t.Fatalf("Block should not have orderer metadata but got %v", metadata.Value)
+	}
+
+	expected := []byte("foo")
+	metadata, err = ordererMetadata(cs.WriteBlock(cb.NewBlock(1, nil), nil, expected))
+	if err != nil {
+		t.Fatalf("Error retrieving orderer metadata: %s", err)
+	}
+	if !reflect.DeepEqual(metadata.Value, expected) {
+		t.Fatalf("Block should have orderer metadata %v but got %v", expected, metadata.Value)
+	}
+}
+
+func TestOrdererMetadataMissing(t *testing.T) {
+	for _, block := range []*cb.Block{nil, {}, {Metadata: &cb.BlockMetadata{Metadata: [][]byte{{}, {}}}}} {
+		metadata, err := ordererMetadata(block)
+		if err != nil {
+			t.Fatalf("Error retrieving orderer metadata from %v: %s", block, err)
+		}
+		if metadata.Value != nil {
+			t.Fatalf("Expected empty orderer metadata for %v but got %v", block, metadata.Value)
+		}
+	}
+}
+
+func TestBlockSignatureVerifiable(t *testing.T) {
+	ml := &mockLedgerReadWriter{}
+	cm := &mockconfigtx.Manager{}
+	cs := &chainSupport{ledger: ml, configManager: cm, signer: localmsp.NewSigner()}
+
+	block := cs.WriteBlock(cb.NewBlock(0, nil), nil, nil)
+
+	for _, index := range []cb.BlockMetadataIndex{cb.BlockMetadataIndex_SIGNATURES, cb.BlockMetadataIndex_LAST_CONFIGURATION} {
+		metadata, err := utils.GetMetadataFromBlock(block, index)
+		if err != nil {
+			t.Fatalf("Error retrieving metadata at index %v: %s", index, err)
+		}
+
+		if len(metadata.Signatures) != 1 {
+			t.Fatalf("Expected exactly one signature at index %v but got %d", index, len(metadata.Signatures))
+		}
+
+		metadataSignature := metadata.Signatures[0]
+		signatureHeader := &cb.SignatureHeader{}
+		if err := proto.Unmarshal(metadataSignature.SignatureHeader, signatureHeader); err != nil {
+			t.Fatalf("Error unmarshaling signature header: %s", err)
+		}
+
+		identity, err := newVerifyingMSPManager(t).DeserializeIdentity(signatureHeader.Creator)
+		if err != nil {
+			t.Fatalf("Error deserializing block signer: %s", err)
+		}
+
+		signedBytes := util.ConcatenateBytes(metadata.Value, metadataSignature.SignatureHeader, block.Header.Bytes())
+		if err := identity.Verify(signedBytes, metadataSignature.Signature); err != nil {
+			t.Fatalf("Signature at index %v should have verified: %s", index, err)
+		}
+
+		block.Header.Number++
+		if err := identity.Verify(util.ConcatenateBytes(metadata.Value, metadataSignature.SignatureHeader, block.Header.Bytes()), metadataSignature.Signature); err == nil {
+			t.Fatalf("Signature at index %v should not have verified for a modified header", index)
+		}
+		block.Header.Number--
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	cs := &chainSupport{mspManager: newVerifyingMSPManager(t), signer: localmsp.NewSigner()}
+
+	signatureHeader, err := cs.NewSignatureHeader()
+	if err != nil {
+		t.Fatalf("Error creating signature header: %s", err)
+	}
+
+	data := []byte("foo")
+	signature, err := cs.Sign(data)
+	if err != nil {
+		t.Fatalf("Error signing: %s", err)
+	}
+
+	if err := cs.VerifySignature(&cb.SignedData{Data: data, Identity: signatureHeader.Creator, Signature: signature}); err != nil {
+		t.Fatalf("Signature should have verified: %s", err)
+	}
+
+	if err := cs.VerifySignature(&cb.SignedData{Data: []byte("bar"), Identity: signatureHeader.Creator, Signature: signature}); err == nil {
+		t.Fatalf("Signature should not have verified over different data")
+	}
+
+	if err := cs.VerifySignature(&cb.SignedData{Data: data, Identity: []byte("garbage"), Signature: signature}); err == nil {
+		t.Fatalf("Signature should not have verified for an unknown identity")
+	}
+}
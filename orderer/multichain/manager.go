@@ -20,8 +20,12 @@ import (
 	"fmt"
 
 	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/chainconfig"
 	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/policies"
+	mspmgmt "github.com/hyperledger/fabric/core/peer/msp"
+	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/orderer/common/sharedconfig"
 	ordererledger "github.com/hyperledger/fabric/orderer/ledger"
 	cb "github.com/hyperledger/fabric/protos/common"
@@ -33,31 +37,6 @@ import (
 
 var logger = logging.MustGetLogger("orderer/multichain")
 
-// XXX This crypto helper is a stand in until we have a real crypto handler
-// it considers all signatures to be valid
-type xxxCryptoHelper struct{}
-
-func (xxx xxxCryptoHelper) VerifySignature(sd *cb.SignedData) error {
-	return nil
-}
-
-func (xxx xxxCryptoHelper) NewSignatureHeader() *cb.SignatureHeader {
-	return &cb.SignatureHeader{}
-}
-
-func (xxx xxxCryptoHelper) Sign(message []byte) []byte {
-	return message
-}
-
-// Signer is a temporary stub interface which will be implemented by the local MSP
-type Signer interface {
-	// NewSignatureHeader creates a SignatureHeader with the correct signing identity and a valid nonce
-	NewSignatureHeader() *cb.SignatureHeader
-
-	// Sign a message which should embed a signature header created by NewSignatureHeader
-	Sign(message []byte) []byte
-}
-
 // Manager coordinates the creation and access of chains
 type Manager interface {
 	// GetChain retrieves the chain support for a chain (and whether it exists)
@@ -74,6 +53,7 @@ type multiLedger struct {
 	consenters    map[string]Consenter
 	ledgerFactory ordererledger.Factory
 	sysChain      *systemChain
+	signer        crypto.LocalSigner
 }
 
 func getConfigTx(reader ordererledger.Reader) *cb.Envelope {
@@ -91,11 +71,12 @@ func getConfigTx(reader ordererledger.Reader) *cb.Envelope {
 }
 
 // NewManagerImpl produces an instance of a Manager
-func NewManagerImpl(ledgerFactory ordererledger.Factory, consenters map[string]Consenter) Manager {
+func NewManagerImpl(ledgerFactory ordererledger.Factory, consenters map[string]Consenter, signer crypto.LocalSigner) Manager {
 	ml := &multiLedger{
 		chains:        make(map[string]*chainSupport),
 		ledgerFactory: ledgerFactory,
 		consenters:    consenters,
+		signer:        signer,
 	}
 
 	existingChains := ledgerFactory.ChainIDs()
@@ -108,7 +89,7 @@ func NewManagerImpl(ledgerFactory ordererledger.Factory, consenters map[string]C
 		if configTx == nil {
 			logger.Fatalf("Could not find configuration transaction for chain %s", chainID)
 		}
-		configManager, policyManager, backingLedger, sharedConfigManager := ml.newResources(configTx)
+		configManager, policyManager, backingLedger, sharedConfigManager, chainConfig, mspManager := ml.newResources(configTx)
 		chainID := configManager.ChainID()
 
 		if sharedConfigManager.ChainCreators() != nil {
@@ -121,8 +102,10 @@ func NewManagerImpl(ledgerFactory ordererledger.Factory, consenters map[string]C
 				policyManager,
 				backingLedger,
 				sharedConfigManager,
+				chainConfig,
+				mspManager,
 				consenters,
-				&xxxCryptoHelper{})
+				signer)
 			ml.chains[string(chainID)] = chain
 			ml.sysChain = newSystemChain(chain)
 			// We delay starting this chain, as it might try to copy and replace the chains map via newChain before the map is fully built
@@ -134,8 +117,10 @@ func NewManagerImpl(ledgerFactory ordererledger.Factory, consenters map[string]C
 				policyManager,
 				backingLedger,
 				sharedConfigManager,
+				chainConfig,
+				mspManager,
 				consenters,
-				&xxxCryptoHelper{})
+				signer)
 			ml.chains[string(chainID)] = chain
 			chain.start()
 		}
@@ -158,7 +143,8 @@ func (ml *multiLedger) GetChain(chainID string) (ChainSupport, bool) {
 	return cs, ok
 }
 
-func newConfigTxManagerAndHandlers(configEnvelope *cb.ConfigurationEnvelope) (configtx.Manager, policies.Manager, sharedconfig.Manager, error) {
+func newConfigTxManagerAndHandlers(configEnvelope *cb.ConfigurationEnvelope) (configtx.Manager, policies.Manager, sharedconfig.Manager, chainconfig.Descriptor, msp.Common, error) {
+	mspConfigHandler := &mspmgmt.MSPConfigHandler{}
 	policyProviderMap := make(map[int32]policies.Provider)
 	for pType := range cb.Policy_PolicyType_name {
 		rtype := cb.Policy_PolicyType(pType)
@@ -166,22 +152,26 @@ func newConfigTxManagerAndHandlers(configEnvelope *cb.ConfigurationEnvelope) (co
 		case cb.Policy_UNKNOWN:
 			// Do not register a handler
 		case cb.Policy_SIGNATURE:
-			policyProviderMap[pType] = cauthdsl.NewPolicyProvider(
-				cauthdsl.NewMockDeserializer()) // FIXME: here we should pass in the orderer MSP as soon as it's ready
+			policyProviderMap[pType] = cauthdsl.NewPolicyProvider(mspConfigHandler)
 		case cb.Policy_MSP:
 			// Add hook for MSP Handler here
 		}
 	}
 	policyManager := policies.NewManagerImpl(policyProviderMap)
 	sharedConfigManager := sharedconfig.NewManagerImpl()
+	chainConfig := chainconfig.NewDescriptorImpl()
 	configHandlerMap := make(map[cb.ConfigurationItem_ConfigurationType]configtx.Handler)
 	for ctype := range cb.ConfigurationItem_ConfigurationType_name {
 		rtype := cb.ConfigurationItem_ConfigurationType(ctype)
 		switch rtype {
 		case cb.ConfigurationItem_Policy:
 			configHandlerMap[rtype] = policyManager
+		case cb.ConfigurationItem_Chain:
+			configHandlerMap[rtype] = chainConfig
 		case cb.ConfigurationItem_Orderer:
 			configHandlerMap[rtype] = sharedConfigManager
+		case cb.ConfigurationItem_MSP:
+			configHandlerMap[rtype] = mspConfigHandler
 		default:
 			configHandlerMap[rtype] = configtx.NewBytesHandler()
 		}
@@ -189,13 +179,17 @@ func newConfigTxManagerAndHandlers(configEnvelope *cb.ConfigurationEnvelope) (co
 
 	configManager, err := configtx.NewConfigurationManager(configEnvelope, policyManager, configHandlerMap)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("Error unpacking configuration transaction: %s", err)
+		return nil, nil, nil, nil, nil, fmt.Errorf("Error unpacking configuration transaction: %s", err)
+	}
+
+	if err = chainConfig.Validate(); err != nil {
+		return nil, nil, nil, nil, nil, err
 	}
 
-	return configManager, policyManager, sharedConfigManager, nil
+	return configManager, policyManager, sharedConfigManager, chainConfig, mspConfigHandler, nil
 }
 
-func (ml *multiLedger) newResources(configTx *cb.Envelope) (configtx.Manager, policies.Manager, ordererledger.ReadWriter, sharedconfig.Manager) {
+func (ml *multiLedger) newResources(configTx *cb.Envelope) (configtx.Manager, policies.Manager, ordererledger.ReadWriter, sharedconfig.Manager, chainconfig.Descriptor, msp.Common) {
 	payload := &cb.Payload{}
 	err := proto.Unmarshal(configTx.Payload, payload)
 	if err != nil {
@@ -208,7 +202,7 @@ func (ml *multiLedger) newResources(configTx *cb.Envelope) (configtx.Manager, po
 		logger.Fatalf("Error unmarshaling a config transaction to config envelope: %s", err)
 	}
 
-	configManager, policyManager, sharedConfigManager, err := newConfigTxManagerAndHandlers(configEnvelope)
+	configManager, policyManager, sharedConfigManager, chainConfig, mspManager, err := newConfigTxManagerAndHandlers(configEnvelope)
 
 	if err != nil {
 		logger.Fatalf("Error creating configtx manager and handlers: %s", err)
@@ -221,7 +215,7 @@ func (ml *multiLedger) newResources(configTx *cb.Envelope) (configtx.Manager, po
 		logger.Fatalf("Error getting ledger for %s", chainID)
 	}
 
-	return configManager, policyManager, ledger, sharedConfigManager
+	return configManager, policyManager, ledger, sharedConfigManager, chainConfig, mspManager
 }
 
 func (ml *multiLedger) systemChain() *systemChain {
@@ -229,8 +223,8 @@ func (ml *multiLedger) systemChain() *systemChain {
 }
 
 func (ml *multiLedger) newChain(configtx *cb.Envelope) {
-	configManager, policyManager, backingLedger, sharedConfig := ml.newResources(configtx)
-	backingLedger.Append(ordererledger.CreateNextBlock(backingLedger, []*cb.Envelope{configtx}))
+	configManager, policyManager, backingLedger, sharedConfig, chainConfig, mspManager := ml.newResources(configtx)
+	backingLedger.Append(ordererledger.CreateNextBlockWithHashingAlgorithm(backingLedger, []*cb.Envelope{configtx}, chainConfig.HashingAlgorithm()))
 
 	// Copy the map to allow concurrent reads from broadcast/deliver while the new chainSupport is
 	newChains := make(map[string]*chainSupport)
@@ -238,7 +232,7 @@ func (ml *multiLedger) newChain(configtx *cb.Envelope) {
 		newChains[key] = value
 	}
 
-	cs := newChainSupport(createStandardFilters(configManager, policyManager, sharedConfig), configManager, policyManager, backingLedger, sharedConfig, ml.consenters, &xxxCryptoHelper{})
+	cs := newChainSupport(createStandardFilters(configManager, policyManager, sharedConfig), configManager, policyManager, backingLedger, sharedConfig, chainConfig, mspManager, ml.consenters, ml.signer)
 	chainID := configManager.ChainID()
 
 	logger.Debugf("Created and starting new chain %s", chainID)
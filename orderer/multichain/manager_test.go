@@ -17,12 +17,17 @@ limitations under the License.
 package multichain
 
 import (
+	"bytes"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/hyperledger/fabric/common/chainconfig"
 	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/genesis"
+	"github.com/hyperledger/fabric/common/localmsp"
 	"github.com/hyperledger/fabric/orderer/common/bootstrap/provisional"
+	"github.com/hyperledger/fabric/orderer/common/sharedconfig"
 	ordererledger "github.com/hyperledger/fabric/orderer/ledger"
 	ramledger "github.com/hyperledger/fabric/orderer/ledger/ram"
 	"github.com/hyperledger/fabric/orderer/localconfig"
@@ -109,7 +114,7 @@ func TestManagerImpl(t *testing.T) {
 	consenters := make(map[string]Consenter)
 	consenters[conf.Genesis.OrdererType] = &mockConsenter{}
 
-	manager := NewManagerImpl(lf, consenters)
+	manager := NewManagerImpl(lf, consenters, &mockCrypto{})
 
 	_, ok := manager.GetChain("Fake")
 	if ok {
@@ -155,7 +160,7 @@ func TestSignatureFilter(t *testing.T) {
 	consenters := make(map[string]Consenter)
 	consenters[conf.Genesis.OrdererType] = &mockConsenter{}
 
-	manager := NewManagerImpl(lf, consenters)
+	manager := NewManagerImpl(lf, consenters, &mockCrypto{})
 
 	cs, ok := manager.GetChain(provisional.TestChainID)
 
@@ -193,7 +198,7 @@ func TestNewChain(t *testing.T) {
 	consenters := make(map[string]Consenter)
 	consenters[conf.Genesis.OrdererType] = &mockConsenter{}
 
-	manager := NewManagerImpl(lf, consenters)
+	manager := NewManagerImpl(lf, consenters, &mockCrypto{})
 
 	generator := provisional.New(conf)
 	items := generator.TemplateItems()
@@ -280,3 +285,202 @@ func TestNewChain(t *testing.T) {
 		t.Fatalf("Block 1 not produced after timeout on new chain")
 	}
 }
+
+// This test makes sure that the MSP configuration of a chain is used to resolve the identities behind signatures
+func TestChainMSPManager(t *testing.T) {
+	items, err := configtx.NewCompositeTemplate(
+		configtx.NewSimpleTemplate(provisional.New(conf).TemplateItems()...),
+		configtx.NewSimpleTemplate(utils.EncodeMSPUnsigned(provisional.TestChainID)),
+	).Items(provisional.TestChainID)
+	if err != nil {
+		t.Fatalf("Error creating configuration items: %s", err)
+	}
+
+	_, _, _, _, mspManager, err := newConfigTxManagerAndHandlers(&cb.ConfigurationEnvelope{Items: items})
+	if err != nil {
+		t.Fatalf("Error creating configuration manager and handlers: %s", err)
+	}
+
+	signatureHeader, err := localmsp.NewSigner().NewSignatureHeader()
+	if err != nil {
+		t.Fatalf("Error creating signature header: %s", err)
+	}
+
+	if _, err := mspManager.DeserializeIdentity(signatureHeader.Creator); err != nil {
+		t.Fatalf("Chain MSP manager should have recognized the local signing identity: %s", err)
+	}
+
+	_, _, _, _, mspManager, err = newConfigTxManagerAndHandlers(&cb.ConfigurationEnvelope{Items: items[:len(items)-1]})
+	if err != nil {
+		t.Fatalf("Error creating configuration manager and handlers: %s", err)
+	}
+
+	if _, err := mspManager.DeserializeIdentity(signatureHeader.Creator); err == nil {
+		t.Fatalf("Chain MSP manager should not have recognized any identity without MSP configuration")
+	}
+}
+
+// modifiableTemplateItems returns the provisional configuration items with every item modifiable,
+// as the provisional defaults reject any modification
+func modifiableTemplateItems() []*cb.ConfigurationItem {
+	items := provisional.New(conf).TemplateItems()
+	for _, item := range items {
+		item.ModificationPolicy = provisional.AcceptAllPolicyKey
+	}
+	return items
+}
+
+// newChainFromItems starts a standard chain, with the mock consenter, from a genesis block carrying the given items
+func newChainFromItems(t *testing.T, chainID string, items []*cb.ConfigurationItem) (ChainSupport, ordererledger.ReadWriter) {
+	configBlock, err := genesis.NewFactoryImpl(configtx.NewSimpleTemplate(items...)).Block(chainID)
+	if err != nil {
+		t.Fatalf("Error creating genesis block: %s", err)
+	}
+	lf := ramledger.New(10)
+	rl, err := lf.GetOrCreate(chainID)
+	if err != nil {
+		t.Fatalf("Error creating ledger: %s", err)
+	}
+	rl.Append(configBlock)
+
+	consenters := make(map[string]Consenter)
+	consenters[conf.Genesis.OrdererType] = &mockConsenter{}
+
+	manager := NewManagerImpl(lf, consenters, &mockCrypto{})
+
+	cs, ok := manager.GetChain(chainID)
+	if !ok {
+		t.Fatalf("Should have gotten chain which was initialized by ramledger")
+	}
+	return cs, rl
+}
+
+// setHashingAlgorithm sets the value of the HashingAlgorithm item among the given items
+func setHashingAlgorithm(items []*cb.ConfigurationItem, name string, lastModified uint64) {
+	for _, item := range items {
+		if item.Type == cb.ConfigurationItem_Chain && item.Key == chainconfig.HashingAlgorithmKey {
+			item.Value = utils.MarshalOrPanic(&cb.HashingAlgorithm{Name: name})
+			item.LastModified = lastModified
+		}
+	}
+}
+
+// This test makes sure that a configuration transaction on an existing chain is cut into its own block,
+// and that the blocks which follow it are cut according to the updated configuration
+func TestConfigUpdate(t *testing.T) {
+	chainID := "TestConfigUpdate"
+	items := modifiableTemplateItems()
+	cs, rl := newChainFromItems(t, chainID, items)
+
+	newMaxMessageCount := uint32(2)
+	for _, item := range items {
+		if item.Type == cb.ConfigurationItem_Orderer && item.Key == sharedconfig.BatchSizeKey {
+			item.Value = utils.MarshalOrPanic(&ab.BatchSize{
+				MaxMessageCount:   newMaxMessageCount,
+				AbsoluteMaxBytes:  conf.Genesis.BatchSize.AbsoluteMaxBytes,
+				PreferredMaxBytes: conf.Genesis.BatchSize.PreferredMaxBytes,
+			})
+			item.LastModified = 1
+		}
+	}
+	configUpdate := makeConfigTxWithItems(chainID, items...)
+
+	cs.Enqueue(makeNormalTx(chainID, 0))
+	cs.Enqueue(makeNormalTx(chainID, 1))
+	cs.Enqueue(configUpdate)
+	for i := 2; i < 6; i++ {
+		cs.Enqueue(makeNormalTx(chainID, i))
+	}
+
+	// Causes the consenter thread to exit after it processes all messages
+	close(cs.(*chainSupport).chain.(*mockChain).queue)
+	<-cs.(*chainSupport).chain.(*mockChain).done
+
+	expected := [][]*cb.Envelope{
+		{makeNormalTx(chainID, 0), makeNormalTx(chainID, 1)},
+		{configUpdate},
+		{makeNormalTx(chainID, 2), makeNormalTx(chainID, 3)},
+		{makeNormalTx(chainID, 4), makeNormalTx(chainID, 5)},
+	}
+
+	if rl.Height() != uint64(len(expected)+1) {
+		t.Fatalf("Expected %d blocks, got %d", len(expected)+1, rl.Height())
+	}
+
+	for i, envs := range expected {
+		block := ordererledger.GetBlock(rl, uint64(i+1))
+		if len(block.Data.Data) != len(envs) {
+			t.Fatalf("Block %d should have had %d messages, got %d", i+1, len(envs), len(block.Data.Data))
+		}
+		for j, env := range envs {
+			if !reflect.DeepEqual(utils.ExtractEnvelopeOrPanic(block, j), env) {
+				t.Errorf("Block %d contents wrong at index %d", i+1, j)
+			}
+		}
+	}
+
+	if cs.SharedConfig().BatchSize().MaxMessageCount != newMaxMessageCount {
+		t.Errorf("Shared configuration should reflect the new batch size, got %d messages", cs.SharedConfig().BatchSize().MaxMessageCount)
+	}
+
+	if cs.ConfigTxManager().Sequence() != 1 {
+		t.Errorf("Configuration sequence should have advanced to 1, got %d", cs.ConfigTxManager().Sequence())
+	}
+}
+
+// This test makes sure that blocks are hashed and chained with the hashing algorithm of the chain configuration
+func TestConfiguredHashingAlgorithm(t *testing.T) {
+	chainID := "TestConfiguredHashingAlgorithm"
+	items := modifiableTemplateItems()
+	setHashingAlgorithm(items, chainconfig.SHA256, 0)
+	cs, rl := newChainFromItems(t, chainID, items)
+
+	for i := 0; i < int(conf.Genesis.BatchSize.MaxMessageCount); i++ {
+		cs.Enqueue(makeNormalTx(chainID, i))
+	}
+
+	// Causes the consenter thread to exit after it processes all messages
+	close(cs.(*chainSupport).chain.(*mockChain).queue)
+	<-cs.(*chainSupport).chain.(*mockChain).done
+
+	if rl.Height() != 2 {
+		t.Fatalf("Expected 2 blocks, got %d", rl.Height())
+	}
+
+	sha256, _ := chainconfig.HashFunction(chainconfig.SHA256)
+	genesisBlock := ordererledger.GetBlock(rl, 0)
+	block := ordererledger.GetBlock(rl, 1)
+
+	if !bytes.Equal(genesisBlock.Header.DataHash, genesisBlock.Data.HashWith(sha256)) {
+		t.Errorf("Genesis block data should have been hashed with %s", chainconfig.SHA256)
+	}
+	if !bytes.Equal(block.Header.DataHash, block.Data.HashWith(sha256)) {
+		t.Errorf("Block data should have been hashed with %s", chainconfig.SHA256)
+	}
+	if !bytes.Equal(block.Header.PreviousHash, genesisBlock.Header.HashWith(sha256)) {
+		t.Errorf("Block should have been chained to the genesis block with %s", chainconfig.SHA256)
+	}
+}
+
+// This test makes sure that a configuration transaction which changes the hashing algorithm of a chain is rejected
+func TestHashingAlgorithmChangeRejected(t *testing.T) {
+	chainID := "TestHashingAlgorithmChangeRejected"
+	items := modifiableTemplateItems()
+	setHashingAlgorithm(items, chainconfig.SHA256, 0)
+	cs, rl := newChainFromItems(t, chainID, items)
+
+	setHashingAlgorithm(items, chainconfig.SHA384, 1)
+	cs.Enqueue(makeConfigTxWithItems(chainID, items...))
+
+	// Causes the consenter thread to exit after it processes all messages
+	close(cs.(*chainSupport).chain.(*mockChain).queue)
+	<-cs.(*chainSupport).chain.(*mockChain).done
+
+	if rl.Height() != 1 {
+		t.Fatalf("The configuration transaction should have been rejected, but the chain grew to %d blocks", rl.Height())
+	}
+
+	if cs.ConfigTxManager().Sequence() != 0 {
+		t.Errorf("Configuration sequence should have remained 0, got %d", cs.ConfigTxManager().Sequence())
+	}
+}
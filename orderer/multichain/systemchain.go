@@ -20,6 +20,7 @@ import (
 	"bytes"
 
 	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/orderer/common/filter"
 	"github.com/hyperledger/fabric/orderer/common/sharedconfig"
@@ -36,6 +37,7 @@ type chainCreator interface {
 }
 
 type limitedSupport interface {
+	crypto.LocalSigner
 	ChainID() string
 	PolicyManager() policies.Manager
 	SharedConfig() sharedconfig.Manager
@@ -116,15 +118,19 @@ func (sc *systemChain) proposeChain(configTx *cb.Envelope) cb.Status {
 		return cb.Status_INTERNAL_SERVER_ERROR
 	}
 
+	signatureHeader, err := sc.support.NewSignatureHeader()
+	if err != nil {
+		logger.Debugf("Rejecting chain proposal: Error creating signature header: %s", err)
+		return cb.Status_INTERNAL_SERVER_ERROR
+	}
+
 	sysPayload := &cb.Payload{
 		Header: &cb.Header{
 			ChainHeader: &cb.ChainHeader{
 				ChainID: sc.support.ChainID(),
 				Type:    int32(cb.HeaderType_ORDERER_TRANSACTION),
 			},
-			SignatureHeader: &cb.SignatureHeader{
-			// XXX Appropriately set the signing identity and nonce here
-			},
+			SignatureHeader: signatureHeader,
 		},
 		Data: marshaledEnv,
 	}
@@ -135,9 +141,15 @@ func (sc *systemChain) proposeChain(configTx *cb.Envelope) cb.Status {
 		return cb.Status_INTERNAL_SERVER_ERROR
 	}
 
+	signature, err := sc.support.Sign(marshaledPayload)
+	if err != nil {
+		logger.Debugf("Rejecting chain proposal: Error signing payload: %s", err)
+		return cb.Status_INTERNAL_SERVER_ERROR
+	}
+
 	sysTran := &cb.Envelope{
-		Payload: marshaledPayload,
-		// XXX Add signature eventually
+		Payload:   marshaledPayload,
+		Signature: signature,
 	}
 
 	if !sc.support.Enqueue(sysTran) {
@@ -241,7 +253,7 @@ func (sc *systemChain) authorizeAndInspect(configTx *cb.Envelope) cb.Status {
 		return status
 	}
 
-	configTxManager, policyManager, sharedConfigManager, err := newConfigTxManagerAndHandlers(configEnvelope)
+	configTxManager, policyManager, sharedConfigManager, _, _, err := newConfigTxManagerAndHandlers(configEnvelope)
 	if err != nil {
 		logger.Debugf("Failed to create config manager and handlers: %s", err)
 		return cb.Status_BAD_REQUEST
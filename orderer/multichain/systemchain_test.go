@@ -20,6 +20,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/hyperledger/fabric/common/chainconfig"
 	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/common/policies"
 	coreutil "github.com/hyperledger/fabric/common/util"
@@ -49,6 +50,7 @@ func (mpm *mockPolicyManager) GetPolicy(id string) (policies.Policy, bool) {
 }
 
 type mockSupport struct {
+	mockCrypto
 	mpm     *mockPolicyManager
 	msc     *mocksharedconfig.Manager
 	chainID string
@@ -109,6 +111,15 @@ func TestGoodProposal(t *testing.T) {
 	mcc.ms.msc.ChainCreatorsVal = []string{provisional.AcceptAllPolicyKey}
 	mcc.ms.mpm.mp = &mockPolicy{}
 
+	hashingAlgorithmItem := &cb.ConfigurationItem{
+		Header: &cb.ChainHeader{
+			ChainID: newChainID,
+			Type:    int32(cb.HeaderType_CONFIGURATION_ITEM),
+		},
+		Key:   chainconfig.HashingAlgorithmKey,
+		Type:  cb.ConfigurationItem_Chain,
+		Value: utils.MarshalOrPanic(&cb.HashingAlgorithm{Name: chainconfig.SHA3Shake256}),
+	}
 	chainCreateTx := &cb.ConfigurationItem{
 		Header: &cb.ChainHeader{
 			ChainID: newChainID,
@@ -118,10 +129,10 @@ func TestGoodProposal(t *testing.T) {
 		Type: cb.ConfigurationItem_Orderer,
 		Value: utils.MarshalOrPanic(&ab.CreationPolicy{
 			Policy: provisional.AcceptAllPolicyKey,
-			Digest: coreutil.ComputeCryptoHash([]byte{}),
+			Digest: configtx.HashItems([]*cb.SignedConfigurationItem{{ConfigurationItem: utils.MarshalOrPanic(hashingAlgorithmItem)}}),
 		}),
 	}
-	ingressTx := makeConfigTxWithItems(newChainID, chainCreateTx)
+	ingressTx := makeConfigTxWithItems(newChainID, chainCreateTx, hashingAlgorithmItem)
 	status := mcc.sysChain.proposeChain(ingressTx)
 	if status != cb.Status_SUCCESS {
 		t.Fatalf("Should have successfully proposed chain")
@@ -233,3 +244,27 @@ func TestProposalWithBadDigest(t *testing.T) {
 		t.Fatalf("Should not have validated the transaction with missing policy")
 	}
 }
+
+func TestProposalWithoutHashingAlgorithm(t *testing.T) {
+	newChainID := "NewChainID"
+
+	mcc := newMockChainCreator()
+	mcc.ms.mpm.mp = &mockPolicy{}
+	mcc.ms.msc.ChainCreatorsVal = []string{provisional.AcceptAllPolicyKey}
+
+	chainCreateTx := &cb.ConfigurationItem{
+		Key:  configtx.CreationPolicyKey,
+		Type: cb.ConfigurationItem_Orderer,
+		Value: utils.MarshalOrPanic(&ab.CreationPolicy{
+			Policy: provisional.AcceptAllPolicyKey,
+			Digest: coreutil.ComputeCryptoHash([]byte{}),
+		}),
+	}
+	ingressTx := makeConfigTxWithItems(newChainID, chainCreateTx)
+
+	status := mcc.sysChain.proposeChain(ingressTx)
+
+	if status == cb.Status_SUCCESS {
+		t.Fatalf("Should not have validated the transaction with no hashing algorithm")
+	}
+}
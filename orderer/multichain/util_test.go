@@ -24,10 +24,21 @@ import (
 	"github.com/hyperledger/fabric/protos/utils"
 )
 
+// mockCrypto produces empty signature headers and "signs" a message by returning it unchanged
+type mockCrypto struct{}
+
+func (mc mockCrypto) NewSignatureHeader() (*cb.SignatureHeader, error) {
+	return &cb.SignatureHeader{}, nil
+}
+
+func (mc mockCrypto) Sign(message []byte) ([]byte, error) {
+	return message, nil
+}
+
 type mockConsenter struct {
 }
 
-func (mc *mockConsenter) HandleChain(support ConsenterSupport) (Chain, error) {
+func (mc *mockConsenter) HandleChain(support ConsenterSupport, metadata *cb.Metadata) (Chain, error) {
 	return &mockChain{
 		queue:   make(chan *cb.Envelope),
 		cutter:  support.BlockCutter(),
@@ -59,7 +70,7 @@ func (mch *mockChain) Start() {
 			batches, committers, _ := mch.cutter.Ordered(msg)
 			for i, batch := range batches {
 				block := mch.support.CreateNextBlock(batch)
-				mch.support.WriteBlock(block, committers[i])
+				mch.support.WriteBlock(block, committers[i], nil)
 			}
 		}
 	}()
@@ -31,15 +31,17 @@ import (
 )
 
 type deliverClient struct {
-	client  ab.AtomicBroadcast_DeliverClient
-	chainID string
+	client        ab.AtomicBroadcast_DeliverClient
+	chainID       string
+	contentType   ab.SeekInfo_SeekContentType
+	chaincodeName string
 }
 
-func newDeliverClient(client ab.AtomicBroadcast_DeliverClient, chainID string) *deliverClient {
-	return &deliverClient{client: client, chainID: chainID}
+func newDeliverClient(client ab.AtomicBroadcast_DeliverClient, chainID string, contentType ab.SeekInfo_SeekContentType, chaincodeName string) *deliverClient {
+	return &deliverClient{client: client, chainID: chainID, contentType: contentType, chaincodeName: chaincodeName}
 }
 
-func seekHelper(chainID string, start *ab.SeekPosition) *cb.Envelope {
+func seekHelper(chainID string, start *ab.SeekPosition, contentType ab.SeekInfo_SeekContentType, chaincodeName string) *cb.Envelope {
 	return &cb.Envelope{
 		Payload: utils.MarshalOrPanic(&cb.Payload{
 			Header: &cb.Header{
@@ -50,24 +52,26 @@ func seekHelper(chainID string, start *ab.SeekPosition) *cb.Envelope {
 			},
 
 			Data: utils.MarshalOrPanic(&ab.SeekInfo{
-				Start:    &ab.SeekPosition{Type: &ab.SeekPosition_Oldest{Oldest: &ab.SeekOldest{}}},
-				Stop:     &ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: math.MaxUint64}}},
-				Behavior: ab.SeekInfo_BLOCK_UNTIL_READY,
+				Start:         &ab.SeekPosition{Type: &ab.SeekPosition_Oldest{Oldest: &ab.SeekOldest{}}},
+				Stop:          &ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: math.MaxUint64}}},
+				Behavior:      ab.SeekInfo_BLOCK_UNTIL_READY,
+				ContentType:   contentType,
+				ChaincodeName: chaincodeName,
 			}),
 		}),
 	}
 }
 
 func (r *deliverClient) seekOldest() error {
-	return r.client.Send(seekHelper(r.chainID, &ab.SeekPosition{Type: &ab.SeekPosition_Oldest{Oldest: &ab.SeekOldest{}}}))
+	return r.client.Send(seekHelper(r.chainID, &ab.SeekPosition{Type: &ab.SeekPosition_Oldest{Oldest: &ab.SeekOldest{}}}, r.contentType, r.chaincodeName))
 }
 
 func (r *deliverClient) seekNewest() error {
-	return r.client.Send(seekHelper(r.chainID, &ab.SeekPosition{Type: &ab.SeekPosition_Newest{Newest: &ab.SeekNewest{}}}))
+	return r.client.Send(seekHelper(r.chainID, &ab.SeekPosition{Type: &ab.SeekPosition_Newest{Newest: &ab.SeekNewest{}}}, r.contentType, r.chaincodeName))
 }
 
 func (r *deliverClient) seek(blockNumber uint64) error {
-	return r.client.Send(seekHelper(r.chainID, &ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: blockNumber}}}))
+	return r.client.Send(seekHelper(r.chainID, &ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: blockNumber}}}, r.contentType, r.chaincodeName))
 }
 
 func (r *deliverClient) readUntilClose() {
@@ -84,6 +88,8 @@ func (r *deliverClient) readUntilClose() {
 			return
 		case *ab.DeliverResponse_Block:
 			fmt.Println("Received block: ", t.Block)
+		case *ab.DeliverResponse_FilteredBlock:
+			fmt.Println("Received filtered block: ", t.FilteredBlock)
 		}
 	}
 }
@@ -93,11 +99,21 @@ func main() {
 
 	var chainID string
 	var serverAddr string
+	var contentType string
+	var chaincodeName string
 
 	flag.StringVar(&serverAddr, "server", fmt.Sprintf("%s:%d", config.General.ListenAddress, config.General.ListenPort), "The RPC server to connect to.")
 	flag.StringVar(&chainID, "chainID", provisional.TestChainID, "The chain ID to deliver from.")
+	flag.StringVar(&contentType, "contentType", ab.SeekInfo_FULL_BLOCK.String(), "The parts of the blocks to deliver: FULL_BLOCK, HEADER_WITH_METADATA, TX_IDS or CHAINCODE_TXS.")
+	flag.StringVar(&chaincodeName, "chaincode", "", "The chaincode whose transactions to deliver with the CHAINCODE_TXS content type.")
 	flag.Parse()
 
+	contentTypeValue, ok := ab.SeekInfo_SeekContentType_value[contentType]
+	if !ok {
+		fmt.Println("Unknown content type:", contentType)
+		return
+	}
+
 	conn, err := grpc.Dial(serverAddr, grpc.WithInsecure())
 	if err != nil {
 		fmt.Println("Error connecting:", err)
@@ -109,7 +125,7 @@ func main() {
 		return
 	}
 
-	s := newDeliverClient(client, chainID)
+	s := newDeliverClient(client, chainID, ab.SeekInfo_SeekContentType(contentTypeValue), chaincodeName)
 	err = s.seekOldest()
 	if err != nil {
 		fmt.Println("Received error:", err)
@@ -17,7 +17,6 @@ limitations under the License.
 package backend
 
 import (
-	"bytes"
 	"fmt"
 	"io"
 	"sort"
@@ -37,43 +36,58 @@ import (
 	"crypto/rsa"
 	"crypto/sha256"
 	"encoding/asn1"
-	"encoding/gob"
 
 	"github.com/golang/protobuf/proto"
-	ordererledger "github.com/hyperledger/fabric/orderer/ledger"
+	"github.com/hyperledger/fabric/orderer/common/filter"
+	"github.com/hyperledger/fabric/orderer/multichain"
 	"github.com/hyperledger/fabric/orderer/sbft/connection"
 	"github.com/hyperledger/fabric/orderer/sbft/persist"
 	s "github.com/hyperledger/fabric/orderer/sbft/simplebft"
 	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/op/go-logging"
 )
 
-const headerIndex = 0
-const signaturesIndex = 1
-const metadataLen = 2
+const lastBatchKey = "lastbatch"
 
 var logger = logging.MustGetLogger("backend")
 
+// Backend connects the local SBFT replicas of all chains to their
+// counterparts. The messages of all chains are multiplexed over a single
+// connection per remote replica, and all events are executed sequentially.
 type Backend struct {
-	consensus s.Receiver
-	conn      *connection.Manager
+	conn *connection.Manager
+
+	lock     sync.Mutex
+	peers    map[string]chan<- *MultiChainMsg
+	peerInfo map[string]connection.PeerInfo
+	chains   map[string]*Chain
+
+	queue chan Executable
+}
+
+type consensusConn Backend
 
-	lock  sync.Mutex
-	peers map[uint64]chan<- *s.Msg
+// Chain is the SBFT replica of this process for a single chain. It
+// implements both multichain.Chain and simplebft.System.
+type Chain struct {
+	backend *Backend
+	chainID string
+	support multichain.ConsenterSupport
+	config  *s.Config
+
+	consensus s.Receiver
 
 	self     *PeerInfo
+	replicas []*PeerInfo
 	peerInfo map[string]*PeerInfo
 
-	queue chan Executable
-
 	persistence *persist.Persist
-	ledger      ordererledger.ReadWriter
+	lastBatch   *s.Batch
 
-	lastBatch *s.Batch
+	exitChan chan struct{}
 }
 
-type consensusConn Backend
-
 type PeerInfo struct {
 	info connection.PeerInfo
 	id   uint64
@@ -93,59 +107,99 @@ func (pi peerInfoSlice) Swap(i, j int) {
 	pi[i], pi[j] = pi[j], pi[i]
 }
 
-func NewBackend(peers map[string][]byte, conn *connection.Manager, rl ordererledger.ReadWriter, persist *persist.Persist) (*Backend, error) {
-	c := &Backend{
+// NewBackend creates a Backend which serves the replica connections of conn
+func NewBackend(conn *connection.Manager) *Backend {
+	b := &Backend{
 		conn:     conn,
-		peers:    make(map[uint64]chan<- *s.Msg),
-		peerInfo: make(map[string]*PeerInfo),
-		ledger:   rl,
+		peers:    make(map[string]chan<- *MultiChainMsg),
+		peerInfo: make(map[string]connection.PeerInfo),
+		chains:   make(map[string]*Chain),
+		queue:    make(chan Executable),
+	}
+	RegisterConsensusServer(conn.Server, (*consensusConn)(b))
+	go b.run()
+	return b
+}
+
+// AddChain creates the replica of the given chain. The peers map the
+// replica-to-replica address of every replica of the chain to the DER
+// encoding of its certificate, and must include the local replica.
+func (b *Backend) AddChain(support multichain.ConsenterSupport, peers map[string][]byte, config *s.Config, persistence *persist.Persist) (*Chain, error) {
+	if config.F*3+1 > config.N {
+		return nil, fmt.Errorf("invalid combination of N (%d) and F (%d)", config.N, config.F)
+	}
+	if uint64(len(peers)) != config.N {
+		return nil, fmt.Errorf("peer list of %d replicas does not match N (%d)", len(peers), config.N)
+	}
+
+	c := &Chain{
+		backend:     b,
+		chainID:     support.ChainID(),
+		support:     support,
+		config:      config,
+		peerInfo:    make(map[string]*PeerInfo),
+		persistence: persistence,
+		exitChan:    make(chan struct{}),
 	}
 
-	var peerInfo []*PeerInfo
 	for addr, cert := range peers {
 		pi, err := connection.NewPeerInfo(addr, cert)
 		if err != nil {
 			return nil, err
 		}
 		cpi := &PeerInfo{info: pi}
-		if pi.Fingerprint() == conn.Self.Fingerprint() {
+		if pi.Fingerprint() == b.conn.Self.Fingerprint() {
 			c.self = cpi
 		}
-		peerInfo = append(peerInfo, cpi)
+		c.replicas = append(c.replicas, cpi)
 		c.peerInfo[pi.Fingerprint()] = cpi
 	}
 
-	sort.Sort(peerInfoSlice(peerInfo))
-	for i, pi := range peerInfo {
+	sort.Sort(peerInfoSlice(c.replicas))
+	for i, pi := range c.replicas {
 		pi.id = uint64(i)
-		logger.Infof("replica %d: %s", i, pi.info.Fingerprint())
+		logger.Infof("chain %s: replica %d: %s", c.chainID, i, pi.info.Fingerprint())
 	}
 
 	if c.self == nil {
-		return nil, fmt.Errorf("peer list does not contain local node")
+		return nil, fmt.Errorf("peer list of chain %s does not contain local node", c.chainID)
 	}
 
-	logger.Infof("we are replica %d (%s)", c.self.id, c.self.info)
+	logger.Infof("chain %s: we are replica %d (%s)", c.chainID, c.self.id, c.self.info)
 
-	for _, peer := range c.peerInfo {
+	c.lastBatch = &s.Batch{Header: nil, Signatures: nil, Payloads: [][]byte{}}
+	batch := &s.Batch{}
+	if c.Restore(lastBatchKey, batch) {
+		c.lastBatch = batch
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if _, ok := b.chains[c.chainID]; ok {
+		return nil, fmt.Errorf("chain %s is already ordered by this replica", c.chainID)
+	}
+	b.chains[c.chainID] = c
+	for _, peer := range c.replicas {
 		if peer == c.self {
 			continue
 		}
-		go c.connectWorker(peer)
+		if _, ok := b.peerInfo[peer.info.Fingerprint()]; ok {
+			continue
+		}
+		b.peerInfo[peer.info.Fingerprint()] = peer.info
+		go b.connectWorker(peer.info)
 	}
-	RegisterConsensusServer(conn.Server, (*consensusConn)(c))
-	c.persistence = persist
-	c.lastBatch = &s.Batch{Header: nil, Signatures: nil, Payloads: [][]byte{}}
-	c.queue = make(chan Executable)
-	go c.run()
 	return c, nil
 }
 
-func (c *Backend) GetMyId() uint64 {
-	return c.self.id
+func (b *Backend) getChain(chainID string) (*Chain, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	c, ok := b.chains[chainID]
+	return c, ok
 }
 
-func (c *Backend) connectWorker(peer *PeerInfo) {
+func (b *Backend) connectWorker(peer connection.PeerInfo) {
 	timeout := 1 * time.Second
 
 	delay := time.After(0)
@@ -156,10 +210,10 @@ func (c *Backend) connectWorker(peer *PeerInfo) {
 		// set up for next
 		delay = time.After(timeout)
 
-		logger.Infof("connecting to replica %d (%s)", peer.id, peer.info)
-		conn, err := c.conn.DialPeer(peer.info, grpc.WithBlock(), grpc.WithTimeout(timeout))
+		logger.Infof("connecting to replica %s", peer)
+		conn, err := b.conn.DialPeer(peer, grpc.WithBlock(), grpc.WithTimeout(timeout))
 		if err != nil {
-			logger.Warningf("could not connect to replica %d (%s): %s", peer.id, peer.info, err)
+			logger.Warningf("could not connect to replica %s: %s", peer, err)
 			continue
 		}
 
@@ -168,10 +222,10 @@ func (c *Backend) connectWorker(peer *PeerInfo) {
 		client := NewConsensusClient(conn)
 		consensus, err := client.Consensus(ctx, &Handshake{})
 		if err != nil {
-			logger.Warningf("could not establish consensus stream with replica %d (%s): %s", peer.id, peer.info, err)
+			logger.Warningf("could not establish consensus stream with replica %s: %s", peer, err)
 			continue
 		}
-		logger.Noticef("connection to replica %d (%s) established", peer.id, peer.info)
+		logger.Noticef("connection to replica %s established", peer)
 
 		for {
 			msg, err := consensus.Recv()
@@ -179,29 +233,35 @@ func (c *Backend) connectWorker(peer *PeerInfo) {
 				break
 			}
 			if err != nil {
-				logger.Warningf("consensus stream with replica %d (%s) broke: %v", peer.id, peer.info, err)
+				logger.Warningf("consensus stream with replica %s broke: %v", peer, err)
 				break
 			}
-			c.enqueueForReceive(msg, peer.id)
+			b.enqueueForReceive(msg.ChainID, msg.Msg, peer.Fingerprint())
 		}
 	}
 }
 
-func (b *Backend) enqueueConnection(peerid uint64) {
+func (b *Backend) enqueueConnection(chainID string, peer string) {
 	go func() {
-		b.queue <- &connectionEvent{peerid: peerid}
+		b.queue <- &connectionEvent{chainID: chainID, peer: peer}
 	}()
 }
 
-func (b *Backend) enqueueRequest(request []byte) {
+func (b *Backend) enqueueRequest(chainID string, request []byte) {
 	go func() {
-		b.queue <- &requestEvent{req: request}
+		b.queue <- &requestEvent{chainID: chainID, req: request}
 	}()
 }
 
-func (b *Backend) enqueueForReceive(msg *s.Msg, src uint64) {
+func (b *Backend) enqueueForReceive(chainID string, msg *s.Msg, src string) {
 	go func() {
-		b.queue <- &msgEvent{msg: msg, src: src}
+		b.queue <- &msgEvent{chainID: chainID, msg: msg, src: src}
+	}()
+}
+
+func (b *Backend) enqueueEvent(e Executable) {
+	go func() {
+		b.queue <- e
 	}()
 }
 
@@ -224,55 +284,64 @@ func (b *Backend) run() {
 // gRPC interface
 func (c *consensusConn) Consensus(_ *Handshake, srv Consensus_ConsensusServer) error {
 	pi := connection.GetPeerInfo(srv)
+	c.lock.Lock()
 	peer, ok := c.peerInfo[pi.Fingerprint()]
+	c.lock.Unlock()
 
-	if !ok || !peer.info.Cert().Equal(pi.Cert()) {
+	if !ok || !peer.Cert().Equal(pi.Cert()) {
 		logger.Infof("rejecting connection from unknown replica %s", pi)
 		return fmt.Errorf("unknown peer certificate")
 	}
-	logger.Infof("connection from replica %d (%s)", peer.id, pi)
+	logger.Infof("connection from replica %s", pi)
 
-	ch := make(chan *s.Msg)
+	ch := make(chan *MultiChainMsg)
 	c.lock.Lock()
-	if oldch, ok := c.peers[peer.id]; ok {
-		logger.Debugf("replacing connection from replica %d", peer.id)
+	if oldch, ok := c.peers[pi.Fingerprint()]; ok {
+		logger.Debugf("replacing connection from replica %s", pi)
 		close(oldch)
 	}
-	c.peers[peer.id] = ch
+	c.peers[pi.Fingerprint()] = ch
 	c.lock.Unlock()
-	((*Backend)(c)).enqueueConnection(peer.id)
+	((*Backend)(c)).enqueueConnection("", pi.Fingerprint())
 
 	var err error
 	for msg := range ch {
 		err = srv.Send(msg)
 		if err != nil {
 			c.lock.Lock()
-			delete(c.peers, peer.id)
+			delete(c.peers, pi.Fingerprint())
 			c.lock.Unlock()
 
-			logger.Infof("lost connection from replica %d (%s): %s", peer.id, pi, err)
+			logger.Infof("lost connection from replica %s: %s", pi, err)
 		}
 	}
 
 	return err
 }
 
-func (c *Backend) Broadcast(msg *s.Msg) error {
-	c.lock.Lock()
-	for _, ch := range c.peers {
+func (b *Backend) isConnected(peer string) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	_, ok := b.peers[peer]
+	return ok
+}
+
+func (b *Backend) Broadcast(msg *MultiChainMsg) error {
+	b.lock.Lock()
+	for _, ch := range b.peers {
 		ch <- msg
 	}
-	c.lock.Unlock()
+	b.lock.Unlock()
 	return nil
 }
 
-func (c *Backend) Unicast(msg *s.Msg, dest uint64) error {
-	c.lock.Lock()
-	ch, ok := c.peers[dest]
-	c.lock.Unlock()
+func (b *Backend) Unicast(msg *MultiChainMsg, dest string) error {
+	b.lock.Lock()
+	ch, ok := b.peers[dest]
+	b.lock.Unlock()
 
 	if !ok {
-		err := fmt.Errorf("peer not found: %v", dest)
+		err := fmt.Errorf("peer not found: %.6s", dest)
 		logger.Debug(err)
 		return err
 	}
@@ -280,59 +349,142 @@ func (c *Backend) Unicast(msg *s.Msg, dest uint64) error {
 	return nil
 }
 
-func (t *Backend) SetReceiver(recv s.Receiver) {
-	t.consensus = recv
+func (b *Backend) Timer(d time.Duration, tf func()) s.Canceller {
+	tm := &Timer{tf: tf, execute: true}
+	b.initTimer(tm, d)
+	return tm
+}
+
+// multichain.Chain interface
+
+// Start creates the SBFT instance of the chain on the event loop of the backend
+func (c *Chain) Start() {
+	c.backend.enqueueEvent(&startEvent{chainID: c.chainID})
+}
+
+// Halt stops the chain from accepting requests and detaches it from the backend
+func (c *Chain) Halt() {
+	select {
+	case <-c.exitChan:
+		// Allow multiple halts without panic
+	default:
+		close(c.exitChan)
+		c.backend.enqueueEvent(&haltEvent{chainID: c.chainID})
+	}
+}
+
+// Enqueue accepts a message and returns true on acceptance, or false on shutdown
+func (c *Chain) Enqueue(env *cb.Envelope) bool {
+	select {
+	case <-c.exitChan:
+		return false
+	default:
+	}
+
+	req, err := proto.Marshal(env)
+	if err != nil {
+		logger.Errorf("chain %s: could not marshal request: %s", c.chainID, err)
+		return false
+	}
+	c.backend.enqueueRequest(c.chainID, req)
+	return true
+}
+
+// start creates the SBFT instance unless it is already running, and announces
+// the connections to the replicas which are already connected
+func (c *Chain) start() {
+	if c.consensus != nil {
+		return
+	}
+
+	_, err := s.New(c.self.id, c.config, c)
+	if err != nil {
+		logger.Panicf("chain %s: failed to create SBFT instance: %s", c.chainID, err)
+	}
+
+	for _, peer := range c.replicas {
+		if peer != c.self && c.backend.isConnected(peer.info.Fingerprint()) {
+			c.consensus.Connection(peer.id)
+		}
+	}
 }
 
-func (t *Backend) Send(msg *s.Msg, dest uint64) {
-	if dest == t.self.id {
-		t.enqueueForReceive(msg, t.self.id)
+// simplebft.System interface
+
+func (c *Chain) SetReceiver(recv s.Receiver) {
+	c.consensus = recv
+}
+
+func (c *Chain) Send(msg *s.Msg, dest uint64) {
+	if dest == c.self.id {
+		c.backend.enqueueForReceive(c.chainID, msg, c.self.info.Fingerprint())
+		return
+	}
+	if dest >= uint64(len(c.replicas)) {
+		logger.Warningf("chain %s: replica %d not found", c.chainID, dest)
 		return
 	}
-	t.Unicast(msg, dest)
+	c.backend.Unicast(&MultiChainMsg{ChainID: c.chainID, Msg: msg}, c.replicas[dest].info.Fingerprint())
 }
 
-func (t *Backend) Timer(d time.Duration, tf func()) s.Canceller {
-	tm := &Timer{tf: tf, execute: true}
-	t.initTimer(tm, d)
-	return tm
+func (c *Chain) Timer(d time.Duration, tf func()) s.Canceller {
+	return c.backend.Timer(d, tf)
 }
 
-// Deliver writes the ledger
-func (t *Backend) Deliver(batch *s.Batch) {
-	blockContents := make([]*cb.Envelope, 0, len(batch.Payloads))
+// Deliver passes the messages of the batch through the block cutter
+// and writes the resulting blocks to the ledger. The header of the batch
+// and the signatures of the replicas over it are recorded in the ORDERER
+// metadata of each block cut from the batch.
+func (c *Chain) Deliver(batch *s.Batch) {
+	metadata := utils.MarshalOrPanic(&s.Batch{Header: batch.Header, Signatures: batch.Signatures})
+	cutter := c.support.BlockCutter()
 	for _, p := range batch.Payloads {
 		envelope := &cb.Envelope{}
 		err := proto.Unmarshal(p, envelope)
-		if err == nil {
-			blockContents = append(blockContents, envelope)
-		} else {
+		if err != nil {
 			logger.Warningf("Payload cannot be unmarshalled.")
+			continue
+		}
+		batches, committers, ok := cutter.Ordered(envelope)
+		if !ok {
+			logger.Debugf("chain %s: request rejected by the block cutter", c.chainID)
+			continue
+		}
+		for i, b := range batches {
+			c.writeBlock(b, committers[i], metadata)
 		}
 	}
-	block := ordererledger.CreateNextBlock(t.ledger, blockContents)
-	// This a quick and dirty solution to make it work.
-	// SBFT needs to use Ordererledger's structures and signatures over the Block.
-	block.Metadata.Metadata[headerIndex] = batch.Header
-	block.Metadata.Metadata[signaturesIndex] = encodeSignatures(batch.Signatures)
-	t.lastBatch = batch
-	t.ledger.Append(block)
+	if envelopes, committers := cutter.Cut(); len(envelopes) > 0 {
+		c.writeBlock(envelopes, committers, metadata)
+	}
+
+	c.lastBatch = batch
+	c.Persist(lastBatchKey, batch)
+
+	// The blocks may have updated the batch timeout of the chain, which SBFT
+	// reads from the configuration as it starts the next batch timer
+	c.config.BatchDurationNsec = uint64(c.support.SharedConfig().BatchTimeout().Nanoseconds())
+}
+
+func (c *Chain) writeBlock(envelopes []*cb.Envelope, committers []filter.Committer, metadata []byte) {
+	block := c.support.CreateNextBlock(envelopes)
+	c.support.WriteBlock(block, committers, metadata)
 }
 
-func (t *Backend) Persist(key string, data proto.Message) {
+func (c *Chain) Persist(key string, data proto.Message) {
 	if data == nil {
-		t.persistence.DelState(key)
+		c.persistence.DelState(key)
 	} else {
 		bytes, err := proto.Marshal(data)
 		if err != nil {
 			panic(err)
 		}
-		t.persistence.StoreState(key, bytes)
+		c.persistence.StoreState(key, bytes)
 	}
 }
 
-func (t *Backend) Restore(key string, out proto.Message) bool {
-	val, err := t.persistence.ReadState(key)
+func (c *Chain) Restore(key string, out proto.Message) bool {
+	val, err := c.persistence.ReadState(key)
 	if err != nil {
 		return false
 	}
@@ -340,24 +492,26 @@ func (t *Backend) Restore(key string, out proto.Message) bool {
 	return (err == nil)
 }
 
-func (t *Backend) LastBatch() *s.Batch {
-	return t.lastBatch
+func (c *Chain) LastBatch() *s.Batch {
+	return c.lastBatch
 }
 
-func (t *Backend) Sign(data []byte) []byte {
-	return Sign(t.conn.Cert.PrivateKey, data)
+func (c *Chain) Sign(data []byte) []byte {
+	return Sign(c.backend.conn.Cert.PrivateKey, data)
 }
 
-func (t *Backend) CheckSig(data []byte, src uint64, sig []byte) error {
-	leaf := t.conn.Cert.Leaf
-	if leaf == nil {
-		panic("No public key found: certificate leaf is nil.")
+func (c *Chain) CheckSig(data []byte, src uint64, sig []byte) error {
+	if src >= uint64(len(c.replicas)) {
+		return fmt.Errorf("replica %d not found", src)
 	}
-	return CheckSig(leaf.PublicKey, data, sig)
+	return CheckSig(c.replicas[src].info.Cert().PublicKey, data, sig)
 }
 
-func (t *Backend) Reconnect(replica uint64) {
-	t.enqueueConnection(replica)
+func (c *Chain) Reconnect(replica uint64) {
+	if replica >= uint64(len(c.replicas)) {
+		return
+	}
+	c.backend.enqueueConnection(c.chainID, c.replicas[replica].info.Fingerprint())
 }
 
 func Sign(privateKey crypto.PrivateKey, data []byte) []byte {
@@ -409,41 +563,3 @@ func CheckSig(publicKey crypto.PublicKey, data []byte, sig []byte) error {
 		return fmt.Errorf("Unsupported public key type.")
 	}
 }
-
-func getHeader(metadata *cb.BlockMetadata) []byte {
-	if metadata == nil || len(metadata.Metadata) < metadataLen {
-		return nil
-	}
-	return metadata.Metadata[headerIndex]
-}
-
-func getEncodedSignatures(metadata *cb.BlockMetadata) []byte {
-	if metadata == nil || len(metadata.Metadata) < metadataLen {
-		return nil
-	}
-	return metadata.Metadata[signaturesIndex]
-}
-
-func encodeSignatures(signatures map[uint64][]byte) []byte {
-	var buf bytes.Buffer
-	enc := gob.NewEncoder(&buf)
-	err := enc.Encode(signatures)
-	if err != nil {
-		panic(err)
-	}
-	return buf.Bytes()
-}
-
-func decodeSignatures(encodedSignatures []byte) map[uint64][]byte {
-	if len(encodedSignatures) == 0 {
-		return nil
-	}
-	buf := bytes.NewBuffer(encodedSignatures)
-	var r map[uint64][]byte
-	dec := gob.NewDecoder(buf)
-	err := dec.Decode(&r)
-	if err != nil {
-		panic(err)
-	}
-	return r
-}
@@ -21,15 +21,22 @@ import (
 	"crypto/elliptic"
 	crand "crypto/rand"
 	"crypto/rsa"
+	"io/ioutil"
+	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/orderer/common/bootstrap/provisional"
-	ramledger "github.com/hyperledger/fabric/orderer/ledger/ram"
-	localconfig "github.com/hyperledger/fabric/orderer/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/filter"
+	mockblockcutter "github.com/hyperledger/fabric/orderer/mocks/blockcutter"
+	mockmultichain "github.com/hyperledger/fabric/orderer/mocks/multichain"
+	mocksharedconfig "github.com/hyperledger/fabric/orderer/mocks/sharedconfig"
+	"github.com/hyperledger/fabric/orderer/sbft/persist"
 	"github.com/hyperledger/fabric/orderer/sbft/simplebft"
 	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
 )
 
 func TestSignAndVerifyRsa(t *testing.T) {
@@ -69,13 +76,22 @@ func TestSignAndVerifyEcdsa(t *testing.T) {
 }
 
 func TestLedgerReadWrite(t *testing.T) {
-	localConf := localconfig.Load()
-	localConf.Genesis.OrdererType = provisional.ConsensusTypeSbft
-	genesis := provisional.New(localConf).GenesisBlock()
-	rlf := ramledger.New(10)
-	rl, _ := rlf.GetOrCreate(provisional.TestChainID)
-	rl.Append(genesis)
-	b := Backend{ledger: rl}
+	dir, err := ioutil.TempDir("", "sbft_backend_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cutter := mockblockcutter.NewReceiver()
+	close(cutter.Block)
+	support := &mockmultichain.ConsenterSupport{
+		BlockCutterVal:  cutter,
+		Batches:         make(chan []*cb.Envelope, 1),
+		ChainIDVal:      provisional.TestChainID,
+		SharedConfigVal: &mocksharedconfig.Manager{BatchTimeoutVal: time.Second},
+	}
+	recorder := &blockRecorder{ConsenterSupport: support, blocks: make(chan *cb.Block, 1)}
+	c := &Chain{support: recorder, chainID: support.ChainIDVal, config: &simplebft.Config{}, persistence: persist.New(dir)}
 
 	header := []byte("header")
 	e1 := &cb.Envelope{Payload: []byte("data1")}
@@ -88,44 +104,81 @@ func TestLedgerReadWrite(t *testing.T) {
 	sgns[uint64(22)] = []byte("sgn22")
 	batch := simplebft.Batch{Header: header, Payloads: data, Signatures: sgns}
 
-	b.Deliver(&batch)
-	batch2 := b.LastBatch()
+	c.Deliver(&batch)
+	batch2 := c.LastBatch()
 
 	if !reflect.DeepEqual(batch, *batch2) {
 		t.Errorf("The wrong batch was returned by LastBatch after Deliver: %v (original was: %v)", batch2, &batch)
 	}
-}
 
-func TestEncoderEncodesDecodesSgnsWithoutPanic(t *testing.T) {
-	defer func() {
-		if r := recover(); r != nil {
-			t.Errorf("Encoding/decoding failed for valid signatures, code panicked.")
+	select {
+	case envs := <-support.Batches:
+		if len(envs) != 2 {
+			t.Errorf("Expected a block of 2 envelopes, got %d", len(envs))
 		}
-	}()
-	sgns1 := make(map[uint64][]byte)
-	e1 := encodeSignatures(sgns1)
-
-	sgns2 := make(map[uint64][]byte)
-	sgns2[uint64(1)] = []byte("sgn1")
-	e2 := encodeSignatures(sgns2)
-
-	sgns3 := make(map[uint64][]byte)
-	sgns3[uint64(22)] = []byte("sgn22")
-	sgns3[uint64(143)] = []byte("sgn22")
-	sgns3[uint64(200)] = []byte("sgn200")
-	e3 := encodeSignatures(sgns3)
-
-	rsgns1 := decodeSignatures(e1)
-	rsgns2 := decodeSignatures(e2)
-	rsgns3 := decodeSignatures(e3)
-
-	if !reflect.DeepEqual(sgns1, rsgns1) {
-		t.Errorf("Decoding error: %v (original: %v). (1)", rsgns1, sgns1)
+	default:
+		t.Errorf("Deliver did not write a block")
 	}
-	if !reflect.DeepEqual(sgns2, rsgns2) {
-		t.Errorf("Decoding error: %v (original: %v). (2)", rsgns2, sgns2)
+
+	select {
+	case block := <-recorder.blocks:
+		metadata, err := utils.GetMetadataFromBlock(block, cb.BlockMetadataIndex_ORDERER)
+		if err != nil {
+			t.Fatalf("The block carries no orderer metadata: %s", err)
+		}
+		sbftMetadata := &simplebft.Batch{}
+		if err := proto.Unmarshal(metadata.Value, sbftMetadata); err != nil {
+			t.Fatalf("The orderer metadata of the block cannot be unmarshalled: %s", err)
+		}
+		if !reflect.DeepEqual(header, sbftMetadata.Header) || !reflect.DeepEqual(sgns, sbftMetadata.Signatures) {
+			t.Errorf("The block does not carry the header and signatures of the batch: %v", sbftMetadata)
+		}
+	default:
+		t.Errorf("Deliver did not write a block")
 	}
-	if !reflect.DeepEqual(sgns3, rsgns3) {
-		t.Errorf("Decoding error: %v (original: %v). (3)", rsgns3, sgns3)
+
+	batch3 := &simplebft.Batch{}
+	if !c.Restore(lastBatchKey, batch3) || !reflect.DeepEqual(batch, *batch3) {
+		t.Errorf("The delivered batch was not persisted: %v (original was: %v)", batch3, &batch)
+	}
+}
+
+// blockRecorder passes the blocks written through the mock ConsenterSupport on to the blocks channel
+type blockRecorder struct {
+	*mockmultichain.ConsenterSupport
+	blocks chan *cb.Block
+}
+
+func (br *blockRecorder) WriteBlock(block *cb.Block, committers []filter.Committer, encodedMetadataValue []byte) *cb.Block {
+	block = br.ConsenterSupport.WriteBlock(block, committers, encodedMetadataValue)
+	br.blocks <- block
+	return block
+}
+
+func TestDeliverUpdatesBatchDuration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sbft_backend_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cutter := mockblockcutter.NewReceiver()
+	close(cutter.Block)
+	support := &mockmultichain.ConsenterSupport{
+		BlockCutterVal:  cutter,
+		Batches:         make(chan []*cb.Envelope, 1),
+		ChainIDVal:      provisional.TestChainID,
+		SharedConfigVal: &mocksharedconfig.Manager{BatchTimeoutVal: time.Second},
+	}
+	config := &simplebft.Config{BatchDurationNsec: uint64(time.Second)}
+	c := &Chain{support: support, chainID: support.ChainIDVal, config: config, persistence: persist.New(dir)}
+
+	// A configuration update of the running chain changes the batch timeout
+	support.SharedConfigVal.BatchTimeoutVal = 10 * time.Millisecond
+	ebytes, _ := proto.Marshal(&cb.Envelope{Payload: []byte("data")})
+	c.Deliver(&simplebft.Batch{Header: []byte("header"), Payloads: [][]byte{ebytes}})
+
+	if config.BatchDurationNsec != uint64(10*time.Millisecond) {
+		t.Errorf("Expected the batch duration to follow the batch timeout of the chain, got %d", config.BatchDurationNsec)
 	}
 }
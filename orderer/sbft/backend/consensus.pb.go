@@ -10,6 +10,7 @@ It is generated from these files:
 
 It has these top-level messages:
 	Handshake
+	MultiChainMsg
 */
 package backend
 
@@ -42,8 +43,27 @@ func (m *Handshake) String() string            { return proto.CompactTextString(
 func (*Handshake) ProtoMessage()               {}
 func (*Handshake) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
 
+// multi_chain_msg carries an SBFT message of the chain identified by chainID
+type MultiChainMsg struct {
+	ChainID string         `protobuf:"bytes,1,opt,name=chainID" json:"chainID,omitempty"`
+	Msg     *simplebft.Msg `protobuf:"bytes,2,opt,name=msg" json:"msg,omitempty"`
+}
+
+func (m *MultiChainMsg) Reset()                    { *m = MultiChainMsg{} }
+func (m *MultiChainMsg) String() string            { return proto.CompactTextString(m) }
+func (*MultiChainMsg) ProtoMessage()               {}
+func (*MultiChainMsg) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *MultiChainMsg) GetMsg() *simplebft.Msg {
+	if m != nil {
+		return m.Msg
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Handshake)(nil), "backend.handshake")
+	proto.RegisterType((*MultiChainMsg)(nil), "backend.multi_chain_msg")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -84,7 +104,7 @@ func (c *consensusClient) Consensus(ctx context.Context, in *Handshake, opts ...
 }
 
 type Consensus_ConsensusClient interface {
-	Recv() (*simplebft.Msg, error)
+	Recv() (*MultiChainMsg, error)
 	grpc.ClientStream
 }
 
@@ -92,8 +112,8 @@ type consensusConsensusClient struct {
 	grpc.ClientStream
 }
 
-func (x *consensusConsensusClient) Recv() (*simplebft.Msg, error) {
-	m := new(simplebft.Msg)
+func (x *consensusConsensusClient) Recv() (*MultiChainMsg, error) {
+	m := new(MultiChainMsg)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
@@ -119,7 +139,7 @@ func _Consensus_Consensus_Handler(srv interface{}, stream grpc.ServerStream) err
 }
 
 type Consensus_ConsensusServer interface {
-	Send(*simplebft.Msg) error
+	Send(*MultiChainMsg) error
 	grpc.ServerStream
 }
 
@@ -127,7 +147,7 @@ type consensusConsensusServer struct {
 	grpc.ServerStream
 }
 
-func (x *consensusConsensusServer) Send(m *simplebft.Msg) error {
+func (x *consensusConsensusServer) Send(m *MultiChainMsg) error {
 	return x.ServerStream.SendMsg(m)
 }
 
@@ -148,16 +168,19 @@ var _Consensus_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("backend/consensus.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 164 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xe2, 0x12, 0x4f, 0x4a, 0x4c, 0xce,
-	0x4e, 0xcd, 0x4b, 0xd1, 0x4f, 0xce, 0xcf, 0x2b, 0x4e, 0xcd, 0x2b, 0x2e, 0x2d, 0xd6, 0x2b, 0x28,
-	0xca, 0x2f, 0xc9, 0x17, 0x62, 0x87, 0x4a, 0x48, 0x49, 0x16, 0x67, 0xe6, 0x16, 0xe4, 0xa4, 0x26,
-	0xa5, 0x95, 0xe8, 0xc3, 0x59, 0x10, 0x35, 0x4a, 0xdc, 0x5c, 0x9c, 0x19, 0x89, 0x79, 0x29, 0xc5,
-	0x19, 0x89, 0xd9, 0xa9, 0x46, 0x0e, 0x5c, 0x9c, 0x70, 0x33, 0x84, 0x8c, 0x91, 0x39, 0x42, 0x7a,
-	0x50, 0xb3, 0xf4, 0xe0, 0xaa, 0xa5, 0xf8, 0xf4, 0x10, 0x86, 0xf9, 0x16, 0xa7, 0x2b, 0x31, 0x18,
-	0x30, 0x3a, 0x99, 0x44, 0x19, 0xa5, 0x67, 0x96, 0x64, 0x94, 0x26, 0xe9, 0x25, 0xe7, 0xe7, 0xea,
-	0x67, 0x54, 0x16, 0xa4, 0x16, 0xe5, 0xa4, 0xa6, 0xa4, 0xa7, 0x16, 0xe9, 0xa7, 0x25, 0x26, 0x15,
-	0x65, 0x26, 0xeb, 0xe7, 0x17, 0xa5, 0xa4, 0x16, 0xa5, 0x16, 0xe9, 0x17, 0x83, 0x1c, 0x03, 0x35,
-	0x33, 0x89, 0x0d, 0xec, 0x16, 0x63, 0x40, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0x8b, 0x41, 0xd6,
-	0xca, 0x00, 0x00, 0x00,
+	// 212 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x8f, 0x3f, 0x4b, 0x04, 0x31,
+	0x10, 0xc5, 0x8d, 0x82, 0xc7, 0xe6, 0xc0, 0x83, 0x34, 0xae, 0x67, 0x73, 0x6c, 0x75, 0x55, 0x22,
+	0xab, 0xad, 0x08, 0x62, 0xa3, 0xe0, 0x17, 0xb0, 0x39, 0xf2, 0x67, 0x2e, 0x09, 0xb7, 0x49, 0x96,
+	0x4c, 0xb6, 0xf0, 0xdb, 0x8b, 0xeb, 0x12, 0xc5, 0x6e, 0xde, 0xcc, 0xf0, 0xde, 0xfb, 0xd1, 0x6b,
+	0x25, 0xf5, 0x09, 0xa2, 0x11, 0x3a, 0x45, 0x84, 0x88, 0x13, 0xf2, 0x31, 0xa7, 0x92, 0xd8, 0x6a,
+	0x39, 0x6c, 0x6f, 0xd0, 0x87, 0x71, 0x00, 0x75, 0x2c, 0xa2, 0x4e, 0x3f, 0x3f, 0xdd, 0x9a, 0x36,
+	0x4e, 0x46, 0x83, 0x4e, 0x9e, 0xa0, 0x7b, 0xa2, 0x9b, 0x30, 0x0d, 0xc5, 0x1f, 0xb4, 0x93, 0x3e,
+	0x1e, 0x02, 0x5a, 0xb6, 0xa1, 0xab, 0x59, 0xbc, 0xbe, 0xb4, 0x64, 0x47, 0xf6, 0x0d, 0xbb, 0xa5,
+	0x17, 0x01, 0x6d, 0x7b, 0xbe, 0x23, 0xfb, 0x75, 0x7f, 0xc5, 0x7f, 0xfd, 0xde, 0xd1, 0xf6, 0x6f,
+	0xb4, 0xa9, 0x25, 0xd8, 0xe3, 0x5f, 0xc1, 0xf8, 0x52, 0x86, 0xd7, 0xb8, 0x6d, 0x5b, 0x77, 0xff,
+	0x52, 0xbb, 0xb3, 0x3b, 0xf2, 0xfc, 0xf0, 0xd1, 0x5b, 0x5f, 0xdc, 0xa4, 0xb8, 0x4e, 0x41, 0xb8,
+	0xcf, 0x11, 0xf2, 0x00, 0xc6, 0x42, 0x16, 0x47, 0xa9, 0xb2, 0xd7, 0x22, 0x65, 0x03, 0x19, 0xb2,
+	0xc0, 0x6f, 0xae, 0xc5, 0x49, 0x5d, 0xce, 0x58, 0xf7, 0x5f, 0x03, 0x00, 0xf9, 0x1f, 0xad, 0x75,
+	0x15, 0x01, 0x00, 0x00,
 }
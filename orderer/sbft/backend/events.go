@@ -40,26 +40,85 @@ func (t *Timer) Execute(backend *Backend) {
 }
 
 type msgEvent struct {
-	msg *s.Msg
-	src uint64
+	chainID string
+	msg     *s.Msg
+	src     string
 }
 
 func (m *msgEvent) Execute(backend *Backend) {
-	backend.consensus.Receive(m.msg, m.src)
+	chain, ok := backend.getChain(m.chainID)
+	if !ok {
+		logger.Debugf("dropping message for unknown chain %s", m.chainID)
+		return
+	}
+	src, ok := chain.peerInfo[m.src]
+	if !ok {
+		logger.Warningf("chain %s: dropping message from unknown replica %.6s", m.chainID, m.src)
+		return
+	}
+	chain.start()
+	chain.consensus.Receive(m.msg, src.id)
 }
 
 type requestEvent struct {
-	req []byte
+	chainID string
+	req     []byte
 }
 
 func (r *requestEvent) Execute(backend *Backend) {
-	backend.consensus.Request(r.req)
+	chain, ok := backend.getChain(r.chainID)
+	if !ok {
+		logger.Debugf("dropping request for unknown chain %s", r.chainID)
+		return
+	}
+	chain.start()
+	chain.consensus.Request(r.req)
 }
 
+// connectionEvent announces a connection to the given replica to the chain
+// identified by chainID, or to all chains of the replica if chainID is empty
 type connectionEvent struct {
-	peerid uint64
+	chainID string
+	peer    string
 }
 
 func (c *connectionEvent) Execute(backend *Backend) {
-	backend.consensus.Connection(c.peerid)
+	backend.lock.Lock()
+	chains := make([]*Chain, 0, len(backend.chains))
+	for chainID, chain := range backend.chains {
+		if c.chainID == "" || c.chainID == chainID {
+			chains = append(chains, chain)
+		}
+	}
+	backend.lock.Unlock()
+
+	for _, chain := range chains {
+		peer, ok := chain.peerInfo[c.peer]
+		if !ok || chain.consensus == nil {
+			continue
+		}
+		chain.consensus.Connection(peer.id)
+	}
+}
+
+type startEvent struct {
+	chainID string
+}
+
+func (st *startEvent) Execute(backend *Backend) {
+	chain, ok := backend.getChain(st.chainID)
+	if !ok {
+		return
+	}
+	chain.start()
+}
+
+type haltEvent struct {
+	chainID string
+}
+
+func (h *haltEvent) Execute(backend *Backend) {
+	backend.lock.Lock()
+	delete(backend.chains, h.chainID)
+	backend.lock.Unlock()
 }
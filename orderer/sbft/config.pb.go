@@ -1,119 +0,0 @@
-// Code generated by protoc-gen-go.
-// source: config.proto
-// DO NOT EDIT!
-
-/*
-Package sbft is a generated protocol buffer package.
-
-It is generated from these files:
-	config.proto
-
-It has these top-level messages:
-	ConsensusConfig
-	JsonConfig
-	Peer
-*/
-package sbft
-
-import proto "github.com/golang/protobuf/proto"
-import fmt "fmt"
-import math "math"
-import simplebft "github.com/hyperledger/fabric/orderer/sbft/simplebft"
-
-// Reference imports to suppress errors if they are not otherwise used.
-var _ = proto.Marshal
-var _ = fmt.Errorf
-var _ = math.Inf
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the proto package it is being compiled against.
-// A compilation error at this line likely means your copy of the
-// proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
-
-type ConsensusConfig struct {
-	Consensus *simplebft.Config `protobuf:"bytes,1,opt,name=consensus" json:"consensus,omitempty"`
-	Peers     map[string][]byte `protobuf:"bytes,2,rep,name=peers" json:"peers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value,proto3"`
-}
-
-func (m *ConsensusConfig) Reset()                    { *m = ConsensusConfig{} }
-func (m *ConsensusConfig) String() string            { return proto.CompactTextString(m) }
-func (*ConsensusConfig) ProtoMessage()               {}
-func (*ConsensusConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
-
-func (m *ConsensusConfig) GetConsensus() *simplebft.Config {
-	if m != nil {
-		return m.Consensus
-	}
-	return nil
-}
-
-func (m *ConsensusConfig) GetPeers() map[string][]byte {
-	if m != nil {
-		return m.Peers
-	}
-	return nil
-}
-
-type JsonConfig struct {
-	Consensus *simplebft.Config `protobuf:"bytes,1,opt,name=consensus" json:"consensus,omitempty"`
-	Peers     []*Peer           `protobuf:"bytes,2,rep,name=peers" json:"peers,omitempty"`
-}
-
-func (m *JsonConfig) Reset()                    { *m = JsonConfig{} }
-func (m *JsonConfig) String() string            { return proto.CompactTextString(m) }
-func (*JsonConfig) ProtoMessage()               {}
-func (*JsonConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
-
-func (m *JsonConfig) GetConsensus() *simplebft.Config {
-	if m != nil {
-		return m.Consensus
-	}
-	return nil
-}
-
-func (m *JsonConfig) GetPeers() []*Peer {
-	if m != nil {
-		return m.Peers
-	}
-	return nil
-}
-
-type Peer struct {
-	Address string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
-	Cert    string `protobuf:"bytes,2,opt,name=cert" json:"cert,omitempty"`
-}
-
-func (m *Peer) Reset()                    { *m = Peer{} }
-func (m *Peer) String() string            { return proto.CompactTextString(m) }
-func (*Peer) ProtoMessage()               {}
-func (*Peer) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
-
-func init() {
-	proto.RegisterType((*ConsensusConfig)(nil), "sbft.consensus_config")
-	proto.RegisterType((*JsonConfig)(nil), "sbft.json_config")
-	proto.RegisterType((*Peer)(nil), "sbft.peer")
-}
-
-func init() { proto.RegisterFile("config.proto", fileDescriptor0) }
-
-var fileDescriptor0 = []byte{
-	// 271 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x9c, 0x91, 0x4d, 0x4b, 0xc3, 0x40,
-	0x10, 0x86, 0x49, 0x9b, 0x2a, 0x99, 0xf4, 0x50, 0x17, 0x0f, 0xb1, 0xa7, 0x98, 0x53, 0x10, 0xd9,
-	0x85, 0x2a, 0x58, 0x3c, 0x2a, 0xde, 0x25, 0x47, 0x2f, 0x9a, 0x8f, 0x49, 0x1a, 0x4d, 0xb3, 0x61,
-	0x76, 0x23, 0xe4, 0x8f, 0xf9, 0xfb, 0x64, 0xb3, 0xb5, 0x41, 0x8f, 0xde, 0xde, 0x19, 0x9e, 0xd9,
-	0xf7, 0x81, 0x85, 0x65, 0x2e, 0xdb, 0xb2, 0xae, 0x78, 0x47, 0x52, 0x4b, 0xe6, 0xaa, 0xac, 0xd4,
-	0xeb, 0x0b, 0x55, 0xef, 0xbb, 0x06, 0xb3, 0x52, 0x8b, 0x63, 0xb2, 0x40, 0xf4, 0xe5, 0xc0, 0x2a,
-	0x97, 0xad, 0xc2, 0x56, 0xf5, 0xea, 0xd5, 0xde, 0x32, 0x01, 0xde, 0x71, 0x17, 0x38, 0xa1, 0x13,
-	0xfb, 0x9b, 0x33, 0x3e, 0x5d, 0x3e, 0x8e, 0x54, 0x32, 0x31, 0xec, 0x0e, 0x16, 0x1d, 0x22, 0xa9,
-	0x60, 0x16, 0xce, 0x63, 0x7f, 0x73, 0xc9, 0x4d, 0x2d, 0xff, 0xfb, 0x2e, 0x7f, 0x36, 0xcc, 0x53,
-	0xab, 0x69, 0x48, 0x2c, 0xbf, 0xde, 0x02, 0x4c, 0x4b, 0xb6, 0x82, 0xf9, 0x07, 0x0e, 0x63, 0xa3,
-	0x97, 0x98, 0xc8, 0xce, 0x61, 0xf1, 0x99, 0x36, 0x3d, 0x06, 0xb3, 0xd0, 0x89, 0x97, 0x89, 0x1d,
-	0xee, 0x67, 0x5b, 0x27, 0x7a, 0x03, 0xff, 0x5d, 0xc9, 0xf6, 0xdf, 0xca, 0xe1, 0x6f, 0x65, 0xb0,
-	0xca, 0x66, 0x75, 0x70, 0x8b, 0x6e, 0xc1, 0x35, 0x81, 0x05, 0x70, 0x9a, 0x16, 0x05, 0xa1, 0x52,
-	0x07, 0xb3, 0x9f, 0x91, 0x31, 0x70, 0x73, 0x24, 0x3d, 0xca, 0x79, 0xc9, 0x98, 0x1f, 0xae, 0x5f,
-	0xae, 0xaa, 0x5a, 0xef, 0xfa, 0x8c, 0xe7, 0x72, 0x2f, 0x76, 0x43, 0x87, 0xd4, 0x60, 0x51, 0x21,
-	0x89, 0x32, 0xcd, 0xa8, 0xce, 0x85, 0xa4, 0x02, 0x09, 0x49, 0x98, 0xbe, 0xec, 0x64, 0xfc, 0x85,
-	0x9b, 0xef, 0x00, 0x00, 0x00, 0xff, 0xff, 0x82, 0x1e, 0x52, 0x33, 0xb6, 0x01, 0x00, 0x00,
-}
@@ -1,5 +1,10 @@
 #!/bin/sh
 
+# Sets up <nreplica> orderers ordering the provisional test chain with SBFT on
+# the local host. Replica n serves the atomic broadcast service on port 7100+n
+# and connects to the other replicas from port 6100+n. It is started by the
+# generated run-<n>.sh, with the orderer executable in the PATH.
+
 set -e
 
 dest=$1
@@ -12,14 +17,14 @@ then
     exit 1
 fi
 
-
+fabric=$(cd $(dirname $0)/../.. && pwd)
 fail=$((($count - 1)/3))
 
 mkdir $dest
 cd $dest
+dest=$(pwd)
 
 certtool --generate-privkey --outfile key.pem 2>/dev/null
-mkdir data
 
 peerconf=""
 
@@ -32,37 +37,47 @@ signing_key
 encryption_key
 EOF
     certtool --generate-self-signed --load-privkey key.pem --outfile cert$n.pem --template template$n.cfg 2>/dev/null
-    certtool -i --infile cert$n.pem --outder --outfile data/config.peers.:$((6100+$n)) 2>/dev/null
 
     peerconf=$(cat <<EOF
-${peerconf}${peerconf:+,}
-{
-  "address": ":$((6100+$n))",
-  "cert": "cert${n}.pem"
-}
+${peerconf}
+            - Address: 127.0.0.1:$((6100+$n))
+              Certificate: $dest/cert$n.pem
 EOF
 )
 done
 
-cat > config.json <<EOF
-{
-  "consensus": {
-	"n" : $count,
-	"f" : $fail,
-	"batch_size_bytes" : 1000,
-	"batch_duration_nsec" : 1000000000,
-	"request_timeout_nsec" : 1000000000
- },
-  "peers": [${peerconf}]
-}
-EOF
-
+# Each orderer creates the genesis block of the test chain from its own
+# configuration, which leaves the settings the orderer defaults out
 for n in $(seq 0 $nodeidmax)
 do
-    sbft -data-dir data$n -init config.json
+    mkdir replica$n
+    cat > replica$n/orderer.yaml <<EOF
+General:
+    LedgerType: file
+    ListenAddress: 127.0.0.1
+    ListenPort: $((7100+$n))
+    GenesisMethod: provisional
+    LocalMSPDir: $fabric/msp/sampleconfig/
+FileLedger:
+    Location: $dest/replica$n/ledger
+Genesis:
+    OrdererType: sbft
+    BatchTimeout: 1s
+    SbftShared:
+        "N": $count
+        F: $fail
+        RequestTimeout: 1s
+        Peers:${peerconf}
+SbftLocal:
+    PeerCommAddr: 127.0.0.1:$((6100+$n))
+    CertFile: $dest/cert$n.pem
+    KeyFile: $dest/key.pem
+    DataDir: $dest/replica$n/data
+EOF
+
     cat > run-$n.sh <<EOF
 #!/bin/sh
-sbft -addr :$((6100+$n)) -gaddr :$((7100+$n)) -cert cert$n.pem -key key.pem -data-dir data$n "\$@"
+ORDERER_CFG_PATH=$dest/replica$n exec orderer "\$@"
 EOF
     chmod +x run-$n.sh
 done
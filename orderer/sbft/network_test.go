@@ -0,0 +1,461 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sbft
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/orderer/common/bootstrap/provisional"
+	"github.com/hyperledger/fabric/orderer/localconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// The network tests run every replica as an orderer process of its own,
+// configured through the environment, and talk to the replicas through the
+// atomic broadcast service only.
+
+const ordererPkg = "github.com/hyperledger/fabric/orderer"
+
+var (
+	buildOnce  sync.Once
+	buildDir   string
+	ordererExe string
+)
+
+type ordererProcess struct {
+	id     uint64
+	port   int
+	cancel context.CancelFunc
+	cmd    *exec.Cmd
+}
+
+type receiver struct {
+	id      uint64
+	retch   chan []byte
+	signals chan bool
+}
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if buildDir != "" {
+		os.RemoveAll(buildDir)
+	}
+	os.Exit(code)
+}
+
+// buildOrderer builds the orderer executable, once for all network tests
+func buildOrderer(t *testing.T) {
+	buildOnce.Do(func() {
+		dir, err := ioutil.TempDir("", "sbft_network_test_bin")
+		if err != nil {
+			t.Fatalf("Failed to create a temporary directory: %s", err)
+		}
+		buildDir = dir
+		exe := filepath.Join(dir, "orderer")
+		buildcmd := exec.Command("go", "build", "-o", exe, ordererPkg)
+		buildcmd.Stdout = os.Stdout
+		buildcmd.Stderr = os.Stderr
+		if err := buildcmd.Run(); err != nil {
+			t.Fatalf("Failed to build the orderer: %s", err)
+		}
+		ordererExe = exe
+	})
+	if ordererExe == "" {
+		t.Fatalf("The orderer could not be built")
+	}
+}
+
+func TestNetworkSingleReplica(t *testing.T) {
+	t.Parallel()
+	skipInShortMode(t)
+	startingPort := 12000
+	orderers, cleanup := startOrderers(t, 1, startingPort)
+	defer cleanup()
+
+	r, err := receive(orderers[0])
+	if err != nil {
+		t.Fatalf("Failed to start up receiver: %s", err)
+	}
+	defer r.stop()
+	if berr := broadcast(orderers[0], []byte{0, 1, 2, 3}); berr != nil {
+		t.Fatalf("Failed to broadcast message: %s", berr)
+	}
+	if !assertWithTimeout(func() bool {
+		return r.received() == 2
+	}, 30) {
+		t.Errorf("Failed to receive some messages. (Received %d)", r.received())
+	}
+}
+
+func TestNetworkTwoReplicasBroadcastAndDeliverUsingTheSame(t *testing.T) {
+	t.Parallel()
+	skipInShortMode(t)
+	startingPort := 12100
+	orderers, cleanup := startOrderers(t, 2, startingPort)
+	defer cleanup()
+
+	r, err := receive(orderers[1])
+	if err != nil {
+		t.Fatalf("Failed to start up receiver: %s", err)
+	}
+	defer r.stop()
+	if berr := broadcast(orderers[1], []byte{0, 1, 2, 3, 4}); berr != nil {
+		t.Errorf("Failed to broadcast message: %s", berr)
+	}
+	if !assertWithTimeout(func() bool {
+		return r.received() == 2
+	}, 30) {
+		t.Errorf("Failed to receive some messages. (Received %d)", r.received())
+	}
+}
+
+func TestNetworkTenReplicasBroadcastAndDeliverUsingDifferent(t *testing.T) {
+	t.Parallel()
+	skipInShortMode(t)
+	startingPort := 12200
+	orderers, cleanup := startOrderers(t, 10, startingPort)
+	defer cleanup()
+
+	r, err := receive(orderers[9])
+	if err != nil {
+		t.Fatalf("Failed to start up receiver: %s", err)
+	}
+	defer r.stop()
+	if berr := broadcast(orderers[1], []byte{0, 1, 2, 3, 4}); berr != nil {
+		t.Errorf("Failed to broadcast message: %s", berr)
+	}
+	if !assertWithTimeout(func() bool {
+		return r.received() == 2
+	}, 30) {
+		t.Errorf("Failed to receive some messages. (Received %d)", r.received())
+	}
+}
+
+func TestNetworkFourReplicasBombedWithBroadcasts(t *testing.T) {
+	t.Parallel()
+	skipInShortMode(t)
+	startingPort := 12300
+	broadcastCount := 15
+	orderers, cleanup := startOrderers(t, 4, startingPort)
+	defer cleanup()
+
+	r, err := receive(orderers[2])
+	if err != nil {
+		t.Fatalf("Failed to start up receiver: %s", err)
+	}
+	defer r.stop()
+	for x := 0; x < broadcastCount; x++ {
+		if berr := broadcast(orderers[2], []byte{0, 1, 2, byte(x), 3, 4, byte(x)}); berr != nil {
+			t.Errorf("Failed to broadcast message: %s (broadcast number %d)", berr, x)
+		}
+		time.Sleep(time.Second)
+	}
+	if !assertWithTimeout(func() bool {
+		return r.received() == broadcastCount+1
+	}, 30) {
+		t.Errorf("Failed to receive some messages. (Received %d)", r.received())
+	}
+}
+
+func TestNetworkTenReplicasBombedWithBroadcasts(t *testing.T) {
+	t.Parallel()
+	skipInShortMode(t)
+	startingPort := 12400
+	broadcastCount := 15
+	orderers, cleanup := startOrderers(t, 10, startingPort)
+	defer cleanup()
+
+	r, err := receive(orderers[3])
+	if err != nil {
+		t.Fatalf("Failed to start up receiver: %s", err)
+	}
+	defer r.stop()
+	for x := 0; x < broadcastCount; x++ {
+		if berr := broadcast(orderers[2], []byte{0, 1, 2, byte(x), 3, 4, byte(x)}); berr != nil {
+			t.Errorf("Failed to broadcast message: %s (broadcast number %d)", berr, x)
+		}
+		time.Sleep(time.Second)
+	}
+	if !assertWithTimeout(func() bool {
+		return r.received() == broadcastCount+1
+	}, 60) {
+		t.Errorf("Failed to receive some messages. (Received %d)", r.received())
+	}
+}
+
+func TestNetworkTenReplicasBombedWithBroadcastsIfLedgersConsistent(t *testing.T) {
+	t.Parallel()
+	skipInShortMode(t)
+	startingPort := 12500
+	broadcastCount := 15
+	orderers, cleanup := startOrderers(t, 10, startingPort)
+	defer cleanup()
+
+	receivers := make([]*receiver, 0, len(orderers))
+	for _, o := range orderers {
+		r, err := receive(o)
+		if err != nil {
+			t.Fatalf("Failed to start up receiver: %s", err)
+		}
+		defer r.stop()
+		receivers = append(receivers, r)
+	}
+
+	for x := 0; x < broadcastCount; x++ {
+		if berr := broadcast(orderers[2], []byte{0, 1, 2, byte(x), 3, 4, byte(x)}); berr != nil {
+			t.Errorf("Failed to broadcast message: %s (broadcast number %d)", berr, x)
+		}
+		time.Sleep(time.Second)
+	}
+
+	for _, r := range receivers {
+		if !assertWithTimeout(func() bool {
+			return r.received() == broadcastCount+1
+		}, 60) {
+			t.Fatalf("Replica %d failed to receive some messages. (Received %d)", r.id, r.received())
+		}
+	}
+	// every replica delivers the same transactions in the same order
+	for i := 0; i < broadcastCount+1; i++ {
+		tx := <-receivers[0].retch
+		for _, r := range receivers[1:] {
+			if other := <-r.retch; !bytes.Equal(tx, other) {
+				t.Errorf("Replica %d delivered transaction %d differently from replica 0", r.id, i)
+			}
+		}
+	}
+}
+
+// startOrderers starts n orderer processes ordering the test chain with
+// SBFT, which all bootstrap from the same genesis block. Each of them uses
+// the port startingPort+2*id for the atomic broadcast service, and the
+// following port for the connections to the other replicas.
+func startOrderers(t *testing.T, n uint64, startingPort int) ([]*ordererProcess, func()) {
+	buildOrderer(t)
+	dir := tempDir(t)
+
+	ordererDir, err := filepath.Abs("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile, err := filepath.Abs(keyfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFiles := make([]string, n)
+	peers := make([]config.SbftPeer, n)
+	for i := uint64(0); i < n; i++ {
+		certFiles[i] = generateCertificate(t, dir, i)
+		peers[i] = config.SbftPeer{Address: peerCommAddress(startingPort, i), Certificate: certFiles[i]}
+	}
+	genesisFile := writeGenesisBlock(t, dir, n, peers)
+
+	orderers := make([]*ordererProcess, n)
+	for i := uint64(0); i < n; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		o := &ordererProcess{id: i, port: startingPort + 2*int(i), cancel: cancel}
+		o.cmd = exec.CommandContext(ctx, ordererExe)
+		o.cmd.Dir = ordererDir
+		o.cmd.Env = append(os.Environ(),
+			"ORDERER_GENERAL_LEDGERTYPE=ram",
+			"ORDERER_GENERAL_LISTENADDRESS=127.0.0.1",
+			fmt.Sprintf("ORDERER_GENERAL_LISTENPORT=%d", o.port),
+			"ORDERER_GENERAL_GENESISMETHOD=file",
+			"ORDERER_GENERAL_GENESISFILE="+genesisFile,
+			"ORDERER_GENESIS_ORDERERTYPE=sbft",
+			"ORDERER_SBFTLOCAL_PEERCOMMADDR="+peerCommAddress(startingPort, i),
+			"ORDERER_SBFTLOCAL_CERTFILE="+certFiles[i],
+			"ORDERER_SBFTLOCAL_KEYFILE="+keyFile,
+			"ORDERER_SBFTLOCAL_DATADIR="+filepath.Join(dir, fmt.Sprintf("data%d", i)),
+		)
+		o.cmd.Stdout = os.Stdout
+		o.cmd.Stderr = os.Stderr
+		if err := o.cmd.Start(); err != nil {
+			t.Fatalf("Failed to start orderer %d: %s", i, err)
+		}
+		orderers[i] = o
+	}
+	waitForNetwork(n)
+
+	return orderers, func() {
+		for _, o := range orderers {
+			o.cancel()
+			o.cmd.Wait()
+		}
+		os.RemoveAll(dir)
+	}
+}
+
+// writeGenesisBlock writes the genesis block of the test chain, ordered by
+// the given SBFT replicas, to a file, as the genesis blocks which the
+// provisional bootstrapper creates differ from one orderer to the other
+func writeGenesisBlock(t *testing.T, dir string, n uint64, peers []config.SbftPeer) string {
+	conf := config.Load()
+	conf.Genesis.OrdererType = provisional.ConsensusTypeSbft
+	conf.Genesis.BatchTimeout = 10 * time.Millisecond
+	conf.Genesis.SbftShared = config.SbftShared{
+		N:              n,
+		F:              (n - 1) / 3,
+		RequestTimeout: time.Second,
+		Peers:          peers,
+	}
+	genesisFile := filepath.Join(dir, "genesisblock")
+	if err := ioutil.WriteFile(genesisFile, utils.MarshalOrPanic(provisional.New(conf).GenesisBlock()), 0644); err != nil {
+		t.Fatalf("Failed to write the genesis block: %s", err)
+	}
+	return genesisFile
+}
+
+// waitForNetwork gives the orderers time to start and the replicas time to
+// connect to each other, as requests which are broadcast before that are lost
+func waitForNetwork(n uint64) {
+	<-time.After(time.Duration(math.Max(3, float64(n)-3)) * time.Second)
+}
+
+func broadcast(o *ordererProcess, data []byte) error {
+	timeout := 10 * time.Second
+	clientconn, err := grpc.Dial(o.address(), grpc.WithBlock(), grpc.WithTimeout(timeout), grpc.WithInsecure())
+	if err != nil {
+		return err
+	}
+	defer clientconn.Close()
+	client := ab.NewAtomicBroadcastClient(clientconn)
+	bstream, err := client.Broadcast(context.Background())
+	if err != nil {
+		return err
+	}
+	pl := &cb.Payload{
+		Header: &cb.Header{
+			ChainHeader:     &cb.ChainHeader{ChainID: provisional.TestChainID},
+			SignatureHeader: &cb.SignatureHeader{},
+		},
+		Data: data,
+	}
+	if e := bstream.Send(&cb.Envelope{Payload: utils.MarshalOrPanic(pl)}); e != nil {
+		return e
+	}
+	resp, err := bstream.Recv()
+	if err != nil {
+		return err
+	}
+	if resp.Status != cb.Status_SUCCESS {
+		return fmt.Errorf("broadcast was answered with status %s", resp.Status)
+	}
+	return nil
+}
+
+// receive delivers the blocks of the test chain from the given orderer,
+// counting the transactions they carry
+func receive(o *ordererProcess) (*receiver, error) {
+	retch := make(chan []byte, 100)
+	signals := make(chan bool, 100)
+	timeout := 10 * time.Second
+	clientconn, err := grpc.Dial(o.address(), grpc.WithBlock(), grpc.WithTimeout(timeout), grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	client := ab.NewAtomicBroadcastClient(clientconn)
+	dstream, err := client.Deliver(context.Background())
+	if err != nil {
+		clientconn.Close()
+		return nil, err
+	}
+	err = dstream.Send(&cb.Envelope{
+		Payload: utils.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChainHeader:     &cb.ChainHeader{ChainID: provisional.TestChainID},
+				SignatureHeader: &cb.SignatureHeader{},
+			},
+			Data: utils.MarshalOrPanic(&ab.SeekInfo{
+				Start:    &ab.SeekPosition{Type: &ab.SeekPosition_Oldest{Oldest: &ab.SeekOldest{}}},
+				Stop:     &ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: math.MaxUint64}}},
+				Behavior: ab.SeekInfo_BLOCK_UNTIL_READY,
+			}),
+		}),
+	})
+	if err != nil {
+		clientconn.Close()
+		return nil, err
+	}
+
+	go func() {
+		defer clientconn.Close()
+		for {
+			select {
+			case <-signals:
+				return
+			default:
+				m, inerr := dstream.Recv()
+				if inerr != nil {
+					return
+				}
+				b, ok := m.Type.(*ab.DeliverResponse_Block)
+				if !ok {
+					continue
+				}
+				for _, tx := range b.Block.Data.Data {
+					pl := &cb.Payload{}
+					e := &cb.Envelope{}
+					merr1 := proto.Unmarshal(tx, e)
+					merr2 := proto.Unmarshal(e.Payload, pl)
+					if merr1 == nil && merr2 == nil {
+						retch <- tx
+					}
+				}
+			}
+		}
+	}()
+	return &receiver{id: o.id, retch: retch, signals: signals}, nil
+}
+
+func (r *receiver) received() int {
+	return len(r.retch)
+}
+
+func (r *receiver) stop() {
+	close(r.signals)
+}
+
+func assertWithTimeout(assertion func() bool, timeoutSec int) bool {
+	for spent := 0; spent <= timeoutSec && !assertion(); spent++ {
+		time.Sleep(time.Second)
+	}
+	return assertion()
+}
+
+func (o *ordererProcess) address() string {
+	return fmt.Sprintf("127.0.0.1:%d", o.port)
+}
+
+func peerCommAddress(startingPort int, id uint64) string {
+	return fmt.Sprintf("127.0.0.1:%d", startingPort+2*int(id)+1)
+}
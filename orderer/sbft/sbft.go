@@ -0,0 +1,93 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sbft
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/hyperledger/fabric/orderer/localconfig"
+	"github.com/hyperledger/fabric/orderer/multichain"
+	"github.com/hyperledger/fabric/orderer/sbft/backend"
+	"github.com/hyperledger/fabric/orderer/sbft/connection"
+	"github.com/hyperledger/fabric/orderer/sbft/persist"
+	s "github.com/hyperledger/fabric/orderer/sbft/simplebft"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/op/go-logging"
+)
+
+var logger = logging.MustGetLogger("orderer/sbft")
+
+type consenter struct {
+	config config.SbftLocal
+
+	lock    sync.Mutex
+	backend *backend.Backend
+}
+
+// New creates a new consenter for the SBFT consensus scheme.
+// Every chain is ordered by its own SBFT instance, whose replica set is taken
+// from the SbftOptions of the chain configuration. The instances of all chains
+// share a single connection to each of the other replicas. Ordered messages
+// are formed into blocks by the blockcutter before being written to the ledger.
+func New(conf config.SbftLocal) multichain.Consenter {
+	return &consenter{config: conf}
+}
+
+// HandleChain creates the SBFT replica for the chain of the given support resources.
+func (sbft *consenter) HandleChain(support multichain.ConsenterSupport, metadata *cb.Metadata) (multichain.Chain, error) {
+	opts := support.SharedConfig().SbftOptions()
+	if opts == nil {
+		return nil, fmt.Errorf("no SBFT options in the configuration of chain %s", support.ChainID())
+	}
+
+	b, err := sbft.getBackend()
+	if err != nil {
+		return nil, err
+	}
+
+	conf := &s.Config{
+		N:                  opts.N,
+		F:                  opts.F,
+		BatchDurationNsec:  uint64(support.SharedConfig().BatchTimeout().Nanoseconds()),
+		BatchSizeBytes:     uint64(support.SharedConfig().BatchSize().PreferredMaxBytes),
+		RequestTimeoutNsec: opts.RequestTimeoutNsec,
+	}
+	persistence := persist.New(filepath.Join(sbft.config.DataDir, support.ChainID()))
+
+	return b.AddChain(support, opts.Peers, conf, persistence)
+}
+
+// getBackend returns the backend shared by all chains, creating it on first use
+func (sbft *consenter) getBackend() (*backend.Backend, error) {
+	sbft.lock.Lock()
+	defer sbft.lock.Unlock()
+
+	if sbft.backend != nil {
+		return sbft.backend, nil
+	}
+
+	conn, err := connection.New(sbft.config.PeerCommAddr, sbft.config.CertFile, sbft.config.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not set up replica connections on %s: %s", sbft.config.PeerCommAddr, err)
+	}
+	logger.Infof("Listening for SBFT replica connections on %s", sbft.config.PeerCommAddr)
+
+	sbft.backend = backend.NewBackend(conn)
+	return sbft.backend, nil
+}
@@ -0,0 +1,256 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sbft
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/orderer/localconfig"
+	mockblockcutter "github.com/hyperledger/fabric/orderer/mocks/blockcutter"
+	mockmultichain "github.com/hyperledger/fabric/orderer/mocks/multichain"
+	mocksharedconfig "github.com/hyperledger/fabric/orderer/mocks/sharedconfig"
+	"github.com/hyperledger/fabric/orderer/multichain"
+	"github.com/hyperledger/fabric/orderer/sbft/crypto"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+)
+
+const keyfile = "testdata/key.pem"
+
+type replica struct {
+	consenter multichain.Consenter
+	chains    map[string]multichain.Chain
+	supports  map[string]*mockmultichain.ConsenterSupport
+}
+
+func skipInShortMode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test in short mode.")
+	}
+}
+
+func TestNoSbftOptions(t *testing.T) {
+	support := newSupport("foo", nil)
+	_, err := New(config.SbftLocal{}).HandleChain(support, nil)
+	if err == nil {
+		t.Fatalf("Should have failed to handle a chain without SBFT options")
+	}
+}
+
+func TestLocalReplicaNotInPeers(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	certFile := generateCertificate(t, dir, 0)
+	other := generateCertificate(t, dir, 1)
+	opts := &ab.SbftOptions{N: 1, F: 0, RequestTimeoutNsec: uint64(time.Second), Peers: map[string][]byte{listenAddress(11000, 1): readCert(t, other)}}
+	conf := config.SbftLocal{PeerCommAddr: listenAddress(11000, 0), CertFile: certFile, KeyFile: keyfile, DataDir: dir}
+
+	_, err := New(conf).HandleChain(newSupport("foo", opts), nil)
+	if err == nil {
+		t.Fatalf("Should have failed to handle a chain which does not include the local replica")
+	}
+}
+
+func TestFourReplicasOrder(t *testing.T) {
+	skipInShortMode(t)
+	replicas, cleanup := startReplicas(t, 4, 11100, "foo")
+	defer cleanup()
+
+	if !replicas[2].chains["foo"].Enqueue(makeEnvelope(1)) {
+		t.Fatalf("Enqueue was rejected")
+	}
+	for i, r := range replicas {
+		expectBatch(t, i, r.supports["foo"], 1)
+	}
+}
+
+func TestFourReplicasMultipleChains(t *testing.T) {
+	skipInShortMode(t)
+	replicas, cleanup := startReplicas(t, 4, 11200, "foo", "bar")
+	defer cleanup()
+
+	replicas[0].chains["foo"].Enqueue(makeEnvelope(1))
+	replicas[3].chains["bar"].Enqueue(makeEnvelope(2))
+	for i, r := range replicas {
+		expectBatch(t, i, r.supports["foo"], 1)
+		expectBatch(t, i, r.supports["bar"], 2)
+	}
+}
+
+func TestHaltedChainRejectsEnqueue(t *testing.T) {
+	replicas, cleanup := startReplicas(t, 1, 11300, "foo")
+	defer cleanup()
+
+	replicas[0].chains["foo"].Halt()
+	if replicas[0].chains["foo"].Enqueue(makeEnvelope(1)) {
+		t.Fatalf("Enqueue should be rejected after Halt")
+	}
+}
+
+func startReplicas(t *testing.T, n uint64, startingPort int, chainIDs ...string) ([]*replica, func()) {
+	dir := tempDir(t)
+
+	peers := make(map[string][]byte)
+	certFiles := make([]string, n)
+	for i := uint64(0); i < n; i++ {
+		certFiles[i] = generateCertificate(t, dir, i)
+		peers[listenAddress(startingPort, i)] = readCert(t, certFiles[i])
+	}
+	opts := &ab.SbftOptions{N: n, F: (n - 1) / 3, RequestTimeoutNsec: uint64(time.Second), Peers: peers}
+
+	replicas := make([]*replica, n)
+	for i := uint64(0); i < n; i++ {
+		r := &replica{
+			consenter: New(config.SbftLocal{
+				PeerCommAddr: listenAddress(startingPort, i),
+				CertFile:     certFiles[i],
+				KeyFile:      keyfile,
+				DataDir:      filepath.Join(dir, fmt.Sprintf("data%d", i)),
+			}),
+			chains:   make(map[string]multichain.Chain),
+			supports: make(map[string]*mockmultichain.ConsenterSupport),
+		}
+		for _, chainID := range chainIDs {
+			support := newSupport(chainID, opts)
+			chain, err := r.consenter.HandleChain(support, nil)
+			if err != nil {
+				t.Fatalf("Replica %d failed to handle chain %s: %s", i, chainID, err)
+			}
+			chain.Start()
+			r.chains[chainID] = chain
+			r.supports[chainID] = support
+		}
+		replicas[i] = r
+	}
+	waitForConnection(n)
+
+	return replicas, func() {
+		for _, r := range replicas {
+			for _, chain := range r.chains {
+				chain.Halt()
+			}
+		}
+		os.RemoveAll(dir)
+	}
+}
+
+// waitForConnection gives the replicas time to connect to each other, as
+// requests which are broadcast before that are lost
+func waitForConnection(n uint64) {
+	if n > 1 {
+		time.Sleep(3 * time.Second)
+	}
+}
+
+func newSupport(chainID string, opts *ab.SbftOptions) *mockmultichain.ConsenterSupport {
+	cutter := mockblockcutter.NewReceiver()
+	close(cutter.Block)
+	return &mockmultichain.ConsenterSupport{
+		SharedConfigVal: &mocksharedconfig.Manager{
+			BatchTimeoutVal: 10 * time.Millisecond,
+			BatchSizeVal:    &ab.BatchSize{PreferredMaxBytes: 1024},
+			SbftOptionsVal:  opts,
+		},
+		BlockCutterVal: cutter,
+		Batches:        make(chan []*cb.Envelope, 10),
+		ChainIDVal:     chainID,
+	}
+}
+
+func makeEnvelope(i int) *cb.Envelope {
+	return &cb.Envelope{Payload: []byte(fmt.Sprintf("%d", i))}
+}
+
+func expectBatch(t *testing.T, replica int, support *mockmultichain.ConsenterSupport, i int) {
+	select {
+	case batch := <-support.Batches:
+		if len(batch) != 1 || string(batch[0].Payload) != string(makeEnvelope(i).Payload) {
+			t.Errorf("Replica %d wrote an unexpected batch to chain %s: %v", replica, support.ChainIDVal, batch)
+		}
+	case <-time.After(30 * time.Second):
+		t.Errorf("Replica %d did not write a batch to chain %s", replica, support.ChainIDVal)
+	}
+}
+
+func listenAddress(startingPort int, id uint64) string {
+	return fmt.Sprintf("127.0.0.1:%d", startingPort+int(id))
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "sbft_test")
+	if err != nil {
+		t.Fatalf("Failed to create a temporary directory: %s", err)
+	}
+	return dir
+}
+
+func readCert(t *testing.T, certFile string) []byte {
+	cert, err := crypto.ParseCertPEM(certFile)
+	if err != nil {
+		t.Fatalf("Failed to read certificate %s: %s", certFile, err)
+	}
+	return cert
+}
+
+func generateCertificate(t *testing.T, dir string, id uint64) string {
+	readBytes, err := ioutil.ReadFile(keyfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := pem.Decode(readBytes)
+	priv, err := x509.ParsePKCS1PrivateKey(b.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notBefore := time.Now()
+	notAfter := notBefore.Add(time.Hour)
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(int64(id)),
+		Subject: pkix.Name{
+			Organization: []string{"Acme Co"},
+		},
+		NotBefore: notBefore,
+		NotAfter:  notAfter,
+
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPath := filepath.Join(dir, fmt.Sprintf("cert%d.pem", id))
+	certOut, err := os.Create(certPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pem.Encode(certOut, &pem.Block{Type: "CERTIFICATE", Bytes: derBytes})
+	certOut.Close()
+	return certPath
+}
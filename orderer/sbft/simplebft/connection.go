@@ -73,14 +73,15 @@ func (s *SBFT) handleHello(h *Hello, src uint64) {
 		return
 	}
 
+	s.replicaState[src].hello = h
+
 	if s.sys.LastBatch().DecodeHeader().Seq < bh.Seq {
-		log.Debugf("replica %d: delivering batch %d after hello from replica %d", s.id, bh.Seq, src)
-		s.deliverBatch(h.Batch)
+		log.Debugf("replica %d: fetching batches up to %d after hello from replica %d", s.id, bh.Seq, src)
+		s.startStateTransfer(bh.Seq, h.Batch.Hash())
 	}
 
 	s.handleNewView(h.NewView, src)
 
-	s.replicaState[src].hello = h
 	s.discardBacklog(src)
 	s.processBacklog()
 }
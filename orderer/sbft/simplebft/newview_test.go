@@ -22,7 +22,7 @@ import (
 )
 
 func TestXsetNoByz(t *testing.T) {
-	s := &SBFT{config: Config{N: 4, F: 1}, view: 3}
+	s := &SBFT{config: &Config{N: 4, F: 1}, view: 3}
 	vcs := []*ViewChange{
 		&ViewChange{
 			View: 3,
@@ -57,7 +57,7 @@ func TestXsetNoByz(t *testing.T) {
 }
 
 func TestXsetNoNew(t *testing.T) {
-	s := &SBFT{config: Config{N: 4, F: 1}, view: 3}
+	s := &SBFT{config: &Config{N: 4, F: 1}, view: 3}
 	prev := s.makeBatch(2, []byte("prev"), nil)
 	vcs := []*ViewChange{
 		&ViewChange{
@@ -95,7 +95,7 @@ func TestXsetNoNew(t *testing.T) {
 }
 
 func TestXsetByz0(t *testing.T) {
-	s := &SBFT{config: Config{N: 4, F: 1}, view: 3}
+	s := &SBFT{config: &Config{N: 4, F: 1}, view: 3}
 	vcs := []*ViewChange{
 		&ViewChange{
 			View:       3,
@@ -141,7 +141,7 @@ func TestXsetByz0(t *testing.T) {
 }
 
 func TestXsetByz2(t *testing.T) {
-	s := &SBFT{config: Config{N: 4, F: 1}, view: 3}
+	s := &SBFT{config: &Config{N: 4, F: 1}, view: 3}
 	vcs := []*ViewChange{
 		&ViewChange{
 			View:       3,
@@ -30,6 +30,7 @@ const preprepared string = "preprepared"
 const prepared string = "prepared"
 const committed string = "committed"
 const viewchange string = "viewchange"
+const batchPrefix string = "batch-"
 
 // Receiver defines the API that is exposed by SBFT to the system.
 type Receiver interface {
@@ -61,7 +62,7 @@ type Canceller interface {
 type SBFT struct {
 	sys System
 
-	config            Config
+	config            *Config
 	id                uint64
 	view              uint64
 	batch             []*Request
@@ -73,6 +74,7 @@ type SBFT struct {
 	viewChangeTimer   Canceller
 	replicaState      []replicaInfo
 	pending           map[string]*Request
+	xfer              *xferInfo
 }
 
 type reqInfo struct {
@@ -90,6 +92,7 @@ type reqInfo struct {
 type replicaInfo struct {
 	backLog          []*Msg
 	hello            *Hello
+	checkpoints      []*Checkpoint
 	signedViewchange *Signed
 	viewchange       *ViewChange
 }
@@ -100,14 +103,16 @@ type dummyCanceller struct{}
 
 func (d dummyCanceller) Cancel() {}
 
-// New creates a new SBFT instance.
+// New creates a new SBFT instance. The batch duration of the configuration is
+// read each time a batch timer is started, so the system may change it between
+// the calls it receives.
 func New(id uint64, config *Config, sys System) (*SBFT, error) {
 	if config.F*3+1 > config.N {
 		return nil, fmt.Errorf("invalid combination of N and F")
 	}
 
 	s := &SBFT{
-		config:          *config,
+		config:          config,
 		sys:             sys,
 		id:              id,
 		viewChangeTimer: dummyCanceller{},
@@ -225,6 +230,16 @@ func (s *SBFT) Receive(m *Msg, src uint64) {
 	} else if nv := m.GetNewView(); nv != nil {
 		s.handleNewView(nv, src)
 		return
+	} else if fb := m.GetFetchBatch(); fb != nil {
+		s.handleFetchBatch(fb, src)
+		return
+	} else if b := m.GetBatch(); b != nil {
+		s.handleBatch(b, src)
+		return
+	}
+
+	if c := m.GetCheckpoint(); c != nil {
+		s.noteCheckpoint(c, src)
 	}
 
 	if s.testBacklogMessage(m, src) {
@@ -258,6 +273,7 @@ func (s *SBFT) deliverBatch(batch *Batch) {
 	s.cur.checkpointDone = true
 	s.cur.timeout.Cancel()
 	s.sys.Deliver(batch)
+	s.storeBatch(batch)
 
 	for _, req := range batch.Payloads {
 		key := hash2str(hash(req))
@@ -22,6 +22,7 @@ It has these top-level messages:
 	NewView
 	Checkpoint
 	Hello
+	FetchBatch
 */
 package simplebft
 
@@ -63,6 +64,8 @@ type Msg struct {
 	//	*Msg_NewView
 	//	*Msg_Checkpoint
 	//	*Msg_Hello
+	//	*Msg_FetchBatch
+	//	*Msg_Batch
 	Type isMsg_Type `protobuf_oneof:"type"`
 }
 
@@ -99,6 +102,12 @@ type Msg_Checkpoint struct {
 type Msg_Hello struct {
 	Hello *Hello `protobuf:"bytes,8,opt,name=hello,oneof"`
 }
+type Msg_FetchBatch struct {
+	FetchBatch *FetchBatch `protobuf:"bytes,9,opt,name=fetch_batch,oneof"`
+}
+type Msg_Batch struct {
+	Batch *Batch `protobuf:"bytes,10,opt,name=batch,oneof"`
+}
 
 func (*Msg_Request) isMsg_Type()    {}
 func (*Msg_Preprepare) isMsg_Type() {}
@@ -108,6 +117,8 @@ func (*Msg_ViewChange) isMsg_Type() {}
 func (*Msg_NewView) isMsg_Type()    {}
 func (*Msg_Checkpoint) isMsg_Type() {}
 func (*Msg_Hello) isMsg_Type()      {}
+func (*Msg_FetchBatch) isMsg_Type() {}
+func (*Msg_Batch) isMsg_Type()      {}
 
 func (m *Msg) GetType() isMsg_Type {
 	if m != nil {
@@ -172,6 +183,20 @@ func (m *Msg) GetHello() *Hello {
 	return nil
 }
 
+func (m *Msg) GetFetchBatch() *FetchBatch {
+	if x, ok := m.GetType().(*Msg_FetchBatch); ok {
+		return x.FetchBatch
+	}
+	return nil
+}
+
+func (m *Msg) GetBatch() *Batch {
+	if x, ok := m.GetType().(*Msg_Batch); ok {
+		return x.Batch
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*Msg) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _Msg_OneofMarshaler, _Msg_OneofUnmarshaler, _Msg_OneofSizer, []interface{}{
@@ -183,6 +208,8 @@ func (*Msg) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, fu
 		(*Msg_NewView)(nil),
 		(*Msg_Checkpoint)(nil),
 		(*Msg_Hello)(nil),
+		(*Msg_FetchBatch)(nil),
+		(*Msg_Batch)(nil),
 	}
 }
 
@@ -230,6 +257,16 @@ func _Msg_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.Hello); err != nil {
 			return err
 		}
+	case *Msg_FetchBatch:
+		b.EncodeVarint(9<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.FetchBatch); err != nil {
+			return err
+		}
+	case *Msg_Batch:
+		b.EncodeVarint(10<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Batch); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("Msg.Type has unexpected type %T", x)
@@ -304,6 +341,22 @@ func _Msg_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (b
 		err := b.DecodeMessage(msg)
 		m.Type = &Msg_Hello{msg}
 		return true, err
+	case 9: // type.fetch_batch
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(FetchBatch)
+		err := b.DecodeMessage(msg)
+		m.Type = &Msg_FetchBatch{msg}
+		return true, err
+	case 10: // type.batch
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(Batch)
+		err := b.DecodeMessage(msg)
+		m.Type = &Msg_Batch{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -353,6 +406,16 @@ func _Msg_OneofSizer(msg proto.Message) (n int) {
 		n += proto.SizeVarint(8<<3 | proto.WireBytes)
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *Msg_FetchBatch:
+		s := proto.Size(x.FetchBatch)
+		n += proto.SizeVarint(9<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Msg_Batch:
+		s := proto.Size(x.Batch)
+		n += proto.SizeVarint(10<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -560,6 +623,15 @@ func (m *Hello) GetNewView() *NewView {
 	return nil
 }
 
+type FetchBatch struct {
+	Seq uint64 `protobuf:"varint,1,opt,name=seq" json:"seq,omitempty"`
+}
+
+func (m *FetchBatch) Reset()                    { *m = FetchBatch{} }
+func (m *FetchBatch) String() string            { return proto.CompactTextString(m) }
+func (*FetchBatch) ProtoMessage()               {}
+func (*FetchBatch) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
 func init() {
 	proto.RegisterType((*Config)(nil), "simplebft.Config")
 	proto.RegisterType((*Msg)(nil), "simplebft.Msg")
@@ -574,60 +646,56 @@ func init() {
 	proto.RegisterType((*NewView)(nil), "simplebft.NewView")
 	proto.RegisterType((*Checkpoint)(nil), "simplebft.Checkpoint")
 	proto.RegisterType((*Hello)(nil), "simplebft.Hello")
+	proto.RegisterType((*FetchBatch)(nil), "simplebft.FetchBatch")
 }
 
 func init() { proto.RegisterFile("simplebft/simplebft.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 799 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x8c, 0x55, 0xdd, 0x6e, 0xf3, 0x44,
-	0x10, 0xad, 0x63, 0xc7, 0x49, 0x26, 0x15, 0xe4, 0x5b, 0x4a, 0x65, 0x4a, 0x2f, 0x22, 0x83, 0x4a,
-	0x2e, 0x20, 0xa9, 0x42, 0x05, 0xa8, 0x12, 0x12, 0x4a, 0x41, 0x44, 0x48, 0x54, 0xc8, 0xad, 0x2a,
-	0xd1, 0x0b, 0x22, 0xc7, 0x9e, 0xd8, 0xa6, 0x89, 0xed, 0x78, 0x37, 0x49, 0xd3, 0x87, 0xe1, 0x82,
-	0x27, 0xe0, 0x01, 0x78, 0x23, 0x5e, 0x02, 0xed, 0x4f, 0x6c, 0x37, 0x3f, 0xd5, 0x27, 0xf9, 0x62,
-	0x77, 0xce, 0xd9, 0xdd, 0x39, 0xb3, 0x67, 0xd6, 0xf0, 0x09, 0x8d, 0x66, 0xe9, 0x14, 0xc7, 0x13,
-	0xd6, 0xcb, 0x47, 0xdd, 0x34, 0x4b, 0x58, 0x42, 0x1a, 0x79, 0xc0, 0xfe, 0x47, 0x03, 0xf3, 0x26,
-	0x89, 0x27, 0x51, 0x40, 0x8e, 0x41, 0x8b, 0x2d, 0xad, 0xad, 0x75, 0x0c, 0x47, 0x8b, 0xf9, 0x6c,
-	0x62, 0x55, 0xe4, 0x6c, 0x42, 0xba, 0xf0, 0xd1, 0xd8, 0x65, 0x5e, 0x38, 0xf2, 0x17, 0x99, 0xcb,
-	0xa2, 0x24, 0x1e, 0xc5, 0x14, 0x3d, 0x4b, 0x17, 0xf8, 0x3b, 0x01, 0xfd, 0xa8, 0x90, 0x5b, 0x8a,
-	0x1e, 0xe9, 0x40, 0x4b, 0xf2, 0x69, 0xf4, 0x82, 0xa3, 0xf1, 0x9a, 0x21, 0xb5, 0x0c, 0x41, 0xfe,
-	0x40, 0xc4, 0xef, 0xa2, 0x17, 0x1c, 0xf0, 0x28, 0xb9, 0x84, 0x93, 0x0c, 0xe7, 0x0b, 0xa4, 0x6c,
-	0xc4, 0xa2, 0x19, 0x26, 0x0b, 0x26, 0xb7, 0xae, 0x0a, 0x36, 0x51, 0xd8, 0xbd, 0x84, 0xf8, 0xde,
-	0xf6, 0x5f, 0x3a, 0xe8, 0xbf, 0xd2, 0x80, 0x74, 0xa1, 0xa6, 0x50, 0x91, 0x75, 0xb3, 0x4f, 0xba,
-	0x85, 0x50, 0x47, 0x22, 0xc3, 0x23, 0x67, 0x43, 0x22, 0xdf, 0x02, 0xa4, 0x19, 0xf2, 0xcf, 0xcd,
-	0x50, 0x48, 0x6b, 0xf6, 0x3f, 0x2e, 0x2d, 0xf9, 0x2d, 0x07, 0x87, 0x47, 0x4e, 0x89, 0xca, 0x0f,
-	0xda, 0xac, 0xd2, 0x77, 0x0e, 0xba, 0x5b, 0x8c, 0xff, 0x44, 0x4f, 0x1c, 0xb4, 0xe1, 0x7f, 0x09,
-	0xa6, 0x97, 0xcc, 0x66, 0x11, 0x13, 0x92, 0x0f, 0xd1, 0x15, 0x87, 0x5c, 0x41, 0x73, 0x19, 0xe1,
-	0x6a, 0xe4, 0x85, 0x6e, 0x1c, 0xa0, 0xd0, 0xdd, 0xec, 0xbf, 0x2b, 0x2f, 0x89, 0x82, 0x18, 0x7d,
-	0x9e, 0x13, 0xe7, 0xdd, 0x08, 0x1a, 0xe9, 0x41, 0x3d, 0xc6, 0xd5, 0x88, 0x47, 0x2c, 0x73, 0xe7,
-	0x94, 0x5b, 0x5c, 0x3d, 0x44, 0xb8, 0xe2, 0x49, 0xc5, 0x72, 0xc8, 0xd5, 0x7b, 0x21, 0x7a, 0x4f,
-	0x69, 0x12, 0xc5, 0xcc, 0xaa, 0xed, 0xa8, 0xbf, 0xc9, 0x41, 0x7e, 0x52, 0x41, 0x25, 0x1d, 0xa8,
-	0x86, 0x38, 0x9d, 0x26, 0x56, 0x5d, 0xac, 0x69, 0x95, 0xd6, 0x0c, 0x79, 0x7c, 0x78, 0xe4, 0x48,
-	0xc2, 0xc0, 0x04, 0x83, 0xad, 0x53, 0xb4, 0x3f, 0x83, 0x9a, 0x2a, 0x3f, 0xb1, 0xa0, 0x96, 0xba,
-	0xeb, 0x69, 0xe2, 0xfa, 0xe2, 0x8e, 0x8e, 0x9d, 0xcd, 0xd4, 0xee, 0x41, 0xed, 0x0e, 0xe7, 0x22,
-	0x35, 0x02, 0x86, 0xd0, 0x21, 0xbd, 0x27, 0xc6, 0xa4, 0x05, 0x3a, 0xc5, 0xb9, 0x32, 0x20, 0x1f,
-	0xda, 0xbf, 0x43, 0x73, 0xc0, 0xad, 0x33, 0x44, 0xd7, 0xc7, 0x6c, 0x43, 0xd0, 0x72, 0x02, 0xf9,
-	0x14, 0x1a, 0x69, 0x86, 0xcb, 0x51, 0xe8, 0xd2, 0x50, 0x2c, 0x3c, 0x76, 0xea, 0x3c, 0x30, 0x74,
-	0x69, 0xc8, 0x41, 0xdf, 0x65, 0xae, 0x04, 0x75, 0x09, 0xf2, 0x00, 0x07, 0xed, 0x7f, 0x35, 0xa8,
-	0x8a, 0xbd, 0xc9, 0x29, 0x98, 0xa1, 0xd8, 0x5f, 0xa5, 0xab, 0x66, 0xe4, 0x0c, 0xea, 0x2a, 0x71,
-	0x6a, 0x55, 0xda, 0xba, 0xd8, 0x5a, 0xcd, 0xc9, 0x0f, 0x00, 0x34, 0x0a, 0x62, 0x97, 0x2d, 0x32,
-	0xa4, 0x96, 0xde, 0xd6, 0x3b, 0xcd, 0x7e, 0xbb, 0x54, 0x25, 0xb1, 0xb3, 0xb8, 0x45, 0x49, 0xf9,
-	0x29, 0x66, 0xd9, 0xda, 0x29, 0xad, 0x39, 0xfb, 0x1e, 0x3e, 0xdc, 0x82, 0xb9, 0xbc, 0x27, 0x5c,
-	0x6f, 0xe4, 0x3d, 0xe1, 0x9a, 0x9c, 0x40, 0x75, 0xe9, 0x4e, 0x17, 0xa8, 0xa4, 0xc9, 0xc9, 0x75,
-	0xe5, 0x3b, 0xcd, 0x7e, 0x04, 0x28, 0xbc, 0x4b, 0x3e, 0x2f, 0x0a, 0xb3, 0x65, 0x3d, 0x59, 0x6e,
-	0x59, 0xac, 0x0b, 0xa8, 0x8a, 0x46, 0x54, 0x7d, 0xd0, 0xda, 0xce, 0xd7, 0x91, 0xb0, 0xfd, 0x33,
-	0xd4, 0x94, 0x65, 0xdf, 0x73, 0xe3, 0x53, 0x30, 0xfd, 0x28, 0xe0, 0x4d, 0x29, 0xf3, 0x54, 0x33,
-	0xfb, 0x6f, 0x0d, 0xe0, 0xa1, 0xf0, 0xef, 0xbe, 0x3b, 0xbf, 0x00, 0x23, 0xa5, 0xc8, 0x44, 0x81,
-	0xf7, 0x76, 0x8d, 0x23, 0x70, 0xce, 0x9b, 0x73, 0x9e, 0x7e, 0x98, 0xc7, 0x71, 0x72, 0xf9, 0xca,
-	0xf2, 0xc6, 0x01, 0xa1, 0x25, 0x8e, 0x7d, 0x0d, 0xa6, 0xec, 0x36, 0x9e, 0x1f, 0xb7, 0x87, 0xb2,
-	0x81, 0x18, 0x93, 0x73, 0x68, 0xe4, 0x97, 0xa6, 0xd4, 0x15, 0x01, 0xfb, 0x3f, 0x0d, 0x6a, 0xaa,
-	0xef, 0xf6, 0xaa, 0xbb, 0x04, 0x63, 0x59, 0xa8, 0x3b, 0xdf, 0xed, 0xd6, 0xee, 0x03, 0x45, 0x26,
-	0xcd, 0x21, 0x98, 0x5c, 0xe7, 0xb3, 0xd4, 0xa9, 0x1d, 0xd2, 0xf9, 0x2c, 0x79, 0xea, 0x2e, 0x8d,
-	0x37, 0xef, 0xf2, 0xec, 0x17, 0x68, 0xe4, 0x47, 0xec, 0x31, 0xd8, 0x17, 0x65, 0x83, 0xed, 0x7b,
-	0x82, 0xca, 0x9e, 0xbb, 0x07, 0x28, 0x5e, 0x8c, 0x3d, 0xcd, 0x78, 0xc0, 0x06, 0xaf, 0x6b, 0xa8,
-	0x6f, 0xd7, 0xf0, 0x0f, 0xa8, 0x8a, 0x37, 0xa5, 0x90, 0xa4, 0xbd, 0x29, 0x89, 0x7c, 0x55, 0x7a,
-	0x06, 0x2b, 0x87, 0x9e, 0xc1, 0xfc, 0x11, 0x1c, 0x7c, 0xf3, 0x78, 0x15, 0x44, 0x2c, 0x5c, 0x8c,
-	0xbb, 0x5e, 0x32, 0xeb, 0x85, 0xeb, 0x14, 0xb3, 0x29, 0xfa, 0x01, 0x66, 0xbd, 0x89, 0x3b, 0xce,
-	0x22, 0xaf, 0x97, 0x64, 0x3e, 0x66, 0x98, 0xf5, 0xe8, 0xab, 0xdf, 0xe6, 0xd8, 0x14, 0xff, 0xcd,
-	0xaf, 0xff, 0x0f, 0x00, 0x00, 0xff, 0xff, 0x6d, 0x7b, 0xcb, 0xdb, 0x54, 0x07, 0x00, 0x00,
+	// 709 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x55, 0xdb, 0x6e, 0xd3, 0x4a,
+	0x14, 0x8d, 0x63, 0xc7, 0x69, 0xb6, 0x73, 0x7a, 0x99, 0x73, 0x8e, 0xe4, 0xf6, 0x54, 0x6a, 0x8e,
+	0x15, 0x50, 0xb8, 0x28, 0x11, 0xa1, 0x42, 0xa8, 0x42, 0x08, 0xb5, 0x02, 0xe5, 0x85, 0x0a, 0x51,
+	0xa9, 0x0f, 0xbc, 0x44, 0x8e, 0xbd, 0x63, 0x9b, 0x26, 0xb6, 0x33, 0x33, 0x4e, 0x08, 0xcf, 0x7c,
+	0x01, 0x7f, 0xc0, 0xcf, 0xf0, 0x5d, 0x68, 0x66, 0xdc, 0x38, 0x57, 0x90, 0xf2, 0x30, 0x9a, 0xbd,
+	0xb2, 0xbc, 0xf6, 0x5e, 0x6b, 0xdb, 0x70, 0xcc, 0xa2, 0x71, 0x3a, 0xc2, 0xc1, 0x90, 0x77, 0x16,
+	0xa7, 0x76, 0x4a, 0x13, 0x9e, 0x90, 0xda, 0xe2, 0xc2, 0x61, 0x60, 0x5e, 0x25, 0xf1, 0x30, 0x0a,
+	0x48, 0x0d, 0xb4, 0xd8, 0xd6, 0x1a, 0x5a, 0xcb, 0x10, 0xc7, 0xa1, 0x5d, 0x96, 0xc7, 0xff, 0xe0,
+	0xef, 0x81, 0xcb, 0xbd, 0xb0, 0xef, 0x67, 0xd4, 0xe5, 0x51, 0x12, 0xf7, 0x63, 0x86, 0x9e, 0xad,
+	0xcb, 0xa2, 0x0d, 0x87, 0xaa, 0xc8, 0xa2, 0xaf, 0xd8, 0x1f, 0xcc, 0x39, 0x32, 0xdb, 0x90, 0x95,
+	0x53, 0xf8, 0x87, 0xe2, 0x24, 0x43, 0xc6, 0xfb, 0x3c, 0x1a, 0x63, 0x92, 0x71, 0xf5, 0xbf, 0x8a,
+	0xa8, 0x3a, 0x3f, 0x74, 0xd0, 0xdf, 0xb3, 0x80, 0x3c, 0x80, 0x6a, 0x8e, 0x92, 0x0f, 0xb6, 0xba,
+	0xa4, 0x5d, 0x48, 0xfd, 0xa8, 0x2a, 0xbd, 0x12, 0x79, 0x02, 0x90, 0x52, 0x14, 0x3f, 0x97, 0xa2,
+	0xd4, 0x65, 0x75, 0xff, 0x5d, 0x42, 0x7e, 0x58, 0x14, 0x7b, 0x25, 0xc1, 0x79, 0x8f, 0xd4, 0x37,
+	0x38, 0x6f, 0xb2, 0xc1, 0x67, 0xf4, 0x04, 0x67, 0x13, 0x4c, 0x2f, 0x19, 0x8f, 0x23, 0x6e, 0x1b,
+	0xbf, 0x41, 0xb5, 0xc0, 0x9a, 0x46, 0x38, 0xeb, 0x7b, 0xa1, 0x1b, 0x07, 0x28, 0xd5, 0x5b, 0xdd,
+	0xa3, 0x65, 0x68, 0x14, 0xc4, 0xe8, 0xf7, 0x4a, 0xe4, 0x21, 0xec, 0xc5, 0x38, 0xeb, 0x0b, 0xb4,
+	0x6d, 0x6e, 0x30, 0x5e, 0xe3, 0xec, 0x36, 0xc2, 0x99, 0xea, 0xc5, 0x0b, 0xd1, 0xbb, 0x4b, 0x93,
+	0x28, 0xe6, 0x76, 0x75, 0xa3, 0x97, 0xab, 0x45, 0xb1, 0x57, 0x22, 0xff, 0x43, 0x25, 0xc4, 0xd1,
+	0x28, 0xb1, 0xf7, 0x24, 0xee, 0x70, 0x09, 0xd7, 0x13, 0xf7, 0xbd, 0x12, 0x79, 0x0a, 0xd6, 0x10,
+	0x85, 0x05, 0xd2, 0x08, 0xbb, 0xb6, 0x41, 0xf8, 0x4e, 0x54, 0x2f, 0x45, 0x51, 0x11, 0x2a, 0x1c,
+	0x6c, 0x10, 0xe6, 0x90, 0x4b, 0x13, 0x0c, 0x3e, 0x4f, 0xd1, 0x39, 0x81, 0x6a, 0xee, 0x00, 0x39,
+	0x80, 0x6a, 0xea, 0xce, 0x47, 0x89, 0xeb, 0x4b, 0x9b, 0xea, 0x4e, 0x13, 0xaa, 0x37, 0x38, 0x11,
+	0x1d, 0x91, 0x3a, 0x18, 0xb2, 0x67, 0x15, 0x1c, 0x0b, 0x74, 0x86, 0x13, 0x15, 0x1d, 0xe7, 0x0d,
+	0x58, 0x8a, 0x14, 0x5d, 0x1f, 0xe9, 0x7d, 0x4d, 0x01, 0x8f, 0xa0, 0x96, 0x52, 0x9c, 0xf6, 0x43,
+	0x97, 0x85, 0x12, 0x5e, 0x17, 0x57, 0xbe, 0xcb, 0x5d, 0x75, 0xa5, 0xcb, 0xe7, 0x7c, 0xd7, 0xa0,
+	0x22, 0x29, 0xc8, 0x3e, 0x98, 0xa1, 0xa4, 0x51, 0x0a, 0xc8, 0x21, 0xec, 0xe5, 0x92, 0x98, 0x5d,
+	0x6e, 0xe8, 0xad, 0x3a, 0x39, 0x07, 0x60, 0x51, 0x10, 0xbb, 0x3c, 0xa3, 0xc8, 0x6c, 0xbd, 0xa1,
+	0xb7, 0xac, 0x6e, 0x63, 0xbd, 0xbf, 0xf6, 0xcd, 0x02, 0xf2, 0x36, 0xe6, 0x74, 0x7e, 0xf2, 0x0c,
+	0x0e, 0xd6, 0xae, 0x84, 0xce, 0x3b, 0x9c, 0xe7, 0x3a, 0xff, 0x82, 0xca, 0xd4, 0x1d, 0x65, 0x2a,
+	0x75, 0xf5, 0x8b, 0xf2, 0x4b, 0xcd, 0xb9, 0x06, 0x28, 0x02, 0x47, 0xce, 0x8a, 0xae, 0xd6, 0x42,
+	0x94, 0x0f, 0xe8, 0xec, 0x7e, 0xe4, 0xe5, 0xed, 0x23, 0x77, 0x2e, 0xa0, 0x9a, 0x07, 0xee, 0xcf,
+	0x64, 0xfb, 0x60, 0xfa, 0x51, 0x20, 0xf6, 0x45, 0xea, 0x71, 0xbe, 0x69, 0x00, 0xa2, 0x70, 0x25,
+	0xf3, 0xb9, 0x66, 0x46, 0x03, 0x8c, 0x94, 0x21, 0x97, 0xf3, 0xd9, 0x1a, 0x70, 0x81, 0x98, 0x08,
+	0x84, 0xbe, 0x13, 0xd1, 0x5c, 0x89, 0xab, 0xb1, 0xa3, 0x85, 0x47, 0x60, 0xaa, 0x45, 0x10, 0x0a,
+	0x84, 0x89, 0xb9, 0x4b, 0x47, 0x50, 0x5b, 0x78, 0x92, 0x2b, 0xfe, 0xa9, 0x41, 0x35, 0xdf, 0x86,
+	0x35, 0xb9, 0x8f, 0xc1, 0x98, 0x16, 0x72, 0x4f, 0x37, 0xb7, 0xa7, 0x7d, 0xcb, 0x90, 0x2b, 0x8f,
+	0x1a, 0x60, 0x7c, 0x51, 0xc2, 0x77, 0xec, 0x6e, 0x31, 0xf6, 0x1d, 0x9a, 0x4f, 0x5e, 0x43, 0xad,
+	0xe0, 0x5b, 0xf1, 0xbc, 0xb1, 0xec, 0xf9, 0xb6, 0x75, 0x97, 0x31, 0x78, 0x05, 0x50, 0xec, 0xea,
+	0x6a, 0xb8, 0xd7, 0x5c, 0x5a, 0x1d, 0x83, 0x4a, 0xf6, 0x35, 0x54, 0xe4, 0x06, 0x17, 0x3a, 0xb5,
+	0xed, 0x3a, 0x49, 0x73, 0xe9, 0xc5, 0x52, 0xde, 0xf5, 0x62, 0x71, 0x8e, 0x01, 0x8a, 0x45, 0x5f,
+	0x51, 0x73, 0xf9, 0xe2, 0xd3, 0x79, 0x10, 0xf1, 0x30, 0x1b, 0xb4, 0xbd, 0x64, 0xdc, 0x09, 0xe7,
+	0x29, 0xd2, 0x11, 0xfa, 0x01, 0xd2, 0xce, 0xd0, 0x1d, 0xd0, 0xc8, 0xeb, 0x24, 0xd4, 0x47, 0x8a,
+	0xb4, 0xc3, 0x56, 0x3e, 0x15, 0x03, 0x53, 0x7e, 0x2b, 0x9e, 0xff, 0x1a, 0x00, 0x1b, 0xfe, 0xe8,
+	0xea, 0x48, 0x06, 0x00, 0x00,
 }
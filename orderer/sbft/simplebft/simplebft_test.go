@@ -18,6 +18,7 @@ package simplebft
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -109,6 +110,36 @@ func TestSBFT(t *testing.T) {
 	}
 }
 
+func TestBatchDurationUpdate(t *testing.T) {
+	sys := newTestSystem(1)
+	a := sys.NewAdapter(0)
+	config := &Config{N: 1, F: 0, BatchDurationNsec: 2000000000, BatchSizeBytes: 10, RequestTimeoutNsec: 20000000000}
+	s, err := New(0, config, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var durations []time.Duration
+	sys.filterFn = func(e testElem) (testElem, bool) {
+		if tt, ok := e.ev.(*testTimer); ok && strings.Contains(tt.String(), "maybeSendNextBatch") {
+			durations = append(durations, e.at-sys.now)
+		}
+		return e, true
+	}
+	connectAll(sys)
+	s.Request([]byte{1, 2, 3})
+	sys.Run()
+	// the system changes the batch duration of the running instance
+	config.BatchDurationNsec = 1000000000
+	s.Request([]byte{3, 1, 2})
+	sys.Run()
+	if len(a.batches) != 2 {
+		t.Fatal("expected execution of 2 batches")
+	}
+	if !reflect.DeepEqual([]time.Duration{2 * time.Second, time.Second}, durations) {
+		t.Errorf("expected batch timers of 2s and then 1s, got %v", durations)
+	}
+}
+
 func TestQuorumSizes(t *testing.T) {
 	for N := uint64(1); N < 100; N++ {
 		for f := uint64(0); f <= uint64(math.Floor(float64(N-1)/float64(3))); f++ {
@@ -1445,3 +1476,306 @@ func TestTenReplicasBombedWithRequests(t *testing.T) {
 		}
 	}
 }
+
+func TestCrashAndRejoin(t *testing.T) {
+	skipInShortMode(t)
+	N := lowN
+	sys := newTestSystem(N)
+	var repls []*SBFT
+	var adapters []*testSystemAdapter
+	for i := uint64(0); i < N; i++ {
+		a := sys.NewAdapter(i)
+		s, err := New(i, &Config{N: N, F: 1, BatchDurationNsec: 2000000000, BatchSizeBytes: 1, RequestTimeoutNsec: 20000000000}, a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		repls = append(repls, s)
+		adapters = append(adapters, a)
+	}
+
+	crashed := false
+
+	// replica 3 is down: it neither sends nor receives messages
+	sys.filterFn = func(e testElem) (testElem, bool) {
+		if !crashed {
+			return e, true
+		}
+		switch ev := e.ev.(type) {
+		case *testMsgEvent:
+			if ev.src == 3 || ev.dst == 3 {
+				return e, false
+			}
+		case *testTimer:
+			if ev.id == 3 {
+				return e, false
+			}
+		}
+		return e, true
+	}
+
+	connectAll(sys)
+	r1 := []byte{1, 2, 3}
+	repls[0].Request(r1)
+	sys.Run()
+
+	testLog.Notice("crashing replica 3")
+	crashed = true
+	reqs := [][]byte{{3, 1, 2}, {3, 5, 2}, {4, 5, 6}, {7, 8, 9}}
+	for _, r := range reqs {
+		repls[1].Request(r)
+		sys.Run()
+	}
+
+	if len(adapters[3].batches) != 1 {
+		t.Fatalf("expected crashed replica to execute 1 batch, got %d", len(adapters[3].batches))
+	}
+
+	testLog.Notice("restarting replica 3")
+	crashed = false
+	repls[3], _ = New(3, &Config{N: N, F: 1, BatchDurationNsec: 2000000000, BatchSizeBytes: 1, RequestTimeoutNsec: 20000000000}, adapters[3])
+	for _, a := range adapters {
+		if a.id != 3 {
+			a.receiver.Connection(3)
+			adapters[3].receiver.Connection(a.id)
+		}
+	}
+	sys.Run()
+
+	r6 := []byte{6, 6, 6}
+	repls[3].Request(r6)
+	sys.Run()
+
+	expected := append([][]byte{r1}, reqs...)
+	expected = append(expected, r6)
+	for _, a := range adapters {
+		if len(a.batches) != len(expected) {
+			t.Fatalf("%d: expected execution of %d batches, got %d", a.id, len(expected), len(a.batches))
+		}
+		for i, b := range a.batches {
+			if !reflect.DeepEqual([][]byte{expected[i]}, b.Payloads) {
+				t.Errorf("%d: wrong request executed (%d): %v", a.id, i+1, b.Payloads)
+			}
+			if !reflect.DeepEqual(b.Header, adapters[0].batches[i].Header) {
+				t.Errorf("%d: batch %d differs from the one of replica 0", a.id, i+1)
+			}
+		}
+	}
+}
+
+func TestLaggingReplicaCatchesUpOnCheckpoints(t *testing.T) {
+	skipInShortMode(t)
+	N := lowN
+	sys := newTestSystemWOTimers(N)
+	var repls []*SBFT
+	var adapters []*testSystemAdapter
+	for i := uint64(0); i < N; i++ {
+		a := sys.NewAdapter(i)
+		s, err := New(i, &Config{N: N, F: 1, BatchDurationNsec: 2000000000, BatchSizeBytes: 1, RequestTimeoutNsec: 20000000000}, a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		repls = append(repls, s)
+		adapters = append(adapters, a)
+	}
+
+	phase := 1
+
+	// replica 3 misses all messages in phase 1, but stays connected
+	sys.filterFn = func(e testElem) (testElem, bool) {
+		if msg, ok := e.ev.(*testMsgEvent); ok {
+			if phase == 1 && msg.dst == 3 && msg.src != 3 {
+				return e, false
+			}
+		}
+		return e, true
+	}
+
+	connectAll(sys)
+	reqs := [][]byte{{1, 2, 3}, {3, 1, 2}, {3, 5, 2}}
+	for _, r := range reqs {
+		repls[0].Request(r)
+		sys.Run()
+	}
+
+	if len(adapters[3].batches) != 0 {
+		t.Fatalf("expected lagging replica not to execute batches, got %d", len(adapters[3].batches))
+	}
+
+	phase = 2
+	r4 := []byte{4, 4, 4}
+	repls[0].Request(r4)
+	sys.Run()
+
+	expected := append(reqs, r4)
+	for _, a := range adapters {
+		if len(a.batches) != len(expected) {
+			t.Fatalf("%d: expected execution of %d batches, got %d", a.id, len(expected), len(a.batches))
+		}
+		for i, b := range a.batches {
+			if !reflect.DeepEqual([][]byte{expected[i]}, b.Payloads) {
+				t.Errorf("%d: wrong request executed (%d): %v", a.id, i+1, b.Payloads)
+			}
+		}
+	}
+}
+
+func TestStateTransferRejectsForgedBatch(t *testing.T) {
+	skipInShortMode(t)
+	N := lowN
+	sys := newTestSystem(N)
+	var repls []*SBFT
+	var adapters []*testSystemAdapter
+	for i := uint64(0); i < N; i++ {
+		a := sys.NewAdapter(i)
+		s, err := New(i, &Config{N: N, F: 1, BatchDurationNsec: 2000000000, BatchSizeBytes: 1, RequestTimeoutNsec: 20000000000}, a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		repls = append(repls, s)
+		adapters = append(adapters, a)
+	}
+
+	phase := 1
+
+	// replica 3 misses all messages in phase 1, and replica 0
+	// afterwards serves batches with tampered payloads
+	sys.filterFn = func(e testElem) (testElem, bool) {
+		if msg, ok := e.ev.(*testMsgEvent); ok {
+			if phase == 1 && msg.dst == 3 && msg.src != 3 {
+				return e, false
+			}
+			if b := msg.msg.GetBatch(); b != nil && msg.src == 0 {
+				forged := *b
+				forged.Payloads = [][]byte{{6, 6, 6}}
+				msg.msg = &Msg{&Msg_Batch{&forged}}
+			}
+		}
+		return e, true
+	}
+
+	connectAll(sys)
+	reqs := [][]byte{{1, 2, 3}, {3, 1, 2}}
+	for _, r := range reqs {
+		repls[1].Request(r)
+		sys.Run()
+	}
+
+	phase = 2
+	testLog.Notice("reconnecting replica 3")
+	for _, a := range adapters {
+		if a.id != 3 {
+			a.receiver.Connection(3)
+		}
+	}
+	sys.Run()
+
+	if len(adapters[3].batches) != len(reqs) {
+		t.Fatalf("expected execution of %d batches, got %d", len(reqs), len(adapters[3].batches))
+	}
+	for i, b := range adapters[3].batches {
+		if !reflect.DeepEqual([][]byte{reqs[i]}, b.Payloads) {
+			t.Errorf("wrong request executed (%d): %v", i+1, b.Payloads)
+		}
+	}
+}
+
+func TestStateTransferChecksCheckpointDigest(t *testing.T) {
+	skipInShortMode(t)
+	N := lowN
+	sys := newTestSystem(N)
+	var repls []*SBFT
+	var adapters []*testSystemAdapter
+	for i := uint64(0); i < N; i++ {
+		a := sys.NewAdapter(i)
+		s, err := New(i, &Config{N: N, F: 1, BatchDurationNsec: 2000000000, BatchSizeBytes: 1, RequestTimeoutNsec: 20000000000}, a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		repls = append(repls, s)
+		adapters = append(adapters, a)
+	}
+
+	phase := 1
+
+	// replica 3 misses all messages in phase 1, and replica 0
+	// afterwards serves well formed batches, signed by f+1 replicas,
+	// which are not the ones the checkpoints name
+	sys.filterFn = func(e testElem) (testElem, bool) {
+		if msg, ok := e.ev.(*testMsgEvent); ok {
+			if phase == 1 && msg.dst == 3 && msg.src != 3 {
+				return e, false
+			}
+			if b := msg.msg.GetBatch(); b != nil && msg.src == 0 {
+				bh := b.DecodeHeader()
+				forged := repls[0].makeBatch(bh.Seq, bh.PrevHash, [][]byte{{6, 6, 6}})
+				forged.Signatures = map[uint64][]byte{
+					0: adapters[0].Sign(forged.Hash()),
+					1: adapters[1].Sign(forged.Hash()),
+				}
+				msg.msg = &Msg{&Msg_Batch{forged}}
+			}
+		}
+		return e, true
+	}
+
+	connectAll(sys)
+	reqs := [][]byte{{1, 2, 3}, {3, 1, 2}}
+	for _, r := range reqs {
+		repls[1].Request(r)
+		sys.Run()
+	}
+
+	phase = 2
+	testLog.Notice("reconnecting replica 3")
+	for _, a := range adapters {
+		if a.id != 3 {
+			a.receiver.Connection(3)
+		}
+	}
+	sys.Run()
+
+	if len(adapters[3].batches) != len(reqs) {
+		t.Fatalf("expected execution of %d batches, got %d", len(reqs), len(adapters[3].batches))
+	}
+	for i, b := range adapters[3].batches {
+		if !reflect.DeepEqual([][]byte{reqs[i]}, b.Payloads) {
+			t.Errorf("wrong request executed (%d): %v", i+1, b.Payloads)
+		}
+	}
+}
+
+func TestStoredBatchesPruned(t *testing.T) {
+	skipInShortMode(t)
+	N := lowN
+	sys := newTestSystemWOTimers(N)
+	var repls []*SBFT
+	var adapters []*testSystemAdapter
+	for i := uint64(0); i < N; i++ {
+		a := sys.NewAdapter(i)
+		s, err := New(i, &Config{N: N, F: 1, BatchDurationNsec: 2000000000, BatchSizeBytes: 1, RequestTimeoutNsec: 20000000000}, a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		repls = append(repls, s)
+		adapters = append(adapters, a)
+	}
+
+	connectAll(sys)
+	last := uint64(maxStoredBatches + 2)
+	for i := uint64(1); i <= last; i++ {
+		repls[0].Request([]byte{byte(i >> 8), byte(i), 1})
+		sys.Run()
+	}
+
+	for _, a := range adapters {
+		if len(a.batches) != int(last) {
+			t.Fatalf("%d: expected execution of %d batches, got %d", a.id, last, len(a.batches))
+		}
+		for seq := uint64(1); seq <= last; seq++ {
+			_, stored := a.persistence[batchKey(seq)]
+			if expected := seq > last-maxStoredBatches; stored != expected {
+				t.Errorf("%d: expected batch %d stored to be %v", a.id, seq, expected)
+			}
+		}
+	}
+}
@@ -0,0 +1,286 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simplebft
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// xferInfo tracks a running state transfer.  The batches are fetched
+// backwards from the target, whose hash is the digest of a stable
+// checkpoint, each batch being the one whose hash the following batch
+// names as its predecessor.
+type xferInfo struct {
+	target     uint64
+	digest     []byte
+	batches    []*Batch // fetched batches, in descending order
+	nextTarget uint64   // stable checkpoint noted during the transfer
+	nextDigest []byte
+	attempt    uint64
+	timeout    Canceller
+	fetching   uint64
+}
+
+// maxStoredBatches is the number of most recent batches kept for
+// serving state transfers.  A replica lagging further behind cannot
+// catch up through state transfer.
+const maxStoredBatches = 100
+
+func batchKey(seq uint64) string {
+	return fmt.Sprintf("%s%d", batchPrefix, seq)
+}
+
+// storeBatch persists a delivered batch for state transfer and
+// deletes the batch which dropped out of the stored window.  Batches
+// are delivered in order, so older ones were deleted already.
+func (s *SBFT) storeBatch(batch *Batch) {
+	seq := batch.DecodeHeader().Seq
+	s.sys.Persist(batchKey(seq), batch)
+	if seq > maxStoredBatches {
+		s.sys.Persist(batchKey(seq-maxStoredBatches), nil)
+	}
+}
+
+// noteCheckpoint records the recent checkpoints of a replica.  Once
+// f+1 replicas report the same checkpoint beyond the batch we are
+// working on, at least one correct replica committed batches which we
+// will not receive through the common case any more, and we fetch them.
+func (s *SBFT) noteCheckpoint(c *Checkpoint, src uint64) {
+	if c.Seq <= s.cur.subject.Seq.Seq || src == s.id {
+		return
+	}
+	state := &s.replicaState[src]
+	if n := len(state.checkpoints); n > 0 && state.checkpoints[n-1].Seq >= c.Seq {
+		return
+	}
+	if err := s.checkBytesSig(c.Digest, src, c.Signature); err != nil {
+		log.Infof("replica %d: checkpoint signature invalid for %d from %d", s.id, c.Seq, src)
+		return
+	}
+	state.checkpoints = append(state.checkpoints, c)
+	if len(state.checkpoints) > maxStoredBatches {
+		state.checkpoints = state.checkpoints[1:]
+	}
+
+	stable := s.stableCheckpoint()
+	if stable == nil {
+		return
+	}
+	log.Infof("replica %d: %d replicas checkpointed %d, we are at %d", s.id, s.oneCorrectQuorum(), stable.Seq, s.seq())
+	s.startStateTransfer(stable.Seq, stable.Digest)
+}
+
+// stableCheckpoint returns the highest checkpoint beyond the batch we
+// are working on whose digest f+1 replicas agree on, or nil.
+func (s *SBFT) stableCheckpoint() *Checkpoint {
+	var stable *Checkpoint
+	votes := make(map[string]int)
+	for _, state := range s.replicaState {
+		for _, c := range state.checkpoints {
+			if c.Seq <= s.cur.subject.Seq.Seq || (stable != nil && c.Seq <= stable.Seq) {
+				continue
+			}
+			key := fmt.Sprintf("%d/%x", c.Seq, c.Digest)
+			votes[key]++
+			if votes[key] >= s.oneCorrectQuorum() {
+				stable = c
+			}
+		}
+	}
+	return stable
+}
+
+// startStateTransfer fetches the committed batches following our
+// last batch up to and including target, whose hash is digest.
+func (s *SBFT) startStateTransfer(target uint64, digest []byte) {
+	if target <= s.seq() {
+		return
+	}
+	if s.xfer != nil {
+		if s.xfer.target < target && s.xfer.nextTarget < target {
+			s.xfer.nextTarget = target
+			s.xfer.nextDigest = digest
+		}
+		return
+	}
+
+	log.Noticef("replica %d: starting state transfer from %d to %d", s.id, s.seq(), target)
+	s.xfer = &xferInfo{target: target, digest: digest, timeout: dummyCanceller{}}
+	s.fetchNextBatch()
+}
+
+// expectedDigest returns the hash of the batch we fetch next: the
+// digest of the checkpoint first, and then the hash that the earliest
+// fetched batch names as its predecessor.
+func (x *xferInfo) expectedDigest() []byte {
+	if n := len(x.batches); n > 0 {
+		return x.batches[n-1].DecodeHeader().PrevHash
+	}
+	return x.digest
+}
+
+// fetchNextBatch requests the batch preceding the earliest fetched
+// batch from f+1 replicas, preferring those known to have committed
+// it.  If none of them answers in time, the next f+1 replicas are
+// asked.
+func (s *SBFT) fetchNextBatch() {
+	s.xfer.timeout.Cancel()
+
+	seq := s.xfer.target
+	if n := len(s.xfer.batches); n > 0 {
+		seq = s.xfer.batches[n-1].DecodeHeader().Seq - 1
+	}
+	if seq <= s.seq() {
+		s.completeStateTransfer()
+		return
+	}
+
+	if s.xfer.fetching != seq {
+		s.xfer.fetching = seq
+		s.xfer.attempt = 0
+	}
+
+	var ahead, others []uint64
+	for r := uint64(0); r < s.config.N; r++ {
+		if r == s.id {
+			continue
+		}
+		if s.knownSeq(r) >= seq {
+			ahead = append(ahead, r)
+		} else {
+			others = append(others, r)
+		}
+	}
+	candidates := append(ahead, others...)
+
+	log.Debugf("replica %d: fetching batch %d (attempt %d)", s.id, seq, s.xfer.attempt)
+	for i := 0; i < s.oneCorrectQuorum() && i < len(candidates); i++ {
+		r := candidates[(s.xfer.attempt*uint64(s.oneCorrectQuorum())+uint64(i))%uint64(len(candidates))]
+		s.sys.Send(&Msg{&Msg_FetchBatch{&FetchBatch{Seq: seq}}}, r)
+	}
+	s.xfer.attempt++
+	s.xfer.timeout = s.sys.Timer(time.Duration(s.config.RequestTimeoutNsec)*time.Nanosecond, s.fetchNextBatch)
+}
+
+// completeStateTransfer delivers the fetched batches which follow our
+// last batch, once they reach back to it.
+func (s *SBFT) completeStateTransfer() {
+	xfer := s.xfer
+	s.xfer = nil
+
+	for i := len(xfer.batches) - 1; i >= 0; i-- {
+		b := xfer.batches[i]
+		bh := b.DecodeHeader()
+		if bh.Seq <= s.seq() {
+			// delivered through the common case meanwhile
+			continue
+		}
+		prevhash := s.sys.LastBatch().Hash()
+		if !bytes.Equal(bh.PrevHash, prevhash) {
+			log.Criticalf("replica %d: batch %d of checkpoint %d %s does not extend our last batch %s, got %s",
+				s.id, bh.Seq, xfer.target, hash2str(xfer.digest), hash2str(prevhash), hash2str(bh.PrevHash))
+			return
+		}
+
+		log.Infof("replica %d: delivering batch %d fetched for checkpoint %d", s.id, bh.Seq, xfer.target)
+		s.deliverBatch(b)
+
+		// the fetched batch supersedes whatever we were working on
+		if s.cur.subject.Seq.Seq <= bh.Seq {
+			seq := &SeqView{Seq: bh.Seq, View: s.view}
+			s.cur = reqInfo{
+				subject:        Subject{Seq: seq, Digest: b.Hash()},
+				timeout:        dummyCanceller{},
+				preprep:        &Preprepare{Seq: seq, Batch: b},
+				prep:           make(map[uint64]*Subject),
+				commit:         make(map[uint64]*Subject),
+				checkpoint:     make(map[uint64]*Checkpoint),
+				prepared:       true,
+				committed:      true,
+				checkpointDone: true,
+			}
+		}
+	}
+	log.Noticef("replica %d: state transfer to %d completed", s.id, xfer.target)
+
+	if xfer.nextTarget > s.seq() {
+		s.startStateTransfer(xfer.nextTarget, xfer.nextDigest)
+		return
+	}
+	s.maybeSendNextBatch()
+	s.processBacklog()
+}
+
+// knownSeq returns the highest seq that replica r is known to have
+// committed.
+func (s *SBFT) knownSeq(r uint64) uint64 {
+	seq := uint64(0)
+	state := &s.replicaState[r]
+	if n := len(state.checkpoints); n > 0 {
+		seq = state.checkpoints[n-1].Seq
+	}
+	if state.hello != nil {
+		if hseq := state.hello.Batch.DecodeHeader().Seq; hseq > seq {
+			seq = hseq
+		}
+	}
+	return seq
+}
+
+func (s *SBFT) handleFetchBatch(fb *FetchBatch, src uint64) {
+	var batch *Batch
+	if fb.Seq == s.seq() {
+		batch = s.sys.LastBatch()
+	} else {
+		batch = &Batch{}
+		if !s.sys.Restore(batchKey(fb.Seq), batch) {
+			log.Debugf("replica %d: cannot serve batch %d to %d", s.id, fb.Seq, src)
+			return
+		}
+	}
+	s.sys.Send(&Msg{&Msg_Batch{batch}}, src)
+}
+
+// handleBatch accepts a fetched batch whose hash is the one we expect:
+// the digest of the stable checkpoint for the target, and the hash of
+// the predecessor named by the following batch for the others.
+func (s *SBFT) handleBatch(b *Batch, src uint64) {
+	if s.xfer == nil {
+		return
+	}
+
+	bh, err := s.checkBatch(b, true, true)
+	if err != nil {
+		log.Warningf("replica %d: invalid batch from %d: %s", s.id, src, err)
+		return
+	}
+	if bh.Seq != s.xfer.fetching {
+		// duplicate answer, or a batch we did not ask for
+		return
+	}
+	expected := s.xfer.expectedDigest()
+	if !bytes.Equal(b.Hash(), expected) {
+		log.Warningf("replica %d: batch %d from %d does not match the checkpointed hash %s, got %s", s.id, bh.Seq, src, hash2str(expected), hash2str(b.Hash()))
+		return
+	}
+
+	log.Debugf("replica %d: fetched batch %d from %d", s.id, bh.Seq, src)
+	s.xfer.batches = append(s.xfer.batches, b)
+	s.fetchNextBatch()
+}
@@ -29,10 +29,9 @@ var logger = logging.MustGetLogger("orderer/solo")
 type consenter struct{}
 
 type chain struct {
-	support      multichain.ConsenterSupport
-	batchTimeout time.Duration
-	sendChan     chan *cb.Envelope
-	exitChan     chan struct{}
+	support  multichain.ConsenterSupport
+	sendChan chan *cb.Envelope
+	exitChan chan struct{}
 }
 
 // New creates a new consenter for the solo consensus scheme.
@@ -43,16 +42,15 @@ func New() multichain.Consenter {
 	return &consenter{}
 }
 
-func (solo *consenter) HandleChain(support multichain.ConsenterSupport) (multichain.Chain, error) {
+func (solo *consenter) HandleChain(support multichain.ConsenterSupport, metadata *cb.Metadata) (multichain.Chain, error) {
 	return newChain(support), nil
 }
 
 func newChain(support multichain.ConsenterSupport) *chain {
 	return &chain{
-		batchTimeout: support.SharedConfig().BatchTimeout(),
-		support:      support,
-		sendChan:     make(chan *cb.Envelope),
-		exitChan:     make(chan struct{}),
+		support:  support,
+		sendChan: make(chan *cb.Envelope),
+		exitChan: make(chan struct{}),
 	}
 }
 
@@ -87,12 +85,12 @@ func (ch *chain) main() {
 		case msg := <-ch.sendChan:
 			batches, committers, ok := ch.support.BlockCutter().Ordered(msg)
 			if ok && len(batches) == 0 && timer == nil {
-				timer = time.After(ch.batchTimeout)
+				timer = multichain.NewBatchTimer(ch.support)
 				continue
 			}
 			for i, batch := range batches {
 				block := ch.support.CreateNextBlock(batch)
-				ch.support.WriteBlock(block, committers[i])
+				ch.support.WriteBlock(block, committers[i], nil)
 			}
 			if len(batches) > 0 {
 				timer = nil
@@ -108,7 +106,7 @@ func (ch *chain) main() {
 			}
 			logger.Debugf("Batch timer expired, creating block")
 			block := ch.support.CreateNextBlock(batch)
-			ch.support.WriteBlock(block, committers)
+			ch.support.WriteBlock(block, committers, nil)
 		case <-ch.exitChan:
 			logger.Debugf("Exiting")
 			return
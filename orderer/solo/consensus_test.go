@@ -134,7 +134,7 @@ func TestBatchTimerHaltOnFilledBatch(t *testing.T) {
 	}
 
 	// Change the batch timeout to be near instant, if the timer was not reset, it will still be waiting an hour
-	bs.batchTimeout = time.Millisecond
+	support.SharedConfigVal.BatchTimeoutVal = time.Millisecond
 
 	support.BlockCutterVal.CutNext = false
 	syncQueueMessage(testMessage, bs, support.BlockCutterVal)
@@ -153,6 +153,37 @@ func TestBatchTimerHaltOnFilledBatch(t *testing.T) {
 	}
 }
 
+func TestBatchTimerUpdated(t *testing.T) {
+	batchTimeout, _ := time.ParseDuration("1h")
+	support := &mockmultichain.ConsenterSupport{
+		Batches:         make(chan []*cb.Envelope),
+		BlockCutterVal:  mockblockcutter.NewReceiver(),
+		SharedConfigVal: &mocksharedconfig.Manager{BatchTimeoutVal: batchTimeout},
+	}
+	defer close(support.BlockCutterVal.Block)
+
+	bs := newChain(support)
+	wg := goWithWait(bs.main)
+	defer bs.Halt()
+
+	// A configuration update of the running chain makes the batch timeout near instant
+	support.SharedConfigVal.BatchTimeoutVal = time.Millisecond
+	syncQueueMessage(testMessage, bs, support.BlockCutterVal)
+
+	select {
+	case <-support.Batches:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected a block to be cut with the updated batch timeout, but did not")
+	}
+
+	bs.Halt()
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("Should have exited")
+	case <-wg.done:
+	}
+}
+
 func TestConfigStyleMultiBatch(t *testing.T) {
 	batchTimeout, _ := time.ParseDuration("1h")
 	support := &mockmultichain.ConsenterSupport{
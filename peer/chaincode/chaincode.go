@@ -50,6 +50,12 @@ func AddFlags(cmd *cobra.Command) {
 		fmt.Sprint("Name of a custom ID generation algorithm (hashing and decoding) e.g. sha256base64"))
 	flags.StringVarP(&chainID, "chainID", "C", util.GetTestChainID(),
 		fmt.Sprint("The chain on which this command should be executed"))
+	flags.StringVarP(&policy, "policy", "P", common.UndefinedParamValue,
+		fmt.Sprint("The endorsement policy associated to this chaincode, e.g. OutOf(2, 'Org1.member', 'Org2.member', 'Org3.member')"))
+	flags.StringVarP(&escc, "escc", "E", common.UndefinedParamValue,
+		fmt.Sprint("The name of the endorsement system chaincode to be used for this chaincode"))
+	flags.StringVarP(&vscc, "vscc", "V", common.UndefinedParamValue,
+		fmt.Sprint("The name of the verification system chaincode to be used for this chaincode"))
 }
 
 // Cmd returns the cobra command for Chaincode
@@ -76,6 +82,9 @@ var (
 	chaincodeAttributesJSON string
 	customIDGenAlg          string
 	chainID                 string
+	policy                  string
+	escc                    string
+	vscc                    string
 )
 
 var chaincodeCmd = &cobra.Command{
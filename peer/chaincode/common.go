@@ -23,6 +23,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/hyperledger/fabric/common/cauthdsl"
 	cutil "github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/chaincode"
 	"github.com/hyperledger/fabric/core/chaincode/platforms"
@@ -72,6 +73,19 @@ func getChaincodeBytes(spec *pb.ChaincodeSpec) (*pb.ChaincodeDeploymentSpec, err
 	return chaincodeDeploymentSpec, nil
 }
 
+// getEndorsementPolicy compiles the endorsement policy given on the command line, if any,
+// and returns it serialized as expected by LCCC
+func getEndorsementPolicy() ([]byte, error) {
+	if policy == common.UndefinedParamValue {
+		return nil, nil
+	}
+	p, err := cauthdsl.FromString(policy)
+	if err != nil {
+		return nil, fmt.Errorf("Endorsement policy error: %s", err)
+	}
+	return putils.Marshal(p)
+}
+
 func getChaincodeSpecification(cmd *cobra.Command) (*pb.ChaincodeSpec, error) {
 	spec := &pb.ChaincodeSpec{}
 	if err := checkChaincodeCmdParams(cmd); err != nil {
@@ -267,6 +281,8 @@ func ChaincodeInvokeOrQuery(spec *pb.ChaincodeSpec, cID string, invoke bool, sig
 				return proposalResp, fmt.Errorf("Error sending transaction %s: %s", funcName, err)
 			}
 		}
+	} else if proposalResp != nil && proposalResp.Response.Status != 0 && proposalResp.Response.Status != 200 {
+		return proposalResp, fmt.Errorf("Error querying %s: status %d, %s", funcName, proposalResp.Response.Status, proposalResp.Response.Message)
 	}
 
 	return proposalResp, nil
@@ -57,6 +57,11 @@ func deploy(cmd *cobra.Command, cf *ChaincodeCmdFactory) (*protcommon.Envelope,
 		return nil, fmt.Errorf("Error getting chaincode code %s: %s", chainFuncName, err)
 	}
 
+	policyBytes, err := getEndorsementPolicy()
+	if err != nil {
+		return nil, err
+	}
+
 	creator, err := cf.Signer.Serialize()
 	if err != nil {
 		return nil, fmt.Errorf("Error serializing identity for %s: %s\n", cf.Signer.GetIdentifier(), err)
@@ -64,7 +69,7 @@ func deploy(cmd *cobra.Command, cf *ChaincodeCmdFactory) (*protcommon.Envelope,
 
 	uuid := util.GenerateUUID()
 
-	prop, err := utils.CreateDeployProposalFromCDS(uuid, chainID, cds, creator)
+	prop, err := utils.CreateDeployProposalFromCDS(uuid, chainID, cds, creator, policyBytes, escc, vscc)
 	if err != nil {
 		return nil, fmt.Errorf("Error creating proposal  %s: %s\n", chainFuncName, err)
 	}
@@ -19,6 +19,7 @@ package channel
 import (
 	"fmt"
 
+	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/peer/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
@@ -27,7 +28,6 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"golang.org/x/net/context"
-	"google.golang.org/grpc"
 )
 
 const channelFuncName = "channel"
@@ -102,8 +102,7 @@ func InitCmdFactory(forJoin bool) (*ChannelCmdFactory, error) {
 			return nil, fmt.Errorf("Error getting endorser client %s: %s", channelFuncName, err)
 		}
 	} else {
-		orderer := viper.GetString("peer.committer.ledger.orderer")
-		conn, err := grpc.Dial(orderer, grpc.WithInsecure())
+		conn, err := comm.NewOrdererClientConnection(false)
 		if err != nil {
 			return nil, err
 		}
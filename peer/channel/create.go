@@ -50,7 +50,7 @@ func sendCreateChainTransaction(cf *ChannelCmdFactory) error {
 
 	mspcfg := configtx.NewSimpleTemplate(utils.EncodeMSPUnsigned(chainID))
 
-	chCrtTemp := configtx.NewCompositeTemplate(oTemplate, mspcfg)
+	chCrtTemp := configtx.NewCompositeTemplate(oTemplate, mspcfg, configtxtest.PoliciesTemplate{})
 
 	signer, err := mspmgmt.GetLocalMSP().GetDefaultSigningIdentity()
 	if err != nil {
@@ -18,8 +18,8 @@ package common
 
 import (
 	"fmt"
-	"time"
 
+	"github.com/hyperledger/fabric/core/comm"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/spf13/viper"
@@ -49,12 +49,7 @@ func GetBroadcastClient() (BroadcastClient, error) {
 		return nil, fmt.Errorf("Can't get orderer address")
 	}
 
-	var opts []grpc.DialOption
-	opts = append(opts, grpc.WithInsecure())
-	opts = append(opts, grpc.WithTimeout(3*time.Second))
-	opts = append(opts, grpc.WithBlock())
-
-	conn, err := grpc.Dial(orderer, opts...)
+	conn, err := comm.NewOrdererClientConnection(true)
 	if err != nil {
 		return nil, fmt.Errorf("Error connecting to %s due to %s", orderer, err)
 	}
@@ -0,0 +1,109 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ledger
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	startBlockNum uint64
+	endBlockNum   uint64
+)
+
+var marshaler = &jsonpb.Marshaler{Indent: "  "}
+
+func blocksCmd() *cobra.Command {
+	flags := ledgerBlocksCmd.Flags()
+	flags.Uint64VarP(&startBlockNum, "start", "s", 1, "Number of the first block to dump")
+	flags.Uint64VarP(&endBlockNum, "end", "e", 0, "Number of the last block to dump, the last block of the ledger if not set")
+
+	return ledgerBlocksCmd
+}
+
+var ledgerBlocksCmd = &cobra.Command{
+	Use:   "blocks <ledgerID>",
+	Short: "Dumps blocks of a ledger as JSON.",
+	Long:  `Dumps the blocks of a ledger in the given range as JSON.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return blocks(cmd, args)
+	},
+}
+
+func blocks(cmd *cobra.Command, args []string) error {
+	if err := checkLedgerCmdParams(cmd, args, 1); err != nil {
+		return err
+	}
+	ledgerID := args[0]
+	return withInspector(func(inspector *kvledger.Inspector) error {
+		end := endBlockNum
+		if end == 0 {
+			info, err := inspector.GetBlockchainInfo(ledgerID)
+			if err != nil {
+				return err
+			}
+			end = info.Height
+		}
+		for blockNum := startBlockNum; blockNum <= end; blockNum++ {
+			block, err := inspector.GetBlockByNumber(ledgerID, blockNum)
+			if err != nil {
+				return fmt.Errorf("Error while retrieving block %d: %s", blockNum, err)
+			}
+			json, err := marshaler.MarshalToString(block)
+			if err != nil {
+				return err
+			}
+			fmt.Println(json)
+		}
+		return nil
+	})
+}
+
+func txCmd() *cobra.Command {
+	return ledgerTxCmd
+}
+
+var ledgerTxCmd = &cobra.Command{
+	Use:   "tx <ledgerID> <txID>",
+	Short: "Dumps a transaction of a ledger as JSON.",
+	Long:  `Dumps the transaction with the given id as JSON, along with its validation code.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return tx(cmd, args)
+	},
+}
+
+func tx(cmd *cobra.Command, args []string) error {
+	if err := checkLedgerCmdParams(cmd, args, 2); err != nil {
+		return err
+	}
+	return withInspector(func(inspector *kvledger.Inspector) error {
+		transaction, err := inspector.GetTransactionByID(args[0], args[1])
+		if err != nil {
+			return fmt.Errorf("Error while retrieving transaction [%s]: %s", args[1], err)
+		}
+		json, err := marshaler.MarshalToString(transaction)
+		if err != nil {
+			return err
+		}
+		fmt.Println(json)
+		return nil
+	})
+}
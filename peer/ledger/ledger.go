@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ledger
+
+import (
+	"fmt"
+
+	coreledger "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/op/go-logging"
+	"github.com/spf13/cobra"
+)
+
+const ledgerFuncName = "ledger"
+
+var logger = logging.MustGetLogger("ledgerCmd")
+
+// Cmd returns the cobra command for Ledger. The ledger commands work directly on the
+// ledgers stored under the file system path of the peer, and the peer must not be running
+func Cmd() *cobra.Command {
+	ledgerCmd.AddCommand(listCmd())
+	ledgerCmd.AddCommand(blocksCmd())
+	ledgerCmd.AddCommand(txCmd())
+	ledgerCmd.AddCommand(savepointsCmd())
+	ledgerCmd.AddCommand(verifyCmd())
+	ledgerCmd.AddCommand(rebuildIndexCmd())
+	ledgerCmd.AddCommand(rebuildStateCmd())
+	ledgerCmd.AddCommand(exportSnapshotCmd())
+	ledgerCmd.AddCommand(importSnapshotCmd())
+
+	return ledgerCmd
+}
+
+var ledgerCmd = &cobra.Command{
+	Use:   ledgerFuncName,
+	Short: fmt.Sprintf("%s specific commands.", ledgerFuncName),
+	Long:  fmt.Sprintf("%s specific commands for inspecting and repairing the ledgers of a stopped peer, and for exporting and importing snapshots of them.", ledgerFuncName),
+}
+
+// checkLedgerCmdParams checks that the command is given the expected number of arguments
+func checkLedgerCmdParams(cmd *cobra.Command, args []string, numArgs int) error {
+	if len(args) != numArgs {
+		return fmt.Errorf("Expected %d argument(s) for command %s, got %d", numArgs, cmd.Name(), len(args))
+	}
+	return nil
+}
+
+// withInspector invokes the given function with an inspector over the ledgers of the peer
+func withInspector(fn func(inspector *kvledger.Inspector) error) error {
+	inspector, err := kvledger.NewInspector()
+	if err != nil {
+		return err
+	}
+	defer inspector.Close()
+	return fn(inspector)
+}
+
+// withProvider invokes the given function with the provider of the ledgers of the peer
+func withProvider(fn func(provider coreledger.PeerLedgerProvider) error) error {
+	provider, err := kvledger.NewProvider()
+	if err != nil {
+		return err
+	}
+	defer provider.Close()
+	return fn(provider)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ledger
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/core/ledger/testutil"
+	"github.com/spf13/viper"
+)
+
+// TestLedgerCmdParams tests the parameter checking of the ledger commands
+func TestLedgerCmdParams(t *testing.T) {
+	testutil.AssertError(t, checkLedgerCmdParams(verifyCmd(), nil, 1), "Expected an error for a missing ledger id")
+	testutil.AssertError(t, checkLedgerCmdParams(txCmd(), []string{"ledger"}, 2), "Expected an error for a missing tx id")
+	testutil.AssertNoError(t, checkLedgerCmdParams(listCmd(), nil, 0), "")
+}
+
+// TestLedgerCmds runs the ledger commands against a ledger with a few blocks
+func TestLedgerCmds(t *testing.T) {
+	viper.Set("peer.fileSystemPath", "/tmp/fabric/peer/ledgercmdtests")
+	defer os.RemoveAll("/tmp/fabric/peer/ledgercmdtests")
+	provider, err := kvledger.NewProvider()
+	testutil.AssertNoError(t, err, "")
+	l, err := provider.Create("testLedger")
+	testutil.AssertNoError(t, err, "")
+	bg := testutil.NewBlockGenerator(t)
+	for i := 0; i < 3; i++ {
+		simulator, _ := l.NewTxSimulator()
+		simulator.SetState("ns1", "key1", []byte("value1"))
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		testutil.AssertNoError(t, l.Commit(bg.NextBlock([][]byte{simRes}, false)), "")
+	}
+	l.Close()
+	provider.Close()
+
+	testutil.AssertNoError(t, list(listCmd(), nil), "")
+	testutil.AssertNoError(t, blocks(blocksCmd(), []string{"testLedger"}), "")
+	testutil.AssertNoError(t, savepoints(savepointsCmd(), []string{"testLedger"}), "")
+	testutil.AssertNoError(t, verify(verifyCmd(), []string{"testLedger"}), "")
+	testutil.AssertNoError(t, rebuildIndex(rebuildIndexCmd(), []string{"testLedger"}), "")
+	testutil.AssertNoError(t, rebuildState(rebuildStateCmd(), []string{"testLedger"}), "")
+	testutil.AssertError(t, verify(verifyCmd(), []string{"nonExistingLedger"}), "Expected an error for a non-existing ledger")
+	testutil.AssertError(t, tx(txCmd(), []string{"testLedger", "nonExistingTx"}), "Expected an error for a non-existing transaction")
+}
+
+// TestSnapshotCmds exports a snapshot of a ledger and creates the ledger from it on another peer
+func TestSnapshotCmds(t *testing.T) {
+	snapshotDir := "/tmp/fabric/peer/ledgercmdtests/snapshot"
+	viper.Set("peer.fileSystemPath", "/tmp/fabric/peer/ledgercmdtests/peer1")
+	defer os.RemoveAll("/tmp/fabric/peer/ledgercmdtests")
+	provider, err := kvledger.NewProvider()
+	testutil.AssertNoError(t, err, "")
+	l, err := provider.Create("testLedger")
+	testutil.AssertNoError(t, err, "")
+	simulator, _ := l.NewTxSimulator()
+	simulator.SetState("ns1", "key1", []byte("value1"))
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	testutil.AssertNoError(t, l.Commit(testutil.NewBlockGenerator(t).NextBlock([][]byte{simRes}, false)), "")
+	l.Close()
+	provider.Close()
+
+	testutil.AssertError(t, exportSnapshot(exportSnapshotCmd(), []string{"testLedger"}), "Expected an error for a missing snapshot directory")
+	testutil.AssertNoError(t, exportSnapshot(exportSnapshotCmd(), []string{"testLedger", snapshotDir}), "")
+
+	viper.Set("peer.fileSystemPath", "/tmp/fabric/peer/ledgercmdtests/peer2")
+	cmd := importSnapshotCmd()
+	testutil.AssertNoError(t, importSnapshot(cmd, []string{snapshotDir}), "")
+	testutil.AssertError(t, importSnapshot(cmd, []string{snapshotDir}), "Expected an error for an existing ledger")
+	testutil.AssertNoError(t, verify(verifyCmd(), []string{"testLedger"}), "")
+}
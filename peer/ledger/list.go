@@ -0,0 +1,57 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ledger
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/spf13/cobra"
+)
+
+func listCmd() *cobra.Command {
+	return ledgerListCmd
+}
+
+var ledgerListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the ledgers of the peer.",
+	Long:  `Lists the ledgers of the peer along with their heights.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return list(cmd, args)
+	},
+}
+
+func list(cmd *cobra.Command, args []string) error {
+	if err := checkLedgerCmdParams(cmd, args, 0); err != nil {
+		return err
+	}
+	return withInspector(func(inspector *kvledger.Inspector) error {
+		ledgerIDs, err := inspector.List()
+		if err != nil {
+			return err
+		}
+		for _, ledgerID := range ledgerIDs {
+			info, err := inspector.GetBlockchainInfo(ledgerID)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s\theight=%d\tcurrentBlockHash=%x\n", ledgerID, info.Height, info.CurrentBlockHash)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,74 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ledger
+
+import (
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/spf13/cobra"
+)
+
+func rebuildIndexCmd() *cobra.Command {
+	return ledgerRebuildIndexCmd
+}
+
+var ledgerRebuildIndexCmd = &cobra.Command{
+	Use:   "rebuild-index <ledgerID>",
+	Short: "Rebuilds the block index of a ledger.",
+	Long:  `Discards the block index of a ledger and indexes the blocks again from the block files.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return rebuildIndex(cmd, args)
+	},
+}
+
+func rebuildIndex(cmd *cobra.Command, args []string) error {
+	if err := checkLedgerCmdParams(cmd, args, 1); err != nil {
+		return err
+	}
+	return withInspector(func(inspector *kvledger.Inspector) error {
+		if err := inspector.RebuildBlockIndex(args[0]); err != nil {
+			return err
+		}
+		logger.Infof("Rebuilt the block index of ledger [%s]", args[0])
+		return nil
+	})
+}
+
+func rebuildStateCmd() *cobra.Command {
+	return ledgerRebuildStateCmd
+}
+
+var ledgerRebuildStateCmd = &cobra.Command{
+	Use:   "rebuild-state <ledgerID>",
+	Short: "Rebuilds the state database of a ledger.",
+	Long:  `Discards the state of a ledger and recommits the blocks of the ledger to the state database.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return rebuildState(cmd, args)
+	},
+}
+
+func rebuildState(cmd *cobra.Command, args []string) error {
+	if err := checkLedgerCmdParams(cmd, args, 1); err != nil {
+		return err
+	}
+	return withInspector(func(inspector *kvledger.Inspector) error {
+		if err := inspector.RebuildStateDB(args[0]); err != nil {
+			return err
+		}
+		logger.Infof("Rebuilt the state database of ledger [%s]", args[0])
+		return nil
+	})
+}
@@ -0,0 +1,57 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ledger
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/spf13/cobra"
+)
+
+func savepointsCmd() *cobra.Command {
+	return ledgerSavepointsCmd
+}
+
+var ledgerSavepointsCmd = &cobra.Command{
+	Use:   "savepoints <ledgerID>",
+	Short: "Shows the savepoints of a ledger.",
+	Long:  `Shows the height of the block storage and the savepoints of the state and history databases of a ledger.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return savepoints(cmd, args)
+	},
+}
+
+func savepoints(cmd *cobra.Command, args []string) error {
+	if err := checkLedgerCmdParams(cmd, args, 1); err != nil {
+		return err
+	}
+	return withInspector(func(inspector *kvledger.Inspector) error {
+		savepoints, err := inspector.GetSavepoints(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Block storage height: %d\n", savepoints.BlockStorageHeight)
+		fmt.Printf("State database savepoint: %d\n", savepoints.StateDB)
+		if savepoints.HistoryDBEnabled {
+			fmt.Printf("History database savepoint: %d\n", savepoints.HistoryDB)
+		} else {
+			fmt.Println("History database is not enabled")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,155 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ledger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/chaincode"
+	coreledger "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/peer/common"
+	pcommon "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+var snapshotPeers string
+
+func exportSnapshotCmd() *cobra.Command {
+	return ledgerExportSnapshotCmd
+}
+
+var ledgerExportSnapshotCmd = &cobra.Command{
+	Use:   "export-snapshot <ledgerID> <snapshotDir>",
+	Short: "Exports a snapshot of a ledger.",
+	Long:  `Writes the state of a ledger as of its last committed block, along with the info about that block, to an empty or non-existent directory.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exportSnapshot(cmd, args)
+	},
+}
+
+func exportSnapshot(cmd *cobra.Command, args []string) error {
+	if err := checkLedgerCmdParams(cmd, args, 2); err != nil {
+		return err
+	}
+	return withProvider(func(provider coreledger.PeerLedgerProvider) error {
+		l, err := provider.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer l.Close()
+		snapshotInfo, err := l.ExportSnapshot(args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Exported snapshot of ledger [%s] at height %d with state hash %x to [%s]\n",
+			snapshotInfo.LedgerID, snapshotInfo.BlockchainInfo.Height, snapshotInfo.LedgerStateHash, args[1])
+		return nil
+	})
+}
+
+func importSnapshotCmd() *cobra.Command {
+	flags := ledgerImportSnapshotCmd.Flags()
+	flags.StringVarP(&snapshotPeers, "peers", "p", "", "Comma separated addresses of the peers of the chain to check the state hash of the snapshot against")
+
+	return ledgerImportSnapshotCmd
+}
+
+var ledgerImportSnapshotCmd = &cobra.Command{
+	Use:   "import-snapshot <snapshotDir>",
+	Short: "Creates a ledger from a snapshot.",
+	Long:  `Checks the state hash of a snapshot against the state hash that the given peers report for the block of the snapshot, and creates the ledger of the snapshot from it. Once started, the peer receives the blocks which follow the snapshot.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return importSnapshot(cmd, args)
+	},
+}
+
+func importSnapshot(cmd *cobra.Command, args []string) error {
+	if err := checkLedgerCmdParams(cmd, args, 1); err != nil {
+		return err
+	}
+	snapshotDir := args[0]
+	snapshotInfo, err := kvledger.ReadSnapshotInfo(snapshotDir)
+	if err != nil {
+		return err
+	}
+	if snapshotPeers != "" {
+		for _, peerAddress := range strings.Split(snapshotPeers, ",") {
+			if err = kvledger.CheckSnapshotStateHash(snapshotDir, peerStateHash(peerAddress, snapshotInfo.LedgerID)); err != nil {
+				return fmt.Errorf("Snapshot check against peer [%s] failed: %s", peerAddress, err)
+			}
+			logger.Infof("Checked the state hash of the snapshot against peer [%s]", peerAddress)
+		}
+	}
+	return withProvider(func(provider coreledger.PeerLedgerProvider) error {
+		l, err := provider.CreateFromSnapshot(snapshotInfo.LedgerID, snapshotDir)
+		if err != nil {
+			return err
+		}
+		l.Close()
+		fmt.Printf("Created ledger [%s] at height %d from snapshot [%s]\n", snapshotInfo.LedgerID, snapshotInfo.BlockchainInfo.Height, snapshotDir)
+		return nil
+	})
+}
+
+// peerStateHash returns a function which queries the state hash of the given chain
+// as of a block from the query system chaincode of the peer at the given address
+func peerStateHash(peerAddress string, chainID string) func(blockNum uint64) ([]byte, error) {
+	return func(blockNum uint64) ([]byte, error) {
+		signer, err := common.GetDefaultSigner()
+		if err != nil {
+			return nil, err
+		}
+		creator, err := signer.Serialize()
+		if err != nil {
+			return nil, err
+		}
+		invocation := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_GOLANG,
+			ChaincodeID: &pb.ChaincodeID{Name: "qscc"},
+			CtorMsg:     &pb.ChaincodeInput{Args: [][]byte{[]byte(chaincode.GetStateHash), []byte(chainID), []byte(strconv.FormatUint(blockNum, 10))}},
+		}}
+		prop, err := putils.CreateProposalFromCIS(util.GenerateUUID(), pcommon.HeaderType_ENDORSER_TRANSACTION, "", invocation, creator)
+		if err != nil {
+			return nil, err
+		}
+		signedProp, err := putils.GetSignedProposal(prop, signer)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := peer.NewPeerClientConnectionWithAddress(peerAddress)
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+		proposalResp, err := pb.NewEndorserClient(conn).ProcessProposal(context.Background(), signedProp)
+		if err != nil {
+			return nil, err
+		}
+		if proposalResp.Response.Status != 0 && proposalResp.Response.Status != 200 {
+			return nil, fmt.Errorf("Error querying the state hash: status %d, %s", proposalResp.Response.Status, proposalResp.Response.Message)
+		}
+		return proposalResp.Response.Payload, nil
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ledger
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/spf13/cobra"
+)
+
+func verifyCmd() *cobra.Command {
+	return ledgerVerifyCmd
+}
+
+var ledgerVerifyCmd = &cobra.Command{
+	Use:   "verify <ledgerID>",
+	Short: "Verifies the block storage of a ledger.",
+	Long:  `Verifies the hash chain of the blocks of a ledger, computed with the hashing algorithm of the chain configuration, and the consistency of the block index with the block files.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return verify(cmd, args)
+	},
+}
+
+func verify(cmd *cobra.Command, args []string) error {
+	if err := checkLedgerCmdParams(cmd, args, 1); err != nil {
+		return err
+	}
+	return withInspector(func(inspector *kvledger.Inspector) error {
+		numBlocks, err := inspector.VerifyBlockStorage(args[0])
+		if err != nil {
+			return fmt.Errorf("Verification of ledger [%s] failed after %d blocks: %s", args[0], numBlocks, err)
+		}
+		fmt.Printf("Verified %d blocks of ledger [%s]\n", numBlocks, args[0])
+		return nil
+	})
+}
@@ -29,7 +29,7 @@ func NewBlock(seqNum uint64, previousHash []byte) *Block {
 	block.Header.PreviousHash = previousHash
 	block.Data = &BlockData{}
 	block.Metadata = &BlockMetadata{
-		Metadata: [][]byte{[]byte{}, []byte{}, []byte{}},
+		Metadata: [][]byte{[]byte{}, []byte{}, []byte{}, []byte{}, []byte{}},
 	}
 	return block
 }
@@ -42,15 +42,27 @@ func (b *BlockHeader) Bytes() []byte {
 	return data
 }
 
+// Hash returns the hash of the header computed with the default hashing algorithm
 func (b *BlockHeader) Hash() []byte {
-	return util.ComputeCryptoHash(b.Bytes())
+	return b.HashWith(util.ComputeCryptoHash)
 }
 
+// HashWith returns the hash of the header computed with the given hashing algorithm
+func (b *BlockHeader) HashWith(hashingAlgorithm func(input []byte) []byte) []byte {
+	return hashingAlgorithm(b.Bytes())
+}
+
+// Hash returns the hash of the data computed with the default hashing algorithm
 func (b *BlockData) Hash() []byte {
+	return b.HashWith(util.ComputeCryptoHash)
+}
+
+// HashWith returns the hash of the data computed with the given hashing algorithm
+func (b *BlockData) HashWith(hashingAlgorithm func(input []byte) []byte) []byte {
 	data, err := proto.Marshal(b) // XXX this is wrong, protobuf is not the right mechanism to serialize for a hash, AND, it is not a MerkleTree hash
 	if err != nil {
 		panic("This should never fail and is generally irrecoverable")
 	}
 
-	return util.ComputeCryptoHash(data)
+	return hashingAlgorithm(data)
 }
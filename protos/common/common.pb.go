@@ -63,6 +63,7 @@ const (
 	Status_BAD_REQUEST              Status = 400
 	Status_FORBIDDEN                Status = 403
 	Status_NOT_FOUND                Status = 404
+	Status_GONE                     Status = 410
 	Status_REQUEST_ENTITY_TOO_LARGE Status = 413
 	Status_INTERNAL_SERVER_ERROR    Status = 500
 	Status_SERVICE_UNAVAILABLE      Status = 503
@@ -74,6 +75,7 @@ var Status_name = map[int32]string{
 	400: "BAD_REQUEST",
 	403: "FORBIDDEN",
 	404: "NOT_FOUND",
+	410: "GONE",
 	413: "REQUEST_ENTITY_TOO_LARGE",
 	500: "INTERNAL_SERVER_ERROR",
 	503: "SERVICE_UNAVAILABLE",
@@ -84,6 +86,7 @@ var Status_value = map[string]int32{
 	"BAD_REQUEST":              400,
 	"FORBIDDEN":                403,
 	"NOT_FOUND":                404,
+	"GONE":                     410,
 	"REQUEST_ENTITY_TOO_LARGE": 413,
 	"INTERNAL_SERVER_ERROR":    500,
 	"SERVICE_UNAVAILABLE":      503,
@@ -131,20 +134,26 @@ func (HeaderType) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []i
 type BlockMetadataIndex int32
 
 const (
-	BlockMetadataIndex_SIGNATURES          BlockMetadataIndex = 0
-	BlockMetadataIndex_LAST_CONFIGURATION  BlockMetadataIndex = 1
-	BlockMetadataIndex_TRANSACTIONS_FILTER BlockMetadataIndex = 2
+	BlockMetadataIndex_SIGNATURES                    BlockMetadataIndex = 0
+	BlockMetadataIndex_LAST_CONFIGURATION            BlockMetadataIndex = 1
+	BlockMetadataIndex_TRANSACTIONS_FILTER           BlockMetadataIndex = 2
+	BlockMetadataIndex_ORDERER                       BlockMetadataIndex = 3
+	BlockMetadataIndex_TRANSACTIONS_VALIDATION_CODES BlockMetadataIndex = 4
 )
 
 var BlockMetadataIndex_name = map[int32]string{
 	0: "SIGNATURES",
 	1: "LAST_CONFIGURATION",
 	2: "TRANSACTIONS_FILTER",
+	3: "ORDERER",
+	4: "TRANSACTIONS_VALIDATION_CODES",
 }
 var BlockMetadataIndex_value = map[string]int32{
-	"SIGNATURES":          0,
-	"LAST_CONFIGURATION":  1,
-	"TRANSACTIONS_FILTER": 2,
+	"SIGNATURES":                    0,
+	"LAST_CONFIGURATION":            1,
+	"TRANSACTIONS_FILTER":           2,
+	"ORDERER":                       3,
+	"TRANSACTIONS_VALIDATION_CODES": 4,
 }
 
 func (x BlockMetadataIndex) String() string {
@@ -386,60 +395,55 @@ func init() {
 func init() { proto.RegisterFile("common/common.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 866 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x84, 0x55, 0xdd, 0x6e, 0xe3, 0x44,
-	0x14, 0xae, 0xe3, 0xfc, 0x34, 0x27, 0xa5, 0xeb, 0x4e, 0xb7, 0xbb, 0xde, 0x8a, 0xd5, 0x56, 0x96,
-	0x40, 0xa5, 0x15, 0x89, 0x28, 0x42, 0x82, 0x4b, 0x27, 0x9e, 0x74, 0xad, 0x4d, 0xed, 0x65, 0xc6,
-	0x59, 0x04, 0xbd, 0xb0, 0x9c, 0x64, 0x9a, 0x58, 0x24, 0x76, 0x64, 0x3b, 0x55, 0x7b, 0xcb, 0x03,
-	0x20, 0x24, 0xb8, 0x42, 0xe2, 0x05, 0x78, 0x12, 0xde, 0x80, 0x17, 0x41, 0xe2, 0x16, 0xcd, 0xd8,
-	0xe3, 0xc6, 0xed, 0x05, 0x57, 0x9d, 0xef, 0x7c, 0xdf, 0x9c, 0xf3, 0x9d, 0x73, 0x26, 0x35, 0x1c,
-	0x4e, 0xe3, 0xd5, 0x2a, 0x8e, 0x7a, 0xf9, 0x9f, 0xee, 0x3a, 0x89, 0xb3, 0x18, 0x35, 0x73, 0x74,
-	0xfc, 0x66, 0x1e, 0xc7, 0xf3, 0x25, 0xeb, 0x89, 0xe8, 0x64, 0x73, 0xd3, 0xcb, 0xc2, 0x15, 0x4b,
-	0xb3, 0x60, 0xb5, 0xce, 0x85, 0xc6, 0x67, 0x70, 0x30, 0x0a, 0xd2, 0x6c, 0x10, 0x47, 0x37, 0xe1,
-	0x7c, 0x93, 0x04, 0x59, 0x18, 0x47, 0xe8, 0x39, 0x34, 0xc2, 0x68, 0xc6, 0xee, 0x74, 0xe5, 0x44,
-	0x39, 0xad, 0x93, 0x1c, 0x18, 0xd7, 0xb0, 0x7b, 0xc5, 0xb2, 0x60, 0x16, 0x64, 0x01, 0x57, 0xdc,
-	0x06, 0xcb, 0x0d, 0x13, 0x8a, 0x3d, 0x92, 0x03, 0xf4, 0x0d, 0x40, 0x1a, 0xce, 0xa3, 0x20, 0xdb,
-	0x24, 0x2c, 0xd5, 0x6b, 0x27, 0xea, 0x69, 0xe7, 0xe2, 0x55, 0xb7, 0x30, 0x26, 0xef, 0x52, 0xa9,
-	0x20, 0x5b, 0x62, 0xe3, 0x1a, 0x0e, 0x9e, 0x08, 0xd0, 0x29, 0x3c, 0x2b, 0x25, 0x6f, 0x59, 0x30,
-	0x63, 0x49, 0x51, 0xef, 0x71, 0x18, 0x7d, 0x0c, 0xed, 0x32, 0xa4, 0xd7, 0x84, 0xe6, 0x21, 0x60,
-	0xfc, 0xa4, 0x40, 0xb3, 0x10, 0x7e, 0x05, 0x9d, 0xe9, 0x22, 0x08, 0xa3, 0xad, 0x74, 0x9d, 0x8b,
-	0x43, 0xe9, 0x71, 0xf0, 0x40, 0x91, 0x6d, 0x1d, 0x32, 0x9f, 0x3a, 0xa9, 0x89, 0xab, 0x2f, 0xe5,
-	0x55, 0x5a, 0xa5, 0x9f, 0x58, 0x34, 0xfe, 0x56, 0xa0, 0xb3, 0x95, 0x1f, 0x21, 0xa8, 0x67, 0xf7,
-	0xeb, 0x7c, 0x82, 0x0d, 0x22, 0xce, 0x48, 0x87, 0xd6, 0x2d, 0x4b, 0xd2, 0x30, 0x8e, 0x44, 0xfa,
-	0x06, 0x91, 0x10, 0x7d, 0x0d, 0xed, 0x72, 0x75, 0xba, 0x2a, 0x4a, 0x1f, 0x77, 0xf3, 0xe5, 0x76,
-	0xe5, 0x72, 0xbb, 0x9e, 0x54, 0x90, 0x07, 0x31, 0xcf, 0x29, 0x3a, 0xb1, 0x2d, 0xbd, 0x7e, 0xa2,
-	0x9c, 0xb6, 0x89, 0x84, 0xc2, 0xc1, 0x9d, 0x6d, 0xe9, 0x0d, 0x11, 0x16, 0x67, 0xbe, 0x58, 0xb6,
-	0x8e, 0xa7, 0x0b, 0xbd, 0x99, 0xaf, 0x5e, 0x00, 0x3e, 0x5e, 0x76, 0x97, 0xb1, 0x48, 0x38, 0x6b,
-	0xe5, 0xe3, 0x2d, 0x03, 0x86, 0x09, 0xcf, 0x1e, 0x75, 0x2f, 0x8a, 0x26, 0x2c, 0xc8, 0x62, 0xb9,
-	0x31, 0x09, 0x79, 0x81, 0x28, 0x8e, 0xa6, 0x72, 0x4b, 0x39, 0x30, 0x30, 0xb4, 0xde, 0x07, 0xf7,
-	0xcb, 0x38, 0x98, 0xa1, 0x4f, 0xa1, 0xb9, 0xd8, 0x5e, 0xce, 0xbe, 0x9c, 0x70, 0x31, 0xd8, 0x82,
-	0xe5, 0xee, 0xf9, 0x6b, 0x29, 0xf2, 0x88, 0xb3, 0xd1, 0x87, 0x5d, 0x1c, 0xdd, 0xb2, 0x65, 0x9c,
-	0xcf, 0x72, 0x9d, 0xa7, 0x94, 0x16, 0x0a, 0xf8, 0x3f, 0x8f, 0xe5, 0x67, 0x05, 0x1a, 0xfd, 0x65,
-	0x3c, 0xfd, 0x11, 0x9d, 0xcb, 0x57, 0xf3, 0xf8, 0x99, 0x08, 0x5a, 0xda, 0x29, 0x3a, 0xfe, 0x04,
-	0xea, 0x96, 0xb4, 0xd3, 0xb9, 0x38, 0xa8, 0x48, 0x39, 0x41, 0x04, 0x8d, 0xbe, 0x78, 0xf8, 0x11,
-	0x15, 0x6b, 0x3c, 0xaa, 0x48, 0x25, 0x49, 0x4a, 0x99, 0xc1, 0xa0, 0xb3, 0x55, 0x10, 0xbd, 0x80,
-	0xa6, 0xb3, 0x59, 0x4d, 0x0a, 0x57, 0x75, 0x52, 0x20, 0x64, 0xc0, 0xde, 0xfb, 0x84, 0xdd, 0x86,
-	0xf1, 0x26, 0x7d, 0x1b, 0xa4, 0x8b, 0xa2, 0xb1, 0x4a, 0x0c, 0x1d, 0xc3, 0x2e, 0x77, 0x21, 0x78,
-	0x55, 0xf0, 0x25, 0x36, 0xde, 0x40, 0xbb, 0x34, 0xcb, 0x87, 0x2b, 0xba, 0x51, 0x4e, 0x54, 0x3e,
-	0x5c, 0x7e, 0x36, 0xce, 0xe1, 0xa3, 0x8a, 0x45, 0x9e, 0xad, 0xec, 0x25, 0x17, 0x96, 0xf8, 0xec,
-	0x4f, 0x05, 0x9a, 0x34, 0x0b, 0xb2, 0x4d, 0x8a, 0x3a, 0xd0, 0x1a, 0x3b, 0xef, 0x1c, 0xf7, 0x3b,
-	0x47, 0xdb, 0x41, 0x7b, 0xd0, 0xa2, 0xe3, 0xc1, 0x00, 0x53, 0xaa, 0xfd, 0xa5, 0x20, 0x0d, 0x3a,
-	0x7d, 0xd3, 0xf2, 0x09, 0xfe, 0x76, 0x8c, 0xa9, 0xa7, 0xfd, 0xa2, 0xa2, 0x7d, 0x68, 0x0f, 0x5d,
-	0xd2, 0xb7, 0x2d, 0x0b, 0x3b, 0xda, 0xaf, 0x02, 0x3b, 0xae, 0xe7, 0x0f, 0xdd, 0xb1, 0x63, 0x69,
-	0xbf, 0xa9, 0xe8, 0x35, 0xe8, 0x85, 0xda, 0xc7, 0x8e, 0x67, 0x7b, 0xdf, 0xfb, 0x9e, 0xeb, 0xfa,
-	0x23, 0x93, 0x5c, 0x62, 0xed, 0x0f, 0x15, 0x1d, 0xc3, 0x91, 0xed, 0x78, 0x98, 0x38, 0xe6, 0xc8,
-	0xa7, 0x98, 0x7c, 0xc0, 0xc4, 0xc7, 0x84, 0xb8, 0x44, 0xfb, 0x47, 0x45, 0x3a, 0x1c, 0xf2, 0x90,
-	0x3d, 0xc0, 0xfe, 0xd8, 0x31, 0x3f, 0x98, 0xf6, 0xc8, 0xec, 0x8f, 0xb0, 0xf6, 0xaf, 0x7a, 0xf6,
-	0xbb, 0x02, 0x90, 0x4f, 0xd7, 0xe3, 0xbf, 0xc2, 0x0e, 0xb4, 0xae, 0x30, 0xa5, 0xe6, 0x25, 0xd6,
-	0x76, 0xd0, 0x6b, 0x78, 0x35, 0x70, 0x9d, 0xa1, 0x7d, 0x39, 0x26, 0xa6, 0x67, 0xbb, 0x8e, 0xef,
-	0x11, 0xd3, 0xa1, 0xe6, 0x80, 0x9f, 0x35, 0x05, 0xbd, 0x00, 0x54, 0xa5, 0x6d, 0x0f, 0x5f, 0x69,
-	0x35, 0xa4, 0xc3, 0x73, 0xec, 0x58, 0x2e, 0xa1, 0x98, 0x54, 0x6e, 0xa8, 0xe8, 0x25, 0x1c, 0xba,
-	0xc4, 0xc2, 0xe4, 0x11, 0x51, 0x47, 0x47, 0x70, 0x60, 0xe1, 0x91, 0xcd, 0x3d, 0x53, 0x8c, 0xdf,
-	0xf9, 0xb6, 0x33, 0x74, 0xb5, 0xc6, 0xd9, 0x18, 0x50, 0x65, 0xec, 0x36, 0xff, 0x67, 0x8c, 0xf6,
-	0x01, 0xa8, 0x7d, 0xe9, 0x98, 0xde, 0x98, 0x60, 0xaa, 0xed, 0x70, 0x1f, 0x23, 0x93, 0x7a, 0x7e,
-	0xc5, 0x8c, 0xa6, 0xf0, 0x6a, 0x5b, 0x55, 0xa8, 0x3f, 0xb4, 0x47, 0x1e, 0x26, 0x5a, 0xad, 0xff,
-	0xf9, 0x0f, 0xe7, 0xf3, 0x30, 0x5b, 0x6c, 0x26, 0xfc, 0xf9, 0xf5, 0x16, 0xf7, 0x6b, 0x96, 0x2c,
-	0xd9, 0x6c, 0xce, 0x92, 0xde, 0x4d, 0x30, 0x49, 0xc2, 0x69, 0xfe, 0xc9, 0x48, 0x8b, 0xcf, 0xca,
-	0xa4, 0x29, 0xe0, 0x97, 0xff, 0x05, 0x00, 0x00, 0xff, 0xff, 0x24, 0x97, 0x92, 0x02, 0x6e, 0x06,
-	0x00, 0x00,
+	// 797 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x54, 0x5f, 0x6f, 0xea, 0x36,
+	0x1c, 0x6d, 0x08, 0x7f, 0xca, 0x0f, 0xda, 0x1a, 0xf7, 0x76, 0x4d, 0x2b, 0xdd, 0xdd, 0x8e, 0x3d,
+	0x0c, 0x75, 0x2a, 0x6c, 0xdd, 0xc3, 0x1e, 0xa7, 0x40, 0x4c, 0x1b, 0x5d, 0xea, 0xdc, 0xd9, 0xe1,
+	0x4e, 0xdb, 0x4b, 0x14, 0xc0, 0x85, 0x68, 0x90, 0xa0, 0x10, 0x50, 0xfb, 0x2d, 0x26, 0x6d, 0x4f,
+	0xd3, 0xf6, 0x05, 0xf6, 0x49, 0xf6, 0x81, 0x26, 0xed, 0x75, 0xb2, 0x93, 0xb4, 0xd0, 0x6a, 0x4f,
+	0x60, 0x9f, 0xf3, 0x3b, 0xe7, 0xf8, 0x38, 0x32, 0x1c, 0x8f, 0xa3, 0xc5, 0x22, 0x0a, 0x3b, 0xe9,
+	0x4f, 0x7b, 0x19, 0x47, 0x49, 0x84, 0xcb, 0xe9, 0xea, 0xfc, 0xdd, 0x34, 0x8a, 0xa6, 0x73, 0xd1,
+	0x51, 0xbb, 0xa3, 0xf5, 0x7d, 0x27, 0x09, 0x16, 0x62, 0x95, 0xf8, 0x8b, 0x65, 0x4a, 0x6c, 0x36,
+	0xa1, 0x31, 0xf0, 0x57, 0x49, 0x2f, 0x0a, 0xef, 0x83, 0xe9, 0x3a, 0xf6, 0x93, 0x20, 0x0a, 0xf1,
+	0x01, 0x94, 0x82, 0x70, 0x22, 0x1e, 0x0c, 0xed, 0x42, 0x6b, 0x15, 0x9b, 0xb7, 0xb0, 0x7f, 0x27,
+	0x12, 0x7f, 0xe2, 0x27, 0xbe, 0x84, 0x36, 0xfe, 0x7c, 0x2d, 0x14, 0x54, 0xc7, 0x57, 0x00, 0xab,
+	0x60, 0x1a, 0xfa, 0xc9, 0x3a, 0x16, 0x2b, 0xa3, 0x70, 0xa1, 0xb7, 0x6a, 0xd7, 0x67, 0xed, 0x2c,
+	0x4a, 0x3e, 0xc4, 0x73, 0x46, 0xf3, 0x3b, 0x68, 0xbc, 0xda, 0xc4, 0xa7, 0x70, 0xf4, 0xa4, 0x71,
+	0x2b, 0xfc, 0x89, 0x88, 0x33, 0xf1, 0x06, 0x54, 0x9f, 0x00, 0xa3, 0x20, 0xb7, 0x9a, 0x13, 0x28,
+	0xa7, 0x14, 0xdc, 0x82, 0xda, 0x78, 0xe6, 0x07, 0xe1, 0xd6, 0x44, 0xed, 0xfa, 0x38, 0xb7, 0xee,
+	0x3d, 0x43, 0xf8, 0xab, 0xd7, 0xfa, 0x05, 0xc5, 0x3e, 0xcd, 0xd9, 0x7c, 0x17, 0x6e, 0xfe, 0xa1,
+	0x41, 0x6d, 0x5b, 0xa1, 0x0e, 0xc5, 0xe4, 0x71, 0x99, 0x9e, 0xb9, 0x84, 0x8f, 0xa0, 0xb2, 0x11,
+	0xf1, 0x2a, 0x88, 0x42, 0xa5, 0x53, 0xc2, 0x57, 0x50, 0x7d, 0xaa, 0xd5, 0xd0, 0x95, 0xf4, 0x79,
+	0x3b, 0x2d, 0xbe, 0x9d, 0x17, 0xdf, 0x76, 0x73, 0x86, 0x9c, 0x57, 0xc9, 0x6d, 0xcb, 0x28, 0x5e,
+	0x68, 0xad, 0xaa, 0x92, 0x7f, 0xb0, 0x2d, 0xa3, 0xa4, 0x56, 0x07, 0x50, 0x12, 0xcb, 0x68, 0x3c,
+	0x33, 0xca, 0xb2, 0x7c, 0x59, 0x82, 0x78, 0x48, 0x44, 0xa8, 0xfc, 0x2a, 0xaa, 0x84, 0xaf, 0xe1,
+	0xe8, 0x45, 0x62, 0xa5, 0x19, 0x0b, 0x3f, 0x89, 0xf2, 0xee, 0x0e, 0xa0, 0x14, 0x46, 0xe1, 0x38,
+	0xef, 0xed, 0x5b, 0xa8, 0x7c, 0xf0, 0x1f, 0xe7, 0x91, 0x3f, 0xc1, 0x9f, 0x42, 0x79, 0xb6, 0xdd,
+	0xd9, 0x61, 0xde, 0xc2, 0xf3, 0x61, 0xe5, 0xfd, 0x64, 0x83, 0x6d, 0xd8, 0x27, 0xe1, 0x46, 0xcc,
+	0xa3, 0xa5, 0x90, 0x26, 0xcb, 0x54, 0xe4, 0xff, 0x2f, 0x68, 0x03, 0xa5, 0xee, 0x3c, 0x1a, 0xff,
+	0x8c, 0x3f, 0xcf, 0x6f, 0xea, 0xe5, 0xd5, 0x28, 0x38, 0xf3, 0x7a, 0x07, 0x45, 0x2b, 0xf7, 0xaa,
+	0x5d, 0x37, 0x76, 0x28, 0x12, 0xc0, 0x5f, 0x3c, 0x7f, 0x7a, 0x59, 0xb3, 0x27, 0x3b, 0xa4, 0x1c,
+	0x6c, 0x12, 0xa8, 0x6d, 0x0b, 0x1f, 0x42, 0x99, 0xae, 0x17, 0xa3, 0xcc, 0xbd, 0x88, 0xdf, 0x40,
+	0xfd, 0x43, 0x2c, 0x36, 0x41, 0xb4, 0x5e, 0xdd, 0xfa, 0xab, 0x59, 0x1a, 0x16, 0x23, 0xd8, 0x97,
+	0x2e, 0x6a, 0x47, 0x57, 0xf1, 0xcf, 0xa0, 0xfa, 0x6c, 0x5e, 0xcf, 0xd2, 0x69, 0x17, 0x7a, 0xab,
+	0xde, 0xfc, 0x0c, 0x0e, 0x76, 0x2c, 0xe5, 0xf4, 0x53, 0x36, 0x45, 0xb9, 0xfc, 0x4b, 0x83, 0x32,
+	0x4f, 0xfc, 0x64, 0xbd, 0xc2, 0x35, 0xa8, 0x0c, 0xe9, 0x7b, 0xea, 0xfc, 0x40, 0xd1, 0x1e, 0xae,
+	0x43, 0x85, 0x0f, 0x7b, 0x3d, 0xc2, 0x39, 0xfa, 0x5b, 0xc3, 0x08, 0x6a, 0x5d, 0xd3, 0xf2, 0x18,
+	0xf9, 0x7e, 0x48, 0xb8, 0x8b, 0x7e, 0xd1, 0xf1, 0x21, 0x54, 0xfb, 0x0e, 0xeb, 0xda, 0x96, 0x45,
+	0x28, 0xfa, 0x55, 0xad, 0xa9, 0xe3, 0x7a, 0x7d, 0x67, 0x48, 0x2d, 0xf4, 0x9b, 0x8e, 0xdf, 0x82,
+	0x91, 0xb1, 0x3d, 0x42, 0x5d, 0xdb, 0xfd, 0xd1, 0x73, 0x1d, 0xc7, 0x1b, 0x98, 0xec, 0x86, 0xa0,
+	0x3f, 0x75, 0x7c, 0x0e, 0x27, 0x36, 0x75, 0x09, 0xa3, 0xe6, 0xc0, 0xe3, 0x84, 0x7d, 0x24, 0xcc,
+	0x23, 0x8c, 0x39, 0x0c, 0xfd, 0xa3, 0x63, 0x03, 0x8e, 0xe5, 0x96, 0xdd, 0x23, 0xde, 0x90, 0x9a,
+	0x1f, 0x4d, 0x7b, 0x60, 0x76, 0x07, 0x04, 0xfd, 0xab, 0x5f, 0xfe, 0xae, 0x01, 0xa4, 0x6d, 0xb9,
+	0x8f, 0x4b, 0x21, 0x03, 0xdf, 0x11, 0xce, 0xcd, 0x1b, 0x82, 0xf6, 0xf0, 0x5b, 0x38, 0xeb, 0x39,
+	0xb4, 0x6f, 0xdf, 0x0c, 0x99, 0xe9, 0xda, 0x0e, 0xf5, 0x5c, 0x66, 0x52, 0x6e, 0xf6, 0xe4, 0x7f,
+	0xa4, 0xe1, 0x4f, 0x00, 0xef, 0xc2, 0xb6, 0x4b, 0xee, 0x50, 0x01, 0x1b, 0xf0, 0x86, 0x50, 0xcb,
+	0x61, 0x9c, 0xb0, 0x9d, 0x09, 0x1d, 0x9f, 0xc2, 0xb1, 0xc3, 0x2c, 0xc2, 0x5e, 0x00, 0x45, 0x7c,
+	0x02, 0x0d, 0x8b, 0x0c, 0x6c, 0x99, 0x99, 0x13, 0xf2, 0xde, 0xb3, 0x69, 0xdf, 0x41, 0xa5, 0xcb,
+	0x11, 0xe0, 0x9d, 0xb2, 0x6d, 0xf9, 0x1c, 0xe1, 0x43, 0x00, 0x6e, 0xdf, 0x50, 0xd3, 0x1d, 0x32,
+	0xc2, 0xd1, 0x9e, 0xcc, 0x31, 0x30, 0xb9, 0xeb, 0xed, 0x84, 0x41, 0x9a, 0x74, 0xdb, 0x72, 0xe1,
+	0x5e, 0xdf, 0x1e, 0xb8, 0x84, 0xa1, 0x82, 0x3c, 0x64, 0x16, 0x03, 0xe9, 0xdd, 0xab, 0x9f, 0xbe,
+	0x9c, 0x06, 0xc9, 0x6c, 0x3d, 0x92, 0x5f, 0x54, 0x67, 0xf6, 0xb8, 0x14, 0xf1, 0x5c, 0x4c, 0xa6,
+	0x22, 0xee, 0xdc, 0xfb, 0xa3, 0x38, 0x18, 0xa7, 0x8f, 0xe6, 0x2a, 0x7b, 0x58, 0x47, 0x65, 0xb5,
+	0xfc, 0xe6, 0xbf, 0x01, 0x00, 0xe2, 0x17, 0xe2, 0x19, 0x70, 0x05, 0x00, 0x00,
 }
@@ -20,6 +20,7 @@ const (
 	ConfigurationItem_Chain   ConfigurationItem_ConfigurationType = 1
 	ConfigurationItem_Orderer ConfigurationItem_ConfigurationType = 2
 	ConfigurationItem_Peer    ConfigurationItem_ConfigurationType = 3
+	ConfigurationItem_MSP     ConfigurationItem_ConfigurationType = 4
 )
 
 var ConfigurationItem_ConfigurationType_name = map[int32]string{
@@ -27,12 +28,14 @@ var ConfigurationItem_ConfigurationType_name = map[int32]string{
 	1: "Chain",
 	2: "Orderer",
 	3: "Peer",
+	4: "MSP",
 }
 var ConfigurationItem_ConfigurationType_value = map[string]int32{
 	"Policy":  0,
 	"Chain":   1,
 	"Orderer": 2,
 	"Peer":    3,
+	"MSP":     4,
 }
 
 func (x ConfigurationItem_ConfigurationType) String() string {
@@ -378,46 +381,42 @@ func init() {
 func init() { proto.RegisterFile("common/configuration.proto", fileDescriptor1) }
 
 var fileDescriptor1 = []byte{
-	// 644 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x54, 0xcb, 0x6e, 0xd3, 0x40,
-	0x14, 0x8d, 0xf3, 0x70, 0x9a, 0x9b, 0x40, 0xcd, 0x6d, 0x69, 0x4d, 0x54, 0x95, 0xc8, 0x0b, 0x14,
-	0xa9, 0x90, 0x88, 0xb4, 0x6c, 0x41, 0x2d, 0x2a, 0xa4, 0x2a, 0x75, 0xa2, 0x49, 0x5b, 0x24, 0x36,
-	0xe0, 0xc6, 0x13, 0x67, 0x24, 0xbf, 0x34, 0x76, 0x90, 0xf2, 0x05, 0xfc, 0x03, 0x9f, 0xc2, 0x8a,
-	0x4f, 0x43, 0x9e, 0xb1, 0x8d, 0x93, 0x26, 0x2b, 0xdf, 0xc7, 0x39, 0xe7, 0x3e, 0xec, 0x6b, 0x68,
-	0x4f, 0x03, 0xcf, 0x0b, 0xfc, 0xfe, 0x34, 0xf0, 0x67, 0xcc, 0x59, 0x70, 0x2b, 0x66, 0x81, 0xdf,
-	0x0b, 0x79, 0x10, 0x07, 0xa8, 0xca, 0x5c, 0x7b, 0x2f, 0xc7, 0x24, 0x0f, 0x99, 0x6c, 0x67, 0x44,
-	0x2f, 0x0a, 0xbf, 0x87, 0x9c, 0xf9, 0x53, 0x16, 0x5a, 0xae, 0xcc, 0x19, 0x26, 0x3c, 0xff, 0x58,
-	0xd4, 0xbb, 0xf4, 0x7f, 0x52, 0x37, 0x08, 0x29, 0xbe, 0x83, 0xda, 0x55, 0x4c, 0xbd, 0x48, 0x57,
-	0x3a, 0x95, 0x6e, 0x73, 0xf0, 0xb2, 0x97, 0x4a, 0x4e, 0x98, 0xe3, 0x53, 0x7b, 0x85, 0x93, 0xe0,
-	0x88, 0x44, 0x1b, 0xc3, 0x35, 0xbd, 0x5b, 0xea, 0x85, 0xae, 0x15, 0x53, 0xec, 0xaf, 0xea, 0xbd,
-	0xc8, 0xf4, 0xb6, 0x2a, 0xfd, 0x52, 0xe0, 0x70, 0x4b, 0x31, 0x7c, 0x0d, 0xcf, 0x1e, 0x05, 0x75,
-	0xa5, 0xa3, 0x74, 0x5b, 0xe4, 0x71, 0x02, 0xdf, 0x03, 0x24, 0x42, 0x56, 0xbc, 0xe0, 0x34, 0xd2,
-	0xcb, 0xa2, 0xfe, 0xf1, 0xc6, 0xfa, 0x39, 0x8c, 0x14, 0x18, 0xc6, 0xdf, 0xf2, 0x86, 0x72, 0x78,
-	0x02, 0xea, 0x90, 0x5a, 0x36, 0xe5, 0xa2, 0x70, 0x73, 0xb0, 0x97, 0x2b, 0xce, 0x2d, 0xe6, 0xcb,
-	0x14, 0x49, 0x21, 0xf8, 0x01, 0xaa, 0xb7, 0xcb, 0x90, 0xea, 0xe5, 0x8e, 0xd2, 0x7d, 0x3a, 0x38,
-	0xd9, 0x3a, 0xfc, 0x6a, 0x24, 0xa1, 0x10, 0x41, 0x44, 0x03, 0x5a, 0x5f, 0xac, 0x28, 0xbe, 0x09,
-	0x6c, 0x36, 0x63, 0xd4, 0xd6, 0x2b, 0x1d, 0xa5, 0x5b, 0x25, 0x2b, 0x31, 0xec, 0x01, 0x4a, 0x7b,
-	0x2a, 0xd8, 0xe3, 0xc0, 0x65, 0xd3, 0xa5, 0x5e, 0xed, 0x28, 0xdd, 0x06, 0xd9, 0x90, 0x41, 0x0d,
-	0x2a, 0xd7, 0x74, 0xa9, 0xd7, 0x04, 0x20, 0x31, 0x71, 0x1f, 0x6a, 0xf7, 0x96, 0xbb, 0xa0, 0xba,
-	0x2a, 0x76, 0x29, 0x1d, 0xe3, 0x7c, 0x6d, 0x7c, 0xd1, 0x10, 0x80, 0x2a, 0x65, 0xb4, 0x12, 0x36,
-	0xa0, 0x26, 0x86, 0xd6, 0x14, 0x6c, 0x42, 0x7d, 0xc4, 0x6d, 0xca, 0x29, 0xd7, 0xca, 0xb8, 0x03,
-	0xd5, 0x31, 0xa5, 0x5c, 0xab, 0x18, 0x3f, 0xe0, 0x60, 0xf3, 0xa2, 0xb1, 0x0b, 0xbb, 0x51, 0xe6,
-	0x14, 0xf6, 0xd9, 0x22, 0xeb, 0x61, 0x3c, 0x82, 0x46, 0x1e, 0x12, 0x8b, 0x6c, 0x91, 0xff, 0x01,
-	0xc3, 0xc9, 0xfa, 0x41, 0x84, 0x6a, 0x9c, 0xec, 0x3a, 0x91, 0xa9, 0x11, 0x61, 0xe3, 0x01, 0xa8,
-	0xa1, 0x5c, 0x87, 0x24, 0xa6, 0x9e, 0xf1, 0x16, 0x40, 0xb2, 0xc4, 0x4c, 0x4d, 0xa8, 0xdf, 0x99,
-	0xd7, 0xe6, 0xe8, 0xab, 0xa9, 0x95, 0xf0, 0x09, 0x34, 0x26, 0x57, 0x9f, 0xcd, 0xf3, 0xdb, 0x3b,
-	0x72, 0xa9, 0x29, 0x58, 0x87, 0xca, 0xcd, 0x64, 0xac, 0x95, 0x8d, 0xdf, 0xe9, 0x77, 0x29, 0xca,
-	0x4a, 0x72, 0x7e, 0x34, 0x3a, 0xd4, 0xef, 0x29, 0x8f, 0x58, 0xe0, 0xa7, 0xd5, 0x33, 0x17, 0xfb,
-	0x59, 0x7b, 0xa2, 0x81, 0xe6, 0xe0, 0xb0, 0x78, 0x4f, 0x05, 0x29, 0x92, 0x4d, 0x71, 0x06, 0x70,
-	0x65, 0x53, 0x3f, 0x66, 0x31, 0xa3, 0x91, 0x5e, 0x11, 0x1f, 0xed, 0x7e, 0x46, 0xba, 0x99, 0x8c,
-	0xc7, 0xd9, 0x21, 0x93, 0x02, 0xce, 0xf8, 0xa3, 0xc0, 0xee, 0x9a, 0x22, 0x1e, 0xc1, 0x8e, 0xbc,
-	0xa3, 0x8b, 0xa5, 0xec, 0x6a, 0x58, 0x22, 0x79, 0x04, 0xcf, 0xa0, 0xfa, 0x89, 0x07, 0x5e, 0xda,
-	0xd6, 0xf1, 0x96, 0xb6, 0x7a, 0xe6, 0x68, 0x11, 0x8f, 0x66, 0xc3, 0x12, 0x11, 0xe8, 0xf6, 0x35,
-	0xa8, 0x32, 0x82, 0x2d, 0x50, 0xcc, 0x74, 0x58, 0xc5, 0xc4, 0x53, 0xd8, 0x11, 0x04, 0x96, 0x1f,
-	0xda, 0xd6, 0x41, 0x73, 0xe0, 0x85, 0x2a, 0x8f, 0xc3, 0x78, 0x05, 0xda, 0xd0, 0x8a, 0xe6, 0xcc,
-	0x77, 0xce, 0x5d, 0x27, 0xe0, 0x2c, 0x9e, 0x7b, 0xc9, 0xcb, 0xf4, 0x2d, 0x4f, 0xbe, 0xcc, 0x06,
-	0x11, 0xf6, 0xc5, 0x9b, 0x6f, 0x27, 0x0e, 0x8b, 0xe7, 0x8b, 0x87, 0x44, 0xba, 0x3f, 0x5f, 0x86,
-	0x94, 0xbb, 0xd4, 0x76, 0x28, 0xef, 0xcf, 0xac, 0x07, 0xce, 0xa6, 0x7d, 0xf1, 0x6b, 0x8b, 0xd2,
-	0x9f, 0xe0, 0x83, 0x2a, 0xdc, 0xd3, 0x7f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x6f, 0x22, 0xa7, 0x46,
-	0x40, 0x05, 0x00, 0x00,
+	// 590 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x54, 0xc1, 0x6e, 0xd3, 0x40,
+	0x10, 0x8d, 0x13, 0xc7, 0x69, 0x26, 0x69, 0xeb, 0x4e, 0x0b, 0x75, 0x73, 0x28, 0x91, 0x39, 0x60,
+	0x54, 0x91, 0x88, 0x70, 0xe2, 0x82, 0xd4, 0x42, 0x69, 0x4a, 0x69, 0x12, 0x91, 0xb6, 0x08, 0x2e,
+	0xc8, 0xb5, 0x37, 0xf6, 0x4a, 0xb6, 0xd7, 0x5a, 0x6f, 0x90, 0x72, 0xe0, 0x13, 0xf8, 0x04, 0xfe,
+	0x15, 0x79, 0x37, 0xb6, 0xd2, 0x36, 0xb9, 0x79, 0x67, 0xde, 0xbc, 0x37, 0x33, 0xfb, 0xbc, 0xd0,
+	0xf1, 0x58, 0x1c, 0xb3, 0xa4, 0xef, 0xb1, 0x64, 0x46, 0x83, 0x39, 0x77, 0x05, 0x65, 0x49, 0x2f,
+	0xe5, 0x4c, 0x30, 0x34, 0x54, 0xae, 0xb3, 0x5f, 0x62, 0xe2, 0xb8, 0x48, 0x76, 0x8a, 0xc2, 0x38,
+	0x4b, 0x7f, 0xa5, 0x9c, 0x26, 0x1e, 0x4d, 0xdd, 0x48, 0xe5, 0xec, 0x0b, 0x78, 0xf6, 0x71, 0x95,
+	0xef, 0x3c, 0xf9, 0x4d, 0x22, 0x96, 0x12, 0xec, 0x41, 0xfd, 0x52, 0x90, 0x38, 0xb3, 0xb4, 0x6e,
+	0xcd, 0x69, 0x0d, 0x5e, 0xf4, 0x96, 0x94, 0x53, 0x1a, 0x24, 0xc4, 0x7f, 0x50, 0x93, 0xe3, 0xec,
+	0xd3, 0x47, 0x44, 0x37, 0x24, 0x4e, 0x23, 0x57, 0x10, 0x74, 0x1e, 0x12, 0x1d, 0x15, 0x44, 0x4f,
+	0x29, 0x42, 0x38, 0xdc, 0xc0, 0x8e, 0x47, 0xb0, 0xf7, 0x24, 0x68, 0x69, 0x5d, 0xcd, 0x69, 0xe3,
+	0x00, 0x20, 0xaf, 0x72, 0xc5, 0x9c, 0x93, 0xcc, 0xaa, 0x4a, 0x91, 0xe3, 0xb5, 0x22, 0x25, 0xcc,
+	0xfe, 0x5b, 0x5d, 0xc3, 0x87, 0x2f, 0xc1, 0x18, 0x12, 0xd7, 0x27, 0x5c, 0x32, 0xb7, 0x06, 0xfb,
+	0x25, 0x4b, 0xe8, 0xd2, 0x44, 0xa5, 0xf0, 0x3d, 0xe8, 0x37, 0x8b, 0x94, 0x58, 0xd5, 0xae, 0xe6,
+	0xec, 0x0c, 0x4e, 0x36, 0x4e, 0xf3, 0x30, 0x92, 0x97, 0xe0, 0x01, 0xb4, 0xbf, 0xba, 0x99, 0xb8,
+	0x66, 0x3e, 0x9d, 0x51, 0xe2, 0x5b, 0xb5, 0xae, 0xe6, 0xe8, 0xd8, 0x01, 0x54, 0x11, 0x4f, 0x22,
+	0x27, 0x2c, 0xa2, 0xde, 0xc2, 0xd2, 0xbb, 0x9a, 0xd3, 0xc4, 0x16, 0xd4, 0xae, 0xc8, 0xc2, 0xaa,
+	0xcb, 0xc3, 0x36, 0xd4, 0xef, 0xdc, 0x68, 0x4e, 0x2c, 0x23, 0x9f, 0xdb, 0xfe, 0x02, 0x7b, 0x4f,
+	0x25, 0x00, 0x0c, 0x45, 0x60, 0x56, 0xb0, 0x09, 0x75, 0xd9, 0xb8, 0xa9, 0x61, 0x0b, 0x1a, 0x63,
+	0xee, 0x13, 0x4e, 0xb8, 0x59, 0xc5, 0x2d, 0xd0, 0x27, 0x84, 0x70, 0xb3, 0x86, 0x0d, 0xa8, 0x5d,
+	0x4f, 0x27, 0xa6, 0x6e, 0x7f, 0x82, 0xe7, 0xeb, 0x37, 0x85, 0x87, 0xb0, 0x9b, 0x15, 0x87, 0x95,
+	0xe5, 0xb4, 0x71, 0x0f, 0x9a, 0x65, 0x42, 0x2e, 0xa3, 0x6d, 0xff, 0x28, 0xc4, 0xb1, 0x0d, 0xba,
+	0xc8, 0x97, 0x94, 0x43, 0xeb, 0xb8, 0x03, 0x46, 0xaa, 0xa6, 0x52, 0xb8, 0xb7, 0x00, 0x0a, 0x27,
+	0x5b, 0x6e, 0x41, 0xe3, 0x76, 0x74, 0x35, 0x1a, 0x7f, 0x1f, 0x99, 0x15, 0xdc, 0x86, 0xe6, 0xf4,
+	0xf2, 0x62, 0x74, 0x7a, 0x73, 0xfb, 0xed, 0xdc, 0xd4, 0x8a, 0x06, 0xab, 0xf6, 0x1f, 0x65, 0x0d,
+	0xa9, 0xa6, 0x6a, 0x4b, 0xa3, 0xee, 0x42, 0xe3, 0x8e, 0xf0, 0x8c, 0xb2, 0x64, 0x29, 0xf7, 0xaa,
+	0x68, 0x43, 0xca, 0xb5, 0x06, 0x87, 0xab, 0xd6, 0x5d, 0x61, 0x40, 0x07, 0xe0, 0xd2, 0x27, 0x89,
+	0xa0, 0x82, 0x92, 0xcc, 0xaa, 0x49, 0xe7, 0x1c, 0x14, 0xe0, 0xeb, 0xe9, 0x64, 0x52, 0xfc, 0x2b,
+	0xf6, 0x3f, 0x0d, 0x76, 0x1f, 0x57, 0x23, 0x6c, 0x29, 0xb7, 0x9e, 0x2d, 0x94, 0xf0, 0xb0, 0x82,
+	0x3d, 0xd0, 0x3f, 0x73, 0x16, 0x2f, 0x85, 0x8f, 0x37, 0x08, 0xf7, 0x46, 0xe3, 0xb9, 0x18, 0xcf,
+	0x86, 0x95, 0xce, 0x07, 0x30, 0xd4, 0x37, 0x36, 0x41, 0x1b, 0x2d, 0xfb, 0x7f, 0x0d, 0x5b, 0x12,
+	0x47, 0x4b, 0x3b, 0x6f, 0x9a, 0xe0, 0xcc, 0x50, 0x66, 0xb4, 0xbb, 0x60, 0x0e, 0xdd, 0x2c, 0xa4,
+	0x49, 0x70, 0x1a, 0x05, 0x8c, 0x53, 0x11, 0xc6, 0xf9, 0x1d, 0x24, 0x6e, 0xac, 0xee, 0xa0, 0x79,
+	0xf6, 0xe6, 0xe7, 0x49, 0x40, 0x45, 0x38, 0xbf, 0xcf, 0xa9, 0xfa, 0xe1, 0x22, 0x25, 0x3c, 0x22,
+	0x7e, 0x40, 0x78, 0x7f, 0xe6, 0xde, 0x73, 0xea, 0xf5, 0xe5, 0x73, 0x90, 0x2d, 0x1f, 0x8e, 0x7b,
+	0x43, 0x1e, 0xdf, 0xfd, 0x1f, 0x00, 0xbf, 0xed, 0xcb, 0xbf, 0x74, 0x04, 0x00, 0x00,
 }
@@ -17,6 +17,7 @@ It has these top-level messages:
 	SeekSpecified
 	SeekPosition
 	SeekInfo
+	FilteredBlock
 	DeliverResponse
 	ConsensusType
 	BatchSize
@@ -26,10 +27,12 @@ It has these top-level messages:
 	EgressPolicy
 	ChainCreators
 	KafkaBrokers
+	SbftOptions
 	KafkaMessage
 	KafkaMessageRegular
 	KafkaMessageTimeToCut
 	KafkaMessageConnect
+	KafkaMetadata
 */
 package orderer
 
@@ -75,6 +78,33 @@ func (x SeekInfo_SeekBehavior) String() string {
 }
 func (SeekInfo_SeekBehavior) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{5, 0} }
 
+type SeekInfo_SeekContentType int32
+
+const (
+	SeekInfo_FULL_BLOCK           SeekInfo_SeekContentType = 0
+	SeekInfo_HEADER_WITH_METADATA SeekInfo_SeekContentType = 1
+	SeekInfo_TX_IDS               SeekInfo_SeekContentType = 2
+	SeekInfo_CHAINCODE_TXS        SeekInfo_SeekContentType = 3
+)
+
+var SeekInfo_SeekContentType_name = map[int32]string{
+	0: "FULL_BLOCK",
+	1: "HEADER_WITH_METADATA",
+	2: "TX_IDS",
+	3: "CHAINCODE_TXS",
+}
+var SeekInfo_SeekContentType_value = map[string]int32{
+	"FULL_BLOCK":           0,
+	"HEADER_WITH_METADATA": 1,
+	"TX_IDS":               2,
+	"CHAINCODE_TXS":        3,
+}
+
+func (x SeekInfo_SeekContentType) String() string {
+	return proto.EnumName(SeekInfo_SeekContentType_name, int32(x))
+}
+func (SeekInfo_SeekContentType) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{5, 1} }
+
 type BroadcastResponse struct {
 	Status common.Status `protobuf:"varint,1,opt,name=status,enum=common.Status" json:"status,omitempty"`
 }
@@ -269,10 +299,17 @@ func _SeekPosition_OneofSizer(msg proto.Message) (n int) {
 // error indicating that the block is not found.  To request that all blocks be returned indefinitely
 // as they are created, behavior should be set to BLOCK_UNTIL_READY and the stop should be set to
 // specified with a number of MAX_UINT64
+// The SeekContentType determines how much of each block is returned.  FULL_BLOCK returns the
+// whole block, whereas HEADER_WITH_METADATA and TX_IDS return a FilteredBlock carrying the
+// block header along with the block metadata or the IDs of the transactions in the block
+// respectively.  CHAINCODE_TXS returns a FilteredBlock carrying the block header and metadata
+// along with the transactions of the block which invoke the chaincode named by chaincode_name
 type SeekInfo struct {
-	Start    *SeekPosition         `protobuf:"bytes,1,opt,name=start" json:"start,omitempty"`
-	Stop     *SeekPosition         `protobuf:"bytes,2,opt,name=stop" json:"stop,omitempty"`
-	Behavior SeekInfo_SeekBehavior `protobuf:"varint,3,opt,name=behavior,enum=orderer.SeekInfo_SeekBehavior" json:"behavior,omitempty"`
+	Start         *SeekPosition            `protobuf:"bytes,1,opt,name=start" json:"start,omitempty"`
+	Stop          *SeekPosition            `protobuf:"bytes,2,opt,name=stop" json:"stop,omitempty"`
+	Behavior      SeekInfo_SeekBehavior    `protobuf:"varint,3,opt,name=behavior,enum=orderer.SeekInfo_SeekBehavior" json:"behavior,omitempty"`
+	ContentType   SeekInfo_SeekContentType `protobuf:"varint,4,opt,name=content_type,enum=orderer.SeekInfo_SeekContentType" json:"content_type,omitempty"`
+	ChaincodeName string                   `protobuf:"bytes,5,opt,name=chaincode_name" json:"chaincode_name,omitempty"`
 }
 
 func (m *SeekInfo) Reset()                    { *m = SeekInfo{} }
@@ -294,17 +331,54 @@ func (m *SeekInfo) GetStop() *SeekPosition {
 	return nil
 }
 
+// FilteredBlock is a block stripped of all or part of its data, returned for the
+// SeekContentTypes other than FULL_BLOCK
+type FilteredBlock struct {
+	Header    *common.BlockHeader   `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	Metadata  *common.BlockMetadata `protobuf:"bytes,2,opt,name=metadata" json:"metadata,omitempty"`
+	TxIds     []string              `protobuf:"bytes,3,rep,name=tx_ids" json:"tx_ids,omitempty"`
+	Data      *common.BlockData     `protobuf:"bytes,4,opt,name=data" json:"data,omitempty"`
+	TxIndexes []uint32              `protobuf:"varint,5,rep,name=tx_indexes" json:"tx_indexes,omitempty"`
+}
+
+func (m *FilteredBlock) Reset()                    { *m = FilteredBlock{} }
+func (m *FilteredBlock) String() string            { return proto.CompactTextString(m) }
+func (*FilteredBlock) ProtoMessage()               {}
+func (*FilteredBlock) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *FilteredBlock) GetHeader() *common.BlockHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *FilteredBlock) GetMetadata() *common.BlockMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *FilteredBlock) GetData() *common.BlockData {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
 type DeliverResponse struct {
 	// Types that are valid to be assigned to Type:
 	//	*DeliverResponse_Status
 	//	*DeliverResponse_Block
+	//	*DeliverResponse_FilteredBlock
 	Type isDeliverResponse_Type `protobuf_oneof:"Type"`
 }
 
 func (m *DeliverResponse) Reset()                    { *m = DeliverResponse{} }
 func (m *DeliverResponse) String() string            { return proto.CompactTextString(m) }
 func (*DeliverResponse) ProtoMessage()               {}
-func (*DeliverResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*DeliverResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
 type isDeliverResponse_Type interface {
 	isDeliverResponse_Type()
@@ -316,9 +390,13 @@ type DeliverResponse_Status struct {
 type DeliverResponse_Block struct {
 	Block *common.Block `protobuf:"bytes,2,opt,name=block,oneof"`
 }
+type DeliverResponse_FilteredBlock struct {
+	FilteredBlock *FilteredBlock `protobuf:"bytes,3,opt,name=filtered_block,oneof"`
+}
 
-func (*DeliverResponse_Status) isDeliverResponse_Type() {}
-func (*DeliverResponse_Block) isDeliverResponse_Type()  {}
+func (*DeliverResponse_Status) isDeliverResponse_Type()        {}
+func (*DeliverResponse_Block) isDeliverResponse_Type()         {}
+func (*DeliverResponse_FilteredBlock) isDeliverResponse_Type() {}
 
 func (m *DeliverResponse) GetType() isDeliverResponse_Type {
 	if m != nil {
@@ -341,11 +419,19 @@ func (m *DeliverResponse) GetBlock() *common.Block {
 	return nil
 }
 
+func (m *DeliverResponse) GetFilteredBlock() *FilteredBlock {
+	if x, ok := m.GetType().(*DeliverResponse_FilteredBlock); ok {
+		return x.FilteredBlock
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*DeliverResponse) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _DeliverResponse_OneofMarshaler, _DeliverResponse_OneofUnmarshaler, _DeliverResponse_OneofSizer, []interface{}{
 		(*DeliverResponse_Status)(nil),
 		(*DeliverResponse_Block)(nil),
+		(*DeliverResponse_FilteredBlock)(nil),
 	}
 }
 
@@ -361,6 +447,11 @@ func _DeliverResponse_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.Block); err != nil {
 			return err
 		}
+	case *DeliverResponse_FilteredBlock:
+		b.EncodeVarint(3<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.FilteredBlock); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("DeliverResponse.Type has unexpected type %T", x)
@@ -386,6 +477,14 @@ func _DeliverResponse_OneofUnmarshaler(msg proto.Message, tag, wire int, b *prot
 		err := b.DecodeMessage(msg)
 		m.Type = &DeliverResponse_Block{msg}
 		return true, err
+	case 3: // Type.filtered_block
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(FilteredBlock)
+		err := b.DecodeMessage(msg)
+		m.Type = &DeliverResponse_FilteredBlock{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -403,6 +502,11 @@ func _DeliverResponse_OneofSizer(msg proto.Message) (n int) {
 		n += proto.SizeVarint(2<<3 | proto.WireBytes)
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *DeliverResponse_FilteredBlock:
+		s := proto.Size(x.FilteredBlock)
+		n += proto.SizeVarint(3<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -417,8 +521,10 @@ func init() {
 	proto.RegisterType((*SeekSpecified)(nil), "orderer.SeekSpecified")
 	proto.RegisterType((*SeekPosition)(nil), "orderer.SeekPosition")
 	proto.RegisterType((*SeekInfo)(nil), "orderer.SeekInfo")
+	proto.RegisterType((*FilteredBlock)(nil), "orderer.FilteredBlock")
 	proto.RegisterType((*DeliverResponse)(nil), "orderer.DeliverResponse")
 	proto.RegisterEnum("orderer.SeekInfo_SeekBehavior", SeekInfo_SeekBehavior_name, SeekInfo_SeekBehavior_value)
+	proto.RegisterEnum("orderer.SeekInfo_SeekContentType", SeekInfo_SeekContentType_name, SeekInfo_SeekContentType_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -597,35 +703,42 @@ var _AtomicBroadcast_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("orderer/ab.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 480 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x7c, 0x93, 0xcd, 0x6e, 0xd3, 0x40,
-	0x10, 0xc7, 0x63, 0x48, 0xdd, 0x76, 0x68, 0xd3, 0x74, 0xab, 0x56, 0x51, 0x0e, 0x08, 0x59, 0x02,
-	0x82, 0x00, 0x1b, 0x05, 0x89, 0x03, 0x45, 0x42, 0x31, 0x6d, 0x95, 0x88, 0x28, 0x41, 0x4e, 0x38,
-	0xc0, 0x25, 0xf2, 0xc7, 0xa4, 0x31, 0x75, 0xbc, 0xd6, 0xee, 0x26, 0xa8, 0x4f, 0xc1, 0x8b, 0xf0,
-	0x48, 0x3c, 0x0c, 0xda, 0x0f, 0x3b, 0x0a, 0x14, 0x4e, 0xc9, 0xcc, 0xfc, 0xfe, 0xb3, 0xff, 0x19,
-	0x8d, 0xa1, 0x49, 0x59, 0x82, 0x0c, 0x99, 0x17, 0x46, 0x6e, 0xc1, 0xa8, 0xa0, 0x64, 0xd7, 0x64,
-	0xda, 0x27, 0x31, 0x5d, 0x2e, 0x69, 0xee, 0xe9, 0x1f, 0x5d, 0x75, 0xce, 0xe1, 0xd8, 0x67, 0x34,
-	0x4c, 0xe2, 0x90, 0x8b, 0x00, 0x79, 0x41, 0x73, 0x8e, 0xe4, 0x09, 0xd8, 0x5c, 0x84, 0x62, 0xc5,
-	0x5b, 0xd6, 0x23, 0xab, 0xd3, 0xe8, 0x36, 0x5c, 0xa3, 0x99, 0xa8, 0x6c, 0x60, 0xaa, 0xce, 0x01,
-	0xc0, 0x04, 0xf1, 0x66, 0x84, 0xdf, 0x91, 0x8b, 0x32, 0x1a, 0x67, 0x89, 0x8c, 0x9e, 0xc2, 0xa1,
-	0x8c, 0x26, 0x05, 0xc6, 0xe9, 0x3c, 0xc5, 0x84, 0x9c, 0x81, 0x9d, 0xaf, 0x96, 0x11, 0x32, 0xd5,
-	0xb4, 0x1e, 0x98, 0xc8, 0xf9, 0x69, 0xc1, 0x81, 0x24, 0x3f, 0x51, 0x9e, 0x8a, 0x94, 0xe6, 0xe4,
-	0x25, 0xd8, 0xb9, 0xea, 0xa8, 0xc0, 0x07, 0xdd, 0x13, 0xd7, 0x4c, 0xe0, 0x6e, 0x1e, 0xeb, 0xd7,
-	0x02, 0x03, 0x49, 0x9c, 0xaa, 0x27, 0x5b, 0xf7, 0xee, 0xc0, 0xb5, 0x1b, 0x89, 0x6b, 0x88, 0xbc,
-	0x81, 0x7d, 0x5e, 0x7a, 0x6a, 0xdd, 0x57, 0x8a, 0xb3, 0x2d, 0x45, 0xe5, 0xb8, 0x5f, 0x0b, 0x36,
-	0xa8, 0x6f, 0x43, 0x7d, 0x7a, 0x5b, 0xa0, 0xf3, 0xcb, 0x82, 0x3d, 0x89, 0x0d, 0xf2, 0x39, 0x25,
-	0xcf, 0x61, 0x87, 0x8b, 0x90, 0x95, 0x4e, 0x4f, 0xb7, 0x1a, 0x95, 0x03, 0x05, 0x9a, 0x21, 0xcf,
-	0xa0, 0xce, 0x05, 0x2d, 0x8c, 0xcd, 0x7f, 0xb0, 0x0a, 0x21, 0x6f, 0x61, 0x2f, 0xc2, 0x45, 0xb8,
-	0x4e, 0x29, 0x53, 0x1e, 0x1b, 0xdd, 0x87, 0x5b, 0xb8, 0x7c, 0x5c, 0xfd, 0xf1, 0x0d, 0x15, 0x54,
-	0xbc, 0xf3, 0x4e, 0xaf, 0xb3, 0xac, 0x90, 0x53, 0x38, 0xf6, 0x87, 0xe3, 0x0f, 0x1f, 0x67, 0x9f,
-	0x47, 0xd3, 0xc1, 0x70, 0x16, 0x5c, 0xf6, 0x2e, 0xbe, 0x34, 0x6b, 0x32, 0x7d, 0xd5, 0x1b, 0x0c,
-	0x67, 0x83, 0xab, 0xd9, 0x68, 0x3c, 0x35, 0x69, 0xcb, 0xf9, 0x06, 0x47, 0x17, 0x98, 0xa5, 0x6b,
-	0x64, 0xd5, 0x35, 0x74, 0xfe, 0x7f, 0x0d, 0x72, 0xb7, 0xba, 0x4e, 0x1e, 0xc3, 0x4e, 0x94, 0xd1,
-	0xf8, 0xc6, 0x8c, 0x78, 0x58, 0x82, 0xbe, 0x4c, 0xf6, 0x6b, 0x81, 0xae, 0x96, 0xab, 0xec, 0xfe,
-	0xb0, 0xe0, 0xa8, 0x27, 0xe8, 0x32, 0x8d, 0xab, 0x13, 0x24, 0xef, 0x61, 0x7f, 0x13, 0x34, 0xcb,
-	0x06, 0x97, 0xf9, 0x1a, 0x33, 0x5a, 0x60, 0xbb, 0x5d, 0xad, 0xe1, 0xaf, 0xab, 0x75, 0x6a, 0x1d,
-	0xeb, 0x95, 0x45, 0xce, 0x61, 0xd7, 0x0c, 0x70, 0x87, 0xbc, 0x55, 0xc9, 0xff, 0x18, 0x52, 0x8b,
-	0x7d, 0xf7, 0xeb, 0x8b, 0xeb, 0x54, 0x2c, 0x56, 0x91, 0x54, 0x7a, 0x8b, 0xdb, 0x02, 0x59, 0x86,
-	0xc9, 0x35, 0x32, 0x6f, 0x1e, 0x46, 0x2c, 0x8d, 0x3d, 0xf5, 0xd1, 0x70, 0xcf, 0x74, 0x89, 0x6c,
-	0x15, 0xbf, 0xfe, 0x1d, 0x00, 0x00, 0xff, 0xff, 0xe2, 0x79, 0xcc, 0xb7, 0x76, 0x03, 0x00, 0x00,
+	// 589 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x54, 0xcd, 0x4e, 0xdb, 0x40,
+	0x10, 0xb6, 0x43, 0x30, 0x30, 0x0d, 0x21, 0x2c, 0xa5, 0xb2, 0x38, 0x50, 0x6a, 0x5a, 0x15, 0xa9,
+	0x55, 0x82, 0xc2, 0xa1, 0x87, 0x56, 0xaa, 0x1c, 0x92, 0xd4, 0x51, 0x03, 0x54, 0xc4, 0xa8, 0x3f,
+	0x17, 0xcb, 0x3f, 0x13, 0x62, 0x91, 0x78, 0xad, 0xdd, 0x85, 0x96, 0x87, 0xa8, 0xaa, 0x3e, 0x55,
+	0x5f, 0xab, 0xf2, 0x7a, 0x63, 0x6a, 0x1a, 0x71, 0x4a, 0x66, 0xe6, 0xfb, 0x99, 0xd9, 0x9d, 0x35,
+	0x34, 0x28, 0x8b, 0x90, 0x21, 0x6b, 0xf9, 0x41, 0x33, 0x65, 0x54, 0x50, 0xb2, 0xa2, 0x32, 0x3b,
+	0x5b, 0x21, 0x9d, 0xcd, 0x68, 0xd2, 0xca, 0x7f, 0xf2, 0xaa, 0x75, 0x04, 0x9b, 0x1d, 0x46, 0xfd,
+	0x28, 0xf4, 0xb9, 0x38, 0x47, 0x9e, 0xd2, 0x84, 0x23, 0xd9, 0x05, 0x83, 0x0b, 0x5f, 0x5c, 0x73,
+	0x53, 0xdf, 0xd3, 0x0f, 0xea, 0xed, 0x7a, 0x53, 0x71, 0x46, 0x32, 0x6b, 0xd5, 0x00, 0x46, 0x88,
+	0x57, 0xa7, 0xf8, 0x1d, 0xb9, 0x98, 0x47, 0x67, 0xd3, 0x28, 0x8b, 0x9e, 0xc2, 0x7a, 0x16, 0x8d,
+	0x52, 0x0c, 0xe3, 0x71, 0x8c, 0x11, 0xa9, 0x83, 0x91, 0x5c, 0xcf, 0x02, 0x64, 0x52, 0xac, 0x6a,
+	0xfd, 0xd6, 0xa1, 0x96, 0x21, 0x3e, 0x51, 0x1e, 0x8b, 0x98, 0x26, 0xe4, 0x05, 0x18, 0x89, 0x54,
+	0x92, 0x80, 0x47, 0xed, 0xad, 0xa6, 0xea, 0xb8, 0x79, 0x67, 0xe2, 0x68, 0x19, 0x8c, 0x4a, 0x0b,
+	0xb3, 0xb2, 0x00, 0x96, 0xbb, 0x3b, 0x1a, 0x79, 0x05, 0x6b, 0x7c, 0xee, 0x6d, 0x2e, 0x49, 0xe4,
+	0x93, 0x12, 0xb2, 0xe8, 0xcc, 0xd1, 0x3a, 0x06, 0x54, 0xdd, 0xdb, 0x14, 0xad, 0x3f, 0x15, 0x58,
+	0xcd, 0x6a, 0x83, 0x64, 0x4c, 0xc9, 0x73, 0x58, 0xe6, 0xc2, 0x67, 0xf3, 0x76, 0xb6, 0x4b, 0xec,
+	0xa2, 0xeb, 0x7d, 0xa8, 0x72, 0x41, 0x53, 0xb3, 0xf2, 0x10, 0xe8, 0x10, 0x56, 0x03, 0x9c, 0xf8,
+	0x37, 0x31, 0x65, 0xb2, 0x97, 0x7a, 0x7b, 0xb7, 0x04, 0xcc, 0xfc, 0xe4, 0x9f, 0x8e, 0x42, 0x91,
+	0x37, 0x50, 0x0b, 0x69, 0x22, 0x30, 0x11, 0x9e, 0xb8, 0x4d, 0xd1, 0xac, 0x4a, 0xd6, 0xb3, 0xc5,
+	0xac, 0xe3, 0x1c, 0x29, 0x47, 0x78, 0x07, 0xb5, 0x92, 0xd0, 0x36, 0x6c, 0x76, 0x86, 0x67, 0xc7,
+	0x1f, 0xbd, 0x8b, 0x53, 0x77, 0x30, 0xf4, 0xce, 0x7b, 0x76, 0xf7, 0x6b, 0x43, 0xcb, 0xd2, 0x7d,
+	0x7b, 0x30, 0xf4, 0x06, 0x7d, 0xef, 0xf4, 0xcc, 0x55, 0x69, 0xdd, 0xfa, 0x00, 0x1b, 0xf7, 0x04,
+	0x49, 0x1d, 0xa0, 0x7f, 0x31, 0x1c, 0x7a, 0x52, 0xa5, 0xa1, 0x11, 0x13, 0x1e, 0x3b, 0x3d, 0xbb,
+	0xdb, 0x3b, 0xf7, 0x3e, 0x0f, 0x5c, 0xc7, 0x3b, 0xe9, 0xb9, 0x76, 0xd7, 0x76, 0xed, 0x86, 0x4e,
+	0x00, 0x0c, 0xf7, 0x8b, 0x37, 0xe8, 0x8e, 0x1a, 0x15, 0x6b, 0x06, 0xeb, 0xfd, 0x78, 0x2a, 0x90,
+	0x61, 0xd4, 0x99, 0xd2, 0xf0, 0x8a, 0xec, 0x83, 0x31, 0x41, 0x3f, 0x52, 0xd7, 0x9f, 0x5d, 0x9b,
+	0xda, 0x25, 0x59, 0x76, 0x64, 0x89, 0xbc, 0x84, 0xd5, 0x19, 0x0a, 0x3f, 0xf2, 0x85, 0x5f, 0x1c,
+	0xe8, 0xbf, 0xb0, 0x13, 0x55, 0xcc, 0x96, 0x49, 0xfc, 0xf0, 0xe2, 0x88, 0x9b, 0x4b, 0x7b, 0x4b,
+	0x07, 0x6b, 0xd6, 0x4f, 0x1d, 0x36, 0xba, 0x38, 0x8d, 0x6f, 0x90, 0x15, 0xdb, 0xbb, 0xf7, 0xf0,
+	0xf6, 0x3a, 0x1a, 0xd9, 0x85, 0xe5, 0x20, 0x93, 0x55, 0x5e, 0xeb, 0xe5, 0x96, 0x34, 0x72, 0x08,
+	0xf5, 0xb1, 0x1a, 0xc2, 0xcb, 0x81, 0xf7, 0x17, 0xa9, 0x34, 0xe3, 0xdd, 0x22, 0xb5, 0x7f, 0xe9,
+	0xb0, 0x61, 0x0b, 0x3a, 0x8b, 0xc3, 0xe2, 0x55, 0x91, 0xf7, 0xb0, 0x76, 0x17, 0x34, 0xe6, 0x5e,
+	0xbd, 0xe4, 0x06, 0xa7, 0x34, 0xc5, 0x9d, 0x9d, 0x42, 0xf4, 0xbf, 0x87, 0x68, 0x69, 0x07, 0xfa,
+	0xa1, 0x4e, 0xde, 0xc2, 0x8a, 0x9a, 0x71, 0x01, 0xdd, 0x2c, 0xe8, 0xf7, 0xce, 0x21, 0x27, 0x77,
+	0x9a, 0xdf, 0x5e, 0x5f, 0xc6, 0x62, 0x72, 0x1d, 0x64, 0xcc, 0xd6, 0xe4, 0x36, 0x45, 0x36, 0xc5,
+	0xe8, 0x12, 0x59, 0x6b, 0xec, 0x07, 0x2c, 0x0e, 0x5b, 0xf2, 0x3b, 0xc0, 0x5b, 0x4a, 0x25, 0x30,
+	0x64, 0x7c, 0xf4, 0x77, 0x00, 0xda, 0x6c, 0x03, 0xd7, 0x49, 0x04, 0x00, 0x00,
 }
@@ -115,6 +115,33 @@ func (m *KafkaBrokers) String() string            { return proto.CompactTextStri
 func (*KafkaBrokers) ProtoMessage()               {}
 func (*KafkaBrokers) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{7} }
 
+// SbftOptions carries the parameters of the SBFT consensus protocol and the
+// set of replicas which order the chain
+type SbftOptions struct {
+	// The total number of replicas, which must match the number of peers
+	N uint64 `protobuf:"varint,1,opt,name=n" json:"n,omitempty"`
+	// The number of byzantine replicas tolerated, 3f+1 must not exceed n
+	F uint64 `protobuf:"varint,2,opt,name=f" json:"f,omitempty"`
+	// The time after which a replica suspects the primary of withholding a
+	// request, specified in nanoseconds
+	RequestTimeoutNsec uint64 `protobuf:"varint,3,opt,name=request_timeout_nsec" json:"request_timeout_nsec,omitempty"`
+	// Maps the replica-to-replica address of each replica to the DER encoding
+	// of its TLS certificate
+	Peers map[string][]byte `protobuf:"bytes,4,rep,name=peers" json:"peers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *SbftOptions) Reset()                    { *m = SbftOptions{} }
+func (m *SbftOptions) String() string            { return proto.CompactTextString(m) }
+func (*SbftOptions) ProtoMessage()               {}
+func (*SbftOptions) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{8} }
+
+func (m *SbftOptions) GetPeers() map[string][]byte {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ConsensusType)(nil), "orderer.ConsensusType")
 	proto.RegisterType((*BatchSize)(nil), "orderer.BatchSize")
@@ -124,32 +151,37 @@ func init() {
 	proto.RegisterType((*EgressPolicy)(nil), "orderer.EgressPolicy")
 	proto.RegisterType((*ChainCreators)(nil), "orderer.ChainCreators")
 	proto.RegisterType((*KafkaBrokers)(nil), "orderer.KafkaBrokers")
+	proto.RegisterType((*SbftOptions)(nil), "orderer.SbftOptions")
 }
 
 func init() { proto.RegisterFile("orderer/configuration.proto", fileDescriptor1) }
 
 var fileDescriptor1 = []byte{
-	// 349 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x84, 0x92, 0xcf, 0x8a, 0xdb, 0x30,
-	0x10, 0xc6, 0x71, 0x13, 0x92, 0x46, 0x24, 0xfd, 0xa3, 0x42, 0x31, 0xe9, 0x25, 0xb8, 0x17, 0xd3,
-	0x86, 0xf8, 0xd0, 0x17, 0x28, 0x36, 0x7b, 0x58, 0x96, 0xc0, 0xe2, 0xcd, 0x69, 0x6f, 0xb2, 0x3d,
-	0xb6, 0x45, 0x62, 0x8d, 0x19, 0xc9, 0x10, 0xef, 0x4b, 0xec, 0x2b, 0x2f, 0x96, 0x95, 0x1c, 0x36,
-	0x87, 0x3d, 0xf9, 0xfb, 0x66, 0x7e, 0x58, 0xdf, 0x8c, 0xc4, 0x7e, 0x21, 0x15, 0x40, 0x40, 0x51,
-	0x8e, 0xaa, 0x94, 0x55, 0x47, 0xc2, 0x48, 0x54, 0xbb, 0x96, 0xd0, 0x20, 0x9f, 0xbb, 0xe6, 0xfa,
-	0x47, 0x8e, 0x4d, 0x83, 0x2a, 0x1a, 0x3f, 0x63, 0x37, 0xf8, 0xcd, 0x56, 0x09, 0x2a, 0x0d, 0x4a,
-	0x77, 0xfa, 0xd0, 0xb7, 0xc0, 0x39, 0x9b, 0x9a, 0xbe, 0x05, 0xdf, 0xdb, 0x78, 0xe1, 0x22, 0xb5,
-	0x3a, 0x78, 0xf5, 0xd8, 0x22, 0x16, 0x26, 0xaf, 0x9f, 0xe4, 0x0b, 0xf0, 0x90, 0x7d, 0x6d, 0xc4,
-	0x79, 0x0f, 0x5a, 0x8b, 0x0a, 0x12, 0xec, 0x94, 0xb1, 0xf0, 0x2a, 0x7d, 0x5f, 0xe6, 0x7f, 0xd8,
-	0x37, 0x91, 0x69, 0x3c, 0x75, 0x06, 0xf6, 0xe2, 0x1c, 0xf7, 0x06, 0xb4, 0xff, 0xc9, 0xa2, 0x37,
-	0x75, 0xbe, 0x65, 0xdf, 0x5b, 0x82, 0x12, 0x88, 0xa0, 0xb8, 0xc2, 0x13, 0x0b, 0xdf, 0x36, 0x82,
-	0x90, 0x2d, 0x6d, 0xa0, 0x83, 0x6c, 0x00, 0x3b, 0xc3, 0x7d, 0x36, 0x37, 0xa3, 0x74, 0xc1, 0x2f,
-	0x36, 0xf8, 0xcf, 0xbe, 0x24, 0x04, 0x76, 0x21, 0x8f, 0x78, 0x92, 0x79, 0xcf, 0x7f, 0xb2, 0x59,
-	0x6b, 0x95, 0x43, 0x9d, 0x1b, 0xea, 0x85, 0xac, 0x40, 0x1b, 0x9b, 0x71, 0x99, 0x3a, 0x37, 0xac,
-	0xe8, 0x5e, 0x55, 0x04, 0x5a, 0xbb, 0x1f, 0x70, 0x36, 0x55, 0xa2, 0xb9, 0xae, 0x68, 0xd0, 0x41,
-	0xc0, 0x96, 0x77, 0x1f, 0x31, 0x7f, 0xd9, 0x2a, 0xa9, 0x85, 0x54, 0x36, 0x0f, 0x92, 0xe6, 0x6b,
-	0xf6, 0xd9, 0x9e, 0x2d, 0x41, 0xfb, 0xde, 0x66, 0x12, 0x2e, 0xd2, 0xab, 0x1f, 0x26, 0x7c, 0x10,
-	0xe5, 0x51, 0xc4, 0x84, 0x47, 0x20, 0x3d, 0x4c, 0x98, 0x8d, 0xd2, 0xa1, 0x17, 0x1b, 0xef, 0x9e,
-	0xb7, 0x95, 0x34, 0x75, 0x97, 0xed, 0x72, 0x6c, 0xa2, 0xba, 0x6f, 0x81, 0x4e, 0x50, 0x54, 0x40,
-	0x51, 0x29, 0x32, 0x92, 0x79, 0x64, 0x6f, 0x5a, 0x47, 0xee, 0x1d, 0x64, 0x33, 0xeb, 0xff, 0xbd,
-	0x05, 0x00, 0x00, 0xff, 0xff, 0x13, 0xbb, 0x3c, 0xc7, 0x36, 0x02, 0x00, 0x00,
+	// 411 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x52, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0x95, 0x1b, 0xb7, 0x25, 0x13, 0xa7, 0x2d, 0x0b, 0x12, 0xa1, 0x14, 0x35, 0xe4, 0x94, 0x43,
+	0x65, 0x23, 0xb8, 0x20, 0x8e, 0x89, 0x7a, 0x40, 0xa8, 0xa2, 0x52, 0x7b, 0x81, 0x4b, 0xb5, 0x76,
+	0xc6, 0xce, 0x2a, 0xf6, 0xae, 0x99, 0xd9, 0x45, 0x35, 0x7f, 0x86, 0xbf, 0x8a, 0x76, 0xed, 0x00,
+	0x17, 0x4e, 0x9e, 0xd1, 0x7b, 0x7e, 0x1f, 0x63, 0xc3, 0x2b, 0x43, 0x1b, 0x24, 0xa4, 0xac, 0x30,
+	0xba, 0x54, 0x95, 0x23, 0x69, 0x95, 0xd1, 0x69, 0x4b, 0xc6, 0x1a, 0x71, 0x3c, 0x80, 0xe7, 0xcf,
+	0x0a, 0xd3, 0x34, 0x46, 0x67, 0xfd, 0xa3, 0x47, 0x17, 0xaf, 0x61, 0xba, 0x36, 0x9a, 0x51, 0xb3,
+	0xe3, 0xfb, 0xae, 0x45, 0x91, 0x40, 0x6c, 0xbb, 0x16, 0x67, 0xd1, 0x3c, 0x5a, 0x8e, 0x17, 0x5f,
+	0x61, 0xbc, 0x92, 0xb6, 0xd8, 0xde, 0xa9, 0x9f, 0x28, 0x5e, 0xc0, 0x69, 0x23, 0x1f, 0x6f, 0x90,
+	0x59, 0x56, 0xb8, 0x36, 0x4e, 0xdb, 0xc0, 0x9a, 0x8a, 0x19, 0x9c, 0xc9, 0x9c, 0x4d, 0xed, 0x2c,
+	0xde, 0xc8, 0xc7, 0x55, 0x67, 0x91, 0x67, 0x07, 0x01, 0x79, 0x09, 0x4f, 0x5b, 0xc2, 0x12, 0x89,
+	0x70, 0xf3, 0x07, 0x1a, 0x79, 0x68, 0x71, 0x09, 0x49, 0x90, 0xbe, 0x57, 0x0d, 0x1a, 0x67, 0xc5,
+	0x29, 0x1c, 0xdb, 0x7e, 0x1c, 0xbc, 0xdf, 0xc2, 0xc9, 0x9a, 0x30, 0x54, 0xb9, 0x35, 0xb5, 0x2a,
+	0x3a, 0x71, 0x02, 0x47, 0x6d, 0x98, 0x7a, 0x86, 0xdf, 0x37, 0xaa, 0x42, 0xb6, 0xc1, 0x2d, 0xf1,
+	0x65, 0x3e, 0xe9, 0x8a, 0x90, 0x79, 0x78, 0x21, 0x81, 0x58, 0xcb, 0x66, 0x5f, 0xe6, 0x02, 0x92,
+	0xeb, 0xff, 0xa3, 0x6f, 0x60, 0xba, 0xde, 0x4a, 0xa5, 0x83, 0xa7, 0x21, 0x16, 0x67, 0xf0, 0x24,
+	0xb8, 0x29, 0xe4, 0x59, 0x34, 0x1f, 0x2d, 0xc7, 0x3e, 0xf2, 0x67, 0x59, 0xee, 0xe4, 0x8a, 0xcc,
+	0x0e, 0x89, 0x7d, 0xe4, 0xbc, 0x1f, 0x07, 0xc2, 0xaf, 0x08, 0x26, 0x77, 0x79, 0x69, 0xbf, 0xb4,
+	0x3e, 0x35, 0x8b, 0x31, 0x44, 0x3a, 0xc8, 0xc7, 0x7e, 0x2c, 0x43, 0xcc, 0x58, 0x5c, 0xc0, 0x73,
+	0xc2, 0xef, 0x0e, 0xd9, 0x3e, 0x0c, 0x8d, 0x1f, 0x34, 0x63, 0x11, 0xee, 0x12, 0x8b, 0x14, 0x0e,
+	0x5b, 0xf4, 0x92, 0xf1, 0x7c, 0xb4, 0x9c, 0xbc, 0xbb, 0x4c, 0x87, 0xef, 0x97, 0xfe, 0x23, 0x9c,
+	0xde, 0x7a, 0xc6, 0xb5, 0xb6, 0xd4, 0x9d, 0x5f, 0x01, 0xfc, 0xdd, 0xc4, 0x04, 0x46, 0x3b, 0xdc,
+	0xdf, 0x67, 0x0a, 0x87, 0x3f, 0x64, 0xed, 0xb0, 0x3f, 0xcf, 0xc7, 0x83, 0x0f, 0xd1, 0x2a, 0xfd,
+	0x76, 0x55, 0x29, 0xbb, 0x75, 0x79, 0x5a, 0x98, 0x26, 0xdb, 0x76, 0x2d, 0x52, 0x8d, 0x9b, 0x0a,
+	0x29, 0x2b, 0x65, 0x4e, 0xaa, 0xc8, 0xc2, 0x6f, 0xc1, 0xd9, 0x60, 0x9a, 0x1f, 0x85, 0xfd, 0xfd,
+	0xef, 0x01, 0x00, 0x6a, 0xee, 0xce, 0x12, 0x63, 0x02, 0x00, 0x00,
 }
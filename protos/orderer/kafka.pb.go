@@ -174,6 +174,10 @@ func (m *KafkaMessageRegular) String() string            { return proto.CompactT
 func (*KafkaMessageRegular) ProtoMessage()               {}
 func (*KafkaMessageRegular) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{1} }
 
+// KafkaMessageTimeToCut is posted to the partition by an orderer whose batch
+// timer expired. Every orderer cuts the pending batch into the block with the
+// given number at the first such message it consumes, and ignores any later
+// ones, so that the blocks only depend on the contents of the partition.
 type KafkaMessageTimeToCut struct {
 	BlockNumber uint64 `protobuf:"varint,1,opt,name=block_number,json=blockNumber" json:"block_number,omitempty"`
 }
@@ -192,32 +196,46 @@ func (m *KafkaMessageConnect) String() string            { return proto.CompactT
 func (*KafkaMessageConnect) ProtoMessage()               {}
 func (*KafkaMessageConnect) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{3} }
 
+// KafkaMetadata is encoded into the ORDERER block metadata of the blocks
+// written by the Kafka consenter, to allow it to resume after a restart
+type KafkaMetadata struct {
+	// The offset of the last Kafka message that went into the block
+	LastOffsetPersisted int64 `protobuf:"varint,1,opt,name=last_offset_persisted" json:"last_offset_persisted,omitempty"`
+}
+
+func (m *KafkaMetadata) Reset()                    { *m = KafkaMetadata{} }
+func (m *KafkaMetadata) String() string            { return proto.CompactTextString(m) }
+func (*KafkaMetadata) ProtoMessage()               {}
+func (*KafkaMetadata) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{4} }
+
 func init() {
 	proto.RegisterType((*KafkaMessage)(nil), "orderer.KafkaMessage")
 	proto.RegisterType((*KafkaMessageRegular)(nil), "orderer.KafkaMessageRegular")
 	proto.RegisterType((*KafkaMessageTimeToCut)(nil), "orderer.KafkaMessageTimeToCut")
 	proto.RegisterType((*KafkaMessageConnect)(nil), "orderer.KafkaMessageConnect")
+	proto.RegisterType((*KafkaMetadata)(nil), "orderer.KafkaMetadata")
 }
 
 func init() { proto.RegisterFile("orderer/kafka.proto", fileDescriptor2) }
 
 var fileDescriptor2 = []byte{
-	// 266 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x7c, 0x91, 0x3f, 0x4b, 0xc4, 0x30,
-	0x18, 0xc6, 0xaf, 0x7a, 0x5c, 0x31, 0xed, 0xd4, 0x43, 0xe8, 0x20, 0xa2, 0x9d, 0x1c, 0x24, 0x01,
-	0x5d, 0xc4, 0x49, 0xee, 0x96, 0x03, 0xd1, 0x21, 0x74, 0x72, 0x29, 0x49, 0xfa, 0x5e, 0xaf, 0xf4,
-	0x4f, 0x4a, 0x9a, 0x0c, 0xfd, 0x8e, 0x7e, 0x28, 0x69, 0x9a, 0x82, 0x48, 0xbd, 0xf1, 0x79, 0xf2,
-	0x7b, 0x79, 0x9e, 0x37, 0x2f, 0xda, 0x4a, 0x95, 0x83, 0x02, 0x45, 0x2a, 0x76, 0xac, 0x18, 0xee,
-	0x94, 0xd4, 0x32, 0xf2, 0x9d, 0x99, 0x7c, 0x7b, 0x28, 0x7c, 0x1f, 0x1f, 0x3e, 0xa0, 0xef, 0x59,
-	0x01, 0xd1, 0x0b, 0xf2, 0x15, 0x14, 0xa6, 0x66, 0x2a, 0xf6, 0xee, 0xbc, 0x87, 0xe0, 0xe9, 0x06,
-	0x3b, 0x16, 0xff, 0xe6, 0xe8, 0xc4, 0x1c, 0x56, 0x74, 0xc6, 0xa3, 0x37, 0x14, 0xe8, 0xb2, 0x81,
-	0x4c, 0xcb, 0x4c, 0x18, 0x1d, 0x5f, 0xd8, 0xe9, 0xdb, 0xc5, 0xe9, 0xb4, 0x6c, 0x20, 0x95, 0x7b,
-	0xa3, 0x0f, 0x2b, 0x7a, 0xa5, 0x67, 0x31, 0x66, 0x0b, 0xd9, 0xb6, 0x20, 0x74, 0x7c, 0x79, 0x26,
-	0x7b, 0x3f, 0x31, 0x63, 0xb6, 0xc3, 0x77, 0x1b, 0xb4, 0x4e, 0x87, 0x0e, 0x12, 0x82, 0xb6, 0x0b,
-	0x2d, 0xa3, 0x18, 0xf9, 0x1d, 0x1b, 0x6a, 0xc9, 0x72, 0xbb, 0x54, 0x48, 0x67, 0x99, 0xbc, 0xa2,
-	0xeb, 0xc5, 0x62, 0xd1, 0x3d, 0x0a, 0x79, 0x2d, 0x45, 0x95, 0xb5, 0xa6, 0xe1, 0x30, 0x7d, 0xc6,
-	0x9a, 0x06, 0xd6, 0xfb, 0xb4, 0xd6, 0xdf, 0x30, 0x57, 0xeb, 0xff, 0xb0, 0x1d, 0xfe, 0x7a, 0x2c,
-	0x4a, 0x7d, 0x32, 0x1c, 0x0b, 0xd9, 0x90, 0xd3, 0xd0, 0x81, 0xaa, 0x21, 0x2f, 0x40, 0x91, 0x23,
-	0xe3, 0xaa, 0x14, 0xc4, 0x1e, 0xa7, 0x27, 0x6e, 0x69, 0xbe, 0xb1, 0xfa, 0xf9, 0x27, 0x00, 0x00,
-	0xff, 0xff, 0x9c, 0x1e, 0x0b, 0x94, 0xc3, 0x01, 0x00, 0x00,
+	// 274 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x91, 0xcd, 0x4a, 0xf4, 0x30,
+	0x14, 0x86, 0xdb, 0x6f, 0x86, 0x29, 0x64, 0xfa, 0x21, 0x64, 0x1c, 0xe8, 0x42, 0x45, 0xba, 0x10,
+	0x17, 0x9a, 0x80, 0xe2, 0x0d, 0xcc, 0x6c, 0x0a, 0xe2, 0x46, 0xba, 0x72, 0x53, 0x4e, 0xd3, 0xd3,
+	0x4e, 0xe9, 0x4f, 0x4a, 0x72, 0xba, 0xe8, 0x55, 0x79, 0x8b, 0x32, 0x6d, 0x17, 0xa2, 0xc5, 0x65,
+	0x92, 0xe7, 0x9c, 0xe7, 0x7d, 0x09, 0xdb, 0x69, 0x93, 0xa1, 0x41, 0x23, 0x2b, 0xc8, 0x2b, 0x10,
+	0x9d, 0xd1, 0xa4, 0xb9, 0x37, 0x5f, 0x86, 0x9f, 0x2e, 0xf3, 0x5f, 0xcf, 0x0f, 0x6f, 0x68, 0x2d,
+	0x14, 0xc8, 0x25, 0xf3, 0x0c, 0x16, 0x7d, 0x0d, 0x26, 0x70, 0x6f, 0xdd, 0xfb, 0xed, 0xd3, 0x95,
+	0x98, 0x59, 0xf1, 0x9d, 0x7b, 0x9f, 0x98, 0xc8, 0xe1, 0x2f, 0x6c, 0x4b, 0x65, 0x83, 0x09, 0xe9,
+	0x44, 0xf5, 0x14, 0xfc, 0x1b, 0x87, 0x6e, 0x16, 0x87, 0xe2, 0xb2, 0xc1, 0x58, 0x1f, 0x7b, 0x8a,
+	0x9c, 0xb3, 0x47, 0xe9, 0xb6, 0x45, 0x45, 0xc1, 0xea, 0x0f, 0xcf, 0x71, 0x62, 0x22, 0xe7, 0xb0,
+	0x61, 0xeb, 0x78, 0xe8, 0x30, 0xbc, 0x63, 0xbb, 0x85, 0x20, 0xfc, 0x82, 0x79, 0x1d, 0x0c, 0xb5,
+	0x86, 0x6c, 0xcc, 0xed, 0x87, 0x8f, 0x6c, 0xbf, 0xe8, 0xe6, 0x97, 0xcc, 0x4f, 0x6b, 0xad, 0xaa,
+	0xa4, 0xed, 0x9b, 0x14, 0xa7, 0x9a, 0xeb, 0x9f, 0x6b, 0x67, 0xef, 0xef, 0xb5, 0x82, 0xfd, 0x9f,
+	0x39, 0x82, 0x0c, 0x08, 0xf8, 0x35, 0xdb, 0xd7, 0x60, 0x29, 0xd1, 0x79, 0x6e, 0x91, 0x92, 0x0e,
+	0x8d, 0x2d, 0x2d, 0xe1, 0xc4, 0xaf, 0x0e, 0xe2, 0xe3, 0xa1, 0x28, 0xe9, 0xd4, 0xa7, 0x42, 0xe9,
+	0x46, 0x9e, 0x86, 0x0e, 0x4d, 0x8d, 0x59, 0x81, 0x46, 0xe6, 0x90, 0x9a, 0x52, 0xc9, 0xf1, 0x43,
+	0xac, 0x9c, 0xcb, 0xa7, 0x9b, 0xf1, 0xfc, 0xfc, 0x35, 0x00, 0x2a, 0x0f, 0x1c, 0x33, 0xb7, 0x01,
+	0x00, 0x00,
 }
@@ -97,23 +97,26 @@ func (ChaincodeDeploymentSpec_ExecutionEnvironment) EnumDescriptor() ([]byte, []
 type ChaincodeMessage_Type int32
 
 const (
-	ChaincodeMessage_UNDEFINED               ChaincodeMessage_Type = 0
-	ChaincodeMessage_REGISTER                ChaincodeMessage_Type = 1
-	ChaincodeMessage_REGISTERED              ChaincodeMessage_Type = 2
-	ChaincodeMessage_INIT                    ChaincodeMessage_Type = 3
-	ChaincodeMessage_READY                   ChaincodeMessage_Type = 4
-	ChaincodeMessage_TRANSACTION             ChaincodeMessage_Type = 5
-	ChaincodeMessage_COMPLETED               ChaincodeMessage_Type = 6
-	ChaincodeMessage_ERROR                   ChaincodeMessage_Type = 7
-	ChaincodeMessage_GET_STATE               ChaincodeMessage_Type = 8
-	ChaincodeMessage_PUT_STATE               ChaincodeMessage_Type = 9
-	ChaincodeMessage_DEL_STATE               ChaincodeMessage_Type = 10
-	ChaincodeMessage_INVOKE_CHAINCODE        ChaincodeMessage_Type = 11
-	ChaincodeMessage_RESPONSE                ChaincodeMessage_Type = 13
-	ChaincodeMessage_RANGE_QUERY_STATE       ChaincodeMessage_Type = 14
-	ChaincodeMessage_RANGE_QUERY_STATE_NEXT  ChaincodeMessage_Type = 15
-	ChaincodeMessage_RANGE_QUERY_STATE_CLOSE ChaincodeMessage_Type = 16
-	ChaincodeMessage_KEEPALIVE               ChaincodeMessage_Type = 17
+	ChaincodeMessage_UNDEFINED                ChaincodeMessage_Type = 0
+	ChaincodeMessage_REGISTER                 ChaincodeMessage_Type = 1
+	ChaincodeMessage_REGISTERED               ChaincodeMessage_Type = 2
+	ChaincodeMessage_INIT                     ChaincodeMessage_Type = 3
+	ChaincodeMessage_READY                    ChaincodeMessage_Type = 4
+	ChaincodeMessage_TRANSACTION              ChaincodeMessage_Type = 5
+	ChaincodeMessage_COMPLETED                ChaincodeMessage_Type = 6
+	ChaincodeMessage_ERROR                    ChaincodeMessage_Type = 7
+	ChaincodeMessage_GET_STATE                ChaincodeMessage_Type = 8
+	ChaincodeMessage_PUT_STATE                ChaincodeMessage_Type = 9
+	ChaincodeMessage_DEL_STATE                ChaincodeMessage_Type = 10
+	ChaincodeMessage_INVOKE_CHAINCODE         ChaincodeMessage_Type = 11
+	ChaincodeMessage_RESPONSE                 ChaincodeMessage_Type = 13
+	ChaincodeMessage_RANGE_QUERY_STATE        ChaincodeMessage_Type = 14
+	ChaincodeMessage_RANGE_QUERY_STATE_NEXT   ChaincodeMessage_Type = 15
+	ChaincodeMessage_RANGE_QUERY_STATE_CLOSE  ChaincodeMessage_Type = 16
+	ChaincodeMessage_KEEPALIVE                ChaincodeMessage_Type = 17
+	ChaincodeMessage_GET_QUERY_RESULT         ChaincodeMessage_Type = 18
+	ChaincodeMessage_GET_HISTORY_FOR_KEY      ChaincodeMessage_Type = 19
+	ChaincodeMessage_GET_HISTORY_FOR_KEY_NEXT ChaincodeMessage_Type = 20
 )
 
 var ChaincodeMessage_Type_name = map[int32]string{
@@ -134,25 +137,31 @@ var ChaincodeMessage_Type_name = map[int32]string{
 	15: "RANGE_QUERY_STATE_NEXT",
 	16: "RANGE_QUERY_STATE_CLOSE",
 	17: "KEEPALIVE",
+	18: "GET_QUERY_RESULT",
+	19: "GET_HISTORY_FOR_KEY",
+	20: "GET_HISTORY_FOR_KEY_NEXT",
 }
 var ChaincodeMessage_Type_value = map[string]int32{
-	"UNDEFINED":               0,
-	"REGISTER":                1,
-	"REGISTERED":              2,
-	"INIT":                    3,
-	"READY":                   4,
-	"TRANSACTION":             5,
-	"COMPLETED":               6,
-	"ERROR":                   7,
-	"GET_STATE":               8,
-	"PUT_STATE":               9,
-	"DEL_STATE":               10,
-	"INVOKE_CHAINCODE":        11,
-	"RESPONSE":                13,
-	"RANGE_QUERY_STATE":       14,
-	"RANGE_QUERY_STATE_NEXT":  15,
-	"RANGE_QUERY_STATE_CLOSE": 16,
-	"KEEPALIVE":               17,
+	"UNDEFINED":                0,
+	"REGISTER":                 1,
+	"REGISTERED":               2,
+	"INIT":                     3,
+	"READY":                    4,
+	"TRANSACTION":              5,
+	"COMPLETED":                6,
+	"ERROR":                    7,
+	"GET_STATE":                8,
+	"PUT_STATE":                9,
+	"DEL_STATE":                10,
+	"INVOKE_CHAINCODE":         11,
+	"RESPONSE":                 13,
+	"RANGE_QUERY_STATE":        14,
+	"RANGE_QUERY_STATE_NEXT":   15,
+	"RANGE_QUERY_STATE_CLOSE":  16,
+	"KEEPALIVE":                17,
+	"GET_QUERY_RESULT":         18,
+	"GET_HISTORY_FOR_KEY":      19,
+	"GET_HISTORY_FOR_KEY_NEXT": 20,
 }
 
 func (x ChaincodeMessage_Type) String() string {
@@ -343,9 +352,14 @@ func (m *PutStateInfo) String() string            { return proto.CompactTextStri
 func (*PutStateInfo) ProtoMessage()               {}
 func (*PutStateInfo) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{7} }
 
+// RangeQueryState is the payload of RANGE_QUERY_STATE. When pageSize is set, at most
+// pageSize results are returned, all in the first RangeQueryStateResponse, starting
+// from the bookmark returned with the previous page, if any
 type RangeQueryState struct {
 	StartKey string `protobuf:"bytes,1,opt,name=startKey" json:"startKey,omitempty"`
 	EndKey   string `protobuf:"bytes,2,opt,name=endKey" json:"endKey,omitempty"`
+	PageSize int32  `protobuf:"varint,3,opt,name=pageSize" json:"pageSize,omitempty"`
+	Bookmark string `protobuf:"bytes,4,opt,name=bookmark" json:"bookmark,omitempty"`
 }
 
 func (m *RangeQueryState) Reset()                    { *m = RangeQueryState{} }
@@ -381,10 +395,13 @@ func (m *RangeQueryStateKeyValue) String() string            { return proto.Comp
 func (*RangeQueryStateKeyValue) ProtoMessage()               {}
 func (*RangeQueryStateKeyValue) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{11} }
 
+// RangeQueryStateResponse holds a batch of results. For a paginated query, bookmark
+// is to be used for getting the next page, and is empty when there are no more results
 type RangeQueryStateResponse struct {
 	KeysAndValues []*RangeQueryStateKeyValue `protobuf:"bytes,1,rep,name=keysAndValues" json:"keysAndValues,omitempty"`
 	HasMore       bool                       `protobuf:"varint,2,opt,name=hasMore" json:"hasMore,omitempty"`
 	ID            string                     `protobuf:"bytes,3,opt,name=ID" json:"ID,omitempty"`
+	Bookmark      string                     `protobuf:"bytes,4,opt,name=bookmark" json:"bookmark,omitempty"`
 }
 
 func (m *RangeQueryStateResponse) Reset()                    { *m = RangeQueryStateResponse{} }
@@ -399,6 +416,70 @@ func (m *RangeQueryStateResponse) GetKeysAndValues() []*RangeQueryStateKeyValue
 	return nil
 }
 
+// GetQueryResult is the payload of GET_QUERY_RESULT. The results are returned in
+// a RangeQueryStateResponse, the key and the document of each result being returned
+// as a RangeQueryStateKeyValue. RANGE_QUERY_STATE_NEXT and RANGE_QUERY_STATE_CLOSE
+// are used for the subsequent results and for closing the iterator. pageSize and bookmark
+// are used for paginated queries, as for RangeQueryState
+type GetQueryResult struct {
+	Query    string `protobuf:"bytes,1,opt,name=query" json:"query,omitempty"`
+	PageSize int32  `protobuf:"varint,2,opt,name=pageSize" json:"pageSize,omitempty"`
+	Bookmark string `protobuf:"bytes,3,opt,name=bookmark" json:"bookmark,omitempty"`
+}
+
+func (m *GetQueryResult) Reset()                    { *m = GetQueryResult{} }
+func (m *GetQueryResult) String() string            { return proto.CompactTextString(m) }
+func (*GetQueryResult) ProtoMessage()               {}
+func (*GetQueryResult) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{13} }
+
+// GetHistoryForKey is the payload of GET_HISTORY_FOR_KEY. GET_HISTORY_FOR_KEY_NEXT is
+// used for the subsequent results and RANGE_QUERY_STATE_CLOSE for closing the iterator
+type GetHistoryForKey struct {
+	Key string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+}
+
+func (m *GetHistoryForKey) Reset()                    { *m = GetHistoryForKey{} }
+func (m *GetHistoryForKey) String() string            { return proto.CompactTextString(m) }
+func (*GetHistoryForKey) ProtoMessage()               {}
+func (*GetHistoryForKey) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{14} }
+
+type GetHistoryForKeyNext struct {
+	ID string `protobuf:"bytes,1,opt,name=ID" json:"ID,omitempty"`
+}
+
+func (m *GetHistoryForKeyNext) Reset()                    { *m = GetHistoryForKeyNext{} }
+func (m *GetHistoryForKeyNext) String() string            { return proto.CompactTextString(m) }
+func (*GetHistoryForKeyNext) ProtoMessage()               {}
+func (*GetHistoryForKeyNext) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{15} }
+
+type KeyModification struct {
+	TxID  string `protobuf:"bytes,1,opt,name=txID" json:"txID,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *KeyModification) Reset()                    { *m = KeyModification{} }
+func (m *KeyModification) String() string            { return proto.CompactTextString(m) }
+func (*KeyModification) ProtoMessage()               {}
+func (*KeyModification) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{16} }
+
+type GetHistoryForKeyResponse struct {
+	KeyModifications []*KeyModification `protobuf:"bytes,1,rep,name=keyModifications" json:"keyModifications,omitempty"`
+	HasMore          bool               `protobuf:"varint,2,opt,name=hasMore" json:"hasMore,omitempty"`
+	ID               string             `protobuf:"bytes,3,opt,name=ID" json:"ID,omitempty"`
+}
+
+func (m *GetHistoryForKeyResponse) Reset()                    { *m = GetHistoryForKeyResponse{} }
+func (m *GetHistoryForKeyResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetHistoryForKeyResponse) ProtoMessage()               {}
+func (*GetHistoryForKeyResponse) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{17} }
+
+func (m *GetHistoryForKeyResponse) GetKeyModifications() []*KeyModification {
+	if m != nil {
+		return m.KeyModifications
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ChaincodeID)(nil), "protos.ChaincodeID")
 	proto.RegisterType((*ChaincodeInput)(nil), "protos.ChaincodeInput")
@@ -413,6 +494,11 @@ func init() {
 	proto.RegisterType((*RangeQueryStateClose)(nil), "protos.RangeQueryStateClose")
 	proto.RegisterType((*RangeQueryStateKeyValue)(nil), "protos.RangeQueryStateKeyValue")
 	proto.RegisterType((*RangeQueryStateResponse)(nil), "protos.RangeQueryStateResponse")
+	proto.RegisterType((*GetQueryResult)(nil), "protos.GetQueryResult")
+	proto.RegisterType((*GetHistoryForKey)(nil), "protos.GetHistoryForKey")
+	proto.RegisterType((*GetHistoryForKeyNext)(nil), "protos.GetHistoryForKeyNext")
+	proto.RegisterType((*KeyModification)(nil), "protos.KeyModification")
+	proto.RegisterType((*GetHistoryForKeyResponse)(nil), "protos.GetHistoryForKeyResponse")
 	proto.RegisterEnum("protos.ConfidentialityLevel", ConfidentialityLevel_name, ConfidentialityLevel_value)
 	proto.RegisterEnum("protos.ChaincodeSpec_Type", ChaincodeSpec_Type_name, ChaincodeSpec_Type_value)
 	proto.RegisterEnum("protos.ChaincodeDeploymentSpec_ExecutionEnvironment", ChaincodeDeploymentSpec_ExecutionEnvironment_name, ChaincodeDeploymentSpec_ExecutionEnvironment_value)
@@ -526,75 +612,80 @@ var _ChaincodeSupport_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("peer/chaincode.proto", fileDescriptor2) }
 
 var fileDescriptor2 = []byte{
-	// 1111 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xa4, 0x56, 0xcb, 0x6e, 0xdb, 0x46,
-	0x14, 0x8d, 0x1e, 0xb6, 0xac, 0xab, 0x87, 0x99, 0x89, 0xe2, 0xa8, 0x6a, 0xda, 0x18, 0x44, 0x5b,
-	0xb8, 0x5d, 0x48, 0xa9, 0x9a, 0x16, 0x05, 0x0a, 0x04, 0x65, 0xc8, 0x89, 0xca, 0x58, 0xa6, 0x94,
-	0x11, 0x6d, 0xc4, 0xdd, 0x18, 0x63, 0x6a, 0x24, 0x13, 0x91, 0x48, 0x82, 0x1c, 0x19, 0xd6, 0xae,
-	0xeb, 0xae, 0xfa, 0x0f, 0xfd, 0x8b, 0xfe, 0x4c, 0x7f, 0xa5, 0x33, 0xa4, 0x28, 0xeb, 0x65, 0x20,
-	0x40, 0x57, 0x9a, 0x3b, 0xf7, 0x9c, 0x3b, 0xf7, 0x4d, 0x41, 0x2d, 0x60, 0x2c, 0x6c, 0x39, 0x37,
-	0xd4, 0xf5, 0x1c, 0x7f, 0xc8, 0x9a, 0x41, 0xe8, 0x73, 0x1f, 0xed, 0xc7, 0x3f, 0x51, 0xe3, 0xb3,
-	0x75, 0x2d, 0xbb, 0x65, 0x1e, 0x4f, 0x20, 0x8d, 0x17, 0x63, 0xdf, 0x1f, 0x4f, 0x58, 0x2b, 0x96,
-	0xae, 0x67, 0xa3, 0x16, 0x77, 0xa7, 0x2c, 0xe2, 0x74, 0x1a, 0x24, 0x00, 0xf5, 0x47, 0x28, 0xe9,
-	0x29, 0xd1, 0x34, 0x10, 0x82, 0x7c, 0x40, 0xf9, 0x4d, 0x3d, 0x73, 0x9c, 0x39, 0x29, 0x92, 0xf8,
-	0x2c, 0xef, 0x3c, 0x3a, 0x65, 0xf5, 0x6c, 0x72, 0x27, 0xcf, 0xea, 0x57, 0x50, 0xbd, 0xa7, 0x79,
-	0xc1, 0x8c, 0x4b, 0x14, 0x0d, 0xc7, 0x91, 0x60, 0xe6, 0x4e, 0xca, 0x24, 0x3e, 0xab, 0x7f, 0xe5,
-	0xa0, 0xb2, 0x84, 0x0d, 0x02, 0xe6, 0xa0, 0x26, 0xe4, 0xf9, 0x3c, 0x60, 0xb1, 0xfd, 0x6a, 0xbb,
-	0x91, 0x38, 0x11, 0x35, 0xd7, 0x40, 0x4d, 0x5b, 0x20, 0x48, 0x8c, 0x43, 0xc2, 0x3d, 0xe7, 0xde,
-	0xbd, 0xd8, 0x85, 0x52, 0xfb, 0xc9, 0x16, 0xcd, 0x34, 0xc8, 0x2a, 0x0e, 0xbd, 0x84, 0x82, 0xc3,
-	0xfd, 0xf0, 0x2c, 0x1a, 0xd7, 0x73, 0x31, 0xe5, 0x68, 0x9b, 0x22, 0xbd, 0x26, 0x29, 0x0c, 0xd5,
-	0xa1, 0x20, 0x53, 0xe3, 0xcf, 0x78, 0x3d, 0x2f, 0x18, 0x7b, 0x24, 0x15, 0x51, 0x1f, 0x6a, 0x8e,
-	0xef, 0x8d, 0xdc, 0xa1, 0xc8, 0xaa, 0x4b, 0x27, 0x2e, 0x9f, 0x77, 0x45, 0x86, 0x27, 0xf5, 0xbd,
-	0x38, 0x84, 0xe7, 0x4b, 0xc3, 0x3b, 0x30, 0x64, 0x27, 0x13, 0x35, 0xe0, 0x60, 0xca, 0x38, 0x1d,
-	0x52, 0x4e, 0xeb, 0xfb, 0xc2, 0x4a, 0x99, 0x2c, 0x65, 0xf4, 0x25, 0x00, 0xe5, 0x3c, 0x74, 0xaf,
-	0x67, 0x9c, 0x45, 0xf5, 0x82, 0x48, 0x66, 0x91, 0xac, 0xdc, 0xa8, 0xaf, 0x21, 0x2f, 0xd3, 0x83,
-	0x2a, 0x50, 0x3c, 0xb7, 0x0c, 0xfc, 0xd6, 0xb4, 0xb0, 0xa1, 0x3c, 0x42, 0x00, 0xfb, 0x9d, 0x5e,
-	0x57, 0xb3, 0x3a, 0x4a, 0x06, 0x1d, 0x40, 0xde, 0xea, 0x19, 0x58, 0xc9, 0xa2, 0x02, 0xe4, 0x74,
-	0x8d, 0x28, 0x39, 0x79, 0xf5, 0x4e, 0xbb, 0xd0, 0x94, 0xbc, 0xfa, 0x4f, 0x16, 0x9e, 0x2d, 0x73,
-	0x60, 0xb0, 0x60, 0xe2, 0xcf, 0xa7, 0xc2, 0xbd, 0xb8, 0x38, 0xbf, 0x40, 0xc5, 0x59, 0x2d, 0x44,
-	0x5c, 0xa5, 0x52, 0xfb, 0xe9, 0xce, 0x2a, 0x91, 0x75, 0x2c, 0xfa, 0x15, 0x2a, 0x6c, 0x34, 0x62,
-	0x0e, 0x77, 0x6f, 0x99, 0x41, 0x39, 0x5b, 0xd4, 0xaa, 0xd1, 0x4c, 0x3a, 0xb0, 0x99, 0x76, 0x60,
-	0xd3, 0x4e, 0x3b, 0x90, 0xac, 0x13, 0xd0, 0xb1, 0xa8, 0xb5, 0xb0, 0xd6, 0xa7, 0xce, 0x47, 0x3a,
-	0x66, 0x71, 0xe1, 0xca, 0x64, 0xf5, 0x0a, 0x59, 0x50, 0x60, 0x77, 0xcc, 0xc1, 0xde, 0x6d, 0x5c,
-	0xa4, 0x6a, 0xfb, 0xd5, 0x96, 0x6b, 0xeb, 0x21, 0x35, 0xb1, 0x80, 0xcf, 0xb8, 0xeb, 0x7b, 0x82,
-	0xe3, 0x86, 0xbe, 0x27, 0x15, 0x24, 0x35, 0xa2, 0x36, 0xa1, 0xb6, 0x0b, 0x20, 0xb3, 0x69, 0xf4,
-	0xf4, 0x53, 0x4c, 0x92, 0xcc, 0x0e, 0x2e, 0x07, 0x36, 0x3e, 0x53, 0x32, 0xea, 0x1f, 0x99, 0x95,
-	0xe4, 0x99, 0xde, 0xad, 0xef, 0x50, 0x49, 0xfd, 0xff, 0xc9, 0x3b, 0x81, 0x43, 0x77, 0xd8, 0x61,
-	0x1e, 0x0b, 0x63, 0x83, 0xda, 0x64, 0xbc, 0x98, 0xb6, 0xcd, 0x6b, 0x95, 0x40, 0x7d, 0x69, 0xa9,
-	0x1f, 0xfa, 0x81, 0x1f, 0xd1, 0x89, 0x68, 0x3d, 0xce, 0xee, 0xb8, 0xec, 0x61, 0x27, 0x64, 0x54,
-	0x74, 0x74, 0xfc, 0x78, 0x99, 0xa4, 0x22, 0x7a, 0x0e, 0x45, 0x1e, 0x52, 0x2f, 0x72, 0x45, 0x74,
-	0xb1, 0xe5, 0x32, 0xb9, 0xbf, 0x50, 0xff, 0xcd, 0x83, 0xb2, 0x34, 0x7a, 0xc6, 0xa2, 0x48, 0xe6,
-	0xfa, 0xfb, 0xb5, 0x49, 0xfd, 0x62, 0x2b, 0x8c, 0x05, 0x6e, 0x75, 0x58, 0x7f, 0x16, 0xaf, 0xa4,
-	0xc5, 0xfd, 0x84, 0xf2, 0xdf, 0x83, 0xa5, 0xe7, 0x01, 0x9d, 0x4f, 0x7c, 0x3a, 0x5c, 0x94, 0x3d,
-	0x15, 0xe5, 0x5a, 0xe1, 0x77, 0xee, 0x30, 0xae, 0xb7, 0x58, 0x3e, 0xf2, 0x8c, 0xde, 0xc1, 0x61,
-	0xb0, 0x1e, 0x7a, 0x3c, 0x8c, 0xa5, 0xf6, 0xf1, 0x96, 0x97, 0x1b, 0x29, 0x22, 0x9b, 0x44, 0xf4,
-	0x1a, 0xaa, 0xcb, 0x52, 0x60, 0xb9, 0x38, 0xe3, 0x89, 0xdc, 0xb5, 0x30, 0x62, 0x2d, 0xd9, 0x40,
-	0xab, 0x7f, 0x67, 0x77, 0x0f, 0x64, 0x19, 0x0e, 0x08, 0xee, 0x98, 0xa2, 0x71, 0x88, 0x18, 0xc9,
-	0x2a, 0x40, 0x2a, 0x09, 0x6d, 0x56, 0xce, 0xa3, 0x69, 0x99, 0xb6, 0x98, 0xcc, 0x22, 0xec, 0x11,
-	0xac, 0x19, 0x97, 0x4a, 0x1e, 0x1d, 0x42, 0xc9, 0x26, 0x9a, 0x35, 0xd0, 0x74, 0xdb, 0xec, 0x59,
-	0xca, 0x9e, 0x34, 0xa9, 0xf7, 0xce, 0xfa, 0x5d, 0x6c, 0x0b, 0xd2, 0xbe, 0x84, 0x62, 0x42, 0x7a,
-	0x44, 0x29, 0x48, 0x4d, 0x07, 0xdb, 0x57, 0x03, 0x5b, 0xb3, 0xb1, 0x72, 0x20, 0xc5, 0xfe, 0x79,
-	0x2a, 0x16, 0xa5, 0x68, 0xe0, 0xee, 0x42, 0x04, 0x54, 0x03, 0xc5, 0xb4, 0x2e, 0x7a, 0xa7, 0xf8,
-	0x4a, 0xff, 0x4d, 0x33, 0x2d, 0x5d, 0xee, 0x86, 0x52, 0xe2, 0xe0, 0xa0, 0xdf, 0xb3, 0x06, 0x58,
-	0xa9, 0xa0, 0xa7, 0xf0, 0x58, 0x3c, 0xdd, 0xc1, 0x57, 0xef, 0xcf, 0x31, 0xb9, 0x5c, 0x50, 0xab,
-	0x62, 0x53, 0x1d, 0x6d, 0x5d, 0x5f, 0x59, 0xf8, 0x83, 0xad, 0x1c, 0xa2, 0xcf, 0xe1, 0xd9, 0xb6,
-	0x4e, 0xef, 0xf6, 0x84, 0x3d, 0x45, 0xba, 0x70, 0x8a, 0x71, 0x5f, 0xeb, 0x9a, 0x17, 0x58, 0x79,
-	0xac, 0xfe, 0x04, 0xe5, 0xfe, 0x8c, 0x0f, 0xb8, 0x18, 0x73, 0xd3, 0x1b, 0xf9, 0x48, 0x81, 0xdc,
-	0x47, 0x36, 0x5f, 0x7c, 0x65, 0xe4, 0x51, 0x38, 0xb9, 0x77, 0x4b, 0x27, 0x33, 0xb6, 0xe8, 0xce,
-	0x44, 0x50, 0x31, 0x1c, 0x12, 0xea, 0x8d, 0xd9, 0xfb, 0x19, 0x0b, 0xe7, 0x31, 0x5d, 0x2e, 0x4f,
-	0xd1, 0x33, 0x21, 0x3f, 0x5d, 0xf2, 0x97, 0x32, 0x3a, 0x82, 0x7d, 0xe6, 0x0d, 0xa5, 0x26, 0x99,
-	0x9e, 0x85, 0xa4, 0x7e, 0x0d, 0x4f, 0x36, 0xcc, 0x58, 0xb2, 0xf6, 0x55, 0xc8, 0x8a, 0x6f, 0x4a,
-	0x62, 0x44, 0x9c, 0xd4, 0x6f, 0xa0, 0xb6, 0x01, 0xd3, 0x27, 0x7e, 0xc4, 0xb6, 0x70, 0x9a, 0x88,
-	0x7c, 0x1d, 0x27, 0x1e, 0xb9, 0x90, 0x0e, 0x7f, 0x72, 0x60, 0x7f, 0x66, 0xb6, 0x6c, 0x10, 0x16,
-	0x05, 0xbe, 0x27, 0x9e, 0xc3, 0x50, 0x11, 0xc4, 0x48, 0xf3, 0x86, 0xb1, 0xcd, 0xe4, 0x93, 0x5a,
-	0x6a, 0xbf, 0x48, 0x3b, 0xf2, 0x81, 0xb7, 0xc9, 0x3a, 0x4b, 0xce, 0xd4, 0x0d, 0x8d, 0xce, 0xfc,
-	0x30, 0x79, 0xfa, 0x80, 0xa4, 0xe2, 0x22, 0x9e, 0x5c, 0x1a, 0xcf, 0x77, 0xaf, 0xa0, 0xb6, 0xeb,
-	0xeb, 0x25, 0x57, 0x5f, 0xff, 0xfc, 0x4d, 0xd7, 0xd4, 0x45, 0x3f, 0x2b, 0x50, 0xd6, 0x7b, 0xd6,
-	0x5b, 0xd3, 0xc0, 0x96, 0x6d, 0x6a, 0x5d, 0x25, 0xd3, 0xfe, 0xb0, 0xb2, 0x34, 0x06, 0xb3, 0x20,
-	0xf0, 0x43, 0x8e, 0x0c, 0xd1, 0x54, 0x6c, 0xec, 0x46, 0x9c, 0x85, 0xa8, 0xfe, 0xd0, 0xca, 0x68,
-	0x3c, 0xa8, 0x51, 0x1f, 0x9d, 0x64, 0x5e, 0x66, 0xde, 0xe8, 0x70, 0xe4, 0x87, 0xe3, 0xe6, 0x8d,
-	0x18, 0xab, 0x70, 0xc2, 0x86, 0x63, 0x16, 0x2e, 0x08, 0xbf, 0x7f, 0x3b, 0x76, 0xf9, 0xcd, 0xec,
-	0xba, 0xe9, 0xf8, 0xd3, 0xd6, 0x8a, 0xba, 0x35, 0xa2, 0xd7, 0xa1, 0xeb, 0x24, 0xff, 0x72, 0xa2,
-	0x96, 0xfc, 0x3b, 0x74, 0x9d, 0xfc, 0x39, 0xfa, 0xe1, 0xbf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x94,
-	0xee, 0x4d, 0x7b, 0x3b, 0x09, 0x00, 0x00,
+	// 1187 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0xdd, 0x72, 0xda, 0x46,
+	0x14, 0x0e, 0x3f, 0x36, 0xe6, 0x80, 0x61, 0xb3, 0x26, 0xb1, 0xea, 0xa6, 0x8d, 0xab, 0x9b, 0x92,
+	0xb4, 0xc5, 0x0d, 0x4d, 0x3b, 0xd3, 0x5e, 0x64, 0x46, 0x91, 0xd6, 0x44, 0x05, 0x0b, 0x22, 0xc9,
+	0x6e, 0xdc, 0x1b, 0x77, 0x2d, 0x16, 0xac, 0x1a, 0xb4, 0xaa, 0xb4, 0x30, 0xa6, 0x57, 0xbd, 0xea,
+	0x6b, 0xf5, 0x81, 0x7a, 0xd3, 0x47, 0xe8, 0xac, 0x04, 0xc4, 0x80, 0x9d, 0xe9, 0xf4, 0x8a, 0x39,
+	0x7b, 0xbe, 0xf3, 0xf7, 0xe9, 0xdb, 0xc3, 0x42, 0x2d, 0x64, 0x2c, 0x3a, 0xf2, 0xae, 0xa8, 0x1f,
+	0x78, 0xbc, 0xcf, 0x1a, 0x61, 0xc4, 0x05, 0xc7, 0xdb, 0xc9, 0x4f, 0x7c, 0xf0, 0xd1, 0xaa, 0x97,
+	0x4d, 0x59, 0x20, 0x52, 0xc8, 0xc1, 0xd3, 0x21, 0xe7, 0xc3, 0x11, 0x3b, 0x4a, 0xac, 0xcb, 0xc9,
+	0xe0, 0x48, 0xf8, 0x63, 0x16, 0x0b, 0x3a, 0x0e, 0x53, 0x80, 0xfa, 0x0c, 0x4a, 0xfa, 0x22, 0xd0,
+	0x34, 0x70, 0x19, 0xf2, 0x21, 0x15, 0x57, 0x4a, 0xe6, 0x30, 0x53, 0x2f, 0x4a, 0x2b, 0xa0, 0x63,
+	0xa6, 0x64, 0xa5, 0xa5, 0x7e, 0x0a, 0x95, 0xf7, 0xd0, 0x20, 0x9c, 0x08, 0xe9, 0xa7, 0xd1, 0x30,
+	0x56, 0x32, 0x87, 0xb9, 0x7a, 0x59, 0xfd, 0x2b, 0x0b, 0xbb, 0x4b, 0x80, 0x13, 0x32, 0x0f, 0xd7,
+	0x21, 0x2f, 0x66, 0x21, 0x4b, 0xb2, 0x55, 0x9a, 0x07, 0x69, 0xc9, 0xb8, 0xb1, 0x02, 0x6a, 0xb8,
+	0xb3, 0x90, 0xe1, 0x3a, 0x94, 0xbc, 0xf7, 0x6d, 0x24, 0x05, 0x4b, 0xcd, 0xbd, 0x8d, 0x00, 0xd3,
+	0xc0, 0x9f, 0x43, 0xc1, 0x13, 0x3c, 0x3a, 0x89, 0x87, 0x4a, 0x2e, 0x41, 0x3d, 0xde, 0x44, 0x25,
+	0xcd, 0x55, 0xa1, 0x20, 0x87, 0xe5, 0x13, 0xa1, 0xe4, 0x0f, 0x33, 0xf5, 0x2d, 0xfc, 0x03, 0xd4,
+	0x3c, 0x1e, 0x0c, 0xfc, 0x3e, 0x0b, 0x84, 0x4f, 0x47, 0xbe, 0x98, 0x75, 0xd8, 0x94, 0x8d, 0x94,
+	0xad, 0xa4, 0xbb, 0x27, 0xcb, 0x34, 0x77, 0x60, 0x30, 0x82, 0x9d, 0x31, 0x13, 0xb4, 0x4f, 0x05,
+	0x55, 0xb6, 0x0f, 0x33, 0xf5, 0x32, 0xc6, 0x00, 0x54, 0x88, 0xc8, 0xbf, 0x9c, 0x08, 0x16, 0x2b,
+	0x85, 0xc3, 0x5c, 0xbd, 0xa8, 0xbe, 0x82, 0x7c, 0x32, 0xcd, 0x2e, 0x14, 0x4f, 0x2d, 0x83, 0x1c,
+	0x9b, 0x16, 0x31, 0xd0, 0x03, 0x0c, 0xb0, 0xdd, 0xea, 0x76, 0x34, 0xab, 0x85, 0x32, 0x78, 0x07,
+	0xf2, 0x56, 0xd7, 0x20, 0x28, 0x8b, 0x0b, 0x90, 0xd3, 0x35, 0x1b, 0xe5, 0xe4, 0xd1, 0x8f, 0xda,
+	0x99, 0x86, 0xf2, 0xea, 0x9f, 0x59, 0xd8, 0x5f, 0x4e, 0x61, 0xb0, 0x70, 0xc4, 0x67, 0x63, 0x16,
+	0x88, 0x84, 0xcb, 0x2f, 0x61, 0xd7, 0xbb, 0xcd, 0x5b, 0x42, 0x6a, 0xa9, 0xf9, 0xe8, 0x4e, 0x52,
+	0xf1, 0x0b, 0xd8, 0x65, 0x83, 0x01, 0xf3, 0x84, 0x3f, 0x65, 0x06, 0x15, 0x6c, 0xce, 0xe8, 0x41,
+	0x23, 0xd5, 0x43, 0x63, 0xa1, 0x87, 0x86, 0xbb, 0xd0, 0x03, 0xde, 0x83, 0x92, 0x0c, 0xef, 0x51,
+	0xef, 0x9a, 0x0e, 0x59, 0x42, 0x6e, 0x19, 0x13, 0x28, 0xb0, 0x1b, 0xe6, 0x91, 0x60, 0x9a, 0x90,
+	0x58, 0x69, 0xbe, 0xdc, 0xa8, 0xb7, 0xda, 0x67, 0x83, 0xdc, 0x30, 0x6f, 0x22, 0x7c, 0x1e, 0x90,
+	0x60, 0xea, 0x47, 0x3c, 0x90, 0x0e, 0xb5, 0x01, 0xb5, 0xbb, 0xce, 0x25, 0x33, 0x46, 0x57, 0x6f,
+	0x13, 0x3b, 0x65, 0xc9, 0x39, 0x77, 0x5c, 0x72, 0x82, 0x32, 0xea, 0x2f, 0xb7, 0x78, 0x30, 0x83,
+	0x29, 0xf7, 0xa8, 0x8c, 0xfc, 0x1f, 0x3c, 0xec, 0x43, 0xd5, 0xef, 0xb7, 0x58, 0xc0, 0xa2, 0x24,
+	0x83, 0x36, 0x1a, 0xce, 0xc5, 0xfc, 0x0a, 0x94, 0x25, 0xb2, 0x17, 0xf1, 0x90, 0xc7, 0x74, 0xa4,
+	0xf3, 0x40, 0xb0, 0x9b, 0x44, 0x39, 0x5e, 0xc4, 0xa8, 0xe0, 0x51, 0x92, 0xbc, 0x8c, 0x1f, 0x42,
+	0x51, 0x44, 0x34, 0x88, 0x7d, 0x16, 0x88, 0x24, 0xbe, 0xac, 0xfe, 0x9d, 0x07, 0xb4, 0x4c, 0x70,
+	0xc2, 0xe2, 0x98, 0x0e, 0x19, 0xfe, 0x62, 0x45, 0xef, 0x9f, 0x6c, 0xb4, 0x34, 0xc7, 0xa5, 0x92,
+	0xff, 0x0a, 0x8a, 0xcb, 0xcb, 0xf8, 0x1f, 0x3e, 0x4f, 0x15, 0x0a, 0x21, 0x9d, 0x8d, 0x38, 0xed,
+	0xcf, 0x3f, 0x4d, 0x19, 0xf2, 0xe2, 0xc6, 0xef, 0x27, 0xdf, 0xa5, 0x88, 0xbf, 0x87, 0x6a, 0xb8,
+	0x3a, 0x46, 0xa2, 0xeb, 0x52, 0xf3, 0x70, 0xa3, 0x8b, 0xf5, 0x71, 0x1b, 0x50, 0x59, 0x32, 0x4a,
+	0xe4, 0xee, 0x50, 0xb6, 0xef, 0xb9, 0x58, 0x89, 0x57, 0xfd, 0x27, 0x7b, 0xb7, 0xcc, 0xcb, 0xb0,
+	0x63, 0x93, 0x96, 0xe9, 0xb8, 0xc4, 0x46, 0x19, 0x5c, 0x01, 0x58, 0x58, 0xc4, 0x40, 0x59, 0xa9,
+	0x72, 0xd3, 0x32, 0x5d, 0x94, 0xc3, 0x45, 0xd8, 0xb2, 0x89, 0x66, 0x9c, 0xa3, 0x3c, 0xae, 0x42,
+	0xc9, 0xb5, 0x35, 0xcb, 0xd1, 0x74, 0xd7, 0xec, 0x5a, 0x68, 0x4b, 0xa6, 0xd4, 0xbb, 0x27, 0xbd,
+	0x0e, 0x71, 0x89, 0x81, 0xb6, 0x25, 0x94, 0xd8, 0x76, 0xd7, 0x46, 0x05, 0xe9, 0x69, 0x11, 0xf7,
+	0xc2, 0x71, 0x35, 0x97, 0xa0, 0x1d, 0x69, 0xf6, 0x4e, 0x17, 0x66, 0x51, 0x9a, 0x06, 0xe9, 0xcc,
+	0x4d, 0xc0, 0x35, 0x40, 0xa6, 0x75, 0xd6, 0x6d, 0x93, 0x0b, 0xfd, 0x8d, 0x66, 0x5a, 0xba, 0xbc,
+	0x71, 0xa5, 0xb4, 0x41, 0xa7, 0xd7, 0xb5, 0x1c, 0x82, 0x76, 0xf1, 0x23, 0x78, 0x68, 0x6b, 0x56,
+	0x8b, 0x5c, 0xbc, 0x3d, 0x25, 0xf6, 0xf9, 0x3c, 0xb4, 0x82, 0x0f, 0xe0, 0xf1, 0xc6, 0xf1, 0x85,
+	0x45, 0xde, 0xb9, 0xa8, 0x8a, 0x3f, 0x86, 0xfd, 0x4d, 0x9f, 0xde, 0xe9, 0x3a, 0x04, 0x21, 0xd9,
+	0x42, 0x9b, 0x90, 0x9e, 0xd6, 0x31, 0xcf, 0x08, 0x7a, 0x28, 0x5b, 0x90, 0xfd, 0xa6, 0x48, 0x9b,
+	0x38, 0xa7, 0x1d, 0x17, 0x61, 0xbc, 0x0f, 0x7b, 0xf2, 0xf4, 0x8d, 0xe9, 0xb8, 0x5d, 0xfb, 0xfc,
+	0xe2, 0xb8, 0x6b, 0x5f, 0xb4, 0xc9, 0x39, 0xda, 0xc3, 0x4f, 0x40, 0xb9, 0xc3, 0x91, 0x16, 0xae,
+	0xa9, 0xcf, 0xa1, 0xdc, 0x9b, 0x08, 0x47, 0x50, 0xc1, 0xcc, 0x60, 0xc0, 0x71, 0x09, 0x72, 0xd7,
+	0x6c, 0x36, 0xdf, 0xd2, 0xbb, 0xb0, 0x35, 0xa5, 0xa3, 0x09, 0x9b, 0x2b, 0xf3, 0x27, 0xa8, 0xda,
+	0x34, 0x18, 0xb2, 0xb7, 0x13, 0x16, 0xcd, 0x92, 0x10, 0xb9, 0xbd, 0x62, 0x41, 0x23, 0xd1, 0x5e,
+	0xc6, 0x54, 0x60, 0x9b, 0x05, 0x7d, 0x69, 0x27, 0xd7, 0x41, 0x22, 0x42, 0x3a, 0x64, 0x8e, 0xff,
+	0x7b, 0x7a, 0xf3, 0xb7, 0xe4, 0xc9, 0x25, 0xe7, 0xd7, 0x63, 0x1a, 0x5d, 0xa7, 0x12, 0x53, 0x3f,
+	0x83, 0xbd, 0xb5, 0xc4, 0x96, 0x94, 0x0f, 0x40, 0xd6, 0x34, 0xd2, 0xb4, 0xaa, 0x0a, 0xb5, 0x35,
+	0x88, 0x3e, 0xe2, 0x31, 0x5b, 0xc1, 0x7c, 0x0b, 0xfb, 0x6b, 0x98, 0x36, 0x9b, 0x9d, 0xc9, 0x01,
+	0x3e, 0x38, 0xd6, 0x1f, 0x99, 0x8d, 0x38, 0x9b, 0xc5, 0x21, 0x0f, 0x62, 0x86, 0xbf, 0x83, 0xdd,
+	0x6b, 0x36, 0x8b, 0xb5, 0xa0, 0x9f, 0xe4, 0x49, 0xff, 0x90, 0x4a, 0xcd, 0xa7, 0x0b, 0x01, 0xdf,
+	0x57, 0xaf, 0x0a, 0x85, 0x2b, 0x1a, 0x9f, 0xf0, 0x28, 0x2d, 0xb2, 0x33, 0xef, 0x33, 0xb7, 0xa0,
+	0x64, 0x8d, 0x00, 0x1d, 0x2a, 0x2d, 0x26, 0x92, 0x3c, 0x36, 0x8b, 0x27, 0x23, 0x21, 0x7b, 0xfc,
+	0x4d, 0x9a, 0x4a, 0x66, 0x83, 0xc5, 0xec, 0x06, 0x8b, 0x49, 0x5a, 0xf5, 0x29, 0xa0, 0x16, 0x13,
+	0x6f, 0xfc, 0x58, 0xf0, 0x68, 0x76, 0xcc, 0xa3, 0x36, 0x9b, 0xad, 0xcc, 0x2d, 0x39, 0x5c, 0x07,
+	0x6c, 0xf0, 0xdc, 0x80, 0x6a, 0x9b, 0xcd, 0x4e, 0x78, 0xdf, 0x1f, 0xf8, 0xe9, 0x6e, 0x4c, 0xd7,
+	0xc1, 0x02, 0xb0, 0x4e, 0xde, 0xaf, 0xa0, 0xac, 0xe7, 0x5c, 0x92, 0xf7, 0x02, 0xd0, 0xf5, 0x6a,
+	0xae, 0x05, 0x7f, 0xfb, 0x0b, 0xfe, 0xd6, 0x6b, 0x7d, 0x88, 0xb7, 0xe7, 0x2f, 0xa1, 0x76, 0xe7,
+	0x5f, 0x28, 0xc0, 0x76, 0xef, 0xf4, 0x75, 0xc7, 0xd4, 0xd1, 0x03, 0x8c, 0xa0, 0xac, 0x77, 0xad,
+	0x63, 0xd3, 0x20, 0x96, 0x6b, 0x6a, 0x1d, 0x94, 0x69, 0xbe, 0xbb, 0xb5, 0x4e, 0x9d, 0x49, 0x18,
+	0xf2, 0x48, 0x60, 0x03, 0x76, 0x6c, 0x36, 0xf4, 0x63, 0xc1, 0x22, 0xac, 0xdc, 0xb7, 0x4c, 0x0f,
+	0xee, 0xf5, 0xa8, 0x0f, 0xea, 0x99, 0xaf, 0x33, 0xaf, 0x75, 0x78, 0xcc, 0xa3, 0x61, 0xe3, 0x6a,
+	0x16, 0xb2, 0x68, 0xc4, 0xfa, 0x43, 0x16, 0xcd, 0x03, 0x7e, 0x7e, 0x36, 0xf4, 0xc5, 0xd5, 0xe4,
+	0xb2, 0xe1, 0xf1, 0xf1, 0xd1, 0x2d, 0xf7, 0xd1, 0x80, 0x5e, 0x46, 0xbe, 0x97, 0xbe, 0x99, 0xe2,
+	0x23, 0xf9, 0xb8, 0xba, 0x4c, 0x9f, 0x5a, 0xdf, 0xfc, 0x3b, 0x00, 0xbe, 0x77, 0x31, 0x92, 0x89,
+	0x09, 0x00, 0x00,
 }
@@ -38,6 +38,11 @@ It has these top-level messages:
 	RangeQueryStateClose
 	RangeQueryStateKeyValue
 	RangeQueryStateResponse
+	GetQueryResult
+	GetHistoryForKey
+	GetHistoryForKeyNext
+	KeyModification
+	GetHistoryForKeyResponse
 	ChaincodeActionPayload
 	ChaincodeEndorsedAction
 	AnchorPeers
@@ -65,6 +70,7 @@ It has these top-level messages:
 	InvalidTransaction
 	Transaction
 	TransactionAction
+	ProcessedTransaction
 	ServerStatus
 	LogLevelRequest
 	LogLevelResponse
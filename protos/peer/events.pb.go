@@ -176,8 +176,9 @@ func (m *Register) GetEvents() []*Interest {
 // Rejection is sent by consumers for erroneous transaction rejection events
 // string type - "rejection"
 type Rejection struct {
-	Tx       *Transaction `protobuf:"bytes,1,opt,name=tx" json:"tx,omitempty"`
-	ErrorMsg string       `protobuf:"bytes,2,opt,name=errorMsg" json:"errorMsg,omitempty"`
+	Tx             *Transaction     `protobuf:"bytes,1,opt,name=tx" json:"tx,omitempty"`
+	ErrorMsg       string           `protobuf:"bytes,2,opt,name=errorMsg" json:"errorMsg,omitempty"`
+	ValidationCode TxValidationCode `protobuf:"varint,3,opt,name=validationCode,enum=protos.TxValidationCode" json:"validationCode,omitempty"`
 }
 
 func (m *Rejection) Reset()                    { *m = Rejection{} }
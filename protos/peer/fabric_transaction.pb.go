@@ -14,6 +14,70 @@ var _ = proto.Marshal
 var _ = fmt.Errorf
 var _ = math.Inf
 
+// TxValidationCode is the outcome of the validation of a transaction by the
+// committing peer. The code of each transaction of a block is stored in the
+// TRANSACTIONS_FILTER metadata of the block, one byte per transaction, hence
+// the codes have to fit in a byte
+type TxValidationCode int32
+
+const (
+	TxValidationCode_VALID                        TxValidationCode = 0
+	TxValidationCode_NIL_ENVELOPE                 TxValidationCode = 1
+	TxValidationCode_BAD_PAYLOAD                  TxValidationCode = 2
+	TxValidationCode_BAD_COMMON_HEADER            TxValidationCode = 3
+	TxValidationCode_BAD_CREATOR_SIGNATURE        TxValidationCode = 4
+	TxValidationCode_INVALID_ENDORSER_TRANSACTION TxValidationCode = 5
+	TxValidationCode_INVALID_CONFIG_TRANSACTION   TxValidationCode = 6
+	TxValidationCode_UNKNOWN_TX_TYPE              TxValidationCode = 7
+	TxValidationCode_TARGET_CHAIN_NOT_FOUND       TxValidationCode = 8
+	TxValidationCode_DUPLICATE_TXID               TxValidationCode = 9
+	TxValidationCode_ENDORSEMENT_POLICY_FAILURE   TxValidationCode = 10
+	TxValidationCode_MVCC_READ_CONFLICT           TxValidationCode = 11
+	TxValidationCode_PHANTOM_READ_CONFLICT        TxValidationCode = 12
+	TxValidationCode_NOT_VALIDATED                TxValidationCode = 254
+	TxValidationCode_INVALID_OTHER_REASON         TxValidationCode = 255
+)
+
+var TxValidationCode_name = map[int32]string{
+	0:   "VALID",
+	1:   "NIL_ENVELOPE",
+	2:   "BAD_PAYLOAD",
+	3:   "BAD_COMMON_HEADER",
+	4:   "BAD_CREATOR_SIGNATURE",
+	5:   "INVALID_ENDORSER_TRANSACTION",
+	6:   "INVALID_CONFIG_TRANSACTION",
+	7:   "UNKNOWN_TX_TYPE",
+	8:   "TARGET_CHAIN_NOT_FOUND",
+	9:   "DUPLICATE_TXID",
+	10:  "ENDORSEMENT_POLICY_FAILURE",
+	11:  "MVCC_READ_CONFLICT",
+	12:  "PHANTOM_READ_CONFLICT",
+	254: "NOT_VALIDATED",
+	255: "INVALID_OTHER_REASON",
+}
+var TxValidationCode_value = map[string]int32{
+	"VALID":                        0,
+	"NIL_ENVELOPE":                 1,
+	"BAD_PAYLOAD":                  2,
+	"BAD_COMMON_HEADER":            3,
+	"BAD_CREATOR_SIGNATURE":        4,
+	"INVALID_ENDORSER_TRANSACTION": 5,
+	"INVALID_CONFIG_TRANSACTION":   6,
+	"UNKNOWN_TX_TYPE":              7,
+	"TARGET_CHAIN_NOT_FOUND":       8,
+	"DUPLICATE_TXID":               9,
+	"ENDORSEMENT_POLICY_FAILURE":   10,
+	"MVCC_READ_CONFLICT":           11,
+	"PHANTOM_READ_CONFLICT":        12,
+	"NOT_VALIDATED":                254,
+	"INVALID_OTHER_REASON":         255,
+}
+
+func (x TxValidationCode) String() string {
+	return proto.EnumName(TxValidationCode_name, int32(x))
+}
+func (TxValidationCode) EnumDescriptor() ([]byte, []int) { return fileDescriptor11, []int{0} }
+
 type InvalidTransaction_Cause int32
 
 const (
@@ -128,11 +192,35 @@ func (m *TransactionAction) String() string            { return proto.CompactTex
 func (*TransactionAction) ProtoMessage()               {}
 func (*TransactionAction) Descriptor() ([]byte, []int) { return fileDescriptor11, []int{3} }
 
+// ProcessedTransaction wraps a transaction stored in the ledger with the
+// validation code the committing peer assigned to it
+type ProcessedTransaction struct {
+	// The transaction as found in the block
+	Transaction *Transaction `protobuf:"bytes,1,opt,name=transaction" json:"transaction,omitempty"`
+	// The validation code of the transaction, as stored in the
+	// TRANSACTIONS_FILTER metadata of its block
+	ValidationCode TxValidationCode `protobuf:"varint,2,opt,name=validationCode,enum=protos.TxValidationCode" json:"validationCode,omitempty"`
+}
+
+func (m *ProcessedTransaction) Reset()                    { *m = ProcessedTransaction{} }
+func (m *ProcessedTransaction) String() string            { return proto.CompactTextString(m) }
+func (*ProcessedTransaction) ProtoMessage()               {}
+func (*ProcessedTransaction) Descriptor() ([]byte, []int) { return fileDescriptor11, []int{4} }
+
+func (m *ProcessedTransaction) GetTransaction() *Transaction {
+	if m != nil {
+		return m.Transaction
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*SignedTransaction)(nil), "protos.SignedTransaction")
 	proto.RegisterType((*InvalidTransaction)(nil), "protos.InvalidTransaction")
 	proto.RegisterType((*Transaction)(nil), "protos.Transaction")
 	proto.RegisterType((*TransactionAction)(nil), "protos.TransactionAction")
+	proto.RegisterType((*ProcessedTransaction)(nil), "protos.ProcessedTransaction")
+	proto.RegisterEnum("protos.TxValidationCode", TxValidationCode_name, TxValidationCode_value)
 	proto.RegisterEnum("protos.InvalidTransaction_Cause", InvalidTransaction_Cause_name, InvalidTransaction_Cause_value)
 }
 
@@ -45,6 +45,24 @@ func GetChainIDFromBlock(block *cb.Block) (string, error) {
 	return payload.Header.ChainHeader.ChainID, nil
 }
 
+// IsConfigBlock tells whether the block is a configuration block, that is a block
+// carrying a single configuration transaction
+func IsConfigBlock(block *cb.Block) bool {
+	if block.Data == nil || len(block.Data.Data) != 1 {
+		return false
+	}
+	envelope := &cb.Envelope{}
+	if err := proto.Unmarshal(block.Data.Data[0], envelope); err != nil {
+		return false
+	}
+	payload := &cb.Payload{}
+	if err := proto.Unmarshal(envelope.Payload, payload); err != nil {
+		return false
+	}
+	return payload.Header != nil && payload.Header.ChainHeader != nil &&
+		payload.Header.ChainHeader.Type == int32(cb.HeaderType_CONFIGURATION_TRANSACTION)
+}
+
 // GetMetadataFromBlock retrieves metadata at the specified index
 func GetMetadataFromBlock(block *cb.Block, index cb.BlockMetadataIndex) (*cb.Metadata, error) {
 	md := &cb.Metadata{}
@@ -87,9 +105,9 @@ func CopyBlockMetadata(src *cb.Block, dst *cb.Block) {
 // InitBlockMetadata copies metadata from one block into another
 func InitBlockMetadata(block *cb.Block) {
 	if block.Metadata == nil {
-		block.Metadata = &cb.BlockMetadata{Metadata: [][]byte{[]byte{}, []byte{}, []byte{}}}
-	} else if len(block.Metadata.Metadata) < int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER+1) {
-		for i := int(len(block.Metadata.Metadata)); i <= int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER); i++ {
+		block.Metadata = &cb.BlockMetadata{Metadata: [][]byte{[]byte{}, []byte{}, []byte{}, []byte{}, []byte{}}}
+	} else if len(block.Metadata.Metadata) < int(cb.BlockMetadataIndex_TRANSACTIONS_VALIDATION_CODES+1) {
+		for i := int(len(block.Metadata.Metadata)); i <= int(cb.BlockMetadataIndex_TRANSACTIONS_VALIDATION_CODES); i++ {
 			block.Metadata.Metadata = append(block.Metadata.Metadata, []byte{})
 		}
 	}
@@ -146,12 +164,10 @@ func EncodeMSPUnsigned(testChainID string) *cb.ConfigurationItem {
 	if err != nil {
 		panic(fmt.Sprintf("GetLocalMspConfig failed, err %s", err))
 	}
-	// TODO: once https://gerrit.hyperledger.org/r/#/c/3941 is merged, change this to MSP
-	// Right now we don't have an MSP type there
 	return createConfigItem(testChainID,
 		mspKey,
 		MarshalOrPanic(conf),
-		XXX_DefaultModificationPolicyID, cb.ConfigurationItem_Orderer)
+		XXX_DefaultModificationPolicyID, cb.ConfigurationItem_MSP)
 }
 
 // EncodeMSP gets the signed configuration item with the default MSP
@@ -161,10 +177,8 @@ func EncodeMSP(testChainID string) *cb.SignedConfigurationItem {
 	if err != nil {
 		panic(fmt.Sprintf("GetLocalMspConfig failed, err %s", err))
 	}
-	// TODO: once https://gerrit.hyperledger.org/r/#/c/3941 is merged, change this to MSP
-	// Right now we don't have an MSP type there
 	return createSignedConfigItem(testChainID,
 		mspKey,
 		MarshalOrPanic(conf),
-		XXX_DefaultModificationPolicyID, cb.ConfigurationItem_Orderer)
+		XXX_DefaultModificationPolicyID, cb.ConfigurationItem_MSP)
 }
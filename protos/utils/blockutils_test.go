@@ -68,3 +68,25 @@ func TestGetBlockFromBlockBytes(t *testing.T) {
 		t.Fatalf("failed to get block from block bytes: %s", err)
 	}
 }
+
+func TestIsConfigBlock(t *testing.T) {
+	gb, err := configtxtest.MakeGenesisBlock("myuniquetestchainid")
+	if err != nil {
+		t.Fatalf("failed to create test configuration block: %s", err)
+	}
+	if !utils.IsConfigBlock(gb) {
+		t.Fatalf("expected the genesis block to be a configuration block")
+	}
+
+	payload := &common.Payload{Header: &common.Header{ChainHeader: &common.ChainHeader{Type: int32(common.HeaderType_ENDORSER_TRANSACTION)}}}
+	block := common.NewBlock(1, nil)
+	block.Data.Data = [][]byte{utils.MarshalOrPanic(&common.Envelope{Payload: utils.MarshalOrPanic(payload)})}
+	if utils.IsConfigBlock(block) {
+		t.Fatalf("expected a block of an endorser transaction not to be a configuration block")
+	}
+
+	block.Data.Data = append(block.Data.Data, gb.Data.Data[0])
+	if utils.IsConfigBlock(block) {
+		t.Fatalf("expected a block of several transactions not to be a configuration block")
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"errors"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/crypto/primitives"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
@@ -251,6 +252,7 @@ func CreateChaincodeProposalWithTransient(txid string, typ common.HeaderType, ch
 	hdr := &common.Header{ChainHeader: &common.ChainHeader{Type: int32(typ),
 		TxID:      txid,
 		ChainID:   chainID,
+		Timestamp: util.CreateUtcTimestamp(),
 		Extension: ccHdrExtBytes},
 		SignatureHeader: &common.SignatureHeader{Nonce: nonce, Creator: creator}}
 
@@ -405,18 +407,22 @@ func CreateProposalFromCIS(txid string, typ common.HeaderType, chainID string, c
 	return CreateChaincodeProposal(txid, typ, chainID, cis, creator)
 }
 
-// CreateDeployProposalFromCDS returns a deploy proposal given a serialized identity and a ChaincodeDeploymentSpec
-func CreateDeployProposalFromCDS(txid string, chainID string, cds *peer.ChaincodeDeploymentSpec, creator []byte) (*peer.Proposal, error) {
-	return createProposalFromCDS(txid, chainID, cds, creator, true)
+// CreateDeployProposalFromCDS returns a deploy proposal given a serialized identity and a ChaincodeDeploymentSpec.
+// The endorsement policy is a serialized common.SignaturePolicyEnvelope, and may be nil for the default policy;
+// escc and vscc name the system chaincodes endorsing and validating the transactions of the chaincode, and may
+// be empty for the default ones
+func CreateDeployProposalFromCDS(txid string, chainID string, cds *peer.ChaincodeDeploymentSpec, creator []byte, policy []byte, escc string, vscc string) (*peer.Proposal, error) {
+	return createProposalFromCDS(txid, chainID, cds, creator, policy, escc, vscc, true)
 }
 
-// CreateUpgradeProposalFromCDS returns a upgrade proposal given a serialized identity and a ChaincodeDeploymentSpec
-func CreateUpgradeProposalFromCDS(txid string, chainID string, cds *peer.ChaincodeDeploymentSpec, creator []byte) (*peer.Proposal, error) {
-	return createProposalFromCDS(txid, chainID, cds, creator, false)
+// CreateUpgradeProposalFromCDS returns a upgrade proposal given a serialized identity and a ChaincodeDeploymentSpec.
+// The endorsement policy, escc and vscc may be nil or empty for keeping those of the chaincode being upgraded
+func CreateUpgradeProposalFromCDS(txid string, chainID string, cds *peer.ChaincodeDeploymentSpec, creator []byte, policy []byte, escc string, vscc string) (*peer.Proposal, error) {
+	return createProposalFromCDS(txid, chainID, cds, creator, policy, escc, vscc, false)
 }
 
 // createProposalFromCDS returns a deploy or upgrade proposal given a serialized identity and a ChaincodeDeploymentSpec
-func createProposalFromCDS(txid string, chainID string, cds *peer.ChaincodeDeploymentSpec, creator []byte, deploy bool) (*peer.Proposal, error) {
+func createProposalFromCDS(txid string, chainID string, cds *peer.ChaincodeDeploymentSpec, creator []byte, policy []byte, escc string, vscc string, deploy bool) (*peer.Proposal, error) {
 	b, err := proto.Marshal(cds)
 	if err != nil {
 		return nil, err
@@ -428,12 +434,16 @@ func createProposalFromCDS(txid string, chainID string, cds *peer.ChaincodeDeplo
 	} else {
 		propType = "upgrade"
 	}
+	args := [][]byte{[]byte(propType), []byte(chainID), b}
+	if policy != nil || escc != "" || vscc != "" {
+		args = append(args, policy, []byte(escc), []byte(vscc))
+	}
 	//wrap the deployment in an invocation spec to lccc...
 	lcccSpec := &peer.ChaincodeInvocationSpec{
 		ChaincodeSpec: &peer.ChaincodeSpec{
 			Type:        peer.ChaincodeSpec_GOLANG,
 			ChaincodeID: &peer.ChaincodeID{Name: "lccc"},
-			CtorMsg:     &peer.ChaincodeInput{Args: [][]byte{[]byte(propType), []byte(chainID), b}}}}
+			CtorMsg:     &peer.ChaincodeInput{Args: args}}}
 
 	//...and get the proposal for it
 	return CreateProposalFromCIS(txid, common.HeaderType_ENDORSER_TRANSACTION, chainID, lcccSpec, creator)